@@ -0,0 +1,290 @@
+// application/serviceimpl/admin_service.go
+package serviceimpl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/port"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+)
+
+const maxSuspendReasonLength = 500
+
+type adminService struct {
+	userRepo         repository.UserRepository
+	conversationRepo repository.ConversationRepository
+	messageRepo      repository.MessageRepository
+	auditRepo        repository.AdminAuditLogRepository
+	authService      service.AuthService
+	webSocketPort    port.WebSocketPort
+}
+
+// NewAdminService สร้าง service ใหม่
+func NewAdminService(
+	userRepo repository.UserRepository,
+	conversationRepo repository.ConversationRepository,
+	messageRepo repository.MessageRepository,
+	auditRepo repository.AdminAuditLogRepository,
+	authService service.AuthService,
+	webSocketPort port.WebSocketPort,
+) service.AdminService {
+	return &adminService{
+		userRepo:         userRepo,
+		conversationRepo: conversationRepo,
+		messageRepo:      messageRepo,
+		auditRepo:        auditRepo,
+		authService:      authService,
+		webSocketPort:    webSocketPort,
+	}
+}
+
+// ListUsers ค้นหาผู้ใช้ทุกสถานะ
+func (s *adminService) ListUsers(query, status, role string, limit, offset int) ([]*models.User, int64, error) {
+	return s.userRepo.ListForAdmin(strings.TrimSpace(query), status, role, limit, offset)
+}
+
+// GetUser ดึงข้อมูลผู้ใช้
+func (s *adminService) GetUser(userID uuid.UUID) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+// SuspendUser ระงับบัญชีผู้ใช้และออกจากระบบทุกอุปกรณ์
+// moderator ระงับบัญชีของเจ้าหน้าที่ (ผู้มี system role) ไม่ได้
+func (s *adminService) SuspendUser(actorID uuid.UUID, actorRole string, userID uuid.UUID, reason string) (*models.User, error) {
+	if actorID == userID {
+		return nil, errors.New("you cannot suspend yourself")
+	}
+
+	reason = strings.TrimSpace(reason)
+	if len([]rune(reason)) > maxSuspendReasonLength {
+		return nil, fmt.Errorf("suspend reason is too long (max %d characters)", maxSuspendReasonLength)
+	}
+
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.SystemRole != "" && actorRole != models.SystemRoleSuperAdmin {
+		return nil, errors.New("only super admins can suspend staff accounts")
+	}
+	if user.Status == models.UserStatusSuspended {
+		return nil, errors.New("user is already suspended")
+	}
+
+	now := time.Now()
+	user.Status = models.UserStatusSuspended
+	user.SuspendedAt = &now
+	user.SuspendedReason = reason
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	// เพิกถอน token ทุกอุปกรณ์และตัดการเชื่อมต่อ WebSocket
+	if err := s.authService.RevokeAllSessions(userID); err != nil {
+		return nil, fmt.Errorf("user suspended but failed to revoke sessions: %w", err)
+	}
+
+	return user, nil
+}
+
+// UnsuspendUser ยกเลิกการระงับบัญชี (ผู้ใช้ต้องเข้าสู่ระบบใหม่)
+func (s *adminService) UnsuspendUser(actorID uuid.UUID, actorRole string, userID uuid.UUID) (*models.User, error) {
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.SystemRole != "" && actorRole != models.SystemRoleSuperAdmin {
+		return nil, errors.New("only super admins can unsuspend staff accounts")
+	}
+	if user.Status != models.UserStatusSuspended {
+		return nil, errors.New("user is not suspended")
+	}
+
+	user.Status = models.UserStatusActive
+	user.SuspendedAt = nil
+	user.SuspendedReason = ""
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// SetSystemRole กำหนดหรือถอดบทบาทระดับแพลตฟอร์ม (เฉพาะ super admin ตรวจสอบที่ route)
+func (s *adminService) SetSystemRole(actorID, userID uuid.UUID, role string) (*models.User, error) {
+	role = strings.TrimSpace(role)
+	if !models.IsValidSystemRole(role) {
+		return nil, errors.New("invalid system role")
+	}
+	// ป้องกันการถอดสิทธิ์ตัวเองจนไม่เหลือ super admin
+	if actorID == userID {
+		return nil, errors.New("you cannot change your own system role")
+	}
+
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.SystemRole == role {
+		return user, nil
+	}
+
+	user.SystemRole = role
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// InspectConversation ดึงข้อมูลการสนทนาและสมาชิก โดยไม่ต้องเป็นสมาชิก
+func (s *adminService) InspectConversation(conversationID uuid.UUID) (*dto.AdminConversationDTO, error) {
+	conversation, err := s.conversationRepo.GetByID(conversationID)
+	if err != nil || conversation == nil {
+		return nil, errors.New("conversation not found")
+	}
+
+	members, err := s.conversationRepo.GetMembers(conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	messageCount, err := s.messageRepo.CountAllMessages(conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.AdminConversationDTO{
+		ID:             conversation.ID,
+		Type:           conversation.Type,
+		Title:          conversation.Title,
+		CreatorID:      conversation.CreatorID,
+		IsActive:       conversation.IsActive,
+		DisabledAt:     conversation.DisabledAt,
+		DisabledReason: conversation.DisabledReason,
+		MessageTTL:     conversation.MessageTTL,
+		CreatedAt:      conversation.CreatedAt,
+		UpdatedAt:      conversation.UpdatedAt,
+		LastMessageAt:  conversation.LastMessageAt,
+		MessageCount:   messageCount,
+		MemberCount:    len(members),
+		Members:        make([]dto.AdminMemberDTO, 0, len(members)),
+	}
+
+	for _, member := range members {
+		memberDTO := dto.AdminMemberDTO{
+			UserID:     member.UserID,
+			Role:       string(member.Role),
+			JoinedAt:   member.JoinedAt,
+			LastReadAt: member.LastReadAt,
+		}
+		if member.User != nil {
+			memberDTO.Username = member.User.Username
+			memberDTO.DisplayName = member.User.DisplayName
+			memberDTO.Status = member.User.Status
+		}
+		result.Members = append(result.Members, memberDTO)
+	}
+
+	return result, nil
+}
+
+// GetConversationMessages ดึงข้อความของการสนทนา (ข้อความเข้ารหัส E2EE เป็น ciphertext ที่อ่านไม่ได้)
+func (s *adminService) GetConversationMessages(conversationID uuid.UUID, limit, offset int) ([]*models.Message, int64, error) {
+	conversation, err := s.conversationRepo.GetByID(conversationID)
+	if err != nil || conversation == nil {
+		return nil, 0, errors.New("conversation not found")
+	}
+
+	return s.messageRepo.GetMessagesByConversationID(conversationID, limit, offset)
+}
+
+// DisableConversation ปิดกลุ่ม/ช่อง (ซ่อนจากรายการของสมาชิกและโพสต์ข้อความไม่ได้)
+func (s *adminService) DisableConversation(actorID, conversationID uuid.UUID, reason string) (*models.Conversation, error) {
+	reason = strings.TrimSpace(reason)
+	if len([]rune(reason)) > maxSuspendReasonLength {
+		return nil, fmt.Errorf("disable reason is too long (max %d characters)", maxSuspendReasonLength)
+	}
+
+	conversation, err := s.conversationRepo.GetByID(conversationID)
+	if err != nil || conversation == nil {
+		return nil, errors.New("conversation not found")
+	}
+	if conversation.DisabledAt != nil {
+		return nil, errors.New("conversation is already disabled")
+	}
+	// การสนทนาที่ถูกลบโดยเจ้าของแล้ว (is_active = false) ไม่ต้องปิดซ้ำ
+	if !conversation.IsActive {
+		return nil, errors.New("conversation not found")
+	}
+	if conversation.Type == "direct" {
+		return nil, errors.New("direct conversations cannot be disabled, suspend the user instead")
+	}
+
+	now := time.Now()
+	conversation.IsActive = false
+	conversation.DisabledAt = &now
+	conversation.DisabledReason = reason
+	if err := s.conversationRepo.Update(conversation); err != nil {
+		return nil, err
+	}
+
+	return conversation, nil
+}
+
+// EnableConversation เปิดการสนทนาที่ถูกปิดโดยผู้ดูแลแพลตฟอร์มอีกครั้ง
+func (s *adminService) EnableConversation(actorID, conversationID uuid.UUID) (*models.Conversation, error) {
+	conversation, err := s.conversationRepo.GetByID(conversationID)
+	if err != nil || conversation == nil {
+		return nil, errors.New("conversation not found")
+	}
+	if conversation.DisabledAt == nil {
+		return nil, errors.New("conversation is not disabled")
+	}
+
+	conversation.IsActive = true
+	conversation.DisabledAt = nil
+	conversation.DisabledReason = ""
+	if err := s.conversationRepo.Update(conversation); err != nil {
+		return nil, err
+	}
+
+	return conversation, nil
+}
+
+// GetRealtimeStats ดึงสถิติของ WebSocket Hub
+func (s *adminService) GetRealtimeStats() map[string]interface{} {
+	return s.webSocketPort.GetStats()
+}
+
+// GetRealtimeConnections ดึงรายละเอียดการเชื่อมต่อของ WebSocket Hub
+func (s *adminService) GetRealtimeConnections() map[string]interface{} {
+	return s.webSocketPort.GetAllConnections()
+}
+
+// RecordAudit บันทึกการกระทำของผู้ดูแลระบบ
+func (s *adminService) RecordAudit(entry *models.AdminAuditLog) error {
+	return s.auditRepo.Create(entry)
+}
+
+// ListAuditLogs ค้นหา audit log (ใหม่สุดก่อน)
+func (s *adminService) ListAuditLogs(actorID, targetID *uuid.UUID, action string, limit, offset int) ([]*models.AdminAuditLog, int64, error) {
+	return s.auditRepo.List(repository.AdminAuditLogFilter{
+		ActorID:  actorID,
+		TargetID: targetID,
+		Action:   action,
+		Limit:    limit,
+		Offset:   offset,
+	})
+}
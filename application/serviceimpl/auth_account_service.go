@@ -0,0 +1,294 @@
+// application/serviceimpl/auth_account_service.go
+package serviceimpl
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	passwordResetTokenTTL     = time.Hour
+	emailVerificationTokenTTL = 24 * time.Hour
+	accountTokenBytes         = 32
+	minPasswordLength         = 8
+
+	// accountMailTimeout เวลารอส่งอีเมลหนึ่งฉบับ (ส่งแบบ background ไม่ให้ request รอ)
+	accountMailTimeout = 30 * time.Second
+)
+
+var errInvalidAccountToken = errors.New("invalid or expired token")
+
+// RequestPasswordReset ส่งลิงก์รีเซ็ตรหัสผ่านไปยังอีเมล
+// ไม่บอกว่ามีบัญชีของอีเมลนี้หรือไม่ (คืนค่า nil เสมอเมื่อไม่พบ)
+func (s *authService) RequestPasswordReset(email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return errors.New("email is required")
+	}
+
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil || user == nil || user.IsBot || user.Status == models.UserStatusSuspended {
+		return nil
+	}
+
+	token, err := s.issueAccountToken(user, models.AccountTokenPasswordReset, passwordResetTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	s.sendAccountMail(&service.MailMessage{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"We received a request to reset the password for your account. "+
+			"The link below expires in 1 hour and can only be used once.\n\n"+
+			"%s\n\n"+
+			"If you did not request a password reset, you can ignore this email.\n",
+			accountMailName(user), accountLink("PASSWORD_RESET_URL", token)),
+	})
+
+	return nil
+}
+
+// ResetPassword ตั้งรหัสผ่านใหม่ด้วย token แล้วออกจากระบบทุกอุปกรณ์
+func (s *authService) ResetPassword(token, newPassword string) error {
+	if len([]rune(newPassword)) < minPasswordLength {
+		return fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+
+	record, err := s.consumeAccountToken(token, models.AccountTokenPasswordReset)
+	if err != nil {
+		return err
+	}
+
+	// ลิงก์ใช้ได้เฉพาะกับอีเมลที่ส่งไป (ถ้าเปลี่ยนอีเมลหลังจากส่งลิงก์ ลิงก์เดิมใช้ไม่ได้)
+	user, err := s.userRepo.FindByID(record.UserID)
+	if err != nil || user == nil || !strings.EqualFold(user.Email, record.Email) {
+		return errInvalidAccountToken
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New("failed to hash password")
+	}
+	user.PasswordHash = string(hashedPassword)
+
+	// ลิงก์ที่ส่งถึงอีเมลปัจจุบันยืนยันได้ว่าผู้ใช้เป็นเจ้าของอีเมลนี้
+	now := time.Now()
+	if user.EmailVerifiedAt == nil {
+		user.EmailVerifiedAt = &now
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return errors.New("failed to update password: " + err.Error())
+	}
+
+	// session เดิมทั้งหมดอาจเป็นของผู้ที่รู้รหัสผ่านเก่า
+	if err := s.RevokeAllSessions(user.ID); err != nil {
+		return errors.New("failed to revoke sessions: " + err.Error())
+	}
+
+	s.resetAccountGuard(accountGuardKey(user.Username))
+
+	s.sendAccountMail(&service.MailMessage{
+		To:      user.Email,
+		Subject: "Your password was changed",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"The password for your account was just changed and all devices were signed out.\n\n"+
+			"If you did not make this change, reset your password again immediately.\n",
+			accountMailName(user)),
+	})
+
+	return nil
+}
+
+// SendEmailVerification ส่งลิงก์ยืนยันอีเมลปัจจุบันของผู้ใช้ (ลิงก์เดิมที่ยังไม่ถูกใช้จะใช้ไม่ได้)
+func (s *authService) SendEmailVerification(userID uuid.UUID) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.Email == "" {
+		return errors.New("email is not set")
+	}
+	if user.EmailVerifiedAt != nil {
+		return errors.New("email is already verified")
+	}
+
+	token, err := s.issueAccountToken(user, models.AccountTokenEmailVerification, emailVerificationTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	s.sendAccountMail(&service.MailMessage{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"Please confirm that this is your email address. "+
+			"The link below expires in 24 hours and can only be used once.\n\n"+
+			"%s\n\n"+
+			"If you did not create an account or change your email, you can ignore this email.\n",
+			accountMailName(user), accountLink("EMAIL_VERIFICATION_URL", token)),
+	})
+
+	return nil
+}
+
+// InvalidateAccountTokens ยกเลิก token รีเซ็ตรหัสผ่านและยืนยันอีเมลที่ยังไม่ถูกใช้ของผู้ใช้ (เมื่อเปลี่ยนอีเมล)
+func (s *authService) InvalidateAccountTokens(userID uuid.UUID) error {
+	now := time.Now()
+	for _, purpose := range []string{models.AccountTokenPasswordReset, models.AccountTokenEmailVerification} {
+		if err := s.accountTokenRepo.InvalidateByUserID(userID, purpose, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyEmail ยืนยันอีเมลด้วย token (ใช้ไม่ได้ถ้าเปลี่ยนอีเมลหลังจากส่งลิงก์)
+func (s *authService) VerifyEmail(token string) error {
+	record, err := s.consumeAccountToken(token, models.AccountTokenEmailVerification)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(record.UserID)
+	if err != nil || user == nil || !strings.EqualFold(user.Email, record.Email) {
+		return errInvalidAccountToken
+	}
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	if err := s.userRepo.Update(user); err != nil {
+		return errors.New("failed to verify email: " + err.Error())
+	}
+
+	return nil
+}
+
+// issueAccountToken สร้าง token ใหม่และยกเลิก token เดิมที่ยังไม่ถูกใช้ของวัตถุประสงค์เดียวกัน
+func (s *authService) issueAccountToken(user *models.User, purpose string, ttl time.Duration) (string, error) {
+	token, err := generateAccountToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	if err := s.accountTokenRepo.InvalidateByUserID(user.ID, purpose, now); err != nil {
+		return "", err
+	}
+
+	if err := s.accountTokenRepo.Create(&models.AccountToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: hashAccountToken(token),
+		Email:     user.Email,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}); err != nil {
+		return "", errors.New("failed to create token: " + err.Error())
+	}
+
+	return token, nil
+}
+
+// consumeAccountToken ตรวจสอบและใช้ token (ใช้ได้ครั้งเดียวแม้มี request พร้อมกัน)
+func (s *authService) consumeAccountToken(token, purpose string) (*models.AccountToken, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, errors.New("token is required")
+	}
+
+	record, err := s.accountTokenRepo.FindByTokenHash(hashAccountToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if record == nil || record.Purpose != purpose || !record.IsUsable(now) {
+		return nil, errInvalidAccountToken
+	}
+
+	consumed, err := s.accountTokenRepo.Consume(record.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, errInvalidAccountToken
+	}
+
+	return record, nil
+}
+
+// sendAccountMail ส่งอีเมลแบบ background (ผลลัพธ์ของ request ไม่ขึ้นกับการส่งอีเมล)
+func (s *authService) sendAccountMail(message *service.MailMessage) {
+	if s.mailer == nil {
+		log.Printf("Mailer is not configured, email %q to %s was not sent", message.Subject, message.To)
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), accountMailTimeout)
+		defer cancel()
+
+		if err := s.mailer.Send(ctx, message); err != nil {
+			log.Printf("Failed to send email %q to %s: %v", message.Subject, message.To, err)
+		}
+	}()
+}
+
+// accountMailName ชื่อที่ใช้ทักทายในอีเมล
+func accountMailName(user *models.User) string {
+	if user.DisplayName != "" {
+		return user.DisplayName
+	}
+	return user.Username
+}
+
+// accountLink สร้างลิงก์ไปยังหน้าของ client จาก environment (ไม่ตั้งค่า = ส่ง token อย่างเดียว)
+func accountLink(envKey, token string) string {
+	base := os.Getenv(envKey)
+	if base == "" {
+		return "Token: " + token
+	}
+
+	separator := "?"
+	if strings.Contains(base, "?") {
+		separator = "&"
+	}
+	return base + separator + "token=" + url.QueryEscape(token)
+}
+
+// generateAccountToken สร้าง token แบบสุ่ม (URL-safe ใส่ใน query string ได้)
+func generateAccountToken() (string, error) {
+	buf := make([]byte, accountTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate account token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashAccountToken คืนค่า SHA-256 (hex) ของ token สำหรับเก็บและค้นหาในฐานข้อมูล
+func hashAccountToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
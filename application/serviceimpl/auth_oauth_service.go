@@ -0,0 +1,450 @@
+// application/serviceimpl/auth_oauth_service.go
+package serviceimpl
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+const (
+	oauthStateTTL = 10 * time.Minute
+
+	// oauthExchangeTimeout เวลารอ provider แลก code และโหลด JWKS/userinfo
+	oauthExchangeTimeout = 30 * time.Second
+
+	maxUsernameLength = 50
+)
+
+var (
+	errUnsupportedOAuthProvider = errors.New("unsupported oauth provider")
+	errInvalidOAuthState        = errors.New("invalid or expired oauth state")
+	errOAuthSignInFailed        = errors.New("oauth sign-in failed")
+	errOAuthEmailConflict       = errors.New("an account with this email already exists, sign in with your password and link the provider")
+)
+
+// ListOAuthProviders ชื่อ provider ที่เปิดใช้ (เรียงตามตัวอักษร)
+func (s *authService) ListOAuthProviders() []string {
+	names := make([]string, 0, len(s.oauthProviders))
+	for name := range s.oauthProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StartOAuth เริ่ม authorization code flow: สร้าง state, PKCE verifier และ nonce แล้วคืนค่า URL ของ provider
+// linkUserID มีค่า = เชื่อมบัญชีให้ผู้ใช้ที่เข้าสู่ระบบอยู่ (callback ต้องเรียกผ่าน LinkOAuthIdentity)
+func (s *authService) StartOAuth(providerName string, linkUserID *uuid.UUID) (*dto.OAuthAuthorizationDTO, error) {
+	provider, ok := s.oauthProviders[providerName]
+	if !ok {
+		return nil, errUnsupportedOAuthProvider
+	}
+
+	if linkUserID != nil {
+		user, err := s.userRepo.FindByID(*linkUserID)
+		if err != nil || user == nil {
+			return nil, errors.New("user not found")
+		}
+		if user.IsBot {
+			return nil, errors.New("bot accounts cannot link oauth providers")
+		}
+	}
+
+	state, err := generateAccountToken()
+	if err != nil {
+		return nil, err
+	}
+	codeVerifier, err := generateAccountToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := generateAccountToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.oauthStateRepo.DeleteExpired(now); err != nil {
+		log.Printf("Failed to delete expired oauth states: %v", err)
+	}
+
+	record := &models.OAuthState{
+		ID:           uuid.New(),
+		StateHash:    hashAccountToken(state),
+		Provider:     provider.Name(),
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+		UserID:       linkUserID,
+		ExpiresAt:    now.Add(oauthStateTTL),
+		CreatedAt:    now,
+	}
+	if err := s.oauthStateRepo.Create(record); err != nil {
+		return nil, errors.New("failed to create oauth state: " + err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), oauthExchangeTimeout)
+	defer cancel()
+
+	authorizationURL, err := provider.AuthCodeURL(ctx, state, nonce, pkceChallenge(codeVerifier))
+	if err != nil {
+		log.Printf("Failed to build %s authorization url: %v", provider.Name(), err)
+		return nil, errors.New("oauth provider is unavailable")
+	}
+
+	return &dto.OAuthAuthorizationDTO{
+		Provider:         provider.Name(),
+		AuthorizationURL: authorizationURL,
+		State:            state,
+		ExpiresAt:        record.ExpiresAt,
+	}, nil
+}
+
+// LoginWithOAuth เข้าสู่ระบบด้วย authorization code จาก provider
+// หาผู้ใช้จากบัญชีที่เชื่อมไว้ หรือเชื่อมกับผู้ใช้เดิมที่อีเมลยืนยันแล้วตรงกัน หรือสร้างผู้ใช้ใหม่
+func (s *authService) LoginWithOAuth(providerName, code, state string, device *dto.SessionDeviceInfo) (*models.User, string, string, error) {
+	provider, record, identity, err := s.completeOAuth(providerName, code, state)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if record.UserID != nil {
+		// state นี้ออกให้สำหรับเชื่อมบัญชี ไม่ใช่เข้าสู่ระบบ
+		return nil, "", "", errInvalidOAuthState
+	}
+
+	user, link, err := s.resolveOAuthUser(provider.Name(), identity)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if user.IsBot {
+		return nil, "", "", errors.New("bot accounts cannot sign in with oauth")
+	}
+	if user.Status == models.UserStatusSuspended {
+		return nil, "", "", errors.New("account is suspended")
+	}
+
+	now := time.Now()
+	if link == nil {
+		link = &models.UserIdentity{
+			ID:        uuid.New(),
+			UserID:    user.ID,
+			Provider:  provider.Name(),
+			Subject:   identity.Subject,
+			Email:     identity.Email,
+			CreatedAt: now,
+		}
+		if err := s.userIdentityRepo.Create(link); err != nil {
+			return nil, "", "", errors.New("failed to link oauth identity: " + err.Error())
+		}
+	}
+	if err := s.userIdentityRepo.TouchLastLogin(link.ID, now); err != nil {
+		log.Printf("Failed to update identity last_login_at: %v", err)
+	}
+
+	// บัญชีที่เปิด 2FA ต้องยืนยันรหัสที่ LoginTwoFactor เหมือนการเข้าสู่ระบบด้วยรหัสผ่าน
+	if user.IsTwoFactorEnabled() {
+		challenge, err := s.issueTwoFactorChallenge(user.ID)
+		if err != nil {
+			return nil, "", "", err
+		}
+		return nil, "", "", challenge
+	}
+
+	user.LastActiveAt = &now
+	if err := s.userRepo.Update(user); err != nil {
+		log.Printf("Failed to update last_active_at: %v", err)
+	}
+
+	accessToken, refreshToken, err := s.createSession(user, device, false)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return user, accessToken, refreshToken, nil
+}
+
+// LinkOAuthIdentity เชื่อมบัญชีของ provider กับผู้ใช้ที่เข้าสู่ระบบอยู่ (state ต้องออกให้ผู้ใช้คนเดียวกัน)
+func (s *authService) LinkOAuthIdentity(userID uuid.UUID, providerName, code, state string) (*dto.UserIdentityDTO, error) {
+	provider, record, identity, err := s.completeOAuth(providerName, code, state)
+	if err != nil {
+		return nil, err
+	}
+	if record.UserID == nil || *record.UserID != userID {
+		return nil, errInvalidOAuthState
+	}
+
+	existing, err := s.userIdentityRepo.FindByProviderSubject(provider.Name(), identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.UserID != userID {
+			return nil, errors.New("this provider account is already linked to another user")
+		}
+		return toUserIdentityDTO(existing), nil
+	}
+
+	current, err := s.userIdentityRepo.FindByUserAndProvider(userID, provider.Name())
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		return nil, errors.New("provider is already linked")
+	}
+
+	link := &models.UserIdentity{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Provider:  provider.Name(),
+		Subject:   identity.Subject,
+		Email:     identity.Email,
+		CreatedAt: time.Now(),
+	}
+	if err := s.userIdentityRepo.Create(link); err != nil {
+		return nil, errors.New("failed to link oauth identity: " + err.Error())
+	}
+
+	return toUserIdentityDTO(link), nil
+}
+
+// ListOAuthIdentities รายการบัญชีภายนอกที่ผู้ใช้เชื่อมไว้
+func (s *authService) ListOAuthIdentities(userID uuid.UUID) ([]*dto.UserIdentityDTO, error) {
+	identities, err := s.userIdentityRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.UserIdentityDTO, 0, len(identities))
+	for _, identity := range identities {
+		result = append(result, toUserIdentityDTO(identity))
+	}
+	return result, nil
+}
+
+// UnlinkOAuthIdentity ยกเลิกการเชื่อมบัญชี (ไม่อนุญาตถ้าเป็นช่องทางเข้าสู่ระบบสุดท้ายของผู้ใช้)
+func (s *authService) UnlinkOAuthIdentity(userID, identityID uuid.UUID) error {
+	identity, err := s.userIdentityRepo.FindByID(identityID)
+	if err != nil {
+		return err
+	}
+	if identity == nil || identity.UserID != userID {
+		return errors.New("identity not found")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user == nil {
+		return errors.New("user not found")
+	}
+
+	if user.PasswordHash == "" {
+		identities, err := s.userIdentityRepo.ListByUserID(userID)
+		if err != nil {
+			return err
+		}
+		if len(identities) <= 1 {
+			return errors.New("cannot unlink the last sign-in method, set a password first")
+		}
+	}
+
+	return s.userIdentityRepo.Delete(identity.ID)
+}
+
+// completeOAuth ใช้ state (ครั้งเดียว) แลก code กับ provider และตรวจ nonce
+func (s *authService) completeOAuth(providerName, code, state string) (service.OAuthProvider, *models.OAuthState, *service.OAuthIdentity, error) {
+	provider, ok := s.oauthProviders[providerName]
+	if !ok {
+		return nil, nil, nil, errUnsupportedOAuthProvider
+	}
+
+	code = strings.TrimSpace(code)
+	state = strings.TrimSpace(state)
+	if code == "" || state == "" {
+		return nil, nil, nil, errors.New("code and state are required")
+	}
+
+	record, err := s.oauthStateRepo.FindByStateHash(hashAccountToken(state))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	now := time.Now()
+	if record == nil || record.Provider != provider.Name() || record.UsedAt != nil || !now.Before(record.ExpiresAt) {
+		return nil, nil, nil, errInvalidOAuthState
+	}
+
+	consumed, err := s.oauthStateRepo.Consume(record.ID, now)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !consumed {
+		return nil, nil, nil, errInvalidOAuthState
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), oauthExchangeTimeout)
+	defer cancel()
+
+	identity, err := provider.Exchange(ctx, code, record.CodeVerifier)
+	if err != nil {
+		log.Printf("OAuth exchange with %s failed: %v", provider.Name(), err)
+		return nil, nil, nil, errOAuthSignInFailed
+	}
+	if subtle.ConstantTimeCompare([]byte(identity.Nonce), []byte(record.Nonce)) != 1 {
+		log.Printf("OAuth exchange with %s failed: nonce mismatch", provider.Name())
+		return nil, nil, nil, errOAuthSignInFailed
+	}
+
+	return provider, record, identity, nil
+}
+
+// resolveOAuthUser หาผู้ใช้ของบัญชีภายนอก คืนค่า identity ที่เชื่อมไว้แล้ว (nil = ต้องเชื่อมใหม่)
+// เชื่อมอัตโนมัติตามอีเมลเฉพาะเมื่อทั้ง provider และผู้ใช้เดิมยืนยันอีเมลนั้นแล้ว
+func (s *authService) resolveOAuthUser(providerName string, identity *service.OAuthIdentity) (*models.User, *models.UserIdentity, error) {
+	link, err := s.userIdentityRepo.FindByProviderSubject(providerName, identity.Subject)
+	if err != nil {
+		return nil, nil, err
+	}
+	if link != nil {
+		user, err := s.userRepo.FindByID(link.UserID)
+		if err != nil || user == nil {
+			return nil, nil, errors.New("user not found")
+		}
+		return user, link, nil
+	}
+
+	email := strings.TrimSpace(identity.Email)
+	if email == "" {
+		return nil, nil, errors.New("oauth provider did not return an email address")
+	}
+
+	existing, err := s.userRepo.FindByEmail(email)
+	if err == nil && existing != nil {
+		if !identity.EmailVerified || existing.EmailVerifiedAt == nil {
+			return nil, nil, errOAuthEmailConflict
+		}
+		// ผู้ใช้เดิมมีบัญชีของ provider นี้อยู่แล้ว (subject อื่น) ให้เข้าสู่ระบบด้วยบัญชีนั้นแทน
+		current, err := s.userIdentityRepo.FindByUserAndProvider(existing.ID, providerName)
+		if err != nil {
+			return nil, nil, err
+		}
+		if current != nil {
+			return nil, nil, errOAuthEmailConflict
+		}
+		return existing, nil, nil
+	}
+
+	user, err := s.createOAuthUser(email, identity)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, nil, nil
+}
+
+// createOAuthUser สร้างผู้ใช้ใหม่จากข้อมูลของ provider (ไม่มีรหัสผ่าน ตั้งภายหลังผ่าน forgot-password ได้)
+func (s *authService) createOAuthUser(email string, identity *service.OAuthIdentity) (*models.User, error) {
+	username, err := s.oauthUsername(email)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	displayName := truncateRunes(strings.TrimSpace(identity.Name), 100)
+	if displayName == "" {
+		displayName = username
+	}
+
+	user := &models.User{
+		ID:              uuid.New(),
+		Username:        username,
+		Email:           email,
+		DisplayName:     displayName,
+		ProfileImageURL: identity.Picture,
+		Status:          "active",
+		CreatedAt:       now,
+		Settings:        types.JSONB{},
+	}
+	if identity.EmailVerified {
+		user.EmailVerifiedAt = &now
+	}
+
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, errors.New("failed to create user: " + err.Error())
+	}
+
+	if user.EmailVerifiedAt == nil {
+		if err := s.SendEmailVerification(user.ID); err != nil {
+			log.Printf("Failed to send email verification: %v", err)
+		}
+	}
+
+	return user, nil
+}
+
+// oauthUsername สร้าง username ที่ยังไม่ถูกใช้จากส่วนหน้า @ ของอีเมล
+func (s *authService) oauthUsername(email string) (string, error) {
+	local := strings.ToLower(email)
+	if at := strings.IndexByte(local, '@'); at >= 0 {
+		local = local[:at]
+	}
+
+	var b strings.Builder
+	for _, r := range local {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			b.WriteRune(r)
+		case r == '.' || r == '-' || r == '+':
+			b.WriteRune('_')
+		}
+	}
+	base := strings.Trim(b.String(), "_")
+	if base == "" {
+		base = "user"
+	}
+	if len(base) > maxUsernameLength-5 {
+		base = base[:maxUsernameLength-5]
+	}
+
+	candidate := base
+	for attempt := 0; attempt < 10; attempt++ {
+		if existing, err := s.userRepo.FindByUsername(candidate); err != nil || existing == nil {
+			return candidate, nil
+		}
+
+		suffix, err := rand.Int(rand.Reader, big.NewInt(10000))
+		if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s_%04d", base, suffix.Int64())
+	}
+
+	return "", errors.New("failed to generate a unique username")
+}
+
+// pkceChallenge คืนค่า code_challenge แบบ S256 ของ verifier (RFC 7636)
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func toUserIdentityDTO(identity *models.UserIdentity) *dto.UserIdentityDTO {
+	return &dto.UserIdentityDTO{
+		ID:          identity.ID,
+		Provider:    identity.Provider,
+		Email:       identity.Email,
+		CreatedAt:   identity.CreatedAt,
+		LastLoginAt: identity.LastLoginAt,
+	}
+}
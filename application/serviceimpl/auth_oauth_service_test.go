@@ -0,0 +1,197 @@
+// application/serviceimpl/auth_oauth_service_test.go
+package serviceimpl
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+)
+
+// memoryOAuthStateRepo เก็บ oauth state ในหน่วยความจำ (Consume ใช้ได้ครั้งเดียวแบบเดียวกับ postgres)
+type memoryOAuthStateRepo struct {
+	repository.OAuthStateRepository
+	mu     sync.Mutex
+	states map[string]*models.OAuthState
+}
+
+func (r *memoryOAuthStateRepo) Create(state *models.OAuthState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states[state.StateHash] = state
+	return nil
+}
+
+func (r *memoryOAuthStateRepo) FindByStateHash(stateHash string) (*models.OAuthState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.states[stateHash], nil
+}
+
+func (r *memoryOAuthStateRepo) Consume(id uuid.UUID, usedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, state := range r.states {
+		if state.ID == id && state.UsedAt == nil && usedAt.Before(state.ExpiresAt) {
+			state.UsedAt = &usedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryOAuthStateRepo) DeleteExpired(time.Time) error {
+	return nil
+}
+
+// stubOAuthUserRepo ผู้ใช้เดิมที่ค้นหาได้ตามอีเมล
+type stubOAuthUserRepo struct {
+	repository.UserRepository
+	existing *models.User
+}
+
+func (r *stubOAuthUserRepo) FindByEmail(email string) (*models.User, error) {
+	if r.existing != nil && strings.EqualFold(r.existing.Email, email) {
+		return r.existing, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+// stubUserIdentityRepo ยังไม่มีบัญชีภายนอกที่เชื่อมไว้
+type stubUserIdentityRepo struct {
+	repository.UserIdentityRepository
+}
+
+func (r *stubUserIdentityRepo) FindByProviderSubject(string, string) (*models.UserIdentity, error) {
+	return nil, nil
+}
+
+func (r *stubUserIdentityRepo) FindByUserAndProvider(uuid.UUID, string) (*models.UserIdentity, error) {
+	return nil, nil
+}
+
+// stubOAuthProvider จำ code_challenge/nonce จาก AuthCodeURL และตรวจ PKCE ตอน Exchange เหมือน provider จริง
+type stubOAuthProvider struct {
+	challenge string
+	nonce     string
+	identity  service.OAuthIdentity // Nonce ว่าง = คืน nonce ที่ได้ตอนเริ่ม flow
+}
+
+func (p *stubOAuthProvider) Name() string {
+	return "mock"
+}
+
+func (p *stubOAuthProvider) AuthCodeURL(_ context.Context, state, nonce, codeChallenge string) (string, error) {
+	p.challenge = codeChallenge
+	p.nonce = nonce
+	query := url.Values{"state": {state}, "nonce": {nonce}, "code_challenge": {codeChallenge}}
+	return "https://issuer.example.com/authorize?" + query.Encode(), nil
+}
+
+func (p *stubOAuthProvider) Exchange(_ context.Context, code, codeVerifier string) (*service.OAuthIdentity, error) {
+	if pkceChallenge(codeVerifier) != p.challenge {
+		return nil, errors.New("invalid_grant")
+	}
+	identity := p.identity
+	if identity.Nonce == "" {
+		identity.Nonce = p.nonce
+	}
+	return &identity, nil
+}
+
+func newOAuthTestService(provider *stubOAuthProvider, existing *models.User) *authService {
+	return &authService{
+		userRepo:         &stubOAuthUserRepo{existing: existing},
+		userIdentityRepo: &stubUserIdentityRepo{},
+		oauthStateRepo:   &memoryOAuthStateRepo{states: map[string]*models.OAuthState{}},
+		oauthProviders:   map[string]service.OAuthProvider{provider.Name(): provider},
+	}
+}
+
+func TestCompleteOAuthUsesPKCEAndStateOnce(t *testing.T) {
+	provider := &stubOAuthProvider{identity: service.OAuthIdentity{Subject: "subject-1", Email: "alice@example.com"}}
+	s := newOAuthTestService(provider, nil)
+
+	authorization, err := s.StartOAuth("mock", nil)
+	if err != nil {
+		t.Fatalf("start oauth: %v", err)
+	}
+	if provider.challenge == "" || provider.nonce == "" {
+		t.Fatal("expected code challenge and nonce to be sent to the provider")
+	}
+
+	_, _, identity, err := s.completeOAuth("mock", "code", authorization.State)
+	if err != nil {
+		t.Fatalf("complete oauth: %v", err)
+	}
+	if identity.Subject != "subject-1" {
+		t.Fatalf("unexpected identity %+v", identity)
+	}
+
+	if _, _, _, err := s.completeOAuth("mock", "code", authorization.State); !errors.Is(err, errInvalidOAuthState) {
+		t.Fatalf("expected a used state to be rejected, got %v", err)
+	}
+}
+
+func TestCompleteOAuthRejectsNonceMismatch(t *testing.T) {
+	provider := &stubOAuthProvider{identity: service.OAuthIdentity{Subject: "subject-1", Nonce: "replayed-nonce"}}
+	s := newOAuthTestService(provider, nil)
+
+	authorization, err := s.StartOAuth("mock", nil)
+	if err != nil {
+		t.Fatalf("start oauth: %v", err)
+	}
+
+	if _, _, _, err := s.completeOAuth("mock", "code", authorization.State); !errors.Is(err, errOAuthSignInFailed) {
+		t.Fatalf("expected nonce mismatch to fail sign-in, got %v", err)
+	}
+}
+
+func TestResolveOAuthUserLinksOnlyVerifiedEmails(t *testing.T) {
+	verifiedAt := time.Now()
+
+	cases := []struct {
+		name             string
+		identityVerified bool
+		userVerifiedAt   *time.Time
+		wantLink         bool
+	}{
+		{"both verified", true, &verifiedAt, true},
+		{"provider email unverified", false, &verifiedAt, false},
+		{"local email unverified", true, nil, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			existing := &models.User{ID: uuid.New(), Email: "alice@example.com", EmailVerifiedAt: tc.userVerifiedAt}
+			s := newOAuthTestService(&stubOAuthProvider{}, existing)
+
+			user, link, err := s.resolveOAuthUser("mock", &service.OAuthIdentity{
+				Subject:       "subject-1",
+				Email:         "Alice@example.com",
+				EmailVerified: tc.identityVerified,
+			})
+
+			if !tc.wantLink {
+				if !errors.Is(err, errOAuthEmailConflict) {
+					t.Fatalf("expected email conflict, got user=%v err=%v", user, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolve oauth user: %v", err)
+			}
+			if user.ID != existing.ID || link != nil {
+				t.Fatalf("expected existing user to be linked, got user=%v link=%v", user, link)
+			}
+		})
+	}
+}
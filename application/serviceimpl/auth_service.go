@@ -2,39 +2,88 @@
 package serviceimpl
 
 import (
+	"context"
 	"errors"
 	"log"
-	"os"
+	"strings"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5" // เปลี่ยนเป็น v5
 	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
 	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/port"
 	"github.com/thizplus/gofiber-chat-api/domain/repository"
 	"github.com/thizplus/gofiber-chat-api/domain/service"
 	"github.com/thizplus/gofiber-chat-api/domain/types"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// อายุของ token
+const (
+	accessTokenTTL  = time.Hour * 24
+	refreshTokenTTL = time.Hour * 24 * 30
+
+	maxSessionDeviceNameLength = 100
+	maxSessionUserAgentLength  = 512
+)
+
 type authService struct {
 	userRepo           repository.UserRepository
 	refreshTokenRepo   repository.RefreshTokenRepository
 	tokenBlacklistRepo repository.TokenBlacklistRepository
+	accountTokenRepo   repository.AccountTokenRepository
+	recoveryCodeRepo   repository.TwoFactorRecoveryCodeRepository
+	userIdentityRepo   repository.UserIdentityRepository
+	oauthStateRepo     repository.OAuthStateRepository
+	webSocketPort      port.WebSocketPort
+	loginGuard         service.LoginAttemptGuard // นับความผิดพลาดตาม username+IP และรหัส 2FA
+	accountGuard       service.LoginAttemptGuard // นับความผิดพลาดรวมทั้งบัญชี (threshold สูงกว่า loginGuard มาก)
+	mailer             service.Mailer
+	tokenSigner        service.TokenSigner
+	twoFactorKey       []byte                           // คีย์ AES-256 ของ TOTP secret
+	oauthProviders     map[string]service.OAuthProvider // key = ชื่อ provider
 }
 
 func NewAuthService(
 	userRepo repository.UserRepository,
 	refreshTokenRepo repository.RefreshTokenRepository,
 	tokenBlacklistRepo repository.TokenBlacklistRepository,
+	accountTokenRepo repository.AccountTokenRepository,
+	recoveryCodeRepo repository.TwoFactorRecoveryCodeRepository,
+	userIdentityRepo repository.UserIdentityRepository,
+	oauthStateRepo repository.OAuthStateRepository,
+	webSocketPort port.WebSocketPort,
+	loginGuard service.LoginAttemptGuard,
+	accountGuard service.LoginAttemptGuard,
+	mailer service.Mailer,
+	tokenSigner service.TokenSigner,
+	twoFactorKey []byte,
+	oauthProviders []service.OAuthProvider,
 ) service.AuthService {
+	providers := make(map[string]service.OAuthProvider, len(oauthProviders))
+	for _, provider := range oauthProviders {
+		providers[provider.Name()] = provider
+	}
+
 	return &authService{
 		userRepo:           userRepo,
 		refreshTokenRepo:   refreshTokenRepo,
 		tokenBlacklistRepo: tokenBlacklistRepo,
+		accountTokenRepo:   accountTokenRepo,
+		recoveryCodeRepo:   recoveryCodeRepo,
+		userIdentityRepo:   userIdentityRepo,
+		oauthStateRepo:     oauthStateRepo,
+		webSocketPort:      webSocketPort,
+		loginGuard:         loginGuard,
+		accountGuard:       accountGuard,
+		mailer:             mailer,
+		tokenSigner:        tokenSigner,
+		twoFactorKey:       twoFactorKey,
+		oauthProviders:     providers,
 	}
 }
 
-func (s *authService) Register(username, password, email, displayName string) (*models.User, string, string, error) {
+func (s *authService) Register(username, password, email, displayName string, device *dto.SessionDeviceInfo) (*models.User, string, string, error) {
 
 	// ตรวจสอบข้อมูลขั้นต่ำ
 	if username == "" || password == "" {
@@ -70,43 +119,66 @@ func (s *authService) Register(username, password, email, displayName string) (*
 		return nil, "", "", errors.New("failed to create user: " + err.Error())
 	}
 
-	// สร้าง tokens
-	accessToken, refreshToken, err := s.generateTokens(user.ID, username)
-	if err != nil {
-		return nil, "", "", errors.New("failed to generate tokens: " + err.Error())
-	}
-
-	// บันทึก refresh token
-	refreshTokenModel := &models.RefreshToken{
-		UserID:    user.ID,
-		Token:     refreshToken,
-		ExpiresAt: time.Now().Add(time.Hour * 24 * 30),
-		CreatedAt: time.Now(),
-		Revoked:   false,
+	// ส่งลิงก์ยืนยันอีเมล (ไม่กระทบการลงทะเบียนถ้าส่งไม่สำเร็จ)
+	if user.Email != "" {
+		if err := s.SendEmailVerification(user.ID); err != nil {
+			log.Printf("Failed to send email verification: %v", err)
+		}
 	}
 
-	if err := s.refreshTokenRepo.Create(refreshTokenModel); err != nil {
-		// อาจบันทึกข้อผิดพลาดที่นี่ แต่ยังคงดำเนินการต่อไป
+	// สร้าง session และ tokens
+	accessToken, refreshToken, err := s.createSession(user, device, false)
+	if err != nil {
+		return nil, "", "", err
 	}
 
 	return user, accessToken, refreshToken, nil
 }
 
-func (s *authService) Login(username, password string) (*models.User, string, string, error) {
+func (s *authService) Login(username, password string, device *dto.SessionDeviceInfo) (*models.User, string, string, error) {
 	// ตรวจสอบข้อมูลขั้นต่ำ
 	if username == "" || password == "" {
 		return nil, "", "", errors.New("username and password are required")
 	}
 
+	// ล็อกชั่วคราวจากการใส่รหัสผ่านผิดซ้ำ (นับตาม username แม้ไม่มีผู้ใช้นี้ เพื่อไม่ให้เดาได้ว่ามีบัญชีหรือไม่)
+	// นับแยกตาม IP เพื่อไม่ให้ผู้อื่นล็อกบัญชีได้ง่าย และนับรวมทั้งบัญชีด้วย threshold ที่สูงกว่ามากเพื่อจำกัดการเดาจากหลาย IP
+	guardKey := loginGuardKey(username, device)
+	accountKey := accountGuardKey(username)
+	lockedFor := s.loginLockedFor(guardKey)
+	if accountLockedFor := s.accountLockedFor(accountKey); accountLockedFor > lockedFor {
+		lockedFor = accountLockedFor
+	}
+	if lockedFor > 0 {
+		return nil, "", "", &service.LoginLockedError{RetryAfter: lockedFor}
+	}
+
 	// ค้นหาผู้ใช้
 	user, err := s.userRepo.FindByUsername(username)
 	if err != nil {
-		return nil, "", "", errors.New("invalid username or password")
+		return nil, "", "", s.loginFailed(guardKey, accountKey)
 	}
 
 	// ตรวจสอบรหัสผ่าน
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return nil, "", "", errors.New("invalid username or password")
+		return nil, "", "", s.loginFailed(guardKey, accountKey)
+	}
+
+	s.resetGuard(guardKey)
+	s.resetAccountGuard(accountKey)
+
+	// บัญชีที่ถูกระงับโดยผู้ดูแลระบบเข้าสู่ระบบไม่ได้
+	if user.Status == models.UserStatusSuspended {
+		return nil, "", "", errors.New("account is suspended")
+	}
+
+	// บัญชีที่เปิด 2FA ต้องยืนยันรหัสที่ LoginTwoFactor ก่อนจึงจะได้ token
+	if user.IsTwoFactorEnabled() {
+		challenge, err := s.issueTwoFactorChallenge(user.ID)
+		if err != nil {
+			return nil, "", "", err
+		}
+		return nil, "", "", challenge
 	}
 
 	// อัปเดตเวลาใช้งานล่าสุด
@@ -117,34 +189,98 @@ func (s *authService) Login(username, password string) (*models.User, string, st
 		log.Printf("Failed to update last_active_at: %v", err)
 	}
 
-	// สร้าง tokens
-	accessToken, refreshToken, err := s.generateTokens(user.ID, username)
+	// สร้าง session ใหม่สำหรับอุปกรณ์นี้ (session ของอุปกรณ์อื่นยังคงใช้งานได้)
+	accessToken, refreshToken, err := s.createSession(user, device, false)
 	if err != nil {
-		return nil, "", "", errors.New("failed to generate tokens: " + err.Error())
+		return nil, "", "", err
 	}
 
-	// ยกเลิก refresh tokens เดิม
-	if err := s.refreshTokenRepo.RevokeByUserID(user.ID); err != nil {
-		// อาจบันทึกข้อผิดพลาดที่นี่ แต่ยังคงดำเนินการต่อไป
+	return user, accessToken, refreshToken, nil
+}
+
+// loginGuardKey key ของตัวนับการเข้าสู่ระบบผิดตาม IP และ username (username ไม่สนตัวพิมพ์ใหญ่เล็ก)
+func loginGuardKey(username string, device *dto.SessionDeviceInfo) string {
+	ip := ""
+	if device != nil {
+		ip = device.IPAddress
 	}
+	return "ip:" + ip + ":" + strings.ToLower(strings.TrimSpace(username))
+}
 
-	// บันทึก refresh token ใหม่
-	refreshTokenModel := &models.RefreshToken{
-		UserID:    user.ID,
-		Token:     refreshToken,
-		ExpiresAt: time.Now().Add(time.Hour * 24 * 30),
-		CreatedAt: time.Now(),
-		Revoked:   false,
+// accountGuardKey key ของตัวนับการเข้าสู่ระบบผิดรวมทุก IP ของ username
+func accountGuardKey(username string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(username))
+}
+
+// loginLockedFor คืนค่าเวลาที่เหลือของการล็อก (Redis มีปัญหา = ไม่ล็อก)
+func (s *authService) loginLockedFor(key string) time.Duration {
+	if s.loginGuard == nil {
+		return 0
 	}
+	lockedFor, err := s.loginGuard.LockedFor(context.Background(), key)
+	if err != nil {
+		log.Printf("Failed to check login lock: %v", err)
+		return 0
+	}
+	return lockedFor
+}
 
-	if err := s.refreshTokenRepo.Create(refreshTokenModel); err != nil {
-		// อาจบันทึกข้อผิดพลาดที่นี่ แต่ยังคงดำเนินการต่อไป
+// accountLockedFor คืนค่าเวลาที่เหลือของการล็อกทั้งบัญชี (Redis มีปัญหา = ไม่ล็อก)
+func (s *authService) accountLockedFor(key string) time.Duration {
+	if s.accountGuard == nil {
+		return 0
+	}
+	lockedFor, err := s.accountGuard.LockedFor(context.Background(), key)
+	if err != nil {
+		log.Printf("Failed to check account lock: %v", err)
+		return 0
 	}
+	return lockedFor
+}
 
-	return user, accessToken, refreshToken, nil
+// loginFailed บันทึกการเข้าสู่ระบบผิดทั้งตาม IP และทั้งบัญชี คืนค่า LoginLockedError ถ้าครั้งนี้ทำให้ถูกล็อก
+func (s *authService) loginFailed(key, accountKey string) error {
+	invalid := errors.New("invalid username or password")
+	if s.accountGuard != nil {
+		lockedFor, err := s.accountGuard.RecordFailure(context.Background(), accountKey)
+		if err != nil {
+			log.Printf("Failed to record account login failure: %v", err)
+		} else if lockedFor > 0 {
+			s.guardFailure(key, invalid)
+			return &service.LoginLockedError{RetryAfter: lockedFor}
+		}
+	}
+	return s.guardFailure(key, invalid)
 }
 
-func (s *authService) RefreshToken(refreshTokenStr string) (string, string, error) {
+// resetAccountGuard ล้างตัวนับความผิดพลาดของทั้งบัญชี (Redis มีปัญหา = บันทึก log)
+func (s *authService) resetAccountGuard(key string) {
+	if s.accountGuard == nil {
+		return
+	}
+	if err := s.accountGuard.Reset(context.Background(), key); err != nil {
+		log.Printf("Failed to reset account login failures: %v", err)
+	}
+}
+
+// guardFailure บันทึกความผิดพลาดของ key คืนค่า LoginLockedError ถ้าครั้งนี้ทำให้ถูกล็อก (ไม่เช่นนั้นคืนค่า invalid)
+func (s *authService) guardFailure(key string, invalid error) error {
+	if s.loginGuard == nil {
+		return invalid
+	}
+
+	lockedFor, err := s.loginGuard.RecordFailure(context.Background(), key)
+	if err != nil {
+		log.Printf("Failed to record login failure: %v", err)
+		return invalid
+	}
+	if lockedFor > 0 {
+		return &service.LoginLockedError{RetryAfter: lockedFor}
+	}
+	return invalid
+}
+
+func (s *authService) RefreshToken(refreshTokenStr string, device *dto.SessionDeviceInfo) (string, string, error) {
 	// ตรวจสอบ refresh token ในฐานข้อมูล
 	refreshTokenModel, err := s.refreshTokenRepo.FindByToken(refreshTokenStr)
 	if err != nil {
@@ -161,82 +297,264 @@ func (s *authService) RefreshToken(refreshTokenStr string) (string, string, erro
 	if err != nil {
 		return "", "", errors.New("user not found")
 	}
+	if user.Status == models.UserStatusSuspended {
+		return "", "", errors.New("account is suspended")
+	}
+
+	// บัญชีที่เปิด 2FA รีเฟรชได้เฉพาะ session ที่ผ่าน 2FA (session ที่สร้างก่อนเปิดใช้จะถูกเพิกถอน)
+	if user.IsTwoFactorEnabled() && !refreshTokenModel.TwoFactorVerified {
+		if err := s.revokeSessions(user.ID, []uuid.UUID{refreshTokenModel.ID}); err != nil {
+			log.Printf("Failed to revoke unverified session: %v", err)
+		}
+		return "", "", errors.New("two-factor authentication required")
+	}
 
-	// สร้าง tokens ใหม่
-	accessToken, newRefreshToken, err := s.generateTokens(user.ID, user.Username)
+	// สร้าง tokens ใหม่ใน session เดิม
+	accessToken, newRefreshToken, err := s.generateTokens(user.ID, user.Username, refreshTokenModel.ID)
 	if err != nil {
 		return "", "", errors.New("failed to generate tokens: " + err.Error())
 	}
 
-	// ยกเลิก refresh token เดิม
-	if err := s.refreshTokenRepo.RevokeByUserID(user.ID); err != nil {
-		// อาจบันทึกข้อผิดพลาดที่นี่ แต่ยังคงดำเนินการต่อไป
+	// หมุนเวียน refresh token (ถ้าถูกใช้ไปแล้วโดย request อื่นถือว่าไม่ถูกต้อง)
+	now := time.Now()
+	refreshTokenModel.Token = newRefreshToken
+	refreshTokenModel.ExpiresAt = now.Add(refreshTokenTTL)
+	refreshTokenModel.LastUsedAt = now
+	applySessionDevice(refreshTokenModel, device, false)
+
+	rotated, err := s.refreshTokenRepo.Rotate(refreshTokenModel, refreshTokenStr)
+	if err != nil {
+		return "", "", errors.New("failed to rotate refresh token: " + err.Error())
+	}
+	if !rotated {
+		return "", "", errors.New("invalid refresh token")
 	}
 
 	// อัปเดตเวลาใช้งานล่าสุด
-	now := time.Now()
 	user.LastActiveAt = &now
 	s.userRepo.Update(user)
 
-	// บันทึก refresh token ใหม่
-	newRefreshTokenModel := &models.RefreshToken{
-		UserID:    user.ID,
-		Token:     newRefreshToken,
-		ExpiresAt: time.Now().Add(time.Hour * 24 * 30),
-		CreatedAt: time.Now(),
-		Revoked:   false,
+	return accessToken, newRefreshToken, nil
+}
+
+// Logout ออกจากระบบเฉพาะอุปกรณ์ปัจจุบัน (token แบบเก่าที่ไม่มี session ID จะออกจากระบบทุกอุปกรณ์)
+func (s *authService) Logout(userID uuid.UUID, sessionID *uuid.UUID) error {
+	if sessionID == nil {
+		return s.refreshTokenRepo.RevokeByUserID(userID)
 	}
 
-	if err := s.refreshTokenRepo.Create(newRefreshTokenModel); err != nil {
-		// อาจบันทึกข้อผิดพลาดที่นี่ แต่ยังคงดำเนินการต่อไป
+	session, err := s.refreshTokenRepo.FindByID(*sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil || session.UserID != userID {
+		return nil
 	}
 
-	return accessToken, newRefreshToken, nil
+	return s.revokeSessions(userID, []uuid.UUID{session.ID})
+}
+
+// ListSessions ดึง session ที่ยังใช้งานได้ของผู้ใช้ (ใช้ล่าสุดก่อน)
+func (s *authService) ListSessions(userID uuid.UUID, currentSessionID *uuid.UUID) ([]*dto.SessionDTO, error) {
+	sessions, err := s.refreshTokenRepo.FindActiveByUserID(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.SessionDTO, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, &dto.SessionDTO{
+			ID:         session.ID,
+			DeviceName: session.DeviceName,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			IsCurrent:  currentSessionID != nil && *currentSessionID == session.ID,
+		})
+	}
+
+	return result, nil
 }
 
-func (s *authService) Logout(userID uuid.UUID) error {
-	return s.refreshTokenRepo.RevokeByUserID(userID)
+// RevokeSession ออกจากระบบอุปกรณ์หนึ่งเครื่อง
+func (s *authService) RevokeSession(userID, sessionID uuid.UUID) error {
+	session, err := s.refreshTokenRepo.FindByID(sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil || session.UserID != userID || session.Revoked {
+		return errors.New("session not found")
+	}
+
+	return s.revokeSessions(userID, []uuid.UUID{session.ID})
+}
+
+// RevokeOtherSessions ออกจากระบบทุกอุปกรณ์ยกเว้นอุปกรณ์ปัจจุบัน คืนจำนวน session ที่ถูกเพิกถอน
+func (s *authService) RevokeOtherSessions(userID uuid.UUID, currentSessionID *uuid.UUID) (int, error) {
+	if currentSessionID == nil {
+		return 0, errors.New("current session not found")
+	}
+
+	sessions, err := s.refreshTokenRepo.FindActiveByUserID(userID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	sessionIDs := make([]uuid.UUID, 0, len(sessions))
+	for _, session := range sessions {
+		if session.ID != *currentSessionID {
+			sessionIDs = append(sessionIDs, session.ID)
+		}
+	}
+
+	if err := s.revokeSessions(userID, sessionIDs); err != nil {
+		return 0, err
+	}
+	return len(sessionIDs), nil
+}
+
+// RevokeAllSessions ออกจากระบบทุกอุปกรณ์ของผู้ใช้ (เช่น เมื่อบัญชีถูกระงับ)
+func (s *authService) RevokeAllSessions(userID uuid.UUID) error {
+	sessions, err := s.refreshTokenRepo.FindActiveByUserID(userID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	sessionIDs := make([]uuid.UUID, 0, len(sessions))
+	for _, session := range sessions {
+		sessionIDs = append(sessionIDs, session.ID)
+	}
+
+	return s.revokeSessions(userID, sessionIDs)
+}
+
+// IsTokenRevoked ตรวจสอบ blacklist ทั้งตัว token และ session ของมันในครั้งเดียว
+func (s *authService) IsTokenRevoked(token string, sessionID *uuid.UUID) (bool, error) {
+	keys := []string{token}
+	if sessionID != nil {
+		keys = append(keys, models.SessionBlacklistKey(*sessionID))
+	}
+	return s.tokenBlacklistRepo.IsAnyTokenBlacklisted(keys)
+}
+
+// revokeSessions เพิกถอน refresh token, blacklist access token ทั้งหมดของ session และตัดการเชื่อมต่อ WebSocket
+func (s *authService) revokeSessions(userID uuid.UUID, sessionIDs []uuid.UUID) error {
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+
+	if err := s.refreshTokenRepo.RevokeByIDs(sessionIDs); err != nil {
+		return err
+	}
+
+	// access token มีอายุไม่เกิน accessTokenTTL หลังจากนี้ entry ใน blacklist ก็ไม่จำเป็นแล้ว
+	now := time.Now()
+	for _, sessionID := range sessionIDs {
+		if err := s.tokenBlacklistRepo.Create(&models.TokenBlacklist{
+			Token:     models.SessionBlacklistKey(sessionID),
+			UserID:    userID,
+			ExpiredAt: now.Add(accessTokenTTL),
+			CreatedAt: now,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if s.webSocketPort != nil {
+		s.webSocketPort.DisconnectSessions(userID, sessionIDs)
+	}
+
+	return nil
+}
+
+// createSession สร้าง session ใหม่ของอุปกรณ์พร้อม access/refresh token
+// twoFactorVerified = session ผ่านการยืนยัน 2FA แล้ว (เข้าสู่ระบบผ่าน LoginTwoFactor)
+func (s *authService) createSession(user *models.User, device *dto.SessionDeviceInfo, twoFactorVerified bool) (string, string, error) {
+	now := time.Now()
+	session := &models.RefreshToken{
+		ID:                uuid.New(),
+		UserID:            user.ID,
+		LastUsedAt:        now,
+		ExpiresAt:         now.Add(refreshTokenTTL),
+		CreatedAt:         now,
+		Revoked:           false,
+		TwoFactorVerified: twoFactorVerified,
+	}
+	applySessionDevice(session, device, true)
+
+	accessToken, refreshToken, err := s.generateTokens(user.ID, user.Username, session.ID)
+	if err != nil {
+		return "", "", errors.New("failed to generate tokens: " + err.Error())
+	}
+	session.Token = refreshToken
+
+	if err := s.refreshTokenRepo.Create(session); err != nil {
+		return "", "", errors.New("failed to create session: " + err.Error())
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// applySessionDevice บันทึกข้อมูลอุปกรณ์ลง session (ชื่ออุปกรณ์กำหนดได้เฉพาะตอนเข้าสู่ระบบ)
+func applySessionDevice(session *models.RefreshToken, device *dto.SessionDeviceInfo, setName bool) {
+	if device == nil {
+		return
+	}
+
+	if setName {
+		session.DeviceName = truncateRunes(strings.TrimSpace(device.DeviceName), maxSessionDeviceNameLength)
+	}
+	if device.UserAgent != "" {
+		session.UserAgent = truncateRunes(device.UserAgent, maxSessionUserAgentLength)
+	}
+	if device.IPAddress != "" {
+		session.IPAddress = device.IPAddress
+	}
 }
 
 func (s *authService) GetUserByID(userID uuid.UUID) (*models.User, error) {
 	return s.userRepo.FindByID(userID)
 }
 
-func (s *authService) generateTokens(userID uuid.UUID, username string) (string, string, error) {
+// JSONWebKeys public key ที่ใช้ตรวจ token ได้ (รวม key เดิมระหว่างหมุน key)
+func (s *authService) JSONWebKeys() []service.JSONWebKey {
+	return s.tokenSigner.PublicKeys()
+}
+
+func (s *authService) generateTokens(userID uuid.UUID, username string, sessionID uuid.UUID) (string, string, error) {
 	now := time.Now()
 
 	// สร้าง Access Token (อายุสั้น)
-	accessTokenClaims := jwt.MapClaims{
+	accessTokenClaims := map[string]interface{}{
 		"id":       userID,
 		"username": username,
 		"type":     "access",
-		"exp":      now.Add(time.Hour * 24).Unix(), // หมดอายุใน 24 ชั่วโมง
+		"sid":      sessionID,                      // session ของอุปกรณ์ (ใช้เพิกถอนรายอุปกรณ์)
+		"jti":      uuid.New(),                     // ทำให้ token ไม่ซ้ำแม้ออกในวินาทีเดียวกัน
+		"exp":      now.Add(accessTokenTTL).Unix(), // หมดอายุใน 24 ชั่วโมง
 		"iat":      now.Unix(),                     // เวลาที่ออกโทเคน
 	}
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessTokenClaims)
 
 	// สร้าง Refresh Token (อายุยาว)
-	refreshTokenClaims := jwt.MapClaims{
+	refreshTokenClaims := map[string]interface{}{
 		"id":       userID,
 		"username": username,
 		"type":     "refresh",
-		"exp":      now.Add(time.Hour * 24 * 30).Unix(), // หมดอายุใน 30 วัน
-		"iat":      now.Unix(),                          // เวลาที่ออกโทเคน
-	}
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshTokenClaims)
-
-	// เซ็น tokens
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = "default-jwt-secret-for-development-only"
+		"sid":      sessionID,
+		"jti":      uuid.New(),
+		"exp":      now.Add(refreshTokenTTL).Unix(), // หมดอายุใน 30 วัน
+		"iat":      now.Unix(),                      // เวลาที่ออกโทเคน
 	}
 
-	accessTokenString, err := accessToken.SignedString([]byte(jwtSecret))
+	// เซ็น tokens ด้วย key ที่ใช้งานอยู่ (kid อยู่ใน header)
+	accessTokenString, err := s.tokenSigner.Sign(accessTokenClaims)
 	if err != nil {
 		return "", "", err
 	}
 
-	refreshTokenString, err := refreshToken.SignedString([]byte(jwtSecret))
+	refreshTokenString, err := s.tokenSigner.Sign(refreshTokenClaims)
 	if err != nil {
 		return "", "", err
 	}
@@ -267,3 +585,12 @@ func (s *authService) BlacklistToken(userID uuid.UUID, token string) error {
 
 	return s.tokenBlacklistRepo.Create(blacklist)
 }
+
+// truncateRunes ตัดข้อความตามจำนวนตัวอักษร (ไม่ตัดกลาง UTF-8)
+func truncateRunes(s string, maxRunes int) string {
+	runes := []rune(s)
+	if len(runes) <= maxRunes {
+		return s
+	}
+	return string(runes[:maxRunes])
+}
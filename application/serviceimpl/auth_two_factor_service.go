@@ -0,0 +1,510 @@
+// application/serviceimpl/auth_two_factor_service.go
+package serviceimpl
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/pkg/utils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	twoFactorChallengeTTL  = 5 * time.Minute
+	twoFactorChallengeType = "2fa_challenge"
+	twoFactorSkewSteps     = 1 // ยอมให้นาฬิกาของอุปกรณ์คลาดเคลื่อน ±30 วินาที
+
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10 // ตัวอักษร base32 (48 บิต) แสดงเป็น xxxxx-xxxxx
+)
+
+var (
+	errInvalidTwoFactorChallenge = errors.New("invalid or expired challenge token")
+	errInvalidTwoFactorCode      = errors.New("invalid two-factor code")
+
+	recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// LoginTwoFactor ขั้นที่สองของการเข้าสู่ระบบ: ตรวจรหัส TOTP หรือรหัสกู้คืนแล้วสร้าง session
+func (s *authService) LoginTwoFactor(challengeToken, code string, device *dto.SessionDeviceInfo) (*models.User, string, string, error) {
+	userID, challengeKey, expiresAt, err := s.parseTwoFactorChallenge(challengeToken)
+	if err != nil {
+		return nil, "", "", errInvalidTwoFactorChallenge
+	}
+
+	guardKey := twoFactorGuardKey(userID)
+	if lockedFor := s.loginLockedFor(guardKey); lockedFor > 0 {
+		return nil, "", "", &service.LoginLockedError{RetryAfter: lockedFor}
+	}
+
+	// challenge ใช้ได้ครั้งเดียว
+	used, err := s.tokenBlacklistRepo.IsTokenBlacklisted(challengeKey)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if used {
+		return nil, "", "", errInvalidTwoFactorChallenge
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user == nil || !user.IsTwoFactorEnabled() {
+		return nil, "", "", errInvalidTwoFactorChallenge
+	}
+	if user.Status == models.UserStatusSuspended {
+		return nil, "", "", errors.New("account is suspended")
+	}
+
+	ok, err := s.verifySecondFactor(user, code)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if !ok {
+		return nil, "", "", s.guardFailure(guardKey, errInvalidTwoFactorCode)
+	}
+
+	if err := s.tokenBlacklistRepo.Create(&models.TokenBlacklist{
+		Token:     challengeKey,
+		UserID:    user.ID,
+		ExpiredAt: expiresAt,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return nil, "", "", err
+	}
+	s.resetGuard(guardKey)
+
+	// อัปเดตเวลาใช้งานล่าสุด
+	now := time.Now()
+	user.LastActiveAt = &now
+	if err := s.userRepo.Update(user); err != nil {
+		log.Printf("Failed to update last_active_at: %v", err)
+	}
+
+	accessToken, refreshToken, err := s.createSession(user, device, true)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return user, accessToken, refreshToken, nil
+}
+
+// GetTwoFactorStatus ดึงสถานะ 2FA ของผู้ใช้
+func (s *authService) GetTwoFactorStatus(userID uuid.UUID) (*dto.TwoFactorStatusDTO, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &dto.TwoFactorStatusDTO{
+		Enabled:   user.IsTwoFactorEnabled(),
+		Pending:   !user.IsTwoFactorEnabled() && user.TwoFactorSecret != "",
+		EnabledAt: user.TwoFactorEnabledAt,
+	}
+	if status.Enabled {
+		remaining, err := s.recoveryCodeRepo.CountUnused(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		status.RecoveryCodesRemaining = remaining
+	}
+
+	return status, nil
+}
+
+// SetupTwoFactor เริ่มเปิดใช้ 2FA: สร้าง secret ใหม่ (ยังไม่มีผลจนกว่าจะยืนยันรหัสแรก)
+func (s *authService) SetupTwoFactor(userID uuid.UUID) (*dto.TwoFactorSetupDTO, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsBot {
+		return nil, errors.New("bot accounts cannot use two-factor authentication")
+	}
+	if user.IsTwoFactorEnabled() {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := s.encryptTwoFactorSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	user.TwoFactorSecret = encrypted
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, errors.New("failed to save two-factor secret: " + err.Error())
+	}
+
+	account := user.Username
+	if user.Email != "" {
+		account = user.Email
+	}
+
+	return &dto.TwoFactorSetupDTO{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(twoFactorIssuer(), account, secret),
+	}, nil
+}
+
+// ConfirmTwoFactor ยืนยันรหัสแรกเพื่อเปิดใช้ 2FA
+// session ปัจจุบันถือว่าผ่าน 2FA แล้ว ส่วน session ของอุปกรณ์อื่นจะถูกออกจากระบบ
+func (s *authService) ConfirmTwoFactor(userID uuid.UUID, currentSessionID *uuid.UUID, code string) ([]string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsTwoFactorEnabled() {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+	if user.TwoFactorSecret == "" {
+		return nil, errors.New("two-factor setup has not been started")
+	}
+
+	guardKey := twoFactorGuardKey(user.ID)
+	if lockedFor := s.loginLockedFor(guardKey); lockedFor > 0 {
+		return nil, &service.LoginLockedError{RetryAfter: lockedFor}
+	}
+
+	ok, err := s.verifyTOTP(user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, s.guardFailure(guardKey, errInvalidTwoFactorCode)
+	}
+	s.resetGuard(guardKey)
+
+	// บันทึกรหัสกู้คืนก่อนเปิดใช้ เพื่อไม่ให้มีบัญชีที่เปิด 2FA โดยไม่มีรหัสกู้คืน
+	codes, err := s.replaceRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user.TwoFactorEnabledAt = &now
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, errors.New("failed to enable two-factor authentication: " + err.Error())
+	}
+
+	// token แบบเก่าที่ไม่มี sid ใช้ต่อได้จนหมดอายุ แต่รีเฟรชไม่ได้เพราะ session ไม่ผ่าน 2FA
+	if currentSessionID != nil {
+		if err := s.refreshTokenRepo.MarkTwoFactorVerified(*currentSessionID); err != nil {
+			return nil, err
+		}
+		if _, err := s.RevokeOtherSessions(user.ID, currentSessionID); err != nil {
+			log.Printf("Failed to revoke other sessions after enabling 2FA: %v", err)
+		}
+	}
+
+	return codes, nil
+}
+
+// DisableTwoFactor ปิด 2FA (ต้องยืนยันรหัสผ่านและรหัส TOTP หรือรหัสกู้คืน)
+func (s *authService) DisableTwoFactor(userID uuid.UUID, password, code string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.IsTwoFactorEnabled() {
+		return errors.New("two-factor authentication is not enabled")
+	}
+
+	if err := s.reauthenticate(user, password, code); err != nil {
+		return err
+	}
+
+	user.TwoFactorSecret = ""
+	user.TwoFactorEnabledAt = nil
+	if err := s.userRepo.Update(user); err != nil {
+		return errors.New("failed to disable two-factor authentication: " + err.Error())
+	}
+	if err := s.recoveryCodeRepo.DeleteByUserID(user.ID); err != nil {
+		return err
+	}
+
+	if user.Email != "" {
+		s.sendAccountMail(&service.MailMessage{
+			To:      user.Email,
+			Subject: "Two-factor authentication was disabled",
+			Body: fmt.Sprintf("Hi %s,\n\n"+
+				"Two-factor authentication was just turned off for your account.\n\n"+
+				"If you did not make this change, reset your password immediately.\n",
+				accountMailName(user)),
+		})
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes สร้างรหัสกู้คืนชุดใหม่ (ชุดเดิมใช้ไม่ได้อีก)
+func (s *authService) RegenerateRecoveryCodes(userID uuid.UUID, password, code string) ([]string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsTwoFactorEnabled() {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+
+	if err := s.reauthenticate(user, password, code); err != nil {
+		return nil, err
+	}
+
+	return s.replaceRecoveryCodes(user.ID)
+}
+
+// reauthenticate ยืนยันตัวตนซ้ำด้วยรหัสผ่านและรหัส 2FA (นับความผิดพลาดร่วมกับขั้นตอน 2FA ของการเข้าสู่ระบบ)
+func (s *authService) reauthenticate(user *models.User, password, code string) error {
+	guardKey := twoFactorGuardKey(user.ID)
+	if lockedFor := s.loginLockedFor(guardKey); lockedFor > 0 {
+		return &service.LoginLockedError{RetryAfter: lockedFor}
+	}
+
+	if password == "" {
+		return errors.New("password is required")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return s.guardFailure(guardKey, errors.New("invalid password"))
+	}
+
+	ok, err := s.verifySecondFactor(user, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return s.guardFailure(guardKey, errInvalidTwoFactorCode)
+	}
+
+	s.resetGuard(guardKey)
+	return nil
+}
+
+// verifySecondFactor ตรวจรหัส TOTP (6 หลัก) หรือรหัสกู้คืน (ใช้แล้วจะใช้ซ้ำไม่ได้ทั้งสองแบบ)
+func (s *authService) verifySecondFactor(user *models.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return false, errors.New("two-factor code is required")
+	}
+
+	if isTOTPCode(code) {
+		return s.verifyTOTP(user, code)
+	}
+
+	return s.recoveryCodeRepo.Consume(user.ID, hashRecoveryCode(code), time.Now())
+}
+
+// verifyTOTP ตรวจรหัส TOTP และบันทึก time step ที่ใช้แล้ว
+func (s *authService) verifyTOTP(user *models.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return false, errors.New("two-factor code is required")
+	}
+
+	secret, err := s.decryptTwoFactorSecret(user.TwoFactorSecret)
+	if err != nil {
+		return false, err
+	}
+
+	step, ok := utils.ValidateTOTP(secret, code, time.Now(), twoFactorSkewSteps)
+	if !ok {
+		return false, nil
+	}
+
+	return s.userRepo.AdvanceTwoFactorStep(user.ID, step)
+}
+
+// replaceRecoveryCodes สร้างรหัสกู้คืนชุดใหม่ คืนค่ารหัสจริง (แสดงครั้งเดียว เก็บเฉพาะ hash)
+func (s *authService) replaceRecoveryCodes(userID uuid.UUID) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]*models.TwoFactorRecoveryCode, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		records = append(records, &models.TwoFactorRecoveryCode{
+			UserID:   userID,
+			CodeHash: hashRecoveryCode(code),
+		})
+	}
+
+	if err := s.recoveryCodeRepo.ReplaceForUser(userID, records); err != nil {
+		return nil, errors.New("failed to save recovery codes: " + err.Error())
+	}
+
+	return codes, nil
+}
+
+// issueTwoFactorChallenge สร้าง challenge token อายุสั้นหลังจากตรวจรหัสผ่านผ่านแล้ว
+// ใช้ type แยกจาก access token จึงใช้เรียก API ที่ต้องยืนยันตัวตนไม่ได้
+func (s *authService) issueTwoFactorChallenge(userID uuid.UUID) (*service.TwoFactorRequiredError, error) {
+	now := time.Now()
+	expiresAt := now.Add(twoFactorChallengeTTL)
+
+	signed, err := s.tokenSigner.Sign(map[string]interface{}{
+		"sub":  userID,
+		"type": twoFactorChallengeType,
+		"jti":  uuid.New(),
+		"exp":  expiresAt.Unix(),
+		"iat":  now.Unix(),
+	})
+	if err != nil {
+		return nil, errors.New("failed to generate challenge token: " + err.Error())
+	}
+
+	return &service.TwoFactorRequiredError{ChallengeToken: signed, ExpiresAt: expiresAt}, nil
+}
+
+// parseTwoFactorChallenge ตรวจ challenge token คืนค่า user ID, คีย์ใน blacklist และเวลาหมดอายุ
+func (s *authService) parseTwoFactorChallenge(tokenString string) (uuid.UUID, string, time.Time, error) {
+	verified, err := s.tokenSigner.Verify(tokenString)
+	if err != nil {
+		return uuid.Nil, "", time.Time{}, errInvalidTwoFactorChallenge
+	}
+
+	claims := jwt.MapClaims(verified)
+	if claims["type"] != twoFactorChallengeType {
+		return uuid.Nil, "", time.Time{}, errInvalidTwoFactorChallenge
+	}
+
+	subject, _ := claims["sub"].(string)
+	userID, err := uuid.Parse(subject)
+	if err != nil {
+		return uuid.Nil, "", time.Time{}, errInvalidTwoFactorChallenge
+	}
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		return uuid.Nil, "", time.Time{}, errInvalidTwoFactorChallenge
+	}
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return uuid.Nil, "", time.Time{}, errInvalidTwoFactorChallenge
+	}
+
+	return userID, "2fa_challenge:" + jti, expiresAt.Time, nil
+}
+
+// twoFactorGuardKey key ของตัวนับรหัส 2FA ที่ผิด (แยกจากการนับรหัสผ่านผิดของ username)
+func twoFactorGuardKey(userID uuid.UUID) string {
+	return "2fa:" + userID.String()
+}
+
+// twoFactorIssuer ชื่อที่แสดงใน authenticator app
+func twoFactorIssuer() string {
+	if issuer := os.Getenv("TWO_FACTOR_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return "GoFiber Chat"
+}
+
+// isTOTPCode ตรวจว่ารหัสเป็นตัวเลข 6 หลัก (ไม่เช่นนั้นถือเป็นรหัสกู้คืน)
+func isTOTPCode(code string) bool {
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) != utils.TOTPDigits {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// generateRecoveryCode สร้างรหัสกู้คืนรูปแบบ xxxxx-xxxxx
+func generateRecoveryCode() (string, error) {
+	buf := make([]byte, recoveryCodeLength*5/8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate recovery code: %w", err)
+	}
+	code := strings.ToLower(recoveryCodeEncoding.EncodeToString(buf))
+	return code[:recoveryCodeLength/2] + "-" + code[recoveryCodeLength/2:], nil
+}
+
+// hashRecoveryCode คืนค่า SHA-256 ของรหัสกู้คืน (ไม่สนตัวพิมพ์ใหญ่เล็ก ขีด และช่องว่าง)
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+	return hashAccountToken(normalized)
+}
+
+// encryptTwoFactorSecret เข้ารหัส secret ด้วย AES-GCM (base64 ของ nonce + ciphertext)
+func (s *authService) encryptTwoFactorSecret(secret string) (string, error) {
+	gcm, err := s.twoFactorCipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptTwoFactorSecret ถอดรหัส secret ที่เก็บในฐานข้อมูล
+func (s *authService) decryptTwoFactorSecret(encrypted string) (string, error) {
+	if encrypted == "" {
+		return "", errors.New("two-factor authentication is not set up")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", errors.New("failed to decrypt two-factor secret")
+	}
+
+	gcm, err := s.twoFactorCipher()
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("failed to decrypt two-factor secret")
+	}
+
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", errors.New("failed to decrypt two-factor secret")
+	}
+	return string(plaintext), nil
+}
+
+// twoFactorCipher AES-GCM จากคีย์ที่โหลดตอนเริ่มระบบ (configs.SetupTwoFactorEncryptionKey)
+func (s *authService) twoFactorCipher() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.twoFactorKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// resetGuard ล้างตัวนับความผิดพลาด (Redis มีปัญหา = บันทึก log)
+func (s *authService) resetGuard(key string) {
+	if s.loginGuard == nil {
+		return
+	}
+	if err := s.loginGuard.Reset(context.Background(), key); err != nil {
+		log.Printf("Failed to reset login failures: %v", err)
+	}
+}
@@ -0,0 +1,426 @@
+// application/serviceimpl/bot_service.go
+package serviceimpl
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+const (
+	maxBotsPerOwner    = 20
+	botTokenPrefix     = "bot_"
+	botTokenBytes      = 32
+	botTokenShownChars = 12 // จำนวนตัวอักษรของ token ที่แสดงใน TokenPrefix
+	botSecretBytes     = 32
+
+	// บันทึก last_used_at ไม่บ่อยกว่านี้ เพื่อไม่ให้ทุก request ของบอทต้องเขียนฐานข้อมูล
+	botTouchInterval = time.Minute
+)
+
+// botUsernamePattern ชื่อผู้ใช้ของบอท: a-z, 0-9, _ และต้องลงท้ายด้วย "bot" (เช่น weather_bot)
+var botUsernamePattern = regexp.MustCompile(`^[a-zA-Z0-9_]{0,47}[bB][oO][tT]$`)
+
+// botService เป็น implementation ของ BotService
+type botService struct {
+	botRepo        repository.BotRepository
+	deliveryRepo   repository.BotWebhookDeliveryRepository
+	userRepo       repository.UserRepository
+	messageRepo    repository.MessageRepository
+	messageService service.MessageService
+	sender         service.WebhookSender
+}
+
+// NewBotService สร้าง instance ใหม่ของ BotService
+func NewBotService(
+	botRepo repository.BotRepository,
+	deliveryRepo repository.BotWebhookDeliveryRepository,
+	userRepo repository.UserRepository,
+	messageRepo repository.MessageRepository,
+	messageService service.MessageService,
+	sender service.WebhookSender,
+) service.BotService {
+	return &botService{
+		botRepo:        botRepo,
+		deliveryRepo:   deliveryRepo,
+		userRepo:       userRepo,
+		messageRepo:    messageRepo,
+		messageService: messageService,
+		sender:         sender,
+	}
+}
+
+// =========== Owner management ===========
+
+// CreateBot สร้างบัญชีบอทใหม่ คืนค่า API token และ webhook secret (แสดงครั้งเดียว)
+func (s *botService) CreateBot(ownerID uuid.UUID, req *dto.CreateBotRequest) (*dto.BotCredentialsDTO, error) {
+	owner, err := s.userRepo.FindByID(ownerID)
+	if err != nil || owner == nil {
+		return nil, errors.New("user not found")
+	}
+	if owner.IsBot {
+		return nil, errors.New("bots cannot create bots")
+	}
+
+	username := strings.TrimSpace(req.Username)
+	if !botUsernamePattern.MatchString(username) {
+		return nil, errors.New("bot username must be 3-50 letters, digits or underscores and end with \"bot\"")
+	}
+	if existing, err := s.userRepo.FindByUsername(username); err == nil && existing != nil {
+		return nil, errors.New("username already exists")
+	}
+
+	webhookURL := strings.TrimSpace(req.WebhookURL)
+	if err := s.validateWebhookURL(webhookURL); err != nil {
+		return nil, err
+	}
+
+	bots, err := s.botRepo.GetByOwnerID(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(bots) >= maxBotsPerOwner {
+		return nil, fmt.Errorf("bot limit reached (max %d bots per user)", maxBotsPerOwner)
+	}
+
+	token, tokenHash, err := generateBotToken()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newBotSecret(botSecretBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	displayName := strings.TrimSpace(req.DisplayName)
+	if displayName == "" {
+		displayName = username
+	}
+
+	now := time.Now()
+	user := &models.User{
+		ID:              uuid.New(),
+		Username:        username,
+		DisplayName:     displayName,
+		ProfileImageURL: strings.TrimSpace(req.ProfileImageURL),
+		Bio:             strings.TrimSpace(req.Description),
+		Status:          models.UserStatusActive,
+		IsBot:           true,
+		CreatedAt:       now,
+		Settings:        types.JSONB{},
+	}
+	bot := &models.Bot{
+		ID:            uuid.New(),
+		OwnerID:       ownerID,
+		Description:   strings.TrimSpace(req.Description),
+		TokenHash:     tokenHash,
+		TokenPrefix:   token[:botTokenShownChars],
+		WebhookURL:    webhookURL,
+		WebhookSecret: secret,
+		ReceiveAll:    req.ReceiveAll,
+		IsActive:      true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := s.botRepo.CreateWithUser(user, bot); err != nil {
+		return nil, fmt.Errorf("failed to create bot: %w", err)
+	}
+	bot.User = user
+
+	return &dto.BotCredentialsDTO{
+		Bot:           toBotDTO(bot),
+		Token:         token,
+		WebhookSecret: secret,
+	}, nil
+}
+
+// ListBots ดึงบอททั้งหมดของผู้ใช้
+func (s *botService) ListBots(ownerID uuid.UUID) ([]*dto.BotDTO, error) {
+	bots, err := s.botRepo.GetByOwnerID(ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.BotDTO, 0, len(bots))
+	for _, bot := range bots {
+		result = append(result, toBotDTO(bot))
+	}
+	return result, nil
+}
+
+// GetBot ดึงข้อมูลบอท (เฉพาะเจ้าของ)
+func (s *botService) GetBot(ownerID, botID uuid.UUID) (*dto.BotDTO, error) {
+	bot, err := s.getOwnedBot(ownerID, botID)
+	if err != nil {
+		return nil, err
+	}
+	return toBotDTO(bot), nil
+}
+
+// UpdateBot แก้ไขโปรไฟล์และการตั้งค่า webhook ของบอท
+func (s *botService) UpdateBot(ownerID, botID uuid.UUID, req *dto.UpdateBotRequest) (*dto.BotDTO, error) {
+	bot, err := s.getOwnedBot(ownerID, botID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.WebhookURL != nil {
+		webhookURL := strings.TrimSpace(*req.WebhookURL)
+		if err := s.validateWebhookURL(webhookURL); err != nil {
+			return nil, err
+		}
+		bot.WebhookURL = webhookURL
+	}
+	if req.Description != nil {
+		bot.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.ReceiveAll != nil {
+		bot.ReceiveAll = *req.ReceiveAll
+	}
+	if req.IsActive != nil {
+		bot.IsActive = *req.IsActive
+	}
+	if err := s.botRepo.Update(bot); err != nil {
+		return nil, err
+	}
+
+	if bot.User != nil && (req.DisplayName != nil || req.ProfileImageURL != nil) {
+		if req.DisplayName != nil {
+			if displayName := strings.TrimSpace(*req.DisplayName); displayName != "" {
+				bot.User.DisplayName = displayName
+			}
+		}
+		if req.ProfileImageURL != nil {
+			bot.User.ProfileImageURL = strings.TrimSpace(*req.ProfileImageURL)
+		}
+		if err := s.botRepo.UpdateUserProfile(bot.UserID, bot.User.DisplayName, bot.User.ProfileImageURL); err != nil {
+			return nil, err
+		}
+	}
+
+	return toBotDTO(bot), nil
+}
+
+// RotateToken ออก API token ใหม่ให้บอท
+func (s *botService) RotateToken(ownerID, botID uuid.UUID) (*dto.BotCredentialsDTO, error) {
+	bot, err := s.getOwnedBot(ownerID, botID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, tokenHash, err := generateBotToken()
+	if err != nil {
+		return nil, err
+	}
+	bot.TokenHash = tokenHash
+	bot.TokenPrefix = token[:botTokenShownChars]
+	if err := s.botRepo.Update(bot); err != nil {
+		return nil, err
+	}
+
+	return &dto.BotCredentialsDTO{
+		Bot:   toBotDTO(bot),
+		Token: token,
+	}, nil
+}
+
+// RotateWebhookSecret ออก webhook secret ใหม่ (webhook ถัดไปจะลงลายมือชื่อด้วย secret ใหม่)
+func (s *botService) RotateWebhookSecret(ownerID, botID uuid.UUID) (*dto.BotCredentialsDTO, error) {
+	bot, err := s.getOwnedBot(ownerID, botID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := newBotSecret(botSecretBytes)
+	if err != nil {
+		return nil, err
+	}
+	bot.WebhookSecret = secret
+	if err := s.botRepo.Update(bot); err != nil {
+		return nil, err
+	}
+
+	return &dto.BotCredentialsDTO{
+		Bot:           toBotDTO(bot),
+		WebhookSecret: secret,
+	}, nil
+}
+
+// DeleteBot ลบบอท (ออกจากทุกการสนทนา ข้อความเดิมยังคงอยู่)
+func (s *botService) DeleteBot(ownerID, botID uuid.UUID) error {
+	bot, err := s.getOwnedBot(ownerID, botID)
+	if err != nil {
+		return err
+	}
+	return s.botRepo.DeleteWithUser(bot)
+}
+
+// GetDeliveries ดึงประวัติการส่ง webhook ของบอท
+func (s *botService) GetDeliveries(ownerID, botID uuid.UUID, status string, limit, offset int) ([]*models.BotWebhookDelivery, int64, error) {
+	switch status {
+	case "", models.BotDeliveryPending, models.BotDeliverySent, models.BotDeliveryFailed, models.BotDeliveryDropped:
+	default:
+		return nil, 0, errors.New("invalid delivery status")
+	}
+
+	if _, err := s.getOwnedBot(ownerID, botID); err != nil {
+		return nil, 0, err
+	}
+	return s.deliveryRepo.GetByBotID(botID, status, limit, offset)
+}
+
+// =========== Bot API ===========
+
+// AuthenticateToken ตรวจสอบ API token ของบอท
+func (s *botService) AuthenticateToken(token string) (*models.Bot, error) {
+	if !strings.HasPrefix(token, botTokenPrefix) {
+		return nil, errors.New("invalid bot token")
+	}
+
+	bot, err := s.botRepo.GetByTokenHash(hashBotToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if bot == nil || bot.User == nil {
+		return nil, errors.New("invalid bot token")
+	}
+	if !bot.IsActive || bot.User.Status != models.UserStatusActive {
+		return nil, errors.New("bot is disabled")
+	}
+
+	now := time.Now()
+	if bot.LastUsedAt == nil || now.Sub(*bot.LastUsedAt) >= botTouchInterval {
+		if err := s.botRepo.TouchLastUsed(bot.ID, now); err != nil {
+			fmt.Printf("Error updating bot last used: %v\n", err)
+		}
+	}
+
+	return bot, nil
+}
+
+// GetMe ดึงข้อมูลของบอทที่เรียก API
+func (s *botService) GetMe(botUserID uuid.UUID) (*dto.BotDTO, error) {
+	bot, err := s.botRepo.GetByUserID(botUserID)
+	if err != nil {
+		return nil, err
+	}
+	if bot == nil {
+		return nil, errors.New("bot not found")
+	}
+	return toBotDTO(bot), nil
+}
+
+// SendMessage ส่งข้อความ text ในนามบอท (ตอบกลับข้อความได้ผ่าน ReplyToID)
+func (s *botService) SendMessage(botUserID, conversationID uuid.UUID, req *dto.BotSendMessageRequest) (*models.Message, error) {
+	metadata := map[string]interface{}{}
+	for key, value := range req.Metadata {
+		metadata[key] = value
+	}
+	if len(req.Mentions) > 0 {
+		metadata["mentions"] = req.Mentions
+	}
+
+	if req.ReplyToID == nil {
+		return s.messageService.SendTextMessage(conversationID, botUserID, req.Content, metadata)
+	}
+
+	if strings.TrimSpace(req.Content) == "" {
+		return nil, errors.New("message content cannot be empty")
+	}
+
+	replyTo, err := s.messageRepo.GetByID(*req.ReplyToID)
+	if err != nil || replyTo == nil {
+		return nil, errors.New("message not found")
+	}
+	if replyTo.ConversationID != conversationID {
+		return nil, errors.New("reply target is not in this conversation")
+	}
+
+	return s.messageService.ReplyToMessage(*req.ReplyToID, botUserID, "text", req.Content, "", "", metadata)
+}
+
+// getOwnedBot ดึงบอทและตรวจสอบว่าผู้ใช้เป็นเจ้าของ
+func (s *botService) getOwnedBot(ownerID, botID uuid.UUID) (*models.Bot, error) {
+	bot, err := s.botRepo.GetByID(botID)
+	if err != nil {
+		return nil, err
+	}
+	if bot == nil || bot.OwnerID != ownerID {
+		return nil, errors.New("bot not found")
+	}
+	return bot, nil
+}
+
+// validateWebhookURL ตรวจสอบ URL ของ webhook ("" = ปิด webhook)
+func (s *botService) validateWebhookURL(webhookURL string) error {
+	if webhookURL == "" {
+		return nil
+	}
+	if s.sender == nil {
+		return errors.New("bot webhooks are not enabled")
+	}
+	if err := s.sender.ValidateURL(webhookURL); err != nil {
+		return fmt.Errorf("invalid webhook url: %w", err)
+	}
+	return nil
+}
+
+// toBotDTO แปลง Bot เป็น BotDTO
+func toBotDTO(bot *models.Bot) *dto.BotDTO {
+	result := &dto.BotDTO{
+		ID:          bot.ID,
+		UserID:      bot.UserID,
+		OwnerID:     bot.OwnerID,
+		Description: bot.Description,
+		TokenPrefix: bot.TokenPrefix,
+		WebhookURL:  bot.WebhookURL,
+		ReceiveAll:  bot.ReceiveAll,
+		IsActive:    bot.IsActive,
+		LastUsedAt:  bot.LastUsedAt,
+		CreatedAt:   bot.CreatedAt,
+	}
+	if bot.User != nil {
+		result.Username = bot.User.Username
+		result.DisplayName = bot.User.DisplayName
+		result.ProfileImageURL = bot.User.ProfileImageURL
+	}
+	return result
+}
+
+// generateBotToken สร้าง API token ใหม่ คืนค่า token และ hash สำหรับเก็บในฐานข้อมูล
+func generateBotToken() (string, string, error) {
+	secret, err := newBotSecret(botTokenBytes)
+	if err != nil {
+		return "", "", err
+	}
+	token := botTokenPrefix + secret
+	return token, hashBotToken(token), nil
+}
+
+// newBotSecret สร้างค่าสุ่มแบบ hex สำหรับ API token และ webhook secret
+func newBotSecret(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate bot secret: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashBotToken คืนค่า SHA-256 (hex) ของ API token สำหรับเก็บและค้นหาในฐานข้อมูล
+func hashBotToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
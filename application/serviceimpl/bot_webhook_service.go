@@ -0,0 +1,299 @@
+// application/serviceimpl/bot_webhook_service.go
+package serviceimpl
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+const (
+	botWebhookMaxAttempts    = 6
+	botWebhookRetryBaseDelay = 15 * time.Second
+	botWebhookSendTimeout    = 15 * time.Second
+	botWebhookClaimLease     = 2 * time.Minute // เวลาที่ instance จองรายการไว้ส่ง (ต้องนานกว่า botWebhookSendTimeout)
+
+	// headers ที่ส่งไปกับ webhook (ผู้รับตรวจสอบ X-Bot-Signature = "sha256=" + HMAC-SHA256(secret, timestamp + "." + body))
+	botHeaderEvent     = "X-Bot-Event"
+	botHeaderDelivery  = "X-Bot-Delivery"
+	botHeaderTimestamp = "X-Bot-Timestamp"
+	botHeaderSignature = "X-Bot-Signature"
+)
+
+// botWebhookService เป็น implementation ของ BotWebhookService
+type botWebhookService struct {
+	botRepo          repository.BotRepository
+	deliveryRepo     repository.BotWebhookDeliveryRepository
+	conversationRepo repository.ConversationRepository
+	userRepo         repository.UserRepository
+	sender           service.WebhookSender
+}
+
+// NewBotWebhookService สร้าง instance ใหม่ของ BotWebhookService
+func NewBotWebhookService(
+	botRepo repository.BotRepository,
+	deliveryRepo repository.BotWebhookDeliveryRepository,
+	conversationRepo repository.ConversationRepository,
+	userRepo repository.UserRepository,
+	sender service.WebhookSender,
+) service.BotWebhookService {
+	return &botWebhookService{
+		botRepo:          botRepo,
+		deliveryRepo:     deliveryRepo,
+		conversationRepo: conversationRepo,
+		userRepo:         userRepo,
+		sender:           sender,
+	}
+}
+
+// NotifyNewMessage ส่ง event ของข้อความใหม่ไปยังบอทในการสนทนา
+// แต่ละบอทได้รับไม่เกินหนึ่ง event ต่อข้อความ (command > mention > message)
+func (s *botWebhookService) NotifyNewMessage(message *models.Message) {
+	if s.sender == nil || message == nil || message.SenderID == nil {
+		return
+	}
+	if message.MessageType == "system" || message.MessageType == models.MessageTypeEncrypted {
+		return
+	}
+
+	bots, err := s.botRepo.GetWebhookBotsByConversationID(message.ConversationID)
+	if err != nil {
+		fmt.Printf("Error getting bots for webhook: %v\n", err)
+		return
+	}
+	if len(bots) == 0 {
+		return
+	}
+
+	conversation, err := s.conversationRepo.GetByID(message.ConversationID)
+	if err != nil || conversation == nil {
+		return
+	}
+
+	var sender *dto.UserInfoDTO
+	if user, err := s.userRepo.FindByID(*message.SenderID); err == nil && user != nil {
+		sender = &dto.UserInfoDTO{
+			ID:              user.ID.String(),
+			Username:        user.Username,
+			DisplayName:     user.DisplayName,
+			ProfileImageURL: user.ProfileImageURL,
+		}
+	}
+
+	mentioned := mentionedUserIDs(message)
+	command, target := parseBotCommand(message)
+	leaseUntil := time.Now().Add(botWebhookClaimLease)
+
+	for _, bot := range bots {
+		// ไม่ส่งข้อความของบอทกลับไปหาตัวเอง
+		if bot.UserID == *message.SenderID {
+			continue
+		}
+
+		payload := &dto.BotWebhookPayload{
+			BotID: bot.ID,
+			Conversation: dto.BotWebhookConversation{
+				ID:    conversation.ID,
+				Type:  conversation.Type,
+				Title: conversation.Title,
+			},
+			Message:   botWebhookMessage(message),
+			Sender:    sender,
+			Timestamp: time.Now(),
+		}
+
+		switch {
+		case command != nil && (target == "" || bot.User == nil || strings.EqualFold(target, bot.User.Username)):
+			payload.Event = models.BotEventCommand
+			payload.Command = command
+		case mentioned[bot.UserID]:
+			payload.Event = models.BotEventMention
+		case conversation.Type == "direct" || bot.ReceiveAll:
+			payload.Event = models.BotEventMessage
+		default:
+			// ในกลุ่ม/channel บอทรับเฉพาะ mention และ command ยกเว้นเปิด ReceiveAll
+			continue
+		}
+
+		data, err := botPayloadToJSONB(payload)
+		if err != nil {
+			fmt.Printf("Error encoding bot webhook payload: %v\n", err)
+			continue
+		}
+
+		delivery := &models.BotWebhookDelivery{
+			BotID:          bot.ID,
+			Event:          payload.Event,
+			ConversationID: &message.ConversationID,
+			MessageID:      &message.ID,
+			Payload:        data,
+			Status:         models.BotDeliveryPending,
+			NextRetryAt:    &leaseUntil, // ถ้า instance ล่มก่อนบันทึกผล scheduler จะส่งซ้ำเมื่อ lease หมด
+		}
+		if err := s.deliveryRepo.Create(delivery); err != nil {
+			fmt.Printf("Error saving bot webhook delivery: %v\n", err)
+			continue
+		}
+
+		s.attempt(delivery, bot)
+	}
+}
+
+// RetryDueDeliveries ส่ง webhook ที่ล้มเหลวและถึงเวลา retry แล้ว คืนค่าจำนวนที่ประมวลผล
+func (s *botWebhookService) RetryDueDeliveries(limit int) (int, error) {
+	deliveries, err := s.deliveryRepo.ClaimDueRetries(time.Now(), botWebhookClaimLease, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		bot := delivery.Bot
+		if bot == nil || !bot.HasWebhook() {
+			s.finish(delivery, models.BotDeliveryDropped, "bot webhook is no longer configured")
+			continue
+		}
+		s.attempt(delivery, bot)
+	}
+
+	return len(deliveries), nil
+}
+
+// attempt ส่ง webhook หนึ่งครั้งและบันทึกผล (retry แบบ exponential backoff)
+func (s *botWebhookService) attempt(delivery *models.BotWebhookDelivery, bot *models.Bot) {
+	body, err := json.Marshal(delivery.Payload)
+	if err != nil {
+		s.finish(delivery, models.BotDeliveryFailed, "invalid payload: "+err.Error())
+		return
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request := &service.WebhookRequest{
+		URL: bot.WebhookURL,
+		Headers: map[string]string{
+			botHeaderEvent:     delivery.Event,
+			botHeaderDelivery:  delivery.ID.String(),
+			botHeaderTimestamp: timestamp,
+			botHeaderSignature: "sha256=" + signBotWebhook(bot.WebhookSecret, timestamp, body),
+		},
+		Body: body,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), botWebhookSendTimeout)
+	statusCode, err := s.sender.Send(ctx, request)
+	cancel()
+
+	delivery.Attempts++
+	delivery.ResponseStatus = statusCode
+	now := time.Now()
+
+	switch {
+	case err == nil:
+		delivery.DeliveredAt = &now
+		s.finish(delivery, models.BotDeliverySent, "")
+
+	case !isRetryableWebhookStatus(statusCode) || delivery.Attempts >= botWebhookMaxAttempts:
+		s.finish(delivery, models.BotDeliveryFailed, err.Error())
+
+	default:
+		nextRetryAt := now.Add(botWebhookRetryBaseDelay << (delivery.Attempts - 1))
+		delivery.Status = models.BotDeliveryPending
+		delivery.LastError = err.Error()
+		delivery.NextRetryAt = &nextRetryAt
+		if err := s.deliveryRepo.Update(delivery); err != nil {
+			fmt.Printf("Error updating bot webhook delivery: %v\n", err)
+		}
+	}
+}
+
+// finish บันทึกสถานะสุดท้ายของการส่ง (ไม่ retry อีก)
+func (s *botWebhookService) finish(delivery *models.BotWebhookDelivery, status, lastError string) {
+	delivery.Status = status
+	delivery.LastError = lastError
+	delivery.NextRetryAt = nil
+	if err := s.deliveryRepo.Update(delivery); err != nil {
+		fmt.Printf("Error updating bot webhook delivery: %v\n", err)
+	}
+}
+
+// isRetryableWebhookStatus ตรวจสอบว่าควร retry หรือไม่ (เชื่อมต่อไม่ได้, 408, 429 และ 5xx)
+func isRetryableWebhookStatus(statusCode int) bool {
+	return statusCode == 0 || statusCode == 408 || statusCode == 429 || statusCode >= 500
+}
+
+// signBotWebhook คำนวณ HMAC-SHA256 ของ "timestamp.body" ด้วย webhook secret ของบอท
+func signBotWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// parseBotCommand แยก slash-command จากข้อความ text เช่น "/weather@forecastbot bangkok"
+// คืนค่า command และชื่อบอทเป้าหมาย ("" = ทุกบอทในการสนทนา)
+func parseBotCommand(message *models.Message) (*dto.BotWebhookCommand, string) {
+	if message.MessageType != "text" || !strings.HasPrefix(message.Content, "/") {
+		return nil, ""
+	}
+
+	fields := strings.Fields(message.Content)
+	name := strings.TrimPrefix(fields[0], "/")
+
+	target := ""
+	if at := strings.Index(name, "@"); at >= 0 {
+		target = name[at+1:]
+		name = name[:at]
+	}
+	if name == "" {
+		return nil, ""
+	}
+
+	text := strings.TrimSpace(strings.TrimPrefix(message.Content, fields[0]))
+	return &dto.BotWebhookCommand{
+		Name: strings.ToLower(name),
+		Args: fields[1:],
+		Text: text,
+	}, target
+}
+
+// botWebhookMessage แปลงข้อความเป็นข้อมูลใน webhook payload
+func botWebhookMessage(message *models.Message) dto.BotWebhookMessage {
+	return dto.BotWebhookMessage{
+		ID:           message.ID,
+		SenderID:     message.SenderID,
+		SenderType:   message.SenderType,
+		MessageType:  message.MessageType,
+		Content:      message.Content,
+		MediaURL:     message.MediaURL,
+		Mentions:     message.Mentions,
+		ReplyToID:    message.ReplyToID,
+		ThreadRootID: message.ThreadRootID,
+		CreatedAt:    message.CreatedAt,
+	}
+}
+
+// botPayloadToJSONB แปลง payload เป็น JSONB สำหรับเก็บใน delivery log (ใช้ส่งซ้ำตอน retry)
+func botPayloadToJSONB(payload *dto.BotWebhookPayload) (types.JSONB, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var result types.JSONB
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
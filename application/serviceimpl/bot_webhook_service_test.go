@@ -0,0 +1,285 @@
+// application/serviceimpl/bot_webhook_service_test.go
+package serviceimpl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"github.com/thizplus/gofiber-chat-api/infrastructure/webhook"
+)
+
+// stubBotRepo คืนบอทที่กำหนดไว้สำหรับทุกการสนทนา
+type stubBotRepo struct {
+	repository.BotRepository
+	bots []*models.Bot
+}
+
+func (r *stubBotRepo) GetWebhookBotsByConversationID(uuid.UUID) ([]*models.Bot, error) {
+	return r.bots, nil
+}
+
+type stubConversationRepo struct {
+	repository.ConversationRepository
+	conversation *models.Conversation
+	members      []*models.ConversationMember
+}
+
+func (r *stubConversationRepo) GetByID(uuid.UUID) (*models.Conversation, error) {
+	return r.conversation, nil
+}
+
+func (r *stubConversationRepo) GetMembers(uuid.UUID) ([]*models.ConversationMember, error) {
+	return r.members, nil
+}
+
+type stubUserRepo struct {
+	repository.UserRepository
+}
+
+func (r *stubUserRepo) FindByID(id uuid.UUID) (*models.User, error) {
+	return &models.User{ID: id, Username: "sender"}, nil
+}
+
+// memoryBotDeliveryRepo เก็บ delivery log ในหน่วยความจำ (claim ด้วย lease แบบเดียวกับ postgres)
+type memoryBotDeliveryRepo struct {
+	mu         sync.Mutex
+	bots       map[uuid.UUID]*models.Bot
+	deliveries map[uuid.UUID]models.BotWebhookDelivery
+}
+
+func newMemoryBotDeliveryRepo(bots ...*models.Bot) *memoryBotDeliveryRepo {
+	repo := &memoryBotDeliveryRepo{
+		bots:       make(map[uuid.UUID]*models.Bot),
+		deliveries: make(map[uuid.UUID]models.BotWebhookDelivery),
+	}
+	for _, bot := range bots {
+		repo.bots[bot.ID] = bot
+	}
+	return repo
+}
+
+func (r *memoryBotDeliveryRepo) Create(delivery *models.BotWebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+	r.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func (r *memoryBotDeliveryRepo) Update(delivery *models.BotWebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func (r *memoryBotDeliveryRepo) ClaimDueRetries(now time.Time, lease time.Duration, limit int) ([]*models.BotWebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var claimed []*models.BotWebhookDelivery
+	for id, delivery := range r.deliveries {
+		if len(claimed) >= limit {
+			break
+		}
+		if delivery.Status != models.BotDeliveryPending || delivery.NextRetryAt == nil || delivery.NextRetryAt.After(now) {
+			continue
+		}
+		leaseUntil := now.Add(lease)
+		delivery.NextRetryAt = &leaseUntil
+		r.deliveries[id] = delivery
+
+		copied := delivery
+		copied.Bot = r.bots[delivery.BotID]
+		claimed = append(claimed, &copied)
+	}
+	return claimed, nil
+}
+
+func (r *memoryBotDeliveryRepo) GetByBotID(uuid.UUID, string, int, int) ([]*models.BotWebhookDelivery, int64, error) {
+	return nil, 0, nil
+}
+
+// only คืน delivery เดียวที่บันทึกไว้
+func (r *memoryBotDeliveryRepo) only(t *testing.T) models.BotWebhookDelivery {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.deliveries) != 1 {
+		t.Fatalf("expected 1 delivery, got %d", len(r.deliveries))
+	}
+	for _, delivery := range r.deliveries {
+		return delivery
+	}
+	return models.BotWebhookDelivery{}
+}
+
+// makeDue ทำให้ delivery ที่รอ retry ถึงเวลาส่งทันที
+func (r *memoryBotDeliveryRepo) makeDue() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	past := time.Now().Add(-time.Second)
+	for id, delivery := range r.deliveries {
+		if delivery.NextRetryAt != nil {
+			delivery.NextRetryAt = &past
+			r.deliveries[id] = delivery
+		}
+	}
+}
+
+// newBotWebhookTestService สร้าง service ที่ส่ง webhook ไปยัง stand-in บนเครื่อง
+func newBotWebhookTestService(t *testing.T, handler http.HandlerFunc) (*botWebhookService, *memoryBotDeliveryRepo, *models.Message) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	bot := &models.Bot{
+		ID:            uuid.New(),
+		UserID:        uuid.New(),
+		WebhookURL:    server.URL,
+		WebhookSecret: "test-secret",
+		IsActive:      true,
+	}
+	conversation := &models.Conversation{ID: uuid.New(), Type: "direct"}
+	senderID := uuid.New()
+
+	deliveryRepo := newMemoryBotDeliveryRepo(bot)
+	svc := &botWebhookService{
+		botRepo:          &stubBotRepo{bots: []*models.Bot{bot}},
+		deliveryRepo:     deliveryRepo,
+		conversationRepo: &stubConversationRepo{conversation: conversation},
+		userRepo:         &stubUserRepo{},
+		sender: webhook.NewHTTPSender(&webhook.HTTPSenderConfig{
+			Timeout:       5 * time.Second,
+			AllowInsecure: true,
+			AllowPrivate:  true,
+		}),
+	}
+
+	message := &models.Message{
+		ID:             uuid.New(),
+		ConversationID: conversation.ID,
+		SenderID:       &senderID,
+		MessageType:    "text",
+		Content:        "hello bot",
+	}
+	return svc, deliveryRepo, message
+}
+
+func TestBotWebhookDeliveryIsSigned(t *testing.T) {
+	var verified atomic.Bool
+	svc, deliveryRepo, message := newBotWebhookTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mac := hmac.New(sha256.New, []byte("test-secret"))
+		mac.Write([]byte(r.Header.Get(botHeaderTimestamp) + "."))
+		mac.Write(body)
+		expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+		if hmac.Equal([]byte(expected), []byte(r.Header.Get(botHeaderSignature))) &&
+			r.Header.Get(botHeaderEvent) == models.BotEventMessage &&
+			r.Header.Get(botHeaderDelivery) != "" {
+			verified.Store(true)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	svc.NotifyNewMessage(message)
+
+	if !verified.Load() {
+		t.Fatal("webhook request was not signed with the bot secret")
+	}
+	delivery := deliveryRepo.only(t)
+	if delivery.Status != models.BotDeliverySent || delivery.Attempts != 1 || delivery.NextRetryAt != nil {
+		t.Fatalf("unexpected delivery state: status=%s attempts=%d next_retry_at=%v", delivery.Status, delivery.Attempts, delivery.NextRetryAt)
+	}
+}
+
+func TestBotWebhookRetriesWithExponentialBackoff(t *testing.T) {
+	var calls atomic.Int32
+	svc, deliveryRepo, message := newBotWebhookTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	svc.NotifyNewMessage(message)
+
+	// ครั้งแรกล้มเหลว: retry หลัง base delay
+	start := time.Now()
+	delivery := deliveryRepo.only(t)
+	if delivery.Status != models.BotDeliveryPending || delivery.Attempts != 1 || delivery.ResponseStatus != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected state after first attempt: status=%s attempts=%d response=%d", delivery.Status, delivery.Attempts, delivery.ResponseStatus)
+	}
+	assertRetryDelay(t, delivery.NextRetryAt, start, botWebhookRetryBaseDelay)
+
+	// ยังไม่ถึงเวลา retry: scheduler ต้องไม่ส่งซ้ำ
+	if count, err := svc.RetryDueDeliveries(10); err != nil || count != 0 {
+		t.Fatalf("expected no due retries, got %d (err: %v)", count, err)
+	}
+
+	// ครั้งที่สองล้มเหลว: delay เพิ่มเป็นสองเท่า
+	deliveryRepo.makeDue()
+	start = time.Now()
+	if count, err := svc.RetryDueDeliveries(10); err != nil || count != 1 {
+		t.Fatalf("expected 1 retry, got %d (err: %v)", count, err)
+	}
+	delivery = deliveryRepo.only(t)
+	if delivery.Status != models.BotDeliveryPending || delivery.Attempts != 2 {
+		t.Fatalf("unexpected state after second attempt: status=%s attempts=%d", delivery.Status, delivery.Attempts)
+	}
+	assertRetryDelay(t, delivery.NextRetryAt, start, 2*botWebhookRetryBaseDelay)
+
+	// ครั้งที่สามสำเร็จ
+	deliveryRepo.makeDue()
+	if count, err := svc.RetryDueDeliveries(10); err != nil || count != 1 {
+		t.Fatalf("expected 1 retry, got %d (err: %v)", count, err)
+	}
+	delivery = deliveryRepo.only(t)
+	if delivery.Status != models.BotDeliverySent || delivery.Attempts != 3 || delivery.NextRetryAt != nil || delivery.DeliveredAt == nil {
+		t.Fatalf("unexpected state after final attempt: status=%s attempts=%d", delivery.Status, delivery.Attempts)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 webhook calls, got %d", calls.Load())
+	}
+}
+
+func TestBotWebhookClientErrorIsNotRetried(t *testing.T) {
+	svc, deliveryRepo, message := newBotWebhookTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	svc.NotifyNewMessage(message)
+
+	delivery := deliveryRepo.only(t)
+	if delivery.Status != models.BotDeliveryFailed || delivery.Attempts != 1 || delivery.NextRetryAt != nil {
+		t.Fatalf("unexpected delivery state: status=%s attempts=%d next_retry_at=%v", delivery.Status, delivery.Attempts, delivery.NextRetryAt)
+	}
+}
+
+func assertRetryDelay(t *testing.T, nextRetryAt *time.Time, start time.Time, delay time.Duration) {
+	t.Helper()
+	if nextRetryAt == nil {
+		t.Fatal("expected next_retry_at to be set")
+	}
+	got := nextRetryAt.Sub(start)
+	if got < delay-time.Second || got > delay+time.Second {
+		t.Fatalf("expected retry after ~%s, got %s", delay, got)
+	}
+}
@@ -0,0 +1,253 @@
+// application/serviceimpl/content_filter_service.go
+package serviceimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+// contentFiltersMetadataKey key ใน Conversation.Metadata ที่เก็บการตั้งค่า filter ของกลุ่ม
+const contentFiltersMetadataKey = "content_filters"
+
+type contentFilterService struct {
+	filters          []service.ContentFilter
+	conversationRepo repository.ConversationRepository
+	reportRepo       repository.ModerationReportRepository
+	memberService    service.ConversationMemberService
+}
+
+// NewContentFilterService สร้าง service ใหม่ (filters ทำงานตามลำดับใน slice)
+func NewContentFilterService(
+	filters []service.ContentFilter,
+	conversationRepo repository.ConversationRepository,
+	reportRepo repository.ModerationReportRepository,
+	memberService service.ConversationMemberService,
+) service.ContentFilterService {
+	return &contentFilterService{
+		filters:          filters,
+		conversationRepo: conversationRepo,
+		reportRepo:       reportRepo,
+		memberService:    memberService,
+	}
+}
+
+// Apply รัน filter chain
+// mask = filter ถัดไปเห็นเนื้อหาที่ถูกแทนที่แล้ว, reject = หยุดทันที, flag = บันทึกไว้ส่งเข้าคิวหลังบันทึกข้อความ
+func (s *contentFilterService) Apply(input *service.ContentFilterInput) (*service.ContentFilterResult, error) {
+	result := &service.ContentFilterResult{Content: input.Content}
+	if len(s.filters) == 0 || strings.TrimSpace(input.Content) == "" {
+		return result, nil
+	}
+
+	conversation, err := s.conversationRepo.GetByID(input.ConversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+	groupSettings := conversationFilterSettings(conversation)
+
+	current := *input
+	for _, filter := range s.filters {
+		settings := groupSettings[filter.Name()]
+		if enabled, ok := settings["enabled"].(bool); ok && !enabled {
+			continue
+		}
+
+		verdict, err := filter.Check(context.Background(), &current, settings)
+		if err != nil {
+			// filter ที่ทำงานผิดพลาดไม่บล็อกการส่งข้อความ
+			fmt.Printf("Error running content filter: %v, filter: %s, conversationID: %s\n", err, filter.Name(), input.ConversationID)
+			continue
+		}
+
+		switch verdict.Action {
+		case service.ContentFilterReject:
+			return nil, fmt.Errorf("message blocked by content filter: %s", verdict.Reason)
+		case service.ContentFilterMask:
+			current.Content = verdict.Content
+		case service.ContentFilterFlag:
+			result.Flags = append(result.Flags, service.ContentFilterMatch{
+				Filter: filter.Name(),
+				Reason: verdict.Reason,
+			})
+		}
+	}
+
+	result.Content = current.Content
+	return result, nil
+}
+
+// ReportFlags สร้างรายงานอัตโนมัติในคิวของผู้ดูแล (ไม่สร้างซ้ำถ้ามีรายงานอัตโนมัติของข้อความนี้ที่ยังไม่ปิด)
+func (s *contentFilterService) ReportFlags(message *models.Message, flags []service.ContentFilterMatch) {
+	if message == nil || len(flags) == 0 {
+		return
+	}
+
+	exists, err := s.reportRepo.HasOpenReport(nil, models.ReportTargetMessage, message.ID)
+	if err != nil {
+		fmt.Printf("Error checking content filter report: %v, messageID: %s\n", err, message.ID)
+		return
+	}
+	if exists {
+		return
+	}
+
+	reasons := make([]string, 0, len(flags))
+	matches := make([]interface{}, 0, len(flags))
+	for _, flag := range flags {
+		reasons = append(reasons, flag.Filter+": "+flag.Reason)
+		matches = append(matches, map[string]interface{}{
+			"filter": flag.Filter,
+			"reason": flag.Reason,
+		})
+	}
+
+	snapshot := types.JSONB{
+		"message_type":    message.MessageType,
+		"content":         message.Content,
+		"sender_type":     message.SenderType,
+		"created_at":      message.CreatedAt.Format(time.RFC3339),
+		"content_filters": matches,
+	}
+	if message.SenderID != nil {
+		snapshot["sender_id"] = message.SenderID.String()
+	}
+
+	if err := s.reportRepo.Create(&models.ModerationReport{
+		Source:         models.ReportSourceContentFilter,
+		TargetType:     models.ReportTargetMessage,
+		TargetID:       message.ID,
+		ReportedUserID: message.SenderID,
+		ConversationID: &message.ConversationID,
+		Reason:         models.ReportReasonContentFilter,
+		Description:    strings.Join(reasons, "\n"),
+		Snapshot:       snapshot,
+	}); err != nil {
+		fmt.Printf("Error creating content filter report: %v, messageID: %s\n", err, message.ID)
+	}
+}
+
+// GetSettings ดึงการตั้งค่า filter ของกลุ่ม
+func (s *contentFilterService) GetSettings(conversationID, userID uuid.UUID) (*dto.ContentFilterSettingsDTO, error) {
+	conversation, err := s.getManagedConversation(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.settingsDTO(conversation.ID, conversationFilterSettings(conversation)), nil
+}
+
+// UpdateSettings บันทึกการตั้งค่า filter ของกลุ่ม (เฉพาะ filter ที่ส่งมา, ค่า null = ลบการตั้งค่า)
+func (s *contentFilterService) UpdateSettings(conversationID, userID uuid.UUID, req *dto.UpdateContentFilterSettingsRequest) (*dto.ContentFilterSettingsDTO, error) {
+	conversation, err := s.getManagedConversation(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := make(map[string]service.ContentFilter, len(s.filters))
+	for _, filter := range s.filters {
+		filters[filter.Name()] = filter
+	}
+
+	settings := conversationFilterSettings(conversation)
+	for name, value := range req.Filters {
+		filter, ok := filters[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown content filter: %s", name)
+		}
+		if value == nil {
+			delete(settings, name)
+			continue
+		}
+
+		normalized, err := filter.NormalizeSettings(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s settings: %v", name, err)
+		}
+		settings[name] = normalized
+	}
+
+	stored := types.JSONB{}
+	for name, value := range settings {
+		stored[name] = map[string]interface{}(value)
+	}
+
+	metadata := types.JSONB{}
+	for key, value := range conversation.Metadata {
+		metadata[key] = value
+	}
+	metadata[contentFiltersMetadataKey] = stored
+
+	if err := s.conversationRepo.UpdateConversation(conversationID, types.JSONB{
+		"metadata":   metadata,
+		"updated_at": time.Now(),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update content filters: %w", err)
+	}
+
+	return s.settingsDTO(conversationID, settings), nil
+}
+
+// getManagedConversation ตรวจสอบว่าเป็นกลุ่มหรือช่องที่ผู้ใช้จัดการ filter ได้
+func (s *contentFilterService) getManagedConversation(conversationID, userID uuid.UUID) (*models.Conversation, error) {
+	conversation, err := s.conversationRepo.GetByID(conversationID)
+	if err != nil || conversation == nil || !conversation.IsActive {
+		return nil, errors.New("conversation not found")
+	}
+	if conversation.Type != "group" && !conversation.IsChannel() {
+		return nil, errors.New("content filters can only be configured for groups and channels")
+	}
+
+	allowed, err := s.memberService.HasPermission(conversationID, userID, service.PermissionManageContentFilters)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, errors.New("you don't have permission to manage content filters")
+	}
+
+	return conversation, nil
+}
+
+// settingsDTO แปลงการตั้งค่าเป็น DTO
+func (s *contentFilterService) settingsDTO(conversationID uuid.UUID, settings map[string]types.JSONB) *dto.ContentFilterSettingsDTO {
+	available := make([]string, 0, len(s.filters))
+	for _, filter := range s.filters {
+		available = append(available, filter.Name())
+	}
+
+	return &dto.ContentFilterSettingsDTO{
+		ConversationID: conversationID,
+		Available:      available,
+		Filters:        settings,
+	}
+}
+
+// conversationFilterSettings อ่านการตั้งค่า filter จาก metadata ของการสนทนา (key = ชื่อ filter)
+func conversationFilterSettings(conversation *models.Conversation) map[string]types.JSONB {
+	settings := make(map[string]types.JSONB)
+	if conversation == nil || conversation.Metadata == nil {
+		return settings
+	}
+
+	stored, ok := conversation.Metadata[contentFiltersMetadataKey].(map[string]interface{})
+	if !ok {
+		return settings
+	}
+	for name, value := range stored {
+		if filterSettings, ok := value.(map[string]interface{}); ok {
+			settings[name] = types.JSONB(filterSettings)
+		}
+	}
+
+	return settings
+}
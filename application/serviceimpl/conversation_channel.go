@@ -0,0 +1,49 @@
+// application/serviceimpl/conversation_channel.go
+package serviceimpl
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+)
+
+// checkPostPermission ตรวจสอบสิทธิ์โพสต์ข้อความ (ช่อง channel โพสต์ได้เฉพาะ owner/admin, การสนทนาที่ถูกปิดโพสต์ไม่ได้)
+// ต้องเรียกหลังตรวจสอบการเป็นสมาชิกแล้ว คืนค่าการสนทนาที่โหลดมา (nil ถ้าไม่พบ) ให้ผู้เรียกใช้ต่อ เช่น กำหนด expires_at
+func checkPostPermission(conversationRepo repository.ConversationRepository, conversationID, userID uuid.UUID) (*models.Conversation, error) {
+	conversation, err := conversationRepo.GetByID(conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching conversation: %w", err)
+	}
+	if conversation != nil && conversation.DisabledAt != nil {
+		return nil, errors.New("conversation has been disabled")
+	}
+	if conversation == nil || !conversation.IsChannel() {
+		return conversation, nil
+	}
+
+	member, err := conversationRepo.GetMember(conversationID, userID)
+	if err != nil || member == nil {
+		return nil, errors.New("user is not a member of this conversation")
+	}
+	if !isConversationManager(member) {
+		return nil, errors.New("only channel admins can post in this channel")
+	}
+
+	return conversation, nil
+}
+
+// isConversationManager ตรวจสอบว่าเป็น owner หรือ admin
+func isConversationManager(member *models.ConversationMember) bool {
+	return member.Role == models.RoleOwner || member.Role == models.RoleAdmin
+}
+
+// newMemberRole role เริ่มต้นของสมาชิกใหม่ตามประเภทการสนทนา
+func newMemberRole(conversation *models.Conversation) models.MemberRole {
+	if conversation != nil && conversation.IsChannel() {
+		return models.RoleSubscriber
+	}
+	return models.RoleMember
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/thizplus/gofiber-chat-api/domain/models"
 	"github.com/thizplus/gofiber-chat-api/domain/repository"
 	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
 )
 
 type conversationMemberService struct {
@@ -61,50 +62,88 @@ func (s *conversationMemberService) AddMember(userID, conversationID, newMemberI
 		return nil, errors.New("user to add not found")
 	}
 
-	// 4. ตรวจสอบว่าผู้ใช้เป็นสมาชิกอยู่แล้วหรือไม่
-	isMember, err := s.conversationRepo.IsMember(conversationID, newMemberID)
+	// 4. เพิ่มสมาชิกพร้อมข้อความระบบ
+	adderName, _ := s.getUserName(userID)
+	memberDTO, _, err := s.addMember(conversation, user, adderName+" added "+user.Username+" to the group")
+	return memberDTO, err
+}
+
+// JoinMember เพิ่มผู้ใช้ที่เข้าร่วมเอง (ผู้เรียกตรวจสิทธิ์การเข้าร่วมแล้ว เช่น ลิงก์เชิญที่ยังใช้ได้)
+func (s *conversationMemberService) JoinMember(conversationID, newMemberID uuid.UUID, systemText string) (*dto.MemberDTO, *models.Message, error) {
+	conversation, err := s.conversationRepo.GetByID(conversationID)
+	if err != nil || conversation == nil {
+		return nil, nil, errors.New("conversation not found")
+	}
+	if conversation.Type == "direct" {
+		return nil, nil, errors.New("cannot add members to direct conversation")
+	}
+
+	user, err := s.userRepo.FindByID(newMemberID)
+	if err != nil || user == nil {
+		return nil, nil, errors.New("user to add not found")
+	}
+
+	return s.addMember(conversation, user, systemText)
+}
+
+// addMember เพิ่มผู้ใช้เป็นสมาชิก สร้างข้อความระบบ (ถ้า systemText ไม่ว่าง) และอัปเดตข้อความล่าสุดของการสนทนา
+func (s *conversationMemberService) addMember(conversation *models.Conversation, user *models.User, systemText string) (*dto.MemberDTO, *models.Message, error) {
+	// ตรวจสอบว่าผู้ใช้เป็นสมาชิกอยู่แล้วหรือไม่
+	isMember, err := s.conversationRepo.IsMember(conversation.ID, user.ID)
 	if err != nil {
-		return nil, errors.New("error checking existing membership: " + err.Error())
+		return nil, nil, errors.New("error checking existing membership: " + err.Error())
 	}
 	if isMember {
-		return nil, errors.New("user is already a member of this conversation")
+		return nil, nil, errors.New("user is already a member of this conversation")
 	}
 
-	// 5. เพิ่มสมาชิกใหม่
+	// เพิ่มสมาชิกใหม่
 	now := time.Now()
 	newMember := &models.ConversationMember{
 		ID:             uuid.New(),
-		ConversationID: conversationID,
-		UserID:         newMemberID,
+		ConversationID: conversation.ID,
+		UserID:         user.ID,
+		Role:           newMemberRole(conversation),
 		IsAdmin:        false,
 		JoinedAt:       now,
 	}
 
 	if err := s.conversationRepo.AddMember(newMember); err != nil {
-		return nil, errors.New("error adding member: " + err.Error())
+		return nil, nil, errors.New("error adding member: " + err.Error())
 	}
 
-	// 6. สร้างข้อความระบบ
-	adderName, _ := s.getUserName(userID)
-	newMemberName, _ := s.getUserName(newMemberID)
-	systemMessage := adderName + " added " + newMemberName + " to the group"
-
-	msgID, _ := s.createSystemMessage(conversationID, systemMessage)
-	s.conversationRepo.UpdateLastMessage(conversationID, msgID, systemMessage, now)
+	// สร้างข้อความระบบ
+	var systemMessage *models.Message
+	if systemText != "" {
+		systemMessage = &models.Message{
+			ID:             uuid.New(),
+			ConversationID: conversation.ID,
+			MessageType:    "system",
+			Content:        systemText,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		if err := s.messageRepo.Create(systemMessage); err != nil {
+			fmt.Printf("Error creating member system message: %v, conversationID: %s\n", err, conversation.ID)
+			systemMessage = nil
+		} else {
+			s.conversationRepo.UpdateLastMessage(conversation.ID, systemMessage.ID, systemText, now)
+		}
+	}
 
-	// 7. สร้าง DTO เพื่อส่งกลับ
+	// สร้าง DTO เพื่อส่งกลับ
 	memberDTO := &dto.MemberDTO{
 		ID:             newMember.ID.String(),
 		UserID:         newMember.UserID.String(),
 		Username:       user.Username,
 		DisplayName:    user.DisplayName,
 		ProfilePicture: user.ProfileImageURL,
-		Role:           "member",
+		Role:           string(newMember.Role),
 		JoinedAt:       newMember.JoinedAt,
 		IsOnline:       false, // ต้องมี logic การตรวจสอบว่า online หรือไม่
 	}
 
-	return memberDTO, nil
+	return memberDTO, systemMessage, nil
 }
 
 // BulkAddMembers เพิ่มสมาชิกหลายคนพร้อมกันในการสนทนากลุ่ม
@@ -177,6 +216,7 @@ func (s *conversationMemberService) BulkAddMembers(userID, conversationID uuid.U
 			ID:             uuid.New(),
 			ConversationID: conversationID,
 			UserID:         newMemberID,
+			Role:           newMemberRole(conversation),
 			IsAdmin:        false,
 			JoinedAt:       now,
 		}
@@ -196,7 +236,7 @@ func (s *conversationMemberService) BulkAddMembers(userID, conversationID uuid.U
 			Username:       user.Username,
 			DisplayName:    user.DisplayName,
 			ProfilePicture: user.ProfileImageURL,
-			Role:           "member",
+			Role:           string(newMember.Role),
 			JoinedAt:       newMember.JoinedAt,
 			IsOnline:       false,
 		}
@@ -232,6 +272,15 @@ func (s *conversationMemberService) GetMembers(userID, conversationID uuid.UUID,
 		return nil, 0, errors.New("you are not a member of this conversation")
 	}
 
+	// ช่อง (channel) ซ่อนรายชื่อผู้ติดตาม และแบ่งหน้าที่ฐานข้อมูลเพราะมีผู้ติดตามจำนวนมาก
+	conversation, err := s.conversationRepo.GetByID(conversationID)
+	if err != nil {
+		return nil, 0, errors.New("error fetching conversation: " + err.Error())
+	}
+	if conversation.IsChannel() {
+		return s.getChannelMembers(userID, conversationID, page, limit)
+	}
+
 	// 2. ดึงรายการสมาชิกทั้งหมด
 	members, err := s.conversationRepo.GetMembers(conversationID)
 	if err != nil {
@@ -283,6 +332,52 @@ func (s *conversationMemberService) GetMembers(userID, conversationID uuid.UUID,
 	return memberDTOs, len(members), nil
 }
 
+// getChannelMembers ดึงรายชื่อผู้ติดตามช่อง (เฉพาะ owner/admin)
+func (s *conversationMemberService) getChannelMembers(userID, conversationID uuid.UUID, page, limit int) ([]*dto.MemberDTO, int, error) {
+	member, err := s.conversationRepo.GetMember(conversationID, userID)
+	if err != nil || member == nil {
+		return nil, 0, errors.New("you are not a member of this conversation")
+	}
+	if !isConversationManager(member) {
+		return nil, 0, errors.New("only channel admins can view subscribers")
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+
+	total, err := s.conversationRepo.CountMembers(conversationID)
+	if err != nil {
+		return nil, 0, errors.New("error counting members: " + err.Error())
+	}
+
+	members, err := s.conversationRepo.GetMembersPage(conversationID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, errors.New("error fetching members: " + err.Error())
+	}
+
+	memberDTOs := make([]*dto.MemberDTO, 0, len(members))
+	for _, m := range members {
+		if m.User == nil {
+			continue
+		}
+		memberDTOs = append(memberDTOs, &dto.MemberDTO{
+			ID:             m.ID.String(),
+			UserID:         m.UserID.String(),
+			Username:       m.User.Username,
+			DisplayName:    m.User.DisplayName,
+			ProfilePicture: m.User.ProfileImageURL,
+			Role:           string(m.Role),
+			JoinedAt:       m.JoinedAt,
+		})
+	}
+
+	return memberDTOs, int(total), nil
+}
+
 // RemoveMember ลบสมาชิกออกจากการสนทนา
 func (s *conversationMemberService) RemoveMember(userID, conversationID, memberToRemoveID uuid.UUID) error {
 	// 1. ตรวจสอบประเภทการสนทนา
@@ -447,6 +542,14 @@ func (s *conversationMemberService) getUserName(userID uuid.UUID) (string, error
 	return user.Username, nil
 }
 
+// displayNameOf ชื่อที่แสดงของผู้ใช้ (ชื่อแสดงหรือชื่อผู้ใช้)
+func displayNameOf(user *models.User) string {
+	if user.DisplayName != "" {
+		return user.DisplayName
+	}
+	return user.Username
+}
+
 // createSystemMessage สร้างข้อความระบบในการสนทนา
 func (s *conversationMemberService) createSystemMessage(conversationID uuid.UUID, content string) (uuid.UUID, error) {
 	systemMessage := &models.Message{
@@ -530,6 +633,18 @@ func (s *conversationMemberService) ChangeRole(conversationID, userID uuid.UUID,
 		return nil, errors.New("member not found")
 	}
 
+	// ช่อง (channel) ใช้ subscriber แทน member
+	conversation, err := s.conversationRepo.GetByID(conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+	if conversation.IsChannel() && newRole == models.RoleMember {
+		newRole = models.RoleSubscriber
+	}
+	if !conversation.IsChannel() && newRole == models.RoleSubscriber {
+		return nil, errors.New("subscriber role is only available in channels")
+	}
+
 	// อัปเดต role
 	member.Role = newRole
 
@@ -575,11 +690,171 @@ func (s *conversationMemberService) HasPermission(conversationID, userID uuid.UU
 		// Owner และ Admin เท่านั้น
 		return member.Role == models.RoleOwner || member.Role == models.RoleAdmin, nil
 
+	case service.PermissionManageInviteLinks:
+		// Owner และ Admin เท่านั้น
+		return member.Role == models.RoleOwner || member.Role == models.RoleAdmin, nil
+
+	case service.PermissionManageWebhooks:
+		// Owner และ Admin เท่านั้น
+		return member.Role == models.RoleOwner || member.Role == models.RoleAdmin, nil
+
+	case service.PermissionManageContentFilters:
+		// Owner และ Admin เท่านั้น
+		return member.Role == models.RoleOwner || member.Role == models.RoleAdmin, nil
+
 	case service.PermissionDeleteGroup:
 		// Owner เท่านั้น
 		return member.Role == models.RoleOwner, nil
 
+	case service.PermissionCreatePoll:
+		// ขึ้นกับการตั้งค่า poll_permission ของกลุ่ม (ค่าเริ่มต้น: สมาชิกทุกคน)
+		conversation, err := s.conversationRepo.GetByID(conversationID)
+		if err != nil {
+			return false, fmt.Errorf("failed to get conversation: %w", err)
+		}
+		switch pollPermissionLevel(conversation) {
+		case models.PollPermissionOwner:
+			return member.Role == models.RoleOwner, nil
+		case models.PollPermissionAdmins:
+			return member.Role == models.RoleOwner || member.Role == models.RoleAdmin, nil
+		default:
+			return true, nil
+		}
+
 	default:
 		return false, errors.New("unknown permission")
 	}
 }
+
+// CheckPostPermission ตรวจสอบว่าสมาชิกโพสต์ข้อความได้หรือไม่
+func (s *conversationMemberService) CheckPostPermission(conversationID, userID uuid.UUID) error {
+	_, err := checkPostPermission(s.conversationRepo, conversationID, userID)
+	return err
+}
+
+// SetPollPermission กำหนดว่าใครสร้างโพลในกลุ่มได้
+func (s *conversationMemberService) SetPollPermission(conversationID, userID uuid.UUID, level string) error {
+	switch level {
+	case models.PollPermissionAll, models.PollPermissionAdmins, models.PollPermissionOwner:
+	default:
+		return errors.New("invalid poll permission level")
+	}
+
+	member, err := s.conversationRepo.GetMember(conversationID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get member: %w", err)
+	}
+	if member == nil {
+		return errors.New("user is not a member of this conversation")
+	}
+	if member.Role != models.RoleOwner {
+		return errors.New("only the owner can change poll permission")
+	}
+
+	conversation, err := s.conversationRepo.GetByID(conversationID)
+	if err != nil {
+		return fmt.Errorf("failed to get conversation: %w", err)
+	}
+	if conversation.Type != "group" {
+		return errors.New("poll permission can only be set for group conversations")
+	}
+
+	metadata := types.JSONB{}
+	for key, value := range conversation.Metadata {
+		metadata[key] = value
+	}
+	metadata["poll_permission"] = level
+
+	if err := s.conversationRepo.UpdateConversation(conversationID, types.JSONB{
+		"metadata":   metadata,
+		"updated_at": time.Now(),
+	}); err != nil {
+		return fmt.Errorf("failed to update poll permission: %w", err)
+	}
+
+	return nil
+}
+
+// pollPermissionLevel อ่านระดับสิทธิ์การสร้างโพลจาก metadata ของการสนทนา
+func pollPermissionLevel(conversation *models.Conversation) string {
+	if conversation == nil || conversation.Type != "group" || conversation.Metadata == nil {
+		return models.PollPermissionAll
+	}
+	if level, ok := conversation.Metadata["poll_permission"].(string); ok && level != "" {
+		return level
+	}
+	return models.PollPermissionAll
+}
+
+// SetMessageTTL ตั้งค่าข้อความที่หายไปเอง
+// กลุ่ม: owner/admin เท่านั้น, direct: สมาชิกทุกคน
+func (s *conversationMemberService) SetMessageTTL(conversationID, userID uuid.UUID, ttl int) (int, *models.Message, error) {
+	if !models.IsValidMessageTTL(ttl) {
+		return 0, nil, errors.New("invalid message ttl")
+	}
+
+	member, err := s.conversationRepo.GetMember(conversationID, userID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get member: %w", err)
+	}
+	if member == nil {
+		return 0, nil, errors.New("user is not a member of this conversation")
+	}
+
+	conversation, err := s.conversationRepo.GetByID(conversationID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+
+	if conversation.Type == "group" && member.Role != models.RoleOwner && member.Role != models.RoleAdmin {
+		return 0, nil, errors.New("only owner or admin can change disappearing messages")
+	}
+
+	oldTTL := conversation.MessageTTL
+	if oldTTL == ttl {
+		return oldTTL, nil, nil
+	}
+
+	now := time.Now()
+	if err := s.conversationRepo.UpdateConversation(conversationID, types.JSONB{
+		"message_ttl": ttl,
+		"updated_at":  now,
+	}); err != nil {
+		return 0, nil, fmt.Errorf("failed to update message ttl: %w", err)
+	}
+
+	// สร้างข้อความระบบแจ้งสมาชิก
+	actorName, _ := s.getUserName(userID)
+	content := actorName + " turned off disappearing messages"
+	if ttl > 0 {
+		content = actorName + " set disappearing messages to " + formatMessageTTL(ttl)
+	}
+
+	msgID, err := s.createSystemMessage(conversationID, content)
+	if err != nil {
+		fmt.Printf("Error creating system message: %v, conversationID: %s\n", err, conversationID)
+		return oldTTL, nil, nil
+	}
+	s.conversationRepo.UpdateLastMessage(conversationID, msgID, content, now)
+
+	systemMessage, err := s.messageRepo.GetByID(msgID)
+	if err != nil {
+		return oldTTL, nil, nil
+	}
+
+	return oldTTL, systemMessage, nil
+}
+
+// formatMessageTTL แปลง TTL (วินาที) เป็นข้อความสำหรับข้อความระบบ
+func formatMessageTTL(ttl int) string {
+	switch ttl {
+	case models.MessageTTLOneHour:
+		return "1 hour"
+	case models.MessageTTLOneDay:
+		return "1 day"
+	case models.MessageTTLOneWeek:
+		return "7 days"
+	default:
+		return (time.Duration(ttl) * time.Second).String()
+	}
+}
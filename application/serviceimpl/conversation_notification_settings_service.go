@@ -0,0 +1,104 @@
+// application/serviceimpl/conversation_notification_settings_service.go
+package serviceimpl
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// GetNotificationSettings ดึงการตั้งค่าการแจ้งเตือนของผู้ใช้ในการสนทนา
+func (s *conversationService) GetNotificationSettings(conversationID, userID uuid.UUID) (*dto.ConversationNotificationSettingsDTO, error) {
+	member, err := s.getOwnMembership(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toConversationNotificationSettingsDTO(member, time.Now()), nil
+}
+
+// UpdateNotificationSettings ตั้งค่าระดับการแจ้งเตือนและการปิดเสียง (ถาวร หรือถึงเวลาที่กำหนด)
+func (s *conversationService) UpdateNotificationSettings(conversationID, userID uuid.UUID, req *dto.UpdateConversationNotificationSettingsRequest) (*dto.ConversationNotificationSettingsDTO, error) {
+	member, err := s.getOwnMembership(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	settings := member.NotificationPreferences()
+	isMuted := member.IsMutedAt(now)
+	if !isMuted {
+		settings.MutedUntil = nil // การปิดเสียงชั่วคราวที่หมดเวลาแล้ว
+	}
+
+	if req.Level != nil {
+		if !models.IsValidNotificationLevel(*req.Level) {
+			return nil, errors.New("invalid notification level")
+		}
+		settings.Level = *req.Level
+	}
+
+	switch {
+	case req.Muted != nil && !*req.Muted:
+		if req.MutedUntil != nil {
+			return nil, errors.New("muted_until requires muted to be true")
+		}
+		isMuted = false
+		settings.MutedUntil = nil
+	case req.MutedUntil != nil:
+		if !req.MutedUntil.After(now) {
+			return nil, errors.New("muted_until must be in the future")
+		}
+		isMuted = true
+		mutedUntil := req.MutedUntil.UTC().Truncate(time.Second)
+		settings.MutedUntil = &mutedUntil
+	case req.Muted != nil:
+		isMuted = true
+		settings.MutedUntil = nil
+	}
+
+	merged := settings.MergeInto(member.NotificationSettings)
+	if err := s.conversationRepo.UpdateNotificationSettings(conversationID, userID, isMuted, merged); err != nil {
+		return nil, err
+	}
+
+	member.IsMuted = isMuted
+	member.NotificationSettings = merged
+	return toConversationNotificationSettingsDTO(member, now), nil
+}
+
+// getOwnMembership ดึงข้อมูลสมาชิกของผู้ใช้เอง
+func (s *conversationService) getOwnMembership(conversationID, userID uuid.UUID) (*models.ConversationMember, error) {
+	isMember, err := s.conversationRepo.IsMember(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New("you are not a member of this conversation")
+	}
+	return s.conversationRepo.GetMember(conversationID, userID)
+}
+
+// toConversationNotificationSettingsDTO แปลงการตั้งค่าเป็น DTO (การปิดเสียงที่หมดเวลาแล้วแสดงเป็นไม่ได้ปิดเสียง)
+func toConversationNotificationSettingsDTO(member *models.ConversationMember, now time.Time) *dto.ConversationNotificationSettingsDTO {
+	settings := member.NotificationPreferences()
+	result := &dto.ConversationNotificationSettingsDTO{
+		ConversationID: member.ConversationID,
+		Level:          settings.Level,
+		IsMuted:        member.IsMutedAt(now),
+	}
+	if result.IsMuted {
+		result.MutedUntil = settings.MutedUntil
+	}
+	return result
+}
+
+// applyMemberNotificationFields ใส่สถานะปิดเสียง/ระดับการแจ้งเตือนของผู้ใช้ลงใน ConversationDTO
+func applyMemberNotificationFields(convDTO *dto.ConversationDTO, member *models.ConversationMember) {
+	settings := toConversationNotificationSettingsDTO(member, time.Now())
+	convDTO.IsMuted = settings.IsMuted
+	convDTO.MutedUntil = settings.MutedUntil
+	convDTO.NotificationLevel = settings.Level
+}
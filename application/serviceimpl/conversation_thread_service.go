@@ -0,0 +1,128 @@
+// application/serviceimpl/conversation_thread_service.go
+package serviceimpl
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// GetThreadMessages ดึงข้อความต้นเธรดพร้อมข้อความในเธรด
+// - ไม่ระบุ cursor: ดึงข้อความล่าสุดในเธรด
+// - beforeID: ดึงข้อความที่เก่ากว่า (เลื่อนขึ้น)
+// - afterID: ดึงข้อความที่ใหม่กว่า (เลื่อนลง)
+func (s *conversationService) GetThreadMessages(threadRootID, userID uuid.UUID, beforeID, afterID string, limit int) (*dto.ThreadDTO, error) {
+	root, err := s.getThreadRoot(threadRootID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// ดึง limit+1 เพื่อตรวจสอบ hasMore
+	// ผลลัพธ์จาก repository เรียง ASC เสมอ - โหมด before/ล่าสุด ข้อความส่วนเกินจะอยู่ฝั่งเก่า
+	var replies []*models.Message
+	trimOldest := true
+
+	switch {
+	case beforeID != "":
+		beforeUUID, err := uuid.Parse(beforeID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid before message ID: %w", err)
+		}
+		replies, err = s.messageRepo.GetThreadRepliesBefore(root.ID, beforeUUID, limit+1)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching thread replies before ID: %w", err)
+		}
+
+	case afterID != "":
+		afterUUID, err := uuid.Parse(afterID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid after message ID: %w", err)
+		}
+		replies, err = s.messageRepo.GetThreadRepliesAfter(root.ID, afterUUID, limit+1)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching thread replies after ID: %w", err)
+		}
+		trimOldest = false
+
+	default:
+		replies, err = s.messageRepo.GetLatestThreadReplies(root.ID, limit+1)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching thread replies: %w", err)
+		}
+	}
+
+	hasMore := len(replies) > limit
+	if hasMore {
+		if trimOldest {
+			replies = replies[len(replies)-limit:]
+		} else {
+			replies = replies[:limit]
+		}
+	}
+
+	return s.buildThreadDTO(root, replies, hasMore, userID)
+}
+
+// MarkThreadRead บันทึกว่าผู้ใช้อ่านเธรดถึงปัจจุบันแล้ว
+func (s *conversationService) MarkThreadRead(threadRootID, userID uuid.UUID) error {
+	root, err := s.getThreadRoot(threadRootID, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.threadReadRepo.MarkRead(root.ID, userID, time.Now())
+}
+
+// getThreadRoot ดึงข้อความต้นเธรดและตรวจสอบสิทธิ์การเข้าถึง
+// ถ้าส่ง ID ของข้อความในเธรดมา จะคืนค่าข้อความต้นเธรดของมันแทน
+func (s *conversationService) getThreadRoot(messageID, userID uuid.UUID) (*models.Message, error) {
+	root, err := s.messageRepo.GetByID(messageID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching thread root: %w", err)
+	}
+	if root == nil {
+		return nil, errors.New("message not found")
+	}
+
+	if root.ThreadRootID != nil {
+		root, err = s.messageRepo.GetByID(*root.ThreadRootID)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching thread root: %w", err)
+		}
+		if root == nil {
+			return nil, errors.New("message not found")
+		}
+	}
+
+	isMember, err := s.conversationRepo.IsMember(root.ConversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isMember {
+		return nil, errors.New("you are not a member of this conversation")
+	}
+
+	return root, nil
+}
+
+// buildThreadDTO แปลงข้อความต้นเธรดและข้อความในเธรดเป็น ThreadDTO
+func (s *conversationService) buildThreadDTO(root *models.Message, replies []*models.Message, hasMore bool, userID uuid.UUID) (*dto.ThreadDTO, error) {
+	rootDTO, err := s.ConvertToMessageDTO(root, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// ดึงปฏิกิริยาของข้อความในเธรดทั้งหน้าในคำสั่งเดียว
+	replyDTOs := s.convertMessagesToDTOs(replies, userID)
+
+	return &dto.ThreadDTO{
+		Root:    rootDTO,
+		Replies: replyDTOs,
+		HasMore: hasMore,
+	}, nil
+}
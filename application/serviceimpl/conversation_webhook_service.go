@@ -0,0 +1,376 @@
+// application/serviceimpl/conversation_webhook_service.go
+package serviceimpl
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+)
+
+const (
+	webhookTokenBytes             = 32 // token 43 ตัวอักษร (base64url)
+	webhookTokenPrefixLength      = 8
+	webhookPathPrefix             = "/api/v1/hooks/"
+	maxActiveWebhooksPerGroup     = 10
+	maxWebhookNameLength          = 80
+	maxWebhookTextLength          = 4000
+	maxWebhookAttachments         = 10
+	maxWebhookAttachmentFields    = 20
+	maxWebhookAttachmentTextBytes = 4000
+)
+
+var webhookColorRegex = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type conversationWebhookService struct {
+	webhookRepo      repository.ConversationWebhookRepository
+	conversationRepo repository.ConversationRepository
+	userRepo         repository.UserRepository
+	memberService    service.ConversationMemberService
+	messageService   service.MessageService
+}
+
+// NewConversationWebhookService สร้าง service ใหม่
+func NewConversationWebhookService(
+	webhookRepo repository.ConversationWebhookRepository,
+	conversationRepo repository.ConversationRepository,
+	userRepo repository.UserRepository,
+	memberService service.ConversationMemberService,
+	messageService service.MessageService,
+) service.ConversationWebhookService {
+	return &conversationWebhookService{
+		webhookRepo:      webhookRepo,
+		conversationRepo: conversationRepo,
+		userRepo:         userRepo,
+		memberService:    memberService,
+		messageService:   messageService,
+	}
+}
+
+// CreateWebhook สร้าง incoming webhook ของกลุ่ม/ช่อง
+func (s *conversationWebhookService) CreateWebhook(userID, conversationID uuid.UUID, req *dto.CreateConversationWebhookRequest) (*dto.ConversationWebhookCredentialsDTO, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxWebhookNameLength {
+		return nil, fmt.Errorf("webhook name must be 1-%d characters", maxWebhookNameLength)
+	}
+	if req.AvatarURL != "" && !isWebhookHTTPURL(req.AvatarURL) {
+		return nil, errors.New("invalid avatar url")
+	}
+
+	if _, err := s.getGroupConversation(conversationID); err != nil {
+		return nil, err
+	}
+	if err := s.checkManagePermission(conversationID, userID); err != nil {
+		return nil, err
+	}
+
+	// จำกัดจำนวน webhook ที่ยังใช้งานได้ต่อกลุ่ม
+	active, err := s.webhookRepo.CountActiveByConversationID(conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if active >= maxActiveWebhooksPerGroup {
+		return nil, fmt.Errorf("too many active webhooks (max %d)", maxActiveWebhooksPerGroup)
+	}
+
+	token, err := generateWebhookToken()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &models.ConversationWebhook{
+		ID:             uuid.New(),
+		ConversationID: conversationID,
+		CreatorID:      userID,
+		Name:           name,
+		AvatarURL:      req.AvatarURL,
+		TokenHash:      hashWebhookToken(token),
+		TokenPrefix:    token[:webhookTokenPrefixLength],
+	}
+	if err := s.webhookRepo.Create(webhook); err != nil {
+		return nil, err
+	}
+
+	webhook.Creator, _ = s.userRepo.FindByID(userID)
+	return &dto.ConversationWebhookCredentialsDTO{
+		Webhook: buildConversationWebhookDTO(webhook),
+		Token:   token,
+		Path:    webhookPathPrefix + token,
+	}, nil
+}
+
+// ListWebhooks ดึง incoming webhook ของกลุ่ม
+func (s *conversationWebhookService) ListWebhooks(userID, conversationID uuid.UUID, includeRevoked bool) ([]*dto.ConversationWebhookDTO, error) {
+	if _, err := s.getGroupConversation(conversationID); err != nil {
+		return nil, err
+	}
+	if err := s.checkManagePermission(conversationID, userID); err != nil {
+		return nil, err
+	}
+
+	webhooks, err := s.webhookRepo.ListByConversationID(conversationID, includeRevoked)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.ConversationWebhookDTO, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		result = append(result, buildConversationWebhookDTO(webhook))
+	}
+	return result, nil
+}
+
+// RevokeWebhook เพิกถอน webhook (URL เดิมใช้โพสต์ไม่ได้อีก)
+func (s *conversationWebhookService) RevokeWebhook(userID, conversationID, webhookID uuid.UUID) error {
+	if err := s.checkManagePermission(conversationID, userID); err != nil {
+		return err
+	}
+
+	webhook, err := s.webhookRepo.FindByID(webhookID)
+	if err != nil {
+		return err
+	}
+	if webhook == nil || webhook.ConversationID != conversationID {
+		return errors.New("webhook not found")
+	}
+
+	revoked, err := s.webhookRepo.Revoke(webhookID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return errors.New("webhook has been revoked")
+	}
+	return nil
+}
+
+// Execute ตรวจสอบ token และสร้างข้อความจาก payload (sender_type = webhook)
+func (s *conversationWebhookService) Execute(token string, payload *dto.IncomingWebhookPayload) (*models.Message, error) {
+	if token == "" {
+		return nil, errors.New("webhook not found")
+	}
+
+	webhook, err := s.webhookRepo.FindByTokenHash(hashWebhookToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil {
+		return nil, errors.New("webhook not found")
+	}
+	if !webhook.IsActive() {
+		return nil, errors.New("webhook has been revoked")
+	}
+
+	conversation, err := s.getGroupConversation(webhook.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := validateWebhookPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := buildWebhookMetadata(webhook, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := s.messageService.SendWebhookMessage(conversation, content, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.webhookRepo.TouchLastUsed(webhook.ID, time.Now()); err != nil {
+		fmt.Printf("Error updating webhook last used time: %v\n", err)
+	}
+
+	return message, nil
+}
+
+// getGroupConversation ดึงการสนทนาและตรวจสอบว่าเป็นกลุ่มหรือช่องที่ยังใช้งานอยู่
+func (s *conversationWebhookService) getGroupConversation(conversationID uuid.UUID) (*models.Conversation, error) {
+	conversation, err := s.conversationRepo.GetByID(conversationID)
+	if err != nil || conversation == nil || !conversation.IsActive {
+		return nil, errors.New("conversation not found")
+	}
+	if conversation.Type != "group" && !conversation.IsChannel() {
+		return nil, errors.New("webhooks are only available for groups and channels")
+	}
+	return conversation, nil
+}
+
+// checkManagePermission ตรวจสอบสิทธิ์จัดการ webhook
+func (s *conversationWebhookService) checkManagePermission(conversationID, userID uuid.UUID) error {
+	allowed, err := s.memberService.HasPermission(conversationID, userID, service.PermissionManageWebhooks)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errors.New("you don't have permission to manage webhooks")
+	}
+	return nil
+}
+
+// validateWebhookPayload ตรวจสอบ payload และคืนค่าเนื้อหาข้อความ
+// ถ้าไม่มี text จะใช้ title/text ของ attachment แรกเป็นเนื้อหา (ใช้แสดงเป็นข้อความล่าสุดของการสนทนาด้วย)
+func validateWebhookPayload(payload *dto.IncomingWebhookPayload) (string, error) {
+	if utf8.RuneCountInString(payload.Text) > maxWebhookTextLength {
+		return "", fmt.Errorf("text must not exceed %d characters", maxWebhookTextLength)
+	}
+	if utf8.RuneCountInString(payload.DisplayName) > maxWebhookNameLength {
+		return "", fmt.Errorf("display_name must not exceed %d characters", maxWebhookNameLength)
+	}
+	if payload.AvatarURL != "" && !isWebhookHTTPURL(payload.AvatarURL) {
+		return "", errors.New("invalid avatar url")
+	}
+	if len(payload.Attachments) > maxWebhookAttachments {
+		return "", fmt.Errorf("too many attachments (max %d)", maxWebhookAttachments)
+	}
+
+	fallback := ""
+	for i, attachment := range payload.Attachments {
+		if len(attachment.Title)+len(attachment.Text)+len(attachment.Footer) > maxWebhookAttachmentTextBytes {
+			return "", fmt.Errorf("attachment %d is too long", i)
+		}
+		if len(attachment.Fields) > maxWebhookAttachmentFields {
+			return "", fmt.Errorf("attachment %d has too many fields (max %d)", i, maxWebhookAttachmentFields)
+		}
+		if attachment.Color != "" && !webhookColorRegex.MatchString(attachment.Color) {
+			return "", fmt.Errorf("attachment %d has an invalid color", i)
+		}
+		if (attachment.TitleURL != "" && !isWebhookHTTPURL(attachment.TitleURL)) ||
+			(attachment.ImageURL != "" && !isWebhookHTTPURL(attachment.ImageURL)) {
+			return "", fmt.Errorf("attachment %d has an invalid url", i)
+		}
+
+		if fallback == "" {
+			fallback = strings.TrimSpace(attachment.Title)
+			if fallback == "" {
+				fallback = strings.TrimSpace(attachment.Text)
+			}
+		}
+	}
+
+	content := strings.TrimSpace(payload.Text)
+	if content == "" {
+		content = fallback
+	}
+	if content == "" {
+		return "", errors.New("webhook payload must contain text or attachments")
+	}
+	return content, nil
+}
+
+// buildWebhookMetadata สร้าง metadata ของข้อความ (ข้อมูลผู้ส่งที่แสดงและ attachments)
+func buildWebhookMetadata(webhook *models.ConversationWebhook, payload *dto.IncomingWebhookPayload) (map[string]interface{}, error) {
+	name := strings.TrimSpace(payload.DisplayName)
+	if name == "" {
+		name = webhook.Name
+	}
+	avatarURL := payload.AvatarURL
+	if avatarURL == "" {
+		avatarURL = webhook.AvatarURL
+	}
+
+	metadata := map[string]interface{}{
+		"webhook": map[string]interface{}{
+			"id":         webhook.ID.String(),
+			"name":       name,
+			"avatar_url": avatarURL,
+		},
+	}
+
+	if len(payload.Attachments) > 0 {
+		// แปลงผ่าน JSON เพื่อให้รูปแบบเหมือนตอนอ่านกลับจากฐานข้อมูล
+		data, err := json.Marshal(payload.Attachments)
+		if err != nil {
+			return nil, err
+		}
+		var attachments []interface{}
+		if err := json.Unmarshal(data, &attachments); err != nil {
+			return nil, err
+		}
+		metadata["attachments"] = attachments
+	}
+
+	return metadata, nil
+}
+
+// webhookSenderInfo ดึงชื่อและรูปผู้ส่งที่แสดงของข้อความจาก webhook
+func webhookSenderInfo(message *models.Message) (string, string) {
+	if message.Metadata == nil {
+		return "", ""
+	}
+	info, ok := message.Metadata["webhook"].(map[string]interface{})
+	if !ok {
+		return "", ""
+	}
+	name, _ := info["name"].(string)
+	avatarURL, _ := info["avatar_url"].(string)
+	return name, avatarURL
+}
+
+// isWebhookHTTPURL ตรวจสอบว่าเป็น URL แบบ http/https ที่มี host
+func isWebhookHTTPURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// buildConversationWebhookDTO แปลง model เป็น DTO
+func buildConversationWebhookDTO(webhook *models.ConversationWebhook) *dto.ConversationWebhookDTO {
+	result := &dto.ConversationWebhookDTO{
+		ID:             webhook.ID,
+		ConversationID: webhook.ConversationID,
+		Name:           webhook.Name,
+		AvatarURL:      webhook.AvatarURL,
+		TokenPrefix:    webhook.TokenPrefix,
+		IsActive:       webhook.IsActive(),
+		LastUsedAt:     webhook.LastUsedAt,
+		RevokedAt:      webhook.RevokedAt,
+		CreatedAt:      webhook.CreatedAt,
+	}
+
+	if webhook.Creator != nil {
+		result.Creator = &dto.UserInfoDTO{
+			ID:              webhook.Creator.ID.String(),
+			Username:        webhook.Creator.Username,
+			DisplayName:     webhook.Creator.DisplayName,
+			ProfileImageURL: webhook.Creator.ProfileImageURL,
+		}
+	}
+
+	return result
+}
+
+// generateWebhookToken สร้าง token แบบสุ่ม (URL-safe ใช้ใน path ได้)
+func generateWebhookToken() (string, error) {
+	buf := make([]byte, webhookTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashWebhookToken คืนค่า SHA-256 (hex) ของ token สำหรับเก็บและค้นหาในฐานข้อมูล
+func hashWebhookToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,6 +21,9 @@ type conversationService struct {
 	userRepo         repository.UserRepository
 	messageRepo      repository.MessageRepository
 	mentionRepo      repository.MessageMentionRepository
+	reactionRepo     repository.MessageReactionRepository
+	threadReadRepo   repository.ThreadReadRepository
+	pollRepo         repository.PollRepository
 }
 
 // NewConversationService สร้าง service ใหม่
@@ -28,12 +32,18 @@ func NewConversationService(
 	userRepo repository.UserRepository,
 	messageRepo repository.MessageRepository,
 	mentionRepo repository.MessageMentionRepository,
+	reactionRepo repository.MessageReactionRepository,
+	threadReadRepo repository.ThreadReadRepository,
+	pollRepo repository.PollRepository,
 ) service.ConversationService {
 	return &conversationService{
 		conversationRepo: conversationRepo,
 		userRepo:         userRepo,
 		messageRepo:      messageRepo,
 		mentionRepo:      mentionRepo,
+		reactionRepo:     reactionRepo,
+		threadReadRepo:   threadReadRepo,
+		pollRepo:         pollRepo,
 	}
 }
 
@@ -46,13 +56,15 @@ func (s *conversationService) CreateDirectConversation(userID, friendID uuid.UUI
 		return nil, errors.New("friend not found")
 	}
 
-	// 2. ตรวจสอบความเป็นเพื่อน (เพิ่มความเข้มงวด)
-	isFriend, err := s.checkFriendship(userID, friendID)
-	if err != nil {
-		return nil, err
-	}
-	if !isFriend {
-		return nil, errors.New("you must be friends to start a chat")
+	// 2. ตรวจสอบความเป็นเพื่อน (เพิ่มความเข้มงวด) ยกเว้นบัญชีบอทที่ทุกคนเริ่มแชทได้
+	if !friend.IsBot {
+		isFriend, err := s.checkFriendship(userID, friendID)
+		if err != nil {
+			return nil, err
+		}
+		if !isFriend {
+			return nil, errors.New("you must be friends to start a chat")
+		}
 	}
 
 	// 3. ตรวจสอบว่ามีการสนทนาอยู่แล้วหรือไม่
@@ -152,6 +164,11 @@ func (s *conversationService) GetUserConversations(userID uuid.UUID, limit, offs
 	return dtos, adjustedTotal, nil
 }
 
+// ConvertToConversationDTO แปลง Conversation model เป็น DTO ตามมุมมองของผู้ใช้
+func (s *conversationService) ConvertToConversationDTO(conversation *models.Conversation, userID uuid.UUID) (*dto.ConversationDTO, error) {
+	return s.convertToConversationDTO(conversation, userID)
+}
+
 func (s *conversationService) convertToConversationDTO(conversation *models.Conversation, userID uuid.UUID) (*dto.ConversationDTO, error) {
 	if conversation == nil {
 		return nil, errors.New("conversation is nil")
@@ -170,6 +187,7 @@ func (s *conversationService) convertToConversationDTO(conversation *models.Conv
 		CreatorID:       conversation.CreatorID,
 		IsActive:        conversation.IsActive,
 		Metadata:        conversation.Metadata,
+		MessageTTL:      conversation.MessageTTL,
 	}
 
 	// ดึงข้อมูลเพิ่มเติมตามประเภทการสนทนา
@@ -216,13 +234,22 @@ func (s *conversationService) convertToConversationDTO(conversation *models.Conv
 	member, err := s.conversationRepo.GetMember(conversation.ID, userID)
 	if err == nil && member != nil {
 		convDTO.IsPinned = member.IsPinned
-		convDTO.IsMuted = member.IsMuted
+		applyMemberNotificationFields(convDTO, member)
+		convDTO.MyRole = string(member.Role)
 
 		// คำนวณ unread_count
 		var unreadCount int
 		var lastReadAt *time.Time
 		if member.LastReadAt != nil {
 			lastReadAt = member.LastReadAt
+		}
+		if conversation.IsChannel() {
+			// ช่อง (channel) นับในฐานข้อมูลจากจุดอ่านหรือเวลาที่เข้าร่วม
+			count, err := s.messageRepo.CountMessagesAfterTime(conversation.ID, member.UnreadCursor(), userID)
+			if err == nil {
+				unreadCount = int(count)
+			}
+		} else if member.LastReadAt != nil {
 			messages, err := s.messageRepo.GetMessagesAfterTime(
 				conversation.ID, *member.LastReadAt, userID)
 			if err == nil {
@@ -274,10 +301,10 @@ func (s *conversationService) convertToConversationDTO(conversation *models.Conv
 		convDTO.LastMessageHasMention = false
 	}
 
-	// จำนวนสมาชิก
-	members, err := s.conversationRepo.GetMembers(conversation.ID)
+	// จำนวนสมาชิก (นับที่ฐานข้อมูล เพราะช่อง channel อาจมีผู้ติดตามจำนวนมาก)
+	memberCount, err := s.conversationRepo.CountMembers(conversation.ID)
 	if err == nil {
-		convDTO.MemberCount = len(members)
+		convDTO.MemberCount = int(memberCount)
 	} else {
 		convDTO.MemberCount = 0
 	}
@@ -454,6 +481,48 @@ func (s *conversationService) CreateGroupConversation(userID uuid.UUID, title, i
 	return convDTO, nil
 }
 
+// CreateChannelConversation สร้างช่องกระจายข่าว
+func (s *conversationService) CreateChannelConversation(userID uuid.UUID, title, iconURL string) (*dto.ConversationDTO, error) {
+	if strings.TrimSpace(title) == "" {
+		return nil, errors.New("channel requires a title")
+	}
+
+	now := time.Now()
+	conversation := &models.Conversation{
+		ID:        uuid.New(),
+		Type:      models.ConversationTypeChannel,
+		Title:     strings.TrimSpace(title),
+		IconURL:   iconURL,
+		CreatedAt: now,
+		UpdatedAt: now,
+		CreatorID: &userID,
+		IsActive:  true,
+	}
+
+	if err := s.conversationRepo.Create(conversation); err != nil {
+		return nil, err
+	}
+
+	owner := &models.ConversationMember{
+		ID:             uuid.New(),
+		ConversationID: conversation.ID,
+		UserID:         userID,
+		Role:           models.RoleOwner,
+		IsAdmin:        true, // Keep for backward compatibility
+		JoinedAt:       now,
+	}
+	if err := s.conversationRepo.AddMember(owner); err != nil {
+		return nil, err
+	}
+
+	createdConv, err := s.conversationRepo.GetByID(conversation.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.convertToConversationDTO(createdConv, userID)
+}
+
 // GetConversationMessages ดึงข้อความทั้งหมดในการสนทนา
 func (s *conversationService) GetConversationMessages(conversationID, userID uuid.UUID, limit, offset int) ([]*dto.MessageDTO, int64, error) {
 	// ตรวจสอบว่าผู้ใช้เป็นสมาชิกของการสนทนานี้
@@ -472,16 +541,8 @@ func (s *conversationService) GetConversationMessages(conversationID, userID uui
 		return nil, 0, err
 	}
 
-	// แปลงข้อความเป็น DTOs
-	messageDTOs := make([]*dto.MessageDTO, 0, len(messages))
-	for _, msg := range messages {
-		messageDTO, err := s.ConvertToMessageDTO(msg, userID)
-		if err != nil {
-			// ข้ามข้อความที่มีปัญหา
-			continue
-		}
-		messageDTOs = append(messageDTOs, messageDTO)
-	}
+	// แปลงเป็น DTOs (ดึงปฏิกิริยาของทั้งหน้าในคำสั่งเดียว)
+	messageDTOs := s.convertMessagesToDTOs(messages, userID)
 
 	return messageDTOs, total, nil
 }
@@ -514,8 +575,15 @@ func (s *conversationService) SetMuteStatus(conversationID, userID uuid.UUID, is
 		return errors.New("you are not a member of this conversation")
 	}
 
-	// อัพเดตสถานะปิดเสียง
-	return s.conversationRepo.SetMuteStatus(conversationID, userID, isMuted)
+	member, err := s.conversationRepo.GetMember(conversationID, userID)
+	if err != nil {
+		return err
+	}
+
+	// อัพเดตสถานะปิดเสียง (เปิด/ปิดแบบถาวร จึงล้าง muted_until ของการปิดเสียงชั่วคราว)
+	settings := member.NotificationPreferences()
+	settings.MutedUntil = nil
+	return s.conversationRepo.UpdateNotificationSettings(conversationID, userID, isMuted, settings.MergeInto(member.NotificationSettings))
 }
 
 // CheckMembership ตรวจสอบว่าผู้ใช้เป็นสมาชิกของการสนทนาหรือไม่
@@ -523,8 +591,60 @@ func (s *conversationService) CheckMembership(userID, conversationID uuid.UUID)
 	return s.conversationRepo.IsMember(conversationID, userID)
 }
 
+// GetMemberUserIDs ดึง user ID ของสมาชิกทั้งหมดในการสนทนา
+func (s *conversationService) GetMemberUserIDs(conversationID uuid.UUID) ([]uuid.UUID, error) {
+	return s.conversationRepo.GetMemberUserIDs(conversationID)
+}
+
 // ConvertToMessageDTO แปลง Message model เป็น MessageDTO
 func (s *conversationService) ConvertToMessageDTO(msg *models.Message, userID uuid.UUID) (*dto.MessageDTO, error) {
+	return s.convertToMessageDTO(msg, userID, nil)
+}
+
+// convertMessagesToDTOs แปลงข้อความทั้งหน้าเป็น DTOs โดยดึงปฏิกิริยาของทุกข้อความในคำสั่งเดียว (ข้ามข้อความที่มีปัญหา)
+func (s *conversationService) convertMessagesToDTOs(messages []*models.Message, userID uuid.UUID) []*dto.MessageDTO {
+	reactions := s.loadReactionsByMessage(messages)
+
+	messageDTOs := make([]*dto.MessageDTO, 0, len(messages))
+	for _, msg := range messages {
+		messageDTO, err := s.convertToMessageDTO(msg, userID, reactions)
+		if err != nil {
+			continue
+		}
+		messageDTOs = append(messageDTOs, messageDTO)
+	}
+	return messageDTOs
+}
+
+// loadReactionsByMessage ดึงปฏิกิริยาของข้อความหลายรายการแล้วจัดกลุ่มตาม message ID
+// (คืนค่า nil เมื่อดึงไม่ได้ ซึ่งทำให้ convertToMessageDTO ดึงทีละข้อความแทน)
+func (s *conversationService) loadReactionsByMessage(messages []*models.Message) map[uuid.UUID][]*models.MessageReaction {
+	if s.reactionRepo == nil || len(messages) == 0 {
+		return nil
+	}
+
+	messageIDs := make([]uuid.UUID, 0, len(messages))
+	for _, msg := range messages {
+		if msg != nil && !msg.IsDeleted {
+			messageIDs = append(messageIDs, msg.ID)
+		}
+	}
+
+	reactions, err := s.reactionRepo.GetByMessageIDs(messageIDs)
+	if err != nil {
+		return nil
+	}
+
+	byMessage := make(map[uuid.UUID][]*models.MessageReaction, len(messageIDs))
+	for _, reaction := range reactions {
+		byMessage[reaction.MessageID] = append(byMessage[reaction.MessageID], reaction)
+	}
+	return byMessage
+}
+
+// convertToMessageDTO แปลง Message model เป็น MessageDTO
+// reactions คือปฏิกิริยาที่ดึงไว้แล้วของทั้งหน้า (nil = ดึงปฏิกิริยาของข้อความนี้เอง)
+func (s *conversationService) convertToMessageDTO(msg *models.Message, userID uuid.UUID, reactions map[uuid.UUID][]*models.MessageReaction) (*dto.MessageDTO, error) {
 	if msg == nil {
 		return nil, errors.New("message is nil")
 	}
@@ -556,7 +676,10 @@ func (s *conversationService) ConvertToMessageDTO(msg *models.Message, userID uu
 		IsDeleted:         msg.IsDeleted,
 		IsEdited:          msg.IsEdited,
 		EditCount:         msg.EditCount,
+		ExpiresAt:         msg.ExpiresAt,
 		ReplyToID:         msg.ReplyToID,
+		ThreadRootID:      msg.ThreadRootID,
+		ViewCount:         msg.ViewCount,
 		ReadCount:         0,     // ค่าเริ่มต้น จะอัปเดตทีหลัง
 		IsRead:            false, // ค่าเริ่มต้น จะอัปเดตทีหลัง
 	}
@@ -635,6 +758,15 @@ func (s *conversationService) ConvertToMessageDTO(msg *models.Message, userID uu
 		s.addReplyToInfoToDTO(messageDTO)
 	}
 
+	// 4. เพิ่มสรุปปฏิกิริยา (emoji) ของข้อความ
+	s.addReactionsToDTO(messageDTO, userID, reactions)
+	s.addPollToDTO(messageDTO, userID)
+
+	// 5. เพิ่มข้อมูลเธรด (เฉพาะข้อความต้นเธรดที่มีข้อความตอบกลับ)
+	if msg.ThreadReplyCount > 0 {
+		s.addThreadInfoToDTO(messageDTO, msg, userID)
+	}
+
 	return messageDTO, nil
 }
 
@@ -656,6 +788,44 @@ func (s *conversationService) addSenderInfoToDTO(msgDTO *dto.MessageDTO) {
 		}
 }
 
+// addReactionsToDTO เพิ่มสรุปปฏิกิริยาต่อข้อความใน DTO (ใช้ปฏิกิริยาที่ดึงไว้แล้วถ้ามี)
+func (s *conversationService) addReactionsToDTO(msgDTO *dto.MessageDTO, userID uuid.UUID, preloaded map[uuid.UUID][]*models.MessageReaction) {
+	if s.reactionRepo == nil || msgDTO.IsDeleted {
+		return
+	}
+
+	if preloaded != nil {
+		msgDTO.Reactions = buildReactionSummaries(preloaded[msgDTO.ID], userID)
+		return
+	}
+
+	reactions, err := s.reactionRepo.GetByMessageID(msgDTO.ID)
+	if err != nil {
+		return
+	}
+
+	msgDTO.Reactions = buildReactionSummaries(reactions, userID)
+}
+
+// addPollToDTO เพิ่มข้อมูลโพลและผลโหวตใน DTO (เฉพาะข้อความประเภท poll)
+func (s *conversationService) addPollToDTO(msgDTO *dto.MessageDTO, userID uuid.UUID) {
+	if s.pollRepo == nil || msgDTO.MessageType != "poll" || msgDTO.IsDeleted {
+		return
+	}
+
+	poll, err := s.pollRepo.GetByMessageID(msgDTO.ID)
+	if err != nil || poll == nil {
+		return
+	}
+
+	votes, err := s.pollRepo.GetVotes(poll.ID)
+	if err != nil {
+		return
+	}
+
+	msgDTO.Poll = buildPollDTO(poll, votes, userID)
+}
+
 // addReadStatusToDTO เพิ่มข้อมูลสถานะการอ่านใน DTO
 func (s *conversationService) addReadStatusToDTO(msgDTO *dto.MessageDTO, userID uuid.UUID) {
 	// ดึงข้อมูลการอ่านทั้งหมดของข้อความนี้
@@ -697,6 +867,11 @@ func (s *conversationService) addReplyToInfoToDTO(msgDTO *dto.MessageDTO) {
 		return
 	}
 
+	msgDTO.ReplyToMessage = s.buildReplyInfo(replyMsg)
+}
+
+// buildReplyInfo สร้างข้อมูลย่อของข้อความ (ใช้กับข้อความที่ตอบกลับและตัวอย่างข้อความล่าสุดในเธรด)
+func (s *conversationService) buildReplyInfo(replyMsg *models.Message) *dto.ReplyInfoDTO {
 	// สร้างข้อมูลย่อของข้อความที่ตอบกลับ
 	replyInfo := &dto.ReplyInfoDTO{
 		ID:          replyMsg.ID.String(),
@@ -717,7 +892,34 @@ func (s *conversationService) addReplyToInfoToDTO(msgDTO *dto.MessageDTO) {
 			}
 	}
 
-	msgDTO.ReplyToMessage = replyInfo
+	return replyInfo
+}
+
+// addThreadInfoToDTO เพิ่มจำนวนข้อความ, ตัวอย่างข้อความล่าสุด และจำนวนที่ยังไม่ได้อ่านของเธรดใน DTO
+func (s *conversationService) addThreadInfoToDTO(msgDTO *dto.MessageDTO, msg *models.Message, userID uuid.UUID) {
+	msgDTO.ThreadReplyCount = msg.ThreadReplyCount
+	msgDTO.ThreadLastReplyAt = msg.ThreadLastReplyAt
+
+	if msg.ThreadLastReplyID != nil {
+		lastReply, err := s.messageRepo.GetByID(*msg.ThreadLastReplyID)
+		if err == nil && lastReply != nil {
+			msgDTO.ThreadLastReply = s.buildReplyInfo(lastReply)
+		}
+	}
+
+	if s.threadReadRepo == nil {
+		return
+	}
+
+	lastReadAt, err := s.threadReadRepo.GetLastReadAt(msg.ID, userID)
+	if err != nil {
+		return
+	}
+
+	unread, err := s.messageRepo.CountThreadRepliesAfterTime(msg.ID, lastReadAt, userID)
+	if err == nil {
+		msgDTO.ThreadUnreadCount = int(unread)
+	}
 }
 
 // GetMessageContext ดึงข้อความเป้าหมายพร้อมข้อความก่อนหน้าและถัดไป
@@ -793,9 +995,10 @@ func (s *conversationService) GetMessageContext(conversationID, userID uuid.UUID
 	})
 
 	// แปลงเป็น DTOs โดยใช้ฟังก์ชันที่มีอยู่แล้ว
+	reactions := s.loadReactionsByMessage(allMessages)
 	messageDTOs := make([]*dto.MessageDTO, 0, len(allMessages))
 	for _, msg := range allMessages {
-		messageDTO, err := s.ConvertToMessageDTO(msg, userID)
+		messageDTO, err := s.convertToMessageDTO(msg, userID, reactions)
 		if err != nil {
 			// ข้ามข้อความที่มีปัญหา
 			continue
@@ -846,16 +1049,8 @@ func (s *conversationService) GetMessagesBeforeID(conversationID, userID uuid.UU
 		total = int64(len(messages))
 	}
 
-	// แปลงเป็น DTOs โดยใช้ฟังก์ชันที่มีอยู่แล้ว
-	messageDTOs := make([]*dto.MessageDTO, 0, len(messages))
-	for _, msg := range messages {
-		messageDTO, err := s.ConvertToMessageDTO(msg, userID)
-		if err != nil {
-			// ข้ามข้อความที่มีปัญหา
-			continue
-		}
-		messageDTOs = append(messageDTOs, messageDTO)
-	}
+	// แปลงเป็น DTOs (ดึงปฏิกิริยาของทั้งหน้าในคำสั่งเดียว)
+	messageDTOs := s.convertMessagesToDTOs(messages, userID)
 
 	return messageDTOs, total, nil
 }
@@ -893,16 +1088,8 @@ func (s *conversationService) GetMessagesAfterID(conversationID, userID uuid.UUI
 		total = int64(len(messages))
 	}
 
-	// แปลงเป็น DTOs โดยใช้ฟังก์ชันที่มีอยู่แล้ว
-	messageDTOs := make([]*dto.MessageDTO, 0, len(messages))
-	for _, msg := range messages {
-		messageDTO, err := s.ConvertToMessageDTO(msg, userID)
-		if err != nil {
-			// ข้ามข้อความที่มีปัญหา
-			continue
-		}
-		messageDTOs = append(messageDTOs, messageDTO)
-	}
+	// แปลงเป็น DTOs (ดึงปฏิกิริยาของทั้งหน้าในคำสั่งเดียว)
+	messageDTOs := s.convertMessagesToDTOs(messages, userID)
 
 	return messageDTOs, total, nil
 }
@@ -1242,9 +1429,9 @@ func (s *conversationService) TransferOwnership(conversationID, currentOwnerID,
 		return errors.New("conversation not found")
 	}
 
-	// 2. ตรวจสอบว่าเป็น group conversation (ไม่สามารถโอนความเป็นเจ้าของใน direct chat ได้)
-	if conversation.Type != "group" {
-		return errors.New("ownership transfer is only available for group conversations")
+	// 2. ตรวจสอบว่าเป็น group หรือ channel (ไม่สามารถโอนความเป็นเจ้าของใน direct chat ได้)
+	if conversation.Type != "group" && !conversation.IsChannel() {
+		return errors.New("ownership transfer is only available for groups and channels")
 	}
 
 	// 3. ตรวจสอบว่า current owner เป็น owner จริงหรือไม่
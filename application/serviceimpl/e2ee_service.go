@@ -0,0 +1,321 @@
+// application/serviceimpl/e2ee_service.go
+package serviceimpl
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+)
+
+// ข้อจำกัดของ key (public key ของ Curve25519/Ed25519 มีขนาดเล็กมาก)
+const (
+	maxE2EEDeviceIDLength = 64
+	maxE2EEKeyBytes       = 1024
+	maxE2EEPreKeysPerCall = 100
+)
+
+type e2eeService struct {
+	keyRepo             repository.E2EEKeyRepository
+	userRepo            repository.UserRepository
+	conversationRepo    repository.ConversationRepository
+	userFriendshipRepo  repository.UserFriendshipRepository
+	notificationService service.NotificationService
+}
+
+// NewE2EEService สร้าง service ใหม่
+func NewE2EEService(
+	keyRepo repository.E2EEKeyRepository,
+	userRepo repository.UserRepository,
+	conversationRepo repository.ConversationRepository,
+	userFriendshipRepo repository.UserFriendshipRepository,
+	notificationService service.NotificationService,
+) service.E2EEService {
+	return &e2eeService{
+		keyRepo:             keyRepo,
+		userRepo:            userRepo,
+		conversationRepo:    conversationRepo,
+		userFriendshipRepo:  userFriendshipRepo,
+		notificationService: notificationService,
+	}
+}
+
+// UploadBundle บันทึก identity/signed pre-key ของอุปกรณ์
+// อุปกรณ์ใหม่หรือ identity key ที่เปลี่ยนจะถูกแจ้งไปยังคู่สนทนาเพื่อให้ตรวจสอบ safety number ใหม่
+func (s *e2eeService) UploadBundle(userID uuid.UUID, deviceID string, req *dto.UploadKeyBundleRequest) (*models.E2EEKeyBundle, error) {
+	deviceID, err := normalizeE2EEDeviceID(deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return nil, errors.New("key bundle is required")
+	}
+	if err := validateE2EEKey(req.IdentityKey); err != nil {
+		return nil, fmt.Errorf("invalid identity key")
+	}
+	if err := validateE2EEKey(req.SignedPreKey.PublicKey); err != nil {
+		return nil, fmt.Errorf("invalid signed pre-key")
+	}
+	if err := validateE2EEKey(req.SignedPreKey.Signature); err != nil {
+		return nil, fmt.Errorf("invalid signed pre-key signature")
+	}
+	if err := validatePreKeys(req.OneTimePreKeys); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.keyRepo.GetBundle(userID, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &models.E2EEKeyBundle{
+		UserID:                userID,
+		DeviceID:              deviceID,
+		RegistrationID:        req.RegistrationID,
+		IdentityKey:           req.IdentityKey,
+		SignedPreKeyID:        req.SignedPreKey.KeyID,
+		SignedPreKey:          req.SignedPreKey.PublicKey,
+		SignedPreKeySignature: req.SignedPreKey.Signature,
+	}
+	if err := s.keyRepo.UpsertBundle(bundle); err != nil {
+		return nil, err
+	}
+
+	identityChanged := existing == nil || existing.IdentityKey != req.IdentityKey
+
+	// identity key ใหม่ทำให้ one-time pre-key เดิมใช้ไม่ได้ (ถูกสร้างคู่กับ identity เดิม)
+	if existing != nil && identityChanged {
+		if err := s.keyRepo.DeleteOneTimePreKeys(userID, deviceID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.keyRepo.AddOneTimePreKeys(buildPreKeyModels(userID, deviceID, req.OneTimePreKeys)); err != nil {
+		return nil, err
+	}
+
+	if identityChanged {
+		s.notifyKeyChanged(userID, &dto.KeyChangedEvent{
+			UserID:      userID,
+			DeviceID:    deviceID,
+			Change:      models.E2EEKeyChangeIdentity,
+			IdentityKey: req.IdentityKey,
+		})
+	}
+
+	return bundle, nil
+}
+
+// UploadPreKeys เติม one-time pre-key ให้อุปกรณ์ที่ลงทะเบียนแล้ว
+func (s *e2eeService) UploadPreKeys(userID uuid.UUID, deviceID string, keys []dto.PreKeyDTO) (int64, error) {
+	deviceID, err := normalizeE2EEDeviceID(deviceID)
+	if err != nil {
+		return 0, err
+	}
+	if len(keys) == 0 {
+		return 0, errors.New("one-time pre-keys are required")
+	}
+	if err := validatePreKeys(keys); err != nil {
+		return 0, err
+	}
+
+	bundle, err := s.keyRepo.GetBundle(userID, deviceID)
+	if err != nil {
+		return 0, err
+	}
+	if bundle == nil {
+		return 0, errors.New("device key bundle not found")
+	}
+
+	if err := s.keyRepo.AddOneTimePreKeys(buildPreKeyModels(userID, deviceID, keys)); err != nil {
+		return 0, err
+	}
+
+	return s.keyRepo.CountOneTimePreKeys(userID, deviceID)
+}
+
+// GetPreKeyCount นับ one-time pre-key ที่เหลือ (client ควรเติมเมื่อเหลือน้อย)
+func (s *e2eeService) GetPreKeyCount(userID uuid.UUID, deviceID string) (int64, error) {
+	deviceID, err := normalizeE2EEDeviceID(deviceID)
+	if err != nil {
+		return 0, err
+	}
+
+	bundle, err := s.keyRepo.GetBundle(userID, deviceID)
+	if err != nil {
+		return 0, err
+	}
+	if bundle == nil {
+		return 0, errors.New("device key bundle not found")
+	}
+
+	return s.keyRepo.CountOneTimePreKeys(userID, deviceID)
+}
+
+// GetUserBundles ดึง key bundle ของทุกอุปกรณ์ของผู้ใช้ปลายทาง
+// อนุญาตเฉพาะตัวเอง (อุปกรณ์อื่นของตัวเอง) คู่สนทนาแบบ direct หรือเพื่อน เพื่อไม่ให้ใครก็ได้ดึง pre-key จนหมด
+func (s *e2eeService) GetUserBundles(requesterID, targetUserID uuid.UUID) ([]*dto.KeyBundleDTO, error) {
+	if requesterID != targetUserID {
+		target, err := s.userRepo.FindByID(targetUserID)
+		if err != nil || target == nil {
+			return nil, errors.New("user not found")
+		}
+
+		allowed, err := s.canFetchKeys(requesterID, targetUserID)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, errors.New("you are not allowed to fetch keys for this user")
+		}
+	}
+
+	bundles, err := s.keyRepo.GetBundlesByUserID(targetUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.KeyBundleDTO, 0, len(bundles))
+	for _, bundle := range bundles {
+		bundleDTO := &dto.KeyBundleDTO{
+			UserID:         bundle.UserID,
+			DeviceID:       bundle.DeviceID,
+			RegistrationID: bundle.RegistrationID,
+			IdentityKey:    bundle.IdentityKey,
+			SignedPreKey: dto.SignedPreKeyDTO{
+				KeyID:     bundle.SignedPreKeyID,
+				PublicKey: bundle.SignedPreKey,
+				Signature: bundle.SignedPreKeySignature,
+			},
+		}
+
+		preKey, err := s.keyRepo.ClaimOneTimePreKey(bundle.UserID, bundle.DeviceID)
+		if err != nil {
+			return nil, err
+		}
+		if preKey != nil {
+			bundleDTO.OneTimePreKey = &dto.PreKeyDTO{
+				KeyID:     preKey.KeyID,
+				PublicKey: preKey.PublicKey,
+			}
+		}
+
+		result = append(result, bundleDTO)
+	}
+
+	return result, nil
+}
+
+// RemoveDevice ลบ key ของอุปกรณ์ (เช่น ออกจากระบบหรือรีเซ็ตอุปกรณ์)
+func (s *e2eeService) RemoveDevice(userID uuid.UUID, deviceID string) error {
+	deviceID, err := normalizeE2EEDeviceID(deviceID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.keyRepo.DeleteBundle(userID, deviceID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("device key bundle not found")
+	}
+
+	s.notifyKeyChanged(userID, &dto.KeyChangedEvent{
+		UserID:   userID,
+		DeviceID: deviceID,
+		Change:   models.E2EEKeyChangeRemoved,
+	})
+	return nil
+}
+
+// canFetchKeys ตรวจสอบว่ามีการสนทนาแบบ direct หรือเป็นเพื่อนกัน (และไม่ได้บล็อกกัน)
+func (s *e2eeService) canFetchKeys(requesterID, targetUserID uuid.UUID) (bool, error) {
+	for _, pair := range [][2]uuid.UUID{{requesterID, targetUserID}, {targetUserID, requesterID}} {
+		friendship, err := s.userFriendshipRepo.FindByUserIDAndFriendID(pair[0], pair[1])
+		if err != nil || friendship == nil {
+			continue
+		}
+		if friendship.Status == "blocked" {
+			return false, nil
+		}
+		if friendship.Status == "accepted" {
+			return true, nil
+		}
+	}
+
+	conversation, err := s.conversationRepo.FindDirectConversation(requesterID, targetUserID)
+	if err != nil {
+		return false, err
+	}
+	return conversation != nil, nil
+}
+
+// notifyKeyChanged แจ้ง e2ee.key_changed ไปยังคู่สนทนาแบบ direct และอุปกรณ์อื่นของผู้ใช้เอง
+func (s *e2eeService) notifyKeyChanged(userID uuid.UUID, event *dto.KeyChangedEvent) {
+	recipients, err := s.conversationRepo.GetDirectPartnerIDs(userID)
+	if err != nil {
+		fmt.Printf("Error loading direct partners for key change: %v, userID: %s\n", err, userID)
+	}
+	recipients = append(recipients, userID)
+
+	s.notificationService.NotifyKeyChanged(recipients, event)
+}
+
+// normalizeE2EEDeviceID ตรวจสอบ device ID ที่ client กำหนด
+func normalizeE2EEDeviceID(deviceID string) (string, error) {
+	deviceID = strings.TrimSpace(deviceID)
+	if deviceID == "" || len(deviceID) > maxE2EEDeviceIDLength {
+		return "", errors.New("invalid device id")
+	}
+	return deviceID, nil
+}
+
+// validateE2EEKey ตรวจสอบว่าเป็น base64 ที่ไม่ว่างและขนาดไม่เกินกำหนด
+func validateE2EEKey(key string) error {
+	if key == "" || len(key) > base64.StdEncoding.EncodedLen(maxE2EEKeyBytes) {
+		return errors.New("invalid key")
+	}
+	if _, err := base64.StdEncoding.DecodeString(key); err != nil {
+		return errors.New("invalid key")
+	}
+	return nil
+}
+
+// validatePreKeys ตรวจสอบ one-time pre-key ที่อัปโหลด
+func validatePreKeys(keys []dto.PreKeyDTO) error {
+	if len(keys) > maxE2EEPreKeysPerCall {
+		return fmt.Errorf("too many one-time pre-keys (max %d)", maxE2EEPreKeysPerCall)
+	}
+	seen := make(map[int]bool, len(keys))
+	for _, key := range keys {
+		if seen[key.KeyID] {
+			return errors.New("duplicate one-time pre-key id")
+		}
+		seen[key.KeyID] = true
+		if err := validateE2EEKey(key.PublicKey); err != nil {
+			return errors.New("invalid one-time pre-key")
+		}
+	}
+	return nil
+}
+
+func buildPreKeyModels(userID uuid.UUID, deviceID string, keys []dto.PreKeyDTO) []*models.E2EEOneTimePreKey {
+	preKeys := make([]*models.E2EEOneTimePreKey, 0, len(keys))
+	for _, key := range keys {
+		preKeys = append(preKeys, &models.E2EEOneTimePreKey{
+			ID:        uuid.New(),
+			UserID:    userID,
+			DeviceID:  deviceID,
+			KeyID:     key.KeyID,
+			PublicKey: key.PublicKey,
+		})
+	}
+	return preKeys
+}
@@ -52,15 +52,15 @@ func (s *groupActivityService) GetActivities(conversationID, userID uuid.UUID, l
 	// แปลงเป็น DTO
 	dtos := make([]*dto.ActivityDTO, 0, len(activities))
 	for _, activity := range activities {
-		activityDTO := s.convertToActivityDTO(activity)
+		activityDTO := buildActivityDTO(activity)
 		dtos = append(dtos, activityDTO)
 	}
 
 	return dtos, total, nil
 }
 
-// convertToActivityDTO แปลง Activity model เป็น DTO
-func (s *groupActivityService) convertToActivityDTO(activity *models.GroupActivity) *dto.ActivityDTO {
+// buildActivityDTO แปลง Activity model เป็น DTO (ใช้ร่วมกับ sync service)
+func buildActivityDTO(activity *models.GroupActivity) *dto.ActivityDTO {
 	activityDTO := &dto.ActivityDTO{
 		ID:             activity.ID.String(),
 		ConversationID: activity.ConversationID.String(),
@@ -136,14 +136,15 @@ func (s *groupActivityService) LogGroupIconChanged(conversationID, actorID uuid.
 	return s.activityRepo.Create(activity)
 }
 
-// LogMemberAdded บันทึกการเพิ่มสมาชิก
-func (s *groupActivityService) LogMemberAdded(conversationID, actorID, targetID uuid.UUID) error {
+// LogMemberAdded บันทึกการเพิ่มสมาชิก (source เก็บใน new_value เช่น ลิงก์เชิญที่ใช้เข้ากลุ่ม)
+func (s *groupActivityService) LogMemberAdded(conversationID, actorID, targetID uuid.UUID, source types.JSONB) error {
 	activity := &models.GroupActivity{
 		ID:             uuid.New(),
 		ConversationID: conversationID,
 		Type:           models.ActivityMemberAdded,
 		ActorID:        actorID,
 		TargetID:       &targetID,
+		NewValue:       source,
 		CreatedAt:      time.Now(),
 	}
 
@@ -190,7 +191,7 @@ func (s *groupActivityService) LogMemberRoleChanged(conversationID, actorID, tar
 	// Broadcast WebSocket event พร้อม user info
 	activityWithUsers, err := s.activityRepo.GetByID(activity.ID)
 	if err == nil && s.notificationService != nil {
-		activityDTO := s.convertToActivityDTO(activityWithUsers)
+		activityDTO := buildActivityDTO(activityWithUsers)
 		s.notificationService.NotifyNewActivity(conversationID, activityDTO)
 	}
 
@@ -215,7 +216,7 @@ func (s *groupActivityService) LogOwnershipTransferred(conversationID, oldOwnerI
 	// Broadcast WebSocket event พร้อม user info
 	activityWithUsers, err := s.activityRepo.GetByID(activity.ID)
 	if err == nil && s.notificationService != nil {
-		activityDTO := s.convertToActivityDTO(activityWithUsers)
+		activityDTO := buildActivityDTO(activityWithUsers)
 		s.notificationService.NotifyNewActivity(conversationID, activityDTO)
 	}
 
@@ -234,3 +235,69 @@ func (s *groupActivityService) LogMemberLeft(conversationID, userID uuid.UUID) e
 
 	return s.activityRepo.Create(activity)
 }
+
+// LogMemberJoinRequested บันทึกคำขอเข้าร่วมกลุ่ม (ไม่ broadcast เพราะผู้ขอยังไม่เป็นสมาชิก แอดมินได้รับ join_request.received แทน)
+func (s *groupActivityService) LogMemberJoinRequested(conversationID, userID, requestID uuid.UUID) error {
+	activity := &models.GroupActivity{
+		ID:             uuid.New(),
+		ConversationID: conversationID,
+		Type:           models.ActivityMemberJoinRequested,
+		ActorID:        userID,
+		NewValue:       types.JSONB{"join_request_id": requestID.String()},
+		CreatedAt:      time.Now(),
+	}
+
+	return s.activityRepo.Create(activity)
+}
+
+// LogMemberJoinApproved บันทึกการอนุมัติคำขอเข้าร่วมกลุ่ม (source เก็บใน new_value)
+func (s *groupActivityService) LogMemberJoinApproved(conversationID, actorID, targetID uuid.UUID, source types.JSONB) error {
+	activity := &models.GroupActivity{
+		ID:             uuid.New(),
+		ConversationID: conversationID,
+		Type:           models.ActivityMemberJoinApproved,
+		ActorID:        actorID,
+		TargetID:       &targetID,
+		NewValue:       source,
+		CreatedAt:      time.Now(),
+	}
+
+	if err := s.activityRepo.Create(activity); err != nil {
+		return err
+	}
+
+	// Broadcast WebSocket event พร้อม user info
+	activityWithUsers, err := s.activityRepo.GetByID(activity.ID)
+	if err == nil && s.notificationService != nil {
+		activityDTO := buildActivityDTO(activityWithUsers)
+		s.notificationService.NotifyNewActivity(conversationID, activityDTO)
+	}
+
+	return nil
+}
+
+// LogMessageTTLChanged บันทึกการเปลี่ยนการตั้งค่าข้อความที่หายไปเอง
+func (s *groupActivityService) LogMessageTTLChanged(conversationID, actorID uuid.UUID, oldTTL, newTTL int) error {
+	activity := &models.GroupActivity{
+		ID:             uuid.New(),
+		ConversationID: conversationID,
+		Type:           models.ActivityMessageTTLChanged,
+		ActorID:        actorID,
+		OldValue:       types.JSONB{"message_ttl": oldTTL},
+		NewValue:       types.JSONB{"message_ttl": newTTL},
+		CreatedAt:      time.Now(),
+	}
+
+	if err := s.activityRepo.Create(activity); err != nil {
+		return err
+	}
+
+	// Broadcast WebSocket event พร้อม user info
+	activityWithUsers, err := s.activityRepo.GetByID(activity.ID)
+	if err == nil && s.notificationService != nil {
+		activityDTO := buildActivityDTO(activityWithUsers)
+		s.notificationService.NotifyNewActivity(conversationID, activityDTO)
+	}
+
+	return nil
+}
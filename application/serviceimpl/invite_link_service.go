@@ -0,0 +1,386 @@
+// application/serviceimpl/invite_link_service.go
+package serviceimpl
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+const (
+	inviteCodeBytes          = 16                   // รหัสลิงก์ 22 ตัวอักษร (base64url)
+	maxInviteLinkTTL         = 365 * 24 * time.Hour // อายุลิงก์สูงสุด
+	maxInviteLinkUses        = 100000
+	maxActiveLinksPerGroup   = 20
+	inviteLinkActivitySource = "invite_link"
+)
+
+type inviteLinkService struct {
+	inviteLinkRepo       repository.ConversationInviteLinkRepository
+	conversationRepo     repository.ConversationRepository
+	userRepo             repository.UserRepository
+	memberService        service.ConversationMemberService
+	notificationService  service.NotificationService
+	groupActivityService service.GroupActivityService
+	joinRequestService   service.JoinRequestService
+}
+
+// NewInviteLinkService สร้าง service ใหม่
+func NewInviteLinkService(
+	inviteLinkRepo repository.ConversationInviteLinkRepository,
+	conversationRepo repository.ConversationRepository,
+	userRepo repository.UserRepository,
+	memberService service.ConversationMemberService,
+	notificationService service.NotificationService,
+	groupActivityService service.GroupActivityService,
+	joinRequestService service.JoinRequestService,
+) service.InviteLinkService {
+	return &inviteLinkService{
+		inviteLinkRepo:       inviteLinkRepo,
+		conversationRepo:     conversationRepo,
+		userRepo:             userRepo,
+		memberService:        memberService,
+		notificationService:  notificationService,
+		groupActivityService: groupActivityService,
+		joinRequestService:   joinRequestService,
+	}
+}
+
+// CreateLink สร้างลิงก์เชิญเข้ากลุ่ม
+func (s *inviteLinkService) CreateLink(userID, conversationID uuid.UUID, req *dto.CreateInviteLinkRequest) (*dto.InviteLinkDTO, error) {
+	if req.ExpiresIn < 0 || time.Duration(req.ExpiresIn)*time.Second > maxInviteLinkTTL {
+		return nil, fmt.Errorf("expires_in must be between 0 and %d seconds", int(maxInviteLinkTTL.Seconds()))
+	}
+	if req.MaxUses < 0 || req.MaxUses > maxInviteLinkUses {
+		return nil, fmt.Errorf("max_uses must be between 0 and %d", maxInviteLinkUses)
+	}
+
+	if _, err := s.getGroupConversation(conversationID); err != nil {
+		return nil, err
+	}
+	if err := s.checkManagePermission(conversationID, userID); err != nil {
+		return nil, err
+	}
+
+	// จำกัดจำนวนลิงก์ที่ยังใช้งานได้ต่อกลุ่ม
+	existing, err := s.inviteLinkRepo.ListByConversationID(conversationID, false)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	active := 0
+	for _, link := range existing {
+		if link.IsActive(now) {
+			active++
+		}
+	}
+	if active >= maxActiveLinksPerGroup {
+		return nil, fmt.Errorf("too many active invite links (max %d)", maxActiveLinksPerGroup)
+	}
+
+	code, err := generateInviteCode()
+	if err != nil {
+		return nil, err
+	}
+
+	link := &models.ConversationInviteLink{
+		ID:               uuid.New(),
+		ConversationID:   conversationID,
+		CreatorID:        userID,
+		Code:             code,
+		MaxUses:          req.MaxUses,
+		RequiresApproval: req.RequiresApproval,
+		CreatedAt:        now,
+	}
+	if req.ExpiresIn > 0 {
+		expiresAt := now.Add(time.Duration(req.ExpiresIn) * time.Second)
+		link.ExpiresAt = &expiresAt
+	}
+
+	if err := s.inviteLinkRepo.Create(link); err != nil {
+		return nil, err
+	}
+
+	link.Creator, _ = s.userRepo.FindByID(userID)
+	return buildInviteLinkDTO(link, now), nil
+}
+
+// ListLinks ดึงลิงก์เชิญของกลุ่ม
+func (s *inviteLinkService) ListLinks(userID, conversationID uuid.UUID, includeRevoked bool) ([]*dto.InviteLinkDTO, error) {
+	if _, err := s.getGroupConversation(conversationID); err != nil {
+		return nil, err
+	}
+	if err := s.checkManagePermission(conversationID, userID); err != nil {
+		return nil, err
+	}
+
+	links, err := s.inviteLinkRepo.ListByConversationID(conversationID, includeRevoked)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := make([]*dto.InviteLinkDTO, 0, len(links))
+	for _, link := range links {
+		result = append(result, buildInviteLinkDTO(link, now))
+	}
+	return result, nil
+}
+
+// RevokeLink เพิกถอนลิงก์เชิญ (ลิงก์ที่ถูกเพิกถอนแล้วใช้เข้ากลุ่มไม่ได้อีก)
+func (s *inviteLinkService) RevokeLink(userID, conversationID, linkID uuid.UUID) error {
+	if err := s.checkManagePermission(conversationID, userID); err != nil {
+		return err
+	}
+
+	link, err := s.inviteLinkRepo.FindByID(linkID)
+	if err != nil {
+		return err
+	}
+	if link == nil || link.ConversationID != conversationID {
+		return errors.New("invite link not found")
+	}
+
+	revoked, err := s.inviteLinkRepo.Revoke(linkID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return errors.New("invite link has been revoked")
+	}
+	return nil
+}
+
+// PreviewLink ดึงข้อมูลกลุ่มจากรหัสลิงก์ (ชื่อ รูป และจำนวนสมาชิก)
+func (s *inviteLinkService) PreviewLink(userID uuid.UUID, code string) (*dto.InviteLinkPreviewDTO, error) {
+	link, err := s.getUsableLink(code, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	conversation, err := s.getGroupConversation(link.ConversationID)
+	if err != nil {
+		return nil, errors.New("invite link not found")
+	}
+
+	memberCount, err := s.conversationRepo.CountMembers(link.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	isMember, err := s.conversationRepo.IsMember(link.ConversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.InviteLinkPreviewDTO{
+		Code:             link.Code,
+		ConversationID:   conversation.ID,
+		Type:             conversation.Type,
+		Title:            conversation.Title,
+		IconURL:          conversation.IconURL,
+		MemberCount:      memberCount,
+		RequiresApproval: link.RequiresApproval,
+		ExpiresAt:        link.ExpiresAt,
+		IsMember:         isMember,
+	}, nil
+}
+
+// JoinViaLink เข้าร่วมกลุ่มผ่านลิงก์เชิญ (ลิงก์ที่ต้องอนุมัติจะสร้างคำขอเข้าร่วมแทน)
+func (s *inviteLinkService) JoinViaLink(userID uuid.UUID, code, message string) (*dto.JoinViaInviteLinkResponse, error) {
+	now := time.Now()
+
+	link, err := s.getUsableLink(code, now)
+	if err != nil {
+		return nil, err
+	}
+
+	conversation, err := s.getGroupConversation(link.ConversationID)
+	if err != nil {
+		return nil, errors.New("invite link not found")
+	}
+
+	isMember, err := s.conversationRepo.IsMember(conversation.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if isMember {
+		return nil, errors.New("you are already a member of this conversation")
+	}
+
+	if link.RequiresApproval {
+		return s.requestToJoin(userID, link, message, now)
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	// จองสิทธิ์การใช้ลิงก์ก่อนเพิ่มสมาชิก (ป้องกันการใช้เกิน max_uses เมื่อมีผู้ใช้พร้อมกัน)
+	claimed, err := s.inviteLinkRepo.ClaimUse(link.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, errors.New("invite link is no longer valid")
+	}
+
+	// ข้อความระบบแจ้งสมาชิกในกลุ่ม (ช่อง channel ไม่แจ้ง เพราะผู้ติดตามเข้าร่วมจำนวนมาก)
+	systemText := ""
+	if !conversation.IsChannel() {
+		systemText = displayNameOf(user) + " joined the group via invite link"
+	}
+
+	member, systemMessage, err := s.memberService.JoinMember(conversation.ID, userID, systemText)
+	if err != nil {
+		if releaseErr := s.inviteLinkRepo.ReleaseUse(link.ID); releaseErr != nil {
+			log.Printf("Error releasing invite link use %s: %v", link.ID, releaseErr)
+		}
+		if err.Error() == "user is already a member of this conversation" {
+			return nil, errors.New("you are already a member of this conversation")
+		}
+		return nil, err
+	}
+
+	s.notificationService.NotifyUserAddedToConversation(conversation.ID, userID)
+	if systemMessage != nil {
+		s.notificationService.NotifyNewMessage(conversation.ID, systemMessage)
+	}
+
+	// ผู้สร้างลิงก์เป็น actor และบันทึกลิงก์ที่ใช้เป็น source
+	source := types.JSONB{
+		"source":         inviteLinkActivitySource,
+		"invite_link_id": link.ID.String(),
+	}
+	if err := s.groupActivityService.LogMemberAdded(conversation.ID, link.CreatorID, userID, source); err != nil {
+		log.Printf("Error logging invite link join activity: %v", err)
+	}
+
+	return &dto.JoinViaInviteLinkResponse{
+		ConversationID: conversation.ID,
+		Status:         dto.InviteJoinStatusJoined,
+		Member:         member,
+	}, nil
+}
+
+// requestToJoin สร้างคำขอเข้าร่วมผ่านลิงก์ที่ต้องให้แอดมินอนุมัติ (นับเป็นการใช้ลิงก์หนึ่งครั้ง)
+func (s *inviteLinkService) requestToJoin(userID uuid.UUID, link *models.ConversationInviteLink, message string, now time.Time) (*dto.JoinViaInviteLinkResponse, error) {
+	claimed, err := s.inviteLinkRepo.ClaimUse(link.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, errors.New("invite link is no longer valid")
+	}
+
+	request, err := s.joinRequestService.SubmitRequest(userID, link.ConversationID, message, &link.ID)
+	if err != nil {
+		if releaseErr := s.inviteLinkRepo.ReleaseUse(link.ID); releaseErr != nil {
+			log.Printf("Error releasing invite link use %s: %v", link.ID, releaseErr)
+		}
+		return nil, err
+	}
+
+	return &dto.JoinViaInviteLinkResponse{
+		ConversationID: link.ConversationID,
+		Status:         dto.InviteJoinStatusPending,
+		JoinRequest:    request,
+	}, nil
+}
+
+// getUsableLink ดึงลิงก์จากรหัสและตรวจสอบว่ายังใช้งานได้
+func (s *inviteLinkService) getUsableLink(code string, now time.Time) (*models.ConversationInviteLink, error) {
+	if code == "" {
+		return nil, errors.New("invite link not found")
+	}
+
+	link, err := s.inviteLinkRepo.FindByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return nil, errors.New("invite link not found")
+	}
+
+	switch {
+	case link.RevokedAt != nil:
+		return nil, errors.New("invite link has been revoked")
+	case link.IsExpired(now):
+		return nil, errors.New("invite link has expired")
+	case link.IsExhausted():
+		return nil, errors.New("invite link has reached its usage limit")
+	}
+
+	return link, nil
+}
+
+// getGroupConversation ดึงการสนทนาและตรวจสอบว่าเป็นกลุ่มหรือช่องที่ยังใช้งานอยู่
+func (s *inviteLinkService) getGroupConversation(conversationID uuid.UUID) (*models.Conversation, error) {
+	conversation, err := s.conversationRepo.GetByID(conversationID)
+	if err != nil || conversation == nil || !conversation.IsActive {
+		return nil, errors.New("conversation not found")
+	}
+	if conversation.Type != "group" && !conversation.IsChannel() {
+		return nil, errors.New("invite links are only available for groups and channels")
+	}
+	return conversation, nil
+}
+
+// checkManagePermission ตรวจสอบสิทธิ์จัดการลิงก์เชิญ
+func (s *inviteLinkService) checkManagePermission(conversationID, userID uuid.UUID) error {
+	allowed, err := s.memberService.HasPermission(conversationID, userID, service.PermissionManageInviteLinks)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errors.New("you don't have permission to manage invite links")
+	}
+	return nil
+}
+
+// buildInviteLinkDTO แปลง model เป็น DTO
+func buildInviteLinkDTO(link *models.ConversationInviteLink, now time.Time) *dto.InviteLinkDTO {
+	result := &dto.InviteLinkDTO{
+		ID:               link.ID,
+		ConversationID:   link.ConversationID,
+		Code:             link.Code,
+		ExpiresAt:        link.ExpiresAt,
+		MaxUses:          link.MaxUses,
+		UseCount:         link.UseCount,
+		RequiresApproval: link.RequiresApproval,
+		IsActive:         link.IsActive(now),
+		RevokedAt:        link.RevokedAt,
+		CreatedAt:        link.CreatedAt,
+	}
+
+	if link.Creator != nil {
+		result.Creator = &dto.UserInfoDTO{
+			ID:              link.Creator.ID.String(),
+			Username:        link.Creator.Username,
+			DisplayName:     link.Creator.DisplayName,
+			ProfileImageURL: link.Creator.ProfileImageURL,
+		}
+	}
+
+	return result
+}
+
+// generateInviteCode สร้างรหัสลิงก์แบบสุ่ม (URL-safe)
+func generateInviteCode() (string, error) {
+	buf := make([]byte, inviteCodeBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate invite code: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
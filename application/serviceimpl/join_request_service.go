@@ -0,0 +1,288 @@
+// application/serviceimpl/join_request_service.go
+package serviceimpl
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+const maxJoinRequestMessageLength = 500
+
+type joinRequestService struct {
+	joinRequestRepo      repository.ConversationJoinRequestRepository
+	conversationRepo     repository.ConversationRepository
+	userRepo             repository.UserRepository
+	memberService        service.ConversationMemberService
+	notificationService  service.NotificationService
+	groupActivityService service.GroupActivityService
+}
+
+// NewJoinRequestService สร้าง service ใหม่
+func NewJoinRequestService(
+	joinRequestRepo repository.ConversationJoinRequestRepository,
+	conversationRepo repository.ConversationRepository,
+	userRepo repository.UserRepository,
+	memberService service.ConversationMemberService,
+	notificationService service.NotificationService,
+	groupActivityService service.GroupActivityService,
+) service.JoinRequestService {
+	return &joinRequestService{
+		joinRequestRepo:      joinRequestRepo,
+		conversationRepo:     conversationRepo,
+		userRepo:             userRepo,
+		memberService:        memberService,
+		notificationService:  notificationService,
+		groupActivityService: groupActivityService,
+	}
+}
+
+// SubmitRequest ส่งคำขอเข้าร่วมกลุ่มและแจ้งแอดมินของกลุ่ม
+func (s *joinRequestService) SubmitRequest(userID, conversationID uuid.UUID, message string, inviteLinkID *uuid.UUID) (*dto.JoinRequestDTO, error) {
+	message = strings.TrimSpace(message)
+	if len([]rune(message)) > maxJoinRequestMessageLength {
+		return nil, fmt.Errorf("join request message is too long (max %d characters)", maxJoinRequestMessageLength)
+	}
+
+	conversation, err := s.conversationRepo.GetByID(conversationID)
+	if err != nil || conversation == nil || !conversation.IsActive {
+		return nil, errors.New("conversation not found")
+	}
+	if conversation.Type != "group" && !conversation.IsChannel() {
+		return nil, errors.New("join requests are only available for groups and channels")
+	}
+
+	isMember, err := s.conversationRepo.IsMember(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if isMember {
+		return nil, errors.New("you are already a member of this conversation")
+	}
+
+	existing, err := s.joinRequestRepo.FindPending(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("you already have a pending join request")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	request := &models.ConversationJoinRequest{
+		ID:             uuid.New(),
+		ConversationID: conversationID,
+		UserID:         userID,
+		Message:        message,
+		Status:         models.JoinRequestStatusPending,
+		InviteLinkID:   inviteLinkID,
+	}
+	if err := s.joinRequestRepo.Create(request); err != nil {
+		return nil, err
+	}
+	request.User = user
+
+	if err := s.groupActivityService.LogMemberJoinRequested(conversationID, userID, request.ID); err != nil {
+		log.Printf("Error logging join request activity: %v", err)
+	}
+
+	result := buildJoinRequestDTO(request, conversation)
+	s.notificationService.NotifyJoinRequestReceived(s.getAdminIDs(conversationID), result)
+
+	return result, nil
+}
+
+// ListRequests ดึงคำขอเข้าร่วมกลุ่ม
+func (s *joinRequestService) ListRequests(userID, conversationID uuid.UUID, status string, limit, offset int) ([]*dto.JoinRequestDTO, int64, error) {
+	switch status {
+	case "", models.JoinRequestStatusPending, models.JoinRequestStatusApproved, models.JoinRequestStatusRejected:
+	default:
+		return nil, 0, errors.New("invalid join request status")
+	}
+
+	if err := s.checkReviewPermission(conversationID, userID); err != nil {
+		return nil, 0, err
+	}
+
+	requests, total, err := s.joinRequestRepo.ListByConversationID(conversationID, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]*dto.JoinRequestDTO, 0, len(requests))
+	for _, request := range requests {
+		result = append(result, buildJoinRequestDTO(request, nil))
+	}
+	return result, total, nil
+}
+
+// ApproveRequest อนุมัติคำขอ เพิ่มผู้ขอผ่าน ConversationMemberService.AddMember แล้วแจ้งผู้ขอ
+func (s *joinRequestService) ApproveRequest(userID, conversationID, requestID uuid.UUID) (*dto.JoinRequestDTO, error) {
+	request, err := s.getPendingRequest(userID, conversationID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	updated, err := s.joinRequestRepo.UpdateStatus(requestID, models.JoinRequestStatusPending, models.JoinRequestStatusApproved, &userID, &now)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.New("join request has already been reviewed")
+	}
+
+	added := true
+	if _, err := s.memberService.AddMember(userID, conversationID, request.UserID); err != nil {
+		if err.Error() != "user is already a member of this conversation" {
+			// คืนสถานะเป็น pending เพื่อให้แอดมินลองใหม่ได้
+			if _, revertErr := s.joinRequestRepo.UpdateStatus(requestID, models.JoinRequestStatusApproved, models.JoinRequestStatusPending, nil, nil); revertErr != nil {
+				log.Printf("Error reverting join request %s: %v", requestID, revertErr)
+			}
+			return nil, err
+		}
+		added = false
+	}
+
+	request.Status = models.JoinRequestStatusApproved
+	request.ReviewedBy = &userID
+	request.ReviewedAt = &now
+
+	if added {
+		s.notificationService.NotifyUserAddedToConversation(conversationID, request.UserID)
+
+		source := types.JSONB{
+			"source":          "join_request",
+			"join_request_id": request.ID.String(),
+		}
+		if request.InviteLinkID != nil {
+			source["invite_link_id"] = request.InviteLinkID.String()
+		}
+		if err := s.groupActivityService.LogMemberJoinApproved(conversationID, userID, request.UserID, source); err != nil {
+			log.Printf("Error logging join approval activity: %v", err)
+		}
+	}
+
+	conversation, _ := s.conversationRepo.GetByID(conversationID)
+	result := buildJoinRequestDTO(request, conversation)
+	s.notificationService.NotifyJoinRequestApproved(request.UserID, result)
+
+	return result, nil
+}
+
+// RejectRequest ปฏิเสธคำขอและแจ้งผู้ขอ
+func (s *joinRequestService) RejectRequest(userID, conversationID, requestID uuid.UUID) (*dto.JoinRequestDTO, error) {
+	request, err := s.getPendingRequest(userID, conversationID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	updated, err := s.joinRequestRepo.UpdateStatus(requestID, models.JoinRequestStatusPending, models.JoinRequestStatusRejected, &userID, &now)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.New("join request has already been reviewed")
+	}
+
+	request.Status = models.JoinRequestStatusRejected
+	request.ReviewedBy = &userID
+	request.ReviewedAt = &now
+
+	conversation, _ := s.conversationRepo.GetByID(conversationID)
+	result := buildJoinRequestDTO(request, conversation)
+	s.notificationService.NotifyJoinRequestRejected(request.UserID, result)
+
+	return result, nil
+}
+
+// getPendingRequest ตรวจสอบสิทธิ์และดึงคำขอที่ยังรออนุมัติ
+func (s *joinRequestService) getPendingRequest(userID, conversationID, requestID uuid.UUID) (*models.ConversationJoinRequest, error) {
+	if err := s.checkReviewPermission(conversationID, userID); err != nil {
+		return nil, err
+	}
+
+	request, err := s.joinRequestRepo.FindByID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil || request.ConversationID != conversationID {
+		return nil, errors.New("join request not found")
+	}
+	if request.Status != models.JoinRequestStatusPending {
+		return nil, errors.New("join request has already been reviewed")
+	}
+	return request, nil
+}
+
+// checkReviewPermission ผู้ที่เพิ่มสมาชิกได้ (owner/admin) เท่านั้นที่พิจารณาคำขอได้
+func (s *joinRequestService) checkReviewPermission(conversationID, userID uuid.UUID) error {
+	allowed, err := s.memberService.HasPermission(conversationID, userID, service.PermissionAddMember)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errors.New("you don't have permission to review join requests")
+	}
+	return nil
+}
+
+// getAdminIDs ดึง ID ของ owner/admin ของกลุ่ม
+func (s *joinRequestService) getAdminIDs(conversationID uuid.UUID) []uuid.UUID {
+	members, err := s.conversationRepo.GetMembers(conversationID)
+	if err != nil {
+		log.Printf("Error fetching members of conversation %s: %v", conversationID, err)
+		return nil
+	}
+
+	adminIDs := make([]uuid.UUID, 0)
+	for _, member := range members {
+		if member.Role == models.RoleOwner || member.Role == models.RoleAdmin {
+			adminIDs = append(adminIDs, member.UserID)
+		}
+	}
+	return adminIDs
+}
+
+// buildJoinRequestDTO แปลง model เป็น DTO (conversation = nil จะไม่ใส่ชื่อกลุ่ม)
+func buildJoinRequestDTO(request *models.ConversationJoinRequest, conversation *models.Conversation) *dto.JoinRequestDTO {
+	result := &dto.JoinRequestDTO{
+		ID:             request.ID,
+		ConversationID: request.ConversationID,
+		Message:        request.Message,
+		Status:         request.Status,
+		InviteLinkID:   request.InviteLinkID,
+		ReviewedBy:     request.ReviewedBy,
+		ReviewedAt:     request.ReviewedAt,
+		CreatedAt:      request.CreatedAt,
+	}
+
+	if conversation != nil {
+		result.ConversationTitle = conversation.Title
+	}
+
+	if request.User != nil {
+		result.User = &dto.UserInfoDTO{
+			ID:              request.User.ID.String(),
+			Username:        request.User.Username,
+			DisplayName:     request.User.DisplayName,
+			ProfileImageURL: request.User.ProfileImageURL,
+		}
+	}
+
+	return result
+}
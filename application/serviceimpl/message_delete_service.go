@@ -45,13 +45,46 @@ func (s *messageService) DeleteMessage(messageID, userID uuid.UUID) error {
 		}
 	}
 
+	return s.deleteMessage(message, userID, nil)
+}
+
+// ModerateDeleteMessage ลบข้อความโดยผู้ดูแลแพลตฟอร์ม (ไม่ต้องเป็นเจ้าของหรือแอดมินของการสนทนา)
+// บันทึกประวัติการลบพร้อมเหตุผลเหมือนการลบปกติ
+func (s *messageService) ModerateDeleteMessage(messageID, moderatorID uuid.UUID, reason string) error {
+	message, err := s.messageRepo.GetByID(messageID)
+	if err != nil {
+		return fmt.Errorf("error fetching message: %w", err)
+	}
+
+	if message == nil {
+		return fmt.Errorf("message not found")
+	}
+
+	if message.IsDeleted {
+		return fmt.Errorf("message is already deleted")
+	}
+
+	return s.deleteMessage(message, moderatorID, map[string]interface{}{
+		"moderation":        true,
+		"moderation_reason": reason,
+	})
+}
+
+// deleteMessage บันทึกประวัติการลบและ soft delete ข้อความ (ตรวจสอบสิทธิ์แล้ว)
+// extraMetadata เพิ่มลงใน metadata ของประวัติการลบ
+func (s *messageService) deleteMessage(message *models.Message, deletedBy uuid.UUID, extraMetadata map[string]interface{}) error {
+	messageID := message.ID
+
 	// สร้าง metadata สำหรับประวัติการลบ
 	now := time.Now()
 	metadataObj := map[string]interface{}{
-		"deleted_by_id": userID,
+		"deleted_by_id": deletedBy,
 		"deleted_at":    now.Format(time.RFC3339),
 		"message_type":  message.MessageType,
 	}
+	for k, v := range extraMetadata {
+		metadataObj[k] = v
+	}
 
 	// บันทึกประวัติการลบ
 	deleteHistory := &models.MessageDeleteHistory{
@@ -62,7 +95,7 @@ func (s *messageService) DeleteMessage(messageID, userID uuid.UUID) error {
 		MediaThumbnailURL: message.MediaThumbnailURL,
 		Metadata:          s.convertMetadataToJSON(metadataObj),
 		DeletedAt:         now,
-		DeletedBy:         userID,
+		DeletedBy:         deletedBy,
 	}
 
 	if err := s.messageRepo.CreateDeleteHistory(deleteHistory); err != nil {
@@ -82,46 +115,12 @@ func (s *messageService) DeleteMessage(messageID, userID uuid.UUID) error {
 	}
 
 	// ตรวจสอบว่าเป็นข้อความล่าสุดของการสนทนาหรือไม่ และอัพเดทหากจำเป็น
-	lastMessage, err := s.messageRepo.GetLastMessageByConversation(message.ConversationID)
-	if err == nil && lastMessage != nil && lastMessage.ID == message.ID {
-		// ดึงข้อความล่าสุดที่ไม่ถูกลบ
-		newLastMessage, err := s.messageRepo.GetLastNonDeletedMessageByConversation(message.ConversationID)
-		if err == nil && newLastMessage != nil {
-			// มีข้อความล่าสุดใหม่
-			lastMessageText := ""
-			switch newLastMessage.MessageType {
-			case "text":
-				lastMessageText = newLastMessage.Content
-			case "sticker":
-				lastMessageText = "[Sticker]"
-			case "image":
-				lastMessageText = "[Image]"
-				if newLastMessage.Content != "" {
-					lastMessageText = "[Image] " + newLastMessage.Content
-				}
-			case "file":
-				lastMessageText = "[File]"
-				if newLastMessage.Content != "" {
-					lastMessageText = "[File] " + newLastMessage.Content
-				}
-			default:
-				lastMessageText = "[Message]"
-			}
+	s.refreshConversationLastMessage(message.ConversationID, message.ID)
 
-			if err := s.messageRepo.UpdateConversationLastMessage(message.ConversationID, lastMessageText, newLastMessage.CreatedAt, newLastMessage.ID); err != nil {
-				fmt.Printf("Error updating conversation last message: %v\n", err)
-			} else {
-				// ส่ง WebSocket event แจ้งการอัปเดต conversation พร้อม mention data
-				s.notifyConversationUpdated(message.ConversationID, lastMessageText, newLastMessage.CreatedAt, newLastMessage.ID)
-			}
-		} else {
-			// ไม่มีข้อความเหลือแล้ว - ใช้ zero UUID สำหรับกรณีไม่มีข้อความ
-			if err := s.messageRepo.UpdateConversationLastMessage(message.ConversationID, "[No messages]", now, uuid.Nil); err != nil {
-				fmt.Printf("Error updating conversation last message: %v\n", err)
-			} else {
-				// ส่ง WebSocket event แจ้งการอัปเดต conversation พร้อม mention data
-				s.notifyConversationUpdated(message.ConversationID, "[No messages]", now, uuid.Nil)
-			}
+	// ถ้าเป็นข้อความในเธรด ให้คำนวณจำนวนข้อความของเธรดใหม่
+	if message.ThreadRootID != nil {
+		if err := s.messageRepo.RefreshThreadStats(*message.ThreadRootID); err != nil {
+			fmt.Printf("Error refreshing thread stats: %v\n", err)
 		}
 	}
 
@@ -189,3 +188,52 @@ func (s *messageService) GetMessageDeleteHistory(messageID, userID uuid.UUID) ([
 
 	return history, nil
 }
+
+// refreshConversationLastMessage อัปเดตข้อความล่าสุดของการสนทนาเมื่อข้อความล่าสุดถูกลบ
+func (s *messageService) refreshConversationLastMessage(conversationID, deletedMessageID uuid.UUID) {
+	now := time.Now()
+	lastMessage, err := s.messageRepo.GetLastMessageByConversation(conversationID)
+	if err == nil && lastMessage != nil && lastMessage.ID == deletedMessageID {
+		// ดึงข้อความล่าสุดที่ไม่ถูกลบ
+		newLastMessage, err := s.messageRepo.GetLastNonDeletedMessageByConversation(conversationID)
+		if err == nil && newLastMessage != nil {
+			// มีข้อความล่าสุดใหม่
+			lastMessageText := ""
+			switch newLastMessage.MessageType {
+			case "text":
+				lastMessageText = newLastMessage.Content
+			case "sticker":
+				lastMessageText = "[Sticker]"
+			case "image":
+				lastMessageText = "[Image]"
+				if newLastMessage.Content != "" {
+					lastMessageText = "[Image] " + newLastMessage.Content
+				}
+			case "file":
+				lastMessageText = "[File]"
+				if newLastMessage.Content != "" {
+					lastMessageText = "[File] " + newLastMessage.Content
+				}
+			case "poll":
+				lastMessageText = "[Poll] " + newLastMessage.Content
+			default:
+				lastMessageText = "[Message]"
+			}
+
+			if err := s.messageRepo.UpdateConversationLastMessage(conversationID, lastMessageText, newLastMessage.CreatedAt, newLastMessage.ID); err != nil {
+				fmt.Printf("Error updating conversation last message: %v\n", err)
+			} else {
+				// ส่ง WebSocket event แจ้งการอัปเดต conversation พร้อม mention data
+				s.notifyConversationUpdated(conversationID, lastMessageText, newLastMessage.CreatedAt, newLastMessage.ID)
+			}
+		} else {
+			// ไม่มีข้อความเหลือแล้ว - ใช้ zero UUID สำหรับกรณีไม่มีข้อความ
+			if err := s.messageRepo.UpdateConversationLastMessage(conversationID, "[No messages]", now, uuid.Nil); err != nil {
+				fmt.Printf("Error updating conversation last message: %v\n", err)
+			} else {
+				// ส่ง WebSocket event แจ้งการอัปเดต conversation พร้อม mention data
+				s.notifyConversationUpdated(conversationID, "[No messages]", now, uuid.Nil)
+			}
+		}
+	}
+}
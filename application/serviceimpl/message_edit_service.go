@@ -56,6 +56,13 @@ func (s *messageService) EditMessage(messageID, userID uuid.UUID, newContent str
 		return message, nil
 	}
 
+	// รัน content filter กับเนื้อหาใหม่ก่อนบันทึก
+	filtered, err := s.filterContent(message.ConversationID, &userID, newContent, true)
+	if err != nil {
+		return nil, err
+	}
+	newContent = filtered.Content
+
 	// เก็บประวัติการแก้ไข
 	editHistory := &models.MessageEditHistory{
 		ID:              uuid.New(),
@@ -138,6 +145,9 @@ func (s *messageService) EditMessage(messageID, userID uuid.UUID, newContent str
 		}
 	}
 
+	// ส่งข้อความที่ถูก flag เข้าคิวตรวจสอบ
+	s.reportContentFlags(message, filtered)
+
 	return message, nil
 }
 
@@ -0,0 +1,167 @@
+// application/serviceimpl/message_encrypted_service.go
+package serviceimpl
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// ข้อจำกัดของข้อความเข้ารหัส
+const (
+	encryptedEnvelopeVersion   = 1
+	maxEncryptedCiphertexts    = 50        // จำนวนอุปกรณ์ผู้รับสูงสุดต่อข้อความ
+	maxEncryptedCiphertextSize = 64 * 1024 // ขนาด ciphertext สูงสุดต่ออุปกรณ์ (bytes)
+
+	// encryptedLastMessageText ข้อความแสดงแทนใน LastMessageText และ push (server ไม่รู้เนื้อหา)
+	encryptedLastMessageText = "[Encrypted message]"
+)
+
+// SendEncryptedMessage ส่งข้อความเข้ารหัสแบบ end-to-end
+// Content เก็บเป็น envelope ของ ciphertext แยกตามอุปกรณ์ผู้รับ server ตรวจสอบเฉพาะ routing (ผู้รับ/อุปกรณ์) ไม่แตะ ciphertext
+func (s *messageService) SendEncryptedMessage(conversationID, userID uuid.UUID, senderDeviceID string, ciphertexts []dto.EncryptedPayloadDTO, metadata map[string]interface{}) (*models.Message, error) {
+	// ตรวจสอบว่าผู้ใช้เป็นสมาชิกของการสนทนา
+	isMember, err := s.conversationRepo.IsMember(conversationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error checking conversation membership: %w", err)
+	}
+
+	if !isMember {
+		return nil, fmt.Errorf("user is not a member of this conversation")
+	}
+
+	conversation, err := s.conversationRepo.GetByID(conversationID)
+	if err != nil || conversation == nil {
+		return nil, fmt.Errorf("conversation not found")
+	}
+	if conversation.Type != "direct" {
+		return nil, fmt.Errorf("encrypted messages are only supported in direct conversations")
+	}
+
+	senderDeviceID, err = normalizeE2EEDeviceID(senderDeviceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender device id")
+	}
+	senderBundle, err := s.e2eeKeyRepo.GetBundle(userID, senderDeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if senderBundle == nil {
+		return nil, fmt.Errorf("sender device is not registered for encryption")
+	}
+
+	if err := s.validateCiphertexts(conversationID, ciphertexts); err != nil {
+		return nil, err
+	}
+
+	content, err := json.Marshal(dto.EncryptedEnvelope{
+		Version:        encryptedEnvelopeVersion,
+		SenderDeviceID: senderDeviceID,
+		Ciphertexts:    ciphertexts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding encrypted envelope: %w", err)
+	}
+
+	// metadata ไม่ถูกเข้ารหัส - ไม่ extract links/mentions จากข้อความเข้ารหัส
+	if metadata != nil {
+		delete(metadata, "links")
+		delete(metadata, "mentions")
+	}
+
+	// สร้าง message
+	now := time.Now()
+	message := &models.Message{
+		ID:             uuid.New(),
+		ConversationID: conversationID,
+		SenderID:       &userID,
+		SenderType:     "user",
+		MessageType:    models.MessageTypeEncrypted,
+		Content:        string(content),
+		Metadata:       s.convertMetadataToJSON(metadata),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		ExpiresAt:      conversation.MessageExpiresAt(now),
+		IsDeleted:      false,
+	}
+
+	if err := s.messageRepo.Create(message); err != nil {
+		return nil, fmt.Errorf("error creating message: %w", err)
+	}
+
+	// สร้างบันทึกการอ่านสำหรับผู้ส่ง
+	if err := s.createMessageRead(message.ID, userID); err != nil {
+		fmt.Printf("Error creating read record: %v, messageID: %s, userID: %s", err, message.ID.String(), userID)
+	}
+
+	// อัปเดต last_read_at สำหรับผู้ส่ง
+	if err := s.conversationRepo.UpdateMemberLastRead(conversationID, userID, now); err != nil {
+		fmt.Printf("Error updating last read time: %v, conversationID: %s, userID: %s", err, conversationID, userID)
+	}
+
+	// อัปเดตข้อความล่าสุดของการสนทนา (ใช้ข้อความแสดงแทน ไม่เก็บ ciphertext)
+	if err := s.messageRepo.UpdateConversationLastMessage(conversationID, encryptedLastMessageText, now, message.ID); err != nil {
+		fmt.Printf("Error updating conversation last message: %v, conversationID: %s", err, conversationID)
+	}
+
+	s.notifyConversationUpdated(conversationID, encryptedLastMessageText, now, message.ID)
+
+	return message, nil
+}
+
+// validateCiphertexts ตรวจสอบว่าผู้รับทุกคนเป็นสมาชิก อุปกรณ์ลงทะเบียน key แล้ว และ ciphertext เป็น base64
+func (s *messageService) validateCiphertexts(conversationID uuid.UUID, ciphertexts []dto.EncryptedPayloadDTO) error {
+	if len(ciphertexts) == 0 {
+		return fmt.Errorf("ciphertexts are required")
+	}
+	if len(ciphertexts) > maxEncryptedCiphertexts {
+		return fmt.Errorf("too many ciphertexts (max %d)", maxEncryptedCiphertexts)
+	}
+
+	memberIDs, err := s.conversationRepo.GetMemberUserIDs(conversationID)
+	if err != nil {
+		return err
+	}
+	members := make(map[uuid.UUID]bool, len(memberIDs))
+	for _, memberID := range memberIDs {
+		members[memberID] = true
+	}
+
+	seen := make(map[string]bool, len(ciphertexts))
+	for _, payload := range ciphertexts {
+		if !members[payload.UserID] {
+			return fmt.Errorf("ciphertext recipient is not a member of this conversation")
+		}
+		if payload.Type != "prekey" && payload.Type != "message" {
+			return fmt.Errorf("invalid ciphertext type")
+		}
+
+		key := payload.UserID.String() + "/" + payload.DeviceID
+		if seen[key] {
+			return fmt.Errorf("duplicate ciphertext for device")
+		}
+		seen[key] = true
+
+		if payload.Body == "" || len(payload.Body) > base64.StdEncoding.EncodedLen(maxEncryptedCiphertextSize) {
+			return fmt.Errorf("invalid ciphertext body")
+		}
+		if _, err := base64.StdEncoding.DecodeString(payload.Body); err != nil {
+			return fmt.Errorf("invalid ciphertext body")
+		}
+
+		bundle, err := s.e2eeKeyRepo.GetBundle(payload.UserID, payload.DeviceID)
+		if err != nil {
+			return err
+		}
+		if bundle == nil {
+			return fmt.Errorf("recipient device is not registered for encryption")
+		}
+	}
+
+	return nil
+}
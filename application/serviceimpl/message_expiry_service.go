@@ -0,0 +1,35 @@
+// application/serviceimpl/message_expiry_service.go
+package serviceimpl
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// PurgeExpiredMessages ลบข้อความที่หมดอายุ (soft delete เหมือน DeleteMessage แต่ไม่บันทึกประวัติการลบ)
+// repository ลบและจองข้อความในขั้นตอนเดียว ทุก instance จึงเรียกได้พร้อมกันโดยไม่ส่ง event ซ้ำ
+// คืนค่าสำเนาของข้อความก่อนถูกลบ เพื่อให้ผู้เรียกลบไฟล์ media ต่อได้
+func (s *messageService) PurgeExpiredMessages(before time.Time, limit int) ([]*models.Message, error) {
+	messages, err := s.messageRepo.ClaimExpiredMessages(before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error purging expired messages: %w", err)
+	}
+
+	for _, message := range messages {
+		s.refreshConversationLastMessage(message.ConversationID, message.ID)
+
+		if message.ThreadRootID != nil {
+			if err := s.messageRepo.RefreshThreadStats(*message.ThreadRootID); err != nil {
+				fmt.Printf("Error refreshing thread stats: %v\n", err)
+			}
+		}
+
+		if s.notificationService != nil {
+			s.notificationService.NotifyMessageDeleted(message.ConversationID, message.ID)
+		}
+	}
+
+	return messages, nil
+}
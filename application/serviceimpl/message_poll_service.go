@@ -0,0 +1,417 @@
+// application/serviceimpl/message_poll_service.go
+package serviceimpl
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// ข้อจำกัดของโพล (ตรงกับ PollMessageRequest และ varchar(200) ใน poll_options)
+const (
+	minPollOptions        = 2
+	maxPollOptions        = 12
+	maxPollQuestionLength = 500
+	maxPollOptionLength   = 200
+	pollLastMessagePrefix = "[Poll] "
+)
+
+// Poll actions ที่ส่งไปกับ event poll.updated
+const (
+	pollActionVoted     = "voted"
+	pollActionRetracted = "retracted"
+	pollActionClosed    = "closed"
+)
+
+// SendPollMessage ส่งข้อความประเภทโพล
+func (s *messageService) SendPollMessage(conversationID, userID uuid.UUID, question string, options []string, allowMultiple, isAnonymous bool, closesAt *time.Time, metadata map[string]interface{}) (*models.Message, error) {
+	// ตรวจสอบว่าผู้ใช้เป็นสมาชิกของการสนทนา
+	isMember, err := s.conversationRepo.IsMember(conversationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error checking conversation membership: %w", err)
+	}
+
+	if !isMember {
+		return nil, fmt.Errorf("user is not a member of this conversation")
+	}
+
+	// ช่อง (channel) โพสต์ได้เฉพาะ owner/admin
+	conversation, err := checkPostPermission(s.conversationRepo, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	question, options, err = validatePollPayload(question, options)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if closesAt != nil && !closesAt.After(now) {
+		return nil, fmt.Errorf("poll close time must be in the future")
+	}
+
+	// เก็บ poll_id ไว้ใน metadata ของข้อความเพื่อให้ client อ้างอิงได้
+	pollID := uuid.New()
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	metadata["poll_id"] = pollID.String()
+
+	// สร้าง message
+	message := &models.Message{
+		ID:             uuid.New(),
+		ConversationID: conversationID,
+		SenderID:       &userID,
+		SenderType:     "user",
+		MessageType:    "poll",
+		Content:        question,
+		Metadata:       s.convertMetadataToJSON(metadata),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		ExpiresAt:      conversation.MessageExpiresAt(now),
+		IsDeleted:      false,
+	}
+
+	if err := s.messageRepo.Create(message); err != nil {
+		return nil, fmt.Errorf("error creating message: %w", err)
+	}
+
+	// สร้างโพลพร้อมตัวเลือก
+	poll := &models.Poll{
+		ID:             pollID,
+		MessageID:      message.ID,
+		ConversationID: conversationID,
+		CreatorID:      userID,
+		Question:       question,
+		AllowMultiple:  allowMultiple,
+		IsAnonymous:    isAnonymous,
+		ClosesAt:       closesAt,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	for i, text := range options {
+		poll.Options = append(poll.Options, &models.PollOption{
+			ID:       uuid.New(),
+			Text:     text,
+			Position: i,
+		})
+	}
+
+	if err := s.pollRepo.Create(poll); err != nil {
+		// ไม่ให้เหลือข้อความโพลที่ไม่มีข้อมูลโพล
+		if delErr := s.messageRepo.Delete(message.ID); delErr != nil {
+			fmt.Printf("Error cleaning up poll message: %v, messageID: %s\n", delErr, message.ID)
+		}
+		return nil, fmt.Errorf("error creating poll: %w", err)
+	}
+	message.Poll = poll
+
+	// สร้างบันทึกการอ่านสำหรับผู้ส่ง
+	if err := s.createMessageRead(message.ID, userID); err != nil {
+		fmt.Printf("Error creating read record: %v, messageID: %s, userID: %s", err, message.ID.String(), userID)
+	}
+
+	// อัปเดต last_read_at สำหรับผู้ส่ง
+	if err := s.conversationRepo.UpdateMemberLastRead(conversationID, userID, now); err != nil {
+		fmt.Printf("Error updating last read time: %v, conversationID: %s, userID: %s", err, conversationID, userID)
+	}
+
+	// อัปเดตข้อความล่าสุดของการสนทนา
+	lastMessageText := pollLastMessagePrefix + question
+	if err := s.messageRepo.UpdateConversationLastMessage(conversationID, lastMessageText, now, message.ID); err != nil {
+		fmt.Printf("Error updating conversation last message: %v, conversationID: %s", err, conversationID)
+	}
+
+	s.notifyConversationUpdated(conversationID, lastMessageText, now, message.ID)
+
+	return message, nil
+}
+
+// VotePoll โหวตตัวเลือกในโพล
+// โพลแบบเลือกได้ข้อเดียวจะแทนที่การโหวตเดิม ส่วนโพลแบบหลายข้อจะเพิ่มตัวเลือกเข้าไป
+func (s *messageService) VotePoll(conversationID, messageID, userID uuid.UUID, optionIDs []uuid.UUID) (*dto.PollDTO, error) {
+	poll, err := s.getOpenPoll(conversationID, messageID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	optionIDs = uniquePollOptionIDs(optionIDs)
+	if len(optionIDs) == 0 {
+		return nil, fmt.Errorf("at least one option is required")
+	}
+	if !poll.AllowMultiple && len(optionIDs) > 1 {
+		return nil, fmt.Errorf("this poll allows only one option")
+	}
+	if err := validatePollOptionIDs(poll, optionIDs); err != nil {
+		return nil, err
+	}
+
+	// รวมกับการโหวตเดิมและตรวจว่าโพลยังเปิดอยู่ภายใน transaction เดียวกับการบันทึก
+	opened, err := s.pollRepo.CastVotes(poll.ID, userID, optionIDs, !poll.AllowMultiple, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("error saving poll vote: %w", err)
+	}
+	if !opened {
+		return nil, fmt.Errorf("poll is closed")
+	}
+
+	return s.pollResultsAndNotify(poll, userID, pollActionVoted)
+}
+
+// RetractPollVote ถอนการโหวต (optionIDs ว่าง = ถอนทั้งหมด)
+func (s *messageService) RetractPollVote(conversationID, messageID, userID uuid.UUID, optionIDs []uuid.UUID) (*dto.PollDTO, error) {
+	poll, err := s.getOpenPoll(conversationID, messageID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	optionIDs = uniquePollOptionIDs(optionIDs)
+	if err := validatePollOptionIDs(poll, optionIDs); err != nil {
+		return nil, err
+	}
+
+	removed, err := s.pollRepo.DeleteUserVotes(poll.ID, userID, optionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error retracting poll vote: %w", err)
+	}
+
+	if removed == 0 {
+		return nil, fmt.Errorf("vote not found")
+	}
+
+	return s.pollResultsAndNotify(poll, userID, pollActionRetracted)
+}
+
+// ClosePoll ปิดโพลก่อนเวลา (ผู้สร้างโพล หรือ owner/admin ของกลุ่ม)
+func (s *messageService) ClosePoll(conversationID, messageID, userID uuid.UUID) (*dto.PollDTO, error) {
+	poll, err := s.getPollForMember(conversationID, messageID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if poll.IsClosedAt(time.Now()) {
+		return nil, fmt.Errorf("poll is already closed")
+	}
+
+	if poll.CreatorID != userID {
+		member, err := s.conversationRepo.GetMember(conversationID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("error checking membership: %w", err)
+		}
+		if member == nil || (member.Role != models.RoleOwner && member.Role != models.RoleAdmin) {
+			return nil, fmt.Errorf("only the poll creator or group admins can close this poll")
+		}
+	}
+
+	now := time.Now()
+	if err := s.pollRepo.Close(poll.ID, userID, now); err != nil {
+		return nil, fmt.Errorf("error closing poll: %w", err)
+	}
+
+	poll.IsClosed = true
+	poll.ClosedAt = &now
+	poll.ClosedBy = &userID
+
+	return s.pollResultsAndNotify(poll, userID, pollActionClosed)
+}
+
+// getPollForMember ดึงโพลของข้อความและตรวจสอบว่าผู้ใช้เป็นสมาชิกของการสนทนา
+func (s *messageService) getPollForMember(conversationID, messageID, userID uuid.UUID) (*models.Poll, error) {
+	message, err := s.messageRepo.GetByID(messageID)
+	if err != nil || message == nil || message.ConversationID != conversationID {
+		return nil, fmt.Errorf("message not found")
+	}
+
+	if message.MessageType != "poll" {
+		return nil, fmt.Errorf("message is not a poll")
+	}
+
+	if message.IsDeleted {
+		return nil, fmt.Errorf("poll has been deleted")
+	}
+
+	isMember, err := s.conversationRepo.IsMember(conversationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error checking membership: %w", err)
+	}
+
+	if !isMember {
+		return nil, fmt.Errorf("user is not a member of this conversation")
+	}
+
+	poll, err := s.pollRepo.GetByMessageID(messageID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching poll: %w", err)
+	}
+
+	if poll == nil {
+		return nil, fmt.Errorf("poll not found")
+	}
+
+	return poll, nil
+}
+
+// getOpenPoll ดึงโพลที่ยังเปิดให้โหวตอยู่
+func (s *messageService) getOpenPoll(conversationID, messageID, userID uuid.UUID) (*models.Poll, error) {
+	poll, err := s.getPollForMember(conversationID, messageID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if poll.IsClosedAt(time.Now()) {
+		return nil, fmt.Errorf("poll is closed")
+	}
+
+	return poll, nil
+}
+
+// pollResultsAndNotify ดึงผลโหวตล่าสุดและแจ้งเตือนสมาชิกในการสนทนาผ่าน WebSocket
+func (s *messageService) pollResultsAndNotify(poll *models.Poll, userID uuid.UUID, action string) (*dto.PollDTO, error) {
+	votes, err := s.pollRepo.GetVotes(poll.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching poll votes: %w", err)
+	}
+
+	result := buildPollDTO(poll, votes, userID)
+
+	if s.notificationService != nil {
+		// ส่งผลโหวตแบบไม่ระบุผู้รับ (voted_by_me/my_votes ขึ้นกับผู้รับแต่ละคน)
+		payload := map[string]interface{}{
+			"message_id":      poll.MessageID.String(),
+			"conversation_id": poll.ConversationID.String(),
+			"action":          action,
+			"poll":            buildPollDTO(poll, votes, uuid.Nil),
+			"updated_at":      time.Now().Format(time.RFC3339),
+		}
+		// โพลแบบไม่ระบุตัวตนจะไม่เปิดเผยว่าใครโหวต
+		if !poll.IsAnonymous || action == pollActionClosed {
+			payload["user_id"] = userID.String()
+		}
+
+		s.notificationService.NotifyPollUpdated(poll.ConversationID, payload)
+	}
+
+	return result, nil
+}
+
+// validatePollPayload ตรวจสอบคำถามและตัวเลือกของโพล
+func validatePollPayload(question string, options []string) (string, []string, error) {
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return "", nil, fmt.Errorf("poll question cannot be empty")
+	}
+	if utf8.RuneCountInString(question) > maxPollQuestionLength {
+		return "", nil, fmt.Errorf("poll question is too long")
+	}
+
+	cleaned := make([]string, 0, len(options))
+	seen := make(map[string]bool)
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			return "", nil, fmt.Errorf("poll option cannot be empty")
+		}
+		if utf8.RuneCountInString(option) > maxPollOptionLength {
+			return "", nil, fmt.Errorf("poll option is too long")
+		}
+		key := strings.ToLower(option)
+		if seen[key] {
+			return "", nil, fmt.Errorf("poll options must be unique")
+		}
+		seen[key] = true
+		cleaned = append(cleaned, option)
+	}
+
+	if len(cleaned) < minPollOptions || len(cleaned) > maxPollOptions {
+		return "", nil, fmt.Errorf("poll must have between %d and %d options", minPollOptions, maxPollOptions)
+	}
+
+	return question, cleaned, nil
+}
+
+// validatePollOptionIDs ตรวจสอบว่าตัวเลือกทั้งหมดเป็นของโพลนี้
+func validatePollOptionIDs(poll *models.Poll, optionIDs []uuid.UUID) error {
+	valid := make(map[uuid.UUID]bool, len(poll.Options))
+	for _, option := range poll.Options {
+		valid[option.ID] = true
+	}
+
+	for _, optionID := range optionIDs {
+		if !valid[optionID] {
+			return fmt.Errorf("invalid poll option")
+		}
+	}
+
+	return nil
+}
+
+// uniquePollOptionIDs ตัด ID ที่ซ้ำออกโดยคงลำดับเดิม
+func uniquePollOptionIDs(optionIDs []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(optionIDs))
+	unique := make([]uuid.UUID, 0, len(optionIDs))
+	for _, optionID := range optionIDs {
+		if optionID == uuid.Nil || seen[optionID] {
+			continue
+		}
+		seen[optionID] = true
+		unique = append(unique, optionID)
+	}
+	return unique
+}
+
+// buildPollDTO รวมผลโหวตของโพล (viewerID = uuid.Nil สำหรับข้อมูลที่ไม่ขึ้นกับผู้ดู)
+func buildPollDTO(poll *models.Poll, votes []*models.PollVote, viewerID uuid.UUID) *dto.PollDTO {
+	result := &dto.PollDTO{
+		ID:            poll.ID,
+		MessageID:     poll.MessageID,
+		CreatorID:     poll.CreatorID,
+		Question:      poll.Question,
+		AllowMultiple: poll.AllowMultiple,
+		IsAnonymous:   poll.IsAnonymous,
+		ClosesAt:      poll.ClosesAt,
+		IsClosed:      poll.IsClosedAt(time.Now()),
+		ClosedAt:      poll.ClosedAt,
+		ClosedBy:      poll.ClosedBy,
+		Options:       make([]dto.PollOptionDTO, 0, len(poll.Options)),
+	}
+
+	indexByOption := make(map[uuid.UUID]int, len(poll.Options))
+	for i, option := range poll.Options {
+		indexByOption[option.ID] = i
+		result.Options = append(result.Options, dto.PollOptionDTO{
+			ID:       option.ID,
+			Text:     option.Text,
+			Position: option.Position,
+		})
+	}
+
+	voters := make(map[uuid.UUID]bool)
+	for _, vote := range votes {
+		idx, ok := indexByOption[vote.OptionID]
+		if !ok {
+			continue
+		}
+
+		result.Options[idx].VoteCount++
+		result.TotalVotes++
+		voters[vote.UserID] = true
+
+		if !poll.IsAnonymous {
+			result.Options[idx].VoterIDs = append(result.Options[idx].VoterIDs, vote.UserID)
+		}
+
+		if viewerID != uuid.Nil && vote.UserID == viewerID {
+			result.Options[idx].VotedByMe = true
+			result.MyVotes = append(result.MyVotes, vote.OptionID)
+		}
+	}
+	result.TotalVoters = len(voters)
+
+	return result
+}
@@ -0,0 +1,214 @@
+// application/serviceimpl/message_reaction_service.go
+package serviceimpl
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// maxReactionEmojiLength ความยาวสูงสุดของ emoji (ตรงกับ varchar(32) ใน message_reactions)
+const maxReactionEmojiLength = 32
+
+// Reaction actions ที่ส่งไปกับ event message.reaction
+const (
+	reactionActionAdded   = "added"
+	reactionActionRemoved = "removed"
+)
+
+// AddReaction เพิ่มปฏิกิริยาต่อข้อความ
+func (s *messageService) AddReaction(conversationID, messageID, userID uuid.UUID, emoji string) ([]dto.MessageReactionSummaryDTO, error) {
+	emoji, err := s.validateReactionTarget(conversationID, messageID, userID, emoji)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.createReaction(messageID, userID, emoji)
+	if err != nil {
+		return nil, err
+	}
+
+	// ผู้ใช้ใส่ปฏิกิริยานี้ไว้แล้ว: ไม่มีอะไรเปลี่ยน จึงไม่ส่ง event ซ้ำ
+	if !created {
+		return s.reactionSummaries(messageID, userID)
+	}
+
+	return s.reactionSummariesAndNotify(conversationID, messageID, userID, emoji, reactionActionAdded)
+}
+
+// RemoveReaction ลบปฏิกิริยาของผู้ใช้ออกจากข้อความ
+func (s *messageService) RemoveReaction(conversationID, messageID, userID uuid.UUID, emoji string) ([]dto.MessageReactionSummaryDTO, error) {
+	emoji, err := s.validateReactionTarget(conversationID, messageID, userID, emoji)
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := s.reactionRepo.Delete(messageID, userID, emoji)
+	if err != nil {
+		return nil, fmt.Errorf("error removing reaction: %w", err)
+	}
+
+	if !removed {
+		return nil, fmt.Errorf("reaction not found")
+	}
+
+	return s.reactionSummariesAndNotify(conversationID, messageID, userID, emoji, reactionActionRemoved)
+}
+
+// ToggleReaction สลับปฏิกิริยา (ถ้ามีอยู่แล้วจะลบ ถ้ายังไม่มีจะเพิ่ม)
+// คืนค่า true ถ้าเป็นการเพิ่มปฏิกิริยา
+func (s *messageService) ToggleReaction(conversationID, messageID, userID uuid.UUID, emoji string) (bool, []dto.MessageReactionSummaryDTO, error) {
+	emoji, err := s.validateReactionTarget(conversationID, messageID, userID, emoji)
+	if err != nil {
+		return false, nil, err
+	}
+
+	removed, err := s.reactionRepo.Delete(messageID, userID, emoji)
+	if err != nil {
+		return false, nil, fmt.Errorf("error removing reaction: %w", err)
+	}
+
+	action := reactionActionRemoved
+	if !removed {
+		created, err := s.createReaction(messageID, userID, emoji)
+		if err != nil {
+			return false, nil, err
+		}
+
+		// คำขออื่นเพิ่มปฏิกิริยาเดียวกันไปพร้อมกัน: ปฏิกิริยามีอยู่แล้ว ไม่ต้องส่ง event ซ้ำ
+		if !created {
+			summaries, err := s.reactionSummaries(messageID, userID)
+			return true, summaries, err
+		}
+		action = reactionActionAdded
+	}
+
+	summaries, err := s.reactionSummariesAndNotify(conversationID, messageID, userID, emoji, action)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return action == reactionActionAdded, summaries, nil
+}
+
+// validateReactionTarget ตรวจสอบ emoji, ข้อความ และสิทธิ์ของผู้ใช้ก่อนจัดการปฏิกิริยา
+func (s *messageService) validateReactionTarget(conversationID, messageID, userID uuid.UUID, emoji string) (string, error) {
+	emoji = strings.TrimSpace(emoji)
+	if emoji == "" {
+		return "", fmt.Errorf("emoji is required")
+	}
+	if utf8.RuneCountInString(emoji) > maxReactionEmojiLength {
+		return "", fmt.Errorf("emoji is too long")
+	}
+
+	message, err := s.messageRepo.GetByID(messageID)
+	if err != nil || message == nil {
+		return "", fmt.Errorf("message not found")
+	}
+
+	if message.ConversationID != conversationID {
+		return "", fmt.Errorf("message not found")
+	}
+
+	if message.IsDeleted {
+		return "", fmt.Errorf("cannot react to deleted message")
+	}
+
+	isMember, err := s.conversationRepo.IsMember(conversationID, userID)
+	if err != nil {
+		return "", fmt.Errorf("error checking membership: %w", err)
+	}
+
+	if !isMember {
+		return "", fmt.Errorf("user is not a member of this conversation")
+	}
+
+	return emoji, nil
+}
+
+// createReaction บันทึกปฏิกิริยาใหม่ คืนค่า false ถ้าผู้ใช้ใส่ปฏิกิริยานี้ไว้แล้ว
+func (s *messageService) createReaction(messageID, userID uuid.UUID, emoji string) (bool, error) {
+	reaction := &models.MessageReaction{
+		ID:        uuid.New(),
+		MessageID: messageID,
+		UserID:    userID,
+		Emoji:     emoji,
+		CreatedAt: time.Now(),
+	}
+
+	created, err := s.reactionRepo.Create(reaction)
+	if err != nil {
+		return false, fmt.Errorf("error adding reaction: %w", err)
+	}
+
+	return created, nil
+}
+
+// reactionSummaries ดึงสรุปปฏิกิริยาล่าสุดของข้อความตามมุมมองของผู้ใช้
+func (s *messageService) reactionSummaries(messageID, userID uuid.UUID) ([]dto.MessageReactionSummaryDTO, error) {
+	reactions, err := s.reactionRepo.GetByMessageID(messageID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching reactions: %w", err)
+	}
+
+	return buildReactionSummaries(reactions, userID), nil
+}
+
+// reactionSummariesAndNotify ดึงสรุปปฏิกิริยาล่าสุดและแจ้งเตือนสมาชิกในการสนทนาผ่าน WebSocket
+func (s *messageService) reactionSummariesAndNotify(conversationID, messageID, userID uuid.UUID, emoji, action string) ([]dto.MessageReactionSummaryDTO, error) {
+	summaries, err := s.reactionSummaries(messageID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.notificationService != nil {
+		// ส่งเฉพาะจำนวนต่อ emoji เพราะ reacted_by_me ขึ้นกับผู้รับแต่ละคน
+		// client ใช้ user_id + action เพื่ออัปเดตสถานะของตัวเอง
+		counts := make([]map[string]interface{}, 0, len(summaries))
+		for _, summary := range summaries {
+			counts = append(counts, map[string]interface{}{
+				"emoji": summary.Emoji,
+				"count": summary.Count,
+			})
+		}
+
+		s.notificationService.NotifyMessageReaction(conversationID, map[string]interface{}{
+			"message_id":      messageID.String(),
+			"conversation_id": conversationID.String(),
+			"user_id":         userID.String(),
+			"emoji":           emoji,
+			"action":          action,
+			"reactions":       counts,
+			"updated_at":      time.Now().Format(time.RFC3339),
+		})
+	}
+
+	return summaries, nil
+}
+
+// buildReactionSummaries รวมปฏิกิริยาเป็นจำนวนต่อ emoji โดยเรียงตามลำดับที่ emoji ถูกใช้ครั้งแรก
+func buildReactionSummaries(reactions []*models.MessageReaction, userID uuid.UUID) []dto.MessageReactionSummaryDTO {
+	summaries := make([]dto.MessageReactionSummaryDTO, 0)
+	indexByEmoji := make(map[string]int)
+
+	for _, reaction := range reactions {
+		idx, exists := indexByEmoji[reaction.Emoji]
+		if !exists {
+			idx = len(summaries)
+			indexByEmoji[reaction.Emoji] = idx
+			summaries = append(summaries, dto.MessageReactionSummaryDTO{Emoji: reaction.Emoji})
+		}
+
+		summaries[idx].Count++
+		if reaction.UserID == userID {
+			summaries[idx].ReactedByMe = true
+		}
+	}
+
+	return summaries
+}
@@ -6,11 +6,15 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
 	"github.com/thizplus/gofiber-chat-api/domain/models"
 	"github.com/thizplus/gofiber-chat-api/domain/repository"
 	"github.com/thizplus/gofiber-chat-api/domain/service"
 )
 
+// channelUnreadIDsLimit จำนวน ID สูงสุดที่คืนให้สำหรับข้อความที่ยังไม่ได้อ่านในช่อง (ใช้ GetUnreadCount สำหรับจำนวนทั้งหมด)
+const channelUnreadIDsLimit = 100
+
 // messageReadService เป็น implementation ของ MessageReadService
 type messageReadService struct {
 	messageRepo      repository.MessageRepository
@@ -59,6 +63,18 @@ func (s *messageReadService) MarkMessageAsRead(messageID, userID uuid.UUID) (uui
 		return uuid.Nil, errors.New("you are not a member of this conversation")
 	}
 
+	// ช่อง (channel) ใช้จุดอ่านของสมาชิกและยอดผู้ชมแทน message_reads รายคน
+	channel, err := s.getChannel(message.ConversationID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if channel != nil {
+		if _, err := s.markChannelReadUpTo(message.ConversationID, userID, message.CreatedAt); err != nil {
+			return uuid.Nil, err
+		}
+		return message.ConversationID, nil
+	}
+
 	// ตรวจสอบว่าอ่านแล้วหรือยัง
 	isRead, err := s.messageRepo.IsMessageRead(messageID, userID)
 	if err != nil {
@@ -144,10 +160,48 @@ func (s *messageReadService) GetMessageReads(messageID, userID uuid.UUID) ([]*mo
 		return nil, errors.New("you are not a member of this conversation")
 	}
 
+	// ช่อง (channel) ไม่มี read receipt รายคน (ใช้ GetMessageViews)
+	channel, err := s.getChannel(message.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	if channel != nil {
+		return []*models.MessageRead{}, nil
+	}
+
 	// ดึงข้อมูลการอ่านทั้งหมด
 	return s.messageReadRepo.GetByMessageID(messageID)
 }
 
+// GetMessageViews ดึงยอดผู้ชมของข้อความในช่อง (nil ถ้าไม่ใช่ข้อความในช่อง)
+func (s *messageReadService) GetMessageViews(messageID, userID uuid.UUID) (*dto.MessageViewsDTO, error) {
+	message, err := s.messageRepo.GetByID(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message == nil {
+		return nil, errors.New("message not found")
+	}
+
+	isMember, err := s.conversationRepo.IsMember(message.ConversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New("you are not a member of this conversation")
+	}
+
+	channel, err := s.getChannel(message.ConversationID)
+	if err != nil || channel == nil {
+		return nil, err
+	}
+
+	return &dto.MessageViewsDTO{
+		MessageID: message.ID,
+		ViewCount: message.ViewCount,
+	}, nil
+}
+
 // MarkAllMessagesAsRead ทำเครื่องหมายว่าข้อความทั้งหมดในการสนทนาถูกอ่านแล้ว
 func (s *messageReadService) MarkAllMessagesAsRead(conversationID, userID uuid.UUID) (int, error) {
 
@@ -161,6 +215,14 @@ func (s *messageReadService) MarkAllMessagesAsRead(conversationID, userID uuid.U
 		return 0, errors.New("you are not a member of this conversation")
 	}
 
+	channel, err := s.getChannel(conversationID)
+	if err != nil {
+		return 0, err
+	}
+	if channel != nil {
+		return s.markChannelReadUpTo(conversationID, userID, time.Now())
+	}
+
 	// อัปเดต last_read_at ก่อน
 	now := time.Now()
 	if err := s.conversationRepo.UpdateMemberLastRead(conversationID, userID, now); err != nil {
@@ -207,6 +269,14 @@ func (s *messageReadService) GetUnreadCount(conversationID, userID uuid.UUID) (i
 		return 0, errors.New("you are not a member of this conversation")
 	}
 
+	channel, err := s.getChannel(conversationID)
+	if err != nil {
+		return 0, err
+	}
+	if channel != nil {
+		return s.channelUnreadCount(conversationID, userID)
+	}
+
 	// ดึงข้อความทั้งหมดที่ยังไม่ได้อ่าน
 	unreadMessageIDs, err := s.messageReadRepo.GetUnreadMessageIDs(conversationID, userID)
 	if err != nil {
@@ -243,6 +313,17 @@ func (s *messageReadService) MarkConversationAsRead(conversationID, userID, last
 		return 0, errors.New("message does not belong to this conversation")
 	}
 
+	channel, err := s.getChannel(conversationID)
+	if err != nil {
+		return 0, err
+	}
+	if channel != nil {
+		if _, err := s.markChannelReadUpTo(conversationID, userID, lastReadMessage.CreatedAt); err != nil {
+			return 0, err
+		}
+		return s.channelUnreadCount(conversationID, userID)
+	}
+
 	// ดึงข้อความทั้งหมดที่ยังไม่ได้อ่าน
 	unreadMessageIDs, err := s.messageReadRepo.GetUnreadMessageIDs(conversationID, userID)
 	if err != nil {
@@ -304,6 +385,17 @@ func (s *messageReadService) GetUnreadCounts(userID uuid.UUID) (map[uuid.UUID]in
 	totalUnread := 0
 
 	for _, conversation := range conversations {
+		// ช่อง (channel) นับจากจุดอ่านของสมาชิก (ไม่รวมข้อความของตัวเองอยู่แล้ว)
+		if conversation.IsChannel() {
+			count, err := s.channelUnreadCount(conversation.ID, userID)
+			if err != nil || count == 0 {
+				continue
+			}
+			unreadCounts[conversation.ID] = count
+			totalUnread += count
+			continue
+		}
+
 		// ดึงจำนวนข้อความที่ยังไม่ได้อ่านในแต่ละการสนทนา
 		unreadMessageIDs, err := s.messageReadRepo.GetUnreadMessageIDs(conversation.ID, userID)
 		if err != nil {
@@ -347,6 +439,87 @@ func (s *messageReadService) GetUnreadMessageIDs(conversationID, userID uuid.UUI
 		return nil, errors.New("you are not a member of this conversation")
 	}
 
+	channel, err := s.getChannel(conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if channel != nil {
+		return s.channelUnreadMessageIDs(conversationID, userID)
+	}
+
 	// ดึงข้อความทั้งหมดที่ยังไม่ได้อ่าน
 	return s.messageReadRepo.GetUnreadMessageIDs(conversationID, userID)
 }
+
+// getChannel ดึงการสนทนาถ้าเป็นช่อง channel (nil ถ้าเป็นการสนทนาประเภทอื่น)
+func (s *messageReadService) getChannel(conversationID uuid.UUID) (*models.Conversation, error) {
+	conversation, err := s.conversationRepo.GetByID(conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if conversation == nil || !conversation.IsChannel() {
+		return nil, nil
+	}
+	return conversation, nil
+}
+
+// channelReadCursor จุดเริ่มนับข้อความที่ยังไม่ได้อ่านในช่องของสมาชิก
+func (s *messageReadService) channelReadCursor(conversationID, userID uuid.UUID) (time.Time, error) {
+	member, err := s.conversationRepo.GetMember(conversationID, userID)
+	if err != nil || member == nil {
+		return time.Time{}, errors.New("you are not a member of this conversation")
+	}
+	return member.UnreadCursor(), nil
+}
+
+// channelUnreadCount นับข้อความในช่องที่ใหม่กว่าจุดอ่านของสมาชิก (นับในฐานข้อมูล ไม่โหลดข้อความ)
+func (s *messageReadService) channelUnreadCount(conversationID, userID uuid.UUID) (int, error) {
+	cursor, err := s.channelReadCursor(conversationID, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := s.messageRepo.CountMessagesAfterTime(conversationID, cursor, userID)
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// channelUnreadMessageIDs ดึง ID ของข้อความในช่องที่ใหม่กว่าจุดอ่านของสมาชิก (ไม่เกิน channelUnreadIDsLimit รายการแรก)
+func (s *messageReadService) channelUnreadMessageIDs(conversationID, userID uuid.UUID) ([]uuid.UUID, error) {
+	cursor, err := s.channelReadCursor(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.messageRepo.GetMessageIDsAfterTime(conversationID, cursor, userID, channelUnreadIDsLimit)
+}
+
+// markChannelReadUpTo เลื่อนจุดอ่านของสมาชิกในช่องและเพิ่มยอดผู้ชมของข้อความที่อ่านผ่าน
+// (ไม่สร้าง message_reads รายคน เพื่อรองรับผู้ติดตามจำนวนมาก) คืนค่าจำนวนข้อความที่ถูกนับ
+func (s *messageReadService) markChannelReadUpTo(conversationID, userID uuid.UUID, readUpTo time.Time) (int, error) {
+	member, err := s.conversationRepo.GetMember(conversationID, userID)
+	if err != nil || member == nil {
+		return 0, errors.New("you are not a member of this conversation")
+	}
+	// นับเฉพาะข้อความหลังจุดอ่านล่าสุด หรือหลังเวลาเข้าร่วมถ้ายังไม่เคยอ่าน (โพสต์ก่อนติดตามไม่นับเป็นยอดผู้ชม)
+	cursor := member.UnreadCursor()
+	if !readUpTo.After(cursor) {
+		return 0, nil
+	}
+
+	// เลื่อนจุดอ่านแบบมีเงื่อนไขก่อน เพื่อไม่ให้คำขอที่มาพร้อมกันนับยอดผู้ชมซ้ำ
+	advanced, err := s.conversationRepo.AdvanceMemberLastRead(conversationID, userID, member.LastReadAt, readUpTo)
+	if err != nil {
+		return 0, err
+	}
+	if !advanced {
+		return 0, nil
+	}
+
+	viewed, err := s.messageRepo.IncrementViewCounts(conversationID, userID, cursor, readUpTo)
+	if err != nil {
+		return 0, err
+	}
+	return int(viewed), nil
+}
@@ -38,25 +38,25 @@ func (s *messageService) ReplyToMessage(replyToID, userID uuid.UUID, messageType
 		return nil, fmt.Errorf("you are not a member of this conversation")
 	}
 
+	// ช่อง (channel) โพสต์ได้เฉพาะ owner/admin
+	conversation, err := checkPostPermission(s.conversationRepo, replyToMessage.ConversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	// ตรวจสอบตามประเภทข้อความ
-	switch messageType {
-	case "text":
-		if strings.TrimSpace(content) == "" {
-			return nil, fmt.Errorf("message content is required")
-		}
-	case "sticker":
-		if mediaURL == "" {
-			return nil, fmt.Errorf("sticker URL is required")
-		}
-	case "image", "file":
-		if mediaURL == "" {
-			return nil, fmt.Errorf("media URL is required")
-		}
-	default:
-		return nil, fmt.Errorf("invalid message type")
+	if err := validateReplyPayload(messageType, content, mediaURL); err != nil {
+		return nil, err
+	}
+
+	// รัน content filter กับข้อความ/คำบรรยายก่อนบันทึก
+	filtered, err := s.filterContent(replyToMessage.ConversationID, &userID, content, false)
+	if err != nil {
+		return nil, err
 	}
+	content = filtered.Content
 
-	senderType := "user"
+	senderType := s.senderTypeOf(userID)
 
 	// ตรวจสอบว่ามี business_id ใน metadata หรือไม่
 
@@ -75,6 +75,7 @@ func (s *messageService) ReplyToMessage(replyToID, userID uuid.UUID, messageType
 		Metadata:          s.convertMetadataToJSON(metadata),
 		CreatedAt:         now,
 		UpdatedAt:         now,
+		ExpiresAt:         conversation.MessageExpiresAt(now),
 	}
 
 
@@ -121,5 +122,30 @@ func (s *messageService) ReplyToMessage(replyToID, userID uuid.UUID, messageType
 		fmt.Printf("Error creating read record: %v, messageID: %s, userID: %s", err, message.ID.String(), userID)
 	}
 
+	// ส่งข้อความที่ถูก flag เข้าคิวตรวจสอบ
+	s.reportContentFlags(message, filtered)
+
 	return message, nil
 }
+
+// validateReplyPayload ตรวจสอบข้อมูลของข้อความตอบกลับตามประเภทข้อความ
+func validateReplyPayload(messageType, content, mediaURL string) error {
+	switch messageType {
+	case "text":
+		if strings.TrimSpace(content) == "" {
+			return fmt.Errorf("message content is required")
+		}
+	case "sticker":
+		if mediaURL == "" {
+			return fmt.Errorf("sticker URL is required")
+		}
+	case "image", "file":
+		if mediaURL == "" {
+			return fmt.Errorf("media URL is required")
+		}
+	default:
+		return fmt.Errorf("invalid message type")
+	}
+
+	return nil
+}
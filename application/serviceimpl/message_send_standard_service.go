@@ -24,6 +24,12 @@ func (s *messageService) SendTextMessage(conversationID, userID uuid.UUID, conte
 		return nil, fmt.Errorf("user is not a member of this conversation")
 	}
 
+	// ช่อง (channel) โพสต์ได้เฉพาะ owner/admin
+	conversation, err := checkPostPermission(s.conversationRepo, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	// ตรวจสอบเนื้อหาข้อความ
 	if strings.TrimSpace(content) == "" {
 		return nil, fmt.Errorf("message content cannot be empty")
@@ -34,6 +40,30 @@ func (s *messageService) SendTextMessage(conversationID, userID uuid.UUID, conte
 		return nil, fmt.Errorf("error fetching conversation: %w", err)
 	}
 
+	return s.createTextMessage(conversationID, conversation, &userID, s.senderTypeOf(userID), content, metadata)
+}
+
+// SendWebhookMessage ส่งข้อความ text จาก incoming webhook (ไม่มีผู้ใช้เป็นผู้ส่ง)
+// ผ่านขั้นตอนเดียวกับ SendTextMessage ยกเว้นการตรวจสอบสมาชิกซึ่งทำตอนตรวจ token ของ webhook แล้ว
+func (s *messageService) SendWebhookMessage(conversation *models.Conversation, content string, metadata map[string]interface{}) (*models.Message, error) {
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("message content cannot be empty")
+	}
+
+	return s.createTextMessage(conversation.ID, conversation, nil, models.SenderTypeWebhook, content, metadata)
+}
+
+// createTextMessage บันทึกข้อความ text และอัปเดตข้อความล่าสุดของการสนทนา
+// senderID เป็น nil สำหรับข้อความที่ไม่มีผู้ใช้เป็นผู้ส่ง (เช่น incoming webhook)
+// conversation คือการสนทนาที่ผู้เรียกโหลดไว้แล้ว (ใช้กำหนด expires_at ตาม message_ttl)
+func (s *messageService) createTextMessage(conversationID uuid.UUID, conversation *models.Conversation, senderID *uuid.UUID, senderType, content string, metadata map[string]interface{}) (*models.Message, error) {
+	// รัน content filter ก่อนบันทึก (mask = ใช้เนื้อหาที่ถูกแทนที่, reject = คืนค่า error)
+	filtered, err := s.filterContent(conversationID, senderID, content, false)
+	if err != nil {
+		return nil, err
+	}
+	content = filtered.Content
+
 	// Extract links จากข้อความและเพิ่มลงใน metadata
 	links := s.extractLinks(content)
 	if len(links) > 0 {
@@ -69,14 +99,15 @@ func (s *messageService) SendTextMessage(conversationID, userID uuid.UUID, conte
 	message := &models.Message{
 		ID:             uuid.New(),
 		ConversationID: conversationID,
-		SenderID:       &userID,
-		SenderType:     "user",
+		SenderID:       senderID,
+		SenderType:     senderType,
 		MessageType:    "text",
 		Content:        content,
 		Metadata:       s.convertMetadataToJSON(metadata),
 		Mentions:       mentionsJSON,
 		CreatedAt:      now,
 		UpdatedAt:      now,
+		ExpiresAt:      conversation.MessageExpiresAt(now),
 		IsDeleted:      false,
 	}
 
@@ -86,21 +117,22 @@ func (s *messageService) SendTextMessage(conversationID, userID uuid.UUID, conte
 		return nil, fmt.Errorf("error creating message: %w", err)
 	}
 
-	// สร้างบันทึกการอ่านสำหรับผู้ส่ง
-	messageRead := &models.MessageRead{
-		ID:        uuid.New(),
-		MessageID: message.ID,
-		UserID:    userID,
-		ReadAt:    now,
-	}
+	// สร้างบันทึกการอ่านและอัปเดต last_read_at สำหรับผู้ส่ง (ข้อความจาก webhook ไม่มีผู้ส่ง)
+	if senderID != nil {
+		messageRead := &models.MessageRead{
+			ID:        uuid.New(),
+			MessageID: message.ID,
+			UserID:    *senderID,
+			ReadAt:    now,
+		}
 
-	if err := s.messageReadRepo.CreateRead(messageRead); err != nil {
-		fmt.Printf("Error creating read record: %v, messageID: %s, userID: %s", err, message.ID.String(), userID)
-	}
+		if err := s.messageReadRepo.CreateRead(messageRead); err != nil {
+			fmt.Printf("Error creating read record: %v, messageID: %s, userID: %s", err, message.ID.String(), *senderID)
+		}
 
-	// อัปเดต last_read_at สำหรับผู้ส่ง
-	if err := s.conversationRepo.UpdateMemberLastRead(conversationID, userID, now); err != nil {
-		fmt.Printf("Error updating last read time: %v, conversationID: %s, userID: %s", err, conversationID, userID)
+		if err := s.conversationRepo.UpdateMemberLastRead(conversationID, *senderID, now); err != nil {
+			fmt.Printf("Error updating last read time: %v, conversationID: %s, userID: %s", err, conversationID, *senderID)
+		}
 	}
 
 	// อัปเดตข้อความล่าสุดของการสนทนา
@@ -109,13 +141,16 @@ func (s *messageService) SendTextMessage(conversationID, userID uuid.UUID, conte
 	}
 
 	// ส่งการแจ้งเตือนสำหรับผู้ใช้ที่ถูก mention
-	if mentions != nil {
-		s.notifyMentionedUsers(message, mentions, userID)
+	if mentions != nil && senderID != nil {
+		s.notifyMentionedUsers(message, mentions, *senderID)
 	}
 
 	// ส่ง WebSocket event แจ้งการอัปเดต conversation พร้อม mention data
 	s.notifyConversationUpdated(conversationID, content, now, message.ID)
 
+	// ส่งข้อความที่ถูก flag เข้าคิวตรวจสอบ
+	s.reportContentFlags(message, filtered)
+
 	return message, nil
 }
 
@@ -132,6 +167,12 @@ func (s *messageService) SendStickerMessage(conversationID, userID, stickerID, s
 		return nil, fmt.Errorf("user is not a member of this conversation")
 	}
 
+	// ช่อง (channel) โพสต์ได้เฉพาะ owner/admin
+	conversation, err := checkPostPermission(s.conversationRepo, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	// ตรวจสอบ URL สติกเกอร์
 	if mediaURL == "" {
 		return nil, fmt.Errorf("sticker URL is required")
@@ -165,13 +206,14 @@ func (s *messageService) SendStickerMessage(conversationID, userID, stickerID, s
 		ID:                uuid.New(),
 		ConversationID:    conversationID,
 		SenderID:          &userID,
-		SenderType:        "user",
+		SenderType:        s.senderTypeOf(userID),
 		MessageType:       "sticker",
 		MediaURL:          mediaURL,
 		MediaThumbnailURL: thumbnailURL,
 		Metadata:          s.convertMetadataToJSON(stickerMetadata),
 		CreatedAt:         now,
 		UpdatedAt:         now,
+		ExpiresAt:         conversation.MessageExpiresAt(now),
 		IsDeleted:         false,
 	}
 
@@ -222,6 +264,12 @@ func (s *messageService) SendImageMessage(conversationID, userID uuid.UUID, medi
 		return nil, fmt.Errorf("user is not a member of this conversation")
 	}
 
+	// ช่อง (channel) โพสต์ได้เฉพาะ owner/admin
+	conversation, err := checkPostPermission(s.conversationRepo, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	// ตรวจสอบ URL รูปภาพ
 	if mediaURL == "" {
 		return nil, fmt.Errorf("image URL is required")
@@ -238,7 +286,7 @@ func (s *messageService) SendImageMessage(conversationID, userID uuid.UUID, medi
 		ID:                uuid.New(),
 		ConversationID:    conversationID,
 		SenderID:          &userID,
-		SenderType:        "user",
+		SenderType:        s.senderTypeOf(userID),
 		MessageType:       "image",
 		Content:           caption,
 		MediaURL:          mediaURL,
@@ -246,6 +294,7 @@ func (s *messageService) SendImageMessage(conversationID, userID uuid.UUID, medi
 		Metadata:          s.convertMetadataToJSON(metadata),
 		CreatedAt:         now,
 		UpdatedAt:         now,
+		ExpiresAt:         conversation.MessageExpiresAt(now),
 		IsDeleted:         false,
 	}
 
@@ -301,6 +350,12 @@ func (s *messageService) SendFileMessage(conversationID, userID uuid.UUID, media
 		return nil, fmt.Errorf("user is not a member of this conversation")
 	}
 
+	// ช่อง (channel) โพสต์ได้เฉพาะ owner/admin
+	conversation, err := checkPostPermission(s.conversationRepo, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	// ตรวจสอบ URL ไฟล์
 	if mediaURL == "" {
 		return nil, fmt.Errorf("file URL is required")
@@ -338,13 +393,14 @@ func (s *messageService) SendFileMessage(conversationID, userID uuid.UUID, media
 		ID:             uuid.New(),
 		ConversationID: conversationID,
 		SenderID:       &userID,
-		SenderType:     "user",
+		SenderType:     s.senderTypeOf(userID),
 		MessageType:    "file",
 		Content:        fileName,
 		MediaURL:       mediaURL,
 		Metadata:       s.convertMetadataToJSON(fileMetadata),
 		CreatedAt:      now,
 		UpdatedAt:      now,
+		ExpiresAt:      conversation.MessageExpiresAt(now),
 		IsDeleted:      false,
 	}
 
@@ -400,6 +456,12 @@ func (s *messageService) SendBulkMessages(conversationID, userID uuid.UUID, capt
 		return nil, fmt.Errorf("user is not a member of this conversation")
 	}
 
+	// ช่อง (channel) โพสต์ได้เฉพาะ owner/admin
+	conversation, err := checkPostPermission(s.conversationRepo, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	// ตรวจสอบจำนวนไฟล์ (สูงสุด 10 ไฟล์)
 	if len(items) == 0 {
 		return nil, fmt.Errorf("at least one file is required")
@@ -488,13 +550,14 @@ func (s *messageService) SendBulkMessages(conversationID, userID uuid.UUID, capt
 		ID:             uuid.New(),
 		ConversationID: conversationID,
 		SenderID:       &userID,
-		SenderType:     "user",
+		SenderType:     s.senderTypeOf(userID),
 		MessageType:    "album",  // ใช้ type "album"
 		Content:        caption,  // caption จาก item แรก (ถ้ามี)
 		AlbumFiles:     albumFiles,  // array ของไฟล์ทั้งหมด
 		Metadata:       metadata,
 		CreatedAt:      now,
 		UpdatedAt:      now,
+		ExpiresAt:      conversation.MessageExpiresAt(now),
 		IsDeleted:      false,
 	}
 
@@ -25,6 +25,11 @@ type messageService struct {
 	userRepo            repository.UserRepository
 	notificationService service.NotificationService
 	mentionRepo         repository.MessageMentionRepository
+	reactionRepo        repository.MessageReactionRepository
+	threadReadRepo      repository.ThreadReadRepository
+	pollRepo            repository.PollRepository
+	e2eeKeyRepo         repository.E2EEKeyRepository
+	contentFilter       service.ContentFilterService
 }
 
 // NewMessageService สร้าง instance ใหม่ของ MessageService
@@ -35,6 +40,11 @@ func NewMessageService(
 	userRepo repository.UserRepository,
 	notificationService service.NotificationService,
 	mentionRepo repository.MessageMentionRepository,
+	reactionRepo repository.MessageReactionRepository,
+	threadReadRepo repository.ThreadReadRepository,
+	pollRepo repository.PollRepository,
+	e2eeKeyRepo repository.E2EEKeyRepository,
+	contentFilter service.ContentFilterService,
 ) service.MessageService {
 	return &messageService{
 		messageRepo:         messageRepo,
@@ -43,11 +53,25 @@ func NewMessageService(
 		userRepo:            userRepo,
 		notificationService: notificationService,
 		mentionRepo:         mentionRepo,
+		reactionRepo:        reactionRepo,
+		threadReadRepo:      threadReadRepo,
+		pollRepo:            pollRepo,
+		e2eeKeyRepo:         e2eeKeyRepo,
+		contentFilter:       contentFilter,
 	}
 }
 
 // CheckBusinessAdmin ตรวจสอบว่าผู้ใช้เป็นแอดมินของธุรกิจหรือไม่
 
+// senderTypeOf คืนค่า SenderType ของผู้ส่ง (บัญชีบอทใช้ "bot")
+func (s *messageService) senderTypeOf(userID uuid.UUID) string {
+	user, err := s.userRepo.FindByID(userID)
+	if err == nil && user != nil && user.IsBot {
+		return models.SenderTypeBot
+	}
+	return models.SenderTypeUser
+}
+
 // createMessageRead สร้างบันทึกการอ่านข้อความ
 func (s *messageService) createMessageRead(messageID, userID uuid.UUID) error {
 	// ตรวจสอบว่ามีบันทึกการอ่านแล้วหรือไม่
@@ -118,6 +142,33 @@ func (s *messageService) extractLinks(content string) []string {
 	return result
 }
 
+// filterContent รัน content filter chain กับเนื้อหาก่อนบันทึก (senderID nil = ข้อความจาก webhook)
+func (s *messageService) filterContent(conversationID uuid.UUID, senderID *uuid.UUID, content string, isEdit bool) (*service.ContentFilterResult, error) {
+	if s.contentFilter == nil {
+		return &service.ContentFilterResult{Content: content}, nil
+	}
+
+	input := &service.ContentFilterInput{
+		ConversationID: conversationID,
+		Content:        content,
+		Links:          s.extractLinks(content),
+		IsEdit:         isEdit,
+	}
+	if senderID != nil {
+		input.SenderID = *senderID
+	}
+
+	return s.contentFilter.Apply(input)
+}
+
+// reportContentFlags ส่งข้อความที่ถูก flag เข้าคิวตรวจสอบของผู้ดูแล
+func (s *messageService) reportContentFlags(message *models.Message, filtered *service.ContentFilterResult) {
+	if s.contentFilter == nil || filtered == nil || len(filtered.Flags) == 0 {
+		return
+	}
+	s.contentFilter.ReportFlags(message, filtered.Flags)
+}
+
 // PinMessage ปักหมุดข้อความ (สมาชิกทุกคนสามารถ pin ได้)
 func (s *messageService) PinMessage(messageID, conversationID, userID uuid.UUID) error {
 	// ตรวจสอบว่า message อยู่ในการสนทนานี้
@@ -404,6 +455,11 @@ func (s *messageService) ForwardMessage(messageID, targetConversationID, userID
 		return nil, errors.New("message not found")
 	}
 
+	// ciphertext ถูกเข้ารหัสสำหรับอุปกรณ์ของผู้รับเดิมเท่านั้น ส่งต่อไปการสนทนาอื่นไม่ได้
+	if originalMsg.MessageType == models.MessageTypeEncrypted {
+		return nil, errors.New("encrypted messages cannot be forwarded")
+	}
+
 	// ตรวจสอบว่า user เป็นสมาชิกของการสนทนาต้นทาง
 	isMember, err := s.conversationRepo.IsMember(originalMsg.ConversationID, userID)
 	if err != nil {
@@ -422,6 +478,18 @@ func (s *messageService) ForwardMessage(messageID, targetConversationID, userID
 		return nil, errors.New("user is not a member of the target conversation")
 	}
 
+	// ส่งต่อเข้าช่อง (channel) ได้เฉพาะ owner/admin
+	targetConversation, err := checkPostPermission(s.conversationRepo, targetConversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// รัน content filter ตามการตั้งค่าของการสนทนาปลายทาง
+	filtered, err := s.filterContent(targetConversationID, &userID, originalMsg.Content, false)
+	if err != nil {
+		return nil, err
+	}
+
 	// สร้างข้อความใหม่
 	now := time.Now()
 	forwardedMsg := &models.Message{
@@ -430,13 +498,14 @@ func (s *messageService) ForwardMessage(messageID, targetConversationID, userID
 		SenderID:          &userID,
 		SenderType:        "user",
 		MessageType:       originalMsg.MessageType,
-		Content:           originalMsg.Content,
+		Content:           filtered.Content,
 		MediaURL:          originalMsg.MediaURL,
 		MediaThumbnailURL: originalMsg.MediaThumbnailURL,
 		AlbumFiles:        originalMsg.AlbumFiles, // Copy album files for album messages
 		Metadata:          originalMsg.Metadata,
 		CreatedAt:         now,
 		UpdatedAt:         now,
+		ExpiresAt:         targetConversation.MessageExpiresAt(now),
 		IsDeleted:         false,
 	}
 
@@ -491,9 +560,9 @@ func (s *messageService) ForwardMessage(messageID, targetConversationID, userID
 	var lastMsgText string
 	if originalMsg.MessageType == "text" {
 		if hideSource {
-			lastMsgText = originalMsg.Content
+			lastMsgText = forwardedMsg.Content
 		} else {
-			lastMsgText = "[Forwarded] " + originalMsg.Content
+			lastMsgText = "[Forwarded] " + forwardedMsg.Content
 		}
 	} else {
 		if hideSource {
@@ -507,6 +576,9 @@ func (s *messageService) ForwardMessage(messageID, targetConversationID, userID
 	// ส่ง WebSocket event แจ้งการอัปเดต conversation พร้อม mention data
 	s.notifyConversationUpdated(targetConversationID, lastMsgText, now, forwardedMsg.ID)
 
+	// ส่งข้อความที่ถูก flag เข้าคิวตรวจสอบ
+	s.reportContentFlags(forwardedMsg, filtered)
+
 	return forwardedMsg, nil
 }
 
@@ -0,0 +1,114 @@
+// application/serviceimpl/message_thread_service.go
+package serviceimpl
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// ReplyInThread ตอบกลับในเธรดของข้อความ
+// ถ้า messageID เป็นข้อความในเธรดอยู่แล้ว จะตอบในเธรดเดียวกัน (เธรดมีชั้นเดียว) และอ้างอิงข้อความนั้นผ่าน ReplyToID
+// ข้อความในเธรดจะไม่อัปเดต LastMessageText ของการสนทนา
+func (s *messageService) ReplyInThread(messageID, userID uuid.UUID, messageType, content, mediaURL, thumbnailURL string, metadata map[string]interface{}) (*models.Message, error) {
+
+	// ดึงข้อความเป้าหมาย
+	target, err := s.messageRepo.GetByID(messageID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching thread message: %w", err)
+	}
+
+	if target == nil {
+		return nil, fmt.Errorf("message not found")
+	}
+
+	// หาข้อความต้นเธรด
+	root := target
+	var replyToID *uuid.UUID
+	if target.ThreadRootID != nil {
+		root, err = s.messageRepo.GetByID(*target.ThreadRootID)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching thread root: %w", err)
+		}
+		if root == nil {
+			return nil, fmt.Errorf("message not found")
+		}
+		replyToID = &target.ID
+	}
+
+	if root.IsDeleted || target.IsDeleted {
+		return nil, fmt.Errorf("cannot reply to deleted message")
+	}
+
+	// ตรวจสอบว่าผู้ใช้เป็นสมาชิกของการสนทนา
+	isMember, err := s.conversationRepo.IsMember(root.ConversationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error checking membership: %w", err)
+	}
+
+	if !isMember {
+		return nil, fmt.Errorf("you are not a member of this conversation")
+	}
+
+	// ช่อง (channel) โพสต์ได้เฉพาะ owner/admin
+	conversation, err := checkPostPermission(s.conversationRepo, root.ConversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateReplyPayload(messageType, content, mediaURL); err != nil {
+		return nil, err
+	}
+
+	// รัน content filter กับข้อความ/คำบรรยายก่อนบันทึก
+	filtered, err := s.filterContent(root.ConversationID, &userID, content, false)
+	if err != nil {
+		return nil, err
+	}
+	content = filtered.Content
+
+	// สร้างข้อความในเธรด
+	now := time.Now()
+	message := &models.Message{
+		ID:                uuid.New(),
+		ConversationID:    root.ConversationID,
+		SenderID:          &userID,
+		SenderType:        "user",
+		MessageType:       messageType,
+		Content:           content,
+		MediaURL:          mediaURL,
+		MediaThumbnailURL: thumbnailURL,
+		ReplyToID:         replyToID,
+		ThreadRootID:      &root.ID,
+		Metadata:          s.convertMetadataToJSON(metadata),
+		CreatedAt:         now,
+		UpdatedAt:         now,
+		ExpiresAt:         conversation.MessageExpiresAt(now),
+	}
+
+	if err := s.messageRepo.Create(message); err != nil {
+		return nil, fmt.Errorf("error creating message: %w", err)
+	}
+
+	// อัปเดตจำนวนข้อความและข้อความล่าสุดของเธรด (ไม่แตะ LastMessageText ของการสนทนา)
+	if err := s.messageRepo.RefreshThreadStats(root.ID); err != nil {
+		fmt.Printf("Error refreshing thread stats: %v, threadRootID: %s\n", err, root.ID.String())
+	}
+
+	// ผู้ส่งถือว่าอ่านเธรดแล้ว
+	if err := s.threadReadRepo.MarkRead(root.ID, userID, now); err != nil {
+		fmt.Printf("Error marking thread read: %v, threadRootID: %s, userID: %s\n", err, root.ID.String(), userID)
+	}
+
+	// สร้างบันทึกการอ่านสำหรับผู้ส่ง
+	if err := s.createMessageRead(message.ID, userID); err != nil {
+		fmt.Printf("Error creating read record: %v, messageID: %s, userID: %s\n", err, message.ID.String(), userID)
+	}
+
+	// ส่งข้อความที่ถูก flag เข้าคิวตรวจสอบ
+	s.reportContentFlags(message, filtered)
+
+	return message, nil
+}
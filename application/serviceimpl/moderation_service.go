@@ -0,0 +1,374 @@
+// application/serviceimpl/moderation_service.go
+package serviceimpl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+const maxReportDescriptionLength = 1000
+
+// reportStatusTransitions การเปลี่ยนสถานะที่ทำได้ผ่าน UpdateReportStatus (resolved ได้จาก TakeAction เท่านั้น)
+var reportStatusTransitions = map[string][]string{
+	models.ReportStatusOpen:      {models.ReportStatusInReview, models.ReportStatusDismissed},
+	models.ReportStatusInReview:  {models.ReportStatusOpen, models.ReportStatusDismissed},
+	models.ReportStatusResolved:  {models.ReportStatusOpen},
+	models.ReportStatusDismissed: {models.ReportStatusOpen},
+}
+
+type moderationService struct {
+	reportRepo       repository.ModerationReportRepository
+	messageRepo      repository.MessageRepository
+	userRepo         repository.UserRepository
+	conversationRepo repository.ConversationRepository
+	messageService   service.MessageService
+	adminService     service.AdminService
+}
+
+// NewModerationService สร้าง service ใหม่
+func NewModerationService(
+	reportRepo repository.ModerationReportRepository,
+	messageRepo repository.MessageRepository,
+	userRepo repository.UserRepository,
+	conversationRepo repository.ConversationRepository,
+	messageService service.MessageService,
+	adminService service.AdminService,
+) service.ModerationService {
+	return &moderationService{
+		reportRepo:       reportRepo,
+		messageRepo:      messageRepo,
+		userRepo:         userRepo,
+		conversationRepo: conversationRepo,
+		messageService:   messageService,
+		adminService:     adminService,
+	}
+}
+
+// ReportMessage รายงานข้อความ (ต้องเป็นสมาชิกของการสนทนา) เก็บสำเนาเนื้อหาไว้ตรวจสอบ
+func (s *moderationService) ReportMessage(reporterID, messageID uuid.UUID, req *dto.CreateReportRequest) (*dto.ReportDTO, error) {
+	if err := validateReportRequest(req); err != nil {
+		return nil, err
+	}
+
+	message, err := s.messageRepo.GetByID(messageID)
+	if err != nil || message == nil || message.IsDeleted {
+		return nil, errors.New("message not found")
+	}
+
+	isMember, err := s.conversationRepo.IsMember(message.ConversationID, reporterID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New("you are not a member of this conversation")
+	}
+	if message.SenderID != nil && *message.SenderID == reporterID {
+		return nil, errors.New("you cannot report yourself")
+	}
+
+	snapshot := types.JSONB{
+		"message_type": message.MessageType,
+		"content":      message.Content,
+		"sender_type":  message.SenderType,
+		"created_at":   message.CreatedAt.Format(time.RFC3339),
+	}
+	if message.MediaURL != "" {
+		snapshot["media_url"] = message.MediaURL
+	}
+	if message.SenderID != nil {
+		snapshot["sender_id"] = message.SenderID.String()
+	}
+
+	return s.createReport(&models.ModerationReport{
+		ReporterID:     &reporterID,
+		Source:         models.ReportSourceUser,
+		TargetType:     models.ReportTargetMessage,
+		TargetID:       message.ID,
+		ReportedUserID: message.SenderID,
+		ConversationID: &message.ConversationID,
+		Reason:         req.Reason,
+		Description:    strings.TrimSpace(req.Description),
+		Snapshot:       snapshot,
+	})
+}
+
+// ReportUser รายงานผู้ใช้
+func (s *moderationService) ReportUser(reporterID, userID uuid.UUID, req *dto.CreateReportRequest) (*dto.ReportDTO, error) {
+	if err := validateReportRequest(req); err != nil {
+		return nil, err
+	}
+	if reporterID == userID {
+		return nil, errors.New("you cannot report yourself")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	return s.createReport(&models.ModerationReport{
+		ReporterID:     &reporterID,
+		Source:         models.ReportSourceUser,
+		TargetType:     models.ReportTargetUser,
+		TargetID:       user.ID,
+		ReportedUserID: &user.ID,
+		Reason:         req.Reason,
+		Description:    strings.TrimSpace(req.Description),
+		Snapshot: types.JSONB{
+			"username":          user.Username,
+			"display_name":      user.DisplayName,
+			"profile_image_url": user.ProfileImageURL,
+			"bio":               user.Bio,
+		},
+	})
+}
+
+// ReportConversation รายงานกลุ่มหรือช่อง (ต้องเป็นสมาชิก, แชทส่วนตัวให้รายงานผู้ใช้แทน)
+func (s *moderationService) ReportConversation(reporterID, conversationID uuid.UUID, req *dto.CreateReportRequest) (*dto.ReportDTO, error) {
+	if err := validateReportRequest(req); err != nil {
+		return nil, err
+	}
+
+	conversation, err := s.conversationRepo.GetByID(conversationID)
+	if err != nil || conversation == nil || !conversation.IsActive {
+		return nil, errors.New("conversation not found")
+	}
+	if conversation.Type == "direct" {
+		return nil, errors.New("direct conversations cannot be reported, report the user instead")
+	}
+
+	isMember, err := s.conversationRepo.IsMember(conversationID, reporterID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New("you are not a member of this conversation")
+	}
+
+	snapshot := types.JSONB{
+		"type":     conversation.Type,
+		"title":    conversation.Title,
+		"icon_url": conversation.IconURL,
+	}
+	if conversation.CreatorID != nil {
+		snapshot["creator_id"] = conversation.CreatorID.String()
+	}
+
+	return s.createReport(&models.ModerationReport{
+		ReporterID:     &reporterID,
+		Source:         models.ReportSourceUser,
+		TargetType:     models.ReportTargetConversation,
+		TargetID:       conversation.ID,
+		ConversationID: &conversation.ID,
+		Reason:         req.Reason,
+		Description:    strings.TrimSpace(req.Description),
+		Snapshot:       snapshot,
+	})
+}
+
+// ListReports ดึงคิวรายงาน (เก่าสุดก่อน)
+func (s *moderationService) ListReports(status, targetType, reason, source string, assigneeID *uuid.UUID, limit, offset int) ([]*models.ModerationReport, int64, error) {
+	return s.reportRepo.List(repository.ModerationReportFilter{
+		Status:     status,
+		TargetType: targetType,
+		Reason:     reason,
+		Source:     source,
+		AssigneeID: assigneeID,
+		Limit:      limit,
+		Offset:     offset,
+	})
+}
+
+// GetReport ดึงรายงาน
+func (s *moderationService) GetReport(reportID uuid.UUID) (*models.ModerationReport, error) {
+	report, err := s.reportRepo.FindByID(reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return nil, errors.New("report not found")
+	}
+	return report, nil
+}
+
+// UpdateReportStatus เปลี่ยนสถานะรายงาน
+// in_review = รับเรื่อง (ผู้เปลี่ยนเป็นผู้รับผิดชอบ), open = คืนเข้าคิว/เปิดใหม่, dismissed = ไม่พบการละเมิด
+func (s *moderationService) UpdateReportStatus(actorID, reportID uuid.UUID, status, note string) (*models.ModerationReport, error) {
+	note = strings.TrimSpace(note)
+	if len([]rune(note)) > maxReportDescriptionLength {
+		return nil, fmt.Errorf("note is too long (max %d characters)", maxReportDescriptionLength)
+	}
+	if status == models.ReportStatusResolved {
+		return nil, errors.New("use an enforcement action to resolve a report")
+	}
+
+	report, err := s.GetReport(reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := false
+	for _, next := range reportStatusTransitions[report.Status] {
+		if next == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("invalid status transition from %s to %s", report.Status, status)
+	}
+
+	now := time.Now()
+	switch status {
+	case models.ReportStatusInReview:
+		report.AssigneeID = &actorID
+		report.Assignee = nil
+
+	case models.ReportStatusOpen:
+		report.AssigneeID = nil
+		report.Assignee = nil
+		report.Action = models.ModerationActionNone
+		report.ResolvedBy = nil
+		report.ResolvedAt = nil
+		report.ResolutionNote = note
+
+	case models.ReportStatusDismissed:
+		if report.AssigneeID == nil {
+			report.AssigneeID = &actorID
+		}
+		report.Action = models.ModerationActionNone
+		report.ResolvedBy = &actorID
+		report.ResolvedAt = &now
+		report.ResolutionNote = note
+	}
+	report.Status = status
+
+	if err := s.reportRepo.Update(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// TakeAction ดำเนินการตามรายงานผ่าน service เดิม (ลบข้อความ/ระงับบัญชี/ปิดการสนทนา) แล้วปิดรายงานเป็น resolved
+func (s *moderationService) TakeAction(actorID uuid.UUID, actorRole string, reportID uuid.UUID, req *dto.ModerationActionRequest) (*models.ModerationReport, error) {
+	note := strings.TrimSpace(req.Note)
+
+	report, err := s.GetReport(reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report.IsClosed() {
+		return nil, errors.New("report is already closed")
+	}
+
+	// เป้าหมายที่ถูกดำเนินการ (ใช้ปิดรายงานอื่นของเป้าหมายเดียวกัน)
+	var enforcedType string
+	var enforcedID uuid.UUID
+
+	switch req.Action {
+	case models.ModerationActionDeleteMessage:
+		if report.TargetType != models.ReportTargetMessage {
+			return nil, errors.New("action is not applicable to this report")
+		}
+		if err := s.messageService.ModerateDeleteMessage(report.TargetID, actorID, note); err != nil {
+			return nil, err
+		}
+		enforcedType, enforcedID = models.ReportTargetMessage, report.TargetID
+
+	case models.ModerationActionSuspendUser:
+		if report.ReportedUserID == nil {
+			return nil, errors.New("action is not applicable to this report")
+		}
+		if _, err := s.adminService.SuspendUser(actorID, actorRole, *report.ReportedUserID, note); err != nil {
+			return nil, err
+		}
+		enforcedType, enforcedID = models.ReportTargetUser, *report.ReportedUserID
+
+	case models.ModerationActionDisableConversation:
+		if report.ConversationID == nil {
+			return nil, errors.New("action is not applicable to this report")
+		}
+		if _, err := s.adminService.DisableConversation(actorID, *report.ConversationID, note); err != nil {
+			return nil, err
+		}
+		enforcedType, enforcedID = models.ReportTargetConversation, *report.ConversationID
+
+	default:
+		return nil, errors.New("invalid moderation action")
+	}
+
+	now := time.Now()
+	if report.AssigneeID == nil {
+		report.AssigneeID = &actorID
+	}
+	report.Status = models.ReportStatusResolved
+	report.Action = req.Action
+	report.ResolutionNote = note
+	report.ResolvedBy = &actorID
+	report.ResolvedAt = &now
+
+	if err := s.reportRepo.Update(report); err != nil {
+		return nil, fmt.Errorf("action applied but failed to update report: %w", err)
+	}
+
+	// รายงานซ้ำของเป้าหมายเดียวกันไม่ต้องตรวจสอบอีก
+	targets := map[string]uuid.UUID{report.TargetType: report.TargetID}
+	targets[enforcedType] = enforcedID
+	for targetType, targetID := range targets {
+		if _, err := s.reportRepo.ResolveOpenByTarget(targetType, targetID, report.ID, req.Action, note, actorID, now); err != nil {
+			fmt.Printf("Error resolving duplicate reports: %v, targetType: %s, targetID: %s\n", err, targetType, targetID)
+		}
+	}
+
+	return report, nil
+}
+
+// createReport บันทึกรายงาน (ไม่ให้รายงานเป้าหมายเดิมซ้ำขณะที่รายงานก่อนหน้ายังไม่ปิด)
+func (s *moderationService) createReport(report *models.ModerationReport) (*dto.ReportDTO, error) {
+	exists, err := s.reportRepo.HasOpenReport(report.ReporterID, report.TargetType, report.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("you have already reported this")
+	}
+
+	report.Status = models.ReportStatusOpen
+	if err := s.reportRepo.Create(report); err != nil {
+		return nil, err
+	}
+
+	return &dto.ReportDTO{
+		ID:         report.ID,
+		TargetType: report.TargetType,
+		TargetID:   report.TargetID,
+		Reason:     report.Reason,
+		Status:     report.Status,
+		CreatedAt:  report.CreatedAt,
+	}, nil
+}
+
+// validateReportRequest ตรวจสอบ reason code และรายละเอียด
+func validateReportRequest(req *dto.CreateReportRequest) error {
+	if !models.IsValidReportReason(req.Reason) {
+		return errors.New("invalid report reason")
+	}
+
+	description := strings.TrimSpace(req.Description)
+	if len([]rune(description)) > maxReportDescriptionLength {
+		return fmt.Errorf("description is too long (max %d characters)", maxReportDescriptionLength)
+	}
+	if req.Reason == models.ReportReasonOther && description == "" {
+		return errors.New("description is required when reason is other")
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+// application/serviceimpl/notification_inbox_service.go
+package serviceimpl
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/port"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+)
+
+// จำนวนรายการสูงสุดที่ทำเครื่องหมายว่าอ่านแล้วได้ในคำขอเดียว
+const maxNotificationMarkReadIDs = 100
+
+type notificationInboxService struct {
+	notificationRepo repository.NotificationRepository
+	wsPort           port.WebSocketPort
+}
+
+// NewNotificationInboxService สร้าง service สำหรับกล่องแจ้งเตือน
+func NewNotificationInboxService(
+	notificationRepo repository.NotificationRepository,
+	wsPort port.WebSocketPort,
+) service.NotificationInboxService {
+	return &notificationInboxService{
+		notificationRepo: notificationRepo,
+		wsPort:           wsPort,
+	}
+}
+
+// ListNotifications ดึงการแจ้งเตือนของผู้ใช้ ล่าสุดก่อน
+func (s *notificationInboxService) ListNotifications(userID uuid.UUID, limit int, cursor *string, unreadOnly bool) ([]*dto.NotificationDTO, *string, bool, error) {
+	notifications, nextCursor, hasMore, err := s.notificationRepo.ListByUserID(userID, limit, cursor, unreadOnly)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	result := make([]*dto.NotificationDTO, 0, len(notifications))
+	for _, n := range notifications {
+		result = append(result, toNotificationDTO(n))
+	}
+	return result, nextCursor, hasMore, nil
+}
+
+// GetUnreadCount จำนวนที่ยังไม่ได้อ่าน (badge)
+func (s *notificationInboxService) GetUnreadCount(userID uuid.UUID) (int64, error) {
+	return s.notificationRepo.CountUnread(userID)
+}
+
+// MarkRead ทำเครื่องหมายว่าอ่านแล้วตาม ID (ID ของผู้อื่นหรือที่อ่านแล้วจะถูกข้าม)
+func (s *notificationInboxService) MarkRead(userID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, errors.New("notification ids are required")
+	}
+	if len(ids) > maxNotificationMarkReadIDs {
+		return 0, errors.New("too many notification ids")
+	}
+
+	updated, err := s.notificationRepo.MarkRead(userID, ids, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return s.afterRead(userID, updated, map[string]interface{}{"ids": ids})
+}
+
+// MarkAllRead ทำเครื่องหมายว่าอ่านแล้วทั้งหมด
+func (s *notificationInboxService) MarkAllRead(userID uuid.UUID) (int64, error) {
+	updated, err := s.notificationRepo.MarkAllRead(userID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return s.afterRead(userID, updated, map[string]interface{}{"all": true})
+}
+
+// DeleteNotification ลบการแจ้งเตือนออกจากกล่อง
+func (s *notificationInboxService) DeleteNotification(userID, notificationID uuid.UUID) error {
+	deleted, err := s.notificationRepo.Delete(userID, notificationID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("notification not found")
+	}
+	return nil
+}
+
+// afterRead นับ badge ใหม่ และแจ้งอุปกรณ์อื่นของผู้ใช้ถ้ามีรายการเปลี่ยนจริง
+func (s *notificationInboxService) afterRead(userID uuid.UUID, updated int64, data map[string]interface{}) (int64, error) {
+	unread, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return 0, err
+	}
+	if updated > 0 {
+		data["unread_count"] = unread
+		s.wsPort.BroadcastNotificationRead(userID, data)
+	}
+	return unread, nil
+}
+
+// toNotificationDTO แปลง model เป็น DTO (Actor ต้อง preload มาแล้วถ้าต้องการข้อมูลผู้กระทำ)
+func toNotificationDTO(n *models.Notification) *dto.NotificationDTO {
+	result := &dto.NotificationDTO{
+		ID:             n.ID,
+		Type:           n.Type,
+		TargetType:     n.TargetType,
+		TargetID:       n.TargetID,
+		ConversationID: n.ConversationID,
+		Payload:        n.Payload,
+		IsRead:         n.ReadAt != nil,
+		ReadAt:         n.ReadAt,
+		CreatedAt:      n.CreatedAt,
+	}
+	if result.Payload == nil {
+		result.Payload = map[string]interface{}{}
+	}
+	if n.Actor != nil {
+		result.Actor = &dto.UserInfoDTO{
+			ID:              n.Actor.ID.String(),
+			Username:        n.Actor.Username,
+			DisplayName:     n.Actor.DisplayName,
+			ProfileImageURL: n.Actor.ProfileImageURL,
+		}
+	}
+	return result
+}
@@ -0,0 +1,134 @@
+// application/serviceimpl/notification_policy.go
+package serviceimpl
+
+import (
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// เหตุผลที่ผู้ใช้ได้รับการแจ้งเตือนจากข้อความ (ใช้เป็น kind ของ push และกล่องแจ้งเตือน)
+const (
+	notifyReasonMessage = "message"
+	notifyReasonMention = "mention"
+	notifyReasonKeyword = "keyword"
+)
+
+// notificationDecision ผลการตัดสินใจว่าจะแจ้งเตือนสมาชิกคนหนึ่งหรือไม่
+type notificationDecision struct {
+	Notify  bool
+	Reason  string // message | mention | keyword
+	Keyword string // keyword ที่ตรง (เมื่อ Reason = keyword)
+	Silent  bool   // อยู่ในช่วง quiet hours: แสดงในแอปได้ แต่ไม่ส่ง push
+}
+
+// decideMessageNotification จุดเดียวที่ตัดสินว่าสมาชิกจะได้รับการแจ้งเตือนจากข้อความหรือไม่
+//   - level "none" ไม่แจ้งเตือนเลย
+//   - ปิดเสียงอยู่ (ยังไม่หมดเวลา) หรือ level "mentions" แจ้งเฉพาะ mention/keyword alert
+//   - quiet hours ของผู้รับทำให้เป็นการแจ้งเตือนแบบเงียบ (ไม่ส่ง push)
+//
+// recipient คือ member.User (อาจเป็น nil ถ้าไม่ได้ preload ซึ่งจะข้าม keyword alert และ quiet hours)
+func decideMessageNotification(member *models.ConversationMember, message *models.Message, mentioned bool, now time.Time) notificationDecision {
+	settings := member.NotificationPreferences()
+	if settings.Level == models.NotificationLevelNone {
+		return notificationDecision{}
+	}
+
+	var userPrefs models.UserNotificationPreferences
+	if member.User != nil {
+		userPrefs = member.User.NotificationPreferences()
+	}
+
+	decision := notificationDecision{Notify: true, Reason: notifyReasonMessage}
+	if mentioned {
+		decision.Reason = notifyReasonMention
+	} else if keyword, ok := userPrefs.MatchKeyword(keywordSearchableText(message)); ok {
+		decision.Reason = notifyReasonKeyword
+		decision.Keyword = keyword
+	}
+
+	if decision.Reason == notifyReasonMessage &&
+		(settings.Level == models.NotificationLevelMentions || member.IsMutedAt(now)) {
+		return notificationDecision{}
+	}
+
+	decision.Silent = userPrefs.QuietHours.ActiveAt(now)
+	return decision
+}
+
+// keywordSearchableText ข้อความที่ใช้ตรวจ keyword alert (เฉพาะข้อความที่เซิร์ฟเวอร์อ่านได้)
+func keywordSearchableText(message *models.Message) string {
+	if message == nil || message.MessageType == models.MessageTypeEncrypted || message.MessageType == "system" {
+		return ""
+	}
+	return message.Content
+}
+
+// mentionRecipients กรองผู้ถูก mention ตามการตั้งค่าการแจ้งเตือนของแต่ละคนในการสนทนา (ดึงสมาชิกในคำสั่งเดียว)
+func (s *notificationService) mentionRecipients(conversationID uuid.UUID, userIDs []uuid.UUID) []uuid.UUID {
+	members, err := s.conversationRepo.GetMembersByUserIDs(conversationID, userIDs)
+	if err != nil {
+		log.Printf("Error getting mentioned members: %v", err)
+		return nil
+	}
+
+	now := time.Now()
+	result := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		if decideMessageNotification(member, nil, true, now).Notify {
+			result = append(result, member.UserID)
+		}
+	}
+	return result
+}
+
+// notifyKeywordAlerts บันทึก keyword alert ให้สมาชิกที่ข้อความตรงกับ keyword ที่ตั้งไว้ (ทำงานแบบ async)
+// ผู้ถูก mention ได้รับการแจ้งเตือน mention อยู่แล้วจึงไม่ได้รับซ้ำ
+func (s *notificationService) notifyKeywordAlerts(message *models.Message) {
+	if s.notificationRepo == nil || keywordSearchableText(message) == "" {
+		return
+	}
+
+	go func() {
+		// อ่านเฉพาะสมาชิกที่ตั้ง keyword alert ไว้ ไม่ใช่สมาชิกทั้งกลุ่ม
+		members, err := s.conversationRepo.GetKeywordAlertMembers(message.ConversationID)
+		if err != nil {
+			log.Printf("Error getting members for keyword alerts: %v", err)
+			return
+		}
+
+		mentioned := mentionedUserIDs(message)
+		now := time.Now()
+		for _, member := range members {
+			if message.SenderID != nil && member.UserID == *message.SenderID {
+				continue
+			}
+
+			decision := decideMessageNotification(member, message, mentioned[member.UserID], now)
+			if !decision.Notify || decision.Reason != notifyReasonKeyword {
+				continue
+			}
+
+			payload := map[string]interface{}{
+				"keyword":         decision.Keyword,
+				"message_id":      message.ID.String(),
+				"conversation_id": message.ConversationID.String(),
+				"message_preview": truncateString(message.Content, 100),
+			}
+			if message.SenderID != nil {
+				payload["sender_id"] = message.SenderID.String()
+			}
+
+			s.storeInbox([]uuid.UUID{member.UserID}, inboxEntry{
+				Type:           models.NotificationTypeKeywordAlert,
+				ActorID:        message.SenderID,
+				TargetType:     models.NotificationTargetMessage,
+				TargetID:       &message.ID,
+				ConversationID: &message.ConversationID,
+				Payload:        payload,
+			})
+		}
+	}()
+}
@@ -4,6 +4,7 @@ package serviceimpl
 import (
 	"encoding/json"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,6 +13,7 @@ import (
 	"github.com/thizplus/gofiber-chat-api/domain/port"
 	"github.com/thizplus/gofiber-chat-api/domain/repository"
 	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
 )
 
 // notificationService เป็น implementation ของ NotificationService interface
@@ -20,6 +22,9 @@ type notificationService struct {
 	userRepo            repository.UserRepository
 	messageRepo         repository.MessageRepository
 	conversationRepo    repository.ConversationRepository
+	pushService         service.PushService
+	botWebhookService   service.BotWebhookService
+	notificationRepo    repository.NotificationRepository // กล่องแจ้งเตือน (บันทึกรายการที่ผู้ใช้ต้องเห็นแม้ออฟไลน์)
 }
 
 // NewNotificationService สร้าง instance ใหม่ของ NotificationService
@@ -28,12 +33,18 @@ func NewNotificationService(
 	userRepo repository.UserRepository,
 	messageRepo repository.MessageRepository,
 	conversationRepo repository.ConversationRepository,
+	pushService service.PushService,
+	botWebhookService service.BotWebhookService,
+	notificationRepo repository.NotificationRepository,
 ) service.NotificationService {
 	return &notificationService{
 		wsPort:              wsPort,
 		userRepo:            userRepo,
 		messageRepo:         messageRepo,
 		conversationRepo:    conversationRepo,
+		pushService:         pushService,
+		botWebhookService:   botWebhookService,
+		notificationRepo:    notificationRepo,
 	}
 }
 
@@ -49,6 +60,42 @@ func (s *notificationService) NotifyNewMessage(conversationID uuid.UUID, message
 		return
 	}
 
+	messageDTO := s.buildMessageDTO(message)
+
+	data, _ := json.MarshalIndent(messageDTO, "", "  ")
+	fmt.Println("[DEBUGXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX] CHECK REPLY TO MESSAGE messageDTO:", string(data))
+
+	// ส่งแจ้งเตือนผ่าน WebSocket
+	s.wsPort.BroadcastNewMessage(message.ConversationID, messageDTO)
+
+	// ส่ง push ไปยังสมาชิกที่ออฟไลน์
+	s.notifyPush(message)
+
+	// ส่ง outgoing webhook ไปยังบอทในการสนทนา
+	s.notifyBots(message)
+
+	// บันทึก keyword alert ลงกล่องแจ้งเตือนของสมาชิกที่ตั้ง keyword ไว้
+	s.notifyKeywordAlerts(message)
+}
+
+// notifyPush ส่ง push notification แบบ async (ข้ามถ้าไม่ได้ตั้งค่า PushService)
+func (s *notificationService) notifyPush(message *models.Message) {
+	if s.pushService == nil {
+		return
+	}
+	go s.pushService.NotifyNewMessage(message)
+}
+
+// notifyBots ส่ง webhook ไปยังบอทแบบ async (ข้ามถ้าไม่ได้ตั้งค่า BotWebhookService)
+func (s *notificationService) notifyBots(message *models.Message) {
+	if s.botWebhookService == nil {
+		return
+	}
+	go s.botWebhookService.NotifyNewMessage(message)
+}
+
+// buildMessageDTO สร้าง MessageDTO สำหรับส่งผ่าน WebSocket จาก models.Message
+func (s *notificationService) buildMessageDTO(message *models.Message) *dto.MessageDTO {
 	// คำนวณ read_count จากฐานข้อมูล
 	readCount := 1 // เริ่มต้นที่ 1 (ผู้ส่งอ่านเอง)
 	if message.ID != uuid.Nil {
@@ -93,7 +140,9 @@ func (s *notificationService) NotifyNewMessage(conversationID uuid.UUID, message
 		IsDeleted:         message.IsDeleted,
 		IsEdited:          message.IsEdited,
 		EditCount:         message.EditCount,
+		ExpiresAt:         message.ExpiresAt,
 		ReplyToID:         message.ReplyToID,
+		ThreadRootID:      message.ThreadRootID,
 		IsRead:            readCount >= 1,
 		ReadCount:         readCount,
 		Status:            status,
@@ -122,6 +171,11 @@ func (s *notificationService) NotifyNewMessage(conversationID uuid.UUID, message
 		}
 	}
 
+	// ข้อมูลโพล (มีเฉพาะตอนสร้างโพลใหม่ ยังไม่มีผลโหวต)
+	if message.Poll != nil {
+		messageDTO.Poll = buildPollDTO(message.Poll, nil, uuid.Nil)
+	}
+
 	// เพิ่มข้อมูลผู้ส่ง
 	if message.SenderID != nil {
 		sender, err := s.userRepo.FindByID(*message.SenderID)
@@ -142,6 +196,11 @@ func (s *notificationService) NotifyNewMessage(conversationID uuid.UUID, message
 		}
 	}
 
+	// ข้อความจาก incoming webhook ใช้ชื่อ/รูปที่กำหนดใน webhook หรือ payload
+	if message.SenderType == models.SenderTypeWebhook {
+		messageDTO.SenderName, messageDTO.SenderAvatar = webhookSenderInfo(message)
+	}
+
 
 	// เพิ่มข้อมูลการตอบกลับ (ถ้ามี)
 	if message.ReplyToID != nil {
@@ -196,11 +255,7 @@ func (s *notificationService) NotifyNewMessage(conversationID uuid.UUID, message
 		messageDTO.ForwardedFrom = forwardedFrom
 	}
 
-	data, _ := json.MarshalIndent(messageDTO, "", "  ")
-	fmt.Println("[DEBUGXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX] CHECK REPLY TO MESSAGE messageDTO:", string(data))
-
-	// ส่งแจ้งเตือนผ่าน WebSocket
-	s.wsPort.BroadcastNewMessage(message.ConversationID, messageDTO)
+	return messageDTO
 }
 
 // NotifyMessageRead แจ้งเตือนการอ่านข้อความ (เก่า - broadcast ไปทุกคน)
@@ -248,6 +303,42 @@ func (s *notificationService) NotifyMessageReaction(conversationID uuid.UUID, re
 	s.wsPort.BroadcastMessageReaction(conversationID, reaction)
 }
 
+// NotifyPollUpdated แจ้งเตือนผลโหวตล่าสุดของโพล
+func (s *notificationService) NotifyPollUpdated(conversationID uuid.UUID, poll interface{}) {
+	s.wsPort.BroadcastPollUpdated(conversationID, poll)
+}
+
+// NotifyKeyChanged แจ้งเตือนว่า key ของอุปกรณ์เปลี่ยนหรือถูกลบ
+func (s *notificationService) NotifyKeyChanged(userIDs []uuid.UUID, change interface{}) {
+	if len(userIDs) == 0 {
+		return
+	}
+	s.wsPort.BroadcastKeyChanged(userIDs, change)
+}
+
+// NotifyThreadReply แจ้งเตือนข้อความใหม่ในเธรด (thread.reply) พร้อมจำนวนข้อความล่าสุดของเธรด
+func (s *notificationService) NotifyThreadReply(conversationID, threadRootID uuid.UUID, messageData interface{}) {
+	payload := map[string]interface{}{
+		"conversation_id": conversationID.String(),
+		"thread_root_id":  threadRootID.String(),
+		"message":         messageData,
+	}
+
+	if message, ok := messageData.(*models.Message); ok {
+		payload["message"] = s.buildMessageDTO(message)
+		s.notifyPush(message)
+		s.notifyBots(message)
+	}
+
+	// แนบสถิติของเธรดเพื่อให้ client อัปเดต reply count ของข้อความต้นเธรดได้ทันที
+	if root, err := s.messageRepo.GetByID(threadRootID); err == nil && root != nil {
+		payload["reply_count"] = root.ThreadReplyCount
+		payload["last_reply_at"] = root.ThreadLastReplyAt
+	}
+
+	s.wsPort.BroadcastThreadReply(conversationID, payload)
+}
+
 // =========== Conversation Notifications ===========
 
 // NotifyConversationCreated แจ้งเตือนการสร้างการสนทนาใหม่
@@ -367,6 +458,27 @@ func (s *notificationService) NotifyUserRemovedFromConversation(userID uuid.UUID
 	s.wsPort.BroadcastUserRemovedFromConversation(userID, conversationID)
 }
 
+// NotifyJoinRequestReceived แจ้งแอดมินของกลุ่มว่ามีคำขอเข้าร่วมใหม่
+func (s *notificationService) NotifyJoinRequestReceived(adminIDs []uuid.UUID, request interface{}) {
+	if len(adminIDs) == 0 {
+		return
+	}
+	s.wsPort.BroadcastJoinRequestReceived(adminIDs, request)
+	s.storeInbox(adminIDs, joinRequestInboxEntry(models.NotificationTypeJoinRequestReceived, request))
+}
+
+// NotifyJoinRequestApproved แจ้งผู้ขอว่าได้รับการอนุมัติให้เข้ากลุ่ม
+func (s *notificationService) NotifyJoinRequestApproved(userID uuid.UUID, request interface{}) {
+	s.wsPort.BroadcastJoinRequestApproved(userID, request)
+	s.storeInbox([]uuid.UUID{userID}, joinRequestInboxEntry(models.NotificationTypeJoinRequestApproved, request))
+}
+
+// NotifyJoinRequestRejected แจ้งผู้ขอว่าคำขอเข้ากลุ่มถูกปฏิเสธ
+func (s *notificationService) NotifyJoinRequestRejected(userID uuid.UUID, request interface{}) {
+	s.wsPort.BroadcastJoinRequestRejected(userID, request)
+	s.storeInbox([]uuid.UUID{userID}, joinRequestInboxEntry(models.NotificationTypeJoinRequestRejected, request))
+}
+
 // NotifyNewConversation แจ้งเตือนการสนทนาใหม่
 func (s *notificationService) NotifyNewConversation(conversation interface{}) error {
 	conversationData, ok := conversation.(*dto.ConversationDTO)
@@ -439,6 +551,14 @@ func (s *notificationService) NotifyFriendRequestReceived(request interface{}) e
 		"created_at": friendshipData.RequestedAt.Format(time.RFC3339),
 	}
 
+	s.storeInbox([]uuid.UUID{friendshipData.FriendID}, inboxEntry{
+		Type:       models.NotificationTypeFriendRequestReceived,
+		Actor:      sender,
+		TargetType: models.NotificationTargetFriendRequest,
+		TargetID:   &friendshipData.ID,
+		Payload:    notificationData,
+	})
+
 	return s.wsPort.BroadcastFriendRequestReceived(friendshipData.FriendID, notificationData)
 }
 
@@ -468,6 +588,14 @@ func (s *notificationService) NotifyFriendRequestAccepted(friendship interface{}
 		"accepted_at": friendshipData.UpdatedAt.Format(time.RFC3339),
 	}
 
+	s.storeInbox([]uuid.UUID{friendshipData.UserID}, inboxEntry{
+		Type:       models.NotificationTypeFriendRequestAccepted,
+		Actor:      acceptor,
+		TargetType: models.NotificationTargetFriendRequest,
+		TargetID:   &friendshipData.ID,
+		Payload:    notificationData,
+	})
+
 	// ส่งการแจ้งเตือนไปยังผู้ส่งคำขอเดิม (userID)
 	return s.wsPort.BroadcastFriendRequestAccepted(friendshipData.UserID, notificationData)
 }
@@ -510,13 +638,24 @@ func (s *notificationService) NotifyUserUnblocked(unblockerID uuid.UUID, unblock
 // =========== General Notifications ===========
 
 // SendNotification ส่งการแจ้งเตือนทั่วไปไปยังผู้ใช้หลายคน
+// การแจ้งเตือน mention เคารพการตั้งค่าการแจ้งเตือนของผู้รับในการสนทนานั้น
 func (s *notificationService) SendNotification(userIDs []uuid.UUID, notification interface{}) {
+	entry := genericInboxEntry(models.NotificationTypeGeneral, notification)
+	if entry.Type == models.NotificationTypeMention && entry.ConversationID != nil {
+		userIDs = s.mentionRecipients(*entry.ConversationID, userIDs)
+		if len(userIDs) == 0 {
+			return
+		}
+	}
+
 	s.wsPort.BroadcastNotification(userIDs, notification)
+	s.storeInbox(userIDs, entry)
 }
 
 // SendAlert ส่งการแจ้งเตือนสำคัญไปยังผู้ใช้
 func (s *notificationService) SendAlert(userID uuid.UUID, alert interface{}) {
 	s.wsPort.BroadcastAlert(userID, alert)
+	s.storeInbox([]uuid.UUID{userID}, genericInboxEntry(models.NotificationTypeAlert, alert))
 }
 
 // NotifySystemMessage ส่งข้อความระบบไปยังผู้ใช้หลายคน
@@ -541,6 +680,7 @@ func (s *notificationService) NotifyMemberRoleChanged(conversationID, userID uui
 			"changed_at":       time.Now().Format(time.RFC3339),
 		}
 		s.wsPort.BroadcastMemberRoleChanged(conversationID, notificationData)
+		s.storeRoleChanged(conversationID, userID, changedByUserID, nil, notificationData)
 		return
 	}
 
@@ -572,6 +712,9 @@ func (s *notificationService) NotifyMemberRoleChanged(conversationID, userID uui
 
 	// ส่ง notification ไปยังสมาชิกทุกคนในกลุ่ม
 	s.wsPort.BroadcastMemberRoleChanged(conversationID, notificationData)
+
+	// บันทึกลงกล่องแจ้งเตือนของผู้ที่ถูกเปลี่ยน role เท่านั้น
+	s.storeRoleChanged(conversationID, userID, changedByUserID, changedBy, notificationData)
 }
 
 // NotifyOwnershipTransferred แจ้งเตือนการโอนความเป็นเจ้าของ
@@ -616,6 +759,17 @@ func (s *notificationService) NotifyOwnershipTransferred(conversationID, previou
 
 	// ส่ง notification ไปยังสมาชิกทุกคนในกลุ่ม
 	s.wsPort.BroadcastOwnershipTransferred(conversationID, notificationData)
+
+	// บันทึกลงกล่องแจ้งเตือนของ owner ใหม่
+	s.storeInbox([]uuid.UUID{newOwnerID}, inboxEntry{
+		Type:           models.NotificationTypeOwnershipTransferred,
+		Actor:          previousOwner,
+		ActorID:        &previousOwnerID,
+		TargetType:     models.NotificationTargetConversation,
+		TargetID:       &conversationID,
+		ConversationID: &conversationID,
+		Payload:        notificationData,
+	})
 }
 
 // NotifyNewActivity แจ้งเตือน activity ใหม่ในกลุ่ม
@@ -645,3 +799,184 @@ func (s *notificationService) NotifyProfileUpdateTags(businessID, userID uuid.UU
 	// ส่งข้อมูลไปยังทุกคนในธุรกิจโดยใช้ BroadcastToBusiness โดยตรง
 	s.wsPort.BroadcastProfileUpdateTags(businessID, userID, payload)
 }
+
+// =========== Notification Inbox ===========
+
+// inboxEntry ข้อมูลของรายการที่จะบันทึกลงกล่องแจ้งเตือน
+type inboxEntry struct {
+	Type           string
+	Actor          *models.User // ถ้ามีอยู่แล้วจะไม่ต้องดึงซ้ำ
+	ActorID        *uuid.UUID
+	TargetType     string
+	TargetID       *uuid.UUID
+	ConversationID *uuid.UUID
+	Payload        interface{}
+}
+
+// storeInbox บันทึกการแจ้งเตือนให้ผู้รับแต่ละคน แล้วส่ง notification.new พร้อม unread_count
+// (ข้ามผู้กระทำเอง และไม่ทำให้การแจ้งเตือนแบบ realtime เดิมล้มเหลวถ้าบันทึกไม่สำเร็จ)
+func (s *notificationService) storeInbox(userIDs []uuid.UUID, entry inboxEntry) {
+	if s.notificationRepo == nil || len(userIDs) == 0 {
+		return
+	}
+
+	if entry.ActorID == nil && entry.Actor != nil {
+		entry.ActorID = &entry.Actor.ID
+	}
+	payload := toInboxPayload(entry.Payload)
+
+	now := time.Now()
+	seen := make(map[uuid.UUID]bool, len(userIDs))
+	notifications := make([]*models.Notification, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if seen[userID] || (entry.ActorID != nil && *entry.ActorID == userID) {
+			continue
+		}
+		seen[userID] = true
+		notifications = append(notifications, &models.Notification{
+			ID:             uuid.New(),
+			UserID:         userID,
+			Type:           entry.Type,
+			ActorID:        entry.ActorID,
+			TargetType:     entry.TargetType,
+			TargetID:       entry.TargetID,
+			ConversationID: entry.ConversationID,
+			Payload:        payload,
+			CreatedAt:      now,
+		})
+	}
+	if len(notifications) == 0 {
+		return
+	}
+
+	if err := s.notificationRepo.CreateBatch(notifications); err != nil {
+		log.Printf("Error storing %s notifications: %v", entry.Type, err)
+		return
+	}
+
+	actor := entry.Actor
+	if actor == nil && entry.ActorID != nil {
+		actor, _ = s.userRepo.FindByID(*entry.ActorID)
+	}
+
+	for _, n := range notifications {
+		n.Actor = actor
+		unread, err := s.notificationRepo.CountUnread(n.UserID)
+		if err != nil {
+			log.Printf("Error counting unread notifications for %s: %v", n.UserID, err)
+		}
+		s.wsPort.BroadcastNotificationNew(n.UserID, map[string]interface{}{
+			"notification": toNotificationDTO(n),
+			"unread_count": unread,
+		})
+	}
+}
+
+// storeRoleChanged บันทึกการเปลี่ยน role ลงกล่องแจ้งเตือนของสมาชิกที่ถูกเปลี่ยน
+func (s *notificationService) storeRoleChanged(conversationID, userID, changedByUserID uuid.UUID, changedBy *models.User, data map[string]interface{}) {
+	s.storeInbox([]uuid.UUID{userID}, inboxEntry{
+		Type:           models.NotificationTypeMemberRoleChanged,
+		Actor:          changedBy,
+		ActorID:        &changedByUserID,
+		TargetType:     models.NotificationTargetConversation,
+		TargetID:       &conversationID,
+		ConversationID: &conversationID,
+		Payload:        data,
+	})
+}
+
+// joinRequestInboxEntry สร้างรายการจาก JoinRequestDTO (ผู้กระทำคือผู้ขอ หรือผู้พิจารณาคำขอ)
+func joinRequestInboxEntry(notificationType string, request interface{}) inboxEntry {
+	entry := inboxEntry{
+		Type:       notificationType,
+		TargetType: models.NotificationTargetJoinRequest,
+		Payload:    request,
+	}
+
+	joinRequest, ok := request.(*dto.JoinRequestDTO)
+	if !ok {
+		return entry
+	}
+	entry.TargetID = &joinRequest.ID
+	entry.ConversationID = &joinRequest.ConversationID
+	if notificationType == models.NotificationTypeJoinRequestReceived {
+		if joinRequest.User != nil {
+			if actorID, err := uuid.Parse(joinRequest.User.ID); err == nil {
+				entry.ActorID = &actorID
+			}
+		}
+	} else {
+		entry.ActorID = joinRequest.ReviewedBy
+	}
+	return entry
+}
+
+// genericInboxEntry สร้างรายการจาก payload ของ SendNotification/SendAlert
+// ใช้ key มาตรฐานใน payload ถ้ามี: type, actor_id/sender_id, conversation_id, message_id
+func genericInboxEntry(defaultType string, notification interface{}) inboxEntry {
+	entry := inboxEntry{Type: defaultType, Payload: notification}
+
+	data, ok := notification.(map[string]interface{})
+	if !ok {
+		if jsonb, isJSONB := notification.(types.JSONB); isJSONB {
+			data, ok = jsonb, true
+		}
+	}
+	if !ok {
+		return entry
+	}
+
+	if t, ok := data["type"].(string); ok && t != "" && len(t) <= 50 {
+		entry.Type = t
+	}
+	entry.ActorID = uuidFromPayload(data, "actor_id")
+	if entry.ActorID == nil {
+		entry.ActorID = uuidFromPayload(data, "sender_id")
+	}
+	entry.ConversationID = uuidFromPayload(data, "conversation_id")
+	if messageID := uuidFromPayload(data, "message_id"); messageID != nil {
+		entry.TargetType = models.NotificationTargetMessage
+		entry.TargetID = messageID
+	}
+	return entry
+}
+
+// uuidFromPayload อ่าน UUID จาก payload (รองรับทั้ง string และ uuid.UUID)
+func uuidFromPayload(data map[string]interface{}, key string) *uuid.UUID {
+	switch v := data[key].(type) {
+	case uuid.UUID:
+		return &v
+	case *uuid.UUID:
+		return v
+	case string:
+		if id, err := uuid.Parse(v); err == nil {
+			return &id
+		}
+	}
+	return nil
+}
+
+// toInboxPayload แปลงข้อมูลใดๆ เป็น JSONB (ค่าที่ไม่ใช่ object จะถูกเก็บไว้ใต้ key "data")
+func toInboxPayload(payload interface{}) types.JSONB {
+	switch v := payload.(type) {
+	case nil:
+		return types.JSONB{}
+	case types.JSONB:
+		return v
+	case map[string]interface{}:
+		return types.JSONB(v)
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return types.JSONB{}
+	}
+	var result types.JSONB
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return types.JSONB{"data": json.RawMessage(raw)}
+	}
+	if result == nil {
+		return types.JSONB{}
+	}
+	return result
+}
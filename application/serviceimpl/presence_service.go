@@ -4,6 +4,7 @@ package serviceimpl
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -16,39 +17,40 @@ type presenceService struct {
 	redis              *redis.Client
 	userRepo           repository.UserRepository
 	userFriendshipRepo repository.UserFriendshipRepository
+	nodeID             string
 	ctx                context.Context
 }
 
 const (
 	// Redis key prefixes
-	onlineKeyPrefix = "user:online:"
+	// user:presence:<userID> เป็น HASH ของ nodeID -> เวลาหมดอายุ (unix) ของ API instance ที่ผู้ใช้เชื่อมต่ออยู่
+	onlineKeyPrefix = "user:presence:"
 	lastSeenPrefix  = "user:lastseen:"
 
-	// TTL for online status (5 minutes)
+	// TTL for online status (5 minutes) - hub ต่ออายุทุกรอบ heartbeat
 	onlineTTL = 5 * time.Minute
 )
 
 // NewPresenceService creates a new PresenceService
+// nodeID ระบุ API instance นี้ เพื่อไม่ให้ instance เดียวที่ตัดการเชื่อมต่อทำให้ผู้ใช้กลายเป็นออฟไลน์
 func NewPresenceService(
 	redis *redis.Client,
 	userRepo repository.UserRepository,
 	userFriendshipRepo repository.UserFriendshipRepository,
+	nodeID string,
 ) service.PresenceService {
 	return &presenceService{
 		redis:              redis,
 		userRepo:           userRepo,
 		userFriendshipRepo: userFriendshipRepo,
+		nodeID:             nodeID,
 		ctx:                context.Background(),
 	}
 }
 
-// SetUserOnline marks a user as online in Redis
+// SetUserOnline marks a user as online on this node in Redis
 func (s *presenceService) SetUserOnline(userID uuid.UUID) error {
-	key := onlineKeyPrefix + userID.String()
-
-	// Set with TTL
-	err := s.redis.Set(s.ctx, key, "1", onlineTTL).Err()
-	if err != nil {
+	if err := s.RefreshOnline([]uuid.UUID{userID}); err != nil {
 		return fmt.Errorf("failed to set user online: %w", err)
 	}
 
@@ -56,28 +58,70 @@ func (s *presenceService) SetUserOnline(userID uuid.UUID) error {
 	return s.UpdateLastActive(userID)
 }
 
-// SetUserOffline marks a user as offline
+// SetUserOffline removes this node from the user's presence
+// ผู้ใช้จะออฟไลน์จริงก็ต่อเมื่อไม่มี node อื่นที่ยังเชื่อมต่ออยู่
 func (s *presenceService) SetUserOffline(userID uuid.UUID) error {
 	key := onlineKeyPrefix + userID.String()
 
-	// Delete online key
-	err := s.redis.Del(s.ctx, key).Err()
-	if err != nil {
+	if err := s.redis.HDel(s.ctx, key, s.nodeID).Err(); err != nil {
 		return fmt.Errorf("failed to set user offline: %w", err)
 	}
 
-	// Store last seen time
-	lastSeenKey := lastSeenPrefix + userID.String()
-	now := time.Now().Unix()
-	err = s.redis.Set(s.ctx, lastSeenKey, now, 0).Err() // No expiry
+	online, err := s.IsUserOnline(userID)
 	if err != nil {
-		return fmt.Errorf("failed to store last seen: %w", err)
+		return err
+	}
+
+	if !online {
+		// Delete presence key (อาจเหลือ node ที่หมดอายุแล้ว)
+		if err := s.redis.Del(s.ctx, key).Err(); err != nil {
+			return fmt.Errorf("failed to set user offline: %w", err)
+		}
+
+		// Store last seen time
+		lastSeenKey := lastSeenPrefix + userID.String()
+		now := time.Now().Unix()
+		err = s.redis.Set(s.ctx, lastSeenKey, now, 0).Err() // No expiry
+		if err != nil {
+			return fmt.Errorf("failed to store last seen: %w", err)
+		}
 	}
 
 	// Update last active in database
 	return s.UpdateLastActive(userID)
 }
 
+// RefreshOnline extends this node's presence for users that are still connected
+func (s *presenceService) RefreshOnline(userIDs []uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	expiresAt := time.Now().Add(onlineTTL).Unix()
+	pipe := s.redis.Pipeline()
+	for _, userID := range userIDs {
+		key := onlineKeyPrefix + userID.String()
+		pipe.HSet(s.ctx, key, s.nodeID, expiresAt)
+		pipe.Expire(s.ctx, key, onlineTTL)
+	}
+
+	if _, err := pipe.Exec(s.ctx); err != nil {
+		return fmt.Errorf("failed to refresh online status: %w", err)
+	}
+	return nil
+}
+
+// hasLiveNode ตรวจว่ามี node ที่ยังไม่หมดอายุใน presence hash หรือไม่
+func hasLiveNode(nodes map[string]string, now int64) bool {
+	for _, value := range nodes {
+		expiresAt, err := strconv.ParseInt(value, 10, 64)
+		if err == nil && expiresAt > now {
+			return true
+		}
+	}
+	return false
+}
+
 // UpdateLastActive updates user's last active timestamp in database
 func (s *presenceService) UpdateLastActive(userID uuid.UUID) error {
 	user, err := s.userRepo.FindByID(userID)
@@ -100,15 +144,12 @@ func (s *presenceService) UpdateLastActive(userID uuid.UUID) error {
 func (s *presenceService) IsUserOnline(userID uuid.UUID) (bool, error) {
 	key := onlineKeyPrefix + userID.String()
 
-	val, err := s.redis.Get(s.ctx, key).Result()
-	if err == redis.Nil {
-		return false, nil
-	}
+	nodes, err := s.redis.HGetAll(s.ctx, key).Result()
 	if err != nil {
 		return false, fmt.Errorf("failed to check online status: %w", err)
 	}
 
-	return val == "1", nil
+	return hasLiveNode(nodes, time.Now().Unix()), nil
 }
 
 // GetUserPresence gets a user's presence information
@@ -155,19 +196,20 @@ func (s *presenceService) GetMultipleUserPresence(userIDs []uuid.UUID) (map[uuid
 
 	// Get all online statuses in one call (pipeline)
 	pipe := s.redis.Pipeline()
-	cmds := make([]*redis.StringCmd, len(keys))
+	cmds := make([]*redis.StringStringMapCmd, len(keys))
 	for i, key := range keys {
-		cmds[i] = pipe.Get(s.ctx, key)
+		cmds[i] = pipe.HGetAll(s.ctx, key)
 	}
 	_, _ = pipe.Exec(s.ctx)
 
 	// Process results
+	now := time.Now().Unix()
 	for i, cmd := range cmds {
 		userID := userIDs[i]
 		isOnline := false
 
-		val, err := cmd.Result()
-		if err == nil && val == "1" {
+		nodes, err := cmd.Result()
+		if err == nil && hasLiveNode(nodes, now) {
 			isOnline = true
 		}
 
@@ -204,7 +246,21 @@ func (s *presenceService) GetOnlineUsers() ([]uuid.UUID, error) {
 			return nil, fmt.Errorf("failed to scan online users: %w", err)
 		}
 
-		for _, key := range keys {
+		// ตรวจว่ายังมี node ที่ไม่หมดอายุ (node ที่ล่มจะค้างอยู่จนกว่า key หมดอายุ)
+		pipe := s.redis.Pipeline()
+		cmds := make([]*redis.StringStringMapCmd, len(keys))
+		for i, key := range keys {
+			cmds[i] = pipe.HGetAll(s.ctx, key)
+		}
+		_, _ = pipe.Exec(s.ctx)
+
+		now := time.Now().Unix()
+		for i, key := range keys {
+			nodes, err := cmds[i].Result()
+			if err != nil || !hasLiveNode(nodes, now) {
+				continue
+			}
+
 			// Extract UUID from key
 			userIDStr := key[len(onlineKeyPrefix):]
 			userID, err := uuid.Parse(userIDStr)
@@ -0,0 +1,479 @@
+// application/serviceimpl/push_service.go
+package serviceimpl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+const (
+	pushKindMessage = notifyReasonMessage
+	pushKindMention = notifyReasonMention
+	pushKindKeyword = notifyReasonKeyword
+
+	pushMaxAttempts    = 5
+	pushRetryBaseDelay = 30 * time.Second
+	pushSendTimeout    = 15 * time.Second
+	pushClaimLease     = 2 * time.Minute // เวลาที่ instance จองรายการไว้ส่ง (ต้องนานกว่า pushSendTimeout)
+	pushBodyMaxLength  = 120
+)
+
+// pushService เป็น implementation ของ PushService (gateway สำหรับส่ง push ไปยังผู้ใช้ที่ออฟไลน์)
+type pushService struct {
+	deviceTokenRepo  repository.DeviceTokenRepository
+	pushDeliveryRepo repository.PushDeliveryRepository
+	conversationRepo repository.ConversationRepository
+	userRepo         repository.UserRepository
+	presenceService  service.PresenceService
+	providers        map[string]service.PushProvider
+}
+
+// NewPushService สร้าง instance ใหม่ของ PushService
+func NewPushService(
+	deviceTokenRepo repository.DeviceTokenRepository,
+	pushDeliveryRepo repository.PushDeliveryRepository,
+	conversationRepo repository.ConversationRepository,
+	userRepo repository.UserRepository,
+	presenceService service.PresenceService,
+	providers []service.PushProvider,
+) service.PushService {
+	providerMap := make(map[string]service.PushProvider, len(providers))
+	for _, provider := range providers {
+		providerMap[provider.Name()] = provider
+	}
+
+	return &pushService{
+		deviceTokenRepo:  deviceTokenRepo,
+		pushDeliveryRepo: pushDeliveryRepo,
+		conversationRepo: conversationRepo,
+		userRepo:         userRepo,
+		presenceService:  presenceService,
+		providers:        providerMap,
+	}
+}
+
+// =========== Device Registration ===========
+
+// RegisterDevice ลงทะเบียน (หรืออัปเดต) อุปกรณ์สำหรับรับ push
+func (s *pushService) RegisterDevice(userID uuid.UUID, device *models.DeviceToken) (*models.DeviceToken, error) {
+	device.Provider = strings.ToLower(strings.TrimSpace(device.Provider))
+	device.Token = strings.TrimSpace(device.Token)
+
+	if device.Token == "" {
+		return nil, errors.New("device token is required")
+	}
+	provider, ok := s.providers[device.Provider]
+	if !ok {
+		return nil, errors.New("push provider is not supported")
+	}
+	if device.Provider == models.PushProviderWebPush {
+		if !strings.HasPrefix(device.Token, "https://") {
+			return nil, errors.New("webpush token must be the subscription endpoint")
+		}
+		if device.WebPushP256dh == "" || device.WebPushAuth == "" {
+			return nil, errors.New("webpush subscription keys are required")
+		}
+	}
+	if validator, ok := provider.(service.PushTokenValidator); ok {
+		if err := validator.ValidateToken(device.Token); err != nil {
+			return nil, err
+		}
+	}
+
+	device.ID = uuid.Nil
+	device.UserID = userID
+	if err := s.deviceTokenRepo.Upsert(device); err != nil {
+		return nil, err
+	}
+
+	return device, nil
+}
+
+// UnregisterDevice ยกเลิกการลงทะเบียนอุปกรณ์
+func (s *pushService) UnregisterDevice(userID, deviceID uuid.UUID) error {
+	deleted, err := s.deviceTokenRepo.Delete(deviceID, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("device not found")
+	}
+	return nil
+}
+
+// ListDevices ดึงอุปกรณ์ที่ลงทะเบียนไว้ของผู้ใช้
+func (s *pushService) ListDevices(userID uuid.UUID) ([]*models.DeviceToken, error) {
+	return s.deviceTokenRepo.GetActiveByUserID(userID)
+}
+
+// SupportedProviders คืนค่ารายชื่อ provider ที่เปิดใช้งาน
+func (s *pushService) SupportedProviders() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// VAPIDPublicKey คืนค่า VAPID public key (ว่างถ้าไม่ได้เปิด WebPush)
+func (s *pushService) VAPIDPublicKey() string {
+	if provider, ok := s.providers[models.PushProviderWebPush].(service.WebPushKeyProvider); ok {
+		return provider.VAPIDPublicKey()
+	}
+	return ""
+}
+
+// =========== Delivery ===========
+
+// NotifyNewMessage ส่ง push ของข้อความใหม่ไปยังสมาชิกที่ออฟไลน์
+func (s *pushService) NotifyNewMessage(message *models.Message) {
+	if len(s.providers) == 0 || message == nil || message.MessageType == "system" {
+		return
+	}
+	// ข้อความที่ไม่มีผู้ส่งแจ้งเตือนเฉพาะที่มาจาก incoming webhook
+	if message.SenderID == nil && message.SenderType != models.SenderTypeWebhook {
+		return
+	}
+
+	conversation, err := s.conversationRepo.GetByID(message.ConversationID)
+	if err != nil || conversation == nil {
+		return
+	}
+
+	mentioned := mentionedUserIDs(message)
+	members, err := s.messageNotificationMembers(conversation, mentioned)
+	if err != nil {
+		fmt.Printf("Error getting members for push: %v\n", err)
+		return
+	}
+	decisions := make(map[uuid.UUID]notificationDecision)
+	recipientIDs := make([]uuid.UUID, 0, len(members))
+	now := time.Now()
+
+	for _, member := range members {
+		if message.SenderID != nil && member.UserID == *message.SenderID {
+			continue
+		}
+
+		// การตั้งค่าการแจ้งเตือนของสมาชิก และ quiet hours ของผู้รับ (ไม่ส่ง push ระหว่าง quiet hours)
+		decision := decideMessageNotification(member, message, mentioned[member.UserID], now)
+		if !decision.Notify || decision.Silent {
+			continue
+		}
+
+		// ผู้ใช้ที่ออนไลน์ได้รับผ่าน WebSocket อยู่แล้ว
+		if online, err := s.presenceService.IsUserOnline(member.UserID); err == nil && online {
+			continue
+		}
+
+		decisions[member.UserID] = decision
+		recipientIDs = append(recipientIDs, member.UserID)
+	}
+
+	if len(recipientIDs) == 0 {
+		return
+	}
+
+	devices, err := s.deviceTokenRepo.GetActiveByUserIDs(recipientIDs)
+	if err != nil {
+		fmt.Printf("Error getting device tokens for push: %v\n", err)
+		return
+	}
+
+	senderName := "Someone"
+	if message.SenderID == nil {
+		if name, _ := webhookSenderInfo(message); name != "" {
+			senderName = name
+		}
+	} else if sender, err := s.userRepo.FindByID(*message.SenderID); err == nil && sender != nil {
+		senderName = sender.DisplayName
+		if senderName == "" {
+			senderName = sender.Username
+		}
+	}
+	leaseUntil := time.Now().Add(pushClaimLease)
+
+	for _, device := range devices {
+		decision := decisions[device.UserID]
+		kind := decision.Reason
+		pushMessage := buildMessagePush(conversation, message, senderName, decision)
+
+		delivery := &models.PushDelivery{
+			UserID:         device.UserID,
+			DeviceTokenID:  device.ID,
+			Provider:       device.Provider,
+			ConversationID: &message.ConversationID,
+			MessageID:      &message.ID,
+			Kind:           kind,
+			Payload:        pushMessageToJSONB(pushMessage),
+			Status:         models.PushDeliveryPending,
+			NextRetryAt:    &leaseUntil, // ถ้า instance ล่มก่อนบันทึกผล scheduler จะส่งซ้ำเมื่อ lease หมด
+		}
+		if err := s.pushDeliveryRepo.Create(delivery); err != nil {
+			fmt.Printf("Error saving push delivery: %v\n", err)
+			continue
+		}
+
+		s.attempt(delivery, device, pushMessage)
+	}
+}
+
+// RetryDueDeliveries ส่ง push ที่ล้มเหลวและถึงเวลา retry แล้ว คืนค่าจำนวนที่ประมวลผล
+func (s *pushService) RetryDueDeliveries(limit int) (int, error) {
+	deliveries, err := s.pushDeliveryRepo.ClaimDueRetries(time.Now(), pushClaimLease, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		device := delivery.DeviceToken
+		if device == nil || !device.IsActive {
+			s.finish(delivery, models.PushDeliveryDropped, "device token is no longer active")
+			continue
+		}
+
+		pushMessage, err := pushMessageFromJSONB(delivery.Payload)
+		if err != nil {
+			s.finish(delivery, models.PushDeliveryFailed, "invalid payload: "+err.Error())
+			continue
+		}
+
+		s.attempt(delivery, device, pushMessage)
+	}
+
+	return len(deliveries), nil
+}
+
+// GetDeliveries ดึงประวัติการส่ง push ของผู้ใช้ (ใช้ตรวจสอบ push ที่ส่งไม่ถึง)
+func (s *pushService) GetDeliveries(userID uuid.UUID, status string, limit, offset int) ([]*models.PushDelivery, int64, error) {
+	switch status {
+	case "", models.PushDeliveryPending, models.PushDeliverySent, models.PushDeliveryFailed, models.PushDeliveryDropped:
+	default:
+		return nil, 0, errors.New("invalid delivery status")
+	}
+	return s.pushDeliveryRepo.GetByUserID(userID, status, limit, offset)
+}
+
+// attempt ส่ง push หนึ่งครั้งและบันทึกผล (retry แบบ exponential backoff)
+func (s *pushService) attempt(delivery *models.PushDelivery, device *models.DeviceToken, message *service.PushMessage) {
+	provider, ok := s.providers[device.Provider]
+	if !ok {
+		s.finish(delivery, models.PushDeliveryDropped, "push provider is not configured: "+device.Provider)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pushSendTimeout)
+	err := provider.Send(ctx, device, message)
+	cancel()
+
+	delivery.Attempts++
+	now := time.Now()
+
+	switch {
+	case err == nil:
+		delivery.SentAt = &now
+		s.finish(delivery, models.PushDeliverySent, "")
+		if err := s.deviceTokenRepo.TouchLastUsed(device.ID, now); err != nil {
+			fmt.Printf("Error updating device last used: %v\n", err)
+		}
+
+	case errors.Is(err, service.ErrPushTokenInvalid):
+		s.finish(delivery, models.PushDeliveryDropped, err.Error())
+		if err := s.deviceTokenRepo.Deactivate(device.ID); err != nil {
+			fmt.Printf("Error deactivating device token: %v\n", err)
+		}
+
+	case delivery.Attempts >= pushMaxAttempts:
+		s.finish(delivery, models.PushDeliveryFailed, err.Error())
+
+	default:
+		nextRetryAt := now.Add(pushRetryBaseDelay << (delivery.Attempts - 1))
+		delivery.Status = models.PushDeliveryPending
+		delivery.LastError = err.Error()
+		delivery.NextRetryAt = &nextRetryAt
+		if err := s.pushDeliveryRepo.Update(delivery); err != nil {
+			fmt.Printf("Error updating push delivery: %v\n", err)
+		}
+	}
+}
+
+// finish บันทึกสถานะสุดท้ายของการส่ง (ไม่ retry อีก)
+func (s *pushService) finish(delivery *models.PushDelivery, status, lastError string) {
+	delivery.Status = status
+	delivery.LastError = lastError
+	delivery.NextRetryAt = nil
+	if err := s.pushDeliveryRepo.Update(delivery); err != nil {
+		fmt.Printf("Error updating push delivery: %v\n", err)
+	}
+}
+
+// messageNotificationMembers ดึงสมาชิกที่อาจได้รับ push จากข้อความ
+// ช่อง (channel) ไม่โหลดผู้ติดตามทั้งหมด: เฉพาะผู้ที่รับทุกโพสต์ และผู้ถูก mention
+func (s *pushService) messageNotificationMembers(conversation *models.Conversation, mentioned map[uuid.UUID]bool) ([]*models.ConversationMember, error) {
+	if !conversation.IsChannel() {
+		return s.conversationRepo.GetMembers(conversation.ID)
+	}
+
+	members, err := s.conversationRepo.GetChannelNotificationMembers(conversation.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := make(map[uuid.UUID]bool, len(members))
+	for _, member := range members {
+		loaded[member.UserID] = true
+	}
+	missing := make([]uuid.UUID, 0, len(mentioned))
+	for userID := range mentioned {
+		if !loaded[userID] {
+			missing = append(missing, userID)
+		}
+	}
+
+	mentionedMembers, err := s.conversationRepo.GetMembersByUserIDs(conversation.ID, missing)
+	if err != nil {
+		return nil, err
+	}
+	return append(members, mentionedMembers...), nil
+}
+
+// mentionedUserIDs ดึง user ID ที่ถูก mention จาก message.Mentions ({"data": [{"user_id": ...}]})
+func mentionedUserIDs(message *models.Message) map[uuid.UUID]bool {
+	result := make(map[uuid.UUID]bool)
+
+	data, ok := message.Mentions["data"].([]interface{})
+	if !ok {
+		return result
+	}
+
+	for _, item := range data {
+		mention, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		userIDStr, ok := mention["user_id"].(string)
+		if !ok {
+			continue
+		}
+		if userID, err := uuid.Parse(userIDStr); err == nil {
+			result[userID] = true
+		}
+	}
+
+	return result
+}
+
+// buildMessagePush สร้างเนื้อหา push ของข้อความ
+func buildMessagePush(conversation *models.Conversation, message *models.Message, senderName string, decision notificationDecision) *service.PushMessage {
+	kind := decision.Reason
+	body := pushMessagePreview(message)
+	title := senderName
+
+	if conversation.Type == "group" {
+		title = conversation.Title
+		body = senderName + ": " + body
+	}
+	if kind == pushKindMention {
+		title = senderName + " mentioned you"
+		if conversation.Type == "group" && conversation.Title != "" {
+			title += " in " + conversation.Title
+		}
+		body = pushMessagePreview(message)
+	}
+	if kind == pushKindKeyword {
+		title = "\"" + decision.Keyword + "\""
+		if conversation.Type == "group" && conversation.Title != "" {
+			title += " in " + conversation.Title
+		}
+		body = senderName + ": " + pushMessagePreview(message)
+	}
+
+	data := map[string]string{
+		"type":            kind,
+		"conversation_id": message.ConversationID.String(),
+		"message_id":      message.ID.String(),
+	}
+	if message.SenderID != nil {
+		data["sender_id"] = message.SenderID.String()
+	}
+	if message.ThreadRootID != nil {
+		data["thread_root_id"] = message.ThreadRootID.String()
+	}
+	if decision.Keyword != "" {
+		data["keyword"] = decision.Keyword
+	}
+
+	return &service.PushMessage{
+		Title:       title,
+		Body:        body,
+		Data:        data,
+		Sound:       "default",
+		CollapseKey: message.ConversationID.String(),
+	}
+}
+
+// pushMessagePreview สร้างข้อความตัวอย่างตามประเภทข้อความ
+func pushMessagePreview(message *models.Message) string {
+	var preview string
+	switch message.MessageType {
+	case "text":
+		preview = message.Content
+	case "sticker":
+		preview = "[Sticker]"
+	case "image":
+		preview = "[Image]"
+	case "file":
+		preview = "[File]"
+	case "album":
+		preview = "[Album]"
+	case "poll":
+		preview = pollLastMessagePrefix + message.Content
+	case models.MessageTypeEncrypted:
+		preview = encryptedLastMessageText
+	default:
+		preview = "[Message]"
+	}
+
+	if utf8.RuneCountInString(preview) > pushBodyMaxLength {
+		preview = string([]rune(preview)[:pushBodyMaxLength]) + "…"
+	}
+	return preview
+}
+
+// pushMessageToJSONB แปลง PushMessage เป็น JSONB เพื่อเก็บไว้ retry
+func pushMessageToJSONB(message *service.PushMessage) types.JSONB {
+	payload := types.JSONB{}
+	data, err := json.Marshal(message)
+	if err != nil {
+		return payload
+	}
+	_ = json.Unmarshal(data, &payload)
+	return payload
+}
+
+// pushMessageFromJSONB แปลง payload ที่บันทึกไว้กลับเป็น PushMessage
+func pushMessageFromJSONB(payload types.JSONB) (*service.PushMessage, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var message service.PushMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
@@ -0,0 +1,356 @@
+// application/serviceimpl/push_service_test.go
+package serviceimpl
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/infrastructure/push/fake"
+	"github.com/thizplus/gofiber-chat-api/infrastructure/push/webpush"
+)
+
+// stubPresenceService ผู้ใช้ที่อยู่ใน online ถือว่าออนไลน์
+type stubPresenceService struct {
+	service.PresenceService
+	online map[uuid.UUID]bool
+}
+
+func (s *stubPresenceService) IsUserOnline(userID uuid.UUID) (bool, error) {
+	return s.online[userID], nil
+}
+
+// memoryDeviceTokenRepo เก็บ device token ในหน่วยความจำ
+type memoryDeviceTokenRepo struct {
+	repository.DeviceTokenRepository
+	mu      sync.Mutex
+	devices map[uuid.UUID]*models.DeviceToken
+}
+
+func newMemoryDeviceTokenRepo(devices ...*models.DeviceToken) *memoryDeviceTokenRepo {
+	repo := &memoryDeviceTokenRepo{devices: make(map[uuid.UUID]*models.DeviceToken)}
+	for _, device := range devices {
+		repo.devices[device.ID] = device
+	}
+	return repo
+}
+
+func (r *memoryDeviceTokenRepo) Upsert(device *models.DeviceToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	device.ID = uuid.New()
+	device.IsActive = true
+	r.devices[device.ID] = device
+	return nil
+}
+
+func (r *memoryDeviceTokenRepo) GetActiveByUserIDs(userIDs []uuid.UUID) ([]*models.DeviceToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	wanted := make(map[uuid.UUID]bool, len(userIDs))
+	for _, userID := range userIDs {
+		wanted[userID] = true
+	}
+	var devices []*models.DeviceToken
+	for _, device := range r.devices {
+		if device.IsActive && wanted[device.UserID] {
+			devices = append(devices, device)
+		}
+	}
+	return devices, nil
+}
+
+func (r *memoryDeviceTokenRepo) Deactivate(id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if device, ok := r.devices[id]; ok {
+		device.IsActive = false
+	}
+	return nil
+}
+
+func (r *memoryDeviceTokenRepo) TouchLastUsed(uuid.UUID, time.Time) error {
+	return nil
+}
+
+// memoryPushDeliveryRepo เก็บ delivery log ในหน่วยความจำ (claim ด้วย lease แบบเดียวกับ postgres)
+type memoryPushDeliveryRepo struct {
+	mu         sync.Mutex
+	devices    *memoryDeviceTokenRepo
+	deliveries map[uuid.UUID]models.PushDelivery
+}
+
+func newMemoryPushDeliveryRepo(devices *memoryDeviceTokenRepo) *memoryPushDeliveryRepo {
+	return &memoryPushDeliveryRepo{
+		devices:    devices,
+		deliveries: make(map[uuid.UUID]models.PushDelivery),
+	}
+}
+
+func (r *memoryPushDeliveryRepo) Create(delivery *models.PushDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+	r.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func (r *memoryPushDeliveryRepo) Update(delivery *models.PushDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func (r *memoryPushDeliveryRepo) ClaimDueRetries(now time.Time, lease time.Duration, limit int) ([]*models.PushDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var claimed []*models.PushDelivery
+	for id, delivery := range r.deliveries {
+		if len(claimed) >= limit {
+			break
+		}
+		if delivery.Status != models.PushDeliveryPending || delivery.NextRetryAt == nil || delivery.NextRetryAt.After(now) {
+			continue
+		}
+		leaseUntil := now.Add(lease)
+		delivery.NextRetryAt = &leaseUntil
+		r.deliveries[id] = delivery
+
+		copied := delivery
+		copied.DeviceToken = r.devices.devices[delivery.DeviceTokenID]
+		claimed = append(claimed, &copied)
+	}
+	return claimed, nil
+}
+
+func (r *memoryPushDeliveryRepo) GetByUserID(uuid.UUID, string, int, int) ([]*models.PushDelivery, int64, error) {
+	return nil, 0, nil
+}
+
+// only คืน delivery เดียวที่บันทึกไว้
+func (r *memoryPushDeliveryRepo) only(t *testing.T) models.PushDelivery {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.deliveries) != 1 {
+		t.Fatalf("expected 1 delivery, got %d", len(r.deliveries))
+	}
+	for _, delivery := range r.deliveries {
+		return delivery
+	}
+	return models.PushDelivery{}
+}
+
+// makeDue ทำให้ delivery ที่รอ retry ถึงเวลาส่งทันที
+func (r *memoryPushDeliveryRepo) makeDue() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	past := time.Now().Add(-time.Second)
+	for id, delivery := range r.deliveries {
+		if delivery.NextRetryAt != nil {
+			delivery.NextRetryAt = &past
+			r.deliveries[id] = delivery
+		}
+	}
+}
+
+// pushTestFixture การสนทนาแบบกลุ่มที่มีผู้ส่ง ผู้รับที่ออฟไลน์ และผู้รับที่ออนไลน์ (แต่ละคนมีอุปกรณ์ fake หนึ่งเครื่อง)
+type pushTestFixture struct {
+	svc          *pushService
+	provider     *fake.FakeProvider
+	devices      *memoryDeviceTokenRepo
+	deliveries   *memoryPushDeliveryRepo
+	message      *models.Message
+	offlineID    uuid.UUID
+	offlineToken *models.DeviceToken
+}
+
+func newPushTestFixture(t *testing.T) *pushTestFixture {
+	t.Helper()
+
+	senderID, offlineID, onlineID := uuid.New(), uuid.New(), uuid.New()
+	conversation := &models.Conversation{ID: uuid.New(), Type: "group", Title: "team"}
+
+	var members []*models.ConversationMember
+	var tokens []*models.DeviceToken
+	for _, userID := range []uuid.UUID{senderID, offlineID, onlineID} {
+		members = append(members, &models.ConversationMember{
+			ConversationID: conversation.ID,
+			UserID:         userID,
+			User:           &models.User{ID: userID},
+		})
+		tokens = append(tokens, &models.DeviceToken{
+			ID:       uuid.New(),
+			UserID:   userID,
+			Provider: models.PushProviderFake,
+			Token:    "token-" + userID.String(),
+			IsActive: true,
+		})
+	}
+
+	provider := fake.NewFakeProvider(nil)
+	devices := newMemoryDeviceTokenRepo(tokens...)
+	deliveries := newMemoryPushDeliveryRepo(devices)
+	svc := NewPushService(
+		devices,
+		deliveries,
+		&stubConversationRepo{conversation: conversation, members: members},
+		&stubUserRepo{},
+		&stubPresenceService{online: map[uuid.UUID]bool{onlineID: true}},
+		[]service.PushProvider{provider},
+	).(*pushService)
+
+	return &pushTestFixture{
+		svc:        svc,
+		provider:   provider,
+		devices:    devices,
+		deliveries: deliveries,
+		message: &models.Message{
+			ID:             uuid.New(),
+			ConversationID: conversation.ID,
+			SenderID:       &senderID,
+			MessageType:    "text",
+			Content:        "hello team",
+		},
+		offlineID:    offlineID,
+		offlineToken: tokens[1],
+	}
+}
+
+func TestPushSentOnlyToOfflineRecipients(t *testing.T) {
+	f := newPushTestFixture(t)
+
+	f.svc.NotifyNewMessage(f.message)
+
+	sent := f.provider.Sent()
+	if len(sent) != 1 {
+		t.Fatalf("expected 1 push, got %d", len(sent))
+	}
+	if sent[0].Device.UserID != f.offlineID {
+		t.Fatalf("push went to %s, expected offline member %s", sent[0].Device.UserID, f.offlineID)
+	}
+	if sent[0].Message.Title != "team" || sent[0].Message.Body != "sender: hello team" {
+		t.Fatalf("unexpected push content: title=%q body=%q", sent[0].Message.Title, sent[0].Message.Body)
+	}
+
+	delivery := f.deliveries.only(t)
+	if delivery.Status != models.PushDeliverySent || delivery.Attempts != 1 || delivery.NextRetryAt != nil || delivery.SentAt == nil {
+		t.Fatalf("unexpected delivery state: status=%s attempts=%d next_retry_at=%v", delivery.Status, delivery.Attempts, delivery.NextRetryAt)
+	}
+}
+
+func TestPushRetriesWithExponentialBackoff(t *testing.T) {
+	f := newPushTestFixture(t)
+	f.provider.FailWith(errors.New("provider unavailable"))
+
+	f.svc.NotifyNewMessage(f.message)
+
+	// ครั้งแรกล้มเหลว: retry หลัง base delay
+	start := time.Now()
+	delivery := f.deliveries.only(t)
+	if delivery.Status != models.PushDeliveryPending || delivery.Attempts != 1 || delivery.LastError != "provider unavailable" {
+		t.Fatalf("unexpected state after first attempt: status=%s attempts=%d error=%q", delivery.Status, delivery.Attempts, delivery.LastError)
+	}
+	assertRetryDelay(t, delivery.NextRetryAt, start, pushRetryBaseDelay)
+
+	// ยังไม่ถึงเวลา retry: scheduler ต้องไม่ส่งซ้ำ
+	if count, err := f.svc.RetryDueDeliveries(10); err != nil || count != 0 {
+		t.Fatalf("expected no due retries, got %d (err: %v)", count, err)
+	}
+
+	// ครั้งที่สองล้มเหลว: delay เพิ่มเป็นสองเท่า
+	f.deliveries.makeDue()
+	start = time.Now()
+	if count, err := f.svc.RetryDueDeliveries(10); err != nil || count != 1 {
+		t.Fatalf("expected 1 retry, got %d (err: %v)", count, err)
+	}
+	delivery = f.deliveries.only(t)
+	if delivery.Status != models.PushDeliveryPending || delivery.Attempts != 2 {
+		t.Fatalf("unexpected state after second attempt: status=%s attempts=%d", delivery.Status, delivery.Attempts)
+	}
+	assertRetryDelay(t, delivery.NextRetryAt, start, 2*pushRetryBaseDelay)
+
+	// ครั้งที่สามสำเร็จ
+	f.provider.FailWith(nil)
+	f.deliveries.makeDue()
+	if count, err := f.svc.RetryDueDeliveries(10); err != nil || count != 1 {
+		t.Fatalf("expected 1 retry, got %d (err: %v)", count, err)
+	}
+	delivery = f.deliveries.only(t)
+	if delivery.Status != models.PushDeliverySent || delivery.Attempts != 3 || delivery.NextRetryAt != nil {
+		t.Fatalf("unexpected state after final attempt: status=%s attempts=%d", delivery.Status, delivery.Attempts)
+	}
+	if len(f.provider.Sent()) != 1 {
+		t.Fatalf("expected 1 successful push, got %d", len(f.provider.Sent()))
+	}
+}
+
+func TestPushInvalidTokenIsDroppedAndDeactivated(t *testing.T) {
+	f := newPushTestFixture(t)
+	f.provider.FailWith(fmt.Errorf("%w: unregistered", service.ErrPushTokenInvalid))
+
+	f.svc.NotifyNewMessage(f.message)
+
+	delivery := f.deliveries.only(t)
+	if delivery.Status != models.PushDeliveryDropped || delivery.NextRetryAt != nil {
+		t.Fatalf("unexpected delivery state: status=%s next_retry_at=%v", delivery.Status, delivery.NextRetryAt)
+	}
+	if f.offlineToken.IsActive {
+		t.Fatal("expected invalid device token to be deactivated")
+	}
+}
+
+func TestRegisterDeviceRejectsPrivateWebPushEndpoint(t *testing.T) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider, err := webpush.NewWebPushProvider(&webpush.WebPushConfig{
+		VAPIDPublicKey:  base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		VAPIDPrivateKey: base64.RawURLEncoding.EncodeToString(key.Bytes()),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewPushService(newMemoryDeviceTokenRepo(), nil, nil, nil, nil, []service.PushProvider{provider})
+
+	for _, endpoint := range []string{
+		"https://127.0.0.1/push",
+		"https://localhost:8443/push",
+		"https://169.254.169.254/latest/meta-data",
+		"https://[::1]/push",
+	} {
+		_, err := svc.RegisterDevice(uuid.New(), &models.DeviceToken{
+			Provider:      models.PushProviderWebPush,
+			Token:         endpoint,
+			WebPushP256dh: "p256dh",
+			WebPushAuth:   "auth",
+		})
+		if err == nil || err.Error() != "webpush endpoint must not point to a private address" {
+			t.Errorf("%s: expected private address error, got %v", endpoint, err)
+		}
+	}
+
+	device, err := svc.RegisterDevice(uuid.New(), &models.DeviceToken{
+		Provider:      models.PushProviderWebPush,
+		Token:         "https://fcm.googleapis.com/fcm/send/abc",
+		WebPushP256dh: "p256dh",
+		WebPushAuth:   "auth",
+	})
+	if err != nil || device.ID == uuid.Nil {
+		t.Fatalf("expected public endpoint to be registered, got %v", err)
+	}
+}
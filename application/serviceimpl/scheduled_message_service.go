@@ -58,6 +58,11 @@ func (s *scheduledMessageService) ScheduleMessage(
 		return nil, errors.New("user is not a member of this conversation")
 	}
 
+	// ช่อง (channel) โพสต์ได้เฉพาะ owner/admin
+	if _, err := checkPostPermission(s.conversationRepo, conversationID, userID); err != nil {
+		return nil, err
+	}
+
 	// ตรวจสอบว่า scheduled_at ต้องอยู่ในอนาคต
 	if scheduledAt.Before(time.Now()) {
 		return nil, errors.New("scheduled_at must be in the future")
@@ -0,0 +1,368 @@
+// application/serviceimpl/sync_service.go
+package serviceimpl
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+)
+
+const (
+	syncDefaultLimit = 100
+	syncMaxLimit     = 500
+	syncTokenVersion = 1
+
+	// syncSafetyWindow ไม่ส่งข้อมูลที่ใหม่กว่านี้ เผื่อ transaction ที่ได้ timestamp แล้วแต่ยัง commit ไม่เสร็จ
+	// (event แบบ realtime ยังมาทาง WebSocket ตามปกติ)
+	syncSafetyWindow = 2 * time.Second
+
+	// syncTombstoneRetention อายุของ tombstone - token ที่เก่ากว่านี้ต้อง resync ทั้งหมด
+	syncTombstoneRetention = 30 * 24 * time.Hour
+)
+
+// ประเภทข้อมูลใน change feed
+const (
+	syncKindMessage      = "message"
+	syncKindConversation = "conversation"
+	syncKindMember       = "member"
+	syncKindActivity     = "activity"
+	syncKindFriendship   = "friendship"
+	syncKindPin          = "pin"
+	syncKindNote         = "note"
+	syncKindTombstone    = "tombstone"
+)
+
+// syncToken เนื้อหาของ token (client มองเป็น opaque string)
+type syncToken struct {
+	Version int       `json:"v"`
+	At      time.Time `json:"at"`
+	ID      uuid.UUID `json:"id"`
+}
+
+// syncEntry อ้างอิงแถวหนึ่งใน change feed สำหรับเรียงลำดับรวมทุกประเภท
+type syncEntry struct {
+	cursor repository.SyncCursor
+	kind   string
+	index  int
+}
+
+type syncService struct {
+	syncRepo            repository.SyncRepository
+	conversationService service.ConversationService
+}
+
+// NewSyncService สร้าง service ใหม่
+func NewSyncService(
+	syncRepo repository.SyncRepository,
+	conversationService service.ConversationService,
+) service.SyncService {
+	return &syncService{
+		syncRepo:            syncRepo,
+		conversationService: conversationService,
+	}
+}
+
+// Sync คืนข้อมูลที่เปลี่ยนแปลงหลัง token
+// แต่ละประเภทดึง limit+1 แถวเพื่อรู้ว่ายังมีต่อหรือไม่ แล้วตัดทุกประเภทที่ cursor เดียวกัน
+// เพื่อให้ next_token ไม่ข้ามข้อมูลของประเภทใด
+func (s *syncService) Sync(userID uuid.UUID, token string, limit int) (*dto.SyncResponse, error) {
+	if limit <= 0 {
+		limit = syncDefaultLimit
+	}
+	if limit > syncMaxLimit {
+		limit = syncMaxLimit
+	}
+
+	now := time.Now()
+	until := now.Add(-syncSafetyWindow)
+	response := newSyncResponse()
+
+	// ไม่มี token = client เพิ่งโหลดข้อมูลทั้งหมดผ่าน API ปกติ ให้เริ่ม sync จากเวลานี้
+	if token == "" {
+		response.NextToken = encodeSyncToken(repository.SyncCursor{At: until, ID: uuid.Max})
+		return response, nil
+	}
+
+	after, err := decodeSyncToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if after.At.Before(now.Add(-syncTombstoneRetention)) {
+		return nil, errors.New("sync token expired")
+	}
+	if !after.At.Before(until) {
+		response.NextToken = token
+		return response, nil
+	}
+
+	fetch := limit + 1
+
+	messages, err := s.syncRepo.GetChangedMessages(userID, after, until, fetch)
+	if err != nil {
+		return nil, err
+	}
+	conversations, err := s.syncRepo.GetChangedConversations(userID, after, until, fetch)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.syncRepo.GetChangedMembers(userID, after, until, fetch)
+	if err != nil {
+		return nil, err
+	}
+	activities, err := s.syncRepo.GetActivities(userID, after, until, fetch)
+	if err != nil {
+		return nil, err
+	}
+	friendships, err := s.syncRepo.GetChangedFriendships(userID, after, until, fetch)
+	if err != nil {
+		return nil, err
+	}
+	pins, err := s.syncRepo.GetChangedPins(userID, after, until, fetch)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.syncRepo.GetChangedNotes(userID, after, until, fetch)
+	if err != nil {
+		return nil, err
+	}
+	tombstones, err := s.syncRepo.GetTombstones(userID, after, until, fetch)
+	if err != nil {
+		return nil, err
+	}
+
+	// รวมทุกประเภทเป็น feed เดียว
+	var entries []syncEntry
+	var cut *repository.SyncCursor
+	collect := func(kind string, cursors []repository.SyncCursor) {
+		for i, cursor := range cursors {
+			if i == limit {
+				// แถวที่ limit+1 ไม่ถูกส่ง - ข้อมูลทุกประเภทต้องหยุดก่อนตำแหน่งนี้
+				if cut == nil || syncCursorLess(cursor, *cut) {
+					cut = &cursor
+				}
+				break
+			}
+			entries = append(entries, syncEntry{cursor: cursor, kind: kind, index: i})
+		}
+	}
+
+	collect(syncKindMessage, syncCursors(len(messages), func(i int) repository.SyncCursor {
+		return repository.SyncCursor{At: messages[i].UpdatedAt, ID: messages[i].ID}
+	}))
+	collect(syncKindConversation, syncCursors(len(conversations), func(i int) repository.SyncCursor {
+		return repository.SyncCursor{At: conversations[i].UpdatedAt, ID: conversations[i].ID}
+	}))
+	collect(syncKindMember, syncCursors(len(members), func(i int) repository.SyncCursor {
+		return repository.SyncCursor{At: members[i].UpdatedAt, ID: members[i].ID}
+	}))
+	collect(syncKindActivity, syncCursors(len(activities), func(i int) repository.SyncCursor {
+		return repository.SyncCursor{At: activities[i].CreatedAt, ID: activities[i].ID}
+	}))
+	collect(syncKindFriendship, syncCursors(len(friendships), func(i int) repository.SyncCursor {
+		return repository.SyncCursor{At: friendships[i].UpdatedAt, ID: friendships[i].ID}
+	}))
+	collect(syncKindPin, syncCursors(len(pins), func(i int) repository.SyncCursor {
+		return repository.SyncCursor{At: pins[i].UpdatedAt, ID: pins[i].ID}
+	}))
+	collect(syncKindNote, syncCursors(len(notes), func(i int) repository.SyncCursor {
+		return repository.SyncCursor{At: notes[i].UpdatedAt, ID: notes[i].ID}
+	}))
+	collect(syncKindTombstone, syncCursors(len(tombstones), func(i int) repository.SyncCursor {
+		return repository.SyncCursor{At: tombstones[i].DeletedAt, ID: tombstones[i].ID}
+	}))
+
+	sort.Slice(entries, func(i, j int) bool {
+		return syncCursorLess(entries[i].cursor, entries[j].cursor)
+	})
+
+	hasMore := false
+	if cut != nil {
+		hasMore = true
+		kept := 0
+		for kept < len(entries) && syncCursorLess(entries[kept].cursor, *cut) {
+			kept++
+		}
+		entries = entries[:kept]
+	}
+	if len(entries) > limit {
+		hasMore = true
+		entries = entries[:limit]
+	}
+
+	for _, entry := range entries {
+		switch entry.kind {
+		case syncKindMessage:
+			messageDTO, err := s.conversationService.ConvertToMessageDTO(messages[entry.index], userID)
+			if err != nil {
+				return nil, err
+			}
+			response.Messages = append(response.Messages, messageDTO)
+		case syncKindConversation:
+			conversationDTO, err := s.conversationService.ConvertToConversationDTO(conversations[entry.index], userID)
+			if err != nil {
+				return nil, err
+			}
+			response.Conversations = append(response.Conversations, conversationDTO)
+		case syncKindMember:
+			member := members[entry.index]
+			response.Members = append(response.Members, buildSyncMemberDTO(member, userID))
+			if member.UserID == userID && member.JoinedAt.After(after.At) {
+				response.ReloadConversationIDs = append(response.ReloadConversationIDs, member.ConversationID)
+			}
+		case syncKindActivity:
+			response.Activities = append(response.Activities, buildActivityDTO(activities[entry.index]))
+		case syncKindFriendship:
+			response.Friendships = append(response.Friendships, buildSyncFriendshipDTO(friendships[entry.index]))
+		case syncKindPin:
+			response.Pins = append(response.Pins, buildSyncPinDTO(pins[entry.index]))
+		case syncKindNote:
+			response.Notes = append(response.Notes, buildSyncNoteDTO(notes[entry.index]))
+		case syncKindTombstone:
+			response.Deleted = append(response.Deleted, buildSyncTombstoneDTO(tombstones[entry.index]))
+		}
+	}
+
+	// ครบทุกอย่างจนถึง until แล้ว ครั้งต่อไปเริ่มหลัง until
+	next := repository.SyncCursor{At: until, ID: uuid.Max}
+	if hasMore {
+		next = entries[len(entries)-1].cursor
+	}
+
+	response.HasMore = hasMore
+	response.NextToken = encodeSyncToken(next)
+	return response, nil
+}
+
+// PurgeTombstones ลบ tombstone ที่เก่ากว่า retention
+func (s *syncService) PurgeTombstones(now time.Time) (int64, error) {
+	return s.syncRepo.DeleteTombstonesBefore(now.Add(-syncTombstoneRetention))
+}
+
+// newSyncResponse สร้าง response ที่ทุก list เป็น [] แทน null
+func newSyncResponse() *dto.SyncResponse {
+	return &dto.SyncResponse{
+		Messages:              []*dto.MessageDTO{},
+		Conversations:         []*dto.ConversationDTO{},
+		Members:               []*dto.SyncMemberDTO{},
+		Activities:            []*dto.ActivityDTO{},
+		Friendships:           []*dto.SyncFriendshipDTO{},
+		Pins:                  []*dto.PinnedMessageDTO{},
+		Notes:                 []*dto.SyncNoteDTO{},
+		Deleted:               []*dto.SyncTombstoneDTO{},
+		ReloadConversationIDs: []uuid.UUID{},
+	}
+}
+
+// syncCursors สร้าง cursor ของแต่ละแถว
+func syncCursors(n int, at func(i int) repository.SyncCursor) []repository.SyncCursor {
+	cursors := make([]repository.SyncCursor, n)
+	for i := range cursors {
+		cursors[i] = at(i)
+	}
+	return cursors
+}
+
+// syncCursorLess เรียงตามเวลาแล้วตาม id (ลำดับ byte เหมือน uuid ของ PostgreSQL)
+func syncCursorLess(a, b repository.SyncCursor) bool {
+	if !a.At.Equal(b.At) {
+		return a.At.Before(b.At)
+	}
+	return bytes.Compare(a.ID[:], b.ID[:]) < 0
+}
+
+func encodeSyncToken(cursor repository.SyncCursor) string {
+	data, _ := json.Marshal(syncToken{Version: syncTokenVersion, At: cursor.At.UTC(), ID: cursor.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSyncToken(token string) (repository.SyncCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return repository.SyncCursor{}, errors.New("invalid sync token")
+	}
+
+	var decoded syncToken
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.Version != syncTokenVersion || decoded.At.IsZero() {
+		return repository.SyncCursor{}, errors.New("invalid sync token")
+	}
+	return repository.SyncCursor{At: decoded.At, ID: decoded.ID}, nil
+}
+
+// buildSyncMemberDTO แปลงสมาชิกเป็น DTO (การตั้งค่าส่วนตัวส่งเฉพาะแถวของผู้ใช้เอง)
+func buildSyncMemberDTO(member *models.ConversationMember, userID uuid.UUID) *dto.SyncMemberDTO {
+	memberDTO := &dto.SyncMemberDTO{
+		ID:             member.ID,
+		ConversationID: member.ConversationID,
+		UserID:         member.UserID,
+		Role:           string(member.Role),
+		JoinedAt:       member.JoinedAt,
+		LastReadAt:     member.LastReadAt,
+		Nickname:       member.Nickname,
+		UpdatedAt:      member.UpdatedAt,
+	}
+
+	if member.UserID == userID {
+		isMuted := member.IsMutedAt(time.Now()) // การปิดเสียงชั่วคราวที่หมดเวลาแล้วถือว่าไม่ได้ปิดเสียง
+		memberDTO.IsMuted = &isMuted
+		memberDTO.IsPinned = &member.IsPinned
+		memberDTO.IsHidden = &member.IsHidden
+		memberDTO.NotificationSettings = member.NotificationSettings
+	}
+
+	return memberDTO
+}
+
+func buildSyncFriendshipDTO(friendship *models.UserFriendship) *dto.SyncFriendshipDTO {
+	return &dto.SyncFriendshipDTO{
+		ID:          friendship.ID,
+		UserID:      friendship.UserID,
+		FriendID:    friendship.FriendID,
+		Status:      friendship.Status,
+		RequestedAt: friendship.RequestedAt,
+		UpdatedAt:   friendship.UpdatedAt,
+	}
+}
+
+func buildSyncPinDTO(pin *models.PinnedMessage) *dto.PinnedMessageDTO {
+	return &dto.PinnedMessageDTO{
+		ID:             pin.ID,
+		MessageID:      pin.MessageID,
+		ConversationID: pin.ConversationID,
+		UserID:         pin.UserID,
+		PinType:        pin.PinType,
+		PinnedAt:       pin.PinnedAt,
+	}
+}
+
+func buildSyncNoteDTO(note *models.Note) *dto.SyncNoteDTO {
+	return &dto.SyncNoteDTO{
+		ID:             note.ID,
+		UserID:         note.UserID,
+		ConversationID: note.ConversationID,
+		Title:          note.Title,
+		Content:        note.Content,
+		Tags:           note.Tags,
+		IsPinned:       note.IsPinned,
+		Visibility:     string(note.Visibility),
+		CreatedAt:      note.CreatedAt,
+		UpdatedAt:      note.UpdatedAt,
+	}
+}
+
+func buildSyncTombstoneDTO(tombstone *models.SyncTombstone) *dto.SyncTombstoneDTO {
+	return &dto.SyncTombstoneDTO{
+		EntityType:     tombstone.EntityType,
+		EntityID:       tombstone.EntityID,
+		ConversationID: tombstone.ConversationID,
+		DeletedAt:      tombstone.DeletedAt,
+	}
+}
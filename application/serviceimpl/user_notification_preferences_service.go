@@ -0,0 +1,151 @@
+// application/serviceimpl/user_notification_preferences_service.go
+package serviceimpl
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+const (
+	maxKeywordAlerts      = 20
+	keywordAlertMinLength = 2
+	keywordAlertMaxLength = 50
+)
+
+// GetNotificationPreferences ดึง quiet hours และ keyword alerts ของผู้ใช้
+func (s *userService) GetNotificationPreferences(userID uuid.UUID) (*dto.NotificationPreferencesDTO, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return toNotificationPreferencesDTO(user.NotificationPreferences(), time.Now()), nil
+}
+
+// UpdateNotificationPreferences บันทึก quiet hours และ keyword alerts ลงใน User.Settings
+func (s *userService) UpdateNotificationPreferences(userID uuid.UUID, req *dto.UpdateNotificationPreferencesRequest) (*dto.NotificationPreferencesDTO, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := types.JSONB{}
+	for key, value := range user.Settings {
+		settings[key] = value
+	}
+
+	if req.QuietHours != nil {
+		quietHours, err := normalizeQuietHours(req.QuietHours)
+		if err != nil {
+			return nil, err
+		}
+		settings[models.UserSettingQuietHours] = map[string]interface{}{
+			"enabled":  quietHours.Enabled,
+			"start":    quietHours.Start,
+			"end":      quietHours.End,
+			"timezone": quietHours.Timezone,
+		}
+	}
+
+	if req.KeywordAlerts != nil {
+		keywords, err := normalizeKeywordAlerts(*req.KeywordAlerts)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, len(keywords))
+		for i, keyword := range keywords {
+			values[i] = keyword
+		}
+		settings[models.UserSettingKeywordAlerts] = values
+	}
+
+	user.Settings = settings
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	return toNotificationPreferencesDTO(user.NotificationPreferences(), time.Now()), nil
+}
+
+// normalizeQuietHours ตรวจสอบเวลา HH:MM และเขตเวลา IANA (ปิดใช้งานได้โดยไม่ต้องระบุช่วงเวลา)
+func normalizeQuietHours(input *dto.QuietHoursDTO) (*models.QuietHours, error) {
+	quietHours := &models.QuietHours{
+		Enabled:  input.Enabled,
+		Start:    strings.TrimSpace(input.Start),
+		End:      strings.TrimSpace(input.End),
+		Timezone: strings.TrimSpace(input.Timezone),
+	}
+	if !quietHours.Enabled && quietHours.Start == "" && quietHours.End == "" {
+		return quietHours, nil
+	}
+
+	start, ok := models.ParseClockMinutes(quietHours.Start)
+	if !ok {
+		return nil, errors.New("invalid quiet hours time, expected HH:MM")
+	}
+	end, ok := models.ParseClockMinutes(quietHours.End)
+	if !ok {
+		return nil, errors.New("invalid quiet hours time, expected HH:MM")
+	}
+	if start == end {
+		return nil, errors.New("quiet hours start and end must differ")
+	}
+
+	if quietHours.Timezone == "" {
+		quietHours.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(quietHours.Timezone); err != nil {
+		return nil, errors.New("invalid timezone")
+	}
+
+	return quietHours, nil
+}
+
+// normalizeKeywordAlerts ตัดช่องว่าง ตัดรายการซ้ำ (ไม่สนตัวพิมพ์) และตรวจความยาว
+func normalizeKeywordAlerts(input []string) ([]string, error) {
+	keywords := make([]string, 0, len(input))
+	seen := make(map[string]bool, len(input))
+	for _, keyword := range input {
+		keyword = strings.TrimSpace(keyword)
+		if keyword == "" {
+			continue
+		}
+		length := utf8.RuneCountInString(keyword)
+		if length < keywordAlertMinLength || length > keywordAlertMaxLength {
+			return nil, errors.New("keyword alert must be between 2 and 50 characters")
+		}
+		key := strings.ToLower(keyword)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		keywords = append(keywords, keyword)
+	}
+
+	if len(keywords) > maxKeywordAlerts {
+		return nil, errors.New("too many keyword alerts")
+	}
+	return keywords, nil
+}
+
+func toNotificationPreferencesDTO(prefs models.UserNotificationPreferences, now time.Time) *dto.NotificationPreferencesDTO {
+	result := &dto.NotificationPreferencesDTO{
+		QuietNow:      prefs.QuietHours.ActiveAt(now),
+		KeywordAlerts: prefs.KeywordAlerts,
+	}
+	if prefs.QuietHours != nil {
+		result.QuietHours = &dto.QuietHoursDTO{
+			Enabled:  prefs.QuietHours.Enabled,
+			Start:    prefs.QuietHours.Start,
+			End:      prefs.QuietHours.End,
+			Timezone: prefs.QuietHours.Timezone,
+		}
+	}
+	return result
+}
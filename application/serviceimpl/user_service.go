@@ -3,6 +3,9 @@ package serviceimpl
 
 import (
 	"errors"
+	"log"
+	"net/mail"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,12 +16,14 @@ import (
 )
 
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo    repository.UserRepository
+	authService serviceInterfaces.AuthService
 }
 
-func NewUserService(userRepo repository.UserRepository) serviceInterfaces.UserService {
+func NewUserService(userRepo repository.UserRepository, authService serviceInterfaces.AuthService) serviceInterfaces.UserService {
 	return &userService{
-		userRepo: userRepo,
+		userRepo:    userRepo,
+		authService: authService,
 	}
 }
 
@@ -73,6 +78,20 @@ func (s *userService) UpdateProfile(id uuid.UUID, data types.JSONB) (*models.Use
 		user.Bio = bio
 	}
 
+	// เปลี่ยนอีเมลต้องยืนยันอีเมลใหม่อีกครั้ง
+	emailChanged := false
+	if email, ok := data["email"].(string); ok {
+		email = strings.TrimSpace(email)
+		if email != user.Email {
+			if err := s.validateNewEmail(user.ID, email); err != nil {
+				return nil, err
+			}
+			user.Email = email
+			user.EmailVerifiedAt = nil
+			emailChanged = true
+		}
+	}
+
 	// อัปเดต settings ถ้ามี
 	if settings, ok := data["settings"].(types.JSONB); ok && user.Settings != nil {
 		// ต้องจัดการตามประเภทข้อมูลของ Settings ในโมเดล
@@ -87,9 +106,34 @@ func (s *userService) UpdateProfile(id uuid.UUID, data types.JSONB) (*models.Use
 		return nil, err
 	}
 
+	if emailChanged && s.authService != nil {
+		// ลิงก์รีเซ็ตรหัสผ่านที่ส่งไปอีเมลเดิมต้องใช้ไม่ได้
+		if err := s.authService.InvalidateAccountTokens(user.ID); err != nil {
+			log.Printf("Failed to invalidate account tokens: %v", err)
+		}
+		if err := s.authService.SendEmailVerification(user.ID); err != nil {
+			log.Printf("Failed to send email verification: %v", err)
+		}
+	}
+
 	return user, nil
 }
 
+// validateNewEmail ตรวจสอบรูปแบบอีเมลและไม่ซ้ำกับผู้ใช้อื่น
+func (s *userService) validateNewEmail(userID uuid.UUID, email string) error {
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email || len(email) > 255 {
+		return errors.New("invalid email address")
+	}
+
+	existing, err := s.userRepo.FindByEmail(email)
+	if err == nil && existing != nil && existing.ID != userID {
+		return errors.New("email already in use")
+	}
+
+	return nil
+}
+
 // SearchUsers ค้นหาผู้ใช้
 func (s *userService) SearchUsers(query string, limit, offset int) ([]*models.User, int, error) {
 	// ต้องมีเมธอด SearchUsers ใน UserRepository
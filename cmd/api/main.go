@@ -39,6 +39,15 @@ func main() {
 		log.Fatalf("StorageService error: %v", err)
 	}
 
+	// สร้าง push providers (FCM, APNs, WebPush)
+	pushProviders, err := configs.SetupPushProviders()
+	if err != nil {
+		log.Fatalf("PushProvider error: %v", err)
+	}
+
+	// สร้าง sender สำหรับ outgoing webhook ของบอท
+	webhookSender := configs.SetupBotWebhookSender()
+
 	// เชื่อมต่อกับ Redis
 	redisConfig := configs.LoadRedisConfig()
 	redisClient := redis.NewClient(&redis.Options{
@@ -55,8 +64,42 @@ func main() {
 	}
 	log.Println("Connected to Redis successfully")
 
-	// สร้าง container โดยส่ง storageService และ redisClient เข้าไป
-	container, err := di.NewContainer(database.DB, storageService, redisClient)
+	// สร้าง content filter chain (word list, link domain, duplicate flood)
+	contentFilters := configs.SetupContentFilters(redisClient)
+
+	// สร้าง rate limiter ของ REST API และตัวล็อกการเข้าสู่ระบบผิดซ้ำตาม IP และทั้งบัญชี (ใช้ Redis ร่วมกันทุก instance)
+	rateLimiter, loginGuard, accountGuard := configs.SetupRateLimiting(redisClient)
+
+	// สร้าง mailer สำหรับอีเมลรีเซ็ตรหัสผ่านและยืนยันอีเมล
+	mailer, err := configs.SetupMailer()
+	if err != nil {
+		log.Fatalf("Mailer error: %v", err)
+	}
+
+	// สร้างตัวเซ็น/ตรวจ JWT (RS256/EdDSA พร้อม kid หรือ HS256 แบบเดิม)
+	tokenSigner, err := configs.SetupTokenSigner()
+	if err != nil {
+		log.Fatalf("TokenSigner error: %v", err)
+	}
+
+	// โหลดคีย์เข้ารหัส TOTP secret ของ 2FA
+	twoFactorKey, err := configs.SetupTwoFactorEncryptionKey()
+	if err != nil {
+		log.Fatalf("TwoFactor error: %v", err)
+	}
+
+	// สร้าง OAuth2/OIDC providers สำหรับเข้าสู่ระบบผ่าน Google, Microsoft หรือ OIDC provider อื่น
+	oauthProviders, err := configs.SetupOAuthProviders()
+	if err != nil {
+		log.Fatalf("OAuthProvider error: %v", err)
+	}
+
+	// โหลด node ID ของ instance นี้ (ใช้แยก presence และ WebSocket fan-out ระหว่าง replica)
+	clusterConfig := configs.LoadClusterConfig()
+	log.Printf("Cluster node ID: %s", clusterConfig.NodeID)
+
+	// สร้าง container โดยส่ง storageService, redisClient, pushProviders, webhookSender, contentFilters, rate limiter, mailer, token signer, คีย์ 2FA, OAuth providers และ node ID เข้าไป
+	container, err := di.NewContainer(database.DB, storageService, redisClient, pushProviders, webhookSender, contentFilters, rateLimiter, loginGuard, accountGuard, mailer, tokenSigner, twoFactorKey, oauthProviders, clusterConfig.NodeID)
 	if err != nil {
 		log.Fatalf("ไม่สามารถสร้าง DI container ได้: %v", err)
 	}
@@ -79,7 +122,7 @@ func main() {
 	go container.WebSocketHub.Run(ctx)
 	log.Println("WebSocket Hub started successfully")
 
-	// เริ่ม Typing Cache Cleanup Routine
+	// เริ่ม Typing Cache Cleanup Routine (สถานะ typing ข้าม instance เก็บใน Redis)
 	websocket.StartTypingCacheCleanup()
 	log.Println("Typing cache cleanup routine started successfully")
 
@@ -87,6 +130,22 @@ func main() {
 	go container.FileCleanupScheduler.Start(ctx)
 	log.Println("File cleanup scheduler started successfully")
 
+	// เริ่ม Message Expiry Scheduler (ข้อความที่หายไปเอง)
+	go container.MessageExpiryScheduler.Start(ctx)
+	log.Println("Message expiry scheduler started successfully")
+
+	// เริ่ม Push Retry Scheduler
+	go container.PushRetryScheduler.Start(ctx)
+	log.Println("Push retry scheduler started successfully")
+
+	// เริ่ม Sync Tombstone Cleanup Scheduler
+	go container.SyncTombstoneCleanupScheduler.Start(ctx)
+	log.Println("Sync tombstone cleanup scheduler started successfully")
+
+	// เริ่ม Bot Webhook Retry Scheduler
+	go container.BotWebhookRetryScheduler.Start(ctx)
+	log.Println("Bot webhook retry scheduler started successfully")
+
 	// เริ่ม Scheduled Message Processor
 	go container.ScheduledMessageProcessor.Start(ctx)
 	log.Println("Scheduled message processor started successfully")
@@ -0,0 +1,56 @@
+// domain/dto/admin_dto.go
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============ Request DTOs ============
+
+// SuspendUserRequest สำหรับระงับบัญชีผู้ใช้
+type SuspendUserRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// DisableConversationRequest สำหรับปิดการสนทนา
+type DisableConversationRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// SetSystemRoleRequest สำหรับกำหนดบทบาทระดับแพลตฟอร์ม ("" = ถอดบทบาท)
+type SetSystemRoleRequest struct {
+	Role string `json:"role" validate:"omitempty,oneof=super_admin moderator support"`
+}
+
+// ============ Response DTOs ============
+
+// AdminMemberDTO ข้อมูลสมาชิกการสนทนาสำหรับผู้ดูแลระบบ
+type AdminMemberDTO struct {
+	UserID      uuid.UUID  `json:"user_id"`
+	Username    string     `json:"username"`
+	DisplayName string     `json:"display_name"`
+	Status      string     `json:"status"`
+	Role        string     `json:"role"`
+	JoinedAt    time.Time  `json:"joined_at"`
+	LastReadAt  *time.Time `json:"last_read_at,omitempty"`
+}
+
+// AdminConversationDTO ข้อมูลการสนทนาสำหรับการตรวจสอบโดยผู้ดูแลระบบ
+type AdminConversationDTO struct {
+	ID             uuid.UUID        `json:"id"`
+	Type           string           `json:"type"`
+	Title          string           `json:"title,omitempty"`
+	CreatorID      *uuid.UUID       `json:"creator_id,omitempty"`
+	IsActive       bool             `json:"is_active"`
+	DisabledAt     *time.Time       `json:"disabled_at,omitempty"`
+	DisabledReason string           `json:"disabled_reason,omitempty"`
+	MessageTTL     int              `json:"message_ttl"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+	LastMessageAt  *time.Time       `json:"last_message_at,omitempty"`
+	MessageCount   int64            `json:"message_count"`
+	MemberCount    int              `json:"member_count"`
+	Members        []AdminMemberDTO `json:"members"`
+}
@@ -20,8 +20,16 @@ type RegisterRequest struct {
 
 // LoginRequest สำหรับรับข้อมูลการเข้าสู่ระบบ
 type LoginRequest struct {
-	Username string `json:"username" validate:"required"`
-	Password string `json:"password" validate:"required"`
+	Username   string `json:"username" validate:"required"`
+	Password   string `json:"password" validate:"required"`
+	DeviceName string `json:"device_name,omitempty"` // ชื่ออุปกรณ์ที่แสดงในรายการ session
+}
+
+// SessionDeviceInfo ข้อมูลอุปกรณ์ของ session (จาก request ที่เข้าสู่ระบบ/รีเฟรช)
+type SessionDeviceInfo struct {
+	DeviceName string
+	UserAgent  string
+	IPAddress  string
 }
 
 // RefreshTokenRequest สำหรับรับ refresh token
@@ -64,6 +72,49 @@ type LogoutResponse struct {
 	GenericResponse
 }
 
+// SessionDTO ข้อมูล session ของอุปกรณ์ที่เข้าสู่ระบบอยู่
+type SessionDTO struct {
+	ID         uuid.UUID `json:"id"`
+	DeviceName string    `json:"device_name"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	IsCurrent  bool      `json:"is_current"`
+}
+
+// TwoFactorSetupDTO ข้อมูลสำหรับเพิ่มบัญชีใน authenticator app (แสดงครั้งเดียวตอนเริ่มเปิดใช้)
+type TwoFactorSetupDTO struct {
+	Secret          string `json:"secret"`           // base32 สำหรับกรอกเอง
+	ProvisioningURI string `json:"provisioning_uri"` // otpauth:// สำหรับสร้าง QR code
+}
+
+// TwoFactorStatusDTO สถานะ 2FA ของผู้ใช้
+type TwoFactorStatusDTO struct {
+	Enabled                bool       `json:"enabled"`
+	Pending                bool       `json:"pending"` // เริ่มตั้งค่าแล้วแต่ยังไม่ยืนยันรหัสแรก
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
+
+// OAuthAuthorizationDTO ข้อมูลสำหรับ redirect ผู้ใช้ไปยัง provider ภายนอก
+type OAuthAuthorizationDTO struct {
+	Provider         string    `json:"provider"`
+	AuthorizationURL string    `json:"authorization_url"`
+	State            string    `json:"state"` // client ต้องส่งกลับมาพร้อม code ที่ callback
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+// UserIdentityDTO บัญชีภายนอกที่เชื่อมกับผู้ใช้
+type UserIdentityDTO struct {
+	ID          uuid.UUID  `json:"id"`
+	Provider    string     `json:"provider"`
+	Email       string     `json:"email,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
 // UserProfileResponse สำหรับผลลัพธ์การดึงข้อมูลผู้ใช้ปัจจุบัน
 type UserProfileResponse struct {
 	Success bool         `json:"success"`
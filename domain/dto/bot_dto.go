@@ -0,0 +1,104 @@
+// domain/dto/bot_dto.go
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+// ============ Request DTOs ============
+
+// CreateBotRequest สำหรับสร้างบอทใหม่ (ผู้สร้างเป็นเจ้าของบอท)
+type CreateBotRequest struct {
+	Username        string `json:"username" validate:"required,min=3,max=50"`
+	DisplayName     string `json:"display_name" validate:"max=100"`
+	Description     string `json:"description" validate:"max=500"`
+	ProfileImageURL string `json:"profile_image_url"`
+	WebhookURL      string `json:"webhook_url"` // ว่าง = ไม่ส่ง webhook
+	ReceiveAll      bool   `json:"receive_all"` // true = รับทุกข้อความในกลุ่ม (ไม่ใช่เฉพาะ mention/command)
+}
+
+// UpdateBotRequest สำหรับแก้ไขบอท (field ที่เป็น nil = ไม่เปลี่ยน)
+type UpdateBotRequest struct {
+	DisplayName     *string `json:"display_name" validate:"omitempty,max=100"`
+	Description     *string `json:"description" validate:"omitempty,max=500"`
+	ProfileImageURL *string `json:"profile_image_url"`
+	WebhookURL      *string `json:"webhook_url"` // "" = ปิด webhook
+	ReceiveAll      *bool   `json:"receive_all"`
+	IsActive        *bool   `json:"is_active"`
+}
+
+// BotSendMessageRequest สำหรับบอทส่งข้อความเข้าการสนทนา
+type BotSendMessageRequest struct {
+	Content   string      `json:"content" validate:"required"`
+	ReplyToID *uuid.UUID  `json:"reply_to_id,omitempty"`
+	Metadata  types.JSONB `json:"metadata,omitempty"`
+	Mentions  types.JSONB `json:"mentions,omitempty"` // Format: [{"user_id": "uuid", "start_index": 0, "length": 10}]
+}
+
+// ============ Response DTOs ============
+
+// BotDTO ข้อมูลบอท
+type BotDTO struct {
+	ID              uuid.UUID  `json:"id"`
+	UserID          uuid.UUID  `json:"user_id"`
+	OwnerID         uuid.UUID  `json:"owner_id"`
+	Username        string     `json:"username"`
+	DisplayName     string     `json:"display_name,omitempty"`
+	ProfileImageURL string     `json:"profile_image_url,omitempty"`
+	Description     string     `json:"description,omitempty"`
+	TokenPrefix     string     `json:"token_prefix"`
+	WebhookURL      string     `json:"webhook_url,omitempty"`
+	ReceiveAll      bool       `json:"receive_all"`
+	IsActive        bool       `json:"is_active"`
+	LastUsedAt      *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// BotCredentialsDTO ข้อมูลบอทพร้อม credential ที่แสดงเพียงครั้งเดียว (ตอนสร้างหรือหมุนเวียน)
+type BotCredentialsDTO struct {
+	Bot           *BotDTO `json:"bot"`
+	Token         string  `json:"token,omitempty"`
+	WebhookSecret string  `json:"webhook_secret,omitempty"`
+}
+
+// BotWebhookPayload เนื้อหา JSON ที่ส่งไปยัง webhook ของบอท
+type BotWebhookPayload struct {
+	Event        string                 `json:"event"` // message, mention, command
+	BotID        uuid.UUID              `json:"bot_id"`
+	Conversation BotWebhookConversation `json:"conversation"`
+	Message      BotWebhookMessage      `json:"message"`
+	Sender       *UserInfoDTO           `json:"sender,omitempty"`
+	Command      *BotWebhookCommand     `json:"command,omitempty"`
+	Timestamp    time.Time              `json:"timestamp"`
+}
+
+// BotWebhookConversation ข้อมูลการสนทนาใน webhook payload
+type BotWebhookConversation struct {
+	ID    uuid.UUID `json:"id"`
+	Type  string    `json:"type"`
+	Title string    `json:"title,omitempty"`
+}
+
+// BotWebhookMessage ข้อมูลข้อความใน webhook payload
+type BotWebhookMessage struct {
+	ID           uuid.UUID   `json:"id"`
+	SenderID     *uuid.UUID  `json:"sender_id,omitempty"`
+	SenderType   string      `json:"sender_type"`
+	MessageType  string      `json:"message_type"`
+	Content      string      `json:"content,omitempty"`
+	MediaURL     string      `json:"media_url,omitempty"`
+	Mentions     types.JSONB `json:"mentions,omitempty"`
+	ReplyToID    *uuid.UUID  `json:"reply_to_id,omitempty"`
+	ThreadRootID *uuid.UUID  `json:"thread_root_id,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+// BotWebhookCommand slash-command ที่แยกจากข้อความ เช่น "/weather bangkok"
+type BotWebhookCommand struct {
+	Name string   `json:"name"` // ไม่รวม "/" และ "@bot"
+	Args []string `json:"args"`
+	Text string   `json:"text"` // ข้อความหลังชื่อคำสั่ง
+}
@@ -0,0 +1,21 @@
+// domain/dto/content_filter_dto.go
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+// UpdateContentFilterSettingsRequest สำหรับตั้งค่า content filter ของกลุ่ม
+// key = ชื่อ filter (word_list, link_domains, duplicate_flood), ค่า null = กลับไปใช้ค่าเริ่มต้นของระบบ
+// ตัวอย่าง: {"filters": {"word_list": {"enabled": true, "action": "mask", "words": ["..."]}}}
+type UpdateContentFilterSettingsRequest struct {
+	Filters map[string]types.JSONB `json:"filters" validate:"required"`
+}
+
+// ContentFilterSettingsDTO การตั้งค่า content filter ของกลุ่ม
+type ContentFilterSettingsDTO struct {
+	ConversationID uuid.UUID              `json:"conversation_id"`
+	Available      []string               `json:"available"` // filter ที่ระบบรองรับ (ตามลำดับใน chain)
+	Filters        map[string]types.JSONB `json:"filters"`   // การตั้งค่าที่กลุ่มกำหนด (ไม่มี = ค่าเริ่มต้นของระบบ)
+}
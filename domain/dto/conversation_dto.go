@@ -72,11 +72,23 @@ type ConversationMuteRequest struct {
 	IsMuted bool `json:"is_muted" validate:"required"`
 }
 
+// UpdateConversationNotificationSettingsRequest สำหรับตั้งค่าการแจ้งเตือนของการสนทนา (ฟิลด์ที่ไม่ส่งมาคงค่าเดิม)
+type UpdateConversationNotificationSettingsRequest struct {
+	Level      *string    `json:"level"`       // all, mentions, none
+	Muted      *bool      `json:"muted"`       // false = เปิดเสียง (ล้าง muted_until)
+	MutedUntil *time.Time `json:"muted_until"` // ปิดเสียงถึงเวลานี้แล้วเปิดเองอัตโนมัติ (ส่งมาอย่างเดียว = ปิดเสียงชั่วคราว)
+}
+
 // ConversationHideRequest สำหรับการซ่อน/แสดงการสนทนา
 type ConversationHideRequest struct {
 	IsHidden bool `json:"is_hidden" validate:"required"`
 }
 
+// ConversationMessageTTLRequest สำหรับตั้งค่าข้อความที่หายไปเอง (วินาที: 0, 3600, 86400, 604800)
+type ConversationMessageTTLRequest struct {
+	TTL int `json:"ttl" validate:"oneof=0 3600 86400 604800"`
+}
+
 // ============ Response DTOs ============
 
 // ConversationDTO โครงสร้างข้อมูลสำหรับส่งกลับข้อมูลการสนทนา
@@ -93,20 +105,24 @@ type ConversationDTO struct {
 	BusinessID      *uuid.UUID  `json:"business_id,omitempty"`
 	IsActive        bool        `json:"is_active"`
 	Metadata        types.JSONB `json:"metadata,omitempty"` // เพิ่มฟิลด์นี้
+	MessageTTL      int         `json:"message_ttl"`        // ข้อความที่หายไปเอง (วินาที) 0 = ปิด
 	MemberCount     int         `json:"member_count"`
 	UnreadCount     int         `json:"unread_count"`
+	MyRole          string      `json:"my_role,omitempty"` // role ของผู้ใช้ (ช่อง channel: subscriber โพสต์ไม่ได้)
 
 	// Mention-related fields
 	HasUnreadMention      bool `json:"has_unread_mention"`
 	UnreadMentionCount    int  `json:"unread_mention_count"`
 	LastMessageHasMention bool `json:"last_message_has_mention"`
 
-	IsPinned        bool        `json:"is_pinned"`
-	IsMuted         bool        `json:"is_muted"`
-	IsHidden        bool        `json:"is_hidden"`
-	HiddenAt        *time.Time  `json:"hidden_at,omitempty"`
-	ContactInfo     types.JSONB `json:"contact_info,omitempty"`
-	BusinessInfo    types.JSONB `json:"business_info,omitempty"`
+	IsPinned          bool        `json:"is_pinned"`
+	IsMuted           bool        `json:"is_muted"`
+	MutedUntil        *time.Time  `json:"muted_until,omitempty"`        // ปิดเสียงชั่วคราว (หมดอายุเอง)
+	NotificationLevel string      `json:"notification_level,omitempty"` // all, mentions, none
+	IsHidden          bool        `json:"is_hidden"`
+	HiddenAt          *time.Time  `json:"hidden_at,omitempty"`
+	ContactInfo       types.JSONB `json:"contact_info,omitempty"`
+	BusinessInfo      types.JSONB `json:"business_info,omitempty"`
 }
 
 // ConversationCreateResponse สำหรับผลลัพธ์การสร้างการสนทนา
@@ -136,6 +152,14 @@ type ConversationMuteResponse struct {
 	} `json:"data"`
 }
 
+// ConversationNotificationSettingsDTO การตั้งค่าการแจ้งเตือนของผู้ใช้ในการสนทนา
+type ConversationNotificationSettingsDTO struct {
+	ConversationID uuid.UUID  `json:"conversation_id"`
+	Level          string     `json:"level"`
+	IsMuted        bool       `json:"is_muted"` // คำนวณจาก muted_until แล้ว (หมดเวลา = false)
+	MutedUntil     *time.Time `json:"muted_until,omitempty"`
+}
+
 // ConversationHideResponse สำหรับผลลัพธ์การซ่อน/แสดงการสนทนา
 type ConversationHideResponse struct {
 	GenericResponse
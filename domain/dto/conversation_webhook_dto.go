@@ -0,0 +1,66 @@
+// domain/dto/conversation_webhook_dto.go
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============ Request DTOs ============
+
+// CreateConversationWebhookRequest สำหรับสร้าง incoming webhook ของการสนทนา
+type CreateConversationWebhookRequest struct {
+	Name      string `json:"name" validate:"required,max=80"` // ชื่อที่แสดงเป็นผู้ส่งข้อความ
+	AvatarURL string `json:"avatar_url" validate:"omitempty,url"`
+}
+
+// IncomingWebhookPayload ข้อมูลที่ระบบภายนอกส่งมาที่ POST /api/v1/hooks/:token
+// ต้องมี text หรือ attachments อย่างน้อยหนึ่งอย่าง
+type IncomingWebhookPayload struct {
+	Text        string                      `json:"text"`
+	DisplayName string                      `json:"display_name"` // override ชื่อผู้ส่งเฉพาะข้อความนี้
+	AvatarURL   string                      `json:"avatar_url"`   // override รูปผู้ส่งเฉพาะข้อความนี้
+	Attachments []IncomingWebhookAttachment `json:"attachments"`
+}
+
+// IncomingWebhookAttachment การ์ดแนบท้ายข้อความ (เช่น ผล build หรือ alert)
+type IncomingWebhookAttachment struct {
+	Title    string                           `json:"title,omitempty"`
+	TitleURL string                           `json:"title_url,omitempty"`
+	Text     string                           `json:"text,omitempty"`
+	Color    string                           `json:"color,omitempty"` // สีแถบด้านข้าง เช่น #36a64f
+	ImageURL string                           `json:"image_url,omitempty"`
+	Fields   []IncomingWebhookAttachmentField `json:"fields,omitempty"`
+	Footer   string                           `json:"footer,omitempty"`
+}
+
+// IncomingWebhookAttachmentField ข้อมูล key/value ใน attachment
+type IncomingWebhookAttachmentField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short,omitempty"` // แสดงคู่กันสองคอลัมน์ได้
+}
+
+// ============ Response DTOs ============
+
+// ConversationWebhookDTO ข้อมูล incoming webhook สำหรับผู้ดูแลกลุ่ม (ไม่มี token)
+type ConversationWebhookDTO struct {
+	ID             uuid.UUID    `json:"id"`
+	ConversationID uuid.UUID    `json:"conversation_id"`
+	Name           string       `json:"name"`
+	AvatarURL      string       `json:"avatar_url,omitempty"`
+	TokenPrefix    string       `json:"token_prefix"`
+	Creator        *UserInfoDTO `json:"creator,omitempty"`
+	IsActive       bool         `json:"is_active"`
+	LastUsedAt     *time.Time   `json:"last_used_at,omitempty"`
+	RevokedAt      *time.Time   `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+// ConversationWebhookCredentialsDTO ข้อมูล webhook พร้อม token (แสดงครั้งเดียวตอนสร้าง)
+type ConversationWebhookCredentialsDTO struct {
+	Webhook *ConversationWebhookDTO `json:"webhook"`
+	Token   string                  `json:"token"`
+	Path    string                  `json:"path"` // path สำหรับโพสต์ข้อความ เช่น /api/v1/hooks/<token>
+}
@@ -0,0 +1,78 @@
+// domain/dto/e2ee_dto.go
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+// ============ Request DTOs ============
+
+// PreKeyDTO public pre-key (base64)
+type PreKeyDTO struct {
+	KeyID     int    `json:"key_id"`
+	PublicKey string `json:"public_key" validate:"required"`
+}
+
+// SignedPreKeyDTO signed pre-key พร้อมลายเซ็นจาก identity key (base64)
+type SignedPreKeyDTO struct {
+	KeyID     int    `json:"key_id"`
+	PublicKey string `json:"public_key" validate:"required"`
+	Signature string `json:"signature" validate:"required"`
+}
+
+// UploadKeyBundleRequest สำหรับอัปโหลด key bundle ของอุปกรณ์
+type UploadKeyBundleRequest struct {
+	RegistrationID int             `json:"registration_id"`
+	IdentityKey    string          `json:"identity_key" validate:"required"`
+	SignedPreKey   SignedPreKeyDTO `json:"signed_pre_key" validate:"required"`
+	OneTimePreKeys []PreKeyDTO     `json:"one_time_pre_keys,omitempty" validate:"max=100,dive"`
+}
+
+// UploadPreKeysRequest สำหรับเติม one-time pre-key
+type UploadPreKeysRequest struct {
+	OneTimePreKeys []PreKeyDTO `json:"one_time_pre_keys" validate:"required,min=1,max=100,dive"`
+}
+
+// EncryptedPayloadDTO ciphertext สำหรับอุปกรณ์ผู้รับหนึ่งเครื่อง
+type EncryptedPayloadDTO struct {
+	UserID   uuid.UUID `json:"user_id" validate:"required"`
+	DeviceID string    `json:"device_id" validate:"required"`
+	Type     string    `json:"type" validate:"required,oneof=prekey message"` // prekey = ข้อความแรกของ session (X3DH)
+	Body     string    `json:"body" validate:"required"`                      // base64 ciphertext
+}
+
+// EncryptedMessageRequest สำหรับส่งข้อความเข้ารหัสแบบ end-to-end (เฉพาะการสนทนาแบบ direct)
+type EncryptedMessageRequest struct {
+	TempID         string                `json:"temp_id,omitempty"`
+	SenderDeviceID string                `json:"sender_device_id" validate:"required"`
+	Ciphertexts    []EncryptedPayloadDTO `json:"ciphertexts" validate:"required,min=1,dive"`
+	Metadata       types.JSONB           `json:"metadata,omitempty"`
+}
+
+// ============ Response DTOs ============
+
+// KeyBundleDTO key bundle ของอุปกรณ์สำหรับเริ่ม session (X3DH)
+type KeyBundleDTO struct {
+	UserID         uuid.UUID       `json:"user_id"`
+	DeviceID       string          `json:"device_id"`
+	RegistrationID int             `json:"registration_id"`
+	IdentityKey    string          `json:"identity_key"`
+	SignedPreKey   SignedPreKeyDTO `json:"signed_pre_key"`
+	OneTimePreKey  *PreKeyDTO      `json:"one_time_pre_key,omitempty"` // nil = pre-key หมด ใช้ signed pre-key อย่างเดียว
+}
+
+// EncryptedEnvelope รูปแบบของ Message.Content สำหรับข้อความประเภท encrypted
+type EncryptedEnvelope struct {
+	Version        int                   `json:"v"`
+	SenderDeviceID string                `json:"sender_device_id"`
+	Ciphertexts    []EncryptedPayloadDTO `json:"ciphertexts"`
+}
+
+// KeyChangedEvent ข้อมูลของ event e2ee.key_changed
+type KeyChangedEvent struct {
+	UserID      uuid.UUID `json:"user_id"`
+	DeviceID    string    `json:"device_id"`
+	Change      string    `json:"change"` // identity_changed, device_removed
+	IdentityKey string    `json:"identity_key,omitempty"`
+}
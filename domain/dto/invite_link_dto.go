@@ -0,0 +1,66 @@
+// domain/dto/invite_link_dto.go
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============ Request DTOs ============
+
+// CreateInviteLinkRequest สำหรับสร้างลิงก์เชิญเข้ากลุ่ม
+type CreateInviteLinkRequest struct {
+	ExpiresIn        int  `json:"expires_in" validate:"min=0"` // อายุลิงก์ (วินาที) 0 = ไม่หมดอายุ
+	MaxUses          int  `json:"max_uses" validate:"min=0"`   // 0 = ไม่จำกัด
+	RequiresApproval bool `json:"requires_approval"`
+}
+
+// JoinViaInviteLinkRequest สำหรับเข้าร่วมกลุ่มผ่านลิงก์ (message ใช้เมื่อลิงก์ต้องให้แอดมินอนุมัติ)
+type JoinViaInviteLinkRequest struct {
+	Message string `json:"message" validate:"max=500"`
+}
+
+// ============ Response DTOs ============
+
+// InviteLinkDTO ข้อมูลลิงก์เชิญสำหรับผู้ดูแลกลุ่ม
+type InviteLinkDTO struct {
+	ID               uuid.UUID    `json:"id"`
+	ConversationID   uuid.UUID    `json:"conversation_id"`
+	Code             string       `json:"code"`
+	Creator          *UserInfoDTO `json:"creator,omitempty"`
+	ExpiresAt        *time.Time   `json:"expires_at,omitempty"`
+	MaxUses          int          `json:"max_uses"`
+	UseCount         int          `json:"use_count"`
+	RequiresApproval bool         `json:"requires_approval"`
+	IsActive         bool         `json:"is_active"`
+	RevokedAt        *time.Time   `json:"revoked_at,omitempty"`
+	CreatedAt        time.Time    `json:"created_at"`
+}
+
+// InviteLinkPreviewDTO ข้อมูลกลุ่มที่แสดงให้ผู้ที่ยังไม่เป็นสมาชิกก่อนเข้าร่วม
+type InviteLinkPreviewDTO struct {
+	Code             string     `json:"code"`
+	ConversationID   uuid.UUID  `json:"conversation_id"`
+	Type             string     `json:"type"` // group, channel
+	Title            string     `json:"title"`
+	IconURL          string     `json:"icon_url,omitempty"`
+	MemberCount      int64      `json:"member_count"`
+	RequiresApproval bool       `json:"requires_approval"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	IsMember         bool       `json:"is_member"`
+}
+
+// สถานะผลการเข้าร่วมกลุ่มผ่านลิงก์เชิญ
+const (
+	InviteJoinStatusJoined  = "joined"
+	InviteJoinStatusPending = "pending" // ลิงก์ต้องให้แอดมินอนุมัติ สร้างคำขอเข้าร่วมแล้ว
+)
+
+// JoinViaInviteLinkResponse ผลการเข้าร่วมกลุ่มผ่านลิงก์เชิญ
+type JoinViaInviteLinkResponse struct {
+	ConversationID uuid.UUID       `json:"conversation_id"`
+	Status         string          `json:"status"`
+	Member         *MemberDTO      `json:"member,omitempty"`
+	JoinRequest    *JoinRequestDTO `json:"join_request,omitempty"`
+}
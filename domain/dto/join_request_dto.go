@@ -0,0 +1,31 @@
+// domain/dto/join_request_dto.go
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============ Request DTOs ============
+
+// SubmitJoinRequestRequest สำหรับส่งคำขอเข้าร่วมกลุ่ม
+type SubmitJoinRequestRequest struct {
+	Message string `json:"message" validate:"max=500"`
+}
+
+// ============ Response DTOs ============
+
+// JoinRequestDTO ข้อมูลคำขอเข้าร่วมกลุ่ม
+type JoinRequestDTO struct {
+	ID                uuid.UUID    `json:"id"`
+	ConversationID    uuid.UUID    `json:"conversation_id"`
+	ConversationTitle string       `json:"conversation_title,omitempty"`
+	User              *UserInfoDTO `json:"user,omitempty"`
+	Message           string       `json:"message,omitempty"`
+	Status            string       `json:"status"`
+	InviteLinkID      *uuid.UUID   `json:"invite_link_id,omitempty"`
+	ReviewedBy        *uuid.UUID   `json:"reviewed_by,omitempty"`
+	ReviewedAt        *time.Time   `json:"reviewed_at,omitempty"`
+	CreatedAt         time.Time    `json:"created_at"`
+}
@@ -59,6 +59,32 @@ type ReplyMessageRequest struct {
 	Metadata          types.JSONB `json:"metadata,omitempty"`
 }
 
+// MessageReactionRequest สำหรับการเพิ่ม/ลบปฏิกิริยาต่อข้อความ
+type MessageReactionRequest struct {
+	Emoji string `json:"emoji" validate:"required,max=32"`
+}
+
+// PollMessageRequest สำหรับการสร้างข้อความประเภทโพล
+type PollMessageRequest struct {
+	TempID        string      `json:"temp_id,omitempty"`
+	Question      string      `json:"question" validate:"required,max=500"`
+	Options       []string    `json:"options" validate:"required,min=2,max=12,dive,required,max=200"`
+	AllowMultiple bool        `json:"allow_multiple"`
+	IsAnonymous   bool        `json:"is_anonymous"`
+	ClosesAt      *time.Time  `json:"closes_at,omitempty"`
+	Metadata      types.JSONB `json:"metadata,omitempty"`
+}
+
+// PollVoteRequest สำหรับการโหวต/ถอนโหวตในโพล
+type PollVoteRequest struct {
+	OptionIDs []uuid.UUID `json:"option_ids" validate:"required,min=1"`
+}
+
+// PollPermissionRequest สำหรับกำหนดว่าใครสร้างโพลในกลุ่มได้ (all, admins, owner)
+type PollPermissionRequest struct {
+	Level string `json:"level" validate:"required,oneof=all admins owner"`
+}
+
 // BulkMessageRequest สำหรับส่งหลายไฟล์ใน 1 message (Album/Group Message)
 type BulkMessageRequest struct {
 	Messages []BulkMessageItem `json:"messages" validate:"required,min=1,max=10,dive"`
@@ -121,7 +147,7 @@ type MessageDTO struct {
 	TempID            string     `json:"temp_id,omitempty"` // Temporary ID from frontend
 	ConversationID    uuid.UUID  `json:"conversation_id"`
 	SenderID          *uuid.UUID `json:"sender_id"`
-	SenderType        string     `json:"sender_type"` // user, business, system
+	SenderType        string     `json:"sender_type"` // user, bot, webhook, business, system
 	SenderName        string     `json:"sender_name,omitempty"`
 	SenderAvatar      string     `json:"sender_avatar,omitempty"`
 	MessageType       string     `json:"message_type"` // text, image, file, sticker, album
@@ -150,6 +176,7 @@ type MessageDTO struct {
 	IsDeleted bool       `json:"is_deleted"`
 	IsEdited  bool       `json:"is_edited"`
 	EditCount int        `json:"edit_count"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // ข้อความที่หายไปเอง
 	DeletedBy *uuid.UUID `json:"deleted_by,omitempty"`
 	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 
@@ -159,11 +186,25 @@ type MessageDTO struct {
 	Status         string      `json:"status"` // sent, delivered, read, failed
 	ReadByIDs      []uuid.UUID `json:"read_by_ids,omitempty"`
 	DeliveredToIDs []uuid.UUID `json:"delivered_to_ids,omitempty"`
+	ViewCount      int64       `json:"view_count,omitempty"` // ยอดผู้ชม (ข้อความในช่อง channel)
 
 	// ข้อมูลการตอบกลับ
 	ReplyToID      *uuid.UUID    `json:"reply_to_id,omitempty"`
 	ReplyToMessage *ReplyInfoDTO `json:"reply_to_message,omitempty"`
 
+	// ข้อมูลเธรด
+	ThreadRootID      *uuid.UUID    `json:"thread_root_id,omitempty"`       // มีค่าเมื่อเป็นข้อความในเธรด
+	ThreadReplyCount  int           `json:"thread_reply_count,omitempty"`   // จำนวนข้อความในเธรด (ข้อความต้นเธรด)
+	ThreadLastReplyAt *time.Time    `json:"thread_last_reply_at,omitempty"` // เวลาของข้อความล่าสุดในเธรด
+	ThreadLastReply   *ReplyInfoDTO `json:"thread_last_reply,omitempty"`    // ตัวอย่างข้อความล่าสุดในเธรด
+	ThreadUnreadCount int           `json:"thread_unread_count,omitempty"`  // จำนวนข้อความในเธรดที่ผู้ใช้ยังไม่ได้อ่าน
+
+	// ข้อมูลปฏิกิริยา (emoji) ต่อข้อความ
+	Reactions []MessageReactionSummaryDTO `json:"reactions,omitempty"`
+
+	// ข้อมูลโพล (message_type = poll)
+	Poll *PollDTO `json:"poll,omitempty"`
+
 	// ข้อมูลการ Forward
 	IsForwarded   bool               `json:"is_forwarded"`
 	ForwardedFrom *ForwardedFromDTO `json:"forwarded_from,omitempty"`
@@ -181,6 +222,48 @@ type MessageDTO struct {
 	Conversation *ConversationBasicDTO `json:"conversation,omitempty"`
 }
 
+// MessageReactionSummaryDTO สรุปปฏิกิริยาต่อข้อความแยกตาม emoji
+type MessageReactionSummaryDTO struct {
+	Emoji       string `json:"emoji"`
+	Count       int    `json:"count"`
+	ReactedByMe bool   `json:"reacted_by_me"`
+}
+
+// PollDTO ข้อมูลโพลพร้อมผลโหวต
+type PollDTO struct {
+	ID            uuid.UUID       `json:"id"`
+	MessageID     uuid.UUID       `json:"message_id"`
+	CreatorID     uuid.UUID       `json:"creator_id"`
+	Question      string          `json:"question"`
+	AllowMultiple bool            `json:"allow_multiple"`
+	IsAnonymous   bool            `json:"is_anonymous"`
+	ClosesAt      *time.Time      `json:"closes_at,omitempty"`
+	IsClosed      bool            `json:"is_closed"`
+	ClosedAt      *time.Time      `json:"closed_at,omitempty"`
+	ClosedBy      *uuid.UUID      `json:"closed_by,omitempty"`
+	Options       []PollOptionDTO `json:"options"`
+	TotalVotes    int             `json:"total_votes"`
+	TotalVoters   int             `json:"total_voters"`
+	MyVotes       []uuid.UUID     `json:"my_votes,omitempty"`
+}
+
+// PollOptionDTO ตัวเลือกของโพลพร้อมจำนวนโหวต (VoterIDs มีเฉพาะโพลที่ไม่ anonymous)
+type PollOptionDTO struct {
+	ID        uuid.UUID   `json:"id"`
+	Text      string      `json:"text"`
+	Position  int         `json:"position"`
+	VoteCount int         `json:"vote_count"`
+	VotedByMe bool        `json:"voted_by_me"`
+	VoterIDs  []uuid.UUID `json:"voter_ids,omitempty"`
+}
+
+// ThreadDTO ข้อมูลเธรดพร้อมข้อความตอบกลับ (cursor-based)
+type ThreadDTO struct {
+	Root    *MessageDTO   `json:"root"`
+	Replies []*MessageDTO `json:"replies"`
+	HasMore bool          `json:"has_more"`
+}
+
 // ConversationBasicDTO ข้อมูลพื้นฐานของ Conversation สำหรับ search results
 type ConversationBasicDTO struct {
 	ID      uuid.UUID `json:"id"`
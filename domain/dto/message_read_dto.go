@@ -60,6 +60,12 @@ type GetMessageReadsResponse struct {
 	} `json:"data"`
 }
 
+// MessageViewsDTO ยอดผู้ชมของข้อความในช่อง channel (ใช้แทนรายชื่อผู้อ่าน)
+type MessageViewsDTO struct {
+	MessageID uuid.UUID `json:"message_id"`
+	ViewCount int64     `json:"view_count"`
+}
+
 // MarkAllMessagesAsReadResponse เป็น DTO สำหรับการตอบกลับการมาร์คข้อความทั้งหมดเป็นอ่านแล้ว
 type MarkAllMessagesAsReadResponse struct {
 	GenericResponse
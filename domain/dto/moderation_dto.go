@@ -0,0 +1,41 @@
+// domain/dto/moderation_dto.go
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============ Request DTOs ============
+
+// CreateReportRequest สำหรับรายงานข้อความ ผู้ใช้ หรือการสนทนา
+type CreateReportRequest struct {
+	Reason      string `json:"reason" validate:"required,oneof=spam harassment hate_speech violence sexual_content scam impersonation other"`
+	Description string `json:"description" validate:"max=1000"` // ต้องระบุเมื่อ reason = other
+}
+
+// UpdateReportStatusRequest สำหรับเปลี่ยนสถานะรายงานในคิว (in_review, open, dismissed)
+type UpdateReportStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=open in_review dismissed"`
+	Note   string `json:"note" validate:"max=1000"`
+}
+
+// ModerationActionRequest สำหรับดำเนินการกับรายงาน (ปิดรายงานเป็น resolved)
+// note ใช้เป็นเหตุผลของการลบข้อความ/ระงับบัญชี/ปิดการสนทนาด้วย
+type ModerationActionRequest struct {
+	Action string `json:"action" validate:"required,oneof=delete_message suspend_user disable_conversation"`
+	Note   string `json:"note" validate:"max=500"`
+}
+
+// ============ Response DTOs ============
+
+// ReportDTO ข้อมูลรายงานที่แสดงให้ผู้รายงาน
+type ReportDTO struct {
+	ID         uuid.UUID `json:"id"`
+	TargetType string    `json:"target_type"`
+	TargetID   uuid.UUID `json:"target_id"`
+	Reason     string    `json:"reason"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -16,3 +16,22 @@ type FriendAcceptNotification struct {
 	ProfileImageURL string    `json:"profile_image_url"`
 	AcceptedAt      time.Time `json:"accepted_at"`
 }
+
+// NotificationDTO รายการในกล่องแจ้งเตือน
+type NotificationDTO struct {
+	ID             uuid.UUID              `json:"id"`
+	Type           string                 `json:"type"`
+	Actor          *UserInfoDTO           `json:"actor,omitempty"`
+	TargetType     string                 `json:"target_type,omitempty"`
+	TargetID       *uuid.UUID             `json:"target_id,omitempty"`
+	ConversationID *uuid.UUID             `json:"conversation_id,omitempty"`
+	Payload        map[string]interface{} `json:"payload"`
+	IsRead         bool                   `json:"is_read"`
+	ReadAt         *time.Time             `json:"read_at,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
+
+// MarkNotificationsReadRequest สำหรับทำเครื่องหมายว่าอ่านแล้วหลายรายการ
+type MarkNotificationsReadRequest struct {
+	IDs []uuid.UUID `json:"ids"`
+}
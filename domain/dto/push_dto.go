@@ -0,0 +1,19 @@
+// domain/dto/push_dto.go
+package dto
+
+// ============ Request DTOs ============
+
+// WebPushKeys keys ของ PushSubscription จาก browser
+type WebPushKeys struct {
+	P256dh string `json:"p256dh"`
+	Auth   string `json:"auth"`
+}
+
+// RegisterDeviceRequest สำหรับการลงทะเบียนอุปกรณ์รับ push
+type RegisterDeviceRequest struct {
+	Provider   string       `json:"provider" validate:"required,oneof=fcm apns webpush fake"`
+	Token      string       `json:"token" validate:"required"` // WebPush: subscription endpoint
+	Platform   string       `json:"platform,omitempty" validate:"omitempty,oneof=ios android web"`
+	DeviceName string       `json:"device_name,omitempty" validate:"omitempty,max=100"`
+	Keys       *WebPushKeys `json:"keys,omitempty"` // จำเป็นสำหรับ webpush
+}
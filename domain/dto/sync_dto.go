@@ -0,0 +1,81 @@
+// domain/dto/sync_dto.go
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+// ============ Response DTOs ============
+
+// SyncResponse ข้อมูลที่เปลี่ยนแปลงตั้งแต่ sync token ล่าสุด
+// ทุกรายการเรียงจากเก่าไปใหม่ client ควร upsert ตาม id และลบรายการใน Deleted ออกจาก local store
+type SyncResponse struct {
+	NextToken string `json:"next_token"`
+	HasMore   bool   `json:"has_more"` // true = เรียก /sync ซ้ำด้วย next_token ทันที
+
+	Messages      []*MessageDTO        `json:"messages"`
+	Conversations []*ConversationDTO   `json:"conversations"`
+	Members       []*SyncMemberDTO     `json:"members"`
+	Activities    []*ActivityDTO       `json:"activities"`
+	Friendships   []*SyncFriendshipDTO `json:"friendships"`
+	Pins          []*PinnedMessageDTO  `json:"pins"`
+	Notes         []*SyncNoteDTO       `json:"notes"`
+	Deleted       []*SyncTombstoneDTO  `json:"deleted"`
+
+	// ReloadConversationIDs การสนทนาที่ผู้ใช้เพิ่งเข้าร่วม ต้องโหลดประวัติผ่าน /conversations/:id/messages
+	ReloadConversationIDs []uuid.UUID `json:"reload_conversation_ids"`
+}
+
+// SyncMemberDTO สถานะสมาชิก (role, read pointer และการตั้งค่าส่วนตัว)
+type SyncMemberDTO struct {
+	ID             uuid.UUID  `json:"id"`
+	ConversationID uuid.UUID  `json:"conversation_id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	Role           string     `json:"role"`
+	JoinedAt       time.Time  `json:"joined_at"`
+	LastReadAt     *time.Time `json:"last_read_at,omitempty"`
+	Nickname       string     `json:"nickname,omitempty"`
+
+	// การตั้งค่าส่วนตัว (ส่งเฉพาะแถวของผู้ใช้เอง)
+	IsMuted              *bool       `json:"is_muted,omitempty"`
+	IsPinned             *bool       `json:"is_pinned,omitempty"`
+	IsHidden             *bool       `json:"is_hidden,omitempty"`
+	NotificationSettings types.JSONB `json:"notification_settings,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SyncFriendshipDTO ความสัมพันธ์เพื่อน
+type SyncFriendshipDTO struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      uuid.UUID `json:"user_id"`
+	FriendID    uuid.UUID `json:"friend_id"`
+	Status      string    `json:"status"`
+	RequestedAt time.Time `json:"requested_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SyncNoteDTO โน้ตส่วนตัวหรือโน้ตที่แชร์ในการสนทนา
+type SyncNoteDTO struct {
+	ID             uuid.UUID   `json:"id"`
+	UserID         uuid.UUID   `json:"user_id"`
+	ConversationID *uuid.UUID  `json:"conversation_id,omitempty"`
+	Title          string      `json:"title"`
+	Content        string      `json:"content"`
+	Tags           types.JSONB `json:"tags,omitempty"`
+	IsPinned       bool        `json:"is_pinned"`
+	Visibility     string      `json:"visibility"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+}
+
+// SyncTombstoneDTO ข้อมูลที่ถูกลบ (entity_type: member, note, pin, friendship)
+type SyncTombstoneDTO struct {
+	EntityType     string     `json:"entity_type"`
+	EntityID       uuid.UUID  `json:"entity_id"`
+	ConversationID *uuid.UUID `json:"conversation_id,omitempty"`
+	DeletedAt      time.Time  `json:"deleted_at"`
+}
@@ -28,6 +28,12 @@ type UpdateProfileRequest struct {
 	Status      string  `json:"status" validate:"omitempty,oneof=online away offline busy"`
 }
 
+// UpdateNotificationPreferencesRequest สำหรับตั้งค่าการแจ้งเตือนระดับผู้ใช้ (ฟิลด์ที่ไม่ส่งมาคงค่าเดิม)
+type UpdateNotificationPreferencesRequest struct {
+	QuietHours    *QuietHoursDTO `json:"quiet_hours"`
+	KeywordAlerts *[]string      `json:"keyword_alerts"`
+}
+
 // UploadProfileImageRequest สำหรับการอัปโหลดรูปโปรไฟล์
 // ใช้ multipart/form-data ไม่ใช่ JSON
 type UploadProfileImageRequest struct {
@@ -69,6 +75,21 @@ type PublicUserData struct {
 	LastActiveAt    *time.Time `json:"last_active_at,omitempty"`
 }
 
+// QuietHoursDTO ช่วงเวลางดส่ง push ตามเขตเวลาของผู้ใช้
+type QuietHoursDTO struct {
+	Enabled  bool   `json:"enabled"`
+	Start    string `json:"start"`    // HH:MM
+	End      string `json:"end"`      // HH:MM
+	Timezone string `json:"timezone"` // IANA เช่น Asia/Bangkok
+}
+
+// NotificationPreferencesDTO การตั้งค่าการแจ้งเตือนระดับผู้ใช้
+type NotificationPreferencesDTO struct {
+	QuietHours    *QuietHoursDTO `json:"quiet_hours"`
+	QuietNow      bool           `json:"quiet_now"` // อยู่ในช่วง quiet hours ขณะนี้
+	KeywordAlerts []string       `json:"keyword_alerts"`
+}
+
 // UserStatusItem ข้อมูลสถานะของผู้ใช้
 type UserStatusItem struct {
 	UserID       uuid.UUID  `json:"user_id"`
@@ -0,0 +1,40 @@
+// domain/models/account_token.go
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// วัตถุประสงค์ของ AccountToken
+const (
+	AccountTokenPasswordReset     = "password_reset"
+	AccountTokenEmailVerification = "email_verification"
+)
+
+// AccountToken - token แบบใช้ครั้งเดียวที่ส่งทางอีเมล (รีเซ็ตรหัสผ่าน, ยืนยันอีเมล)
+// เก็บเฉพาะ hash ของ token token จริงอยู่ในลิงก์ที่ส่งให้ผู้ใช้เท่านั้น
+type AccountToken struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	Purpose   string     `json:"purpose" gorm:"type:varchar(30);not null"`
+	TokenHash string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"` // SHA-256 ของ token
+	Email     string     `json:"email" gorm:"type:varchar(255);not null"`        // อีเมลที่ token ถูกส่งไป (ใช้ตรวจว่าอีเมลยังไม่ถูกเปลี่ยน)
+	ExpiresAt time.Time  `json:"expires_at" gorm:"type:timestamp with time zone;not null"`
+	UsedAt    *time.Time `json:"used_at,omitempty" gorm:"type:timestamp with time zone"` // ใช้แล้วหรือถูกแทนที่ด้วย token ใหม่
+	CreatedAt time.Time  `json:"created_at" gorm:"type:timestamp with time zone;default:now()"`
+
+	// Associations
+	User *User `json:"user,omitempty" gorm:"foreignkey:UserID"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (AccountToken) TableName() string {
+	return "account_tokens"
+}
+
+// IsUsable ตรวจสอบว่า token ยังไม่ถูกใช้และยังไม่หมดอายุ
+func (t *AccountToken) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && t.ExpiresAt.After(now)
+}
@@ -0,0 +1,54 @@
+// domain/models/admin_audit_log.go
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+// AdminAuditLog บันทึกทุกการกระทำผ่าน /api/v1/admin (รวมการเรียกดูข้อมูล)
+type AdminAuditLog struct {
+	ID         uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ActorID    uuid.UUID   `json:"actor_id" gorm:"type:uuid;not null;index"`
+	ActorRole  string      `json:"actor_role" gorm:"type:varchar(20);not null"`
+	Action     string      `json:"action" gorm:"type:varchar(50);not null;index"`
+	TargetType string      `json:"target_type,omitempty" gorm:"type:varchar(30)"`
+	TargetID   *uuid.UUID  `json:"target_id,omitempty" gorm:"type:uuid;index"`
+	StatusCode int         `json:"status_code"`
+	Details    types.JSONB `json:"details,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"`
+	IPAddress  string      `json:"ip_address,omitempty" gorm:"type:varchar(45)"`
+	CreatedAt  time.Time   `json:"created_at" gorm:"type:timestamp with time zone;default:now();index"`
+
+	// Associations
+	Actor *User `json:"actor,omitempty" gorm:"foreignkey:ActorID"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (AdminAuditLog) TableName() string {
+	return "admin_audit_logs"
+}
+
+// System roles (บทบาทระดับแพลตฟอร์ม แยกจาก role ในการสนทนา)
+const (
+	SystemRoleSuperAdmin = "super_admin" // ทำได้ทุกอย่าง รวมถึงกำหนดบทบาทผู้อื่น
+	SystemRoleModerator  = "moderator"   // ระงับผู้ใช้ ตรวจสอบการสนทนา จัดการสติกเกอร์
+	SystemRoleSupport    = "support"     // ดูข้อมูลผู้ใช้/การสนทนา และสถิติระบบ
+)
+
+// สถานะบัญชีผู้ใช้ (User.Status)
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+	UserStatusDeleted   = "deleted" // บัญชีบอทที่ถูกลบ (เก็บไว้เพื่อให้ข้อความเดิมยังอ้างอิงผู้ส่งได้)
+)
+
+// IsValidSystemRole ตรวจสอบชื่อบทบาท ("" = ผู้ใช้ทั่วไป)
+func IsValidSystemRole(role string) bool {
+	switch role {
+	case "", SystemRoleSuperAdmin, SystemRoleModerator, SystemRoleSupport:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+// domain/models/bot.go
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+// Message.SenderType
+const (
+	SenderTypeUser    = "user"
+	SenderTypeBot     = "bot"     // ข้อความที่ส่งผ่าน Bot API
+	SenderTypeWebhook = "webhook" // ข้อความจาก incoming webhook ของการสนทนา (ไม่มี sender_id)
+)
+
+// Bot webhook events (ประเภท event ที่ส่งไปยัง webhook ของบอท)
+const (
+	BotEventMessage = "message" // ข้อความใหม่ในการสนทนาที่บอทเป็นสมาชิก
+	BotEventMention = "mention" // บอทถูก mention
+	BotEventCommand = "command" // ข้อความขึ้นต้นด้วย slash-command เช่น /help
+)
+
+// Bot webhook delivery statuses
+const (
+	BotDeliveryPending = "pending" // รอส่ง / รอ retry
+	BotDeliverySent    = "sent"    // webhook ตอบกลับ 2xx
+	BotDeliveryFailed  = "failed"  // ส่งไม่สำเร็จและหมดจำนวน retry แล้ว
+	BotDeliveryDropped = "dropped" // ไม่ retry (เช่น บอทถูกลบหรือปิด webhook)
+)
+
+// Bot - บัญชีบอท (ผูกกับ User ที่ is_bot = true และมีเจ้าของเป็นผู้ใช้ทั่วไป)
+type Bot struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID        uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;uniqueIndex"`
+	OwnerID       uuid.UUID  `json:"owner_id" gorm:"type:uuid;not null;index"`
+	Description   string     `json:"description,omitempty" gorm:"type:text"`
+	TokenHash     string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"` // SHA-256 ของ API token (token จริงแสดงครั้งเดียวตอนสร้าง)
+	TokenPrefix   string     `json:"token_prefix" gorm:"type:varchar(20);not null"`  // ส่วนต้นของ token สำหรับแสดงผล
+	WebhookURL    string     `json:"webhook_url,omitempty" gorm:"type:text"`         // ว่าง = ไม่ส่ง webhook
+	WebhookSecret string     `json:"-" gorm:"type:varchar(128)"`                     // ใช้ลงลายมือชื่อ HMAC-SHA256
+	ReceiveAll    bool       `json:"receive_all" gorm:"default:false"`               // false = ในกลุ่มรับเฉพาะ mention/command
+	IsActive      bool       `json:"is_active" gorm:"default:true"`
+	LastUsedAt    *time.Time `json:"last_used_at,omitempty" gorm:"type:timestamp with time zone"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"type:timestamp with time zone;default:now()"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"type:timestamp with time zone;default:now()"`
+
+	// Associations
+	User  *User `json:"user,omitempty" gorm:"foreignkey:UserID"`
+	Owner *User `json:"owner,omitempty" gorm:"foreignkey:OwnerID"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (Bot) TableName() string {
+	return "bots"
+}
+
+// HasWebhook ตรวจสอบว่าบอทพร้อมรับ webhook หรือไม่
+func (b *Bot) HasWebhook() bool {
+	return b.IsActive && b.WebhookURL != ""
+}
+
+// BotWebhookDelivery - บันทึกการส่ง webhook แต่ละครั้ง (ใช้ retry และให้เจ้าของบอทตรวจสอบ)
+type BotWebhookDelivery struct {
+	ID             uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	BotID          uuid.UUID   `json:"bot_id" gorm:"type:uuid;not null;index"`
+	Event          string      `json:"event" gorm:"type:varchar(20);not null"` // message, mention, command
+	ConversationID *uuid.UUID  `json:"conversation_id,omitempty" gorm:"type:uuid"`
+	MessageID      *uuid.UUID  `json:"message_id,omitempty" gorm:"type:uuid"`
+	Payload        types.JSONB `json:"payload" gorm:"type:jsonb;default:'{}'::jsonb"`
+	Status         string      `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"`
+	Attempts       int         `json:"attempts" gorm:"default:0"`
+	ResponseStatus int         `json:"response_status,omitempty" gorm:"default:0"` // HTTP status ล่าสุดจาก webhook
+	LastError      string      `json:"last_error,omitempty" gorm:"type:text"`
+	NextRetryAt    *time.Time  `json:"next_retry_at,omitempty" gorm:"type:timestamp with time zone;index"`
+	DeliveredAt    *time.Time  `json:"delivered_at,omitempty" gorm:"type:timestamp with time zone"`
+	CreatedAt      time.Time   `json:"created_at" gorm:"type:timestamp with time zone;default:now()"`
+	UpdatedAt      time.Time   `json:"updated_at" gorm:"type:timestamp with time zone;default:now()"`
+
+	// Associations
+	Bot *Bot `json:"bot,omitempty" gorm:"foreignkey:BotID"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (BotWebhookDelivery) TableName() string {
+	return "bot_webhook_deliveries"
+}
@@ -12,7 +12,7 @@ import (
 // Conversation - การสนทนาระหว่างผู้ใช้หรือกลุ่ม
 type Conversation struct {
 	ID              uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	Type            string      `json:"type" gorm:"type:varchar(20);not null"` // private, group
+	Type            string      `json:"type" gorm:"type:varchar(20);not null"` // direct, group, channel
 	Title           string      `json:"title,omitempty" gorm:"type:varchar(100)"`
 	IconURL         string      `json:"icon_url,omitempty" gorm:"type:text"`
 	CreatedAt       time.Time   `json:"created_at" gorm:"type:timestamp with time zone;default:now()"`
@@ -22,7 +22,10 @@ type Conversation struct {
 	LastMessageID   *uuid.UUID  `json:"last_message_id,omitempty" gorm:"type:uuid"`
 	CreatorID       *uuid.UUID  `json:"creator_id,omitempty" gorm:"type:uuid"`
 	IsActive        bool        `json:"is_active" gorm:"default:true"`
+	DisabledAt      *time.Time  `json:"disabled_at,omitempty" gorm:"type:timestamp with time zone"` // ถูกปิดโดยผู้ดูแลแพลตฟอร์ม (IsActive = false)
+	DisabledReason  string      `json:"disabled_reason,omitempty" gorm:"type:text"`
 	Metadata        types.JSONB `json:"metadata,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"`
+	MessageTTL      int         `json:"message_ttl" gorm:"default:0"` // ข้อความที่หายไปเอง (วินาที) 0 = ปิด
 
 	// Associations
 	Creator  *User                 `json:"creator,omitempty" gorm:"foreignkey:CreatorID"`
@@ -34,3 +37,37 @@ type Conversation struct {
 func (Conversation) TableName() string {
 	return "conversations"
 }
+
+// ConversationTypeChannel ช่องกระจายข่าว (one-to-many) เฉพาะ owner/admin โพสต์ได้ ผู้ติดตามอ่านและ react ได้
+const ConversationTypeChannel = "channel"
+
+// IsChannel ตรวจสอบว่าเป็นช่องกระจายข่าวหรือไม่
+func (c *Conversation) IsChannel() bool {
+	return c.Type == ConversationTypeChannel
+}
+
+// ค่า TTL ที่รองรับสำหรับข้อความที่หายไปเอง (วินาที)
+const (
+	MessageTTLOff     = 0
+	MessageTTLOneHour = 3600
+	MessageTTLOneDay  = 86400
+	MessageTTLOneWeek = 604800
+)
+
+// IsValidMessageTTL ตรวจสอบว่า TTL เป็นค่าที่รองรับหรือไม่
+func IsValidMessageTTL(ttl int) bool {
+	switch ttl {
+	case MessageTTLOff, MessageTTLOneHour, MessageTTLOneDay, MessageTTLOneWeek:
+		return true
+	}
+	return false
+}
+
+// MessageExpiresAt คืนค่าเวลาหมดอายุของข้อความที่สร้างเมื่อ createdAt ตาม MessageTTL (nil = ไม่หายไปเอง)
+func (c *Conversation) MessageExpiresAt(createdAt time.Time) *time.Time {
+	if c == nil || c.MessageTTL <= 0 {
+		return nil
+	}
+	expiresAt := createdAt.Add(time.Duration(c.MessageTTL) * time.Second)
+	return &expiresAt
+}
@@ -0,0 +1,46 @@
+// domain/models/conversation_invite_link.go
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConversationInviteLink ลิงก์เชิญเข้ากลุ่ม (แชร์ได้ กำหนดวันหมดอายุ/จำนวนครั้ง/ต้องให้แอดมินอนุมัติ)
+type ConversationInviteLink struct {
+	ID               uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ConversationID   uuid.UUID  `json:"conversation_id" gorm:"type:uuid;not null;index"`
+	CreatorID        uuid.UUID  `json:"creator_id" gorm:"type:uuid;not null"`
+	Code             string     `json:"code" gorm:"type:varchar(32);not null;uniqueIndex"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty" gorm:"type:timestamp with time zone"`
+	MaxUses          int        `json:"max_uses" gorm:"default:0"` // 0 = ไม่จำกัด
+	UseCount         int        `json:"use_count" gorm:"default:0"`
+	RequiresApproval bool       `json:"requires_approval" gorm:"default:false"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty" gorm:"type:timestamp with time zone"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"type:timestamp with time zone;default:now()"`
+
+	// Associations
+	Conversation *Conversation `json:"conversation,omitempty" gorm:"foreignkey:ConversationID"`
+	Creator      *User         `json:"creator,omitempty" gorm:"foreignkey:CreatorID"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (ConversationInviteLink) TableName() string {
+	return "conversation_invite_links"
+}
+
+// IsExpired ตรวจสอบว่าลิงก์หมดอายุแล้วหรือไม่
+func (l *ConversationInviteLink) IsExpired(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
+
+// IsExhausted ตรวจสอบว่าลิงก์ถูกใช้ครบจำนวนครั้งแล้วหรือไม่
+func (l *ConversationInviteLink) IsExhausted() bool {
+	return l.MaxUses > 0 && l.UseCount >= l.MaxUses
+}
+
+// IsActive ตรวจสอบว่าลิงก์ยังใช้งานได้ (ไม่ถูกเพิกถอน ไม่หมดอายุ และยังไม่ครบจำนวนครั้ง)
+func (l *ConversationInviteLink) IsActive(now time.Time) bool {
+	return l.RevokedAt == nil && !l.IsExpired(now) && !l.IsExhausted()
+}
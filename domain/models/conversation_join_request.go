@@ -0,0 +1,39 @@
+// domain/models/conversation_join_request.go
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// สถานะคำขอเข้าร่วมกลุ่ม
+const (
+	JoinRequestStatusPending  = "pending"
+	JoinRequestStatusApproved = "approved"
+	JoinRequestStatusRejected = "rejected"
+)
+
+// ConversationJoinRequest คำขอเข้าร่วมกลุ่มที่รอแอดมินอนุมัติ
+type ConversationJoinRequest struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ConversationID uuid.UUID  `json:"conversation_id" gorm:"type:uuid;not null;index:idx_join_requests_conversation_status"`
+	UserID         uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	Message        string     `json:"message,omitempty" gorm:"type:varchar(500)"`
+	Status         string     `json:"status" gorm:"type:varchar(20);not null;default:'pending';index:idx_join_requests_conversation_status"`
+	InviteLinkID   *uuid.UUID `json:"invite_link_id,omitempty" gorm:"type:uuid"` // ลิงก์เชิญที่ต้องอนุมัติ (nil = ขอเข้าร่วมโดยตรง)
+	ReviewedBy     *uuid.UUID `json:"reviewed_by,omitempty" gorm:"type:uuid"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty" gorm:"type:timestamp with time zone"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"type:timestamp with time zone;default:now()"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"type:timestamp with time zone;default:now()"`
+
+	// Associations
+	Conversation *Conversation `json:"conversation,omitempty" gorm:"foreignkey:ConversationID"`
+	User         *User         `json:"user,omitempty" gorm:"foreignkey:UserID"`
+	Reviewer     *User         `json:"reviewer,omitempty" gorm:"foreignkey:ReviewedBy"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (ConversationJoinRequest) TableName() string {
+	return "conversation_join_requests"
+}
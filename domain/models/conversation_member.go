@@ -16,6 +16,9 @@ const (
 	RoleOwner  MemberRole = "owner"
 	RoleMember MemberRole = "member"
 	RoleAdmin  MemberRole = "admin"
+
+	// RoleSubscriber ผู้ติดตามช่อง (channel) อ่านและ react ได้แต่โพสต์ไม่ได้
+	RoleSubscriber MemberRole = "subscriber"
 )
 
 // ConversationMember - สมาชิกในการสนทนา
@@ -33,6 +36,7 @@ type ConversationMember struct {
 	HiddenAt             *time.Time  `json:"hidden_at,omitempty" gorm:"type:timestamp with time zone"`
 	Nickname             string      `json:"nickname,omitempty" gorm:"type:varchar(100)"`
 	NotificationSettings types.JSONB `json:"notification_settings,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"`
+	UpdatedAt            time.Time   `json:"updated_at" gorm:"type:timestamp with time zone;default:now()"` // ใช้กับ delta sync (role, read pointer, mute/pin/hide)
 
 	// Associations
 	Conversation *Conversation `json:"conversation,omitempty" gorm:"foreignkey:ConversationID"`
@@ -43,3 +47,12 @@ type ConversationMember struct {
 func (ConversationMember) TableName() string {
 	return "conversation_members"
 }
+
+// UnreadCursor จุดเริ่มนับข้อความที่ยังไม่ได้อ่าน (จุดอ่านล่าสุด หรือเวลาที่เข้าร่วมถ้ายังไม่เคยอ่าน)
+// ใช้กับช่อง (channel) เพื่อไม่ให้ผู้ติดตามใหม่เห็นประวัติทั้งหมดของช่องเป็นข้อความที่ยังไม่ได้อ่าน
+func (m *ConversationMember) UnreadCursor() time.Time {
+	if m.LastReadAt != nil && m.LastReadAt.After(m.JoinedAt) {
+		return *m.LastReadAt
+	}
+	return m.JoinedAt
+}
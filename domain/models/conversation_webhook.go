@@ -0,0 +1,37 @@
+// domain/models/conversation_webhook.go
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConversationWebhook - incoming webhook ของการสนทนา (ระบบภายนอกโพสต์ข้อความเข้ากลุ่มผ่าน URL ที่มี token)
+type ConversationWebhook struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ConversationID uuid.UUID  `json:"conversation_id" gorm:"type:uuid;not null;index"`
+	CreatorID      uuid.UUID  `json:"creator_id" gorm:"type:uuid;not null"`
+	Name           string     `json:"name" gorm:"type:varchar(80);not null"` // ชื่อที่แสดงเป็นผู้ส่ง (payload override ได้)
+	AvatarURL      string     `json:"avatar_url,omitempty" gorm:"type:text"`
+	TokenHash      string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"` // SHA-256 ของ token (token จริงแสดงครั้งเดียวตอนสร้าง)
+	TokenPrefix    string     `json:"token_prefix" gorm:"type:varchar(20);not null"`  // ส่วนต้นของ token สำหรับแสดงผล
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty" gorm:"type:timestamp with time zone"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty" gorm:"type:timestamp with time zone"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"type:timestamp with time zone;default:now()"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"type:timestamp with time zone;default:now()"`
+
+	// Associations
+	Conversation *Conversation `json:"conversation,omitempty" gorm:"foreignkey:ConversationID"`
+	Creator      *User         `json:"creator,omitempty" gorm:"foreignkey:CreatorID"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (ConversationWebhook) TableName() string {
+	return "conversation_webhooks"
+}
+
+// IsActive ตรวจสอบว่า webhook ยังใช้งานได้ (ยังไม่ถูกเพิกถอน)
+func (w *ConversationWebhook) IsActive() bool {
+	return w.RevokedAt == nil
+}
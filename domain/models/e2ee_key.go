@@ -0,0 +1,55 @@
+// domain/models/e2ee_key.go
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// E2EEKeyBundle - identity key และ signed pre-key ของอุปกรณ์ (Signal-style X3DH)
+// server เก็บเฉพาะ public key เท่านั้น private key อยู่บนอุปกรณ์
+type E2EEKeyBundle struct {
+	ID                    uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID                uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:unique_e2ee_device"`
+	DeviceID              string    `json:"device_id" gorm:"type:varchar(64);not null;uniqueIndex:unique_e2ee_device"` // กำหนดโดย client
+	RegistrationID        int       `json:"registration_id" gorm:"not null"`
+	IdentityKey           string    `json:"identity_key" gorm:"type:text;not null"` // base64
+	SignedPreKeyID        int       `json:"signed_pre_key_id" gorm:"not null"`
+	SignedPreKey          string    `json:"signed_pre_key" gorm:"type:text;not null"`           // base64
+	SignedPreKeySignature string    `json:"signed_pre_key_signature" gorm:"type:text;not null"` // base64
+	CreatedAt             time.Time `json:"created_at" gorm:"type:timestamp with time zone;default:now()"`
+	UpdatedAt             time.Time `json:"updated_at" gorm:"type:timestamp with time zone;default:now()"`
+
+	// Associations
+	User *User `json:"user,omitempty" gorm:"foreignkey:UserID"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (E2EEKeyBundle) TableName() string {
+	return "e2ee_key_bundles"
+}
+
+// E2EEOneTimePreKey - one-time pre-key ของอุปกรณ์ (ใช้ได้ครั้งเดียว ถูกลบเมื่อมีผู้ดึงไป)
+type E2EEOneTimePreKey struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:unique_e2ee_prekey"`
+	DeviceID  string    `json:"device_id" gorm:"type:varchar(64);not null;uniqueIndex:unique_e2ee_prekey"`
+	KeyID     int       `json:"key_id" gorm:"not null;uniqueIndex:unique_e2ee_prekey"`
+	PublicKey string    `json:"public_key" gorm:"type:text;not null"` // base64
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamp with time zone;default:now()"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (E2EEOneTimePreKey) TableName() string {
+	return "e2ee_one_time_pre_keys"
+}
+
+// MessageTypeEncrypted ข้อความเข้ารหัสแบบ end-to-end (Content เป็น ciphertext envelope ที่ server อ่านไม่ได้)
+const MessageTypeEncrypted = "encrypted"
+
+// ประเภทการเปลี่ยนแปลง key ที่แจ้งผ่าน e2ee.key_changed
+const (
+	E2EEKeyChangeIdentity = "identity_changed" // อุปกรณ์ใหม่หรือ identity key เปลี่ยน
+	E2EEKeyChangeRemoved  = "device_removed"   // อุปกรณ์ถูกลบ
+)
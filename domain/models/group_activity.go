@@ -40,4 +40,7 @@ const (
 	ActivityMemberRoleChanged    = "member.role_changed"
 	ActivityOwnershipTransferred = "ownership.transferred"
 	ActivityMemberLeft           = "member.left"
+	ActivityMemberJoinRequested  = "member.join_requested"
+	ActivityMemberJoinApproved   = "member.join_approved"
+	ActivityMessageTTLChanged    = "settings.message_ttl_changed"
 )
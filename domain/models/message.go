@@ -34,6 +34,16 @@ type Message struct {
 	ReplyToID         *uuid.UUID  `json:"reply_to_id,omitempty" gorm:"type:uuid"`
 	IsEdited          bool        `json:"is_edited" gorm:"default:false"`
 	EditCount         int         `json:"edit_count" gorm:"default:0"`
+	ExpiresAt         *time.Time  `json:"expires_at,omitempty" gorm:"type:timestamp with time zone;index"` // ข้อความที่หายไปเอง
+
+	// Thread fields - ข้อความที่มี ThreadRootID คือข้อความตอบกลับในเธรด (ไม่แสดงใน timeline หลัก)
+	ThreadRootID      *uuid.UUID `json:"thread_root_id,omitempty" gorm:"type:uuid;index"`
+	ThreadReplyCount  int        `json:"thread_reply_count" gorm:"default:0"`                                 // ใช้กับข้อความต้นเธรดเท่านั้น
+	ThreadLastReplyID *uuid.UUID `json:"thread_last_reply_id,omitempty" gorm:"type:uuid"`                     // ใช้กับข้อความต้นเธรดเท่านั้น
+	ThreadLastReplyAt *time.Time `json:"thread_last_reply_at,omitempty" gorm:"type:timestamp with time zone"` // ใช้กับข้อความต้นเธรดเท่านั้น
+
+	// ยอดผู้ชม (ใช้กับข้อความในช่อง channel แทน read receipt รายคน)
+	ViewCount int64 `json:"view_count" gorm:"default:0"`
 
 	// Pin fields
 	IsPinned bool        `json:"is_pinned" gorm:"default:false"`
@@ -52,6 +62,7 @@ type Message struct {
 	EditHistory   []*MessageEditHistory `json:"edit_history,omitempty" gorm:"foreignkey:MessageID"`
 	DeleteHistory *MessageDeleteHistory `json:"delete_history,omitempty" gorm:"foreignkey:MessageID"`
 	Pinner        *User                 `json:"pinner,omitempty" gorm:"foreignkey:PinnedBy"`
+	Poll          *Poll                 `json:"poll,omitempty" gorm:"foreignkey:MessageID"`
 }
 
 // TableName - ระบุชื่อตารางใน database
@@ -0,0 +1,27 @@
+// domain/models/message_reaction.go
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MessageReaction - บันทึกการแสดงปฏิกิริยา (emoji) ต่อข้อความ
+// ผู้ใช้หนึ่งคนสามารถใส่ได้หลาย emoji ต่อข้อความ แต่ emoji เดียวกันได้ครั้งเดียว
+type MessageReaction struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	MessageID uuid.UUID `json:"message_id" gorm:"type:uuid;not null;uniqueIndex:unique_message_reaction_user_emoji"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:unique_message_reaction_user_emoji"`
+	Emoji     string    `json:"emoji" gorm:"type:varchar(32);not null;uniqueIndex:unique_message_reaction_user_emoji"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamp with time zone;default:now()"`
+
+	// Associations
+	Message *Message `json:"message,omitempty" gorm:"foreignkey:MessageID"`
+	User    *User    `json:"user,omitempty" gorm:"foreignkey:UserID"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (MessageReaction) TableName() string {
+	return "message_reactions"
+}
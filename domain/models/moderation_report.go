@@ -0,0 +1,100 @@
+// domain/models/moderation_report.go
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+// ประเภทสิ่งที่ถูกรายงาน
+const (
+	ReportTargetMessage      = "message"
+	ReportTargetUser         = "user"
+	ReportTargetConversation = "conversation"
+)
+
+// เหตุผลการรายงาน (reason code)
+const (
+	ReportReasonSpam          = "spam"
+	ReportReasonHarassment    = "harassment"
+	ReportReasonHateSpeech    = "hate_speech"
+	ReportReasonViolence      = "violence"
+	ReportReasonSexualContent = "sexual_content"
+	ReportReasonScam          = "scam"
+	ReportReasonImpersonation = "impersonation"
+	ReportReasonOther         = "other" // ต้องระบุรายละเอียด
+
+	// ReportReasonContentFilter ใช้กับรายงานที่ content filter สร้างเอง (ผู้ใช้เลือกไม่ได้)
+	ReportReasonContentFilter = "content_filter"
+)
+
+// แหล่งที่มาของรายงาน
+const (
+	ReportSourceUser          = "user"           // ผู้ใช้รายงาน
+	ReportSourceContentFilter = "content_filter" // ข้อความถูก flag โดย content filter (ไม่มีผู้รายงาน)
+)
+
+// สถานะของรายงานในคิวตรวจสอบ
+// open -> in_review -> resolved/dismissed (resolved/dismissed เปิดใหม่เป็น open ได้)
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusInReview  = "in_review"
+	ReportStatusResolved  = "resolved"  // ดำเนินการแล้ว (ดู Action)
+	ReportStatusDismissed = "dismissed" // ไม่พบการละเมิด
+)
+
+// การดำเนินการกับรายงาน
+const (
+	ModerationActionNone                = ""
+	ModerationActionDeleteMessage       = "delete_message"
+	ModerationActionSuspendUser         = "suspend_user"
+	ModerationActionDisableConversation = "disable_conversation"
+)
+
+// ModerationReport รายงานเนื้อหาไม่เหมาะสมจากผู้ใช้ (ข้อความ ผู้ใช้ หรือการสนทนา)
+type ModerationReport struct {
+	ID             uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ReporterID     *uuid.UUID  `json:"reporter_id,omitempty" gorm:"type:uuid;index"` // nil = รายงานอัตโนมัติจาก content filter
+	Source         string      `json:"source" gorm:"type:varchar(20);not null;default:'user';index"`
+	TargetType     string      `json:"target_type" gorm:"type:varchar(20);not null"`
+	TargetID       uuid.UUID   `json:"target_id" gorm:"type:uuid;not null;index"`
+	ReportedUserID *uuid.UUID  `json:"reported_user_id,omitempty" gorm:"type:uuid;index"` // ผู้ส่งข้อความ หรือผู้ใช้ที่ถูกรายงาน
+	ConversationID *uuid.UUID  `json:"conversation_id,omitempty" gorm:"type:uuid;index"`  // การสนทนาของข้อความ หรือการสนทนาที่ถูกรายงาน
+	Reason         string      `json:"reason" gorm:"type:varchar(30);not null"`
+	Description    string      `json:"description,omitempty" gorm:"type:text"`
+	Snapshot       types.JSONB `json:"snapshot,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"` // สำเนาเนื้อหาตอนรายงาน (ข้อความที่ถูกลบภายหลังยังตรวจสอบได้)
+	Status         string      `json:"status" gorm:"type:varchar(20);not null;default:'open';index"`
+	AssigneeID     *uuid.UUID  `json:"assignee_id,omitempty" gorm:"type:uuid;index"`
+	Action         string      `json:"action,omitempty" gorm:"type:varchar(30)"`
+	ResolutionNote string      `json:"resolution_note,omitempty" gorm:"type:text"`
+	ResolvedBy     *uuid.UUID  `json:"resolved_by,omitempty" gorm:"type:uuid"`
+	ResolvedAt     *time.Time  `json:"resolved_at,omitempty" gorm:"type:timestamp with time zone"`
+	CreatedAt      time.Time   `json:"created_at" gorm:"type:timestamp with time zone;default:now();index"`
+	UpdatedAt      time.Time   `json:"updated_at" gorm:"type:timestamp with time zone;default:now()"`
+
+	// Associations
+	Reporter *User `json:"reporter,omitempty" gorm:"foreignkey:ReporterID"`
+	Assignee *User `json:"assignee,omitempty" gorm:"foreignkey:AssigneeID"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (ModerationReport) TableName() string {
+	return "moderation_reports"
+}
+
+// IsClosed ตรวจสอบว่ารายงานปิดแล้ว (resolved หรือ dismissed)
+func (r *ModerationReport) IsClosed() bool {
+	return r.Status == ReportStatusResolved || r.Status == ReportStatusDismissed
+}
+
+// IsValidReportReason ตรวจสอบ reason code
+func IsValidReportReason(reason string) bool {
+	switch reason {
+	case ReportReasonSpam, ReportReasonHarassment, ReportReasonHateSpeech, ReportReasonViolence,
+		ReportReasonSexualContent, ReportReasonScam, ReportReasonImpersonation, ReportReasonOther:
+		return true
+	}
+	return false
+}
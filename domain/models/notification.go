@@ -0,0 +1,54 @@
+// domain/models/notification.go
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+// Notification - รายการในกล่องแจ้งเตือนของผู้ใช้ (เก็บถาวร ผู้ใช้ที่ออฟไลน์จะเห็นเมื่อกลับมา)
+type Notification struct {
+	ID             uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID         uuid.UUID   `json:"user_id" gorm:"type:uuid;not null;index:idx_notifications_user_created,priority:1"` // ผู้รับ
+	Type           string      `json:"type" gorm:"type:varchar(50);not null"`
+	ActorID        *uuid.UUID  `json:"actor_id,omitempty" gorm:"type:uuid"`           // ผู้ที่ทำให้เกิดการแจ้งเตือน (nil = ระบบ)
+	TargetType     string      `json:"target_type,omitempty" gorm:"type:varchar(30)"` // ชนิดของสิ่งที่อ้างถึง เช่น message, friend_request
+	TargetID       *uuid.UUID  `json:"target_id,omitempty" gorm:"type:uuid"`
+	ConversationID *uuid.UUID  `json:"conversation_id,omitempty" gorm:"type:uuid"`
+	Payload        types.JSONB `json:"payload" gorm:"type:jsonb;default:'{}'::jsonb"`
+	ReadAt         *time.Time  `json:"read_at,omitempty" gorm:"type:timestamp with time zone"`
+	CreatedAt      time.Time   `json:"created_at" gorm:"type:timestamp with time zone;default:now();index:idx_notifications_user_created,priority:2"`
+
+	// Associations
+	Actor *User `json:"actor,omitempty" gorm:"foreignkey:ActorID"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// ชนิดของการแจ้งเตือน (Notification.Type)
+const (
+	NotificationTypeGeneral               = "general" // SendNotification ที่ไม่ได้ระบุ type
+	NotificationTypeAlert                 = "alert"
+	NotificationTypeMention               = "mention"
+	NotificationTypeKeywordAlert          = "keyword_alert" // ข้อความตรงกับ keyword alert ของผู้ใช้
+	NotificationTypeFriendRequestReceived = "friend_request.received"
+	NotificationTypeFriendRequestAccepted = "friend_request.accepted"
+	NotificationTypeMemberRoleChanged     = "member.role_changed"
+	NotificationTypeOwnershipTransferred  = "conversation.ownership_transferred"
+	NotificationTypeJoinRequestReceived   = "join_request.received"
+	NotificationTypeJoinRequestApproved   = "join_request.approved"
+	NotificationTypeJoinRequestRejected   = "join_request.rejected"
+)
+
+// ชนิดของสิ่งที่การแจ้งเตือนอ้างถึง (Notification.TargetType)
+const (
+	NotificationTargetMessage       = "message"
+	NotificationTargetConversation  = "conversation"
+	NotificationTargetFriendRequest = "friend_request"
+	NotificationTargetJoinRequest   = "join_request"
+)
@@ -0,0 +1,184 @@
+// domain/models/notification_preferences.go
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+// ระดับการแจ้งเตือนของการสนทนา (ConversationMember.NotificationSettings["level"])
+const (
+	NotificationLevelAll      = "all"      // ทุกข้อความ
+	NotificationLevelMentions = "mentions" // เฉพาะเมื่อถูก mention หรือตรงกับ keyword alert
+	NotificationLevelNone     = "none"     // ไม่แจ้งเตือนเลย (รวมถึง mention)
+)
+
+// คีย์ใน ConversationMember.NotificationSettings และ User.Settings
+const (
+	notificationSettingLevel      = "level"
+	notificationSettingMutedUntil = "muted_until"
+
+	// คีย์รุ่นเก่า (อ่านอย่างเดียว ถูกลบเมื่อบันทึกการตั้งค่าแบบใหม่)
+	notificationSettingLegacyPush         = "push"
+	notificationSettingLegacyMentionsOnly = "mentions_only"
+
+	UserSettingQuietHours    = "quiet_hours"
+	UserSettingKeywordAlerts = "keyword_alerts"
+)
+
+// IsValidNotificationLevel ตรวจสอบระดับการแจ้งเตือน
+func IsValidNotificationLevel(level string) bool {
+	switch level {
+	case NotificationLevelAll, NotificationLevelMentions, NotificationLevelNone:
+		return true
+	}
+	return false
+}
+
+// ConversationNotificationSettings การตั้งค่าการแจ้งเตือนของสมาชิกในการสนทนาหนึ่ง
+type ConversationNotificationSettings struct {
+	Level      string     `json:"level"`
+	MutedUntil *time.Time `json:"muted_until,omitempty"` // ใช้คู่กับ IsMuted: nil = ปิดเสียงจนกว่าจะเปิดเอง
+}
+
+// NotificationPreferences อ่านการตั้งค่าจาก NotificationSettings (รองรับคีย์รุ่นเก่า push/mentions_only)
+// ผู้ติดตามช่อง (subscriber) ที่ยังไม่ได้ตั้งค่าจะได้รับเฉพาะ mention (เลือกรับทุกโพสต์ได้ด้วย level = all)
+func (m *ConversationMember) NotificationPreferences() ConversationNotificationSettings {
+	settings := m.NotificationSettings
+	result := ConversationNotificationSettings{Level: NotificationLevelAll}
+	if m.Role == RoleSubscriber {
+		result.Level = NotificationLevelMentions
+	}
+
+	if level, ok := settings[notificationSettingLevel].(string); ok && IsValidNotificationLevel(level) {
+		result.Level = level
+	} else if push, ok := settings[notificationSettingLegacyPush].(bool); ok && !push {
+		result.Level = NotificationLevelNone
+	} else if mentionsOnly, ok := settings[notificationSettingLegacyMentionsOnly].(bool); ok && mentionsOnly {
+		result.Level = NotificationLevelMentions
+	}
+
+	if raw, ok := settings[notificationSettingMutedUntil].(string); ok {
+		if mutedUntil, err := time.Parse(time.RFC3339, raw); err == nil {
+			result.MutedUntil = &mutedUntil
+		}
+	}
+
+	return result
+}
+
+// IsMutedAt ตรวจสอบว่ายังปิดเสียงอยู่ ณ เวลาที่ระบุ (การปิดเสียงแบบมีกำหนดหมดอายุเอง)
+func (m *ConversationMember) IsMutedAt(now time.Time) bool {
+	if !m.IsMuted {
+		return false
+	}
+	mutedUntil := m.NotificationPreferences().MutedUntil
+	return mutedUntil == nil || now.Before(*mutedUntil)
+}
+
+// MergeInto เขียนการตั้งค่าลงใน JSONB เดิม (คงคีย์อื่นไว้ ลบคีย์รุ่นเก่า)
+func (s ConversationNotificationSettings) MergeInto(settings types.JSONB) types.JSONB {
+	result := types.JSONB{}
+	for key, value := range settings {
+		result[key] = value
+	}
+	delete(result, notificationSettingLegacyPush)
+	delete(result, notificationSettingLegacyMentionsOnly)
+
+	result[notificationSettingLevel] = s.Level
+	if s.MutedUntil != nil {
+		result[notificationSettingMutedUntil] = s.MutedUntil.UTC().Format(time.RFC3339)
+	} else {
+		delete(result, notificationSettingMutedUntil)
+	}
+	return result
+}
+
+// QuietHours ช่วงเวลางดส่ง push ตามเขตเวลาของผู้ใช้ (Start > End = ข้ามเที่ยงคืน เช่น 22:00-07:00)
+type QuietHours struct {
+	Enabled  bool   `json:"enabled"`
+	Start    string `json:"start"`    // HH:MM
+	End      string `json:"end"`      // HH:MM
+	Timezone string `json:"timezone"` // IANA เช่น Asia/Bangkok
+}
+
+// ActiveAt ตรวจสอบว่าเวลาที่ระบุอยู่ในช่วง quiet hours (ค่าที่ไม่ถูกต้องถือว่าไม่ได้เปิด)
+func (q *QuietHours) ActiveAt(now time.Time) bool {
+	if q == nil || !q.Enabled {
+		return false
+	}
+	start, ok := ParseClockMinutes(q.Start)
+	if !ok {
+		return false
+	}
+	end, ok := ParseClockMinutes(q.End)
+	if !ok || start == end {
+		return false
+	}
+	location, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		return false
+	}
+
+	local := now.In(location)
+	minutes := local.Hour()*60 + local.Minute()
+	if start < end {
+		return minutes >= start && minutes < end
+	}
+	return minutes >= start || minutes < end
+}
+
+// ParseClockMinutes แปลง "HH:MM" เป็นจำนวนนาทีนับจากเที่ยงคืน
+func ParseClockMinutes(value string) (int, bool) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// UserNotificationPreferences การตั้งค่าการแจ้งเตือนระดับผู้ใช้ (เก็บใน User.Settings)
+type UserNotificationPreferences struct {
+	QuietHours    *QuietHours `json:"quiet_hours"`
+	KeywordAlerts []string    `json:"keyword_alerts"`
+}
+
+// NotificationPreferences อ่านการตั้งค่าการแจ้งเตือนจาก User.Settings
+func (u *User) NotificationPreferences() UserNotificationPreferences {
+	result := UserNotificationPreferences{KeywordAlerts: []string{}}
+
+	if raw, ok := u.Settings[UserSettingQuietHours].(map[string]interface{}); ok {
+		quietHours := &QuietHours{}
+		quietHours.Enabled, _ = raw["enabled"].(bool)
+		quietHours.Start, _ = raw["start"].(string)
+		quietHours.End, _ = raw["end"].(string)
+		quietHours.Timezone, _ = raw["timezone"].(string)
+		result.QuietHours = quietHours
+	}
+
+	if raw, ok := u.Settings[UserSettingKeywordAlerts].([]interface{}); ok {
+		for _, item := range raw {
+			if keyword, ok := item.(string); ok && keyword != "" {
+				result.KeywordAlerts = append(result.KeywordAlerts, keyword)
+			}
+		}
+	}
+
+	return result
+}
+
+// MatchKeyword คืน keyword แรกที่พบในข้อความ (ไม่สนตัวพิมพ์เล็ก/ใหญ่ และไม่ต้องมีช่องว่างคั่น เพราะภาษาไทยไม่เว้นวรรคระหว่างคำ)
+func (p UserNotificationPreferences) MatchKeyword(text string) (string, bool) {
+	if text == "" {
+		return "", false
+	}
+	lowered := strings.ToLower(text)
+	for _, keyword := range p.KeywordAlerts {
+		if strings.Contains(lowered, strings.ToLower(keyword)) {
+			return keyword, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,78 @@
+// domain/models/poll.go
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Poll permission levels (เก็บใน Conversation.Metadata["poll_permission"])
+const (
+	PollPermissionAll    = "all"    // สมาชิกทุกคนสร้างโพลได้ (ค่าเริ่มต้น)
+	PollPermissionAdmins = "admins" // owner และ admin เท่านั้น
+	PollPermissionOwner  = "owner"  // owner เท่านั้น
+)
+
+// Poll - โพลที่แนบกับข้อความประเภท poll
+type Poll struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	MessageID      uuid.UUID  `json:"message_id" gorm:"type:uuid;not null;uniqueIndex"`
+	ConversationID uuid.UUID  `json:"conversation_id" gorm:"type:uuid;not null;index"`
+	CreatorID      uuid.UUID  `json:"creator_id" gorm:"type:uuid;not null"`
+	Question       string     `json:"question" gorm:"type:text;not null"`
+	AllowMultiple  bool       `json:"allow_multiple" gorm:"default:false"`
+	IsAnonymous    bool       `json:"is_anonymous" gorm:"default:false"`
+	ClosesAt       *time.Time `json:"closes_at,omitempty" gorm:"type:timestamp with time zone"`
+	IsClosed       bool       `json:"is_closed" gorm:"default:false"`
+	ClosedAt       *time.Time `json:"closed_at,omitempty" gorm:"type:timestamp with time zone"`
+	ClosedBy       *uuid.UUID `json:"closed_by,omitempty" gorm:"type:uuid"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"type:timestamp with time zone;default:now()"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"type:timestamp with time zone;default:now()"`
+
+	// Associations
+	Message *Message      `json:"message,omitempty" gorm:"foreignkey:MessageID"`
+	Creator *User         `json:"creator,omitempty" gorm:"foreignkey:CreatorID"`
+	Options []*PollOption `json:"options,omitempty" gorm:"foreignkey:PollID"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (Poll) TableName() string {
+	return "polls"
+}
+
+// IsClosedAt ตรวจสอบว่าโพลปิดแล้วหรือยัง (ปิดด้วยมือ หรือเลยเวลาปิด)
+func (p *Poll) IsClosedAt(now time.Time) bool {
+	return p.IsClosed || (p.ClosesAt != nil && !now.Before(*p.ClosesAt))
+}
+
+// PollOption - ตัวเลือกของโพล
+type PollOption struct {
+	ID       uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	PollID   uuid.UUID `json:"poll_id" gorm:"type:uuid;not null;index"`
+	Text     string    `json:"text" gorm:"type:varchar(200);not null"`
+	Position int       `json:"position" gorm:"not null;default:0"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (PollOption) TableName() string {
+	return "poll_options"
+}
+
+// PollVote - การโหวตของผู้ใช้ (หนึ่งแถวต่อหนึ่งตัวเลือก)
+type PollVote struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	PollID    uuid.UUID `json:"poll_id" gorm:"type:uuid;not null;index"`
+	OptionID  uuid.UUID `json:"option_id" gorm:"type:uuid;not null;uniqueIndex:unique_poll_vote_option_user"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:unique_poll_vote_option_user"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamp with time zone;default:now()"`
+
+	// Associations
+	Option *PollOption `json:"option,omitempty" gorm:"foreignkey:OptionID"`
+	User   *User       `json:"user,omitempty" gorm:"foreignkey:UserID"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (PollVote) TableName() string {
+	return "poll_votes"
+}
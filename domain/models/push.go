@@ -0,0 +1,76 @@
+// domain/models/push.go
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+// Push providers (ชื่อ driver ที่ใช้ส่ง)
+const (
+	PushProviderFCM     = "fcm"
+	PushProviderAPNs    = "apns"
+	PushProviderWebPush = "webpush"
+	PushProviderFake    = "fake"
+)
+
+// Push delivery statuses
+const (
+	PushDeliveryPending = "pending" // รอส่ง / รอ retry
+	PushDeliverySent    = "sent"    // ส่งถึง provider สำเร็จ
+	PushDeliveryFailed  = "failed"  // ส่งไม่สำเร็จและหมดจำนวน retry แล้ว
+	PushDeliveryDropped = "dropped" // token ใช้ไม่ได้แล้ว (ไม่ retry)
+)
+
+// DeviceToken - token สำหรับส่ง push notification ไปยังอุปกรณ์ของผู้ใช้
+type DeviceToken struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID        uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	Provider      string     `json:"provider" gorm:"type:varchar(20);not null;uniqueIndex:unique_device_token"` // fcm, apns, webpush, fake
+	Token         string     `json:"token" gorm:"type:text;not null;uniqueIndex:unique_device_token"`           // WebPush: subscription endpoint
+	Platform      string     `json:"platform,omitempty" gorm:"type:varchar(20)"`                                // ios, android, web
+	DeviceName    string     `json:"device_name,omitempty" gorm:"type:varchar(100)"`
+	WebPushP256dh string     `json:"-" gorm:"column:webpush_p256dh;type:text"` // WebPush subscription keys
+	WebPushAuth   string     `json:"-" gorm:"column:webpush_auth;type:text"`
+	IsActive      bool       `json:"is_active" gorm:"default:true"`
+	LastUsedAt    *time.Time `json:"last_used_at,omitempty" gorm:"type:timestamp with time zone"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"type:timestamp with time zone;default:now()"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"type:timestamp with time zone;default:now()"`
+
+	// Associations
+	User *User `json:"user,omitempty" gorm:"foreignkey:UserID"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (DeviceToken) TableName() string {
+	return "device_tokens"
+}
+
+// PushDelivery - บันทึกการส่ง push แต่ละครั้ง (ใช้ retry และตรวจสอบ push ที่ส่งไม่ถึง)
+type PushDelivery struct {
+	ID             uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID         uuid.UUID   `json:"user_id" gorm:"type:uuid;not null;index"`
+	DeviceTokenID  uuid.UUID   `json:"device_token_id" gorm:"type:uuid;not null;index"`
+	Provider       string      `json:"provider" gorm:"type:varchar(20);not null"`
+	ConversationID *uuid.UUID  `json:"conversation_id,omitempty" gorm:"type:uuid"`
+	MessageID      *uuid.UUID  `json:"message_id,omitempty" gorm:"type:uuid"`
+	Kind           string      `json:"kind" gorm:"type:varchar(30);not null"` // message, mention, keyword
+	Payload        types.JSONB `json:"payload" gorm:"type:jsonb;default:'{}'::jsonb"`
+	Status         string      `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"`
+	Attempts       int         `json:"attempts" gorm:"default:0"`
+	LastError      string      `json:"last_error,omitempty" gorm:"type:text"`
+	NextRetryAt    *time.Time  `json:"next_retry_at,omitempty" gorm:"type:timestamp with time zone;index"`
+	SentAt         *time.Time  `json:"sent_at,omitempty" gorm:"type:timestamp with time zone"`
+	CreatedAt      time.Time   `json:"created_at" gorm:"type:timestamp with time zone;default:now()"`
+	UpdatedAt      time.Time   `json:"updated_at" gorm:"type:timestamp with time zone;default:now()"`
+
+	// Associations
+	DeviceToken *DeviceToken `json:"device_token,omitempty" gorm:"foreignkey:DeviceTokenID"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (PushDelivery) TableName() string {
+	return "push_deliveries"
+}
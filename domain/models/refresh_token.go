@@ -9,13 +9,22 @@ import (
 )
 
 // RefreshToken - โทเคนสำหรับรีเฟรชการเข้าถึง
+// แต่ละแถวคือ session ของอุปกรณ์หนึ่งเครื่อง (ID = session ID ที่อยู่ใน claim "sid" ของ access token)
+// token ถูกหมุนเวียนในแถวเดิมทุกครั้งที่รีเฟรช session ID จึงคงเดิมตลอดอายุการเข้าสู่ระบบ
 type RefreshToken struct {
-	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
-	Token     string    `json:"token" gorm:"type:text;not null"`
-	ExpiresAt time.Time `json:"expires_at" gorm:"type:timestamp with time zone;not null"`
-	CreatedAt time.Time `json:"created_at" gorm:"type:timestamp with time zone;default:now()"`
-	Revoked   bool      `json:"revoked" gorm:"default:false"`
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	Token      string    `json:"token" gorm:"type:text;not null;index"`
+	DeviceName string    `json:"device_name" gorm:"type:varchar(100)"`
+	UserAgent  string    `json:"user_agent" gorm:"type:text"`
+	IPAddress  string    `json:"ip_address" gorm:"type:varchar(45)"`
+	LastUsedAt time.Time `json:"last_used_at" gorm:"type:timestamp with time zone;default:now()"`
+	ExpiresAt  time.Time `json:"expires_at" gorm:"type:timestamp with time zone;not null"`
+	CreatedAt  time.Time `json:"created_at" gorm:"type:timestamp with time zone;default:now()"`
+	Revoked    bool      `json:"revoked" gorm:"default:false"`
+
+	// TwoFactorVerified session ผ่านการยืนยัน 2FA แล้ว (บัญชีที่เปิด 2FA รีเฟรชได้เฉพาะ session ที่ผ่านการยืนยัน)
+	TwoFactorVerified bool `json:"two_factor_verified" gorm:"default:false"`
 
 	// Associations
 	User *User `json:"user,omitempty" gorm:"foreignkey:UserID"`
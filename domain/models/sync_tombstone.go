@@ -0,0 +1,33 @@
+// domain/models/sync_tombstone.go
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SyncTombstone บันทึกข้อมูลที่ถูกลบจริง (hard delete) เพื่อให้ delta sync แจ้ง client ได้
+// สร้างโดย database trigger ทุกครั้งที่มีการลบแถวจากตารางที่ sync รองรับ
+type SyncTombstone struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	EntityType     string     `json:"entity_type" gorm:"type:varchar(30);not null"`
+	EntityID       uuid.UUID  `json:"entity_id" gorm:"type:uuid;not null"`
+	UserID         *uuid.UUID `json:"user_id,omitempty" gorm:"type:uuid;index"`         // เจ้าของข้อมูล
+	ConversationID *uuid.UUID `json:"conversation_id,omitempty" gorm:"type:uuid;index"` // การสนทนาที่ข้อมูลสังกัด
+	Shared         bool       `json:"shared" gorm:"default:false"`                      // true = สมาชิกทุกคนในการสนทนาต้องได้รับ
+	DeletedAt      time.Time  `json:"deleted_at" gorm:"type:timestamp with time zone;default:now();index"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (SyncTombstone) TableName() string {
+	return "sync_tombstones"
+}
+
+// ประเภทข้อมูลที่บันทึก tombstone
+const (
+	SyncEntityMember     = "member"
+	SyncEntityNote       = "note"
+	SyncEntityPin        = "pin"
+	SyncEntityFriendship = "friendship"
+)
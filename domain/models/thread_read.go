@@ -0,0 +1,25 @@
+// domain/models/thread_read.go
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ThreadRead - สถานะการอ่านเธรดของผู้ใช้แต่ละคน
+type ThreadRead struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ThreadRootID uuid.UUID `json:"thread_root_id" gorm:"type:uuid;not null;uniqueIndex:unique_thread_read_user"`
+	UserID       uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:unique_thread_read_user"`
+	LastReadAt   time.Time `json:"last_read_at" gorm:"type:timestamp with time zone;default:now()"`
+
+	// Associations
+	ThreadRoot *Message `json:"thread_root,omitempty" gorm:"foreignkey:ThreadRootID"`
+	User       *User    `json:"user,omitempty" gorm:"foreignkey:UserID"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (ThreadRead) TableName() string {
+	return "thread_reads"
+}
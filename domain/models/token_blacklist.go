@@ -11,7 +11,7 @@ import (
 // TokenBlacklist - รายการโทเคนที่ถูกปฏิเสธ
 type TokenBlacklist struct {
 	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	Token     string    `json:"token" gorm:"type:text;not null"`
+	Token     string    `json:"token" gorm:"type:text;not null;index"` // access token หรือ "session:<id>" สำหรับ session ที่ถูกเพิกถอน
 	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
 	ExpiredAt time.Time `json:"expired_at" gorm:"type:timestamp with time zone;not null"`
 	CreatedAt time.Time `json:"created_at" gorm:"type:timestamp with time zone;default:now()"`
@@ -24,3 +24,8 @@ type TokenBlacklist struct {
 func (TokenBlacklist) TableName() string {
 	return "token_blacklist"
 }
+
+// SessionBlacklistKey คีย์ใน token_blacklist สำหรับเพิกถอน access token ทุกอันของ session
+func SessionBlacklistKey(sessionID uuid.UUID) string {
+	return "session:" + sessionID.String()
+}
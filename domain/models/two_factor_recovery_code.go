@@ -0,0 +1,25 @@
+// domain/models/two_factor_recovery_code.go
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactorRecoveryCode - รหัสกู้คืนแบบใช้ครั้งเดียว ใช้แทนรหัส TOTP เมื่อไม่มีอุปกรณ์ (เก็บเฉพาะ hash)
+type TwoFactorRecoveryCode struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	CodeHash  string     `json:"-" gorm:"type:varchar(64);not null"` // SHA-256 ของรหัสที่ normalize แล้ว
+	UsedAt    *time.Time `json:"used_at,omitempty" gorm:"type:timestamp with time zone"`
+	CreatedAt time.Time  `json:"created_at" gorm:"type:timestamp with time zone;default:now()"`
+
+	// Associations
+	User *User `json:"user,omitempty" gorm:"foreignkey:UserID"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (TwoFactorRecoveryCode) TableName() string {
+	return "two_factor_recovery_codes"
+}
@@ -15,7 +15,8 @@ type User struct {
 	ID              uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
 	Username        string      `json:"username" gorm:"type:varchar(50);not null;unique"`
 	Email           string      `json:"email,omitempty" gorm:"type:varchar(255);unique"`
-	PasswordHash    string      `json:"-" gorm:"type:text"` // ไม่ส่งกลับในการ response JSON
+	EmailVerifiedAt *time.Time  `json:"email_verified_at,omitempty" gorm:"type:timestamp with time zone"` // nil = ยังไม่ยืนยัน (ล้างค่าเมื่อเปลี่ยนอีเมล)
+	PasswordHash    string      `json:"-" gorm:"type:text"`                                               // ไม่ส่งกลับในการ response JSON
 	DisplayName     string      `json:"display_name,omitempty" gorm:"type:varchar(100)"`
 	ProfileImageURL string      `json:"profile_image_url,omitempty" gorm:"type:text"`
 	Bio             string      `json:"bio,omitempty" gorm:"type:text"`
@@ -23,6 +24,15 @@ type User struct {
 	LastActiveAt    *time.Time  `json:"last_active_at,omitempty" gorm:"type:timestamp with time zone"`
 	Settings        types.JSONB `json:"settings,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"`
 	Status          string      `json:"status" gorm:"type:varchar(20);default:'active'"`
+	SystemRole      string      `json:"system_role,omitempty" gorm:"type:varchar(20);default:'';index"` // บทบาทระดับแพลตฟอร์ม ("" = ผู้ใช้ทั่วไป)
+	SuspendedAt     *time.Time  `json:"suspended_at,omitempty" gorm:"type:timestamp with time zone"`
+	SuspendedReason string      `json:"suspended_reason,omitempty" gorm:"type:text"`
+	IsBot           bool        `json:"is_bot" gorm:"default:false"` // บัญชีบอท (ยืนยันตัวตนด้วย API token แทน JWT)
+
+	// Two-factor authentication (TOTP)
+	TwoFactorSecret    string     `json:"-" gorm:"type:text"` // secret ที่เข้ารหัสแล้ว (มีค่าแต่ยังไม่ยืนยัน = อยู่ระหว่างเปิดใช้)
+	TwoFactorEnabledAt *time.Time `json:"two_factor_enabled_at,omitempty" gorm:"type:timestamp with time zone"`
+	TwoFactorLastStep  int64      `json:"-" gorm:"default:0;<-:false"` // time step ล่าสุดที่ใช้แล้ว (กันใช้รหัสซ้ำ, อัปเดตผ่าน AdvanceTwoFactorStep เท่านั้น)
 
 	// Associations
 	ConversationMembers  []*ConversationMember  `json:"conversation_members,omitempty" gorm:"foreignkey:UserID"`
@@ -41,3 +51,8 @@ type User struct {
 func (User) TableName() string {
 	return "users"
 }
+
+// IsTwoFactorEnabled ตรวจสอบว่าเปิดใช้ 2FA แล้ว (ยืนยันรหัสแรกสำเร็จ)
+func (u *User) IsTwoFactorEnabled() bool {
+	return u.TwoFactorEnabledAt != nil
+}
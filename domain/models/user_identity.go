@@ -0,0 +1,45 @@
+// domain/models/user_identity.go
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity - บัญชีภายนอก (OAuth2/OIDC) ที่เชื่อมกับผู้ใช้ หนึ่ง provider ต่อผู้ใช้หนึ่งบัญชี
+type UserIdentity struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID      uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_user_identities_user_provider"`
+	Provider    string     `json:"provider" gorm:"type:varchar(50);not null;uniqueIndex:idx_user_identities_user_provider;uniqueIndex:idx_user_identities_provider_subject"`
+	Subject     string     `json:"-" gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_provider_subject"` // claim "sub" ของ provider
+	Email       string     `json:"email,omitempty" gorm:"type:varchar(255)"`                                             // อีเมลจาก provider ตอนเชื่อมบัญชี
+	LastLoginAt *time.Time `json:"last_login_at,omitempty" gorm:"type:timestamp with time zone"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"type:timestamp with time zone;default:now()"`
+
+	// Associations
+	User *User `json:"user,omitempty" gorm:"foreignkey:UserID"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
+
+// OAuthState - สถานะของ authorization code flow ที่เริ่มแล้ว (ใช้ครั้งเดียว เก็บ PKCE verifier และ nonce ฝั่งเซิร์ฟเวอร์)
+type OAuthState struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	StateHash    string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"` // SHA-256 ของ state ที่ส่งไปกับ URL
+	Provider     string     `json:"provider" gorm:"type:varchar(50);not null"`
+	CodeVerifier string     `json:"-" gorm:"type:varchar(128);not null"`
+	Nonce        string     `json:"-" gorm:"type:varchar(128);not null"`
+	UserID       *uuid.UUID `json:"user_id,omitempty" gorm:"type:uuid"` // มีค่า = เชื่อมบัญชีให้ผู้ใช้นี้ (ไม่ใช่การเข้าสู่ระบบ)
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"type:timestamp with time zone;not null;index"`
+	UsedAt       *time.Time `json:"used_at,omitempty" gorm:"type:timestamp with time zone"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"type:timestamp with time zone;default:now()"`
+}
+
+// TableName - ระบุชื่อตารางใน database
+func (OAuthState) TableName() string {
+	return "oauth_states"
+}
@@ -19,6 +19,18 @@ type WebSocketPort interface {
 	BroadcastMessageReply(conversationID uuid.UUID, message interface{})
 	BroadcastMessageDeleted(conversationID uuid.UUID, messageID uuid.UUID)
 	BroadcastMessageReaction(conversationID uuid.UUID, reaction interface{})
+	BroadcastThreadReply(conversationID uuid.UUID, data interface{})
+	BroadcastPollUpdated(conversationID uuid.UUID, data interface{})
+
+	// Session management
+	DisconnectSessions(userID uuid.UUID, sessionIDs []uuid.UUID) // ตัดการเชื่อมต่อของ session ที่ถูกเพิกถอน (ทุก instance)
+
+	// Diagnostics (สถิติของ Hub ใน instance นี้ สำหรับผู้ดูแลระบบ)
+	GetStats() map[string]interface{}
+	GetAllConnections() map[string]interface{}
+
+	// E2EE notifications
+	BroadcastKeyChanged(userIDs []uuid.UUID, data interface{}) // identity key ของอุปกรณ์เปลี่ยนหรือถูกลบ
 
 	// Conversation notifications
 	BroadcastConversationCreated(userIDs []uuid.UUID, conversation interface{}) error
@@ -28,6 +40,11 @@ type WebSocketPort interface {
 	BroadcastUserRemovedFromConversation(userID, conversationID uuid.UUID)
 	BroadcastNewConversation(userID uuid.UUID, conversation interface{}) error
 
+	// Join request notifications
+	BroadcastJoinRequestReceived(adminIDs []uuid.UUID, request interface{}) // คำขอใหม่ถึงแอดมินของกลุ่ม
+	BroadcastJoinRequestApproved(userID uuid.UUID, request interface{})
+	BroadcastJoinRequestRejected(userID uuid.UUID, request interface{})
+
 	// Member role notifications
 	BroadcastMemberRoleChanged(conversationID uuid.UUID, data interface{})
 	BroadcastOwnershipTransferred(conversationID uuid.UUID, data interface{})
@@ -59,6 +76,8 @@ type WebSocketPort interface {
 	// General notifications
 	BroadcastNotification(userIDs []uuid.UUID, notification interface{})
 	BroadcastAlert(userID uuid.UUID, alert interface{})
+	BroadcastNotificationNew(userID uuid.UUID, data interface{})  // รายการใหม่ในกล่องแจ้งเตือน พร้อม unread_count
+	BroadcastNotificationRead(userID uuid.UUID, data interface{}) // อ่านแล้วจากอุปกรณ์หนึ่ง (sync badge ข้ามอุปกรณ์)
 	BroadcastSystemMessage(userIDs []uuid.UUID, message interface{})
 
 	// Note notifications (broadcast to conversation members for shared notes)
@@ -0,0 +1,27 @@
+// domain/repository/account_token_repository.go
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// AccountTokenRepository จัดการ token รีเซ็ตรหัสผ่านและยืนยันอีเมล
+type AccountTokenRepository interface {
+	// Create สร้าง token ใหม่
+	Create(token *models.AccountToken) error
+
+	// FindByTokenHash ดึง token ตาม hash (nil ถ้าไม่พบ)
+	FindByTokenHash(tokenHash string) (*models.AccountToken, error)
+
+	// Consume ทำเครื่องหมายว่าใช้แล้ว เฉพาะเมื่อยังไม่ถูกใช้และยังไม่หมดอายุ (false = ใช้ไม่ได้แล้ว)
+	Consume(id uuid.UUID, usedAt time.Time) (bool, error)
+
+	// InvalidateByUserID ยกเลิก token ที่ยังไม่ถูกใช้ของผู้ใช้ตามวัตถุประสงค์ (เมื่อออก token ใหม่หรือใช้สำเร็จ)
+	InvalidateByUserID(userID uuid.UUID, purpose string, usedAt time.Time) error
+
+	// DeleteExpired ลบ token ที่หมดอายุก่อนเวลาที่กำหนด
+	DeleteExpired(before time.Time) error
+}
@@ -0,0 +1,21 @@
+// domain/repository/admin_audit_log_repository.go
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// AdminAuditLogFilter เงื่อนไขการค้นหา audit log (ค่าว่าง = ไม่กรอง)
+type AdminAuditLogFilter struct {
+	ActorID  *uuid.UUID
+	TargetID *uuid.UUID
+	Action   string
+	Limit    int
+	Offset   int
+}
+
+type AdminAuditLogRepository interface {
+	Create(entry *models.AdminAuditLog) error
+	List(filter AdminAuditLogFilter) ([]*models.AdminAuditLog, int64, error)
+}
@@ -0,0 +1,37 @@
+// domain/repository/bot_repository.go
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// BotRepository เป็น interface สำหรับจัดการบัญชีบอท
+type BotRepository interface {
+	// CreateWithUser สร้าง User ของบอทและ Bot ใน transaction เดียวกัน
+	CreateWithUser(user *models.User, bot *models.Bot) error
+	// GetByID / GetByUserID / GetByTokenHash คืนค่า nil ถ้าไม่พบ (preload User)
+	GetByID(id uuid.UUID) (*models.Bot, error)
+	GetByUserID(userID uuid.UUID) (*models.Bot, error)
+	GetByTokenHash(tokenHash string) (*models.Bot, error)
+	GetByOwnerID(ownerID uuid.UUID) ([]*models.Bot, error)
+	// GetWebhookBotsByConversationID ดึงบอทที่เป็นสมาชิกของการสนทนา เปิดใช้งาน และตั้ง webhook ไว้ (preload User)
+	GetWebhookBotsByConversationID(conversationID uuid.UUID) ([]*models.Bot, error)
+	Update(bot *models.Bot) error
+	UpdateUserProfile(userID uuid.UUID, displayName, profileImageURL string) error
+	TouchLastUsed(id uuid.UUID, usedAt time.Time) error
+	// DeleteWithUser ลบบอท ออกจากทุกการสนทนา และปิดบัญชี User ของบอท
+	DeleteWithUser(bot *models.Bot) error
+}
+
+// BotWebhookDeliveryRepository เป็น interface สำหรับบันทึกผลการส่ง webhook ของบอท
+type BotWebhookDeliveryRepository interface {
+	Create(delivery *models.BotWebhookDelivery) error
+	Update(delivery *models.BotWebhookDelivery) error
+	// ClaimDueRetries จองรายการที่ถึงเวลา retry โดยเลื่อน next_retry_at ออกไปเท่ากับ lease (preload Bot)
+	// instance อื่นจะไม่ได้รายการเดียวกันจนกว่า lease จะหมด
+	ClaimDueRetries(now time.Time, lease time.Duration, limit int) ([]*models.BotWebhookDelivery, error)
+	GetByBotID(botID uuid.UUID, status string, limit, offset int) ([]*models.BotWebhookDelivery, int64, error)
+}
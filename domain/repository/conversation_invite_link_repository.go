@@ -0,0 +1,33 @@
+// domain/repository/conversation_invite_link_repository.go
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// ConversationInviteLinkRepository จัดการลิงก์เชิญเข้ากลุ่ม
+type ConversationInviteLinkRepository interface {
+	// Create สร้างลิงก์เชิญใหม่
+	Create(link *models.ConversationInviteLink) error
+
+	// FindByID ดึงลิงก์เชิญตาม ID (nil ถ้าไม่พบ)
+	FindByID(id uuid.UUID) (*models.ConversationInviteLink, error)
+
+	// FindByCode ดึงลิงก์เชิญตามรหัส (nil ถ้าไม่พบ)
+	FindByCode(code string) (*models.ConversationInviteLink, error)
+
+	// ListByConversationID ดึงลิงก์เชิญของการสนทนา (ใหม่สุดก่อน, includeRevoked = รวมลิงก์ที่ถูกเพิกถอน)
+	ListByConversationID(conversationID uuid.UUID, includeRevoked bool) ([]*models.ConversationInviteLink, error)
+
+	// Revoke เพิกถอนลิงก์เชิญ (false ถ้าถูกเพิกถอนไปแล้วหรือไม่พบ)
+	Revoke(id uuid.UUID, revokedAt time.Time) (bool, error)
+
+	// ClaimUse เพิ่มจำนวนการใช้งานแบบ atomic เฉพาะเมื่อลิงก์ยังใช้งานได้ (false ถ้าใช้ไม่ได้แล้ว)
+	ClaimUse(id uuid.UUID, now time.Time) (bool, error)
+
+	// ReleaseUse คืนจำนวนการใช้งานเมื่อการเข้าร่วมล้มเหลวหลัง ClaimUse
+	ReleaseUse(id uuid.UUID) error
+}
@@ -0,0 +1,27 @@
+// domain/repository/conversation_join_request_repository.go
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// ConversationJoinRequestRepository จัดการคำขอเข้าร่วมกลุ่ม
+type ConversationJoinRequestRepository interface {
+	// Create สร้างคำขอใหม่
+	Create(request *models.ConversationJoinRequest) error
+
+	// FindByID ดึงคำขอตาม ID พร้อมข้อมูลผู้ขอ (nil ถ้าไม่พบ)
+	FindByID(id uuid.UUID) (*models.ConversationJoinRequest, error)
+
+	// FindPending ดึงคำขอที่รออนุมัติของผู้ใช้ในกลุ่ม (nil ถ้าไม่มี)
+	FindPending(conversationID, userID uuid.UUID) (*models.ConversationJoinRequest, error)
+
+	// ListByConversationID ดึงคำขอของกลุ่ม (เก่าสุดก่อน, status ว่าง = ทุกสถานะ)
+	ListByConversationID(conversationID uuid.UUID, status string, limit, offset int) ([]*models.ConversationJoinRequest, int64, error)
+
+	// UpdateStatus เปลี่ยนสถานะเฉพาะเมื่อสถานะปัจจุบันตรงกับ fromStatus (false ถ้าถูกดำเนินการไปแล้ว)
+	UpdateStatus(id uuid.UUID, fromStatus, toStatus string, reviewerID *uuid.UUID, reviewedAt *time.Time) (bool, error)
+}
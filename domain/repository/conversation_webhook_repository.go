@@ -0,0 +1,33 @@
+// domain/repository/conversation_webhook_repository.go
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// ConversationWebhookRepository จัดการ incoming webhook ของการสนทนา
+type ConversationWebhookRepository interface {
+	// Create สร้าง webhook ใหม่
+	Create(webhook *models.ConversationWebhook) error
+
+	// FindByID ดึง webhook ตาม ID (nil ถ้าไม่พบ)
+	FindByID(id uuid.UUID) (*models.ConversationWebhook, error)
+
+	// FindByTokenHash ดึง webhook ตาม hash ของ token (nil ถ้าไม่พบ)
+	FindByTokenHash(tokenHash string) (*models.ConversationWebhook, error)
+
+	// ListByConversationID ดึง webhook ของการสนทนา (ใหม่สุดก่อน, includeRevoked = รวมที่ถูกเพิกถอน)
+	ListByConversationID(conversationID uuid.UUID, includeRevoked bool) ([]*models.ConversationWebhook, error)
+
+	// CountActiveByConversationID นับ webhook ที่ยังใช้งานได้ของการสนทนา
+	CountActiveByConversationID(conversationID uuid.UUID) (int64, error)
+
+	// Revoke เพิกถอน webhook (false ถ้าถูกเพิกถอนไปแล้วหรือไม่พบ)
+	Revoke(id uuid.UUID, revokedAt time.Time) (bool, error)
+
+	// TouchLastUsed อัปเดตเวลาที่ใช้งานล่าสุด
+	TouchLastUsed(id uuid.UUID, usedAt time.Time) error
+}
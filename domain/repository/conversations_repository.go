@@ -40,6 +40,12 @@ type ConversationRepository interface {
 	// GetMembers ดึงรายการสมาชิกทั้งหมดในการสนทนา
 	GetMembers(conversationID uuid.UUID) ([]*models.ConversationMember, error)
 
+	// GetMemberUserIDs ดึงเฉพาะ user ID ของสมาชิกในการสนทนา
+	GetMemberUserIDs(conversationID uuid.UUID) ([]uuid.UUID, error)
+
+	// GetDirectPartnerIDs ดึง user ID ของคู่สนทนาในการสนทนาแบบ direct ทั้งหมดของผู้ใช้
+	GetDirectPartnerIDs(userID uuid.UUID) ([]uuid.UUID, error)
+
 	// UpdateMember อัปเดตข้อมูลสมาชิก
 	UpdateMember(member *models.ConversationMember) error
 
@@ -58,6 +64,9 @@ type ConversationRepository interface {
 	// SetMuteStatus กำหนดสถานะการปิดเสียงของการสนทนา
 	SetMuteStatus(conversationID, userID uuid.UUID, isMuted bool) error
 
+	// UpdateNotificationSettings บันทึกสถานะปิดเสียงพร้อม notification_settings ของสมาชิก
+	UpdateNotificationSettings(conversationID, userID uuid.UUID, isMuted bool, settings types.JSONB) error
+
 	// SetHiddenStatus กำหนดสถานะการซ่อนการสนทนา
 	SetHiddenStatus(conversationID, userID uuid.UUID, isHidden bool) error
 
@@ -101,4 +110,23 @@ type ConversationRepository interface {
 
 	// UnhideForAllMembers ยกเลิกการซ่อนการสนทนาสำหรับสมาชิกทุกคน (ใช้เมื่อมีข้อความใหม่)
 	UnhideForAllMembers(conversationID uuid.UUID) error
+
+	// CountMembers นับจำนวนสมาชิกโดยไม่โหลดข้อมูลสมาชิก (ใช้กับช่องที่มีผู้ติดตามจำนวนมาก)
+	CountMembers(conversationID uuid.UUID) (int64, error)
+
+	// GetMembersPage ดึงสมาชิกแบบแบ่งหน้าพร้อมข้อมูลผู้ใช้ (เรียงตามเวลาเข้าร่วม)
+	GetMembersPage(conversationID uuid.UUID, limit, offset int) ([]*models.ConversationMember, error)
+
+	// GetMembersByUserIDs ดึงข้อมูลสมาชิกของผู้ใช้ที่ระบุในการสนทนาในคำสั่งเดียว (preload User, ข้ามผู้ที่ไม่ได้เป็นสมาชิก)
+	GetMembersByUserIDs(conversationID uuid.UUID, userIDs []uuid.UUID) ([]*models.ConversationMember, error)
+
+	// GetKeywordAlertMembers ดึงเฉพาะสมาชิกที่ผู้ใช้ตั้ง keyword alert ไว้ พร้อมข้อมูลผู้ใช้ (ไม่ต้องอ่านสมาชิกทั้งกลุ่มทุกข้อความ)
+	GetKeywordAlertMembers(conversationID uuid.UUID) ([]*models.ConversationMember, error)
+
+	// GetChannelNotificationMembers ดึงสมาชิกของช่องที่รับการแจ้งเตือนทุกโพสต์ (owner/admin และผู้ติดตามที่ตั้ง level = all) พร้อมข้อมูลผู้ใช้
+	GetChannelNotificationMembers(conversationID uuid.UUID) ([]*models.ConversationMember, error)
+
+	// AdvanceMemberLastRead เลื่อน last_read_at จาก fromTime ไปเป็น toTime เฉพาะเมื่อค่าปัจจุบันยังเป็น fromTime
+	// (false ถ้ามีคำขออื่นเลื่อนไปก่อนแล้ว, fromTime nil = ยังไม่เคยอ่าน)
+	AdvanceMemberLastRead(conversationID, userID uuid.UUID, fromTime *time.Time, toTime time.Time) (bool, error)
 }
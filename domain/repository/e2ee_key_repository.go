@@ -0,0 +1,34 @@
+// domain/repository/e2ee_key_repository.go
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// E2EEKeyRepository จัดการ public key ของอุปกรณ์สำหรับการเข้ารหัสแบบ end-to-end
+type E2EEKeyRepository interface {
+	// UpsertBundle บันทึก key bundle ของอุปกรณ์ (แทนที่ของเดิมถ้า user+device ซ้ำ)
+	UpsertBundle(bundle *models.E2EEKeyBundle) error
+
+	// GetBundle ดึง key bundle ของอุปกรณ์ (nil ถ้าไม่พบ)
+	GetBundle(userID uuid.UUID, deviceID string) (*models.E2EEKeyBundle, error)
+
+	// GetBundlesByUserID ดึง key bundle ของทุกอุปกรณ์ของผู้ใช้
+	GetBundlesByUserID(userID uuid.UUID) ([]*models.E2EEKeyBundle, error)
+
+	// DeleteBundle ลบอุปกรณ์พร้อม one-time pre-key ทั้งหมด (false ถ้าไม่พบ)
+	DeleteBundle(userID uuid.UUID, deviceID string) (bool, error)
+
+	// AddOneTimePreKeys เพิ่ม one-time pre-key (key_id ที่ซ้ำจะถูกข้าม)
+	AddOneTimePreKeys(keys []*models.E2EEOneTimePreKey) error
+
+	// DeleteOneTimePreKeys ลบ one-time pre-key ทั้งหมดของอุปกรณ์
+	DeleteOneTimePreKeys(userID uuid.UUID, deviceID string) error
+
+	// ClaimOneTimePreKey ดึงและลบ one-time pre-key หนึ่งอันของอุปกรณ์ (nil ถ้าหมดแล้ว)
+	ClaimOneTimePreKey(userID uuid.UUID, deviceID string) (*models.E2EEOneTimePreKey, error)
+
+	// CountOneTimePreKeys นับ one-time pre-key ที่เหลือของอุปกรณ์
+	CountOneTimePreKeys(userID uuid.UUID, deviceID string) (int64, error)
+}
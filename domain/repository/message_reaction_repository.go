@@ -0,0 +1,19 @@
+// domain/repository/message_reaction_repository.go
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// MessageReactionRepository เป็น interface สำหรับจัดการข้อมูลปฏิกิริยาต่อข้อความ
+type MessageReactionRepository interface {
+	// Create เพิ่มปฏิกิริยา (ถ้ามีอยู่แล้วจะไม่เพิ่มซ้ำ) คืนค่า true ถ้ามีการเพิ่มจริง
+	Create(reaction *models.MessageReaction) (bool, error)
+	// Delete ลบปฏิกิริยาของผู้ใช้ คืนค่า true ถ้ามีการลบจริง
+	Delete(messageID, userID uuid.UUID, emoji string) (bool, error)
+
+	GetByMessageID(messageID uuid.UUID) ([]*models.MessageReaction, error)
+	// GetByMessageIDs ดึงปฏิกิริยาของข้อความหลายรายการในคำสั่งเดียว (ใช้ตอนแปลงข้อความทั้งหน้า)
+	GetByMessageIDs(messageIDs []uuid.UUID) ([]*models.MessageReaction, error)
+}
@@ -58,6 +58,12 @@ type MessageRepository interface {
 	// เมธอดสำหรับดึงข้อความทั้งหมดที่ไม่ใช่ของผู้ใช้ (สำหรับกรณีไม่มี LastReadAt)
 	GetAllUnreadMessages(conversationID uuid.UUID, excludeUserID uuid.UUID) ([]*models.Message, error)
 
+	// CountMessagesAfterTime นับข้อความหลังเวลาที่กำหนดที่ไม่ใช่ของผู้ใช้ (ใช้นับข้อความที่ยังไม่ได้อ่านในช่อง)
+	CountMessagesAfterTime(conversationID uuid.UUID, afterTime time.Time, excludeUserID uuid.UUID) (int64, error)
+
+	// GetMessageIDsAfterTime ดึง ID ของข้อความหลังเวลาที่กำหนดที่ไม่ใช่ของผู้ใช้ (เก่าไปใหม่ ไม่เกิน limit)
+	GetMessageIDsAfterTime(conversationID uuid.UUID, afterTime time.Time, excludeUserID uuid.UUID, limit int) ([]uuid.UUID, error)
+
 	// สรุปข้อมูล media และ link
 	GetMessageTypeSummary(conversationID uuid.UUID) (map[string]int64, error)
 	CountMessagesWithLinks(conversationID uuid.UUID) (int64, error)
@@ -78,6 +84,27 @@ type MessageRepository interface {
 	// Returns: messages, nextCursor, hasMore, error
 	SearchMessages(searchQuery string, conversationID *uuid.UUID, userID uuid.UUID, limit int, cursor *string, direction string) ([]*models.Message, *string, bool, error)
 
+	// Threads (ข้อความตอบกลับในเธรด - ไม่รวมใน timeline หลัก)
+	// GetLatestThreadReplies ดึงข้อความล่าสุดในเธรด เรียง ASC (เก่า → ใหม่)
+	GetLatestThreadReplies(threadRootID uuid.UUID, limit int) ([]*models.Message, error)
+	GetThreadRepliesBefore(threadRootID, messageID uuid.UUID, limit int) ([]*models.Message, error)
+	GetThreadRepliesAfter(threadRootID, messageID uuid.UUID, limit int) ([]*models.Message, error)
+	// CountThreadRepliesAfterTime นับข้อความในเธรดที่ใหม่กว่าเวลาที่กำหนด (ไม่รวมของผู้ใช้ที่ระบุ)
+	CountThreadRepliesAfterTime(threadRootID uuid.UUID, afterTime *time.Time, excludeUserID uuid.UUID) (int64, error)
+	// RefreshThreadStats คำนวณ thread_reply_count / thread_last_reply_* ของข้อความต้นเธรดใหม่
+	RefreshThreadStats(threadRootID uuid.UUID) error
+
 	// Bulk/Album messages
 	GetMessagesByAlbumID(albumID string) ([]*models.Message, error)
+
+	// ข้อความที่หายไปเอง (service กำหนด expires_at ตาม message_ttl ของการสนทนาก่อน Create)
+	// ClaimExpiredMessages soft delete ข้อความที่หมดอายุแล้ว (เก่าไปใหม่) และคืนค่าข้อความก่อนถูกลบ
+	// แต่ละข้อความถูกคืนค่าให้ผู้เรียกเพียงครั้งเดียวแม้มีหลาย instance
+	ClaimExpiredMessages(before time.Time, limit int) ([]*models.Message, error)
+	// CountActiveByMediaURL นับข้อความที่ยังไม่ถูกลบซึ่งใช้ media URL นี้ (เช่น ข้อความที่ถูก forward)
+	CountActiveByMediaURL(mediaURL string) (int64, error)
+
+	// IncrementViewCounts เพิ่มยอดผู้ชมของข้อความในช่วง (afterTime, upToTime] ที่ผู้ชมไม่ได้ส่งเอง
+	// afterTime คือจุดอ่านเดิมของผู้ชม (หรือเวลาเข้าร่วม) คืนค่าจำนวนข้อความที่ถูกนับ
+	IncrementViewCounts(conversationID, viewerID uuid.UUID, afterTime, upToTime time.Time) (int64, error)
 }
@@ -0,0 +1,42 @@
+// domain/repository/moderation_report_repository.go
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// ModerationReportFilter เงื่อนไขการค้นหารายงาน (ค่าว่าง = ไม่กรอง)
+type ModerationReportFilter struct {
+	Status     string
+	TargetType string
+	Reason     string
+	Source     string
+	TargetID   *uuid.UUID
+	AssigneeID *uuid.UUID
+	Limit      int
+	Offset     int
+}
+
+// ModerationReportRepository จัดการรายงานเนื้อหาและคิวตรวจสอบ
+type ModerationReportRepository interface {
+	// Create สร้างรายงานใหม่
+	Create(report *models.ModerationReport) error
+
+	// FindByID ดึงรายงานตาม ID พร้อมผู้รายงานและผู้รับผิดชอบ (nil ถ้าไม่พบ)
+	FindByID(id uuid.UUID) (*models.ModerationReport, error)
+
+	// HasOpenReport ตรวจสอบว่าผู้ใช้มีรายงานที่ยังไม่ปิดของเป้าหมายนี้อยู่แล้วหรือไม่ (reporterID nil = รายงานอัตโนมัติ)
+	HasOpenReport(reporterID *uuid.UUID, targetType string, targetID uuid.UUID) (bool, error)
+
+	// List ค้นหารายงาน (เก่าสุดก่อน เพื่อให้คิวทำงานแบบ FIFO)
+	List(filter ModerationReportFilter) ([]*models.ModerationReport, int64, error)
+
+	// Update บันทึกการเปลี่ยนแปลงของรายงาน
+	Update(report *models.ModerationReport) error
+
+	// ResolveOpenByTarget ปิดรายงานอื่นที่ยังไม่ปิดของเป้าหมายเดียวกันหลังดำเนินการแล้ว คืนค่าจำนวนที่ปิด
+	ResolveOpenByTarget(targetType string, targetID, excludeID uuid.UUID, action, note string, resolvedBy uuid.UUID, resolvedAt time.Time) (int64, error)
+}
@@ -0,0 +1,27 @@
+// domain/repository/notification_repository.go
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// NotificationRepository จัดการกล่องแจ้งเตือนของผู้ใช้
+type NotificationRepository interface {
+	CreateBatch(notifications []*models.Notification) error
+
+	// ListByUserID ดึงการแจ้งเตือนล่าสุดก่อน (cursor = ID ของรายการสุดท้ายในหน้าก่อน)
+	// Returns: notifications, nextCursor, hasMore, error
+	ListByUserID(userID uuid.UUID, limit int, cursor *string, unreadOnly bool) ([]*models.Notification, *string, bool, error)
+
+	CountUnread(userID uuid.UUID) (int64, error)
+
+	// MarkRead ทำเครื่องหมายว่าอ่านแล้วเฉพาะรายการของผู้ใช้ที่ยังไม่ได้อ่าน คืนจำนวนแถวที่เปลี่ยน
+	MarkRead(userID uuid.UUID, ids []uuid.UUID, readAt time.Time) (int64, error)
+	MarkAllRead(userID uuid.UUID, readAt time.Time) (int64, error)
+
+	// Delete ลบการแจ้งเตือนของผู้ใช้ คืน false ถ้าไม่พบ
+	Delete(userID, id uuid.UUID) (bool, error)
+}
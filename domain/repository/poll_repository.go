@@ -0,0 +1,28 @@
+// domain/repository/poll_repository.go
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// PollRepository เป็น interface สำหรับจัดการข้อมูลโพล
+type PollRepository interface {
+	// Create สร้างโพลพร้อมตัวเลือก (poll.Options) ใน transaction เดียว
+	Create(poll *models.Poll) error
+	// GetByID / GetByMessageID ดึงโพลพร้อมตัวเลือก (เรียงตาม position) คืนค่า nil ถ้าไม่พบ
+	GetByID(id uuid.UUID) (*models.Poll, error)
+	GetByMessageID(messageID uuid.UUID) (*models.Poll, error)
+	Close(pollID, closedBy uuid.UUID, closedAt time.Time) error
+
+	// การโหวต
+	GetVotes(pollID uuid.UUID) ([]*models.PollVote, error)
+	// CastVotes บันทึกการโหวตใน transaction เดียวที่ตรวจว่าโพลยังเปิดอยู่ ณ เวลา now
+	// replace = แทนที่การโหวตเดิมทั้งหมด (โพลแบบข้อเดียว) ไม่เช่นนั้นเพิ่มตัวเลือกเข้าไปโดยข้ามที่โหวตไว้แล้ว
+	// คืนค่า false ถ้าโพลถูกปิดแล้ว (ไม่มีการบันทึก)
+	CastVotes(pollID, userID uuid.UUID, optionIDs []uuid.UUID, replace bool, now time.Time) (bool, error)
+	// DeleteUserVotes ลบการโหวตของผู้ใช้ (optionIDs ว่าง = ลบทั้งหมด) คืนค่าจำนวนที่ลบ
+	DeleteUserVotes(pollID, userID uuid.UUID, optionIDs []uuid.UUID) (int64, error)
+}
@@ -0,0 +1,33 @@
+// domain/repository/push_repository.go
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// DeviceTokenRepository เป็น interface สำหรับจัดการ device token ของ push notification
+type DeviceTokenRepository interface {
+	// Upsert บันทึก token (ถ้า provider+token มีอยู่แล้วจะย้ายไปเป็นของผู้ใช้/อุปกรณ์ใหม่และเปิดใช้งาน)
+	Upsert(token *models.DeviceToken) error
+	GetByID(id uuid.UUID) (*models.DeviceToken, error)
+	GetActiveByUserID(userID uuid.UUID) ([]*models.DeviceToken, error)
+	GetActiveByUserIDs(userIDs []uuid.UUID) ([]*models.DeviceToken, error)
+	// Delete ลบ token ของผู้ใช้ คืนค่า false ถ้าไม่พบ
+	Delete(id, userID uuid.UUID) (bool, error)
+	Deactivate(id uuid.UUID) error
+	TouchLastUsed(id uuid.UUID, usedAt time.Time) error
+}
+
+// PushDeliveryRepository เป็น interface สำหรับบันทึกผลการส่ง push
+type PushDeliveryRepository interface {
+	Create(delivery *models.PushDelivery) error
+	Update(delivery *models.PushDelivery) error
+	// ClaimDueRetries จองรายการที่ถึงเวลา retry โดยเลื่อน next_retry_at ออกไปเท่ากับ lease (preload DeviceToken)
+	// instance อื่นจะไม่ได้รายการเดียวกันจนกว่า lease จะหมด
+	ClaimDueRetries(now time.Time, lease time.Duration, limit int) ([]*models.PushDelivery, error)
+	// GetByUserID ดึงประวัติการส่งของผู้ใช้ (status ว่าง = ทุกสถานะ) เรียงจากใหม่ไปเก่า
+	GetByUserID(userID uuid.UUID, status string, limit, offset int) ([]*models.PushDelivery, int64, error)
+}
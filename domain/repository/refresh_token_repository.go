@@ -13,5 +13,14 @@ type RefreshTokenRepository interface {
 	FindByToken(token string) (*models.RefreshToken, error)
 	RevokeByUserID(userID uuid.UUID) error
 	DeleteExpired(before time.Time) error
+
+	// Sessions (หนึ่ง refresh token ต่ออุปกรณ์)
+	FindByID(id uuid.UUID) (*models.RefreshToken, error)
+	FindActiveByUserID(userID uuid.UUID, now time.Time) ([]*models.RefreshToken, error)
+	// Rotate เปลี่ยน token ของ session เฉพาะเมื่อ token เดิมยังตรงและไม่ถูกเพิกถอน (กันการรีเฟรชซ้อน)
+	Rotate(session *models.RefreshToken, oldToken string) (bool, error)
+	RevokeByIDs(ids []uuid.UUID) error
+	// MarkTwoFactorVerified ทำเครื่องหมายว่า session ผ่านการยืนยัน 2FA แล้ว (เมื่อเปิดใช้ 2FA จากอุปกรณ์นี้)
+	MarkTwoFactorVerified(id uuid.UUID) error
 	// เพิ่ม method อื่นๆ ตามที่จำเป็น
 }
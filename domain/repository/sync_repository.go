@@ -0,0 +1,46 @@
+// domain/repository/sync_repository.go
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// SyncCursor ตำแหน่งใน change feed (เรียงตาม timestamp แล้วตาม id)
+type SyncCursor struct {
+	At time.Time
+	ID uuid.UUID
+}
+
+// SyncRepository ดึงข้อมูลที่เปลี่ยนแปลงสำหรับ delta sync
+// ทุกเมธอดคืนแถวที่ (timestamp, id) > after และ timestamp <= until เรียงจากเก่าไปใหม่ ไม่เกิน limit แถว
+type SyncRepository interface {
+	// GetChangedMessages ข้อความใหม่/แก้ไข/ลบ ในการสนทนาที่ผู้ใช้เป็นสมาชิก
+	GetChangedMessages(userID uuid.UUID, after SyncCursor, until time.Time, limit int) ([]*models.Message, error)
+
+	// GetChangedConversations ข้อมูลการสนทนาที่เปลี่ยนแปลง
+	GetChangedConversations(userID uuid.UUID, after SyncCursor, until time.Time, limit int) ([]*models.Conversation, error)
+
+	// GetChangedMembers สมาชิกที่เพิ่ม/เปลี่ยน role/read pointer ในการสนทนาที่ผู้ใช้เป็นสมาชิก
+	GetChangedMembers(userID uuid.UUID, after SyncCursor, until time.Time, limit int) ([]*models.ConversationMember, error)
+
+	// GetActivities กิจกรรมกลุ่ม รวมถึงกิจกรรมที่ผู้ใช้เป็นเป้าหมาย (เช่น ถูกนำออกจากกลุ่ม)
+	GetActivities(userID uuid.UUID, after SyncCursor, until time.Time, limit int) ([]*models.GroupActivity, error)
+
+	// GetChangedFriendships ความสัมพันธ์เพื่อนที่เปลี่ยนแปลง
+	GetChangedFriendships(userID uuid.UUID, after SyncCursor, until time.Time, limit int) ([]*models.UserFriendship, error)
+
+	// GetChangedPins การปักหมุดส่วนตัวของผู้ใช้และการปักหมุดสาธารณะในการสนทนาที่เป็นสมาชิก
+	GetChangedPins(userID uuid.UUID, after SyncCursor, until time.Time, limit int) ([]*models.PinnedMessage, error)
+
+	// GetChangedNotes โน้ตของผู้ใช้และโน้ตที่แชร์ในการสนทนาที่เป็นสมาชิก
+	GetChangedNotes(userID uuid.UUID, after SyncCursor, until time.Time, limit int) ([]*models.Note, error)
+
+	// GetTombstones ข้อมูลที่ถูกลบจริงซึ่งผู้ใช้ต้องลบออกจาก local store
+	GetTombstones(userID uuid.UUID, after SyncCursor, until time.Time, limit int) ([]*models.SyncTombstone, error)
+
+	// DeleteTombstonesBefore ลบ tombstone ที่เก่ากว่าเวลาที่กำหนด
+	DeleteTombstonesBefore(before time.Time) (int64, error)
+}
@@ -0,0 +1,16 @@
+// domain/repository/thread_read_repository.go
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ThreadReadRepository เป็น interface สำหรับจัดการสถานะการอ่านเธรด
+type ThreadReadRepository interface {
+	// MarkRead บันทึกเวลาอ่านล่าสุดของเธรด (ไม่ย้อนเวลากลับ)
+	MarkRead(threadRootID, userID uuid.UUID, readAt time.Time) error
+	// GetLastReadAt คืนค่า nil ถ้าผู้ใช้ยังไม่เคยเปิดอ่านเธรดนี้
+	GetLastReadAt(threadRootID, userID uuid.UUID) (*time.Time, error)
+}
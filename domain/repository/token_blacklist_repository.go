@@ -11,5 +11,6 @@ type TokenBlacklistRepository interface {
 	Create(blacklist *models.TokenBlacklist) error
 	FindByToken(token string) (*models.TokenBlacklist, error)
 	IsTokenBlacklisted(token string) (bool, error)
+	IsAnyTokenBlacklisted(tokens []string) (bool, error)
 	DeleteExpired(before time.Time) error
 }
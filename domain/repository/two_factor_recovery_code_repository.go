@@ -0,0 +1,24 @@
+// domain/repository/two_factor_recovery_code_repository.go
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// TwoFactorRecoveryCodeRepository จัดการรหัสกู้คืนของ 2FA
+type TwoFactorRecoveryCodeRepository interface {
+	// ReplaceForUser ลบรหัสเดิมทั้งหมดของผู้ใช้แล้วบันทึกชุดใหม่ (ใน transaction เดียว)
+	ReplaceForUser(userID uuid.UUID, codes []*models.TwoFactorRecoveryCode) error
+
+	// Consume ทำเครื่องหมายว่าใช้แล้ว เฉพาะเมื่อยังไม่ถูกใช้ (false = ไม่พบหรือใช้ไปแล้ว)
+	Consume(userID uuid.UUID, codeHash string, usedAt time.Time) (bool, error)
+
+	// CountUnused นับรหัสที่ยังใช้ได้
+	CountUnused(userID uuid.UUID) (int64, error)
+
+	// DeleteByUserID ลบรหัสทั้งหมดของผู้ใช้ (เมื่อปิด 2FA)
+	DeleteByUserID(userID uuid.UUID) error
+}
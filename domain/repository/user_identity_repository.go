@@ -0,0 +1,48 @@
+// domain/repository/user_identity_repository.go
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// UserIdentityRepository จัดการบัญชีภายนอกที่เชื่อมกับผู้ใช้
+type UserIdentityRepository interface {
+	// Create เชื่อมบัญชีใหม่
+	Create(identity *models.UserIdentity) error
+
+	// FindByID ดึงตาม ID (nil ถ้าไม่พบ)
+	FindByID(id uuid.UUID) (*models.UserIdentity, error)
+
+	// FindByProviderSubject ดึงตาม provider และ subject (nil ถ้าไม่พบ)
+	FindByProviderSubject(provider, subject string) (*models.UserIdentity, error)
+
+	// FindByUserAndProvider ดึงบัญชีของ provider ที่ผู้ใช้เชื่อมไว้ (nil ถ้าไม่พบ)
+	FindByUserAndProvider(userID uuid.UUID, provider string) (*models.UserIdentity, error)
+
+	// ListByUserID ดึงบัญชีทั้งหมดที่ผู้ใช้เชื่อมไว้
+	ListByUserID(userID uuid.UUID) ([]*models.UserIdentity, error)
+
+	// TouchLastLogin อัปเดตเวลาที่ใช้เข้าสู่ระบบล่าสุด
+	TouchLastLogin(id uuid.UUID, at time.Time) error
+
+	// Delete ยกเลิกการเชื่อมบัญชี
+	Delete(id uuid.UUID) error
+}
+
+// OAuthStateRepository จัดการสถานะของ authorization code flow
+type OAuthStateRepository interface {
+	// Create บันทึก state ใหม่
+	Create(state *models.OAuthState) error
+
+	// FindByStateHash ดึงตาม hash ของ state (nil ถ้าไม่พบ)
+	FindByStateHash(stateHash string) (*models.OAuthState, error)
+
+	// Consume ทำเครื่องหมายว่าใช้แล้ว เฉพาะเมื่อยังไม่ถูกใช้และยังไม่หมดอายุ (false = ใช้ไม่ได้แล้ว)
+	Consume(id uuid.UUID, usedAt time.Time) (bool, error)
+
+	// DeleteExpired ลบ state ที่หมดอายุก่อนเวลาที่กำหนด
+	DeleteExpired(before time.Time) error
+}
@@ -17,4 +17,10 @@ type UserRepository interface {
 	// ไม่ต้องเพิ่ม GetByID เพราะมี FindByID อยู่แล้ว แค่เปลี่ยนการเรียกใช้ในโค้ดเป็น FindByID แทน
 	// เพิ่มฟังก์ชันใหม่
 	SearchUsersExact(query string, limit, offset int) ([]*models.User, int64, error)
+
+	// ListForAdmin ค้นหาผู้ใช้ทุกสถานะสำหรับผู้ดูแลระบบ (status/role ว่าง = ไม่กรอง)
+	ListForAdmin(query, status, role string, limit, offset int) ([]*models.User, int64, error)
+
+	// AdvanceTwoFactorStep บันทึก time step ของรหัส TOTP ที่ใช้แล้ว เฉพาะเมื่อใหม่กว่าค่าเดิม (false = รหัสถูกใช้ไปแล้ว)
+	AdvanceTwoFactorStep(id uuid.UUID, step int64) (bool, error)
 }
@@ -0,0 +1,32 @@
+// domain/service/admin_service.go
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// AdminService งานของผู้ดูแลแพลตฟอร์ม (สิทธิ์ตาม system role ตรวจสอบที่ middleware)
+type AdminService interface {
+	// Users
+	ListUsers(query, status, role string, limit, offset int) ([]*models.User, int64, error)
+	GetUser(userID uuid.UUID) (*models.User, error)
+	SuspendUser(actorID uuid.UUID, actorRole string, userID uuid.UUID, reason string) (*models.User, error)
+	UnsuspendUser(actorID uuid.UUID, actorRole string, userID uuid.UUID) (*models.User, error)
+	SetSystemRole(actorID, userID uuid.UUID, role string) (*models.User, error)
+
+	// Conversations
+	InspectConversation(conversationID uuid.UUID) (*dto.AdminConversationDTO, error)
+	GetConversationMessages(conversationID uuid.UUID, limit, offset int) ([]*models.Message, int64, error)
+	DisableConversation(actorID, conversationID uuid.UUID, reason string) (*models.Conversation, error)
+	EnableConversation(actorID, conversationID uuid.UUID) (*models.Conversation, error)
+
+	// Realtime (สถิติของ WebSocket Hub ใน instance นี้)
+	GetRealtimeStats() map[string]interface{}
+	GetRealtimeConnections() map[string]interface{}
+
+	// Audit
+	RecordAudit(entry *models.AdminAuditLog) error
+	ListAuditLogs(actorID, targetID *uuid.UUID, action string, limit, offset int) ([]*models.AdminAuditLog, int64, error)
+}
@@ -3,15 +3,64 @@
 package service
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
 	"github.com/thizplus/gofiber-chat-api/domain/models"
 )
 
 type AuthService interface {
-	Register(username, password, email, displayName string) (*models.User, string, string, error)
-	Login(username, password string) (*models.User, string, string, error)
-	RefreshToken(refreshToken string) (string, string, error)
-	Logout(userID uuid.UUID) error                       // เปลี่ยนเป็น UUID
+	Register(username, password, email, displayName string, device *dto.SessionDeviceInfo) (*models.User, string, string, error)
+	Login(username, password string, device *dto.SessionDeviceInfo) (*models.User, string, string, error)
+	RefreshToken(refreshToken string, device *dto.SessionDeviceInfo) (string, string, error)
+	Logout(userID uuid.UUID, sessionID *uuid.UUID) error // sessionID = nil ออกจากระบบทุกอุปกรณ์ (token แบบเก่าที่ไม่มี sid)
 	BlacklistToken(userID uuid.UUID, token string) error // เปลี่ยนเป็น UUID
 	GetUserByID(userID uuid.UUID) (*models.User, error)  // เปลี่ยนเป็น UUID
+
+	// Sessions (หนึ่ง session ต่ออุปกรณ์)
+	ListSessions(userID uuid.UUID, currentSessionID *uuid.UUID) ([]*dto.SessionDTO, error)
+	RevokeSession(userID, sessionID uuid.UUID) error
+	RevokeOtherSessions(userID uuid.UUID, currentSessionID *uuid.UUID) (int, error)
+	RevokeAllSessions(userID uuid.UUID) error // ใช้เมื่อระงับบัญชี
+
+	// IsTokenRevoked ตรวจสอบว่า access token หรือ session ของมันถูกเพิกถอนแล้วหรือไม่
+	IsTokenRevoked(token string, sessionID *uuid.UUID) (bool, error)
+
+	// JSONWebKeys public key สำหรับให้ service อื่นตรวจ token ของระบบ (/.well-known/jwks.json)
+	JSONWebKeys() []JSONWebKey
+
+	// Password reset & email verification (token ใช้ครั้งเดียว ส่งทางอีเมล)
+	RequestPasswordReset(email string) error       // ไม่บอกว่ามีบัญชีของอีเมลนี้หรือไม่
+	ResetPassword(token, newPassword string) error // ออกจากระบบทุกอุปกรณ์เมื่อสำเร็จ
+	SendEmailVerification(userID uuid.UUID) error
+	VerifyEmail(token string) error
+	InvalidateAccountTokens(userID uuid.UUID) error // ใช้เมื่อเปลี่ยนอีเมล (ลิงก์ที่ส่งไปอีเมลเดิมใช้ไม่ได้)
+
+	// Two-factor authentication (TOTP และรหัสกู้คืนแบบใช้ครั้งเดียว)
+	LoginTwoFactor(challengeToken, code string, device *dto.SessionDeviceInfo) (*models.User, string, string, error)
+	GetTwoFactorStatus(userID uuid.UUID) (*dto.TwoFactorStatusDTO, error)
+	SetupTwoFactor(userID uuid.UUID) (*dto.TwoFactorSetupDTO, error)
+	ConfirmTwoFactor(userID uuid.UUID, currentSessionID *uuid.UUID, code string) ([]string, error) // คืนค่ารหัสกู้คืน
+	DisableTwoFactor(userID uuid.UUID, password, code string) error                                // ต้องยืนยันรหัสผ่านและรหัส 2FA
+	RegenerateRecoveryCodes(userID uuid.UUID, password, code string) ([]string, error)
+
+	// OAuth2/OIDC (authorization code + PKCE ผ่าน Google, Microsoft หรือ OIDC provider อื่น)
+	ListOAuthProviders() []string
+	StartOAuth(provider string, linkUserID *uuid.UUID) (*dto.OAuthAuthorizationDTO, error) // linkUserID = nil เข้าสู่ระบบ, มีค่า = เชื่อมบัญชี
+	LoginWithOAuth(provider, code, state string, device *dto.SessionDeviceInfo) (*models.User, string, string, error)
+	LinkOAuthIdentity(userID uuid.UUID, provider, code, state string) (*dto.UserIdentityDTO, error)
+	ListOAuthIdentities(userID uuid.UUID) ([]*dto.UserIdentityDTO, error)
+	UnlinkOAuthIdentity(userID, identityID uuid.UUID) error // ไม่อนุญาตถ้าเป็นช่องทางเข้าสู่ระบบสุดท้าย
+}
+
+// TwoFactorRequiredError ถูกคืนค่าจาก AuthService.Login เมื่อบัญชีเปิด 2FA
+// client ต้องส่ง ChallengeToken พร้อมรหัส 2FA ไปที่ LoginTwoFactor เพื่อรับ access/refresh token
+type TwoFactorRequiredError struct {
+	ChallengeToken string
+	ExpiresAt      time.Time
+}
+
+func (e *TwoFactorRequiredError) Error() string {
+	return "two-factor authentication required"
 }
@@ -0,0 +1,55 @@
+// domain/service/bot_service.go
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// WebhookRequest คำขอ HTTP POST ที่ส่งไปยัง webhook ของบอท
+type WebhookRequest struct {
+	URL     string
+	Headers map[string]string
+	Body    []byte
+}
+
+// WebhookSender กำหนด interface สำหรับ driver ที่ส่ง webhook ออกไปภายนอก
+type WebhookSender interface {
+	// ValidateURL ตรวจสอบว่า URL ใช้เป็น webhook ได้ (เช่น ต้องเป็น https ยกเว้นเปิดโหมดทดสอบ)
+	ValidateURL(rawURL string) error
+
+	// Send ส่งคำขอหนึ่งครั้ง คืนค่า HTTP status (0 = เชื่อมต่อไม่สำเร็จ) และ error ถ้าไม่ใช่ 2xx
+	Send(ctx context.Context, request *WebhookRequest) (int, error)
+}
+
+// BotService กำหนด interface สำหรับจัดการบัญชีบอทและ Bot API
+type BotService interface {
+	// Owner management
+	CreateBot(ownerID uuid.UUID, req *dto.CreateBotRequest) (*dto.BotCredentialsDTO, error)
+	ListBots(ownerID uuid.UUID) ([]*dto.BotDTO, error)
+	GetBot(ownerID, botID uuid.UUID) (*dto.BotDTO, error)
+	UpdateBot(ownerID, botID uuid.UUID, req *dto.UpdateBotRequest) (*dto.BotDTO, error)
+	// RotateToken ออก API token ใหม่ (token เดิมใช้ไม่ได้ทันที)
+	RotateToken(ownerID, botID uuid.UUID) (*dto.BotCredentialsDTO, error)
+	// RotateWebhookSecret ออก secret ใหม่สำหรับลงลายมือชื่อ webhook
+	RotateWebhookSecret(ownerID, botID uuid.UUID) (*dto.BotCredentialsDTO, error)
+	DeleteBot(ownerID, botID uuid.UUID) error
+	GetDeliveries(ownerID, botID uuid.UUID, status string, limit, offset int) ([]*models.BotWebhookDelivery, int64, error)
+
+	// Bot API
+	// AuthenticateToken ตรวจสอบ API token และคืนค่าบอท (ใช้โดย middleware BotAuth)
+	AuthenticateToken(token string) (*models.Bot, error)
+	GetMe(botUserID uuid.UUID) (*dto.BotDTO, error)
+	// SendMessage ส่งข้อความในนามบอท (ต้องถูกเพิ่มเข้าการสนทนาแล้ว)
+	SendMessage(botUserID, conversationID uuid.UUID, req *dto.BotSendMessageRequest) (*models.Message, error)
+}
+
+// BotWebhookService กำหนด interface สำหรับส่ง outgoing webhook ไปยังบอท
+type BotWebhookService interface {
+	// NotifyNewMessage ส่ง event message/mention/command ไปยังบอทที่เป็นสมาชิกของการสนทนา
+	NotifyNewMessage(message *models.Message)
+	RetryDueDeliveries(limit int) (int, error)
+}
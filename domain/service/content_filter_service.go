@@ -0,0 +1,72 @@
+// domain/service/content_filter_service.go
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+// ผลการตรวจของ content filter
+const (
+	ContentFilterAllow  = "allow"  // ผ่าน
+	ContentFilterMask   = "mask"   // ผ่านโดยแทนที่ส่วนที่ไม่อนุญาต
+	ContentFilterReject = "reject" // ไม่บันทึกข้อความ
+	ContentFilterFlag   = "flag"   // บันทึกข้อความแต่ส่งเข้าคิวของผู้ดูแล
+)
+
+// ContentFilterInput ข้อความที่กำลังจะถูกบันทึก
+type ContentFilterInput struct {
+	ConversationID uuid.UUID
+	SenderID       uuid.UUID
+	Content        string
+	Links          []string // ลิงก์ในเนื้อหา (จาก extractLinks ของ MessageService)
+	IsEdit         bool     // true = แก้ไขข้อความเดิม
+}
+
+// ContentFilterVerdict ผลการตรวจของ filter หนึ่งตัว
+type ContentFilterVerdict struct {
+	Action  string // allow, mask, reject หรือ flag
+	Content string // เนื้อหาหลัง mask (ใช้เมื่อ Action = mask)
+	Reason  string
+}
+
+// ContentFilter ตัวกรองเนื้อหาหนึ่งตัวใน chain (word list, link domain, duplicate flood)
+type ContentFilter interface {
+	// Name ชื่อ filter (ใช้เป็น key ใน Conversation.Metadata["content_filters"])
+	Name() string
+
+	// Check ตรวจเนื้อหา settings คือการตั้งค่าของกลุ่ม (nil = ค่าเริ่มต้นของระบบ)
+	Check(ctx context.Context, input *ContentFilterInput, settings types.JSONB) (*ContentFilterVerdict, error)
+
+	// NormalizeSettings ตรวจสอบและจัดรูปการตั้งค่าที่กลุ่มส่งมาก่อนบันทึก
+	NormalizeSettings(settings types.JSONB) (types.JSONB, error)
+}
+
+// ContentFilterMatch filter ที่ flag ข้อความพร้อมเหตุผล
+type ContentFilterMatch struct {
+	Filter string `json:"filter"`
+	Reason string `json:"reason"`
+}
+
+// ContentFilterResult ผลรวมของ chain (reject คืนค่าเป็น error แทน)
+type ContentFilterResult struct {
+	Content string
+	Flags   []ContentFilterMatch
+}
+
+// ContentFilterService รัน filter chain ก่อนบันทึกข้อความและจัดการการตั้งค่าของกลุ่ม
+type ContentFilterService interface {
+	// Apply รัน filter ตามลำดับ คืนค่า error "message blocked by content filter: ..." ถ้าถูก reject
+	Apply(input *ContentFilterInput) (*ContentFilterResult, error)
+
+	// ReportFlags ส่งข้อความที่ถูก flag เข้าคิวตรวจสอบของผู้ดูแล
+	ReportFlags(message *models.Message, flags []ContentFilterMatch)
+
+	// การตั้งค่าของกลุ่ม (owner/admin)
+	GetSettings(conversationID, userID uuid.UUID) (*dto.ContentFilterSettingsDTO, error)
+	UpdateSettings(conversationID, userID uuid.UUID, req *dto.UpdateContentFilterSettingsRequest) (*dto.ContentFilterSettingsDTO, error)
+}
@@ -16,6 +16,11 @@ const (
 	PermissionChangeRole   Permission = "change_role"
 	PermissionUpdateInfo   Permission = "update_info"
 	PermissionDeleteGroup  Permission = "delete_group"
+	PermissionCreatePoll   Permission = "create_poll"
+
+	PermissionManageInviteLinks    Permission = "manage_invite_links"
+	PermissionManageWebhooks       Permission = "manage_webhooks"
+	PermissionManageContentFilters Permission = "manage_content_filters"
 )
 
 // ConversationMemberService interface สำหรับจัดการสมาชิกในการสนทนา
@@ -23,6 +28,11 @@ type ConversationMemberService interface {
 	// AddMember เพิ่มสมาชิกในการสนทนากลุ่ม
 	AddMember(userID, conversationID, newMemberID uuid.UUID) (*dto.MemberDTO, error)
 
+	// JoinMember เพิ่มผู้ใช้ที่เข้าร่วมเอง (เช่น ผ่านลิงก์เชิญ) ผ่านขั้นตอนเดียวกับ AddMember แต่ไม่ตรวจสิทธิ์แอดมิน
+	// ผู้เรียกต้องตรวจสิทธิ์การเข้าร่วมแล้ว systemText ว่าง = ไม่สร้างข้อความระบบ
+	// คืนค่าข้อความระบบที่สร้าง (nil ถ้าไม่มี) เพื่อให้ผู้เรียกแจ้งสมาชิกในกลุ่ม
+	JoinMember(conversationID, newMemberID uuid.UUID, systemText string) (*dto.MemberDTO, *models.Message, error)
+
 	// BulkAddMembers เพิ่มสมาชิกหลายคนพร้อมกันในการสนทนากลุ่ม
 	BulkAddMembers(userID, conversationID uuid.UUID, newMemberIDs []uuid.UUID) (addedMembers []*dto.MemberDTO, failed []struct {
 		UserID uuid.UUID
@@ -47,6 +57,16 @@ type ConversationMemberService interface {
 	// HasPermission ตรวจสอบว่าผู้ใช้มีสิทธิ์ทำอะไรใน conversation หรือไม่
 	HasPermission(conversationID, userID uuid.UUID, permission Permission) (bool, error)
 
+	// SetPollPermission กำหนดว่าใครสร้างโพลในกลุ่มได้ (all, admins, owner) - owner เท่านั้น
+	SetPollPermission(conversationID, userID uuid.UUID, level string) error
+
+	// SetMessageTTL ตั้งค่าข้อความที่หายไปเอง (วินาที, 0 = ปิด) และสร้างข้อความระบบแจ้งสมาชิก
+	// คืนค่า TTL เดิมและข้อความระบบที่สร้าง
+	SetMessageTTL(conversationID, userID uuid.UUID, ttl int) (int, *models.Message, error)
+
+	// CheckPostPermission ตรวจสอบว่าสมาชิกโพสต์ข้อความได้หรือไม่ (ช่อง channel โพสต์ได้เฉพาะ owner/admin)
+	CheckPostPermission(conversationID, userID uuid.UUID) error
+
 	//ค้นหาการสนทนาแบบ direct ระหว่างผู้ใช้สองคน
 	FindDirectConversationBetweenUsers(userID, friendID uuid.UUID) (uuid.UUID, error)
 }
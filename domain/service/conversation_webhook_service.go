@@ -0,0 +1,23 @@
+// domain/service/conversation_webhook_service.go
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// ConversationWebhookService จัดการ incoming webhook สำหรับโพสต์ข้อความเข้าการสนทนาจากระบบภายนอก
+type ConversationWebhookService interface {
+	// CreateWebhook สร้าง webhook และคืน token ครั้งเดียว (ต้องมีสิทธิ์ PermissionManageWebhooks)
+	CreateWebhook(userID, conversationID uuid.UUID, req *dto.CreateConversationWebhookRequest) (*dto.ConversationWebhookCredentialsDTO, error)
+
+	// ListWebhooks ดึง webhook ของการสนทนา (ต้องมีสิทธิ์ PermissionManageWebhooks)
+	ListWebhooks(userID, conversationID uuid.UUID, includeRevoked bool) ([]*dto.ConversationWebhookDTO, error)
+
+	// RevokeWebhook เพิกถอน webhook (ต้องมีสิทธิ์ PermissionManageWebhooks)
+	RevokeWebhook(userID, conversationID, webhookID uuid.UUID) error
+
+	// Execute สร้างข้อความจาก payload ของ webhook ที่ระบุด้วย token
+	Execute(token string, payload *dto.IncomingWebhookPayload) (*models.Message, error)
+}
@@ -4,6 +4,7 @@ package service
 import (
 	"github.com/google/uuid"
 	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
 	"github.com/thizplus/gofiber-chat-api/domain/types"
 )
 
@@ -14,6 +15,9 @@ type ConversationService interface {
 	// CreateGroupConversation สร้างการสนทนาแบบกลุ่ม
 	CreateGroupConversation(userID uuid.UUID, title, iconURL string, memberIDs []uuid.UUID) (*dto.ConversationDTO, error)
 
+	// CreateChannelConversation สร้างช่องกระจายข่าว (ผู้สร้างเป็น owner, ผู้ติดตามเข้าร่วมผ่านลิงก์เชิญ)
+	CreateChannelConversation(userID uuid.UUID, title, iconURL string) (*dto.ConversationDTO, error)
+
 	// GetUserConversations ดึงรายการการสนทนาทั้งหมดของผู้ใช้
 	GetUserConversations(userID uuid.UUID, limit, offset int, convType string, pinned bool) ([]*dto.ConversationDTO, int, error)
 
@@ -26,9 +30,18 @@ type ConversationService interface {
 	// SetMuteStatus กำหนดสถานะการปิดเสียงของการสนทนา
 	SetMuteStatus(conversationID, userID uuid.UUID, isMuted bool) error
 
+	// GetNotificationSettings ดึงการตั้งค่าการแจ้งเตือนของผู้ใช้ในการสนทนา
+	GetNotificationSettings(conversationID, userID uuid.UUID) (*dto.ConversationNotificationSettingsDTO, error)
+
+	// UpdateNotificationSettings ตั้งค่าระดับการแจ้งเตือน (all/mentions/none) และการปิดเสียง (ถาวรหรือถึงเวลาที่กำหนด)
+	UpdateNotificationSettings(conversationID, userID uuid.UUID, req *dto.UpdateConversationNotificationSettingsRequest) (*dto.ConversationNotificationSettingsDTO, error)
+
 	// CheckMembership ตรวจสอบว่าผู้ใช้เป็นสมาชิกของการสนทนาหรือไม่
 	CheckMembership(userID, conversationID uuid.UUID) (bool, error)
 
+	// GetMemberUserIDs ดึง user ID ของสมาชิกทั้งหมดในการสนทนา
+	GetMemberUserIDs(conversationID uuid.UUID) ([]uuid.UUID, error)
+
 	// GetMessageContext ดึงข้อความเป้าหมายพร้อมข้อความก่อนหน้าและถัดไป
 	GetMessageContext(conversationID, userID uuid.UUID, targetID string,
 		beforeCount, afterCount int) ([]*dto.MessageDTO, bool, bool, error)
@@ -41,6 +54,12 @@ type ConversationService interface {
 	GetMessagesAfterID(conversationID, userID uuid.UUID, afterID string,
 		limit int) ([]*dto.MessageDTO, int64, error)
 
+	// GetThreadMessages ดึงข้อความต้นเธรดพร้อมข้อความในเธรด (ใช้ before/after เป็น cursor แบบเดียวกับข้อความในการสนทนา)
+	GetThreadMessages(threadRootID, userID uuid.UUID, beforeID, afterID string, limit int) (*dto.ThreadDTO, error)
+
+	// MarkThreadRead บันทึกว่าผู้ใช้อ่านเธรดถึงปัจจุบันแล้ว
+	MarkThreadRead(threadRootID, userID uuid.UUID) error
+
 	// GetConversationsBeforeTime ดึงการสนทนาที่เก่ากว่าเวลาที่ระบุ
 	GetConversationsBeforeTime(userID uuid.UUID, beforeTime string, limit int, convType string, pinned bool) ([]*dto.ConversationDTO, int, error)
 
@@ -70,4 +89,10 @@ type ConversationService interface {
 
 	// TransferOwnership โอนความเป็นเจ้าของกลุ่มให้สมาชิกคนอื่น
 	TransferOwnership(conversationID, currentOwnerID, newOwnerID uuid.UUID) error
+
+	// ConvertToConversationDTO แปลง Conversation model เป็น DTO ตามมุมมองของผู้ใช้
+	ConvertToConversationDTO(conversation *models.Conversation, userID uuid.UUID) (*dto.ConversationDTO, error)
+
+	// ConvertToMessageDTO แปลง Message model เป็น DTO ตามมุมมองของผู้ใช้
+	ConvertToMessageDTO(msg *models.Message, userID uuid.UUID) (*dto.MessageDTO, error)
 }
@@ -0,0 +1,27 @@
+// domain/service/e2ee_service.go
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// E2EEService จัดการ key bundle สำหรับการสนทนาแบบเข้ารหัส end-to-end
+// server เก็บและส่งต่อเฉพาะ public key ไม่สามารถถอดรหัสข้อความได้
+type E2EEService interface {
+	// UploadBundle บันทึก identity/signed pre-key ของอุปกรณ์ (แจ้ง e2ee.key_changed ถ้า identity key เปลี่ยน)
+	UploadBundle(userID uuid.UUID, deviceID string, req *dto.UploadKeyBundleRequest) (*models.E2EEKeyBundle, error)
+
+	// UploadPreKeys เติม one-time pre-key คืนจำนวนที่เหลือ
+	UploadPreKeys(userID uuid.UUID, deviceID string, keys []dto.PreKeyDTO) (int64, error)
+
+	// GetPreKeyCount นับ one-time pre-key ที่เหลือของอุปกรณ์
+	GetPreKeyCount(userID uuid.UUID, deviceID string) (int64, error)
+
+	// GetUserBundles ดึง key bundle ของทุกอุปกรณ์ของผู้ใช้ (ใช้ one-time pre-key อุปกรณ์ละหนึ่งอัน)
+	GetUserBundles(requesterID, targetUserID uuid.UUID) ([]*dto.KeyBundleDTO, error)
+
+	// RemoveDevice ลบ key ของอุปกรณ์ (แจ้ง e2ee.key_changed)
+	RemoveDevice(userID uuid.UUID, deviceID string) error
+}
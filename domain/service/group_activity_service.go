@@ -4,6 +4,7 @@ package service
 import (
 	"github.com/google/uuid"
 	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
 )
 
 // GroupActivityService interface สำหรับจัดการ group activities
@@ -15,9 +16,12 @@ type GroupActivityService interface {
 	LogGroupCreated(conversationID, creatorID uuid.UUID) error
 	LogGroupNameChanged(conversationID, actorID uuid.UUID, oldName, newName string) error
 	LogGroupIconChanged(conversationID, actorID uuid.UUID, oldIcon, newIcon string) error
-	LogMemberAdded(conversationID, actorID, targetID uuid.UUID) error
+	LogMemberAdded(conversationID, actorID, targetID uuid.UUID, source types.JSONB) error // source = ช่องทางที่เข้ากลุ่ม (nil = แอดมินเพิ่มเอง)
 	LogMemberRemoved(conversationID, actorID, targetID uuid.UUID) error
 	LogMemberRoleChanged(conversationID, actorID, targetID uuid.UUID, oldRole, newRole string) error
 	LogOwnershipTransferred(conversationID, oldOwnerID, newOwnerID uuid.UUID) error
 	LogMemberLeft(conversationID, userID uuid.UUID) error
+	LogMemberJoinRequested(conversationID, userID, requestID uuid.UUID) error
+	LogMemberJoinApproved(conversationID, actorID, targetID uuid.UUID, source types.JSONB) error
+	LogMessageTTLChanged(conversationID, actorID uuid.UUID, oldTTL, newTTL int) error
 }
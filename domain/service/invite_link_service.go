@@ -0,0 +1,25 @@
+// domain/service/invite_link_service.go
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+)
+
+// InviteLinkService จัดการลิงก์เชิญเข้ากลุ่ม
+type InviteLinkService interface {
+	// CreateLink สร้างลิงก์เชิญ (ต้องมีสิทธิ์ PermissionManageInviteLinks)
+	CreateLink(userID, conversationID uuid.UUID, req *dto.CreateInviteLinkRequest) (*dto.InviteLinkDTO, error)
+
+	// ListLinks ดึงลิงก์เชิญของกลุ่ม (ต้องมีสิทธิ์ PermissionManageInviteLinks)
+	ListLinks(userID, conversationID uuid.UUID, includeRevoked bool) ([]*dto.InviteLinkDTO, error)
+
+	// RevokeLink เพิกถอนลิงก์เชิญ (ต้องมีสิทธิ์ PermissionManageInviteLinks)
+	RevokeLink(userID, conversationID, linkID uuid.UUID) error
+
+	// PreviewLink ดึงข้อมูลกลุ่มจากรหัสลิงก์ สำหรับผู้ที่ยังไม่เป็นสมาชิก
+	PreviewLink(userID uuid.UUID, code string) (*dto.InviteLinkPreviewDTO, error)
+
+	// JoinViaLink เข้าร่วมกลุ่มผ่านลิงก์เชิญ (ลิงก์ที่ต้องอนุมัติจะสร้างคำขอเข้าร่วมพร้อม message แทน)
+	JoinViaLink(userID uuid.UUID, code, message string) (*dto.JoinViaInviteLinkResponse, error)
+}
@@ -0,0 +1,22 @@
+// domain/service/join_request_service.go
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+)
+
+// JoinRequestService จัดการคำขอเข้าร่วมกลุ่มที่ต้องให้แอดมินอนุมัติ
+type JoinRequestService interface {
+	// SubmitRequest ส่งคำขอเข้าร่วมกลุ่ม (inviteLinkID = ลิงก์เชิญที่ต้องอนุมัติ, nil = ขอโดยตรง)
+	SubmitRequest(userID, conversationID uuid.UUID, message string, inviteLinkID *uuid.UUID) (*dto.JoinRequestDTO, error)
+
+	// ListRequests ดึงคำขอของกลุ่ม (ต้องมีสิทธิ์ PermissionAddMember)
+	ListRequests(userID, conversationID uuid.UUID, status string, limit, offset int) ([]*dto.JoinRequestDTO, int64, error)
+
+	// ApproveRequest อนุมัติคำขอและเพิ่มผู้ขอเป็นสมาชิก
+	ApproveRequest(userID, conversationID, requestID uuid.UUID) (*dto.JoinRequestDTO, error)
+
+	// RejectRequest ปฏิเสธคำขอ
+	RejectRequest(userID, conversationID, requestID uuid.UUID) (*dto.JoinRequestDTO, error)
+}
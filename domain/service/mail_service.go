@@ -0,0 +1,16 @@
+// domain/service/mail_service.go
+package service
+
+import "context"
+
+// MailMessage อีเมลหนึ่งฉบับ (ข้อความล้วน)
+type MailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer กำหนด interface สำหรับ driver ที่ส่งอีเมล (SMTP, file/log สำหรับทดสอบบนเครื่อง)
+type Mailer interface {
+	Send(ctx context.Context, message *MailMessage) error
+}
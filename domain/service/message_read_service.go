@@ -3,6 +3,7 @@ package service
 
 import (
 	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
 	"github.com/thizplus/gofiber-chat-api/domain/models"
 )
 
@@ -14,6 +15,9 @@ type MessageReadService interface {
 	// GetMessageReads ดึงข้อมูลผู้ที่อ่านข้อความแล้ว
 	GetMessageReads(messageID, userID uuid.UUID) ([]*models.MessageRead, error)
 
+	// GetMessageViews ดึงยอดผู้ชมของข้อความในช่อง channel (nil ถ้าไม่ใช่ข้อความในช่อง)
+	GetMessageViews(messageID, userID uuid.UUID) (*dto.MessageViewsDTO, error)
+
 	// MarkAllMessagesAsRead ทำเครื่องหมายว่าข้อความทั้งหมดในการสนทนาถูกอ่านแล้ว
 	MarkAllMessagesAsRead(conversationID, userID uuid.UUID) (int, error)
 
@@ -2,7 +2,10 @@
 package service
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
 	"github.com/thizplus/gofiber-chat-api/domain/models"
 )
 
@@ -15,6 +18,10 @@ type MessageService interface {
 	SendFileMessage(conversationID uuid.UUID, userID uuid.UUID, mediaURL string, fileName string, fileSize int64, fileType string, metadata map[string]interface{}) (*models.Message, error)
 	SendBulkMessages(conversationID uuid.UUID, userID uuid.UUID, caption string, items []map[string]interface{}) (*models.Message, error)
 
+	// SendWebhookMessage ส่งข้อความ text จาก incoming webhook (sender_type = webhook, ไม่มี sender_id)
+	// conversation คือการสนทนาที่ webhook ผูกอยู่ซึ่งผู้เรียกโหลดและตรวจสอบแล้ว
+	SendWebhookMessage(conversation *models.Conversation, content string, metadata map[string]interface{}) (*models.Message, error)
+
 	// ส่งข้อความในนามธุรกิจ
 
 	// เพิ่มเมธอดใหม่สำหรับ Welcome Message โดยเฉพาะ
@@ -24,8 +31,29 @@ type MessageService interface {
 	// จัดการข้อความ
 	EditMessage(messageID uuid.UUID, userID uuid.UUID, newContent string, metadata map[string]interface{}) (*models.Message, error)
 	DeleteMessage(messageID uuid.UUID, userID uuid.UUID) error
+	ModerateDeleteMessage(messageID uuid.UUID, moderatorID uuid.UUID, reason string) error // ลบโดยผู้ดูแลแพลตฟอร์ม (moderation)
 	ReplyToMessage(replyToID uuid.UUID, userID uuid.UUID, messageType string, content string, mediaURL string, thumbnailURL string, metadata map[string]interface{}) (*models.Message, error)
 
+	// เธรด - ตอบกลับในเธรดโดยไม่อัปเดตข้อความล่าสุดของการสนทนา
+	ReplyInThread(messageID uuid.UUID, userID uuid.UUID, messageType string, content string, mediaURL string, thumbnailURL string, metadata map[string]interface{}) (*models.Message, error)
+
+	// ปฏิกิริยาต่อข้อความ (emoji reactions) - คืนค่าสรุปปฏิกิริยาล่าสุดของข้อความ
+	AddReaction(conversationID, messageID, userID uuid.UUID, emoji string) ([]dto.MessageReactionSummaryDTO, error)
+	RemoveReaction(conversationID, messageID, userID uuid.UUID, emoji string) ([]dto.MessageReactionSummaryDTO, error)
+	ToggleReaction(conversationID, messageID, userID uuid.UUID, emoji string) (bool, []dto.MessageReactionSummaryDTO, error)
+
+	// โพล - คืนค่าผลโหวตล่าสุดของโพลจากมุมมองของผู้ใช้
+	SendPollMessage(conversationID uuid.UUID, userID uuid.UUID, question string, options []string, allowMultiple bool, isAnonymous bool, closesAt *time.Time, metadata map[string]interface{}) (*models.Message, error)
+	VotePoll(conversationID, messageID, userID uuid.UUID, optionIDs []uuid.UUID) (*dto.PollDTO, error)
+	RetractPollVote(conversationID, messageID, userID uuid.UUID, optionIDs []uuid.UUID) (*dto.PollDTO, error)
+	ClosePoll(conversationID, messageID, userID uuid.UUID) (*dto.PollDTO, error)
+
+	// ข้อความเข้ารหัสแบบ end-to-end (เฉพาะการสนทนาแบบ direct) - server เก็บ ciphertext โดยไม่อ่านเนื้อหา
+	SendEncryptedMessage(conversationID uuid.UUID, userID uuid.UUID, senderDeviceID string, ciphertexts []dto.EncryptedPayloadDTO, metadata map[string]interface{}) (*models.Message, error)
+
+	// ข้อความที่หายไปเอง - ใช้โดย MessageExpiryScheduler
+	PurgeExpiredMessages(before time.Time, limit int) ([]*models.Message, error)
+
 	// ดูประวัติข้อความ
 	GetMessageEditHistory(messageID uuid.UUID, userID uuid.UUID) ([]*models.MessageEditHistory, error)
 	GetMessageDeleteHistory(messageID uuid.UUID, userID uuid.UUID) ([]*models.MessageDeleteHistory, error)
@@ -0,0 +1,24 @@
+// domain/service/moderation_service.go
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// ModerationService รายงานเนื้อหาไม่เหมาะสมและคิวตรวจสอบของผู้ดูแลแพลตฟอร์ม
+type ModerationService interface {
+	// Reports (ผู้ใช้ทั่วไป)
+	ReportMessage(reporterID, messageID uuid.UUID, req *dto.CreateReportRequest) (*dto.ReportDTO, error)
+	ReportUser(reporterID, userID uuid.UUID, req *dto.CreateReportRequest) (*dto.ReportDTO, error)
+	ReportConversation(reporterID, conversationID uuid.UUID, req *dto.CreateReportRequest) (*dto.ReportDTO, error)
+
+	// Queue (สิทธิ์ moderator ตรวจสอบที่ middleware)
+	ListReports(status, targetType, reason, source string, assigneeID *uuid.UUID, limit, offset int) ([]*models.ModerationReport, int64, error)
+	GetReport(reportID uuid.UUID) (*models.ModerationReport, error)
+	UpdateReportStatus(actorID, reportID uuid.UUID, status, note string) (*models.ModerationReport, error)
+
+	// TakeAction ดำเนินการ (ลบข้อความ/ระงับบัญชี/ปิดการสนทนา) และปิดรายงานอื่นของเป้าหมายเดียวกัน
+	TakeAction(actorID uuid.UUID, actorRole string, reportID uuid.UUID, req *dto.ModerationActionRequest) (*models.ModerationReport, error)
+}
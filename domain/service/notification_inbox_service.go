@@ -0,0 +1,20 @@
+// domain/service/notification_inbox_service.go
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+)
+
+// NotificationInboxService กล่องแจ้งเตือนของผู้ใช้ (รายการถูกบันทึกโดย NotificationService)
+type NotificationInboxService interface {
+	// ListNotifications ดึงการแจ้งเตือนล่าสุดก่อน แบบ cursor
+	// Returns: notifications, nextCursor, hasMore, error
+	ListNotifications(userID uuid.UUID, limit int, cursor *string, unreadOnly bool) ([]*dto.NotificationDTO, *string, bool, error)
+	GetUnreadCount(userID uuid.UUID) (int64, error)
+
+	// MarkRead/MarkAllRead คืนจำนวนที่ยังไม่ได้อ่านหลังอัปเดต (สำหรับ badge)
+	MarkRead(userID uuid.UUID, ids []uuid.UUID) (int64, error)
+	MarkAllRead(userID uuid.UUID) (int64, error)
+	DeleteNotification(userID, notificationID uuid.UUID) error
+}
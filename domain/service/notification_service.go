@@ -19,6 +19,11 @@ type NotificationService interface {
 	NotifyMessageReply(conversationID uuid.UUID, message interface{})
 	NotifyMessageDeleted(conversationID uuid.UUID, messageID uuid.UUID)
 	NotifyMessageReaction(conversationID uuid.UUID, reaction interface{})
+	NotifyThreadReply(conversationID, threadRootID uuid.UUID, message interface{}) // ข้อความใหม่ในเธรด (ไม่ใช่ message.receive)
+	NotifyPollUpdated(conversationID uuid.UUID, poll interface{})                  // ผลโหวต/สถานะโพลเปลี่ยน (poll.updated)
+
+	// E2EE notifications
+	NotifyKeyChanged(userIDs []uuid.UUID, change interface{}) // key ของอุปกรณ์เปลี่ยน (e2ee.key_changed)
 
 	// Conversation notifications
 	NotifyConversationCreated(userIDs []uuid.UUID, conversation interface{}) error
@@ -29,6 +34,11 @@ type NotificationService interface {
 	NotifyUserRemovedFromConversation(userID, conversationID uuid.UUID)
 	NotifyNewConversation(conversation interface{}) error
 
+	// Join request notifications
+	NotifyJoinRequestReceived(adminIDs []uuid.UUID, request interface{}) // join_request.received ถึงแอดมินของกลุ่ม
+	NotifyJoinRequestApproved(userID uuid.UUID, request interface{})     // join_request.approved ถึงผู้ขอ
+	NotifyJoinRequestRejected(userID uuid.UUID, request interface{})     // join_request.rejected ถึงผู้ขอ
+
 	// Member role notifications
 	NotifyMemberRoleChanged(conversationID, userID uuid.UUID, oldRole, newRole string, changedByUserID uuid.UUID)
 	NotifyOwnershipTransferred(conversationID, previousOwnerID, newOwnerID uuid.UUID)
@@ -0,0 +1,27 @@
+// domain/service/oauth_service.go
+package service
+
+import "context"
+
+// OAuthIdentity ข้อมูลผู้ใช้จาก ID token ของ provider (ผ่านการตรวจลายเซ็น issuer และ audience แล้ว)
+type OAuthIdentity struct {
+	Subject       string // claim "sub" ไม่ซ้ำภายใน provider
+	Email         string
+	EmailVerified bool
+	Name          string
+	Picture       string
+	Nonce         string // ต้องตรงกับ nonce ที่ส่งไปตอนเริ่ม flow
+}
+
+// OAuthProvider กำหนด interface สำหรับ driver ของ OAuth2/OIDC (Google, Microsoft, generic OIDC)
+// ใช้ authorization code flow พร้อม PKCE (S256) เสมอ
+type OAuthProvider interface {
+	// Name คืนค่าชื่อ provider ที่ใช้ใน URL และตาราง user_identities
+	Name() string
+
+	// AuthCodeURL สร้าง URL หน้าเข้าสู่ระบบของ provider
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+
+	// Exchange แลก authorization code เป็น token แล้วตรวจสอบ ID token
+	Exchange(ctx context.Context, code, codeVerifier string) (*OAuthIdentity, error)
+}
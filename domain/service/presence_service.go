@@ -20,9 +20,13 @@ type PresenceService interface {
 	// SetUserOnline marks a user as online
 	SetUserOnline(userID uuid.UUID) error
 
-	// SetUserOffline marks a user as offline
+	// SetUserOffline marks a user as offline on this node
+	// (the user stays online while another node still holds a connection)
 	SetUserOffline(userID uuid.UUID) error
 
+	// RefreshOnline extends this node's online status for connected users (heartbeat)
+	RefreshOnline(userIDs []uuid.UUID) error
+
 	// UpdateLastActive updates user's last active timestamp
 	UpdateLastActive(userID uuid.UUID) error
 
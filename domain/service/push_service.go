@@ -0,0 +1,61 @@
+// domain/service/push_service.go
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// ErrPushTokenInvalid ถูกคืนค่าจาก PushProvider เมื่อ token ถูกยกเลิกหรือใช้ไม่ได้แล้ว
+// (gateway จะปิดการใช้งาน token และไม่ retry)
+var ErrPushTokenInvalid = errors.New("push token is no longer valid")
+
+// PushMessage เนื้อหาของ push notification ที่ส่งไปยัง provider
+type PushMessage struct {
+	Title       string            `json:"title"`
+	Body        string            `json:"body"`
+	Data        map[string]string `json:"data,omitempty"`
+	Badge       *int              `json:"badge,omitempty"`
+	Sound       string            `json:"sound,omitempty"`
+	CollapseKey string            `json:"collapse_key,omitempty"` // รวม notification ของการสนทนาเดียวกัน
+	TTL         int               `json:"ttl,omitempty"`          // วินาที (0 = ค่า default ของ provider)
+}
+
+// PushProvider กำหนด interface สำหรับ driver ที่ส่ง push (FCM, APNs, WebPush, fake)
+type PushProvider interface {
+	// Name คืนค่าชื่อ provider ให้ตรงกับ DeviceToken.Provider
+	Name() string
+
+	// Send ส่ง push ไปยังอุปกรณ์เดียว คืนค่า ErrPushTokenInvalid ถ้า token ใช้ไม่ได้แล้ว
+	Send(ctx context.Context, device *models.DeviceToken, message *PushMessage) error
+}
+
+// WebPushKeyProvider ถูก implement โดย WebPush driver เพื่อให้ client ใช้ subscribe
+type WebPushKeyProvider interface {
+	VAPIDPublicKey() string
+}
+
+// PushTokenValidator ถูก implement โดย driver ที่ต้องตรวจ token ตอนลงทะเบียน (เช่น endpoint ของ WebPush ต้องไม่ชี้ไปยัง address ภายใน)
+type PushTokenValidator interface {
+	ValidateToken(token string) error
+}
+
+// PushService กำหนด interface สำหรับ push notification gateway
+type PushService interface {
+	// Device registration
+	RegisterDevice(userID uuid.UUID, device *models.DeviceToken) (*models.DeviceToken, error)
+	UnregisterDevice(userID, deviceID uuid.UUID) error
+	ListDevices(userID uuid.UUID) ([]*models.DeviceToken, error)
+
+	// Delivery
+	NotifyNewMessage(message *models.Message)
+	RetryDueDeliveries(limit int) (int, error)
+
+	// Diagnostics
+	GetDeliveries(userID uuid.UUID, status string, limit, offset int) ([]*models.PushDelivery, int64, error)
+	SupportedProviders() []string
+	VAPIDPublicKey() string
+}
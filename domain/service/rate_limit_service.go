@@ -0,0 +1,42 @@
+// domain/service/rate_limit_service.go
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitResult ผลการตรวจ rate limit ของ request หนึ่งครั้ง
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAt    time.Time     // เวลาที่โควต้าจะคืนอย่างน้อย 1 ครั้ง
+	RetryAfter time.Duration // ใช้เมื่อ Allowed = false
+}
+
+// RateLimiter จำกัดจำนวน request ต่อ key ในช่วงเวลา (sliding window ใช้ร่วมกันทุก API instance)
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error)
+}
+
+// LoginAttemptGuard ติดตามการเข้าสู่ระบบที่ล้มเหลวและล็อกแบบเพิ่มระยะเวลาขึ้นเรื่อยๆ
+type LoginAttemptGuard interface {
+	// LockedFor คืนค่าเวลาที่เหลือของการล็อก (0 = ไม่ถูกล็อก)
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+
+	// RecordFailure บันทึกความล้มเหลว คืนค่าระยะเวลาล็อกถ้าครั้งนี้ทำให้ถูกล็อก (0 = ยังไม่ล็อก)
+	RecordFailure(ctx context.Context, key string) (time.Duration, error)
+
+	// Reset ล้างตัวนับหลังเข้าสู่ระบบสำเร็จ
+	Reset(ctx context.Context, key string) error
+}
+
+// LoginLockedError ถูกคืนค่าจาก AuthService.Login เมื่อบัญชีถูกล็อกชั่วคราวจากการเข้าสู่ระบบผิดซ้ำ
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return "too many failed login attempts, please try again later"
+}
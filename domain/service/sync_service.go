@@ -0,0 +1,18 @@
+// domain/service/sync_service.go
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+)
+
+// SyncService ให้ client แบบ offline-first ดึงเฉพาะข้อมูลที่เปลี่ยนไปตั้งแต่ sync ครั้งก่อน
+type SyncService interface {
+	// Sync คืนข้อมูลที่เปลี่ยนแปลงหลัง token (token ว่าง = เริ่มต้น ได้ token ของเวลาปัจจุบัน)
+	Sync(userID uuid.UUID, token string, limit int) (*dto.SyncResponse, error)
+
+	// PurgeTombstones ลบ tombstone ที่เก่ากว่า retention (token ที่เก่ากว่านั้นต้อง resync ทั้งหมด)
+	PurgeTombstones(now time.Time) (int64, error)
+}
@@ -0,0 +1,28 @@
+// domain/service/token_signer.go
+package service
+
+// JSONWebKey public key ในรูปแบบ JWK (RFC 7517) สำหรับเผยแพร่ที่ /.well-known/jwks.json
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // EC / OKP curve
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// TokenSigner เซ็นและตรวจสอบ JWT ของระบบ (access, refresh และ 2FA challenge token)
+// รองรับหลาย key พร้อมกันเพื่อหมุน key โดยไม่ทำให้ token ที่ออกไปแล้วใช้ไม่ได้
+type TokenSigner interface {
+	// Sign เซ็น claims ด้วย key ที่ใช้งานอยู่ (ใส่ kid ใน header)
+	Sign(claims map[string]interface{}) (string, error)
+
+	// Verify ตรวจลายเซ็นด้วย key ตาม kid และวันหมดอายุ แล้วคืนค่า claims
+	Verify(token string) (map[string]interface{}, error)
+
+	// PublicKeys public key ทั้งหมดที่ใช้ตรวจ token ได้ (ว่างเมื่อใช้ HS256)
+	PublicKeys() []JSONWebKey
+}
@@ -3,6 +3,7 @@ package service
 
 import (
 	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
 	"github.com/thizplus/gofiber-chat-api/domain/models"
 	"github.com/thizplus/gofiber-chat-api/domain/types"
 )
@@ -17,4 +18,8 @@ type UserService interface {
 	GetUserStatuses(userIDs []uuid.UUID) ([]types.JSONB, error) // เปลี่ยนจาก types.JSONB เป็น []types.JSONB
 	UploadProfileImage(userID uuid.UUID, imageURL string) error
 	SearchUsersExact(query string, limit, offset int) ([]*models.User, int64, error)
+
+	// การตั้งค่าการแจ้งเตือนระดับผู้ใช้ (quiet hours ตามเขตเวลาของผู้ใช้ และ keyword alerts)
+	GetNotificationPreferences(userID uuid.UUID) (*dto.NotificationPreferencesDTO, error)
+	UpdateNotificationPreferences(userID uuid.UUID, req *dto.UpdateNotificationPreferencesRequest) (*dto.NotificationPreferencesDTO, error)
 }
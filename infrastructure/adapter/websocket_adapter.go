@@ -39,6 +39,21 @@ func (a *WebSocketAdapter) BroadcastToUsers(userIDs []uuid.UUID, messageType str
 	return nil // คืนค่า nil เสมอ หรืออาจมีการตรวจสอบความผิดพลาดแล้วคืนค่า error
 }
 
+// DisconnectSessions ตัดการเชื่อมต่อ WebSocket ของ session ที่ถูกเพิกถอน
+func (a *WebSocketAdapter) DisconnectSessions(userID uuid.UUID, sessionIDs []uuid.UUID) {
+	a.hub.DisconnectSessions(userID, sessionIDs)
+}
+
+// GetStats ดึงสถิติของ WebSocket Hub
+func (a *WebSocketAdapter) GetStats() map[string]interface{} {
+	return a.hub.GetStats()
+}
+
+// GetAllConnections ดึงรายละเอียดการเชื่อมต่อทั้งหมดของ WebSocket Hub
+func (a *WebSocketAdapter) GetAllConnections() map[string]interface{} {
+	return a.hub.GetAllConnections()
+}
+
 // BroadcastToBusiness ส่งข้อความไปยังธุรกิจหนึ่ง
 func (a *WebSocketAdapter) BroadcastToBusiness(businessID uuid.UUID, messageType string, data interface{}) {
 	a.hub.BroadcastToBusiness(businessID, websocket.MessageType(messageType), data)
@@ -101,6 +116,21 @@ func (a *WebSocketAdapter) BroadcastMessageReaction(conversationID uuid.UUID, re
 	a.BroadcastToConversation(conversationID, "message.reaction", reaction)
 }
 
+// BroadcastThreadReply ส่งการแจ้งเตือนว่ามีข้อความใหม่ในเธรด
+func (a *WebSocketAdapter) BroadcastThreadReply(conversationID uuid.UUID, data interface{}) {
+	a.BroadcastToConversation(conversationID, "thread.reply", data)
+}
+
+// BroadcastPollUpdated ส่งการแจ้งเตือนว่าผลโหวตหรือสถานะของโพลเปลี่ยนแปลง
+func (a *WebSocketAdapter) BroadcastPollUpdated(conversationID uuid.UUID, data interface{}) {
+	a.BroadcastToConversation(conversationID, "poll.updated", data)
+}
+
+// BroadcastKeyChanged ส่งการแจ้งเตือนว่า key ของอุปกรณ์เปลี่ยน (client ต้องสร้าง session ใหม่และตรวจสอบ safety number)
+func (a *WebSocketAdapter) BroadcastKeyChanged(userIDs []uuid.UUID, data interface{}) {
+	a.BroadcastToUsers(userIDs, "e2ee.key_changed", data)
+}
+
 // BroadcastConversationCreated ส่งการแจ้งเตือนว่ามีการสร้างบทสนทนาใหม่
 func (a *WebSocketAdapter) BroadcastConversationCreated(userIDs []uuid.UUID, conversation interface{}) error {
 	return a.BroadcastToUsers(userIDs, "conversation.create", conversation)
@@ -159,6 +189,21 @@ func (a *WebSocketAdapter) BroadcastNewConversation(userID uuid.UUID, conversati
 	return nil
 }
 
+// BroadcastJoinRequestReceived ส่งคำขอเข้าร่วมกลุ่มใหม่ไปยังแอดมินของกลุ่ม
+func (a *WebSocketAdapter) BroadcastJoinRequestReceived(adminIDs []uuid.UUID, request interface{}) {
+	a.BroadcastToUsers(adminIDs, "join_request.received", request)
+}
+
+// BroadcastJoinRequestApproved แจ้งผู้ขอว่าคำขอเข้าร่วมกลุ่มได้รับการอนุมัติ
+func (a *WebSocketAdapter) BroadcastJoinRequestApproved(userID uuid.UUID, request interface{}) {
+	a.BroadcastToUser(userID, "join_request.approved", request)
+}
+
+// BroadcastJoinRequestRejected แจ้งผู้ขอว่าคำขอเข้าร่วมกลุ่มถูกปฏิเสธ
+func (a *WebSocketAdapter) BroadcastJoinRequestRejected(userID uuid.UUID, request interface{}) {
+	a.BroadcastToUser(userID, "join_request.rejected", request)
+}
+
 // BroadcastBusinessBroadcast ส่งการแจ้งเตือนประกาศจากธุรกิจ
 func (a *WebSocketAdapter) BroadcastBusinessBroadcast(userIDs []uuid.UUID, broadcast interface{}) {
 	a.BroadcastToUsers(userIDs, "business.broadcast", broadcast)
@@ -309,6 +354,16 @@ func (a *WebSocketAdapter) BroadcastAlert(userID uuid.UUID, alert interface{}) {
 	a.BroadcastToUser(userID, "alert", alert)
 }
 
+// BroadcastNotificationNew ส่งรายการใหม่ในกล่องแจ้งเตือนไปยังผู้รับ
+func (a *WebSocketAdapter) BroadcastNotificationNew(userID uuid.UUID, data interface{}) {
+	a.BroadcastToUser(userID, "notification.new", data)
+}
+
+// BroadcastNotificationRead แจ้งทุกอุปกรณ์ของผู้ใช้ว่ามีการอ่านการแจ้งเตือน
+func (a *WebSocketAdapter) BroadcastNotificationRead(userID uuid.UUID, data interface{}) {
+	a.BroadcastToUser(userID, "notification.read", data)
+}
+
 // BroadcastSystemMessage ส่งข้อความจากระบบ
 func (a *WebSocketAdapter) BroadcastSystemMessage(userIDs []uuid.UUID, message interface{}) {
 	a.BroadcastToUsers(userIDs, "system.message", message)
@@ -0,0 +1,136 @@
+// infrastructure/contentfilter/duplicate_flood_filter.go
+package contentfilter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+const (
+	floodKeyPrefix   = "content_filter:flood:"
+	maxFloodRepeats  = 100
+	minFloodWindow   = 5
+	maxFloodWindow   = 3600
+	defaultRepeats   = 3
+	defaultWindowSec = 60
+)
+
+// DuplicateFloodConfig การตั้งค่าเริ่มต้นของการตรวจข้อความซ้ำ
+type DuplicateFloodConfig struct {
+	MaxRepeats int           // จำนวนครั้งที่ส่งข้อความเดิมได้ในช่วงเวลา (default: 3)
+	Window     time.Duration // ช่วงเวลาที่นับ (default: 60 วินาที)
+	Action     string        // reject หรือ flag (default: reject)
+}
+
+// DuplicateFloodFilter ตรวจการส่งข้อความเดิมซ้ำๆ ในการสนทนาเดียวกัน
+// นับใน Redis เพื่อให้ทุก API instance เห็นตัวนับเดียวกัน
+// การตั้งค่าของกลุ่ม: {"enabled": bool, "action": "reject|flag", "max_repeats": 3, "window_seconds": 60}
+type DuplicateFloodFilter struct {
+	redis  *redis.Client
+	config DuplicateFloodConfig
+}
+
+// NewDuplicateFloodFilter สร้าง duplicate flood filter ใหม่
+func NewDuplicateFloodFilter(redisClient *redis.Client, config *DuplicateFloodConfig) *DuplicateFloodFilter {
+	cfg := DuplicateFloodConfig{}
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.MaxRepeats <= 0 {
+		cfg.MaxRepeats = defaultRepeats
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = defaultWindowSec * time.Second
+	}
+	if cfg.Action != service.ContentFilterFlag {
+		cfg.Action = service.ContentFilterReject
+	}
+
+	return &DuplicateFloodFilter{redis: redisClient, config: cfg}
+}
+
+// Name คืนค่าชื่อ filter
+func (f *DuplicateFloodFilter) Name() string {
+	return DuplicateFloodFilterName
+}
+
+// Check นับจำนวนครั้งที่ผู้ส่งส่งเนื้อหาเดิมในช่วงเวลา (ไม่ตรวจการแก้ไขข้อความ)
+func (f *DuplicateFloodFilter) Check(ctx context.Context, input *service.ContentFilterInput, settings types.JSONB) (*service.ContentFilterVerdict, error) {
+	allowed := &service.ContentFilterVerdict{Action: service.ContentFilterAllow}
+	normalized := strings.Join(strings.Fields(strings.ToLower(input.Content)), " ")
+	if input.IsEdit || normalized == "" {
+		return allowed, nil
+	}
+
+	maxRepeats := f.config.MaxRepeats
+	if value, ok, err := intSetting(settings, "max_repeats"); err != nil {
+		return nil, err
+	} else if ok {
+		maxRepeats = value
+	}
+
+	window := f.config.Window
+	if value, ok, err := intSetting(settings, "window_seconds"); err != nil {
+		return nil, err
+	} else if ok {
+		window = time.Duration(value) * time.Second
+	}
+
+	hash := sha256.Sum256([]byte(normalized))
+	key := fmt.Sprintf("%s%s:%s:%s", floodKeyPrefix, input.ConversationID, input.SenderID, hex.EncodeToString(hash[:16]))
+
+	count, err := f.redis.Incr(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count duplicate messages: %w", err)
+	}
+	if count == 1 {
+		if err := f.redis.Expire(ctx, key, window).Err(); err != nil {
+			return nil, fmt.Errorf("failed to set duplicate window: %w", err)
+		}
+	}
+
+	if count <= int64(maxRepeats) {
+		return allowed, nil
+	}
+
+	return &service.ContentFilterVerdict{
+		Action: actionSetting(settings, f.config.Action),
+		Reason: fmt.Sprintf("same message sent more than %d times within %d seconds", maxRepeats, int(window.Seconds())),
+	}, nil
+}
+
+// NormalizeSettings ตรวจสอบการตั้งค่าของกลุ่ม
+func (f *DuplicateFloodFilter) NormalizeSettings(settings types.JSONB) (types.JSONB, error) {
+	normalized, err := baseSettings(f.Name(), settings, service.ContentFilterReject, service.ContentFilterFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	if value, ok, err := intSetting(settings, "max_repeats"); err != nil {
+		return nil, err
+	} else if ok {
+		if value < 1 || value > maxFloodRepeats {
+			return nil, fmt.Errorf("max_repeats must be between 1 and %d", maxFloodRepeats)
+		}
+		normalized["max_repeats"] = value
+	}
+
+	if value, ok, err := intSetting(settings, "window_seconds"); err != nil {
+		return nil, err
+	} else if ok {
+		if value < minFloodWindow || value > maxFloodWindow {
+			return nil, fmt.Errorf("window_seconds must be between %d and %d", minFloodWindow, maxFloodWindow)
+		}
+		normalized["window_seconds"] = value
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,182 @@
+// infrastructure/contentfilter/link_domain_filter.go
+package contentfilter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+const (
+	maxGroupDomains  = 100
+	linkMaskReplaced = "[link removed]"
+)
+
+// LinkDomainConfig การตั้งค่าโดเมนต้องห้ามของระบบ
+type LinkDomainConfig struct {
+	DenyDomains []string // ใช้กับทุกการสนทนา (กลุ่มอนุญาตโดเมนเหล่านี้เองไม่ได้)
+	Action      string   // mask, reject หรือ flag (default: reject)
+}
+
+// LinkDomainFilter ตรวจโดเมนของลิงก์ในข้อความ
+// การตั้งค่าของกลุ่ม: {"enabled": bool, "action": "mask|reject|flag", "allow_domains": [...], "deny_domains": [...]}
+// allow_domains ไม่ว่าง = อนุญาตเฉพาะโดเมนในรายการ (รวม subdomain)
+type LinkDomainFilter struct {
+	denyDomains   []string
+	defaultAction string
+}
+
+// NewLinkDomainFilter สร้าง link domain filter ใหม่
+func NewLinkDomainFilter(config *LinkDomainConfig) *LinkDomainFilter {
+	if config == nil {
+		config = &LinkDomainConfig{}
+	}
+
+	action := config.Action
+	if !containsString([]string{service.ContentFilterMask, service.ContentFilterReject, service.ContentFilterFlag}, action) {
+		action = service.ContentFilterReject
+	}
+
+	domains, _ := normalizeDomains(config.DenyDomains)
+	return &LinkDomainFilter{
+		denyDomains:   domains,
+		defaultAction: action,
+	}
+}
+
+// Name คืนค่าชื่อ filter
+func (f *LinkDomainFilter) Name() string {
+	return LinkDomainFilterName
+}
+
+// Check ตรวจลิงก์ที่ MessageService แยกไว้แล้ว (input.Links)
+func (f *LinkDomainFilter) Check(ctx context.Context, input *service.ContentFilterInput, settings types.JSONB) (*service.ContentFilterVerdict, error) {
+	allowed := &service.ContentFilterVerdict{Action: service.ContentFilterAllow}
+	if len(input.Links) == 0 {
+		return allowed, nil
+	}
+
+	allowList, err := stringListSetting(settings, "allow_domains")
+	if err != nil {
+		return nil, err
+	}
+	denyList, err := stringListSetting(settings, "deny_domains")
+	if err != nil {
+		return nil, err
+	}
+	allowDomains, _ := normalizeDomains(allowList)
+	denyDomains, _ := normalizeDomains(denyList)
+	denyDomains = append(append([]string{}, f.denyDomains...), denyDomains...)
+
+	blocked := []string{}
+	blockedHosts := []string{}
+	for _, link := range input.Links {
+		host := linkHost(link)
+		if host == "" {
+			continue
+		}
+		if matchDomain(host, denyDomains) || (len(allowDomains) > 0 && !matchDomain(host, allowDomains)) {
+			blocked = append(blocked, link)
+			blockedHosts = append(blockedHosts, host)
+		}
+	}
+	if len(blocked) == 0 {
+		return allowed, nil
+	}
+
+	action := actionSetting(settings, f.defaultAction)
+	verdict := &service.ContentFilterVerdict{
+		Action: action,
+		Reason: "links to blocked domains: " + strings.Join(blockedHosts, ", "),
+	}
+	if action == service.ContentFilterMask {
+		content := input.Content
+		for _, link := range blocked {
+			content = strings.ReplaceAll(content, link, linkMaskReplaced)
+		}
+		verdict.Content = content
+	}
+
+	return verdict, nil
+}
+
+// NormalizeSettings ตรวจสอบการตั้งค่าของกลุ่ม
+func (f *LinkDomainFilter) NormalizeSettings(settings types.JSONB) (types.JSONB, error) {
+	normalized, err := baseSettings(f.Name(), settings, service.ContentFilterMask, service.ContentFilterReject, service.ContentFilterFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range []string{"allow_domains", "deny_domains"} {
+		list, err := stringListSetting(settings, key)
+		if err != nil {
+			return nil, err
+		}
+		domains, err := normalizeDomains(list)
+		if err != nil {
+			return nil, err
+		}
+		if len(domains) > maxGroupDomains {
+			return nil, fmt.Errorf("%s supports at most %d domains", key, maxGroupDomains)
+		}
+		if len(domains) > 0 {
+			normalized[key] = domains
+		}
+	}
+
+	return normalized, nil
+}
+
+// normalizeDomains แปลงเป็นตัวพิมพ์เล็ก ตัด scheme/"*." และตัดค่าซ้ำ
+// โดเมนที่ไม่ถูกต้องจะถูกข้าม และคืนค่า error ของรายการแรกที่ไม่ถูกต้อง
+func normalizeDomains(domains []string) ([]string, error) {
+	var firstErr error
+	seen := make(map[string]bool)
+	result := []string{}
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if strings.Contains(domain, "://") {
+			domain = linkHost(domain)
+		}
+		domain = strings.TrimPrefix(domain, "*.")
+		domain = strings.Trim(domain, ".")
+		if domain == "" {
+			continue
+		}
+		if strings.ContainsAny(domain, " /:@?#") || !strings.Contains(domain, ".") {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("invalid domain: %s", domain)
+			}
+			continue
+		}
+		if seen[domain] {
+			continue
+		}
+		seen[domain] = true
+		result = append(result, domain)
+	}
+	return result, firstErr
+}
+
+// linkHost ดึง hostname (ตัวพิมพ์เล็ก) จากลิงก์
+func linkHost(link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+}
+
+// matchDomain ตรวจว่า host ตรงกับโดเมนหรือเป็น subdomain ของโดเมนในรายการ
+func matchDomain(host string, domains []string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
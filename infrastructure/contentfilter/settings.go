@@ -0,0 +1,102 @@
+// infrastructure/contentfilter/settings.go
+package contentfilter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+// ชื่อ filter ที่มาพร้อมระบบ (ใช้เป็น key ใน Conversation.Metadata["content_filters"])
+const (
+	WordListFilterName       = "word_list"
+	LinkDomainFilterName     = "link_domains"
+	DuplicateFloodFilterName = "duplicate_flood"
+)
+
+// baseSettings ตรวจสอบ action และ enabled ที่ทุก filter ใช้ร่วมกัน
+func baseSettings(name string, settings types.JSONB, actions ...string) (types.JSONB, error) {
+	normalized := types.JSONB{}
+
+	if value, ok := settings["enabled"]; ok && value != nil {
+		enabled, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s enabled must be a boolean", name)
+		}
+		normalized["enabled"] = enabled
+	}
+
+	if value, ok := settings["action"]; ok && value != nil {
+		action, ok := value.(string)
+		if !ok || !containsString(actions, action) {
+			return nil, fmt.Errorf("invalid %s action, must be one of: %s", name, strings.Join(actions, ", "))
+		}
+		normalized["action"] = action
+	}
+
+	return normalized, nil
+}
+
+// actionSetting อ่าน action จากการตั้งค่าของกลุ่ม (ไม่มี = ค่าเริ่มต้น)
+func actionSetting(settings types.JSONB, defaultAction string) string {
+	if action, ok := settings["action"].(string); ok && action != "" {
+		return action
+	}
+	return defaultAction
+}
+
+// stringListSetting อ่านรายการข้อความ (JSONB เก็บ array เป็น []interface{})
+func stringListSetting(settings types.JSONB, key string) ([]string, error) {
+	value, ok := settings[key]
+	if !ok || value == nil {
+		return nil, nil
+	}
+
+	switch list := value.(type) {
+	case []string:
+		return list, nil
+	case []interface{}:
+		result := make([]string, 0, len(list))
+		for _, item := range list {
+			text, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s must be a list of strings", key)
+			}
+			result = append(result, text)
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("%s must be a list of strings", key)
+	}
+}
+
+// intSetting อ่านตัวเลขจำนวนเต็ม (JSON decode ตัวเลขเป็น float64)
+func intSetting(settings types.JSONB, key string) (int, bool, error) {
+	value, ok := settings[key]
+	if !ok || value == nil {
+		return 0, false, nil
+	}
+
+	switch number := value.(type) {
+	case float64:
+		if number != float64(int(number)) {
+			return 0, false, fmt.Errorf("%s must be an integer", key)
+		}
+		return int(number), true, nil
+	case int:
+		return number, true, nil
+	default:
+		return 0, false, fmt.Errorf("%s must be an integer", key)
+	}
+}
+
+// containsString ตรวจสอบว่ามีค่าอยู่ในรายการหรือไม่
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
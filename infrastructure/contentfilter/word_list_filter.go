@@ -0,0 +1,150 @@
+// infrastructure/contentfilter/word_list_filter.go
+package contentfilter
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+)
+
+const (
+	maxGroupWords  = 200
+	maxWordLength  = 50
+	wordMaskSymbol = "*"
+)
+
+// asciiWordRegex คำภาษาอังกฤษ/ตัวเลขล้วนจะจับทั้งคำ (ภาษาไทยไม่มีช่องว่างคั่นคำจึงจับแบบ substring)
+var asciiWordRegex = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+
+// WordListConfig การตั้งค่าคำต้องห้ามของระบบ
+type WordListConfig struct {
+	Words  []string // ใช้กับทุกการสนทนา (กลุ่มเพิ่มคำของตัวเองได้)
+	Action string   // mask, reject หรือ flag (default: mask)
+}
+
+// WordListFilter ตรวจคำหยาบ/คำต้องห้าม
+// การตั้งค่าของกลุ่ม: {"enabled": bool, "action": "mask|reject|flag", "words": ["..."]}
+type WordListFilter struct {
+	words         []string
+	defaultAction string
+}
+
+// NewWordListFilter สร้าง word list filter ใหม่
+func NewWordListFilter(config *WordListConfig) *WordListFilter {
+	if config == nil {
+		config = &WordListConfig{}
+	}
+
+	action := config.Action
+	if !containsString([]string{service.ContentFilterMask, service.ContentFilterReject, service.ContentFilterFlag}, action) {
+		action = service.ContentFilterMask
+	}
+
+	return &WordListFilter{
+		words:         normalizeWords(config.Words),
+		defaultAction: action,
+	}
+}
+
+// Name คืนค่าชื่อ filter
+func (f *WordListFilter) Name() string {
+	return WordListFilterName
+}
+
+// Check ตรวจหาคำต้องห้ามของระบบและของกลุ่ม
+func (f *WordListFilter) Check(ctx context.Context, input *service.ContentFilterInput, settings types.JSONB) (*service.ContentFilterVerdict, error) {
+	groupWords, err := stringListSetting(settings, "words")
+	if err != nil {
+		return nil, err
+	}
+
+	pattern := buildWordPattern(append(append([]string{}, f.words...), normalizeWords(groupWords)...))
+	if pattern == nil || !pattern.MatchString(input.Content) {
+		return &service.ContentFilterVerdict{Action: service.ContentFilterAllow}, nil
+	}
+
+	action := actionSetting(settings, f.defaultAction)
+	verdict := &service.ContentFilterVerdict{
+		Action: action,
+		Reason: "message contains blocked words",
+	}
+	if action == service.ContentFilterMask {
+		verdict.Content = pattern.ReplaceAllStringFunc(input.Content, func(match string) string {
+			return strings.Repeat(wordMaskSymbol, utf8.RuneCountInString(match))
+		})
+	}
+
+	return verdict, nil
+}
+
+// NormalizeSettings ตรวจสอบการตั้งค่าของกลุ่ม
+func (f *WordListFilter) NormalizeSettings(settings types.JSONB) (types.JSONB, error) {
+	normalized, err := baseSettings(f.Name(), settings, service.ContentFilterMask, service.ContentFilterReject, service.ContentFilterFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	words, err := stringListSetting(settings, "words")
+	if err != nil {
+		return nil, err
+	}
+	words = normalizeWords(words)
+	if len(words) > maxGroupWords {
+		return nil, fmt.Errorf("word_list supports at most %d words", maxGroupWords)
+	}
+	for _, word := range words {
+		if utf8.RuneCountInString(word) > maxWordLength {
+			return nil, fmt.Errorf("word_list words must be at most %d characters", maxWordLength)
+		}
+	}
+	if len(words) > 0 {
+		normalized["words"] = words
+	}
+
+	return normalized, nil
+}
+
+// normalizeWords ตัดช่องว่าง แปลงเป็นตัวพิมพ์เล็ก และตัดคำซ้ำ
+func normalizeWords(words []string) []string {
+	seen := make(map[string]bool)
+	result := []string{}
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" || seen[word] {
+			continue
+		}
+		seen[word] = true
+		result = append(result, word)
+	}
+	return result
+}
+
+// buildWordPattern สร้าง regex แบบไม่สนตัวพิมพ์ใหญ่เล็กจากรายการคำ (nil = ไม่มีคำ)
+func buildWordPattern(words []string) *regexp.Regexp {
+	if len(words) == 0 {
+		return nil
+	}
+
+	// คำยาวก่อน เพื่อให้ mask คำที่ซ้อนกันได้ครบ
+	sorted := append([]string{}, words...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return utf8.RuneCountInString(sorted[i]) > utf8.RuneCountInString(sorted[j])
+	})
+
+	parts := make([]string, 0, len(sorted))
+	for _, word := range sorted {
+		part := regexp.QuoteMeta(word)
+		if asciiWordRegex.MatchString(word) {
+			part = `\b` + part + `\b`
+		}
+		parts = append(parts, part)
+	}
+
+	return regexp.MustCompile(`(?i)(?:` + strings.Join(parts, "|") + `)`)
+}
@@ -0,0 +1,200 @@
+// infrastructure/jwtsigner/key_ring.go
+package jwtsigner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+)
+
+// KeyRingConfig เก็บการตั้งค่าของ key ที่ใช้เซ็นและตรวจ JWT
+//
+// หมุน key โดยไม่มี downtime:
+//  1. เพิ่มไฟล์ key ใหม่ใน KeysDir แล้ว deploy ทุก instance (ยังเซ็นด้วย key เดิม แต่ตรวจและเผยแพร่ key ใหม่แล้ว)
+//  2. เปลี่ยน ActiveKeyID เป็น key ใหม่แล้ว deploy อีกครั้ง
+//  3. ลบไฟล์ key เดิมเมื่อ token ที่เซ็นด้วย key เดิมหมดอายุหมดแล้ว
+type KeyRingConfig struct {
+	KeysDir     string // โฟลเดอร์ไฟล์ .pem หนึ่งไฟล์ต่อ key (ชื่อไฟล์ = kid) ว่าง = ใช้ HS256 ด้วย HMACSecret
+	ActiveKeyID string // kid ของ key ที่ใช้เซ็น (ต้องเป็น private key, ว่างได้ถ้ามี private key เดียว)
+	HMACSecret  []byte // secret ของ HS256
+	AcceptHMAC  bool   // เมื่อใช้ KeysDir: ยังรับ token HS256 เดิม (ไม่มี kid) ระหว่างย้ายไปใช้ key แบบ asymmetric
+}
+
+// KeyRing เซ็น JWT ด้วย key ที่ใช้งานอยู่ และตรวจ token ด้วย key ใดก็ได้ใน ring ตาม kid
+type KeyRing struct {
+	active     *signingKey
+	keys       map[string]*signingKey
+	ordered    []*signingKey // เรียงตาม kid (ลำดับคงที่ใน JWKS)
+	hmacSecret []byte        // nil = ไม่รับ HS256
+	methods    []string
+}
+
+// NewKeyRing โหลด key จาก KeysDir (หรือใช้ HS256 ถ้าไม่ได้ตั้งค่า)
+func NewKeyRing(config *KeyRingConfig) (*KeyRing, error) {
+	if config == nil {
+		return nil, errors.New("key ring config is required")
+	}
+
+	ring := &KeyRing{keys: map[string]*signingKey{}}
+
+	if config.KeysDir == "" {
+		if len(config.HMACSecret) == 0 {
+			return nil, errors.New("jwt secret is required when no signing keys are configured")
+		}
+		ring.hmacSecret = config.HMACSecret
+		ring.methods = []string{jwt.SigningMethodHS256.Alg()}
+		return ring, nil
+	}
+
+	if err := ring.loadDir(config.KeysDir); err != nil {
+		return nil, err
+	}
+
+	activeID := config.ActiveKeyID
+	if activeID == "" {
+		var privateIDs []string
+		for _, key := range ring.ordered {
+			if key.private != nil {
+				privateIDs = append(privateIDs, key.kid)
+			}
+		}
+		if len(privateIDs) != 1 {
+			return nil, fmt.Errorf("active key id is required when %s has %d private keys", config.KeysDir, len(privateIDs))
+		}
+		activeID = privateIDs[0]
+	}
+
+	active, ok := ring.keys[activeID]
+	if !ok {
+		return nil, fmt.Errorf("active signing key %q not found in %s", activeID, config.KeysDir)
+	}
+	if active.private == nil {
+		return nil, fmt.Errorf("active signing key %q is a public key", activeID)
+	}
+	ring.active = active
+
+	seen := map[string]bool{}
+	for _, key := range ring.ordered {
+		if !seen[key.method.Alg()] {
+			seen[key.method.Alg()] = true
+			ring.methods = append(ring.methods, key.method.Alg())
+		}
+	}
+	if config.AcceptHMAC {
+		if len(config.HMACSecret) == 0 {
+			return nil, errors.New("jwt secret is required to accept hs256 tokens")
+		}
+		ring.hmacSecret = config.HMACSecret
+		ring.methods = append(ring.methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	return ring, nil
+}
+
+// loadDir โหลดไฟล์ .pem ทั้งหมดในโฟลเดอร์
+func (r *KeyRing) loadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read signing keys: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".pem") {
+			continue
+		}
+
+		kid := strings.TrimSuffix(entry.Name(), ".pem")
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read signing key %s: %w", entry.Name(), err)
+		}
+		key, err := parseKeyPEM(kid, data)
+		if err != nil {
+			return fmt.Errorf("invalid signing key %s: %w", entry.Name(), err)
+		}
+
+		r.keys[kid] = key
+		r.ordered = append(r.ordered, key)
+	}
+
+	if len(r.ordered) == 0 {
+		return fmt.Errorf("no signing keys (*.pem) found in %s", dir)
+	}
+	sort.Slice(r.ordered, func(i, j int) bool { return r.ordered[i].kid < r.ordered[j].kid })
+	return nil
+}
+
+// Algorithm อัลกอริทึมที่ใช้เซ็น token ใหม่
+func (r *KeyRing) Algorithm() string {
+	if r.active == nil {
+		return jwt.SigningMethodHS256.Alg()
+	}
+	return r.active.method.Alg()
+}
+
+// ActiveKeyID kid ของ key ที่ใช้เซ็น (ว่างเมื่อใช้ HS256)
+func (r *KeyRing) ActiveKeyID() string {
+	if r.active == nil {
+		return ""
+	}
+	return r.active.kid
+}
+
+// Sign เซ็น claims ด้วย key ที่ใช้งานอยู่
+func (r *KeyRing) Sign(claims map[string]interface{}) (string, error) {
+	if r.active == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims(claims)).SignedString(r.hmacSecret)
+	}
+
+	token := jwt.NewWithClaims(r.active.method, jwt.MapClaims(claims))
+	token.Header["kid"] = r.active.kid
+	return token.SignedString(r.active.private)
+}
+
+// Verify ตรวจลายเซ็นด้วย key ตาม kid และวันหมดอายุ
+func (r *KeyRing) Verify(tokenString string) (map[string]interface{}, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods(r.methods), jwt.WithExpirationRequired())
+
+	claims := jwt.MapClaims{}
+	token, err := parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			if r.hmacSecret != nil && token.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+				return r.hmacSecret, nil
+			}
+			return nil, errors.New("token has no key id")
+		}
+
+		key, ok := r.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, errors.New("signing method does not match key")
+		}
+		return key.public, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
+
+// PublicKeys public key ทั้งหมดใน ring (HS256 ไม่มี public key จึงไม่เผยแพร่)
+func (r *KeyRing) PublicKeys() []service.JSONWebKey {
+	keys := make([]service.JSONWebKey, 0, len(r.ordered))
+	for _, key := range r.ordered {
+		keys = append(keys, key.jwk())
+	}
+	return keys
+}
@@ -0,0 +1,72 @@
+// infrastructure/mail/file_mailer.go
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	netmail "net/mail"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+)
+
+// FileMailerConfig เก็บการตั้งค่าสำหรับ file/log mailer (ใช้ในการพัฒนาและทดสอบ)
+type FileMailerConfig struct {
+	Dir  string // โฟลเดอร์ที่เก็บไฟล์ .eml (ว่าง = พิมพ์อีเมลออกทาง log)
+	From string // default: no-reply@localhost
+}
+
+// FileMailer เขียนอีเมลเป็นไฟล์ .eml หรือพิมพ์ออกทาง log แทนการส่งจริง
+type FileMailer struct {
+	config *FileMailerConfig
+	from   *netmail.Address
+}
+
+// NewFileMailer สร้าง file/log mailer ใหม่
+func NewFileMailer(config *FileMailerConfig) (*FileMailer, error) {
+	if config == nil {
+		config = &FileMailerConfig{}
+	}
+	if config.From == "" {
+		config.From = "no-reply@localhost"
+	}
+
+	from, err := parseFrom(config.From)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Dir != "" {
+		if err := os.MkdirAll(config.Dir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create mail directory: %w", err)
+		}
+	}
+
+	return &FileMailer{config: config, from: from}, nil
+}
+
+// Send บันทึกอีเมลลงไฟล์ (หรือ log)
+func (m *FileMailer) Send(ctx context.Context, message *service.MailMessage) error {
+	recipient, data, err := buildMessage(m.from, message)
+	if err != nil {
+		return err
+	}
+
+	if m.config.Dir == "" {
+		log.Printf("[mail] to=%s subject=%q\n%s", recipient, message.Subject, message.Body)
+		return nil
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102T150405"), uuid.NewString()[:8])
+	path := filepath.Join(m.config.Dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write mail file: %w", err)
+	}
+
+	log.Printf("[mail] to=%s subject=%q written to %s", recipient, message.Subject, path)
+	return nil
+}
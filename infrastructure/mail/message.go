@@ -0,0 +1,66 @@
+// infrastructure/mail/message.go
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+)
+
+// parseFrom ตรวจสอบที่อยู่ผู้ส่ง (เช่น "Chat <no-reply@example.com>")
+func parseFrom(from string) (*netmail.Address, error) {
+	if strings.TrimSpace(from) == "" {
+		return nil, errors.New("mail sender address is required")
+	}
+	address, err := netmail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mail sender address: %w", err)
+	}
+	return address, nil
+}
+
+// buildMessage สร้างอีเมลตาม RFC 5322 (subject เข้ารหัส UTF-8, body แบบ quoted-printable)
+// คืนค่าที่อยู่ผู้รับที่ผ่านการตรวจสอบแล้วสำหรับคำสั่ง RCPT
+func buildMessage(from *netmail.Address, message *service.MailMessage) (string, []byte, error) {
+	if message == nil {
+		return "", nil, errors.New("mail message is required")
+	}
+	to, err := netmail.ParseAddress(message.To)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid mail recipient: %w", err)
+	}
+	if strings.ContainsAny(message.Subject, "\r\n") {
+		return "", nil, errors.New("mail subject must not contain line breaks")
+	}
+
+	domain := from.Address[strings.LastIndex(from.Address, "@")+1:]
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", uuid.NewString(), domain)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	buf.WriteString("\r\n")
+
+	writer := quotedprintable.NewWriter(&buf)
+	if _, err := writer.Write([]byte(message.Body)); err != nil {
+		return "", nil, fmt.Errorf("failed to encode mail body: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", nil, fmt.Errorf("failed to encode mail body: %w", err)
+	}
+
+	return to.Address, buf.Bytes(), nil
+}
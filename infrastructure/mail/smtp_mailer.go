@@ -0,0 +1,130 @@
+// infrastructure/mail/smtp_mailer.go
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+)
+
+// SMTPConfig เก็บการตั้งค่าสำหรับส่งอีเมลผ่าน SMTP
+type SMTPConfig struct {
+	Host        string
+	Port        int // default 587
+	Username    string
+	Password    string
+	From        string        // ที่อยู่ผู้ส่ง เช่น "Chat <no-reply@example.com>"
+	ImplicitTLS bool          // เชื่อมต่อแบบ TLS ตั้งแต่ต้น (port 465) แทน STARTTLS
+	Timeout     time.Duration // เวลารอทั้งการส่งหนึ่งครั้ง (default 15 วินาที)
+}
+
+// SMTPMailer ส่งอีเมลด้วย net/smtp (ใช้ STARTTLS เมื่อเซิร์ฟเวอร์รองรับ)
+type SMTPMailer struct {
+	config *SMTPConfig
+	from   *netmail.Address
+}
+
+// NewSMTPMailer สร้าง SMTP mailer ใหม่
+func NewSMTPMailer(config *SMTPConfig) (*SMTPMailer, error) {
+	if config == nil || config.Host == "" {
+		return nil, errors.New("smtp host is required")
+	}
+	if config.Port <= 0 {
+		config.Port = 587
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 15 * time.Second
+	}
+
+	from, err := parseFrom(config.From)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SMTPMailer{config: config, from: from}, nil
+}
+
+// Send ส่งอีเมลหนึ่งฉบับ
+func (m *SMTPMailer) Send(ctx context.Context, message *service.MailMessage) error {
+	recipient, data, err := buildMessage(m.from, message)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(m.config.Timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	dialer := &net.Dialer{Deadline: deadline}
+	tlsConfig := &tls.Config{ServerName: m.config.Host}
+
+	var conn net.Conn
+	if m.config.ImplicitTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to set smtp deadline: %w", err)
+	}
+
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	defer client.Close()
+
+	if !m.config.ImplicitTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("failed to start tls: %w", err)
+			}
+		}
+	}
+
+	if m.config.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("smtp server does not support authentication")
+		}
+		// PlainAuth ปฏิเสธการส่งรหัสผ่านผ่านการเชื่อมต่อที่ไม่เข้ารหัส (ยกเว้น localhost)
+		if err := client.Auth(smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)); err != nil {
+			return fmt.Errorf("smtp authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(m.from.Address); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	if err := client.Rcpt(recipient); err != nil {
+		return fmt.Errorf("smtp RCPT TO failed: %w", err)
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to write mail: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,99 @@
+// infrastructure/netguard/dialer.go
+package netguard
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateAddress ปลายทางเป็น address ภายใน (loopback, private, link-local, metadata ฯลฯ)
+var ErrPrivateAddress = errors.New("destination address is not allowed")
+
+// blockedPrefixes ช่วง address ที่ไม่ใช่ internet สาธารณะ (นอกเหนือจากที่ netip ตรวจได้เอง)
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // TEST-NET-1
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // TEST-NET-2
+	netip.MustParsePrefix("203.0.113.0/24"),  // TEST-NET-3
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved + broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64 (แปลงเป็น IPv4 ภายในได้)
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+}
+
+// IsPublicAddr ตรวจว่า address ชี้ไปยัง internet สาธารณะ
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || // รวม 169.254.169.254 (cloud metadata)
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// DialControl ใช้กับ net.Dialer.Control ตรวจ address หลัง resolve DNS แล้ว (กัน DNS rebinding)
+func DialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !IsPublicAddr(addr) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+// ValidateHost ตรวจ host ของ URL ล่วงหน้า (IP ภายในหรือ localhost) เพื่อแจ้ง error ตั้งแต่ตอนบันทึก
+// การตรวจจริงอยู่ที่ DialControl เพราะชื่อโดเมนอาจ resolve เป็น address ภายในภายหลัง
+func ValidateHost(parsed *url.URL) error {
+	host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrPrivateAddress
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !IsPublicAddr(addr) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+// NewTransport สร้าง http.Transport ที่ปฏิเสธการเชื่อมต่อไปยัง address ภายใน
+// allowPrivate = true ปิดการตรวจ (ใช้กับ stand-in บนเครื่องระหว่างพัฒนาและทดสอบเท่านั้น)
+// ไม่ใช้ proxy จาก environment เพราะ proxy จะทำให้ตรวจ address ปลายทางไม่ได้
+func NewTransport(allowPrivate bool) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if !allowPrivate {
+		dialer.Control = DialControl
+	}
+
+	return &http.Transport{
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
@@ -0,0 +1,176 @@
+// infrastructure/oauth/jwks.go
+package oauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ระยะเวลาขั้นต่ำระหว่างการโหลด JWKS ซ้ำเมื่อเจอ kid ที่ไม่รู้จัก (กัน token ปลอมยิงให้โหลดรัว ๆ)
+const jwksMinRefreshInterval = 30 * time.Second
+
+// jsonWebKey หนึ่ง key ใน JWKS (รองรับ RSA และ EC)
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// jwksCache เก็บ public key ของ provider ตาม kid และโหลดใหม่เมื่อ provider หมุน key
+type jwksCache struct {
+	mu          sync.Mutex
+	keys        map[string]interface{}
+	fetchedAt   time.Time
+	fetch       func(ctx context.Context, dst interface{}) error
+	maxAge      time.Duration
+	lastRefresh time.Time
+}
+
+func newJWKSCache(fetch func(ctx context.Context, dst interface{}) error, maxAge time.Duration) *jwksCache {
+	return &jwksCache{fetch: fetch, maxAge: maxAge}
+}
+
+// key คืนค่า public key ตาม kid (kid ว่าง = ใช้ได้เมื่อมี key เดียว)
+func (c *jwksCache) key(ctx context.Context, kid string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.keys != nil && time.Since(c.fetchedAt) < c.maxAge {
+		if key, ok := c.lookup(kid); ok {
+			return key, nil
+		}
+		if time.Since(c.lastRefresh) < jwksMinRefreshInterval {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+	}
+
+	if err := c.refresh(ctx); err != nil {
+		return nil, err
+	}
+	if key, ok := c.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (c *jwksCache) lookup(kid string) (interface{}, bool) {
+	if kid != "" {
+		key, ok := c.keys[kid]
+		return key, ok
+	}
+	if len(c.keys) == 1 {
+		for _, key := range c.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+func (c *jwksCache) refresh(ctx context.Context) error {
+	c.lastRefresh = time.Now()
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := c.fetch(ctx, &set); err != nil {
+		return fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// ข้าม key ชนิดที่ไม่รองรับ แทนที่จะทำให้ทั้งชุดใช้ไม่ได้
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return errors.New("jwks contains no usable signing keys")
+	}
+
+	c.keys = keys
+	c.fetchedAt = time.Now()
+	return nil
+}
+
+// publicKey แปลง JWK เป็น *rsa.PublicKey หรือ *ecdsa.PublicKey
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid rsa exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("ec point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(raw) == 0 {
+		return nil, errors.New("invalid jwk parameter")
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
+
+// ตรวจว่า response เป็น 2xx
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,379 @@
+// infrastructure/oauth/oidc_provider.go
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+)
+
+// จำนวน byte สูงสุดที่อ่านจาก response ของ provider
+const maxResponseBytes = 1 << 20
+
+// เผื่อเวลาคลาดเคลื่อนระหว่างนาฬิกาของเรากับ provider ตอนตรวจ exp/iat ของ ID token
+const idTokenLeeway = time.Minute
+
+// OIDCConfig เก็บการตั้งค่าสำหรับ OpenID Connect provider หนึ่งราย
+type OIDCConfig struct {
+	Name          string // ชื่อ provider ที่ใช้ใน URL เช่น google, microsoft
+	IssuerURL     string // ใช้หา /.well-known/openid-configuration
+	ClientID      string
+	ClientSecret  string        // ว่างได้สำหรับ public client (PKCE อย่างเดียว)
+	RedirectURL   string        // ต้องตรงกับที่ลงทะเบียนไว้กับ provider
+	Scopes        []string      // default: openid email profile
+	AllowInsecure bool          // อนุญาต http:// (เช่น mock issuer บนเครื่องระหว่างพัฒนา)
+	Timeout       time.Duration // เวลารอ response ต่อครั้ง (default 10 วินาที)
+	CacheTTL      time.Duration // อายุ cache ของ discovery document และ JWKS (default 1 ชั่วโมง)
+}
+
+// discoveryDocument ส่วนของ OpenID Provider Metadata ที่เราใช้
+type discoveryDocument struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// OIDCProvider ใช้ authorization code flow + PKCE กับ provider ที่รองรับ OpenID Connect Discovery
+type OIDCProvider struct {
+	config *OIDCConfig
+	client *http.Client
+	jwks   *jwksCache
+
+	mu           sync.Mutex
+	discovery    *discoveryDocument
+	discoveredAt time.Time
+}
+
+// NewOIDCProvider สร้าง provider ใหม่ (โหลด discovery document ตอนใช้งานครั้งแรก)
+func NewOIDCProvider(config *OIDCConfig) (*OIDCProvider, error) {
+	if config == nil {
+		return nil, errors.New("oidc config is required")
+	}
+	config.Name = strings.ToLower(strings.TrimSpace(config.Name))
+	config.IssuerURL = strings.TrimRight(strings.TrimSpace(config.IssuerURL), "/")
+	if config.Name == "" {
+		return nil, errors.New("oidc provider name is required")
+	}
+	if config.ClientID == "" {
+		return nil, fmt.Errorf("oidc provider %s: client id is required", config.Name)
+	}
+	if config.RedirectURL == "" {
+		return nil, fmt.Errorf("oidc provider %s: redirect url is required", config.Name)
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = time.Hour
+	}
+
+	p := &OIDCProvider{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}
+	if err := p.validateURL(config.IssuerURL); err != nil {
+		return nil, fmt.Errorf("oidc provider %s: issuer %w", config.Name, err)
+	}
+	p.jwks = newJWKSCache(func(ctx context.Context, dst interface{}) error {
+		doc, err := p.discover(ctx)
+		if err != nil {
+			return err
+		}
+		return p.getJSON(ctx, doc.JWKSURI, "", dst)
+	}, config.CacheTTL)
+
+	return p, nil
+}
+
+// Name คืนค่าชื่อ provider
+func (p *OIDCProvider) Name() string {
+	return p.config.Name
+}
+
+// AuthCodeURL สร้าง URL สำหรับ redirect ผู้ใช้ไปยังหน้าเข้าสู่ระบบของ provider
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(doc.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
+
+// Exchange แลก authorization code เป็น token แล้วตรวจสอบ ID token
+func (p *OIDCProvider) Exchange(ctx context.Context, code, codeVerifier string) (*service.OAuthIdentity, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	form.Set("client_id", p.config.ClientID)
+
+	// ใช้ client_secret_post ถ้า provider รองรับ ไม่เช่นนั้นใช้ client_secret_basic
+	useBasic := p.config.ClientSecret != "" && !p.supportsAuthMethod(doc, "client_secret_post")
+	if p.config.ClientSecret != "" && !useBasic {
+		form.Set("client_secret", p.config.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if useBasic {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tokenResponse struct {
+		AccessToken      string `json:"access_token"`
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&tokenResponse); err != nil {
+		return nil, fmt.Errorf("invalid token response (status %d)", resp.StatusCode)
+	}
+	if tokenResponse.Error != "" {
+		return nil, fmt.Errorf("token request rejected: %s %s", tokenResponse.Error, tokenResponse.ErrorDescription)
+	}
+	if err := checkStatus(resp); err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	if tokenResponse.IDToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	identity, err := p.verifyIDToken(ctx, doc, tokenResponse.IDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// บาง provider ไม่ใส่ email ใน ID token ให้ถามจาก userinfo endpoint แทน
+	if identity.Email == "" && doc.UserinfoEndpoint != "" && tokenResponse.AccessToken != "" {
+		if err := p.fillFromUserinfo(ctx, doc, tokenResponse.AccessToken, identity); err != nil {
+			return nil, err
+		}
+	}
+
+	return identity, nil
+}
+
+// verifyIDToken ตรวจลายเซ็น (JWKS), issuer, audience และวันหมดอายุของ ID token
+func (p *OIDCProvider) verifyIDToken(ctx context.Context, doc *discoveryDocument, rawIDToken string) (*service.OAuthIdentity, error) {
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(idTokenLeeway),
+	)
+
+	claims := jwt.MapClaims{}
+	_, err := parser.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.jwks.key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid id token: %w", err)
+	}
+
+	issuer, _ := claims["iss"].(string)
+	expectedIssuer := doc.Issuer
+	if strings.Contains(expectedIssuer, "{tenantid}") {
+		// endpoint แบบ multi-tenant (เช่น Microsoft common) ใช้ issuer ตาม tenant ของผู้ใช้
+		tenantID, _ := claims["tid"].(string)
+		if tenantID == "" {
+			return nil, errors.New("invalid id token: missing tenant id")
+		}
+		expectedIssuer = strings.ReplaceAll(expectedIssuer, "{tenantid}", tenantID)
+	}
+	if issuer != expectedIssuer {
+		return nil, errors.New("invalid id token: issuer mismatch")
+	}
+
+	// มีหลาย audience ต้องระบุ azp เป็น client ของเรา
+	if audiences, _ := claims.GetAudience(); len(audiences) > 1 {
+		if azp, _ := claims["azp"].(string); azp != p.config.ClientID {
+			return nil, errors.New("invalid id token: authorized party mismatch")
+		}
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, errors.New("invalid id token: missing subject")
+	}
+
+	identity := &service.OAuthIdentity{
+		Subject:       subject,
+		Email:         stringClaim(claims, "email"),
+		EmailVerified: boolClaim(claims, "email_verified"),
+		Name:          stringClaim(claims, "name"),
+		Picture:       stringClaim(claims, "picture"),
+		Nonce:         stringClaim(claims, "nonce"),
+	}
+	return identity, nil
+}
+
+// fillFromUserinfo เติม email/ชื่อจาก userinfo endpoint (sub ต้องตรงกับ ID token)
+func (p *OIDCProvider) fillFromUserinfo(ctx context.Context, doc *discoveryDocument, accessToken string, identity *service.OAuthIdentity) error {
+	claims := map[string]interface{}{}
+	if err := p.getJSON(ctx, doc.UserinfoEndpoint, accessToken, &claims); err != nil {
+		return fmt.Errorf("userinfo request failed: %w", err)
+	}
+	if stringClaim(claims, "sub") != identity.Subject {
+		return errors.New("userinfo subject mismatch")
+	}
+
+	identity.Email = stringClaim(claims, "email")
+	identity.EmailVerified = boolClaim(claims, "email_verified")
+	if identity.Name == "" {
+		identity.Name = stringClaim(claims, "name")
+	}
+	if identity.Picture == "" {
+		identity.Picture = stringClaim(claims, "picture")
+	}
+	return nil
+}
+
+// discover โหลด discovery document (cache ตาม CacheTTL)
+func (p *OIDCProvider) discover(ctx context.Context) (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil && time.Since(p.discoveredAt) < p.config.CacheTTL {
+		return p.discovery, nil
+	}
+
+	var doc discoveryDocument
+	if err := p.getJSON(ctx, p.config.IssuerURL+"/.well-known/openid-configuration", "", &doc); err != nil {
+		return nil, fmt.Errorf("oidc discovery failed for %s: %w", p.config.Name, err)
+	}
+
+	issuer := strings.TrimRight(doc.Issuer, "/")
+	if issuer != p.config.IssuerURL && !strings.Contains(issuer, "{tenantid}") {
+		return nil, fmt.Errorf("oidc discovery failed for %s: issuer mismatch %q", p.config.Name, doc.Issuer)
+	}
+	for _, endpoint := range []string{doc.AuthorizationEndpoint, doc.TokenEndpoint, doc.JWKSURI} {
+		if err := p.validateURL(endpoint); err != nil {
+			return nil, fmt.Errorf("oidc discovery failed for %s: endpoint %w", p.config.Name, err)
+		}
+	}
+	if doc.UserinfoEndpoint != "" {
+		if err := p.validateURL(doc.UserinfoEndpoint); err != nil {
+			doc.UserinfoEndpoint = ""
+		}
+	}
+
+	p.discovery = &doc
+	p.discoveredAt = time.Now()
+	return p.discovery, nil
+}
+
+// supportsAuthMethod ตรวจว่า token endpoint รองรับวิธียืนยันตัวตนของ client นี้ (ไม่ระบุ = client_secret_basic ตาม spec)
+func (p *OIDCProvider) supportsAuthMethod(doc *discoveryDocument, method string) bool {
+	if len(doc.TokenEndpointAuthMethodsSupported) == 0 {
+		return method == "client_secret_basic"
+	}
+	for _, supported := range doc.TokenEndpointAuthMethodsSupported {
+		if supported == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *OIDCProvider) getJSON(ctx context.Context, endpoint, bearer string, dst interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+		return err
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(dst)
+}
+
+// validateURL ตรวจว่าเป็น absolute URL แบบ https (ยกเว้น AllowInsecure)
+func (p *OIDCProvider) validateURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return errors.New("must be an absolute url")
+	}
+	if parsed.Scheme == "https" || (parsed.Scheme == "http" && p.config.AllowInsecure) {
+		return nil
+	}
+	return errors.New("must use https")
+}
+
+func stringClaim(claims map[string]interface{}, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// boolClaim อ่าน claim ที่เป็น bool (บาง provider ส่งเป็น string "true")
+func boolClaim(claims map[string]interface{}, name string) bool {
+	switch value := claims[name].(type) {
+	case bool:
+		return value
+	case string:
+		return value == "true"
+	default:
+		return false
+	}
+}
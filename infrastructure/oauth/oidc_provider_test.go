@@ -0,0 +1,286 @@
+// infrastructure/oauth/oidc_provider_test.go
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID    = "chat-api"
+	testRedirectURL = "https://chat.example.com/auth/oauth/mock/callback"
+	testKeyID       = "mock-key"
+)
+
+// mockAuthorization สิ่งที่ mock issuer จำไว้ต่อ authorization code (เหมือนหน้า authorize ของ provider จริง)
+type mockAuthorization struct {
+	challenge string
+	nonce     string
+}
+
+// mockIssuer OpenID provider จำลองบน httptest: discovery, JWKS และ token endpoint ที่ตรวจ PKCE (S256)
+type mockIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]mockAuthorization
+
+	// ค่าที่ใส่ใน ID token (ว่าง = ใช้ค่าปกติของ issuer นี้)
+	tokenIssuer   string
+	tokenAudience []string
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	m := &mockIssuer{key: key, codes: map[string]mockAuthorization{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", m.handleDiscovery)
+	mux.HandleFunc("/jwks", m.handleJWKS)
+	mux.HandleFunc("/token", m.handleToken)
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+// authorize จำลองผู้ใช้เข้าสู่ระบบที่หน้า authorize แล้วคืนค่า code
+func (m *mockIssuer) authorize(t *testing.T, authURL string) string {
+	t.Helper()
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse authorization url: %v", err)
+	}
+	query := parsed.Query()
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		t.Fatalf("authorization url must carry an S256 code challenge: %s", authURL)
+	}
+	if query.Get("client_id") != testClientID || query.Get("redirect_uri") != testRedirectURL {
+		t.Fatalf("unexpected client in authorization url: %s", authURL)
+	}
+
+	code := "code-" + query.Get("state")
+	m.mu.Lock()
+	m.codes[code] = mockAuthorization{challenge: query.Get("code_challenge"), nonce: query.Get("nonce")}
+	m.mu.Unlock()
+	return code
+}
+
+func (m *mockIssuer) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"issuer":                                m.server.URL,
+		"authorization_endpoint":                m.server.URL + "/authorize",
+		"token_endpoint":                        m.server.URL + "/token",
+		"jwks_uri":                              m.server.URL + "/jwks",
+		"token_endpoint_auth_methods_supported": []string{"client_secret_post", "none"},
+	})
+}
+
+func (m *mockIssuer) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testKeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (m *mockIssuer) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "invalid_request")
+		return
+	}
+	if r.PostForm.Get("client_id") != testClientID || r.PostForm.Get("redirect_uri") != testRedirectURL {
+		tokenError(w, "invalid_client")
+		return
+	}
+
+	m.mu.Lock()
+	authorization, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+	if !ok {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	// RFC 7636: BASE64URL(SHA256(code_verifier)) ต้องตรงกับ code_challenge ที่ได้ตอน authorize
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != authorization.challenge {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	issuer := m.server.URL
+	if m.tokenIssuer != "" {
+		issuer = m.tokenIssuer
+	}
+	audience := []string{testClientID}
+	if len(m.tokenAudience) > 0 {
+		audience = m.tokenAudience
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            issuer,
+		"aud":            audience,
+		"sub":            "mock-subject",
+		"email":          "alice@example.com",
+		"email_verified": true,
+		"name":           "Alice",
+		"nonce":          authorization.nonce,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+	})
+	token.Header["kid"] = testKeyID
+	idToken, err := token.SignedString(m.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"access_token": "mock-access-token",
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+	})
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
+
+func newTestProvider(t *testing.T, issuer *mockIssuer) *OIDCProvider {
+	t.Helper()
+
+	provider, err := NewOIDCProvider(&OIDCConfig{
+		Name:          "mock",
+		IssuerURL:     issuer.server.URL,
+		ClientID:      testClientID,
+		RedirectURL:   testRedirectURL,
+		AllowInsecure: true,
+	})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	return provider
+}
+
+func testChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func TestOIDCProviderExchangeWithPKCE(t *testing.T) {
+	issuer := newMockIssuer(t)
+	provider := newTestProvider(t, issuer)
+	ctx := context.Background()
+
+	verifier := "verifier-0123456789-0123456789-0123456789"
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", testChallenge(verifier))
+	if err != nil {
+		t.Fatalf("auth code url: %v", err)
+	}
+	if !strings.HasPrefix(authURL, issuer.server.URL+"/authorize?") {
+		t.Fatalf("expected discovered authorization endpoint, got %s", authURL)
+	}
+	code := issuer.authorize(t, authURL)
+
+	identity, err := provider.Exchange(ctx, code, verifier)
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+	if identity.Subject != "mock-subject" || identity.Email != "alice@example.com" || !identity.EmailVerified {
+		t.Fatalf("unexpected identity %+v", identity)
+	}
+	if identity.Nonce != "nonce-1" {
+		t.Fatalf("expected nonce from the authorization request, got %q", identity.Nonce)
+	}
+}
+
+func TestOIDCProviderExchangeRejectsWrongVerifier(t *testing.T) {
+	issuer := newMockIssuer(t)
+	provider := newTestProvider(t, issuer)
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", testChallenge("the-real-verifier"))
+	if err != nil {
+		t.Fatalf("auth code url: %v", err)
+	}
+	code := issuer.authorize(t, authURL)
+
+	if _, err := provider.Exchange(ctx, code, "an-intercepted-code-without-verifier"); err == nil {
+		t.Fatal("expected exchange with a wrong code verifier to fail")
+	}
+}
+
+func TestOIDCProviderRejectsIssuerMismatch(t *testing.T) {
+	issuer := newMockIssuer(t)
+	issuer.tokenIssuer = "https://evil.example.com"
+	provider := newTestProvider(t, issuer)
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", testChallenge("verifier"))
+	if err != nil {
+		t.Fatalf("auth code url: %v", err)
+	}
+
+	_, err = provider.Exchange(ctx, issuer.authorize(t, authURL), "verifier")
+	if err == nil || !strings.Contains(err.Error(), "issuer mismatch") {
+		t.Fatalf("expected issuer mismatch, got %v", err)
+	}
+}
+
+func TestOIDCProviderRejectsAudienceMismatch(t *testing.T) {
+	issuer := newMockIssuer(t)
+	provider := newTestProvider(t, issuer)
+	ctx := context.Background()
+
+	// token ที่ออกให้ client อื่นต้องใช้กับเราไม่ได้
+	issuer.tokenAudience = []string{"another-client"}
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", testChallenge("verifier"))
+	if err != nil {
+		t.Fatalf("auth code url: %v", err)
+	}
+	if _, err := provider.Exchange(ctx, issuer.authorize(t, authURL), "verifier"); err == nil {
+		t.Fatal("expected id token for another audience to be rejected")
+	}
+
+	// หลาย audience ต้องมี azp เป็น client ของเรา
+	issuer.tokenAudience = []string{testClientID, "another-client"}
+	authURL, err = provider.AuthCodeURL(ctx, "state-2", "nonce-2", testChallenge("verifier"))
+	if err != nil {
+		t.Fatalf("auth code url: %v", err)
+	}
+	_, err = provider.Exchange(ctx, issuer.authorize(t, authURL), "verifier")
+	if err == nil || !strings.Contains(err.Error(), "authorized party mismatch") {
+		t.Fatalf("expected authorized party mismatch, got %v", err)
+	}
+}
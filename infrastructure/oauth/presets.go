@@ -0,0 +1,17 @@
+// infrastructure/oauth/presets.go
+package oauth
+
+import "strings"
+
+// GoogleIssuerURL issuer ของ Google Identity
+const GoogleIssuerURL = "https://accounts.google.com"
+
+// MicrosoftIssuerURL issuer ของ Microsoft identity platform (v2.0)
+// tenant = common, organizations, consumers หรือ tenant ID (default: common)
+func MicrosoftIssuerURL(tenant string) string {
+	tenant = strings.TrimSpace(tenant)
+	if tenant == "" {
+		tenant = "common"
+	}
+	return "https://login.microsoftonline.com/" + tenant + "/v2.0"
+}
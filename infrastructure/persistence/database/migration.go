@@ -43,6 +43,28 @@ func RunMigration(db *gorm.DB) error {
 		&models.Note{},
 		&models.GroupActivity{},
 		&models.PinnedMessage{},
+		&models.MessageReaction{},
+		&models.ThreadRead{},
+		&models.Poll{},
+		&models.PollOption{},
+		&models.PollVote{},
+		&models.DeviceToken{},
+		&models.PushDelivery{},
+		&models.SyncTombstone{},
+		&models.E2EEKeyBundle{},
+		&models.E2EEOneTimePreKey{},
+		&models.AdminAuditLog{},
+		&models.ConversationInviteLink{},
+		&models.ConversationJoinRequest{},
+		&models.Bot{},
+		&models.BotWebhookDelivery{},
+		&models.ConversationWebhook{},
+		&models.ModerationReport{},
+		&models.AccountToken{},
+		&models.TwoFactorRecoveryCode{},
+		&models.UserIdentity{},
+		&models.OAuthState{},
+		&models.Notification{},
 	)
 
 	if err != nil {
@@ -140,11 +162,11 @@ func SetupFullTextSearch(db *gorm.DB) error {
 		return err
 	}
 
-	// Step 2: Populate existing data
+	// Step 2: Populate existing data (ไม่ index ข้อความเข้ารหัส)
 	if err := db.Exec(`
 		UPDATE messages
 		SET content_tsvector = to_tsvector('english', COALESCE(content, ''))
-		WHERE content_tsvector IS NULL
+		WHERE content_tsvector IS NULL AND message_type <> 'encrypted'
 	`).Error; err != nil {
 		return err
 	}
@@ -162,7 +184,11 @@ func SetupFullTextSearch(db *gorm.DB) error {
 		CREATE OR REPLACE FUNCTION messages_content_tsvector_update()
 		RETURNS trigger AS $$
 		BEGIN
-		  NEW.content_tsvector := to_tsvector('english', COALESCE(NEW.content, ''));
+		  IF NEW.message_type = 'encrypted' THEN
+		    NEW.content_tsvector := NULL;
+		  ELSE
+		    NEW.content_tsvector := to_tsvector('english', COALESCE(NEW.content, ''));
+		  END IF;
 		  RETURN NEW;
 		END;
 		$$ LANGUAGE plpgsql
@@ -176,7 +202,7 @@ func SetupFullTextSearch(db *gorm.DB) error {
 	}
 	if err := db.Exec(`
 		CREATE TRIGGER tsvector_update
-		BEFORE INSERT OR UPDATE OF content ON messages
+		BEFORE INSERT OR UPDATE OF content, message_type ON messages
 		FOR EACH ROW
 		EXECUTE FUNCTION messages_content_tsvector_update()
 	`).Error; err != nil {
@@ -187,6 +213,66 @@ func SetupFullTextSearch(db *gorm.DB) error {
 	return nil
 }
 
+// SetupDeltaSync ตั้งค่า index และ trigger สำหรับ delta sync (GET /sync)
+func SetupDeltaSync(db *gorm.DB) error {
+	log.Println("กำลังตั้งค่า delta sync...")
+
+	// Step 1: Cursor indexes - ทุก query ของ sync เรียงตาม (timestamp, id)
+	indices := []string{
+		"CREATE INDEX IF NOT EXISTS idx_messages_sync ON messages(conversation_id, updated_at, id)",
+		"CREATE INDEX IF NOT EXISTS idx_conversation_members_sync ON conversation_members(conversation_id, updated_at, id)",
+		"CREATE INDEX IF NOT EXISTS idx_user_friendships_sync ON user_friendships(updated_at, id)",
+		"CREATE INDEX IF NOT EXISTS idx_notes_sync ON notes(updated_at, id)",
+		"CREATE INDEX IF NOT EXISTS idx_pinned_messages_sync ON pinned_messages(updated_at, id)",
+	}
+	for _, index := range indices {
+		if err := db.Exec(index).Error; err != nil {
+			return err
+		}
+	}
+
+	// Step 2: Trigger function - บันทึก tombstone เมื่อแถวถูกลบจริง
+	if err := db.Exec(`
+		CREATE OR REPLACE FUNCTION record_sync_tombstone()
+		RETURNS trigger AS $$
+		BEGIN
+		  IF TG_TABLE_NAME = 'conversation_members' THEN
+		    INSERT INTO sync_tombstones (entity_type, entity_id, user_id, conversation_id, shared)
+		    VALUES ('member', OLD.id, OLD.user_id, OLD.conversation_id, TRUE);
+		  ELSIF TG_TABLE_NAME = 'notes' THEN
+		    INSERT INTO sync_tombstones (entity_type, entity_id, user_id, conversation_id, shared)
+		    VALUES ('note', OLD.id, OLD.user_id, OLD.conversation_id,
+		            OLD.conversation_id IS NOT NULL AND OLD.visibility = 'shared');
+		  ELSIF TG_TABLE_NAME = 'pinned_messages' THEN
+		    INSERT INTO sync_tombstones (entity_type, entity_id, user_id, conversation_id, shared)
+		    VALUES ('pin', OLD.id, OLD.user_id, OLD.conversation_id, OLD.pin_type = 'public');
+		  ELSIF TG_TABLE_NAME = 'user_friendships' THEN
+		    INSERT INTO sync_tombstones (entity_type, entity_id, user_id, shared)
+		    VALUES ('friendship', OLD.id, OLD.user_id, FALSE),
+		           ('friendship', OLD.id, OLD.friend_id, FALSE);
+		  END IF;
+		  RETURN OLD;
+		END;
+		$$ LANGUAGE plpgsql
+	`).Error; err != nil {
+		return err
+	}
+
+	// Step 3: Triggers บนตารางที่ลบแบบ hard delete
+	for _, table := range []string{"conversation_members", "notes", "pinned_messages", "user_friendships"} {
+		if err := db.Exec("DROP TRIGGER IF EXISTS sync_tombstone ON " + table).Error; err != nil {
+			return err
+		}
+		if err := db.Exec("CREATE TRIGGER sync_tombstone AFTER DELETE ON " + table +
+			" FOR EACH ROW EXECUTE FUNCTION record_sync_tombstone()").Error; err != nil {
+			return err
+		}
+	}
+
+	log.Println("ตั้งค่า delta sync สำเร็จ")
+	return nil
+}
+
 // SetupDatabase ตั้งค่าฐานข้อมูลทั้งหมด
 func SetupDatabase(db *gorm.DB) error {
 	// ทำ migration
@@ -204,5 +290,10 @@ func SetupDatabase(db *gorm.DB) error {
 		return err
 	}
 
+	// ตั้งค่า delta sync
+	if err := SetupDeltaSync(db); err != nil {
+		return err
+	}
+
 	return nil
 }
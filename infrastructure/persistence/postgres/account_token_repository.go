@@ -0,0 +1,62 @@
+// infrastructure/persistence/postgres/account_token_repository.go
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"gorm.io/gorm"
+)
+
+type accountTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountTokenRepository(db *gorm.DB) repository.AccountTokenRepository {
+	return &accountTokenRepository{db: db}
+}
+
+func (r *accountTokenRepository) Create(token *models.AccountToken) error {
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+
+	return r.db.Create(token).Error
+}
+
+func (r *accountTokenRepository) FindByTokenHash(tokenHash string) (*models.AccountToken, error) {
+	var token models.AccountToken
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *accountTokenRepository) Consume(id uuid.UUID, usedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.AccountToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", id, usedAt).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *accountTokenRepository) InvalidateByUserID(userID uuid.UUID, purpose string, usedAt time.Time) error {
+	return r.db.Model(&models.AccountToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", usedAt).Error
+}
+
+func (r *accountTokenRepository) DeleteExpired(before time.Time) error {
+	return r.db.Where("expires_at < ?", before).Delete(&models.AccountToken{}).Error
+}
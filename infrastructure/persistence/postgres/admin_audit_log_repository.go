@@ -0,0 +1,65 @@
+// infrastructure/persistence/postgres/admin_audit_log_repository.go
+package postgres
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+	"gorm.io/gorm"
+)
+
+type adminAuditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAdminAuditLogRepository(db *gorm.DB) repository.AdminAuditLogRepository {
+	return &adminAuditLogRepository{db: db}
+}
+
+func (r *adminAuditLogRepository) Create(entry *models.AdminAuditLog) error {
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	if entry.Details == nil {
+		entry.Details = types.JSONB{}
+	}
+
+	return r.db.Create(entry).Error
+}
+
+func (r *adminAuditLogRepository) List(filter repository.AdminAuditLogFilter) ([]*models.AdminAuditLog, int64, error) {
+	var entries []*models.AdminAuditLog
+	var total int64
+
+	db := r.db.Model(&models.AdminAuditLog{})
+	if filter.ActorID != nil {
+		db = db.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.TargetID != nil {
+		db = db.Where("target_id = ?", *filter.TargetID)
+	}
+	if filter.Action != "" {
+		db = db.Where("action = ?", filter.Action)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Preload("Actor").
+		Order("created_at DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
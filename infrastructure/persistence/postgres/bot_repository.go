@@ -0,0 +1,226 @@
+// infrastructure/persistence/postgres/bot_repository.go
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"gorm.io/gorm"
+)
+
+// botRepository เป็น implementation ของ BotRepository
+type botRepository struct {
+	db *gorm.DB
+}
+
+// NewBotRepository สร้าง repository ใหม่
+func NewBotRepository(db *gorm.DB) repository.BotRepository {
+	return &botRepository{
+		db: db,
+	}
+}
+
+// CreateWithUser สร้าง User ของบอทและ Bot ใน transaction เดียวกัน
+// (บอทไม่มีอีเมล จึงไม่บันทึกคอลัมน์ email เพื่อให้เป็น NULL และไม่ชน unique index)
+func (r *botRepository) CreateWithUser(user *models.User, bot *models.Bot) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Email").Create(user).Error; err != nil {
+			return err
+		}
+		bot.UserID = user.ID
+		return tx.Create(bot).Error
+	})
+}
+
+// GetByID ดึงบอทตาม ID
+func (r *botRepository) GetByID(id uuid.UUID) (*models.Bot, error) {
+	return r.findOne("id = ?", id)
+}
+
+// GetByUserID ดึงบอทตาม user ID ของบอท
+func (r *botRepository) GetByUserID(userID uuid.UUID) (*models.Bot, error) {
+	return r.findOne("user_id = ?", userID)
+}
+
+// GetByTokenHash ดึงบอทตาม hash ของ API token
+func (r *botRepository) GetByTokenHash(tokenHash string) (*models.Bot, error) {
+	return r.findOne("token_hash = ?", tokenHash)
+}
+
+// GetByOwnerID ดึงบอททั้งหมดของเจ้าของ
+func (r *botRepository) GetByOwnerID(ownerID uuid.UUID) ([]*models.Bot, error) {
+	var bots []*models.Bot
+	err := r.db.Preload("User").
+		Where("owner_id = ?", ownerID).
+		Order("created_at ASC").
+		Find(&bots).Error
+	return bots, err
+}
+
+// GetWebhookBotsByConversationID ดึงบอทในการสนทนาที่เปิดใช้งานและตั้ง webhook ไว้
+func (r *botRepository) GetWebhookBotsByConversationID(conversationID uuid.UUID) ([]*models.Bot, error) {
+	var bots []*models.Bot
+	err := r.db.Preload("User").
+		Joins("JOIN conversation_members ON conversation_members.user_id = bots.user_id").
+		Where("conversation_members.conversation_id = ?", conversationID).
+		Where("bots.is_active = ? AND bots.webhook_url <> ''", true).
+		Find(&bots).Error
+	return bots, err
+}
+
+// Update บันทึกการตั้งค่าของบอท
+func (r *botRepository) Update(bot *models.Bot) error {
+	bot.UpdatedAt = time.Now()
+	return r.db.Model(&models.Bot{}).
+		Where("id = ?", bot.ID).
+		Updates(map[string]interface{}{
+			"description":    bot.Description,
+			"token_hash":     bot.TokenHash,
+			"token_prefix":   bot.TokenPrefix,
+			"webhook_url":    bot.WebhookURL,
+			"webhook_secret": bot.WebhookSecret,
+			"receive_all":    bot.ReceiveAll,
+			"is_active":      bot.IsActive,
+			"updated_at":     bot.UpdatedAt,
+		}).Error
+}
+
+// UpdateUserProfile อัปเดตชื่อและรูปโปรไฟล์ของ User ของบอท
+func (r *botRepository) UpdateUserProfile(userID uuid.UUID, displayName, profileImageURL string) error {
+	return r.db.Model(&models.User{}).
+		Where("id = ? AND is_bot = ?", userID, true).
+		Updates(map[string]interface{}{
+			"display_name":      displayName,
+			"profile_image_url": profileImageURL,
+		}).Error
+}
+
+// TouchLastUsed บันทึกเวลาที่ใช้ API token ล่าสุด
+func (r *botRepository) TouchLastUsed(id uuid.UUID, usedAt time.Time) error {
+	return r.db.Model(&models.Bot{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", usedAt).Error
+}
+
+// DeleteWithUser ลบบอท ออกจากทุกการสนทนา และเปลี่ยน User ของบอทเป็นสถานะ deleted
+// (ไม่ลบ User เพราะข้อความเดิมยังอ้างอิง sender_id อยู่)
+func (r *botRepository) DeleteWithUser(bot *models.Bot) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("bot_id = ?", bot.ID).Delete(&models.BotWebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", bot.UserID).Delete(&models.ConversationMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id = ?", bot.ID).Delete(&models.Bot{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).
+			Where("id = ?", bot.UserID).
+			Update("status", models.UserStatusDeleted).Error
+	})
+}
+
+// findOne ดึงบอทหนึ่งรายการพร้อม User (nil ถ้าไม่พบ)
+func (r *botRepository) findOne(query string, args ...interface{}) (*models.Bot, error) {
+	var bot models.Bot
+	if err := r.db.Preload("User").Where(query, args...).First(&bot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &bot, nil
+}
+
+// botWebhookDeliveryRepository เป็น implementation ของ BotWebhookDeliveryRepository
+type botWebhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+// NewBotWebhookDeliveryRepository สร้าง repository ใหม่
+func NewBotWebhookDeliveryRepository(db *gorm.DB) repository.BotWebhookDeliveryRepository {
+	return &botWebhookDeliveryRepository{
+		db: db,
+	}
+}
+
+// Create บันทึกการส่งใหม่
+func (r *botWebhookDeliveryRepository) Create(delivery *models.BotWebhookDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+// Update บันทึกผลการส่ง
+func (r *botWebhookDeliveryRepository) Update(delivery *models.BotWebhookDelivery) error {
+	delivery.UpdatedAt = time.Now()
+	return r.db.Model(&models.BotWebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"response_status": delivery.ResponseStatus,
+			"last_error":      delivery.LastError,
+			"next_retry_at":   delivery.NextRetryAt,
+			"delivered_at":    delivery.DeliveredAt,
+			"updated_at":      delivery.UpdatedAt,
+		}).Error
+}
+
+// ClaimDueRetries จองรายการที่ถึงเวลา retry พร้อมข้อมูลบอท
+// (SKIP LOCKED + lease ป้องกันไม่ให้หลาย instance ส่งรายการเดียวกันซ้ำ ถ้า instance ล่มระหว่างส่ง รายการจะถูก retry อีกครั้งเมื่อ lease หมด)
+func (r *botWebhookDeliveryRepository) ClaimDueRetries(now time.Time, lease time.Duration, limit int) ([]*models.BotWebhookDelivery, error) {
+	var claimedIDs []uuid.UUID
+	err := r.db.Raw(`
+		UPDATE bot_webhook_deliveries
+		SET next_retry_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM bot_webhook_deliveries
+			WHERE status = ? AND next_retry_at IS NOT NULL AND next_retry_at <= ?
+			ORDER BY next_retry_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id
+	`, now.Add(lease), now, models.BotDeliveryPending, now, limit).Scan(&claimedIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(claimedIDs) == 0 {
+		return nil, nil
+	}
+
+	var deliveries []*models.BotWebhookDelivery
+	err = r.db.Preload("Bot").
+		Where("id IN ?", claimedIDs).
+		Order("created_at ASC").
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// GetByBotID ดึงประวัติการส่งของบอท
+func (r *botWebhookDeliveryRepository) GetByBotID(botID uuid.UUID, status string, limit, offset int) ([]*models.BotWebhookDelivery, int64, error) {
+	var deliveries []*models.BotWebhookDelivery
+	var total int64
+
+	query := r.db.Model(&models.BotWebhookDelivery{}).Where("bot_id = ?", botID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
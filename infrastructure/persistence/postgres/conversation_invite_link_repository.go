@@ -0,0 +1,88 @@
+// infrastructure/persistence/postgres/conversation_invite_link_repository.go
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"gorm.io/gorm"
+)
+
+type conversationInviteLinkRepository struct {
+	db *gorm.DB
+}
+
+func NewConversationInviteLinkRepository(db *gorm.DB) repository.ConversationInviteLinkRepository {
+	return &conversationInviteLinkRepository{db: db}
+}
+
+func (r *conversationInviteLinkRepository) Create(link *models.ConversationInviteLink) error {
+	if link.ID == uuid.Nil {
+		link.ID = uuid.New()
+	}
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = time.Now()
+	}
+
+	return r.db.Create(link).Error
+}
+
+func (r *conversationInviteLinkRepository) FindByID(id uuid.UUID) (*models.ConversationInviteLink, error) {
+	var link models.ConversationInviteLink
+	if err := r.db.Where("id = ?", id).First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *conversationInviteLinkRepository) FindByCode(code string) (*models.ConversationInviteLink, error) {
+	var link models.ConversationInviteLink
+	if err := r.db.Where("code = ?", code).First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *conversationInviteLinkRepository) ListByConversationID(conversationID uuid.UUID, includeRevoked bool) ([]*models.ConversationInviteLink, error) {
+	var links []*models.ConversationInviteLink
+
+	db := r.db.Preload("Creator").Where("conversation_id = ?", conversationID)
+	if !includeRevoked {
+		db = db.Where("revoked_at IS NULL")
+	}
+
+	err := db.Order("created_at DESC").Find(&links).Error
+	return links, err
+}
+
+func (r *conversationInviteLinkRepository) Revoke(id uuid.UUID, revokedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.ConversationInviteLink{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *conversationInviteLinkRepository) ClaimUse(id uuid.UUID, now time.Time) (bool, error) {
+	// เงื่อนไขอยู่ใน WHERE เดียวกับการเพิ่มค่า เพื่อไม่ให้ผู้ใช้พร้อมกันหลายคนใช้ลิงก์เกิน max_uses
+	result := r.db.Model(&models.ConversationInviteLink{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Where("max_uses = 0 OR use_count < max_uses").
+		Update("use_count", gorm.Expr("use_count + 1"))
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *conversationInviteLinkRepository) ReleaseUse(id uuid.UUID) error {
+	return r.db.Model(&models.ConversationInviteLink{}).
+		Where("id = ? AND use_count > 0", id).
+		Update("use_count", gorm.Expr("use_count - 1")).Error
+}
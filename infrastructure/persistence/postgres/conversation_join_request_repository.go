@@ -0,0 +1,94 @@
+// infrastructure/persistence/postgres/conversation_join_request_repository.go
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"gorm.io/gorm"
+)
+
+type conversationJoinRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewConversationJoinRequestRepository(db *gorm.DB) repository.ConversationJoinRequestRepository {
+	return &conversationJoinRequestRepository{db: db}
+}
+
+func (r *conversationJoinRequestRepository) Create(request *models.ConversationJoinRequest) error {
+	if request.ID == uuid.Nil {
+		request.ID = uuid.New()
+	}
+	now := time.Now()
+	if request.CreatedAt.IsZero() {
+		request.CreatedAt = now
+	}
+	request.UpdatedAt = now
+	if request.Status == "" {
+		request.Status = models.JoinRequestStatusPending
+	}
+
+	return r.db.Create(request).Error
+}
+
+func (r *conversationJoinRequestRepository) FindByID(id uuid.UUID) (*models.ConversationJoinRequest, error) {
+	var request models.ConversationJoinRequest
+	if err := r.db.Preload("User").Where("id = ?", id).First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *conversationJoinRequestRepository) FindPending(conversationID, userID uuid.UUID) (*models.ConversationJoinRequest, error) {
+	var request models.ConversationJoinRequest
+	err := r.db.Where("conversation_id = ? AND user_id = ? AND status = ?", conversationID, userID, models.JoinRequestStatusPending).
+		First(&request).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *conversationJoinRequestRepository) ListByConversationID(conversationID uuid.UUID, status string, limit, offset int) ([]*models.ConversationJoinRequest, int64, error) {
+	var requests []*models.ConversationJoinRequest
+	var total int64
+
+	db := r.db.Model(&models.ConversationJoinRequest{}).Where("conversation_id = ?", conversationID)
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Preload("User").
+		Order("created_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&requests).Error
+
+	return requests, total, err
+}
+
+func (r *conversationJoinRequestRepository) UpdateStatus(id uuid.UUID, fromStatus, toStatus string, reviewerID *uuid.UUID, reviewedAt *time.Time) (bool, error) {
+	result := r.db.Model(&models.ConversationJoinRequest{}).
+		Where("id = ? AND status = ?", id, fromStatus).
+		Updates(map[string]interface{}{
+			"status":      toStatus,
+			"reviewed_by": reviewerID,
+			"reviewed_at": reviewedAt,
+			"updated_at":  time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
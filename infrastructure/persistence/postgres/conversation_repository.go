@@ -53,6 +53,29 @@ func (r *conversationRepository) GetMembers(conversationID uuid.UUID) ([]*models
 	return members, nil
 }
 
+// GetMemberUserIDs ดึงเฉพาะ user ID ของสมาชิกในการสนทนา
+func (r *conversationRepository) GetMemberUserIDs(conversationID uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.Model(&models.ConversationMember{}).
+		Where("conversation_id = ?", conversationID).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// GetDirectPartnerIDs ดึง user ID ของคู่สนทนาในการสนทนาแบบ direct ทั้งหมดของผู้ใช้
+func (r *conversationRepository) GetDirectPartnerIDs(userID uuid.UUID) ([]uuid.UUID, error) {
+	var partnerIDs []uuid.UUID
+	err := r.db.Model(&models.ConversationMember{}).
+		Where("conversation_id IN (?)", r.db.Model(&models.ConversationMember{}).
+			Select("conversation_members.conversation_id").
+			Joins("JOIN conversations ON conversations.id = conversation_members.conversation_id").
+			Where("conversation_members.user_id = ? AND conversations.type = ?", userID, "direct")).
+		Where("user_id <> ?", userID).
+		Distinct().
+		Pluck("user_id", &partnerIDs).Error
+	return partnerIDs, err
+}
+
 // GetMember ดึงข้อมูลสมาชิกในการสนทนา
 func (r *conversationRepository) GetMember(conversationID, userID uuid.UUID) (*models.ConversationMember, error) {
 	var member models.ConversationMember
@@ -214,6 +237,24 @@ func (r *conversationRepository) SetPinStatus(conversationID, userID uuid.UUID,
 	return nil
 }
 
+// UpdateNotificationSettings บันทึกสถานะปิดเสียงพร้อม notification_settings ของสมาชิก
+func (r *conversationRepository) UpdateNotificationSettings(conversationID, userID uuid.UUID, isMuted bool, settings types.JSONB) error {
+	result := r.db.Model(&models.ConversationMember{}).
+		Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+		Updates(map[string]interface{}{
+			"is_muted":              isMuted,
+			"notification_settings": settings,
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("conversation member not found")
+	}
+	return nil
+}
+
 // SetMuteStatus กำหนดสถานะการปิดเสียงของการสนทนา
 func (r *conversationRepository) SetMuteStatus(conversationID, userID uuid.UUID, isMuted bool) error {
 	result := r.db.Model(&models.ConversationMember{}).
@@ -828,3 +869,79 @@ func (r *conversationRepository) UnhideForAllMembers(conversationID uuid.UUID) e
 	return nil
 }
 
+// CountMembers นับจำนวนสมาชิกในการสนทนา
+func (r *conversationRepository) CountMembers(conversationID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ConversationMember{}).
+		Where("conversation_id = ?", conversationID).
+		Count(&count).Error
+	return count, err
+}
+
+// GetMembersPage ดึงสมาชิกแบบแบ่งหน้าพร้อมข้อมูลผู้ใช้
+func (r *conversationRepository) GetMembersPage(conversationID uuid.UUID, limit, offset int) ([]*models.ConversationMember, error) {
+	var members []*models.ConversationMember
+	err := r.db.Preload("User").
+		Where("conversation_id = ?", conversationID).
+		Order("joined_at ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&members).Error
+	return members, err
+}
+
+// GetMembersByUserIDs ดึงข้อมูลสมาชิกของผู้ใช้หลายคนในคำสั่งเดียว
+func (r *conversationRepository) GetMembersByUserIDs(conversationID uuid.UUID, userIDs []uuid.UUID) ([]*models.ConversationMember, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	var members []*models.ConversationMember
+	err := r.db.Preload("User").
+		Where("conversation_id = ? AND user_id IN ?", conversationID, userIDs).
+		Find(&members).Error
+	return members, err
+}
+
+// GetKeywordAlertMembers ดึงสมาชิกที่ผู้ใช้ตั้ง keyword alert ไว้
+// (เงื่อนไขของ users ตรงกับ partial index idx_users_keyword_alerts ซึ่งมีเฉพาะผู้ใช้ส่วนน้อยที่ตั้ง keyword)
+func (r *conversationRepository) GetKeywordAlertMembers(conversationID uuid.UUID) ([]*models.ConversationMember, error) {
+	var members []*models.ConversationMember
+	err := r.db.Preload("User").
+		Joins("JOIN users ON users.id = conversation_members.user_id").
+		Where("conversation_members.conversation_id = ?", conversationID).
+		Where("jsonb_typeof(users.settings->'keyword_alerts') = 'array' AND users.settings->'keyword_alerts' <> '[]'::jsonb").
+		Find(&members).Error
+	return members, err
+}
+
+// GetChannelNotificationMembers ดึงสมาชิกของช่องที่รับการแจ้งเตือนทุกโพสต์
+// (เงื่อนไขตรงกับ partial index idx_conversation_members_channel_notify จึงไม่ต้องอ่านแถวของผู้ติดตามทั้งหมด)
+func (r *conversationRepository) GetChannelNotificationMembers(conversationID uuid.UUID) ([]*models.ConversationMember, error) {
+	var members []*models.ConversationMember
+	err := r.db.Preload("User").
+		Where("conversation_id = ?", conversationID).
+		Where("(role <> 'subscriber' OR notification_settings->>'level' = 'all')").
+		Find(&members).Error
+	return members, err
+}
+
+// AdvanceMemberLastRead เลื่อน last_read_at แบบมีเงื่อนไข (ป้องกันการนับยอดผู้ชมซ้ำเมื่อมีคำขอพร้อมกัน)
+func (r *conversationRepository) AdvanceMemberLastRead(conversationID, userID uuid.UUID, fromTime *time.Time, toTime time.Time) (bool, error) {
+	query := r.db.Model(&models.ConversationMember{}).
+		Where("conversation_id = ? AND user_id = ?", conversationID, userID)
+	if fromTime == nil {
+		query = query.Where("last_read_at IS NULL")
+	} else {
+		query = query.Where("last_read_at = ?", *fromTime)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"last_read_at": toTime,
+		"updated_at":   time.Now(),
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,91 @@
+// infrastructure/persistence/postgres/conversation_webhook_repository.go
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"gorm.io/gorm"
+)
+
+type conversationWebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewConversationWebhookRepository(db *gorm.DB) repository.ConversationWebhookRepository {
+	return &conversationWebhookRepository{db: db}
+}
+
+func (r *conversationWebhookRepository) Create(webhook *models.ConversationWebhook) error {
+	if webhook.ID == uuid.Nil {
+		webhook.ID = uuid.New()
+	}
+	now := time.Now()
+	if webhook.CreatedAt.IsZero() {
+		webhook.CreatedAt = now
+	}
+	webhook.UpdatedAt = now
+
+	return r.db.Create(webhook).Error
+}
+
+func (r *conversationWebhookRepository) FindByID(id uuid.UUID) (*models.ConversationWebhook, error) {
+	var webhook models.ConversationWebhook
+	if err := r.db.Where("id = ?", id).First(&webhook).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *conversationWebhookRepository) FindByTokenHash(tokenHash string) (*models.ConversationWebhook, error) {
+	var webhook models.ConversationWebhook
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&webhook).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *conversationWebhookRepository) ListByConversationID(conversationID uuid.UUID, includeRevoked bool) ([]*models.ConversationWebhook, error) {
+	var webhooks []*models.ConversationWebhook
+
+	db := r.db.Preload("Creator").Where("conversation_id = ?", conversationID)
+	if !includeRevoked {
+		db = db.Where("revoked_at IS NULL")
+	}
+
+	err := db.Order("created_at DESC").Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *conversationWebhookRepository) CountActiveByConversationID(conversationID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ConversationWebhook{}).
+		Where("conversation_id = ? AND revoked_at IS NULL", conversationID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *conversationWebhookRepository) Revoke(id uuid.UUID, revokedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.ConversationWebhook{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"revoked_at": revokedAt,
+			"updated_at": revokedAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *conversationWebhookRepository) TouchLastUsed(id uuid.UUID, usedAt time.Time) error {
+	return r.db.Model(&models.ConversationWebhook{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", usedAt).Error
+}
@@ -0,0 +1,130 @@
+// infrastructure/persistence/postgres/e2ee_key_repository.go
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// e2eeKeyRepository เป็น implementation ของ E2EEKeyRepository
+type e2eeKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewE2EEKeyRepository สร้าง repository ใหม่
+func NewE2EEKeyRepository(db *gorm.DB) repository.E2EEKeyRepository {
+	return &e2eeKeyRepository{
+		db: db,
+	}
+}
+
+// UpsertBundle บันทึก key bundle ถ้า user+device ซ้ำจะแทนที่ key ทั้งหมด
+func (r *e2eeKeyRepository) UpsertBundle(bundle *models.E2EEKeyBundle) error {
+	bundle.UpdatedAt = time.Now()
+
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "device_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"registration_id", "identity_key", "signed_pre_key_id", "signed_pre_key", "signed_pre_key_signature", "updated_at",
+		}),
+	}).Create(bundle).Error
+	if err != nil {
+		return err
+	}
+
+	// ดึงข้อมูลล่าสุด (ID และ created_at ของแถวเดิมกรณี conflict)
+	return r.db.Where("user_id = ? AND device_id = ?", bundle.UserID, bundle.DeviceID).First(bundle).Error
+}
+
+// GetBundle ดึง key bundle ของอุปกรณ์
+func (r *e2eeKeyRepository) GetBundle(userID uuid.UUID, deviceID string) (*models.E2EEKeyBundle, error) {
+	var bundle models.E2EEKeyBundle
+	if err := r.db.Where("user_id = ? AND device_id = ?", userID, deviceID).First(&bundle).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// GetBundlesByUserID ดึง key bundle ของทุกอุปกรณ์ของผู้ใช้
+func (r *e2eeKeyRepository) GetBundlesByUserID(userID uuid.UUID) ([]*models.E2EEKeyBundle, error) {
+	var bundles []*models.E2EEKeyBundle
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&bundles).Error
+	return bundles, err
+}
+
+// DeleteBundle ลบอุปกรณ์พร้อม one-time pre-key ทั้งหมด
+func (r *e2eeKeyRepository) DeleteBundle(userID uuid.UUID, deviceID string) (bool, error) {
+	var deleted bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND device_id = ?", userID, deviceID).
+			Delete(&models.E2EEOneTimePreKey{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("user_id = ? AND device_id = ?", userID, deviceID).Delete(&models.E2EEKeyBundle{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected > 0
+		return nil
+	})
+	return deleted, err
+}
+
+// AddOneTimePreKeys เพิ่ม one-time pre-key (key_id ที่ซ้ำจะถูกข้าม)
+func (r *e2eeKeyRepository) AddOneTimePreKeys(keys []*models.E2EEOneTimePreKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&keys).Error
+}
+
+// DeleteOneTimePreKeys ลบ one-time pre-key ทั้งหมดของอุปกรณ์
+func (r *e2eeKeyRepository) DeleteOneTimePreKeys(userID uuid.UUID, deviceID string) error {
+	return r.db.Where("user_id = ? AND device_id = ?", userID, deviceID).
+		Delete(&models.E2EEOneTimePreKey{}).Error
+}
+
+// ClaimOneTimePreKey ดึงและลบ one-time pre-key ที่เก่าที่สุดในคำสั่งเดียว
+// (SKIP LOCKED ป้องกันไม่ให้ผู้ส่งสองคนได้ key เดียวกัน)
+func (r *e2eeKeyRepository) ClaimOneTimePreKey(userID uuid.UUID, deviceID string) (*models.E2EEOneTimePreKey, error) {
+	var keys []*models.E2EEOneTimePreKey
+	err := r.db.Raw(`
+		DELETE FROM e2ee_one_time_pre_keys
+		WHERE id = (
+			SELECT id FROM e2ee_one_time_pre_keys
+			WHERE user_id = ? AND device_id = ?
+			ORDER BY key_id ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, userID, deviceID).Scan(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	return keys[0], nil
+}
+
+// CountOneTimePreKeys นับ one-time pre-key ที่เหลือของอุปกรณ์
+func (r *e2eeKeyRepository) CountOneTimePreKeys(userID uuid.UUID, deviceID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.E2EEOneTimePreKey{}).
+		Where("user_id = ? AND device_id = ?", userID, deviceID).
+		Count(&count).Error
+	return count, err
+}
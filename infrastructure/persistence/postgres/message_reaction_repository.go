@@ -0,0 +1,78 @@
+// infrastructure/persistence/postgres/message_reaction_repository.go
+package postgres
+
+import (
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"gorm.io/gorm"
+)
+
+// messageReactionRepository เป็น implementation ของ MessageReactionRepository
+type messageReactionRepository struct {
+	db *gorm.DB
+}
+
+// NewMessageReactionRepository สร้าง repository ใหม่
+func NewMessageReactionRepository(db *gorm.DB) repository.MessageReactionRepository {
+	return &messageReactionRepository{
+		db: db,
+	}
+}
+
+// Create เพิ่มปฏิกิริยา (ป้องกันการซ้ำซ้อน)
+func (r *messageReactionRepository) Create(reaction *models.MessageReaction) (bool, error) {
+	result := r.db.Exec(`
+    INSERT INTO message_reactions (id, message_id, user_id, emoji, created_at)
+    VALUES ($1, $2, $3, $4, $5)
+    ON CONFLICT (message_id, user_id, emoji) DO NOTHING
+`, reaction.ID, reaction.MessageID, reaction.UserID, reaction.Emoji, reaction.CreatedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Delete ลบปฏิกิริยาของผู้ใช้ต่อข้อความ
+func (r *messageReactionRepository) Delete(messageID, userID uuid.UUID, emoji string) (bool, error) {
+	result := r.db.Where("message_id = ? AND user_id = ? AND emoji = ?", messageID, userID, emoji).
+		Delete(&models.MessageReaction{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// GetByMessageID ดึงปฏิกิริยาทั้งหมดของข้อความ เรียงตามเวลาที่กด
+func (r *messageReactionRepository) GetByMessageID(messageID uuid.UUID) ([]*models.MessageReaction, error) {
+	var reactions []*models.MessageReaction
+	err := r.db.Where("message_id = ?", messageID).
+		Order("created_at ASC").
+		Find(&reactions).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reactions, nil
+}
+
+// GetByMessageIDs ดึงปฏิกิริยาของข้อความหลายรายการ เรียงตามเวลาที่กด
+func (r *messageReactionRepository) GetByMessageIDs(messageIDs []uuid.UUID) ([]*models.MessageReaction, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
+	}
+
+	var reactions []*models.MessageReaction
+	err := r.db.Where("message_id IN ?", messageIDs).
+		Order("created_at ASC").
+		Find(&reactions).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reactions, nil
+}
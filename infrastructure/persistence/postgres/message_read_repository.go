@@ -46,10 +46,10 @@ func (r *messageReadRepository) GetByMessageID(messageID uuid.UUID) ([]*models.M
 
 // GetUnreadMessageIDs ดึงรายการ ID ของข้อความที่ยังไม่ได้อ่าน
 func (r *messageReadRepository) GetUnreadMessageIDs(conversationID, userID uuid.UUID) ([]uuid.UUID, error) {
-	// ดึงข้อความทั้งหมดในการสนทนาที่ไม่ได้ส่งโดยผู้ใช้นี้
+	// ดึงข้อความทั้งหมดในการสนทนาที่ไม่ได้ส่งโดยผู้ใช้นี้ (ไม่รวมข้อความในเธรด)
 	subQuery := r.db.Model(&models.Message{}).
 		Select("id").
-		Where("conversation_id = ? AND sender_id != ? AND is_deleted = ?",
+		Where("conversation_id = ? AND thread_root_id IS NULL AND sender_id != ? AND is_deleted = ?",
 			conversationID, userID, false)
 
 	// ดึงข้อความที่ผู้ใช้ยังไม่ได้อ่าน
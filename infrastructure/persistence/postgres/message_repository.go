@@ -38,13 +38,13 @@ func (r *messageRepository) GetByID(id uuid.UUID) (*models.Message, error) {
 // GetMessagesByConversationID ดึงข้อความทั้งหมดในการสนทนา
 func (r *messageRepository) GetMessagesByConversationID(conversationID uuid.UUID, limit, offset int) ([]*models.Message, int64, error) {
 	var count int64
-	if err := r.db.Model(&models.Message{}).Where("conversation_id = ?", conversationID).Count(&count).Error; err != nil {
+	if err := r.db.Model(&models.Message{}).Where("conversation_id = ? AND thread_root_id IS NULL", conversationID).Count(&count).Error; err != nil {
 		return nil, 0, err
 	}
 
 	var messages []*models.Message
-	// Fetch ข้อความล่าสุดก่อน (DESC) แล้วค่อย reverse เป็น ASC
-	if err := r.db.Where("conversation_id = ?", conversationID).
+	// Fetch ข้อความล่าสุดก่อน (DESC) แล้วค่อย reverse เป็น ASC (ไม่รวมข้อความในเธรด)
+	if err := r.db.Where("conversation_id = ? AND thread_root_id IS NULL", conversationID).
 		Order("created_at DESC"). // ดึงข้อความล่าสุดก่อน
 		Limit(limit).
 		Offset(offset).
@@ -70,6 +70,41 @@ func (r *messageRepository) BulkCreate(messages []*models.Message) error {
 	return r.db.CreateInBatches(messages, 100).Error
 }
 
+// ClaimExpiredMessages ลบ (soft delete) ข้อความที่หมดอายุแล้วใน statement เดียว และคืนค่าข้อความก่อนถูกลบ
+// (SKIP LOCKED ทำให้แต่ละข้อความถูกลบโดย instance เดียว จึงไม่ส่ง event หรือลบไฟล์ซ้ำ)
+func (r *messageRepository) ClaimExpiredMessages(before time.Time, limit int) ([]*models.Message, error) {
+	var messages []*models.Message
+	err := r.db.Raw(`
+		WITH expired AS (
+			SELECT * FROM messages
+			WHERE expires_at IS NOT NULL AND expires_at <= ? AND is_deleted = false
+			ORDER BY expires_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE messages m
+		SET is_deleted = true, content = '', media_url = '', media_thumbnail_url = '',
+			album_files = NULL, metadata = '{}'::jsonb, updated_at = ?
+		FROM expired
+		WHERE m.id = expired.id
+		RETURNING expired.*
+	`, before, limit, time.Now()).Scan(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// CountActiveByMediaURL นับข้อความที่ยังใช้ media URL นี้อยู่
+func (r *messageRepository) CountActiveByMediaURL(mediaURL string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Message{}).
+		Where("is_deleted = ? AND (media_url = ? OR media_thumbnail_url = ?)", false, mediaURL, mediaURL).
+		Count(&count).Error
+	return count, err
+}
+
 // GetMessagesByAlbumID ดึงข้อความทั้งหมดในอัลบั้มเดียวกัน
 func (r *messageRepository) GetMessagesByAlbumID(albumID string) ([]*models.Message, error) {
 	var messages []*models.Message
@@ -276,7 +311,7 @@ func (r *messageRepository) UpdateConversationLastMessage(conversationID uuid.UU
 // GetLastMessageByConversation ดึงข้อความล่าสุดของการสนทนา
 func (r *messageRepository) GetLastMessageByConversation(conversationID uuid.UUID) (*models.Message, error) {
 	var message models.Message
-	err := r.db.Where("conversation_id = ?", conversationID).
+	err := r.db.Where("conversation_id = ? AND thread_root_id IS NULL", conversationID).
 		Order("created_at DESC").
 		First(&message).Error
 
@@ -292,7 +327,7 @@ func (r *messageRepository) GetLastMessageByConversation(conversationID uuid.UUI
 // GetLastNonDeletedMessageByConversation ดึงข้อความล่าสุดที่ไม่ถูกลบของการสนทนา
 func (r *messageRepository) GetLastNonDeletedMessageByConversation(conversationID uuid.UUID) (*models.Message, error) {
 	var message models.Message
-	err := r.db.Where("conversation_id = ? AND is_deleted = ?", conversationID, false).
+	err := r.db.Where("conversation_id = ? AND thread_root_id IS NULL AND is_deleted = ?", conversationID, false).
 		Order("created_at DESC").
 		First(&message).Error
 
@@ -316,7 +351,7 @@ func (r *messageRepository) GetMessagesBefore(conversationID, messageID uuid.UUI
 
 	// ดึงข้อความที่เก่ากว่าข้อความเป้าหมาย โดยเรียงจากใหม่ไปเก่า (DESC) ก่อน
 	// ใช้ composite cursor (created_at + id) เพื่อป้องกัน overlap เมื่อมี messages ที่มี timestamp เดียวกัน
-	if err := r.db.Where("conversation_id = ? AND (created_at < ? OR (created_at = ? AND id < ?))",
+	if err := r.db.Where("conversation_id = ? AND thread_root_id IS NULL AND (created_at < ? OR (created_at = ? AND id < ?))",
 		conversationID, targetMessage.CreatedAt, targetMessage.CreatedAt, messageID).
 		Order("created_at DESC, id DESC"). // Query DESC ก่อน
 		Limit(limit).
@@ -343,7 +378,7 @@ func (r *messageRepository) GetMessagesAfter(conversationID, messageID uuid.UUID
 
 	// ดึงข้อความที่ใหม่กว่าข้อความเป้าหมาย โดยเรียงจากเก่าไปใหม่ (ASC)
 	// ใช้ composite cursor (created_at + id) เพื่อป้องกัน overlap เมื่อมี messages ที่มี timestamp เดียวกัน
-	if err := r.db.Where("conversation_id = ? AND (created_at > ? OR (created_at = ? AND id > ?))",
+	if err := r.db.Where("conversation_id = ? AND thread_root_id IS NULL AND (created_at > ? OR (created_at = ? AND id > ?))",
 		conversationID, targetMessage.CreatedAt, targetMessage.CreatedAt, messageID).
 		Order("created_at ASC, id ASC"). // ✅ Query ASC (เก่า → ใหม่)
 		Limit(limit).
@@ -354,10 +389,32 @@ func (r *messageRepository) GetMessagesAfter(conversationID, messageID uuid.UUID
 	return messages, nil
 }
 
+// CountMessagesAfterTime นับข้อความหลังเวลาที่กำหนดที่ไม่ใช่ของผู้ใช้และไม่ถูกลบ
+func (r *messageRepository) CountMessagesAfterTime(conversationID uuid.UUID, afterTime time.Time, excludeUserID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Message{}).
+		Where("conversation_id = ? AND thread_root_id IS NULL AND created_at > ? AND is_deleted = ? AND (sender_id IS NULL OR sender_id != ?)",
+			conversationID, afterTime, false, excludeUserID).
+		Count(&count).Error
+	return count, err
+}
+
+// GetMessageIDsAfterTime ดึง ID ของข้อความหลังเวลาที่กำหนดที่ไม่ใช่ของผู้ใช้และไม่ถูกลบ (เก่าไปใหม่)
+func (r *messageRepository) GetMessageIDsAfterTime(conversationID uuid.UUID, afterTime time.Time, excludeUserID uuid.UUID, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&models.Message{}).
+		Where("conversation_id = ? AND thread_root_id IS NULL AND created_at > ? AND is_deleted = ? AND (sender_id IS NULL OR sender_id != ?)",
+			conversationID, afterTime, false, excludeUserID).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
 // CountAllMessages นับจำนวนข้อความทั้งหมดในการสนทนา
 func (r *messageRepository) CountAllMessages(conversationID uuid.UUID) (int64, error) {
 	var count int64
-	err := r.db.Model(&models.Message{}).Where("conversation_id = ?", conversationID).Count(&count).Error
+	err := r.db.Model(&models.Message{}).Where("conversation_id = ? AND thread_root_id IS NULL", conversationID).Count(&count).Error
 	return count, err
 }
 
@@ -366,7 +423,7 @@ func (r *messageRepository) GetMessagesAfterTime(conversationID uuid.UUID, after
 	var messages []*models.Message
 
 	// ดึงข้อความที่สร้างหลังเวลาที่กำหนด ไม่ใช่ของผู้ใช้ที่กำหนด และไม่ถูกลบ
-	err := r.db.Where("conversation_id = ? AND created_at > ? AND sender_id != ? AND is_deleted = ?",
+	err := r.db.Where("conversation_id = ? AND thread_root_id IS NULL AND created_at > ? AND sender_id != ? AND is_deleted = ?",
 		conversationID, afterTime, excludeUserID, false).
 		Find(&messages).Error
 
@@ -377,7 +434,7 @@ func (r *messageRepository) GetAllUnreadMessages(conversationID uuid.UUID, exclu
 	var messages []*models.Message
 
 	// ดึงข้อความทั้งหมดในการสนทนาที่ไม่ใช่ของผู้ใช้ที่กำหนด และไม่ถูกลบ
-	err := r.db.Where("conversation_id = ? AND sender_id != ? AND is_deleted = ?",
+	err := r.db.Where("conversation_id = ? AND thread_root_id IS NULL AND sender_id != ? AND is_deleted = ?",
 		conversationID, excludeUserID, false).
 		Find(&messages).Error
 
@@ -677,6 +734,7 @@ func (r *messageRepository) SearchMessages(
 	// ใช้ ILIKE แทน full-text search เพื่อรองรับภาษาไทย (ไม่มี word boundaries)
 	baseQuery := r.db.Model(&models.Message{}).
 		Where("is_deleted = ?", false).
+		Where("message_type <> ?", models.MessageTypeEncrypted). // ciphertext ค้นหาไม่ได้
 		Where("content ILIKE ?", "%"+searchQuery+"%")
 
 	// Filter by conversation if specified
@@ -759,3 +817,116 @@ func (r *messageRepository) SearchMessages(
 	return messages, nextCursor, hasMore, nil
 }
 
+
+// GetLatestThreadReplies ดึงข้อความล่าสุดในเธรด
+func (r *messageRepository) GetLatestThreadReplies(threadRootID uuid.UUID, limit int) ([]*models.Message, error) {
+	var messages []*models.Message
+
+	// Query DESC เพื่อเอาข้อความล่าสุด แล้ว reverse เป็น ASC
+	if err := r.db.Where("thread_root_id = ?", threadRootID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&messages).Error; err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+
+	return messages, nil
+}
+
+// GetThreadRepliesBefore ดึงข้อความในเธรดที่เก่ากว่า ID ที่ระบุ
+func (r *messageRepository) GetThreadRepliesBefore(threadRootID, messageID uuid.UUID, limit int) ([]*models.Message, error) {
+	var targetMessage models.Message
+	if err := r.db.First(&targetMessage, "id = ? AND thread_root_id = ?", messageID, threadRootID).Error; err != nil {
+		return nil, err
+	}
+
+	var messages []*models.Message
+
+	// ใช้ composite cursor (created_at + id) แบบเดียวกับ GetMessagesBefore
+	if err := r.db.Where("thread_root_id = ? AND (created_at < ? OR (created_at = ? AND id < ?))",
+		threadRootID, targetMessage.CreatedAt, targetMessage.CreatedAt, messageID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&messages).Error; err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+
+	return messages, nil
+}
+
+// GetThreadRepliesAfter ดึงข้อความในเธรดที่ใหม่กว่า ID ที่ระบุ
+func (r *messageRepository) GetThreadRepliesAfter(threadRootID, messageID uuid.UUID, limit int) ([]*models.Message, error) {
+	var targetMessage models.Message
+	if err := r.db.First(&targetMessage, "id = ? AND thread_root_id = ?", messageID, threadRootID).Error; err != nil {
+		return nil, err
+	}
+
+	var messages []*models.Message
+
+	// ใช้ composite cursor (created_at + id) แบบเดียวกับ GetMessagesAfter
+	if err := r.db.Where("thread_root_id = ? AND (created_at > ? OR (created_at = ? AND id > ?))",
+		threadRootID, targetMessage.CreatedAt, targetMessage.CreatedAt, messageID).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&messages).Error; err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// CountThreadRepliesAfterTime นับข้อความในเธรดที่ยังไม่ได้อ่าน
+func (r *messageRepository) CountThreadRepliesAfterTime(threadRootID uuid.UUID, afterTime *time.Time, excludeUserID uuid.UUID) (int64, error) {
+	var count int64
+
+	query := r.db.Model(&models.Message{}).
+		Where("thread_root_id = ? AND is_deleted = ? AND (sender_id IS NULL OR sender_id != ?)",
+			threadRootID, false, excludeUserID)
+
+	// ถ้ายังไม่เคยอ่านเธรด ให้นับทุกข้อความ
+	if afterTime != nil {
+		query = query.Where("created_at > ?", *afterTime)
+	}
+
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// RefreshThreadStats คำนวณสถิติของเธรดใหม่จากข้อความจริง (รองรับกรณีข้อความในเธรดถูกลบ)
+func (r *messageRepository) RefreshThreadStats(threadRootID uuid.UUID) error {
+	return r.db.Exec(`
+    UPDATE messages SET
+        thread_reply_count = (
+            SELECT COUNT(*) FROM messages
+            WHERE thread_root_id = $1 AND is_deleted = false
+        ),
+        thread_last_reply_id = (
+            SELECT id FROM messages
+            WHERE thread_root_id = $1 AND is_deleted = false
+            ORDER BY created_at DESC, id DESC LIMIT 1
+        ),
+        thread_last_reply_at = (
+            SELECT MAX(created_at) FROM messages
+            WHERE thread_root_id = $1 AND is_deleted = false
+        )
+    WHERE id = $1
+`, threadRootID).Error
+}
+
+// IncrementViewCounts เพิ่มยอดผู้ชมของข้อความในช่วงเวลาที่ผู้ชมอ่านผ่าน (ไม่แตะ updated_at เพื่อไม่ให้ทุกการเปิดอ่านกลายเป็น delta sync)
+func (r *messageRepository) IncrementViewCounts(conversationID, viewerID uuid.UUID, afterTime, upToTime time.Time) (int64, error) {
+	result := r.db.Model(&models.Message{}).
+		Where("conversation_id = ? AND thread_root_id IS NULL AND is_deleted = ? AND created_at > ? AND created_at <= ?",
+			conversationID, false, afterTime, upToTime).
+		Where("(sender_id IS NULL OR sender_id <> ?)", viewerID).
+		UpdateColumn("view_count", gorm.Expr("view_count + 1"))
+	return result.RowsAffected, result.Error
+}
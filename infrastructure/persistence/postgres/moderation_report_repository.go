@@ -0,0 +1,131 @@
+// infrastructure/persistence/postgres/moderation_report_repository.go
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+	"gorm.io/gorm"
+)
+
+type moderationReportRepository struct {
+	db *gorm.DB
+}
+
+func NewModerationReportRepository(db *gorm.DB) repository.ModerationReportRepository {
+	return &moderationReportRepository{db: db}
+}
+
+func (r *moderationReportRepository) Create(report *models.ModerationReport) error {
+	if report.ID == uuid.Nil {
+		report.ID = uuid.New()
+	}
+	now := time.Now()
+	if report.CreatedAt.IsZero() {
+		report.CreatedAt = now
+	}
+	report.UpdatedAt = now
+	if report.Status == "" {
+		report.Status = models.ReportStatusOpen
+	}
+	if report.Source == "" {
+		report.Source = models.ReportSourceUser
+	}
+	if report.Snapshot == nil {
+		report.Snapshot = types.JSONB{}
+	}
+
+	return r.db.Create(report).Error
+}
+
+func (r *moderationReportRepository) FindByID(id uuid.UUID) (*models.ModerationReport, error) {
+	var report models.ModerationReport
+	err := r.db.Preload("Reporter").Preload("Assignee").
+		Where("id = ?", id).
+		First(&report).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (r *moderationReportRepository) HasOpenReport(reporterID *uuid.UUID, targetType string, targetID uuid.UUID) (bool, error) {
+	var count int64
+	db := r.db.Model(&models.ModerationReport{})
+	if reporterID != nil {
+		db = db.Where("reporter_id = ?", *reporterID)
+	} else {
+		db = db.Where("reporter_id IS NULL")
+	}
+	err := db.Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Where("status IN ?", []string{models.ReportStatusOpen, models.ReportStatusInReview}).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *moderationReportRepository) List(filter repository.ModerationReportFilter) ([]*models.ModerationReport, int64, error) {
+	var reports []*models.ModerationReport
+	var total int64
+
+	db := r.db.Model(&models.ModerationReport{})
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	if filter.TargetType != "" {
+		db = db.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.Reason != "" {
+		db = db.Where("reason = ?", filter.Reason)
+	}
+	if filter.Source != "" {
+		db = db.Where("source = ?", filter.Source)
+	}
+	if filter.TargetID != nil {
+		db = db.Where("target_id = ?", *filter.TargetID)
+	}
+	if filter.AssigneeID != nil {
+		db = db.Where("assignee_id = ?", *filter.AssigneeID)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Preload("Reporter").Preload("Assignee").
+		Order("created_at ASC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&reports).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return reports, total, nil
+}
+
+func (r *moderationReportRepository) Update(report *models.ModerationReport) error {
+	report.UpdatedAt = time.Now()
+	return r.db.Omit("Reporter", "Assignee").Save(report).Error
+}
+
+func (r *moderationReportRepository) ResolveOpenByTarget(targetType string, targetID, excludeID uuid.UUID, action, note string, resolvedBy uuid.UUID, resolvedAt time.Time) (int64, error) {
+	result := r.db.Model(&models.ModerationReport{}).
+		Where("target_type = ? AND target_id = ? AND id <> ?", targetType, targetID, excludeID).
+		Where("status IN ?", []string{models.ReportStatusOpen, models.ReportStatusInReview}).
+		Updates(map[string]interface{}{
+			"status":          models.ReportStatusResolved,
+			"action":          action,
+			"resolution_note": note,
+			"resolved_by":     resolvedBy,
+			"resolved_at":     resolvedAt,
+			"updated_at":      resolvedAt,
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,112 @@
+// infrastructure/persistence/postgres/notification_repository.go
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"gorm.io/gorm"
+)
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository สร้าง repository สำหรับกล่องแจ้งเตือน
+func NewNotificationRepository(db *gorm.DB) repository.NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) CreateBatch(notifications []*models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(notifications, 100).Error
+}
+
+func (r *notificationRepository) ListByUserID(userID uuid.UUID, limit int, cursor *string, unreadOnly bool) ([]*models.Notification, *string, bool, error) {
+	var notifications []*models.Notification
+
+	query := r.db.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	if cursor != nil && *cursor != "" {
+		cursorID, err := uuid.Parse(*cursor)
+		if err != nil {
+			return nil, nil, false, errors.New("invalid cursor")
+		}
+
+		var cursorNotification models.Notification
+		if err := r.db.Where("id = ? AND user_id = ?", cursorID, userID).First(&cursorNotification).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil, false, errors.New("cursor not found")
+			}
+			return nil, nil, false, err
+		}
+
+		query = query.Where(
+			"(created_at < ?) OR (created_at = ? AND id < ?)",
+			cursorNotification.CreatedAt, cursorNotification.CreatedAt, cursorID,
+		)
+	}
+
+	// ดึง limit + 1 เพื่อตรวจว่ามีหน้าถัดไปหรือไม่
+	if err := query.
+		Preload("Actor").
+		Order("created_at DESC, id DESC").
+		Limit(limit + 1).
+		Find(&notifications).Error; err != nil {
+		return nil, nil, false, err
+	}
+
+	hasMore := len(notifications) > limit
+	if hasMore {
+		notifications = notifications[:limit]
+	}
+
+	var nextCursor *string
+	if len(notifications) > 0 {
+		lastID := notifications[len(notifications)-1].ID.String()
+		nextCursor = &lastID
+	}
+
+	return notifications, nextCursor, hasMore, nil
+}
+
+func (r *notificationRepository) CountUnread(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *notificationRepository) MarkRead(userID uuid.UUID, ids []uuid.UUID, readAt time.Time) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND id IN ? AND read_at IS NULL", userID, ids).
+		Update("read_at", readAt)
+	return result.RowsAffected, result.Error
+}
+
+func (r *notificationRepository) MarkAllRead(userID uuid.UUID, readAt time.Time) (int64, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", readAt)
+	return result.RowsAffected, result.Error
+}
+
+func (r *notificationRepository) Delete(userID, id uuid.UUID) (bool, error) {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Notification{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
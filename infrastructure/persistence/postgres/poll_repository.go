@@ -0,0 +1,161 @@
+// infrastructure/persistence/postgres/poll_repository.go
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"gorm.io/gorm"
+)
+
+// pollRepository เป็น implementation ของ PollRepository
+type pollRepository struct {
+	db *gorm.DB
+}
+
+// NewPollRepository สร้าง repository ใหม่
+func NewPollRepository(db *gorm.DB) repository.PollRepository {
+	return &pollRepository{
+		db: db,
+	}
+}
+
+// Create สร้างโพลพร้อมตัวเลือก
+func (r *pollRepository) Create(poll *models.Poll) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		options := poll.Options
+		poll.Options = nil
+
+		if err := tx.Create(poll).Error; err != nil {
+			return err
+		}
+
+		for _, option := range options {
+			option.PollID = poll.ID
+		}
+		if len(options) > 0 {
+			if err := tx.Create(&options).Error; err != nil {
+				return err
+			}
+		}
+
+		poll.Options = options
+		return nil
+	})
+}
+
+// GetByID ดึงโพลตาม ID
+func (r *pollRepository) GetByID(id uuid.UUID) (*models.Poll, error) {
+	return r.findOne("id = ?", id)
+}
+
+// GetByMessageID ดึงโพลของข้อความ
+func (r *pollRepository) GetByMessageID(messageID uuid.UUID) (*models.Poll, error) {
+	return r.findOne("message_id = ?", messageID)
+}
+
+// findOne ดึงโพลหนึ่งรายการพร้อมตัวเลือก
+func (r *pollRepository) findOne(query string, args ...interface{}) (*models.Poll, error) {
+	var poll models.Poll
+	err := r.db.Preload("Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Where(query, args...).First(&poll).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &poll, nil
+}
+
+// Close ปิดโพล
+func (r *pollRepository) Close(pollID, closedBy uuid.UUID, closedAt time.Time) error {
+	return r.db.Model(&models.Poll{}).
+		Where("id = ?", pollID).
+		Updates(map[string]interface{}{
+			"is_closed":  true,
+			"closed_at":  closedAt,
+			"closed_by":  closedBy,
+			"updated_at": closedAt,
+		}).Error
+}
+
+// GetVotes ดึงการโหวตทั้งหมดของโพล
+func (r *pollRepository) GetVotes(pollID uuid.UUID) ([]*models.PollVote, error) {
+	var votes []*models.PollVote
+	err := r.db.Where("poll_id = ?", pollID).
+		Order("created_at ASC").
+		Find(&votes).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return votes, nil
+}
+
+// CastVotes บันทึกการโหวตของผู้ใช้
+// ล็อกแถวโพลแบบ FOR SHARE แล้วตรวจว่ายังเปิดอยู่ (Close ต้องรอ transaction นี้ จึงไม่มีโหวตเข้ามาหลังปิดโพล)
+// โพลแบบข้อเดียวล็อกต่อ (โพล, ผู้ใช้) ก่อนลบ/เพิ่ม เพื่อไม่ให้คำขอพร้อมกันต่างคนต่างเพิ่มจนมีหลายโหวต
+// (FOR UPDATE ใช้ไม่ได้เมื่อยังไม่มีแถวโหวต) ส่วนโพลแบบหลายข้อเพิ่มด้วย ON CONFLICT DO NOTHING จึงไม่ลบโหวตของคำขออื่น
+func (r *pollRepository) CastVotes(pollID, userID uuid.UUID, optionIDs []uuid.UUID, replace bool, now time.Time) (bool, error) {
+	opened := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var open int64
+		if err := tx.Raw(`
+    SELECT COUNT(*) FROM (
+        SELECT id FROM polls
+        WHERE id = ? AND is_closed = false AND (closes_at IS NULL OR closes_at > ?)
+        FOR SHARE
+    ) AS open_poll
+`, pollID, now).Scan(&open).Error; err != nil {
+			return err
+		}
+		if open == 0 {
+			return nil
+		}
+		opened = true
+
+		if replace {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "poll_vote:"+pollID.String()+":"+userID.String()).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("poll_id = ? AND user_id = ?", pollID, userID).
+				Delete(&models.PollVote{}).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, optionID := range optionIDs {
+			if err := tx.Exec(`
+    INSERT INTO poll_votes (id, poll_id, option_id, user_id, created_at)
+    VALUES ($1, $2, $3, $4, $5)
+    ON CONFLICT (option_id, user_id) DO NOTHING
+`, uuid.New(), pollID, optionID, userID, now).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return opened, nil
+}
+
+// DeleteUserVotes ลบการโหวตของผู้ใช้
+func (r *pollRepository) DeleteUserVotes(pollID, userID uuid.UUID, optionIDs []uuid.UUID) (int64, error) {
+	query := r.db.Where("poll_id = ? AND user_id = ?", pollID, userID)
+	if len(optionIDs) > 0 {
+		query = query.Where("option_id IN ?", optionIDs)
+	}
+
+	result := query.Delete(&models.PollVote{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,186 @@
+// infrastructure/persistence/postgres/push_repository.go
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// deviceTokenRepository เป็น implementation ของ DeviceTokenRepository
+type deviceTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewDeviceTokenRepository สร้าง repository ใหม่
+func NewDeviceTokenRepository(db *gorm.DB) repository.DeviceTokenRepository {
+	return &deviceTokenRepository{
+		db: db,
+	}
+}
+
+// Upsert บันทึก token ถ้า provider+token ซ้ำจะอัปเดตเจ้าของและเปิดใช้งานอีกครั้ง
+func (r *deviceTokenRepository) Upsert(token *models.DeviceToken) error {
+	token.IsActive = true
+	token.UpdatedAt = time.Now()
+
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "provider"}, {Name: "token"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"user_id", "platform", "device_name", "webpush_p256dh", "webpush_auth", "is_active", "updated_at",
+		}),
+	}).Create(token).Error
+	if err != nil {
+		return err
+	}
+
+	// ดึงข้อมูลล่าสุด (ID และ created_at ของแถวเดิมกรณี conflict)
+	return r.db.Where("provider = ? AND token = ?", token.Provider, token.Token).First(token).Error
+}
+
+// GetByID ดึง token ตาม ID
+func (r *deviceTokenRepository) GetByID(id uuid.UUID) (*models.DeviceToken, error) {
+	var token models.DeviceToken
+	if err := r.db.Where("id = ?", id).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// GetActiveByUserID ดึง token ที่ใช้งานอยู่ของผู้ใช้
+func (r *deviceTokenRepository) GetActiveByUserID(userID uuid.UUID) ([]*models.DeviceToken, error) {
+	var tokens []*models.DeviceToken
+	err := r.db.Where("user_id = ? AND is_active = ?", userID, true).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	return tokens, err
+}
+
+// GetActiveByUserIDs ดึง token ที่ใช้งานอยู่ของผู้ใช้หลายคน
+func (r *deviceTokenRepository) GetActiveByUserIDs(userIDs []uuid.UUID) ([]*models.DeviceToken, error) {
+	var tokens []*models.DeviceToken
+	if len(userIDs) == 0 {
+		return tokens, nil
+	}
+	err := r.db.Where("user_id IN ? AND is_active = ?", userIDs, true).Find(&tokens).Error
+	return tokens, err
+}
+
+// Delete ลบ token ของผู้ใช้
+func (r *deviceTokenRepository) Delete(id, userID uuid.UUID) (bool, error) {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.DeviceToken{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Deactivate ปิดการใช้งาน token (เช่น provider แจ้งว่า token หมดอายุ)
+func (r *deviceTokenRepository) Deactivate(id uuid.UUID) error {
+	return r.db.Model(&models.DeviceToken{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"is_active":  false,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// TouchLastUsed บันทึกเวลาที่ส่ง push สำเร็จล่าสุด
+func (r *deviceTokenRepository) TouchLastUsed(id uuid.UUID, usedAt time.Time) error {
+	return r.db.Model(&models.DeviceToken{}).
+		Where("id = ?", id).
+		Update("last_used_at", usedAt).Error
+}
+
+// pushDeliveryRepository เป็น implementation ของ PushDeliveryRepository
+type pushDeliveryRepository struct {
+	db *gorm.DB
+}
+
+// NewPushDeliveryRepository สร้าง repository ใหม่
+func NewPushDeliveryRepository(db *gorm.DB) repository.PushDeliveryRepository {
+	return &pushDeliveryRepository{
+		db: db,
+	}
+}
+
+// Create บันทึกการส่งใหม่
+func (r *pushDeliveryRepository) Create(delivery *models.PushDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+// Update บันทึกผลการส่ง
+func (r *pushDeliveryRepository) Update(delivery *models.PushDelivery) error {
+	delivery.UpdatedAt = time.Now()
+	return r.db.Model(&models.PushDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":        delivery.Status,
+			"attempts":      delivery.Attempts,
+			"last_error":    delivery.LastError,
+			"next_retry_at": delivery.NextRetryAt,
+			"sent_at":       delivery.SentAt,
+			"updated_at":    delivery.UpdatedAt,
+		}).Error
+}
+
+// ClaimDueRetries จองรายการที่ถึงเวลา retry พร้อม device token
+// (SKIP LOCKED + lease ป้องกันไม่ให้หลาย instance ส่ง push เดียวกันซ้ำ)
+func (r *pushDeliveryRepository) ClaimDueRetries(now time.Time, lease time.Duration, limit int) ([]*models.PushDelivery, error) {
+	var claimedIDs []uuid.UUID
+	err := r.db.Raw(`
+		UPDATE push_deliveries
+		SET next_retry_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM push_deliveries
+			WHERE status = ? AND next_retry_at IS NOT NULL AND next_retry_at <= ?
+			ORDER BY next_retry_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id
+	`, now.Add(lease), now, models.PushDeliveryPending, now, limit).Scan(&claimedIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(claimedIDs) == 0 {
+		return nil, nil
+	}
+
+	var deliveries []*models.PushDelivery
+	err = r.db.Preload("DeviceToken").
+		Where("id IN ?", claimedIDs).
+		Order("created_at ASC").
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// GetByUserID ดึงประวัติการส่งของผู้ใช้
+func (r *pushDeliveryRepository) GetByUserID(userID uuid.UUID, status string, limit, offset int) ([]*models.PushDelivery, int64, error) {
+	var deliveries []*models.PushDelivery
+	var total int64
+
+	query := r.db.Model(&models.PushDelivery{}).Where("user_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
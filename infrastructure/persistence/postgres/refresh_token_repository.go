@@ -47,3 +47,54 @@ func (r *refreshTokenRepository) RevokeByUserID(userID uuid.UUID) error {
 func (r *refreshTokenRepository) DeleteExpired(before time.Time) error {
 	return r.db.Where("expires_at < ?", before).Delete(&models.RefreshToken{}).Error
 }
+
+func (r *refreshTokenRepository) FindByID(id uuid.UUID) (*models.RefreshToken, error) {
+	var refreshToken models.RefreshToken
+	err := r.db.Where("id = ?", id).First(&refreshToken).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &refreshToken, nil
+}
+
+func (r *refreshTokenRepository) FindActiveByUserID(userID uuid.UUID, now time.Time) ([]*models.RefreshToken, error) {
+	var sessions []*models.RefreshToken
+	err := r.db.Where("user_id = ? AND revoked = ? AND expires_at > ?", userID, false, now).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *refreshTokenRepository) Rotate(session *models.RefreshToken, oldToken string) (bool, error) {
+	result := r.db.Model(&models.RefreshToken{}).
+		Where("id = ? AND token = ? AND revoked = ?", session.ID, oldToken, false).
+		Updates(map[string]interface{}{
+			"token":        session.Token,
+			"expires_at":   session.ExpiresAt,
+			"last_used_at": session.LastUsedAt,
+			"ip_address":   session.IPAddress,
+			"user_agent":   session.UserAgent,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *refreshTokenRepository) RevokeByIDs(ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.RefreshToken{}).
+		Where("id IN ?", ids).
+		Update("revoked", true).Error
+}
+
+func (r *refreshTokenRepository) MarkTwoFactorVerified(id uuid.UUID) error {
+	return r.db.Model(&models.RefreshToken{}).
+		Where("id = ?", id).
+		Update("two_factor_verified", true).Error
+}
@@ -0,0 +1,118 @@
+// infrastructure/persistence/postgres/sync_repository.go
+package postgres
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"gorm.io/gorm"
+)
+
+type syncRepository struct {
+	db *gorm.DB
+}
+
+// NewSyncRepository สร้าง repository ใหม่
+func NewSyncRepository(db *gorm.DB) repository.SyncRepository {
+	return &syncRepository{
+		db: db,
+	}
+}
+
+// memberConversations subquery ของการสนทนาที่ผู้ใช้เป็นสมาชิก
+func (r *syncRepository) memberConversations(userID uuid.UUID) *gorm.DB {
+	return r.db.Model(&models.ConversationMember{}).
+		Select("conversation_id").
+		Where("user_id = ?", userID)
+}
+
+// memberListConversations subquery ของการสนทนาที่ผู้ใช้ดูรายชื่อสมาชิกได้ (กฎเดียวกับ API รายชื่อสมาชิก)
+// ช่อง (channel) ซ่อนรายชื่อผู้ติดตาม ยกเว้น owner/admin ของช่อง
+func (r *syncRepository) memberListConversations(userID uuid.UUID) *gorm.DB {
+	return r.db.Table("conversation_members AS cm").
+		Select("cm.conversation_id").
+		Joins("JOIN conversations c ON c.id = cm.conversation_id").
+		Where("cm.user_id = ? AND (c.type <> ? OR cm.role IN ?)",
+			userID, models.ConversationTypeChannel, []models.MemberRole{models.RoleOwner, models.RoleAdmin})
+}
+
+// window จำกัดช่วง cursor และเรียงลำดับตาม (column, id)
+func window(query *gorm.DB, column string, after repository.SyncCursor, until time.Time, limit int) *gorm.DB {
+	return query.
+		Where("("+column+", id) > (?, ?)", after.At, after.ID).
+		Where(column+" <= ?", until).
+		Order(column + " ASC, id ASC").
+		Limit(limit)
+}
+
+func (r *syncRepository) GetChangedMessages(userID uuid.UUID, after repository.SyncCursor, until time.Time, limit int) ([]*models.Message, error) {
+	var messages []*models.Message
+	query := r.db.Where("conversation_id IN (?)", r.memberConversations(userID))
+	err := window(query, "updated_at", after, until, limit).Find(&messages).Error
+	return messages, err
+}
+
+func (r *syncRepository) GetChangedConversations(userID uuid.UUID, after repository.SyncCursor, until time.Time, limit int) ([]*models.Conversation, error) {
+	var conversations []*models.Conversation
+	query := r.db.Where("id IN (?)", r.memberConversations(userID))
+	err := window(query, "updated_at", after, until, limit).Find(&conversations).Error
+	return conversations, err
+}
+
+// GetChangedMembers สมาชิกที่เปลี่ยนแปลง (การสนทนาที่ดูรายชื่อสมาชิกไม่ได้ ได้เฉพาะแถวของผู้ใช้เอง)
+func (r *syncRepository) GetChangedMembers(userID uuid.UUID, after repository.SyncCursor, until time.Time, limit int) ([]*models.ConversationMember, error) {
+	var members []*models.ConversationMember
+	query := r.db.Where("user_id = ? OR conversation_id IN (?)", userID, r.memberListConversations(userID))
+	err := window(query, "updated_at", after, until, limit).Find(&members).Error
+	return members, err
+}
+
+func (r *syncRepository) GetActivities(userID uuid.UUID, after repository.SyncCursor, until time.Time, limit int) ([]*models.GroupActivity, error) {
+	var activities []*models.GroupActivity
+	query := r.db.
+		Preload("Actor").
+		Preload("Target").
+		Where("conversation_id IN (?) OR target_id = ?", r.memberConversations(userID), userID)
+	err := window(query, "created_at", after, until, limit).Find(&activities).Error
+	return activities, err
+}
+
+func (r *syncRepository) GetChangedFriendships(userID uuid.UUID, after repository.SyncCursor, until time.Time, limit int) ([]*models.UserFriendship, error) {
+	var friendships []*models.UserFriendship
+	query := r.db.Where("user_id = ? OR friend_id = ?", userID, userID)
+	err := window(query, "updated_at", after, until, limit).Find(&friendships).Error
+	return friendships, err
+}
+
+func (r *syncRepository) GetChangedPins(userID uuid.UUID, after repository.SyncCursor, until time.Time, limit int) ([]*models.PinnedMessage, error) {
+	var pins []*models.PinnedMessage
+	query := r.db.Where("(pin_type = ? AND user_id = ?) OR (pin_type = ? AND conversation_id IN (?))",
+		models.PinTypePersonal, userID, models.PinTypePublic, r.memberConversations(userID))
+	err := window(query, "updated_at", after, until, limit).Find(&pins).Error
+	return pins, err
+}
+
+func (r *syncRepository) GetChangedNotes(userID uuid.UUID, after repository.SyncCursor, until time.Time, limit int) ([]*models.Note, error) {
+	var notes []*models.Note
+	query := r.db.Where("user_id = ? OR (visibility = ? AND conversation_id IN (?))",
+		userID, models.NoteVisibilityShared, r.memberConversations(userID))
+	err := window(query, "updated_at", after, until, limit).Find(&notes).Error
+	return notes, err
+}
+
+func (r *syncRepository) GetTombstones(userID uuid.UUID, after repository.SyncCursor, until time.Time, limit int) ([]*models.SyncTombstone, error) {
+	var tombstones []*models.SyncTombstone
+	// tombstone ของสมาชิกส่งเฉพาะการสนทนาที่ดูรายชื่อสมาชิกได้ (ช่องบันทึกแบบไม่ shared แต่ owner/admin ยังต้องได้รับ)
+	query := r.db.Where("user_id = ? OR (shared = ? AND entity_type <> ? AND conversation_id IN (?)) OR (entity_type = ? AND conversation_id IN (?))",
+		userID, true, models.SyncEntityMember, r.memberConversations(userID),
+		models.SyncEntityMember, r.memberListConversations(userID))
+	err := window(query, "deleted_at", after, until, limit).Find(&tombstones).Error
+	return tombstones, err
+}
+
+func (r *syncRepository) DeleteTombstonesBefore(before time.Time) (int64, error) {
+	result := r.db.Where("deleted_at < ?", before).Delete(&models.SyncTombstone{})
+	return result.RowsAffected, result.Error
+}
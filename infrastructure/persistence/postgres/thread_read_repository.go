@@ -0,0 +1,51 @@
+// infrastructure/persistence/postgres/thread_read_repository.go
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"gorm.io/gorm"
+)
+
+// threadReadRepository เป็น implementation ของ ThreadReadRepository
+type threadReadRepository struct {
+	db *gorm.DB
+}
+
+// NewThreadReadRepository สร้าง repository ใหม่
+func NewThreadReadRepository(db *gorm.DB) repository.ThreadReadRepository {
+	return &threadReadRepository{
+		db: db,
+	}
+}
+
+// MarkRead บันทึกเวลาอ่านล่าสุดของเธรด
+func (r *threadReadRepository) MarkRead(threadRootID, userID uuid.UUID, readAt time.Time) error {
+	// ใช้ GREATEST เพื่อไม่ให้เวลาอ่านย้อนกลับเมื่อมี request มาไม่เรียงลำดับ
+	return r.db.Exec(`
+    INSERT INTO thread_reads (id, thread_root_id, user_id, last_read_at)
+    VALUES ($1, $2, $3, $4)
+    ON CONFLICT (thread_root_id, user_id)
+    DO UPDATE SET last_read_at = GREATEST(thread_reads.last_read_at, EXCLUDED.last_read_at)
+`, uuid.New(), threadRootID, userID, readAt).Error
+}
+
+// GetLastReadAt ดึงเวลาอ่านล่าสุดของเธรด
+func (r *threadReadRepository) GetLastReadAt(threadRootID, userID uuid.UUID) (*time.Time, error) {
+	var threadRead models.ThreadRead
+	err := r.db.Where("thread_root_id = ? AND user_id = ?", threadRootID, userID).
+		First(&threadRead).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &threadRead.LastReadAt, nil
+}
@@ -47,6 +47,18 @@ func (r *tokenBlacklistRepository) IsTokenBlacklisted(token string) (bool, error
 	return count > 0, nil
 }
 
+func (r *tokenBlacklistRepository) IsAnyTokenBlacklisted(tokens []string) (bool, error) {
+	if len(tokens) == 0 {
+		return false, nil
+	}
+	var count int64
+	err := r.db.Model(&models.TokenBlacklist{}).Where("token IN ?", tokens).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 func (r *tokenBlacklistRepository) DeleteExpired(before time.Time) error {
 	return r.db.Where("expired_at < ?", before).Delete(&models.TokenBlacklist{}).Error
 }
@@ -0,0 +1,64 @@
+// infrastructure/persistence/postgres/two_factor_recovery_code_repository.go
+package postgres
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"gorm.io/gorm"
+)
+
+type twoFactorRecoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorRecoveryCodeRepository(db *gorm.DB) repository.TwoFactorRecoveryCodeRepository {
+	return &twoFactorRecoveryCodeRepository{db: db}
+}
+
+func (r *twoFactorRecoveryCodeRepository) ReplaceForUser(userID uuid.UUID, codes []*models.TwoFactorRecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.TwoFactorRecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, code := range codes {
+			if code.ID == uuid.Nil {
+				code.ID = uuid.New()
+			}
+			if code.CreatedAt.IsZero() {
+				code.CreatedAt = now
+			}
+			code.UserID = userID
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (r *twoFactorRecoveryCodeRepository) Consume(userID uuid.UUID, codeHash string, usedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.TwoFactorRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *twoFactorRecoveryCodeRepository) CountUnused(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.TwoFactorRecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *twoFactorRecoveryCodeRepository) DeleteByUserID(userID uuid.UUID) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.TwoFactorRecoveryCode{}).Error
+}
@@ -0,0 +1,112 @@
+// infrastructure/persistence/postgres/user_identity_repository.go
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"gorm.io/gorm"
+)
+
+type userIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewUserIdentityRepository(db *gorm.DB) repository.UserIdentityRepository {
+	return &userIdentityRepository{db: db}
+}
+
+func (r *userIdentityRepository) Create(identity *models.UserIdentity) error {
+	if identity.ID == uuid.Nil {
+		identity.ID = uuid.New()
+	}
+	if identity.CreatedAt.IsZero() {
+		identity.CreatedAt = time.Now()
+	}
+
+	return r.db.Create(identity).Error
+}
+
+func (r *userIdentityRepository) FindByID(id uuid.UUID) (*models.UserIdentity, error) {
+	return r.findOne("id = ?", id)
+}
+
+func (r *userIdentityRepository) FindByProviderSubject(provider, subject string) (*models.UserIdentity, error) {
+	return r.findOne("provider = ? AND subject = ?", provider, subject)
+}
+
+func (r *userIdentityRepository) FindByUserAndProvider(userID uuid.UUID, provider string) (*models.UserIdentity, error) {
+	return r.findOne("user_id = ? AND provider = ?", userID, provider)
+}
+
+func (r *userIdentityRepository) ListByUserID(userID uuid.UUID) ([]*models.UserIdentity, error) {
+	var identities []*models.UserIdentity
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&identities).Error
+	return identities, err
+}
+
+func (r *userIdentityRepository) TouchLastLogin(id uuid.UUID, at time.Time) error {
+	return r.db.Model(&models.UserIdentity{}).Where("id = ?", id).Update("last_login_at", at).Error
+}
+
+func (r *userIdentityRepository) Delete(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&models.UserIdentity{}).Error
+}
+
+func (r *userIdentityRepository) findOne(query string, args ...interface{}) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	if err := r.db.Where(query, args...).First(&identity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
+type oauthStateRepository struct {
+	db *gorm.DB
+}
+
+func NewOAuthStateRepository(db *gorm.DB) repository.OAuthStateRepository {
+	return &oauthStateRepository{db: db}
+}
+
+func (r *oauthStateRepository) Create(state *models.OAuthState) error {
+	if state.ID == uuid.Nil {
+		state.ID = uuid.New()
+	}
+	if state.CreatedAt.IsZero() {
+		state.CreatedAt = time.Now()
+	}
+
+	return r.db.Create(state).Error
+}
+
+func (r *oauthStateRepository) FindByStateHash(stateHash string) (*models.OAuthState, error) {
+	var state models.OAuthState
+	if err := r.db.Where("state_hash = ?", stateHash).First(&state).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *oauthStateRepository) Consume(id uuid.UUID, usedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.OAuthState{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", id, usedAt).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *oauthStateRepository) DeleteExpired(before time.Time) error {
+	return r.db.Where("expires_at < ?", before).Delete(&models.OAuthState{}).Error
+}
@@ -124,3 +124,44 @@ func (r *userRepository) FindByEmail(email string) (*models.User, error) {
 	}
 	return &user, nil
 }
+
+// AdvanceTwoFactorStep อัปเดต time step แบบมีเงื่อนไข (ใช้ Exec เพราะ field เป็น read-only สำหรับ Save)
+func (r *userRepository) AdvanceTwoFactorStep(id uuid.UUID, step int64) (bool, error) {
+	result := r.db.Exec("UPDATE users SET two_factor_last_step = ? WHERE id = ? AND two_factor_last_step < ?", step, id, step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListForAdmin ค้นหาผู้ใช้ทุกสถานะสำหรับผู้ดูแลระบบ
+func (r *userRepository) ListForAdmin(query, status, role string, limit, offset int) ([]*models.User, int64, error) {
+	var users []*models.User
+	var total int64
+
+	db := r.db.Model(&models.User{})
+	if query != "" {
+		searchQuery := "%" + strings.ToLower(query) + "%"
+		db = db.Where("LOWER(username) LIKE ? OR LOWER(display_name) LIKE ? OR LOWER(email) LIKE ?", searchQuery, searchQuery, searchQuery)
+	}
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	if role != "" {
+		db = db.Where("system_role = ?", role)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
@@ -0,0 +1,24 @@
+// infrastructure/push/apns/apns_config.go
+package apns
+
+// APNsConfig เก็บการตั้งค่าสำหรับ Apple Push Notification service (token-based auth)
+type APNsConfig struct {
+	KeyID          string // Key ID ของ .p8 auth key
+	TeamID         string // Apple Developer Team ID
+	BundleID       string // App bundle ID (ใช้เป็น apns-topic)
+	PrivateKeyFile string // path ของไฟล์ .p8
+	PrivateKey     string // เนื้อหา .p8 (ใช้แทน PrivateKeyFile ได้)
+	Production     bool   // true = production, false = sandbox
+	Endpoint       string // Custom endpoint (optional)
+}
+
+// GetEndpoint คืนค่า base URL ของ APNs ตาม environment
+func (c *APNsConfig) GetEndpoint() string {
+	if c.Endpoint != "" {
+		return c.Endpoint
+	}
+	if c.Production {
+		return "https://api.push.apple.com"
+	}
+	return "https://api.sandbox.push.apple.com"
+}
@@ -0,0 +1,183 @@
+// infrastructure/push/apns/apns_provider.go
+package apns
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+)
+
+// APNs ยอมรับ provider token อายุไม่เกิน 1 ชั่วโมง และไม่ให้สร้างใหม่บ่อยกว่า 20 นาที
+const tokenRefreshInterval = 50 * time.Minute
+
+// APNsProvider ส่ง push ผ่าน APNs HTTP/2 API
+type APNsProvider struct {
+	config     *APNsConfig
+	privateKey *ecdsa.PrivateKey
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+// NewAPNsProvider สร้าง APNs provider ใหม่
+func NewAPNsProvider(config *APNsConfig) (*APNsProvider, error) {
+	if config.KeyID == "" || config.TeamID == "" || config.BundleID == "" {
+		return nil, fmt.Errorf("apns key id, team id and bundle id are required")
+	}
+
+	keyPEM := []byte(config.PrivateKey)
+	if len(keyPEM) == 0 {
+		if config.PrivateKeyFile == "" {
+			return nil, fmt.Errorf("apns private key is required")
+		}
+		data, err := os.ReadFile(config.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read apns private key: %w", err)
+		}
+		keyPEM = data
+	}
+
+	privateKey, err := jwt.ParseECPrivateKeyFromPEM(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid apns private key: %w", err)
+	}
+
+	// http.Client ของ Go ใช้ HTTP/2 อัตโนมัติเมื่อเชื่อมต่อผ่าน TLS
+	return &APNsProvider{
+		config:     config,
+		privateKey: privateKey,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+// Name คืนค่าชื่อ provider
+func (p *APNsProvider) Name() string {
+	return models.PushProviderAPNs
+}
+
+// Send ส่ง push ไปยัง device token
+func (p *APNsProvider) Send(ctx context.Context, device *models.DeviceToken, message *service.PushMessage) error {
+	token, err := p.getProviderToken()
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(buildPayload(message))
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/3/device/%s", p.config.GetEndpoint(), device.Token)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("authorization", "bearer "+token)
+	req.Header.Set("apns-topic", p.config.BundleID)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+	req.Header.Set("Content-Type", "application/json")
+	if message.CollapseKey != "" {
+		req.Header.Set("apns-collapse-id", message.CollapseKey)
+	}
+	if message.TTL > 0 {
+		req.Header.Set("apns-expiration", strconv.FormatInt(time.Now().Add(time.Duration(message.TTL)*time.Second).Unix(), 10))
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var errResp struct {
+		Reason string `json:"reason"`
+	}
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	_ = json.Unmarshal(respBody, &errResp)
+
+	switch errResp.Reason {
+	case "BadDeviceToken", "Unregistered", "DeviceTokenNotForTopic":
+		return service.ErrPushTokenInvalid
+	case "ExpiredProviderToken", "InvalidProviderToken":
+		p.mu.Lock()
+		p.token = ""
+		p.mu.Unlock()
+	}
+	if resp.StatusCode == http.StatusGone {
+		return service.ErrPushTokenInvalid
+	}
+
+	return fmt.Errorf("apns send failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+}
+
+// buildPayload สร้าง payload ตามรูปแบบ aps ของ Apple (data อื่นๆ อยู่ระดับบนสุด)
+func buildPayload(message *service.PushMessage) map[string]interface{} {
+	aps := map[string]interface{}{
+		"alert": map[string]string{
+			"title": message.Title,
+			"body":  message.Body,
+		},
+		"mutable-content": 1,
+	}
+	if message.Badge != nil {
+		aps["badge"] = *message.Badge
+	}
+	if message.Sound != "" {
+		aps["sound"] = message.Sound
+	}
+	if message.CollapseKey != "" {
+		aps["thread-id"] = message.CollapseKey
+	}
+
+	payload := map[string]interface{}{"aps": aps}
+	for key, value := range message.Data {
+		payload[key] = value
+	}
+	return payload
+}
+
+// getProviderToken สร้าง JWT (ES256) สำหรับยืนยันตัวตนกับ APNs
+func (p *APNsProvider) getProviderToken() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Since(p.issuedAt) < tokenRefreshInterval {
+		return p.token, nil
+	}
+
+	now := time.Now()
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": p.config.TeamID,
+		"iat": now.Unix(),
+	})
+	jwtToken.Header["kid"] = p.config.KeyID
+
+	signed, err := jwtToken.SignedString(p.privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	p.token = signed
+	p.issuedAt = now
+	return p.token, nil
+}
@@ -0,0 +1,16 @@
+// infrastructure/push/fake/fake_config.go
+package fake
+
+// FakeConfig เก็บการตั้งค่าสำหรับ fake provider (ใช้ในการพัฒนาและทดสอบ)
+type FakeConfig struct {
+	Name    string // ชื่อ provider ที่ต้องการจำลอง (default: fake)
+	Verbose bool   // พิมพ์ push ที่ส่งออกทาง log
+}
+
+// GetName คืนค่าชื่อ provider
+func (c *FakeConfig) GetName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return "fake"
+}
@@ -0,0 +1,87 @@
+// infrastructure/push/fake/fake_provider.go
+package fake
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+)
+
+// SentPush บันทึก push หนึ่งรายการที่ fake provider ได้รับ
+type SentPush struct {
+	Device  models.DeviceToken
+	Message service.PushMessage
+	SentAt  time.Time
+}
+
+// FakeProvider provider จำลองที่เก็บ push ไว้ในหน่วยความจำแทนการส่งจริง
+type FakeProvider struct {
+	config *FakeConfig
+
+	mu      sync.Mutex
+	sent    []SentPush
+	failErr error
+}
+
+// NewFakeProvider สร้าง fake provider ใหม่
+func NewFakeProvider(config *FakeConfig) *FakeProvider {
+	if config == nil {
+		config = &FakeConfig{}
+	}
+	return &FakeProvider{config: config}
+}
+
+// Name คืนค่าชื่อ provider
+func (p *FakeProvider) Name() string {
+	return p.config.GetName()
+}
+
+// Send บันทึก push ไว้ (หรือคืนค่า error ที่กำหนดด้วย FailWith)
+func (p *FakeProvider) Send(ctx context.Context, device *models.DeviceToken, message *service.PushMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.failErr != nil {
+		return p.failErr
+	}
+
+	p.sent = append(p.sent, SentPush{
+		Device:  *device,
+		Message: *message,
+		SentAt:  time.Now(),
+	})
+
+	if p.config.Verbose {
+		log.Printf("[push:%s] user=%s device=%s title=%q body=%q", p.Name(), device.UserID, device.ID, message.Title, message.Body)
+	}
+	return nil
+}
+
+// Sent คืนค่าสำเนาของ push ทั้งหมดที่ได้รับ
+func (p *FakeProvider) Sent() []SentPush {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sent := make([]SentPush, len(p.sent))
+	copy(sent, p.sent)
+	return sent
+}
+
+// FailWith กำหนด error ที่จะคืนค่าในการส่งครั้งถัดไป (nil = ส่งสำเร็จตามปกติ)
+func (p *FakeProvider) FailWith(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failErr = err
+}
+
+// Reset ล้าง push ที่บันทึกไว้
+func (p *FakeProvider) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent = nil
+	p.failErr = nil
+}
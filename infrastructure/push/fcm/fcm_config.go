@@ -0,0 +1,18 @@
+// infrastructure/push/fcm/fcm_config.go
+package fcm
+
+// FCMConfig เก็บการตั้งค่าสำหรับ Firebase Cloud Messaging (HTTP v1 API)
+type FCMConfig struct {
+	ProjectID       string // Firebase project ID (ถ้าว่างจะใช้ project_id จาก service account)
+	CredentialsFile string // path ของไฟล์ service account JSON
+	CredentialsJSON string // เนื้อหา service account JSON (ใช้แทน CredentialsFile ได้)
+	Endpoint        string // Custom endpoint (optional)
+}
+
+// GetEndpoint คืนค่า base URL ของ FCM API
+func (c *FCMConfig) GetEndpoint() string {
+	if c.Endpoint != "" {
+		return c.Endpoint
+	}
+	return "https://fcm.googleapis.com"
+}
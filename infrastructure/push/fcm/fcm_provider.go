@@ -0,0 +1,263 @@
+// infrastructure/push/fcm/fcm_provider.go
+package fcm
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+)
+
+const fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+
+// serviceAccount ข้อมูลที่ใช้จากไฟล์ service account ของ Google
+type serviceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// FCMProvider ส่ง push ผ่าน FCM HTTP v1 API
+type FCMProvider struct {
+	config     *FCMConfig
+	account    *serviceAccount
+	privateKey *rsa.PrivateKey
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewFCMProvider สร้าง FCM provider ใหม่
+func NewFCMProvider(config *FCMConfig) (*FCMProvider, error) {
+	raw := []byte(config.CredentialsJSON)
+	if len(raw) == 0 {
+		if config.CredentialsFile == "" {
+			return nil, fmt.Errorf("fcm credentials are required")
+		}
+		data, err := os.ReadFile(config.CredentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fcm credentials: %w", err)
+		}
+		raw = data
+	}
+
+	var account serviceAccount
+	if err := json.Unmarshal(raw, &account); err != nil {
+		return nil, fmt.Errorf("invalid fcm credentials: %w", err)
+	}
+	if account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, fmt.Errorf("fcm credentials must contain client_email and private_key")
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+	if config.ProjectID == "" {
+		config.ProjectID = account.ProjectID
+	}
+	if config.ProjectID == "" {
+		return nil, fmt.Errorf("fcm project id is required")
+	}
+
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid fcm private key: %w", err)
+	}
+
+	return &FCMProvider{
+		config:     config,
+		account:    &account,
+		privateKey: privateKey,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+// Name คืนค่าชื่อ provider
+func (p *FCMProvider) Name() string {
+	return models.PushProviderFCM
+}
+
+// Send ส่ง push ไปยัง registration token
+func (p *FCMProvider) Send(ctx context.Context, device *models.DeviceToken, message *service.PushMessage) error {
+	accessToken, err := p.getAccessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"message": p.buildMessage(device, message),
+	})
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/projects/%s/messages:send", p.config.GetEndpoint(), p.config.ProjectID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode == http.StatusUnauthorized {
+		// token หมดอายุก่อนเวลา ให้ขอใหม่ในรอบถัดไป
+		p.mu.Lock()
+		p.accessToken = ""
+		p.mu.Unlock()
+	}
+	if isUnregistered(resp.StatusCode, respBody) {
+		return service.ErrPushTokenInvalid
+	}
+
+	return fmt.Errorf("fcm send failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+}
+
+// buildMessage สร้าง message object ตามรูปแบบของ FCM v1
+func (p *FCMProvider) buildMessage(device *models.DeviceToken, message *service.PushMessage) map[string]interface{} {
+	msg := map[string]interface{}{
+		"token": device.Token,
+		"notification": map[string]string{
+			"title": message.Title,
+			"body":  message.Body,
+		},
+	}
+	if len(message.Data) > 0 {
+		msg["data"] = message.Data
+	}
+
+	android := map[string]interface{}{"priority": "high"}
+	if message.CollapseKey != "" {
+		android["collapse_key"] = message.CollapseKey
+	}
+	if message.TTL > 0 {
+		android["ttl"] = fmt.Sprintf("%ds", message.TTL)
+	}
+	msg["android"] = android
+
+	aps := map[string]interface{}{}
+	if message.Badge != nil {
+		aps["badge"] = *message.Badge
+	}
+	if message.Sound != "" {
+		aps["sound"] = message.Sound
+	}
+	if len(aps) > 0 {
+		msg["apns"] = map[string]interface{}{
+			"payload": map[string]interface{}{"aps": aps},
+		}
+	}
+
+	return msg
+}
+
+// getAccessToken ขอ OAuth2 access token ด้วย service account (cache จนใกล้หมดอายุ)
+func (p *FCMProvider) getAccessToken(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.accessToken != "" && time.Now().Before(p.expiresAt.Add(-time.Minute)) {
+		return p.accessToken, nil
+	}
+
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   p.account.ClientEmail,
+		"scope": fcmScope,
+		"aud":   p.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(p.privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("fcm token exchange failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("fcm token exchange returned empty access token")
+	}
+
+	p.accessToken = token.AccessToken
+	p.expiresAt = now.Add(time.Duration(token.ExpiresIn) * time.Second)
+	return p.accessToken, nil
+}
+
+// isUnregistered ตรวจว่า FCM แจ้งว่า token ใช้ไม่ได้แล้วหรือไม่
+func isUnregistered(status int, body []byte) bool {
+	if status == http.StatusNotFound {
+		return true
+	}
+	if status != http.StatusBadRequest {
+		return false
+	}
+
+	var errResp struct {
+		Error struct {
+			Message string `json:"message"`
+			Details []struct {
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errResp); err != nil {
+		return false
+	}
+	for _, detail := range errResp.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			return true
+		}
+	}
+	return strings.Contains(errResp.Error.Message, "not a valid FCM registration token")
+}
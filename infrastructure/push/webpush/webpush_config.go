@@ -0,0 +1,18 @@
+// infrastructure/push/webpush/webpush_config.go
+package webpush
+
+// WebPushConfig เก็บการตั้งค่า VAPID สำหรับ Web Push
+type WebPushConfig struct {
+	VAPIDPublicKey  string // public key (base64url, uncompressed P-256 point) ที่ client ใช้ subscribe
+	VAPIDPrivateKey string // private key (base64url, 32 bytes)
+	Subject         string // ช่องทางติดต่อ เช่น mailto:admin@example.com
+	AllowPrivate    bool   // อนุญาต endpoint ที่เป็น address ภายใน (ใช้กับ push service จำลองระหว่างพัฒนาเท่านั้น)
+}
+
+// GetSubject คืนค่า subject (default: mailto ว่างไม่ได้ตามข้อกำหนด VAPID)
+func (c *WebPushConfig) GetSubject() string {
+	if c.Subject != "" {
+		return c.Subject
+	}
+	return "mailto:admin@localhost"
+}
@@ -0,0 +1,298 @@
+// infrastructure/push/webpush/webpush_provider.go
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/infrastructure/netguard"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	recordSize     = 4096
+	maxPayloadSize = recordSize - 16 - 1 - 86 // หัก tag, delimiter และ header ของ aes128gcm
+	defaultTTL     = 24 * 60 * 60
+)
+
+// WebPushProvider ส่ง push ไปยัง browser ผ่าน Web Push Protocol (RFC 8030/8291/8292)
+type WebPushProvider struct {
+	config     *WebPushConfig
+	privateKey *ecdsa.PrivateKey
+	httpClient *http.Client
+}
+
+// NewWebPushProvider สร้าง WebPush provider ใหม่
+func NewWebPushProvider(config *WebPushConfig) (*WebPushProvider, error) {
+	if config.VAPIDPublicKey == "" || config.VAPIDPrivateKey == "" {
+		return nil, fmt.Errorf("vapid public and private keys are required")
+	}
+
+	privateKey, err := parseVAPIDPrivateKey(config.VAPIDPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebPushProvider{
+		config:     config,
+		privateKey: privateKey,
+		httpClient: &http.Client{
+			Timeout:   15 * time.Second,
+			Transport: netguard.NewTransport(config.AllowPrivate),
+		},
+	}, nil
+}
+
+// Name คืนค่าชื่อ provider
+func (p *WebPushProvider) Name() string {
+	return models.PushProviderWebPush
+}
+
+// VAPIDPublicKey คืนค่า public key สำหรับ PushManager.subscribe() ฝั่ง browser
+func (p *WebPushProvider) VAPIDPublicKey() string {
+	return p.config.VAPIDPublicKey
+}
+
+// ValidateToken ตรวจ subscription endpoint ตอนลงทะเบียน (ห้ามชี้ไปยัง address ภายใน)
+// การตรวจจริงอยู่ที่ transport ตอนเชื่อมต่อ เพราะชื่อโดเมนอาจ resolve เป็น address ภายในภายหลัง
+func (p *WebPushProvider) ValidateToken(token string) error {
+	parsed, err := url.Parse(token)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return errors.New("webpush token must be the subscription endpoint")
+	}
+	if !p.config.AllowPrivate && netguard.ValidateHost(parsed) != nil {
+		return errors.New("webpush endpoint must not point to a private address")
+	}
+	return nil
+}
+
+// Send เข้ารหัส payload และส่งไปยัง subscription endpoint
+func (p *WebPushProvider) Send(ctx context.Context, device *models.DeviceToken, message *service.PushMessage) error {
+	if device.WebPushP256dh == "" || device.WebPushAuth == "" {
+		return service.ErrPushTokenInvalid
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxPayloadSize {
+		return fmt.Errorf("webpush payload too large: %d bytes", len(payload))
+	}
+
+	body, err := encrypt(payload, device.WebPushP256dh, device.WebPushAuth)
+	if err != nil {
+		// key ของ subscription เสีย ไม่มีประโยชน์ที่จะ retry
+		return fmt.Errorf("%w: %v", service.ErrPushTokenInvalid, err)
+	}
+
+	authorization, err := p.vapidAuthorization(device.Token)
+	if err != nil {
+		return err
+	}
+
+	ttl := message.TTL
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, device.Token, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(ttl))
+	req.Header.Set("Urgency", "high")
+	if topic := sanitizeTopic(message.CollapseKey); topic != "" {
+		req.Header.Set("Topic", topic)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return service.ErrPushTokenInvalid
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf("webpush send failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+}
+
+// vapidAuthorization สร้าง Authorization header ตาม RFC 8292
+func (p *WebPushProvider) vapidAuthorization(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", service.ErrPushTokenInvalid
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+		"sub": p.config.GetSubject(),
+	}).SignedString(p.privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("vapid t=%s, k=%s", token, p.config.VAPIDPublicKey), nil
+}
+
+// encrypt เข้ารหัส payload แบบ aes128gcm ตาม RFC 8291 (record เดียว)
+func encrypt(plaintext []byte, p256dh, authSecret string) ([]byte, error) {
+	uaPublicBytes, err := decodeBase64(p256dh)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh: %w", err)
+	}
+	auth, err := decodeBase64(authSecret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth secret: %w", err)
+	}
+
+	curve := ecdh.P256()
+	uaPublic, err := curve.NewPublicKey(uaPublicBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh: %w", err)
+	}
+
+	asPrivate, err := curve.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	asPublicBytes := asPrivate.PublicKey().Bytes()
+
+	sharedSecret, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	// IKM = HKDF(auth_secret, ecdh_secret, "WebPush: info" || 0x00 || ua_public || as_public)
+	keyInfo := append([]byte("WebPush: info\x00"), uaPublicBytes...)
+	keyInfo = append(keyInfo, asPublicBytes...)
+	ikm, err := expand(hkdf.Extract(sha256.New, sharedSecret, auth), keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	prk := hkdf.Extract(sha256.New, ikm, salt)
+	cek, err := expand(prk, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := expand(prk, []byte("Content-Encoding: nonce\x00"), 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// 0x02 = delimiter ของ record สุดท้าย
+	record := append(append([]byte{}, plaintext...), 0x02)
+	ciphertext := gcm.Seal(nil, nonce, record, nil)
+
+	// header: salt (16) || rs (4) || idlen (1) || keyid (as_public)
+	header := make([]byte, 0, 21+len(asPublicBytes))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(asPublicBytes)))
+	header = append(header, asPublicBytes...)
+
+	return append(header, ciphertext...), nil
+}
+
+// expand อ่านค่า HKDF-Expand ตามความยาวที่ต้องการ
+func expand(prk, info []byte, length int) ([]byte, error) {
+	out := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, info), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// parseVAPIDPrivateKey แปลง private key แบบ raw base64url เป็น ecdsa.PrivateKey
+func parseVAPIDPrivateKey(encoded string) (*ecdsa.PrivateKey, error) {
+	raw, err := decodeBase64(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vapid private key: %w", err)
+	}
+
+	key, err := ecdh.P256().NewPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vapid private key: %w", err)
+	}
+
+	// public key แบบ uncompressed: 0x04 || X (32) || Y (32)
+	public := key.PublicKey().Bytes()
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(public[1:33]),
+			Y:     new(big.Int).SetBytes(public[33:65]),
+		},
+		D: new(big.Int).SetBytes(raw),
+	}, nil
+}
+
+// decodeBase64 รองรับทั้ง base64url และ base64 มาตรฐาน (มีหรือไม่มี padding)
+func decodeBase64(value string) ([]byte, error) {
+	value = strings.TrimRight(strings.TrimSpace(value), "=")
+	if strings.ContainsAny(value, "+/") {
+		return base64.RawStdEncoding.DecodeString(value)
+	}
+	return base64.RawURLEncoding.DecodeString(value)
+}
+
+// sanitizeTopic ตัด Topic ให้อยู่ในรูปแบบที่ push service ยอมรับ (base64url ไม่เกิน 32 ตัวอักษร)
+func sanitizeTopic(topic string) string {
+	var b strings.Builder
+	for _, r := range topic {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			b.WriteRune(r)
+		}
+		if b.Len() == 32 {
+			break
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,117 @@
+// infrastructure/ratelimit/redis_login_guard.go
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	loginFailuresPrefix = "login:failures:"
+	loginLockPrefix     = "login:lock:"
+	loginLockoutsPrefix = "login:lockouts:"
+
+	// loginLockoutMemory ระยะเวลาที่จำจำนวนครั้งที่ถูกล็อก (ใช้คำนวณการล็อกที่นานขึ้นครั้งถัดไป)
+	loginLockoutMemory = 24 * time.Hour
+)
+
+// LoginGuardConfig การตั้งค่าการล็อกบัญชีจากการเข้าสู่ระบบผิด
+type LoginGuardConfig struct {
+	Threshold   int           // จำนวนครั้งที่ผิดได้ก่อนถูกล็อก (default: 5)
+	Window      time.Duration // ช่วงเวลาที่นับความล้มเหลว (default: 15 นาที)
+	BaseLockout time.Duration // ระยะล็อกครั้งแรก เพิ่มเป็น 2 เท่าทุกครั้งที่ถูกล็อกซ้ำ (default: 1 นาที)
+	MaxLockout  time.Duration // ระยะล็อกสูงสุด (default: 1 ชั่วโมง)
+}
+
+// RedisLoginGuard ติดตามการเข้าสู่ระบบที่ล้มเหลวใน Redis
+type RedisLoginGuard struct {
+	redis  *redis.Client
+	config LoginGuardConfig
+}
+
+// NewRedisLoginGuard สร้าง login guard ใหม่
+func NewRedisLoginGuard(redisClient *redis.Client, config *LoginGuardConfig) *RedisLoginGuard {
+	cfg := LoginGuardConfig{}
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = 5
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = 15 * time.Minute
+	}
+	if cfg.BaseLockout <= 0 {
+		cfg.BaseLockout = time.Minute
+	}
+	if cfg.MaxLockout <= 0 {
+		cfg.MaxLockout = time.Hour
+	}
+	if cfg.MaxLockout < cfg.BaseLockout {
+		cfg.MaxLockout = cfg.BaseLockout
+	}
+
+	return &RedisLoginGuard{redis: redisClient, config: cfg}
+}
+
+// LockedFor คืนค่าเวลาที่เหลือของการล็อก
+func (g *RedisLoginGuard) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := g.redis.PTTL(ctx, loginLockPrefix+key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check login lock: %w", err)
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// RecordFailure นับความล้มเหลว เมื่อถึง threshold จะล็อกโดยระยะเวลาเพิ่มเป็น 2 เท่าทุกครั้งที่ถูกล็อกซ้ำภายใน 24 ชั่วโมง
+func (g *RedisLoginGuard) RecordFailure(ctx context.Context, key string) (time.Duration, error) {
+	failuresKey := loginFailuresPrefix + key
+
+	failures, err := g.redis.Incr(ctx, failuresKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to record login failure: %w", err)
+	}
+	if failures == 1 {
+		if err := g.redis.Expire(ctx, failuresKey, g.config.Window).Err(); err != nil {
+			return 0, fmt.Errorf("failed to set login failure window: %w", err)
+		}
+	}
+	if failures < int64(g.config.Threshold) {
+		return 0, nil
+	}
+
+	lockoutsKey := loginLockoutsPrefix + key
+	lockouts, err := g.redis.Incr(ctx, lockoutsKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to record lockout: %w", err)
+	}
+
+	duration := g.config.BaseLockout
+	for i := int64(1); i < lockouts && duration < g.config.MaxLockout; i++ {
+		duration *= 2
+	}
+	if duration > g.config.MaxLockout {
+		duration = g.config.MaxLockout
+	}
+
+	pipe := g.redis.TxPipeline()
+	pipe.Expire(ctx, lockoutsKey, loginLockoutMemory)
+	pipe.Set(ctx, loginLockPrefix+key, lockouts, duration)
+	pipe.Del(ctx, failuresKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to lock login: %w", err)
+	}
+
+	return duration, nil
+}
+
+// Reset ล้างตัวนับความล้มเหลวและประวัติการล็อก
+func (g *RedisLoginGuard) Reset(ctx context.Context, key string) error {
+	return g.redis.Del(ctx, loginFailuresPrefix+key, loginLockoutsPrefix+key).Err()
+}
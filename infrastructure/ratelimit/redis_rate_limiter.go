@@ -0,0 +1,92 @@
+// infrastructure/ratelimit/redis_rate_limiter.go
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+)
+
+const rateLimitKeyPrefix = "ratelimit:"
+
+// slidingWindowScript นับ request ใน sorted set (score = เวลาเป็น ms) แบบ atomic
+// KEYS[1] = key, ARGV = now (ms), window (ms), limit, member
+// คืนค่า {allowed, count, oldest score}
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, 0, now - window)
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', key, window)
+
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+local oldestScore = now
+if oldest[2] then
+	oldestScore = tonumber(oldest[2])
+end
+return {allowed, count, oldestScore}
+`)
+
+// RedisRateLimiter sliding window rate limiter บน Redis
+type RedisRateLimiter struct {
+	redis *redis.Client
+}
+
+// NewRedisRateLimiter สร้าง rate limiter ใหม่
+func NewRedisRateLimiter(redisClient *redis.Client) *RedisRateLimiter {
+	return &RedisRateLimiter{redis: redisClient}
+}
+
+// Allow ตรวจและนับ request ของ key ในช่วง window ล่าสุด
+func (l *RedisRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (*service.RateLimitResult, error) {
+	now := time.Now()
+	nowMs := now.UnixNano() / int64(time.Millisecond)
+	windowMs := window.Milliseconds()
+
+	values, err := slidingWindowScript.Run(ctx, l.redis, []string{rateLimitKeyPrefix + key},
+		nowMs, windowMs, limit, strconv.FormatInt(nowMs, 10)+"-"+uuid.NewString()[:8],
+	).Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run rate limit script: %w", err)
+	}
+	if len(values) != 3 {
+		return nil, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	allowed, _ := values[0].(int64)
+	count, _ := values[1].(int64)
+	oldest, _ := values[2].(int64)
+
+	resetAt := time.Unix(0, (oldest+windowMs)*int64(time.Millisecond))
+	result := &service.RateLimitResult{
+		Allowed:   allowed == 1,
+		Limit:     limit,
+		Remaining: limit - int(count),
+		ResetAt:   resetAt,
+	}
+	if result.Remaining < 0 {
+		result.Remaining = 0
+	}
+	if !result.Allowed {
+		result.RetryAfter = resetAt.Sub(now)
+		if result.RetryAfter < time.Second {
+			result.RetryAfter = time.Second
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,110 @@
+// infrastructure/webhook/http_sender.go
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/infrastructure/netguard"
+)
+
+// จำนวน byte สูงสุดที่อ่านจาก response ของ webhook (อ่านทิ้งเพื่อให้ใช้ connection ซ้ำได้)
+const maxResponseBytes = 64 * 1024
+
+// HTTPSenderConfig เก็บการตั้งค่าสำหรับส่ง webhook ผ่าน HTTP
+type HTTPSenderConfig struct {
+	Timeout       time.Duration // เวลารอ response ต่อครั้ง (default 10 วินาที)
+	AllowInsecure bool          // อนุญาต http:// (เช่น stand-in บนเครื่องระหว่างพัฒนา)
+	AllowPrivate  bool          // อนุญาตปลายทางที่เป็น address ภายใน (loopback, private, link-local) ใช้ระหว่างพัฒนาเท่านั้น
+	UserAgent     string
+}
+
+// HTTPSender ส่ง webhook ด้วย net/http
+type HTTPSender struct {
+	config *HTTPSenderConfig
+	client *http.Client
+}
+
+// NewHTTPSender สร้าง sender ใหม่
+func NewHTTPSender(config *HTTPSenderConfig) *HTTPSender {
+	if config == nil {
+		config = &HTTPSenderConfig{}
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.UserAgent == "" {
+		config.UserAgent = "gofiber-chat-api-webhook/1.0"
+	}
+
+	return &HTTPSender{
+		config: config,
+		client: &http.Client{
+			Timeout: config.Timeout,
+			// ตรวจ address หลัง resolve DNS ทุกครั้งที่ dial เพื่อกัน SSRF (รวมถึง DNS rebinding)
+			Transport: netguard.NewTransport(config.AllowPrivate),
+			// ไม่ตาม redirect เพื่อไม่ให้ payload ที่ลงลายมือชื่อแล้วถูกส่งต่อไปยังปลายทางอื่น
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// ValidateURL ตรวจสอบ URL ของ webhook (ต้องเป็น https ยกเว้น AllowInsecure และห้ามชี้ไปยัง address ภายใน)
+func (s *HTTPSender) ValidateURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return errors.New("webhook url must be an absolute url")
+	}
+
+	switch parsed.Scheme {
+	case "https":
+	case "http":
+		if !s.config.AllowInsecure {
+			return errors.New("webhook url must use https")
+		}
+	default:
+		return errors.New("webhook url must use https")
+	}
+
+	if !s.config.AllowPrivate && netguard.ValidateHost(parsed) != nil {
+		return errors.New("webhook url must not point to a private address")
+	}
+	return nil
+}
+
+// Send ส่ง POST หนึ่งครั้ง คืนค่า HTTP status และ error ถ้าไม่ใช่ 2xx
+func (s *HTTPSender) Send(ctx context.Context, request *service.WebhookRequest) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, request.URL, bytes.NewReader(request.Body))
+	if err != nil {
+		return 0, fmt.Errorf("invalid webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", s.config.UserAgent)
+	for key, value := range request.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,59 @@
+// infrastructure/webhook/http_sender_test.go
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/infrastructure/netguard"
+)
+
+func TestValidateURLRejectsPrivateAddresses(t *testing.T) {
+	sender := NewHTTPSender(&HTTPSenderConfig{AllowInsecure: true})
+
+	for _, rawURL := range []string{
+		"http://127.0.0.1/hook",
+		"http://localhost:8080/hook",
+		"http://10.0.0.5/hook",
+		"http://192.168.1.10/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"http://[fd00::1]/hook",
+		"http://100.64.0.1/hook",
+	} {
+		if err := sender.ValidateURL(rawURL); err == nil {
+			t.Errorf("expected %s to be rejected", rawURL)
+		}
+	}
+
+	if err := sender.ValidateURL("https://hooks.example.com/bot"); err != nil {
+		t.Errorf("expected public url to be accepted, got %v", err)
+	}
+}
+
+func TestSendBlocksPrivateAddressAtDialTime(t *testing.T) {
+	var called bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// ValidateURL ถูกข้ามได้ (เช่น DNS เปลี่ยนไปชี้ address ภายในหลังบันทึก) การ dial ต้องถูกปฏิเสธเสมอ
+	sender := NewHTTPSender(&HTTPSenderConfig{AllowInsecure: true})
+	_, err := sender.Send(context.Background(), &service.WebhookRequest{URL: server.URL, Body: []byte(`{}`)})
+	if !errors.Is(err, netguard.ErrPrivateAddress) {
+		t.Fatalf("expected private address error, got %v", err)
+	}
+	if called {
+		t.Fatal("request reached a loopback server")
+	}
+
+	sender = NewHTTPSender(&HTTPSenderConfig{AllowInsecure: true, AllowPrivate: true})
+	if _, err := sender.Send(context.Background(), &service.WebhookRequest{URL: server.URL, Body: []byte(`{}`)}); err != nil {
+		t.Fatalf("expected AllowPrivate sender to reach local stand-in, got %v", err)
+	}
+}
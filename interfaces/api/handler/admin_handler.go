@@ -0,0 +1,459 @@
+// interfaces/api/handler/admin_handler.go
+package handler
+
+import (
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+	"github.com/thizplus/gofiber-chat-api/pkg/utils"
+)
+
+// AdminHandler handles platform administration endpoints
+type AdminHandler struct {
+	adminService service.AdminService
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(adminService service.AdminService) *AdminHandler {
+	return &AdminHandler{adminService: adminService}
+}
+
+// Audit บันทึก audit log ของ request หลัง handler ทำงานเสร็จ (รวม request ที่ล้มเหลว)
+// targetParam = ชื่อ route param ที่เป็น ID ของสิ่งที่ถูกกระทำ ("" = ไม่มี)
+// handler เพิ่มรายละเอียดได้ผ่าน c.Locals("auditDetails", types.JSONB{...})
+func (h *AdminHandler) Audit(action, targetType, targetParam string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		handlerErr := c.Next()
+
+		actorID, err := middleware.GetUserUUID(c)
+		if err != nil {
+			return handlerErr
+		}
+
+		statusCode := c.Response().StatusCode()
+		if handlerErr != nil {
+			statusCode = fiber.StatusInternalServerError
+			if fiberErr, ok := handlerErr.(*fiber.Error); ok {
+				statusCode = fiberErr.Code
+			}
+		}
+
+		details := types.JSONB{
+			"method": c.Method(),
+			"path":   c.Path(),
+		}
+		if query := string(c.Request().URI().QueryString()); query != "" {
+			details["query"] = query
+		}
+		if extra, ok := c.Locals("auditDetails").(types.JSONB); ok {
+			for key, value := range extra {
+				details[key] = value
+			}
+		}
+
+		entry := &models.AdminAuditLog{
+			ActorID:    actorID,
+			ActorRole:  middleware.GetSystemRole(c),
+			Action:     action,
+			TargetType: targetType,
+			StatusCode: statusCode,
+			Details:    details,
+			IPAddress:  c.IP(),
+		}
+		if targetParam != "" {
+			if targetID, err := uuid.Parse(c.Params(targetParam)); err == nil {
+				entry.TargetID = &targetID
+			}
+		}
+
+		if err := h.adminService.RecordAudit(entry); err != nil {
+			log.Printf("Error recording admin audit log: %v, action: %s, actorID: %s", err, action, actorID)
+		}
+
+		return handlerErr
+	}
+}
+
+// ListUsers lists users of every status
+// GET /api/v1/admin/users?q=&status=&role=&limit=20&offset=0
+func (h *AdminHandler) ListUsers(c *fiber.Ctx) error {
+	limit, offset := adminPagination(c)
+
+	users, total, err := h.adminService.ListUsers(c.Query("q"), c.Query("status"), c.Query("role"), limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"message": "Error listing users: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    users,
+		"pagination": fiber.Map{
+			"total":  total,
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
+// GetUser returns a single user
+// GET /api/v1/admin/users/:userId
+func (h *AdminHandler) GetUser(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUIDParam(c, "userId")
+	if err != nil {
+		return err
+	}
+
+	user, err := h.adminService.GetUser(userID)
+	if err != nil {
+		return c.Status(adminErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    user,
+	})
+}
+
+// SuspendUser suspends a user account and revokes all of its sessions
+// POST /api/v1/admin/users/:userId/suspend
+func (h *AdminHandler) SuspendUser(c *fiber.Ctx) error {
+	actorID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	userID, err := utils.ParseUUIDParam(c, "userId")
+	if err != nil {
+		return err
+	}
+
+	var req dto.SuspendUserRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+	}
+	c.Locals("auditDetails", types.JSONB{"reason": req.Reason})
+
+	user, err := h.adminService.SuspendUser(actorID, middleware.GetSystemRole(c), userID, req.Reason)
+	if err != nil {
+		return c.Status(adminErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "User suspended successfully",
+		"data":    user,
+	})
+}
+
+// UnsuspendUser lifts a suspension
+// POST /api/v1/admin/users/:userId/unsuspend
+func (h *AdminHandler) UnsuspendUser(c *fiber.Ctx) error {
+	actorID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	userID, err := utils.ParseUUIDParam(c, "userId")
+	if err != nil {
+		return err
+	}
+
+	user, err := h.adminService.UnsuspendUser(actorID, middleware.GetSystemRole(c), userID)
+	if err != nil {
+		return c.Status(adminErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "User unsuspended successfully",
+		"data":    user,
+	})
+}
+
+// SetSystemRole grants or removes a platform role
+// PUT /api/v1/admin/users/:userId/role
+func (h *AdminHandler) SetSystemRole(c *fiber.Ctx) error {
+	actorID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	userID, err := utils.ParseUUIDParam(c, "userId")
+	if err != nil {
+		return err
+	}
+
+	var req dto.SetSystemRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body",
+		})
+	}
+	c.Locals("auditDetails", types.JSONB{"role": req.Role})
+
+	user, err := h.adminService.SetSystemRole(actorID, userID, req.Role)
+	if err != nil {
+		return c.Status(adminErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "System role updated successfully",
+		"data":    user,
+	})
+}
+
+// InspectConversation returns a conversation with its members
+// GET /api/v1/admin/conversations/:conversationId
+func (h *AdminHandler) InspectConversation(c *fiber.Ctx) error {
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	conversation, err := h.adminService.InspectConversation(conversationID)
+	if err != nil {
+		return c.Status(adminErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    conversation,
+	})
+}
+
+// GetConversationMessages returns messages of a conversation (newest page first)
+// GET /api/v1/admin/conversations/:conversationId/messages?limit=20&offset=0
+func (h *AdminHandler) GetConversationMessages(c *fiber.Ctx) error {
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	limit, offset := adminPagination(c)
+
+	messages, total, err := h.adminService.GetConversationMessages(conversationID, limit, offset)
+	if err != nil {
+		return c.Status(adminErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    messages,
+		"pagination": fiber.Map{
+			"total":  total,
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
+// DisableConversation disables a group or channel (hidden from member lists, posting is blocked)
+// POST /api/v1/admin/conversations/:conversationId/disable
+func (h *AdminHandler) DisableConversation(c *fiber.Ctx) error {
+	actorID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	var req dto.DisableConversationRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+	}
+	c.Locals("auditDetails", types.JSONB{"reason": req.Reason})
+
+	conversation, err := h.adminService.DisableConversation(actorID, conversationID, req.Reason)
+	if err != nil {
+		return c.Status(adminErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Conversation disabled successfully",
+		"data":    conversation,
+	})
+}
+
+// EnableConversation re-enables a conversation disabled by a moderator
+// POST /api/v1/admin/conversations/:conversationId/enable
+func (h *AdminHandler) EnableConversation(c *fiber.Ctx) error {
+	actorID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	conversation, err := h.adminService.EnableConversation(actorID, conversationID)
+	if err != nil {
+		return c.Status(adminErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Conversation enabled successfully",
+		"data":    conversation,
+	})
+}
+
+// GetRealtimeStats returns WebSocket hub statistics of this instance
+// GET /api/v1/admin/realtime/stats
+func (h *AdminHandler) GetRealtimeStats(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    h.adminService.GetRealtimeStats(),
+	})
+}
+
+// GetRealtimeConnections returns active WebSocket connections of this instance
+// GET /api/v1/admin/realtime/connections
+func (h *AdminHandler) GetRealtimeConnections(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    h.adminService.GetRealtimeConnections(),
+	})
+}
+
+// ListAuditLogs lists admin audit logs
+// GET /api/v1/admin/audit-logs?actor_id=&target_id=&action=&limit=20&offset=0
+func (h *AdminHandler) ListAuditLogs(c *fiber.Ctx) error {
+	actorID, err := utils.ParseUUIDQuery(c, "actor_id", false)
+	if err != nil {
+		return err
+	}
+	targetID, err := utils.ParseUUIDQuery(c, "target_id", false)
+	if err != nil {
+		return err
+	}
+
+	limit, offset := adminPagination(c)
+
+	entries, total, err := h.adminService.ListAuditLogs(optionalUUID(actorID), optionalUUID(targetID), c.Query("action"), limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"message": "Error listing audit logs: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    entries,
+		"pagination": fiber.Map{
+			"total":  total,
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
+// adminPagination อ่าน limit/offset (limit 1-100 ค่าเริ่มต้น 20)
+func adminPagination(c *fiber.Ctx) (int, int) {
+	limit := c.QueryInt("limit", 20)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset := c.QueryInt("offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// optionalUUID แปลง uuid.Nil เป็น nil
+func optionalUUID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
+
+// adminErrorStatus แปลง error ของ AdminService เป็น HTTP status
+func adminErrorStatus(err error) int {
+	switch err.Error() {
+	case "user not found", "conversation not found":
+		return fiber.StatusNotFound
+	case "you cannot suspend yourself", "you cannot change your own system role",
+		"only super admins can suspend staff accounts", "only super admins can unsuspend staff accounts":
+		return fiber.StatusForbidden
+	case "user is already suspended", "user is not suspended",
+		"conversation is already disabled", "conversation is not disabled":
+		return fiber.StatusConflict
+	case "invalid system role", "direct conversations cannot be disabled, suspend the user instead":
+		return fiber.StatusBadRequest
+	default:
+		if strings.HasPrefix(err.Error(), "suspend reason is too long") ||
+			strings.HasPrefix(err.Error(), "disable reason is too long") {
+			return fiber.StatusBadRequest
+		}
+		return fiber.StatusInternalServerError
+	}
+}
@@ -2,11 +2,14 @@
 package handler
 
 import (
+	"errors"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
 	"github.com/thizplus/gofiber-chat-api/domain/service"
 	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+	"github.com/thizplus/gofiber-chat-api/pkg/utils"
 )
 
 type AuthHandler struct {
@@ -35,6 +38,7 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 		input["password"],
 		input["email"],
 		input["display_name"],
+		sessionDeviceInfo(c, input["device_name"]),
 	)
 
 	if err != nil {
@@ -51,12 +55,13 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 		"access_token":  accessToken,
 		"refresh_token": refreshToken,
 		"user": fiber.Map{
-			"id":           user.ID,
-			"username":     user.Username,
-			"email":        user.Email,
-			"display_name": user.DisplayName,
-			"status":       user.Status,
-			"created_at":   user.CreatedAt,
+			"id":             user.ID,
+			"username":       user.Username,
+			"email":          user.Email,
+			"display_name":   user.DisplayName,
+			"status":         user.Status,
+			"created_at":     user.CreatedAt,
+			"email_verified": user.EmailVerifiedAt != nil,
 		},
 	})
 }
@@ -75,10 +80,31 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	user, accessToken, refreshToken, err := h.authService.Login(
 		input["username"],
 		input["password"],
+		sessionDeviceInfo(c, input["device_name"]),
 	)
 
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+		// บัญชีที่เปิด 2FA: ส่ง challenge token ให้ client ยืนยันรหัสที่ /auth/login/2fa
+		var twoFactorErr *service.TwoFactorRequiredError
+		if errors.As(err, &twoFactorErr) {
+			return c.JSON(fiber.Map{
+				"success":             true,
+				"message":             "Two-factor authentication required",
+				"two_factor_required": true,
+				"challenge_token":     twoFactorErr.ChallengeToken,
+				"expires_at":          twoFactorErr.ExpiresAt,
+			})
+		}
+
+		statusCode := fiber.StatusUnauthorized
+		var lockedErr *service.LoginLockedError
+		if errors.As(err, &lockedErr) {
+			statusCode = fiber.StatusTooManyRequests
+			middleware.SetRetryAfter(c, lockedErr.RetryAfter)
+		} else if err.Error() == "account is suspended" {
+			statusCode = fiber.StatusForbidden
+		}
+		return c.Status(statusCode).JSON(fiber.Map{
 			"success": false,
 			"message": err.Error(),
 		})
@@ -125,8 +151,8 @@ func (h *AuthHandler) Logout(c *fiber.Ctx) error {
 		})
 	}
 
-	// เรียกใช้ service เพื่อทำการ logout ด้วย UUID
-	if err := h.authService.Logout(userUUID); err != nil {
+	// เรียกใช้ service เพื่อทำการ logout เฉพาะ session ของอุปกรณ์นี้
+	if err := h.authService.Logout(userUUID, middleware.GetSessionID(c)); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
 			"message": "Error logging out: " + err.Error(),
@@ -166,7 +192,7 @@ func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 	}
 
 	// เรียกใช้ service
-	accessToken, newRefreshToken, err := h.authService.RefreshToken(refreshTokenString)
+	accessToken, newRefreshToken, err := h.authService.RefreshToken(refreshTokenString, sessionDeviceInfo(c, ""))
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 			"success": false,
@@ -207,15 +233,637 @@ func (h *AuthHandler) GetCurrentUser(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{
 		"success": true,
 		"user": fiber.Map{
-			"id":                user.ID,
-			"username":          user.Username,
-			"email":             user.Email,
-			"display_name":      user.DisplayName,
-			"profile_image_url": user.ProfileImageURL,
-			"bio":               user.Bio,
-			"created_at":        user.CreatedAt,
-			"last_active_at":    user.LastActiveAt,
-			"status":            user.Status,
+			"id":                 user.ID,
+			"username":           user.Username,
+			"email":              user.Email,
+			"email_verified":     user.EmailVerifiedAt != nil,
+			"two_factor_enabled": user.IsTwoFactorEnabled(),
+			"display_name":       user.DisplayName,
+			"profile_image_url":  user.ProfileImageURL,
+			"bio":                user.Bio,
+			"created_at":         user.CreatedAt,
+			"last_active_at":     user.LastActiveAt,
+			"status":             user.Status,
+		},
+	})
+}
+
+// ListSessions ดึงรายการอุปกรณ์ที่เข้าสู่ระบบอยู่
+func (h *AuthHandler) ListSessions(c *fiber.Ctx) error {
+	userUUID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	sessions, err := h.authService.ListSessions(userUUID, middleware.GetSessionID(c))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"message": "Error getting sessions: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    sessions,
+	})
+}
+
+// RevokeSession ออกจากระบบอุปกรณ์ที่ระบุ
+func (h *AuthHandler) RevokeSession(c *fiber.Ctx) error {
+	userUUID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	sessionID, err := utils.ParseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.authService.RevokeSession(userUUID, sessionID); err != nil {
+		statusCode := fiber.StatusInternalServerError
+		if err.Error() == "session not found" {
+			statusCode = fiber.StatusNotFound
+		}
+
+		return c.Status(statusCode).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Session revoked successfully",
+	})
+}
+
+// RevokeOtherSessions ออกจากระบบทุกอุปกรณ์ยกเว้นอุปกรณ์ปัจจุบัน
+func (h *AuthHandler) RevokeOtherSessions(c *fiber.Ctx) error {
+	userUUID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	count, err := h.authService.RevokeOtherSessions(userUUID, middleware.GetSessionID(c))
+	if err != nil {
+		statusCode := fiber.StatusInternalServerError
+		if err.Error() == "current session not found" {
+			// token แบบเก่าที่ไม่มี sid ต้องเข้าสู่ระบบใหม่ก่อน
+			statusCode = fiber.StatusBadRequest
+		}
+
+		return c.Status(statusCode).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Other sessions revoked successfully",
+		"data": fiber.Map{
+			"revoked_count": count,
+		},
+	})
+}
+
+// LoginTwoFactor ขั้นที่สองของการเข้าสู่ระบบด้วยรหัส TOTP หรือรหัสกู้คืน
+// POST /api/v1/auth/login/2fa
+func (h *AuthHandler) LoginTwoFactor(c *fiber.Ctx) error {
+	var input map[string]string
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request data",
+		})
+	}
+
+	user, accessToken, refreshToken, err := h.authService.LoginTwoFactor(
+		input["challenge_token"],
+		input["code"],
+		sessionDeviceInfo(c, input["device_name"]),
+	)
+	if err != nil {
+		return twoFactorErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success":       true,
+		"message":       "Login successful",
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+		"user": fiber.Map{
+			"id":           user.ID,
+			"username":     user.Username,
+			"email":        user.Email,
+			"display_name": user.DisplayName,
+			"status":       user.Status,
+		},
+	})
+}
+
+// GetTwoFactorStatus ดึงสถานะ 2FA ของผู้ใช้ปัจจุบัน
+// GET /api/v1/auth/2fa
+func (h *AuthHandler) GetTwoFactorStatus(c *fiber.Ctx) error {
+	userUUID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	status, err := h.authService.GetTwoFactorStatus(userUUID)
+	if err != nil {
+		return twoFactorErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    status,
+	})
+}
+
+// SetupTwoFactor เริ่มเปิดใช้ 2FA (คืนค่า secret และ provisioning URI สำหรับ QR code)
+// POST /api/v1/auth/2fa/setup
+func (h *AuthHandler) SetupTwoFactor(c *fiber.Ctx) error {
+	userUUID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	setup, err := h.authService.SetupTwoFactor(userUUID)
+	if err != nil {
+		return twoFactorErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Scan the QR code and confirm with a code from your authenticator app",
+		"data":    setup,
+	})
+}
+
+// ConfirmTwoFactor ยืนยันรหัสแรกเพื่อเปิดใช้ 2FA (คืนค่ารหัสกู้คืนครั้งเดียว)
+// POST /api/v1/auth/2fa/confirm
+func (h *AuthHandler) ConfirmTwoFactor(c *fiber.Ctx) error {
+	userUUID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	var input map[string]string
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request data",
+		})
+	}
+
+	codes, err := h.authService.ConfirmTwoFactor(userUUID, middleware.GetSessionID(c), input["code"])
+	if err != nil {
+		return twoFactorErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Two-factor authentication enabled, store the recovery codes somewhere safe",
+		"data": fiber.Map{
+			"recovery_codes": codes,
+		},
+	})
+}
+
+// DisableTwoFactor ปิด 2FA (ต้องส่งรหัสผ่านและรหัส TOTP หรือรหัสกู้คืน)
+// POST /api/v1/auth/2fa/disable
+func (h *AuthHandler) DisableTwoFactor(c *fiber.Ctx) error {
+	userUUID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	var input map[string]string
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request data",
+		})
+	}
+
+	if err := h.authService.DisableTwoFactor(userUUID, input["password"], input["code"]); err != nil {
+		return twoFactorErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// RegenerateRecoveryCodes สร้างรหัสกู้คืนชุดใหม่ (ต้องส่งรหัสผ่านและรหัส 2FA)
+// POST /api/v1/auth/2fa/recovery-codes
+func (h *AuthHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	userUUID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	var input map[string]string
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request data",
+		})
+	}
+
+	codes, err := h.authService.RegenerateRecoveryCodes(userUUID, input["password"], input["code"])
+	if err != nil {
+		return twoFactorErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Recovery codes regenerated",
+		"data": fiber.Map{
+			"recovery_codes": codes,
+		},
+	})
+}
+
+// twoFactorErrorResponse ส่ง error ของ 2FA กลับพร้อม HTTP status (ใส่ Retry-After เมื่อถูกล็อก)
+func twoFactorErrorResponse(c *fiber.Ctx, err error) error {
+	statusCode := fiber.StatusInternalServerError
+	var lockedErr *service.LoginLockedError
+	if errors.As(err, &lockedErr) {
+		statusCode = fiber.StatusTooManyRequests
+		middleware.SetRetryAfter(c, lockedErr.RetryAfter)
+	} else {
+		switch err.Error() {
+		case "invalid or expired challenge token", "invalid two-factor code", "invalid password":
+			statusCode = fiber.StatusUnauthorized
+		case "two-factor code is required", "password is required", "two-factor setup has not been started",
+			"bot accounts cannot use two-factor authentication":
+			statusCode = fiber.StatusBadRequest
+		case "two-factor authentication is already enabled", "two-factor authentication is not enabled":
+			statusCode = fiber.StatusConflict
+		case "account is suspended":
+			statusCode = fiber.StatusForbidden
+		}
+	}
+
+	return c.Status(statusCode).JSON(fiber.Map{
+		"success": false,
+		"message": err.Error(),
+	})
+}
+
+// ForgotPassword ส่งลิงก์รีเซ็ตรหัสผ่านไปยังอีเมล
+// POST /api/v1/auth/forgot-password
+func (h *AuthHandler) ForgotPassword(c *fiber.Ctx) error {
+	var input map[string]string
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request data",
+		})
+	}
+
+	if err := h.authService.RequestPasswordReset(input["email"]); err != nil {
+		return c.Status(accountTokenErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	// ตอบเหมือนกันเสมอเพื่อไม่ให้ตรวจได้ว่ามีบัญชีของอีเมลนี้หรือไม่
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "If the email is registered, a password reset link has been sent",
+	})
+}
+
+// ResetPassword ตั้งรหัสผ่านใหม่ด้วย token จากอีเมล (ออกจากระบบทุกอุปกรณ์)
+// POST /api/v1/auth/reset-password
+func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
+	var input map[string]string
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request data",
+		})
+	}
+
+	if err := h.authService.ResetPassword(input["token"], input["password"]); err != nil {
+		return c.Status(accountTokenErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Password reset successfully, please log in again",
+	})
+}
+
+// VerifyEmail ยืนยันอีเมลด้วย token จากอีเมล
+// POST /api/v1/auth/verify-email
+func (h *AuthHandler) VerifyEmail(c *fiber.Ctx) error {
+	var input map[string]string
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request data",
+		})
+	}
+
+	if err := h.authService.VerifyEmail(input["token"]); err != nil {
+		return c.Status(accountTokenErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Email verified successfully",
+	})
+}
+
+// ResendEmailVerification ส่งลิงก์ยืนยันอีเมลอีกครั้ง
+// POST /api/v1/auth/verify-email/resend
+func (h *AuthHandler) ResendEmailVerification(c *fiber.Ctx) error {
+	userUUID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	if err := h.authService.SendEmailVerification(userUUID); err != nil {
+		return c.Status(accountTokenErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Verification email sent",
+	})
+}
+
+// accountTokenErrorStatus แปลง error ของการรีเซ็ตรหัสผ่าน/ยืนยันอีเมลเป็น HTTP status
+func accountTokenErrorStatus(err error) int {
+	msg := err.Error()
+	switch msg {
+	case "email is required", "token is required", "email is not set", "invalid or expired token":
+		return fiber.StatusBadRequest
+	case "email is already verified":
+		return fiber.StatusConflict
+	}
+	if strings.HasPrefix(msg, "password must be at least") {
+		return fiber.StatusBadRequest
+	}
+	return fiber.StatusInternalServerError
+}
+
+// GetJWKS public key ที่ใช้ตรวจ JWT ของระบบ (รวม key เดิมระหว่างหมุน key)
+// GET /.well-known/jwks.json
+func (h *AuthHandler) GetJWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(fiber.Map{
+		"keys": h.authService.JSONWebKeys(),
+	})
+}
+
+// ListOAuthProviders รายการ provider ภายนอกที่เปิดใช้
+// GET /api/v1/auth/oauth/providers
+func (h *AuthHandler) ListOAuthProviders(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    h.authService.ListOAuthProviders(),
+	})
+}
+
+// StartOAuthLogin เริ่มเข้าสู่ระบบผ่าน provider (คืนค่า URL สำหรับ redirect และ state)
+// GET /api/v1/auth/oauth/:provider/authorize
+func (h *AuthHandler) StartOAuthLogin(c *fiber.Ctx) error {
+	authorization, err := h.authService.StartOAuth(c.Params("provider"), nil)
+	if err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    authorization,
+	})
+}
+
+// OAuthCallback เข้าสู่ระบบด้วย code และ state ที่ provider ส่งกลับมา
+// POST /api/v1/auth/oauth/:provider/callback
+func (h *AuthHandler) OAuthCallback(c *fiber.Ctx) error {
+	var input map[string]string
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request data",
+		})
+	}
+
+	user, accessToken, refreshToken, err := h.authService.LoginWithOAuth(
+		c.Params("provider"),
+		input["code"],
+		input["state"],
+		sessionDeviceInfo(c, input["device_name"]),
+	)
+	if err != nil {
+		// บัญชีที่เปิด 2FA: ส่ง challenge token ให้ client ยืนยันรหัสที่ /auth/login/2fa
+		var twoFactorErr *service.TwoFactorRequiredError
+		if errors.As(err, &twoFactorErr) {
+			return c.JSON(fiber.Map{
+				"success":             true,
+				"message":             "Two-factor authentication required",
+				"two_factor_required": true,
+				"challenge_token":     twoFactorErr.ChallengeToken,
+				"expires_at":          twoFactorErr.ExpiresAt,
+			})
+		}
+		return oauthErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success":       true,
+		"message":       "Login successful",
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+		"user": fiber.Map{
+			"id":             user.ID,
+			"username":       user.Username,
+			"email":          user.Email,
+			"email_verified": user.EmailVerifiedAt != nil,
+			"display_name":   user.DisplayName,
+			"status":         user.Status,
 		},
 	})
 }
+
+// StartOAuthLink เริ่มเชื่อมบัญชีของ provider กับผู้ใช้ปัจจุบัน
+// POST /api/v1/auth/oauth/:provider/link
+func (h *AuthHandler) StartOAuthLink(c *fiber.Ctx) error {
+	userUUID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	authorization, err := h.authService.StartOAuth(c.Params("provider"), &userUUID)
+	if err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    authorization,
+	})
+}
+
+// OAuthLinkCallback เชื่อมบัญชีด้วย code และ state ที่ provider ส่งกลับมา
+// POST /api/v1/auth/oauth/:provider/link/callback
+func (h *AuthHandler) OAuthLinkCallback(c *fiber.Ctx) error {
+	userUUID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	var input map[string]string
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request data",
+		})
+	}
+
+	identity, err := h.authService.LinkOAuthIdentity(userUUID, c.Params("provider"), input["code"], input["state"])
+	if err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Account linked successfully",
+		"data":    identity,
+	})
+}
+
+// ListOAuthIdentities รายการบัญชีภายนอกที่ผู้ใช้ปัจจุบันเชื่อมไว้
+// GET /api/v1/auth/oauth/identities
+func (h *AuthHandler) ListOAuthIdentities(c *fiber.Ctx) error {
+	userUUID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	identities, err := h.authService.ListOAuthIdentities(userUUID)
+	if err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    identities,
+	})
+}
+
+// UnlinkOAuthIdentity ยกเลิกการเชื่อมบัญชีภายนอก
+// DELETE /api/v1/auth/oauth/identities/:id
+func (h *AuthHandler) UnlinkOAuthIdentity(c *fiber.Ctx) error {
+	userUUID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	identityID, err := utils.ParseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.authService.UnlinkOAuthIdentity(userUUID, identityID); err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Account unlinked successfully",
+	})
+}
+
+// oauthErrorResponse ส่ง error ของ OAuth กลับพร้อม HTTP status
+func oauthErrorResponse(c *fiber.Ctx, err error) error {
+	statusCode := fiber.StatusInternalServerError
+	switch err.Error() {
+	case "unsupported oauth provider", "identity not found", "user not found":
+		statusCode = fiber.StatusNotFound
+	case "code and state are required", "invalid or expired oauth state", "oauth provider did not return an email address":
+		statusCode = fiber.StatusBadRequest
+	case "oauth sign-in failed":
+		statusCode = fiber.StatusUnauthorized
+	case "account is suspended", "bot accounts cannot sign in with oauth", "bot accounts cannot link oauth providers":
+		statusCode = fiber.StatusForbidden
+	case "an account with this email already exists, sign in with your password and link the provider",
+		"this provider account is already linked to another user", "provider is already linked",
+		"cannot unlink the last sign-in method, set a password first":
+		statusCode = fiber.StatusConflict
+	case "oauth provider is unavailable":
+		statusCode = fiber.StatusBadGateway
+	}
+
+	return c.Status(statusCode).JSON(fiber.Map{
+		"success": false,
+		"message": err.Error(),
+	})
+}
+
+// sessionDeviceInfo สร้างข้อมูลอุปกรณ์ของ session จาก request
+func sessionDeviceInfo(c *fiber.Ctx, deviceName string) *dto.SessionDeviceInfo {
+	return &dto.SessionDeviceInfo{
+		DeviceName: deviceName,
+		UserAgent:  c.Get(fiber.HeaderUserAgent),
+		IPAddress:  c.IP(),
+	}
+}
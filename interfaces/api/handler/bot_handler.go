@@ -0,0 +1,388 @@
+// interfaces/api/handler/bot_handler.go
+package handler
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+	"github.com/thizplus/gofiber-chat-api/pkg/utils"
+)
+
+// BotHandler handles bot management (for owners) and the Bot API (for bots)
+type BotHandler struct {
+	botService          service.BotService
+	notificationService service.NotificationService
+}
+
+// NewBotHandler creates a new bot handler
+func NewBotHandler(botService service.BotService, notificationService service.NotificationService) *BotHandler {
+	return &BotHandler{
+		botService:          botService,
+		notificationService: notificationService,
+	}
+}
+
+// =========== Owner management ===========
+
+// CreateBot creates a bot owned by the current user and returns its token once
+// POST /api/v1/bots
+func (h *BotHandler) CreateBot(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	var req dto.CreateBotRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body",
+		})
+	}
+
+	credentials, err := h.botService.CreateBot(userID, &req)
+	if err != nil {
+		return c.Status(botErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Bot created successfully. Store the token now, it will not be shown again",
+		"data":    credentials,
+	})
+}
+
+// ListBots lists bots owned by the current user
+// GET /api/v1/bots
+func (h *BotHandler) ListBots(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	bots, err := h.botService.ListBots(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"message": "Error listing bots: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    bots,
+	})
+}
+
+// GetBot returns a bot owned by the current user
+// GET /api/v1/bots/:botId
+func (h *BotHandler) GetBot(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	botID, err := utils.ParseUUIDParam(c, "botId")
+	if err != nil {
+		return err
+	}
+
+	bot, err := h.botService.GetBot(userID, botID)
+	if err != nil {
+		return c.Status(botErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    bot,
+	})
+}
+
+// UpdateBot updates a bot's profile and webhook settings
+// PATCH /api/v1/bots/:botId
+func (h *BotHandler) UpdateBot(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	botID, err := utils.ParseUUIDParam(c, "botId")
+	if err != nil {
+		return err
+	}
+
+	var req dto.UpdateBotRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body",
+		})
+	}
+
+	bot, err := h.botService.UpdateBot(userID, botID, &req)
+	if err != nil {
+		return c.Status(botErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Bot updated successfully",
+		"data":    bot,
+	})
+}
+
+// RotateToken issues a new API token (the old token stops working immediately)
+// POST /api/v1/bots/:botId/token
+func (h *BotHandler) RotateToken(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	botID, err := utils.ParseUUIDParam(c, "botId")
+	if err != nil {
+		return err
+	}
+
+	credentials, err := h.botService.RotateToken(userID, botID)
+	if err != nil {
+		return c.Status(botErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Bot token rotated successfully",
+		"data":    credentials,
+	})
+}
+
+// RotateWebhookSecret issues a new webhook signing secret
+// POST /api/v1/bots/:botId/webhook-secret
+func (h *BotHandler) RotateWebhookSecret(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	botID, err := utils.ParseUUIDParam(c, "botId")
+	if err != nil {
+		return err
+	}
+
+	credentials, err := h.botService.RotateWebhookSecret(userID, botID)
+	if err != nil {
+		return c.Status(botErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Webhook secret rotated successfully",
+		"data":    credentials,
+	})
+}
+
+// DeleteBot deletes a bot and removes it from every conversation
+// DELETE /api/v1/bots/:botId
+func (h *BotHandler) DeleteBot(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	botID, err := utils.ParseUUIDParam(c, "botId")
+	if err != nil {
+		return err
+	}
+
+	if err := h.botService.DeleteBot(userID, botID); err != nil {
+		return c.Status(botErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Bot deleted successfully",
+	})
+}
+
+// GetDeliveries returns the webhook delivery log of a bot
+// GET /api/v1/bots/:botId/deliveries?status=&limit=50&offset=0
+func (h *BotHandler) GetDeliveries(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	botID, err := utils.ParseUUIDParam(c, "botId")
+	if err != nil {
+		return err
+	}
+
+	limit := c.QueryInt("limit", 50)
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	offset := c.QueryInt("offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	deliveries, total, err := h.botService.GetDeliveries(userID, botID, c.Query("status"), limit, offset)
+	if err != nil {
+		return c.Status(botErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    deliveries,
+		"pagination": fiber.Map{
+			"total":  total,
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
+// =========== Bot API ===========
+
+// GetMe returns the authenticated bot
+// GET /api/v1/bot-api/me
+func (h *BotHandler) GetMe(c *fiber.Ctx) error {
+	botUserID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	bot, err := h.botService.GetMe(botUserID)
+	if err != nil {
+		return c.Status(botErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    bot,
+	})
+}
+
+// SendMessage sends a text message (optionally a reply) as the bot
+// POST /api/v1/bot-api/conversations/:conversationId/messages
+func (h *BotHandler) SendMessage(c *fiber.Ctx) error {
+	botUserID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	var req dto.BotSendMessageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body",
+		})
+	}
+
+	message, err := h.botService.SendMessage(botUserID, conversationID, &req)
+	if err != nil {
+		return c.Status(botErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	h.notificationService.NotifyNewMessage(conversationID, message)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Message sent successfully",
+		"data":    message,
+	})
+}
+
+// botErrorStatus แปลง error ของ BotService เป็น HTTP status
+func botErrorStatus(err error) int {
+	switch err.Error() {
+	case "bot not found", "user not found", "message not found":
+		return fiber.StatusNotFound
+	case "bots cannot create bots", "user is not a member of this conversation",
+		"you are not a member of this conversation", "only channel admins can post in this channel":
+		return fiber.StatusForbidden
+	case "username already exists":
+		return fiber.StatusConflict
+	case "invalid delivery status", "message content cannot be empty",
+		"reply target is not in this conversation", "cannot reply to deleted message":
+		return fiber.StatusBadRequest
+	case "bot webhooks are not enabled":
+		return fiber.StatusServiceUnavailable
+	default:
+		if strings.HasPrefix(err.Error(), "bot username must") || strings.HasPrefix(err.Error(), "invalid webhook url") {
+			return fiber.StatusBadRequest
+		}
+		if strings.HasPrefix(err.Error(), "bot limit reached") {
+			return fiber.StatusForbidden
+		}
+		if isContentFilterRejection(err) {
+			return fiber.StatusUnprocessableEntity
+		}
+		return fiber.StatusInternalServerError
+	}
+}
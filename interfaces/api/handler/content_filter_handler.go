@@ -0,0 +1,117 @@
+// interfaces/api/handler/content_filter_handler.go
+package handler
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+	"github.com/thizplus/gofiber-chat-api/pkg/utils"
+)
+
+// contentFilterRejectionPrefix ข้อความ error เมื่อ content filter ปฏิเสธข้อความ
+const contentFilterRejectionPrefix = "message blocked by content filter"
+
+// ContentFilterHandler handles per-group content filter settings
+type ContentFilterHandler struct {
+	contentFilterService service.ContentFilterService
+}
+
+// NewContentFilterHandler creates a new content filter handler
+func NewContentFilterHandler(contentFilterService service.ContentFilterService) *ContentFilterHandler {
+	return &ContentFilterHandler{contentFilterService: contentFilterService}
+}
+
+// GetSettings returns the content filter settings of a group
+// GET /api/v1/conversations/:conversationId/content-filters
+func (h *ContentFilterHandler) GetSettings(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	settings, err := h.contentFilterService.GetSettings(conversationID, userID)
+	if err != nil {
+		return c.Status(contentFilterErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    settings,
+	})
+}
+
+// UpdateSettings updates the content filter settings of a group (only the filters sent are changed)
+// PUT /api/v1/conversations/:conversationId/content-filters
+func (h *ContentFilterHandler) UpdateSettings(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	var req dto.UpdateContentFilterSettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body",
+		})
+	}
+
+	settings, err := h.contentFilterService.UpdateSettings(conversationID, userID, &req)
+	if err != nil {
+		return c.Status(contentFilterErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Content filter settings updated successfully",
+		"data":    settings,
+	})
+}
+
+// contentFilterErrorStatus แปลง error ของ ContentFilterService เป็น HTTP status
+func contentFilterErrorStatus(err error) int {
+	switch err.Error() {
+	case "conversation not found":
+		return fiber.StatusNotFound
+	case "you don't have permission to manage content filters", "user is not a member of this conversation":
+		return fiber.StatusForbidden
+	case "content filters can only be configured for groups and channels":
+		return fiber.StatusBadRequest
+	default:
+		if strings.HasPrefix(err.Error(), "invalid ") ||
+			strings.HasPrefix(err.Error(), "unknown content filter") {
+			return fiber.StatusBadRequest
+		}
+		return fiber.StatusInternalServerError
+	}
+}
+
+// isContentFilterRejection ตรวจสอบว่า error มาจาก content filter ปฏิเสธข้อความ (ใช้ 422)
+func isContentFilterRejection(err error) bool {
+	return strings.HasPrefix(err.Error(), contentFilterRejectionPrefix)
+}
@@ -4,11 +4,13 @@ package handler
 import (
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
 	"github.com/thizplus/gofiber-chat-api/domain/repository"
 	"github.com/thizplus/gofiber-chat-api/domain/service"
 	"github.com/thizplus/gofiber-chat-api/domain/types"
@@ -67,10 +69,11 @@ func (h *ConversationHandler) Create(c *fiber.Ctx) error {
 
 	// ตรวจสอบและกำหนดค่า type
 	conversationType, ok := input["type"].(string)
-	if !ok || (conversationType != "direct" && conversationType != "group" && conversationType != "business") {
+	if !ok || (conversationType != "direct" && conversationType != "group" && conversationType != "business" &&
+		conversationType != models.ConversationTypeChannel) {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"success": false,
-			"message": "Invalid conversation type, must be 'direct', 'group', or 'business'",
+			"message": "Invalid conversation type, must be 'direct', 'group', 'channel', or 'business'",
 		})
 	}
 
@@ -80,6 +83,8 @@ func (h *ConversationHandler) Create(c *fiber.Ctx) error {
 		return h.createDirectConversation(c, userID, input)
 	case "group":
 		return h.createGroupConversation(c, userID, input)
+	case models.ConversationTypeChannel:
+		return h.createChannelConversation(c, userID, input)
 	default:
 		// ไม่ควรเข้าเงื่อนไขนี้เนื่องจากมีการตรวจสอบข้างต้นแล้ว
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -203,6 +208,42 @@ func (h *ConversationHandler) createGroupConversation(c *fiber.Ctx, userID uuid.
 	})
 }
 
+// createChannelConversation สร้างช่องกระจายข่าว (ผู้ติดตามเข้าร่วมผ่านลิงก์เชิญ)
+func (h *ConversationHandler) createChannelConversation(c *fiber.Ctx, userID uuid.UUID, input types.JSONB) error {
+	title, ok := input["title"].(string)
+	if !ok || strings.TrimSpace(title) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Channel requires a title",
+		})
+	}
+
+	iconURL := ""
+	if iconURLValue, ok := input["icon_url"].(string); ok {
+		iconURL = iconURLValue
+	}
+
+	conversation, err := h.conversationService.CreateChannelConversation(userID, title, iconURL)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	if err := h.notificationService.NotifyConversationCreated([]uuid.UUID{userID}, conversation); err != nil {
+		log.Printf("Failed to send channel created notification: %v", err)
+	}
+
+	h.groupActivityService.LogGroupCreated(conversation.ID, userID)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success":      true,
+		"message":      "Channel created successfully",
+		"conversation": conversation,
+	})
+}
+
 
 // GetUserConversations ดึงรายการการสนทนาทั้งหมดของผู้ใช้
 func (h *ConversationHandler) GetUserConversations(c *fiber.Ctx) error {
@@ -606,6 +647,86 @@ func (h *ConversationHandler) ToggleMuteConversation(c *fiber.Ctx) error {
 	})
 }
 
+// GetNotificationSettings ดึงการตั้งค่าการแจ้งเตือนของผู้ใช้ในการสนทนา
+// GET /api/v1/conversations/:conversationId/notification-settings
+func (h *ConversationHandler) GetNotificationSettings(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	settings, err := h.conversationService.GetNotificationSettings(conversationID, userID)
+	if err != nil {
+		return c.Status(notificationSettingsErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    settings,
+	})
+}
+
+// UpdateNotificationSettings ตั้งค่าระดับการแจ้งเตือน (all/mentions/none) และปิดเสียงถาวรหรือถึงเวลาที่กำหนด
+// PUT /api/v1/conversations/:conversationId/notification-settings
+func (h *ConversationHandler) UpdateNotificationSettings(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	var req dto.UpdateConversationNotificationSettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request data: " + err.Error(),
+		})
+	}
+
+	settings, err := h.conversationService.UpdateNotificationSettings(conversationID, userID, &req)
+	if err != nil {
+		return c.Status(notificationSettingsErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Notification settings updated successfully",
+		"data":    settings,
+	})
+}
+
+func notificationSettingsErrorStatus(err error) int {
+	switch err.Error() {
+	case "you are not a member of this conversation":
+		return fiber.StatusForbidden
+	case "invalid notification level", "muted_until must be in the future", "muted_until requires muted to be true":
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
 // UpdateConversation อัปเดตข้อมูลการสนทนา (ชื่อ, icon)
 func (h *ConversationHandler) UpdateConversation(c *fiber.Ctx) error {
 	// ดึงข้อมูลผู้ใช้จาก context
@@ -1107,7 +1228,7 @@ func (h *ConversationHandler) TransferOwnership(c *fiber.Ctx) error {
 			"new owner is not a member of this conversation":
 			statusCode = fiber.StatusNotFound
 		case "cannot transfer ownership to yourself",
-			"ownership transfer is only available for group conversations":
+			"ownership transfer is only available for groups and channels":
 			statusCode = fiber.StatusBadRequest
 		}
 
@@ -108,7 +108,7 @@ func (h *ConversationMemberHandler) AddConversationMember(c *fiber.Ctx) error {
 	h.notificationService.NotifyUserAddedToConversation(conversationID, newMemberID)
 
 	// บันทึก activity log
-	if err := h.groupActivityService.LogMemberAdded(conversationID, userID, newMemberID); err != nil {
+	if err := h.groupActivityService.LogMemberAdded(conversationID, userID, newMemberID, nil); err != nil {
 		// Log error แต่ไม่ fail request (activity log เป็น secondary)
 		println("⚠️ [AddMember] Failed to log activity:", err.Error())
 	} else {
@@ -221,7 +221,7 @@ func (h *ConversationMemberHandler) BulkAddConversationMembers(c *fiber.Ctx) err
 		h.notificationService.NotifyUserAddedToConversation(conversationID, memberUUID)
 
 		// บันทึก activity log
-		if err := h.groupActivityService.LogMemberAdded(conversationID, userID, memberUUID); err != nil {
+		if err := h.groupActivityService.LogMemberAdded(conversationID, userID, memberUUID, nil); err != nil {
 			println("⚠️ [BulkAddMembers] Failed to log activity for member:", member.UserID, err.Error())
 		}
 	}
@@ -275,7 +275,8 @@ func (h *ConversationMemberHandler) GetConversationMembers(c *fiber.Ctx) error {
 	if err != nil {
 		// จัดการรหัสสถานะตามข้อผิดพลาด
 		statusCode := fiber.StatusInternalServerError
-		if err.Error() == "you are not a member of this conversation" {
+		if err.Error() == "you are not a member of this conversation" ||
+			err.Error() == "only channel admins can view subscribers" {
 			statusCode = fiber.StatusForbidden
 		}
 
@@ -497,7 +498,7 @@ func (h *ConversationMemberHandler) ChangeRole(c *fiber.Ctx) error {
 	}
 
 	// 4. Validate role
-	validRoles := []string{"owner", "admin", "member"}
+	validRoles := []string{"owner", "admin", "member", "subscriber"}
 	isValidRole := false
 	for _, validRole := range validRoles {
 		if input.Role == validRole {
@@ -508,7 +509,7 @@ func (h *ConversationMemberHandler) ChangeRole(c *fiber.Ctx) error {
 	if !isValidRole {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"success": false,
-			"message": "Invalid role. Valid roles: owner, admin, member",
+			"message": "Invalid role. Valid roles: owner, admin, member, subscriber",
 		})
 	}
 
@@ -551,6 +552,12 @@ func (h *ConversationMemberHandler) ChangeRole(c *fiber.Ctx) error {
 	newRole := models.MemberRole(input.Role)
 	updatedMember, err := h.memberService.ChangeRole(conversationID, targetUserID, newRole)
 	if err != nil {
+		if err.Error() == "subscriber role is only available in channels" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"message": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
 			"message": "Failed to change member role: " + err.Error(),
@@ -558,10 +565,10 @@ func (h *ConversationMemberHandler) ChangeRole(c *fiber.Ctx) error {
 	}
 
 	// 10. ส่ง WebSocket notification
-	h.notificationService.NotifyMemberRoleChanged(conversationID, targetUserID, oldRole, string(newRole), userID)
+	h.notificationService.NotifyMemberRoleChanged(conversationID, targetUserID, oldRole, string(updatedMember.Role), userID)
 
 	// บันทึก activity log
-	h.groupActivityService.LogMemberRoleChanged(conversationID, userID, targetUserID, oldRole, string(newRole))
+	h.groupActivityService.LogMemberRoleChanged(conversationID, userID, targetUserID, oldRole, string(updatedMember.Role))
 
 	// 11. ส่งผลลัพธ์กลับ
 	return c.JSON(fiber.Map{
@@ -575,3 +582,135 @@ func (h *ConversationMemberHandler) ChangeRole(c *fiber.Ctx) error {
 		},
 	})
 }
+
+// UpdatePollPermission กำหนดว่าใครสร้างโพลในกลุ่มได้ (all, admins, owner) - owner เท่านั้น
+func (h *ConversationMemberHandler) UpdatePollPermission(c *fiber.Ctx) error {
+	// 1. ดึงข้อมูลผู้ใช้จาก context
+	userID, err := uuid.Parse(c.Locals("userID").(string))
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized",
+		})
+	}
+
+	// 2. ดึง conversation ID
+	conversationID, err := uuid.Parse(c.Params("conversationId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid conversation ID",
+		})
+	}
+
+	// 3. รับระดับสิทธิ์
+	var input dto.PollPermissionRequest
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body",
+		})
+	}
+
+	// 4. บันทึกการตั้งค่า
+	if err := h.memberService.SetPollPermission(conversationID, userID, input.Level); err != nil {
+		statusCode := fiber.StatusInternalServerError
+		switch err.Error() {
+		case "invalid poll permission level", "poll permission can only be set for group conversations":
+			statusCode = fiber.StatusBadRequest
+		case "user is not a member of this conversation", "only the owner can change poll permission":
+			statusCode = fiber.StatusForbidden
+		}
+
+		return c.Status(statusCode).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	// 5. แจ้งสมาชิกในกลุ่มผ่าน WebSocket
+	h.notificationService.NotifyConversationUpdated(conversationID, map[string]interface{}{
+		"conversation_id": conversationID.String(),
+		"poll_permission": input.Level,
+	})
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Poll permission updated successfully",
+		"data": fiber.Map{
+			"conversation_id": conversationID,
+			"poll_permission": input.Level,
+		},
+	})
+}
+
+// UpdateMessageTTL ตั้งค่าข้อความที่หายไปเอง (ttl เป็นวินาที: 0 = ปิด, 3600, 86400, 604800)
+func (h *ConversationMemberHandler) UpdateMessageTTL(c *fiber.Ctx) error {
+	// 1. ดึงข้อมูลผู้ใช้จาก context
+	userID, err := uuid.Parse(c.Locals("userID").(string))
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized",
+		})
+	}
+
+	// 2. ดึง conversation ID
+	conversationID, err := uuid.Parse(c.Params("conversationId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid conversation ID",
+		})
+	}
+
+	// 3. รับค่า TTL
+	var input dto.ConversationMessageTTLRequest
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body",
+		})
+	}
+
+	// 4. บันทึกการตั้งค่า
+	oldTTL, systemMessage, err := h.memberService.SetMessageTTL(conversationID, userID, input.TTL)
+	if err != nil {
+		statusCode := fiber.StatusInternalServerError
+		switch err.Error() {
+		case "invalid message ttl":
+			statusCode = fiber.StatusBadRequest
+		case "user is not a member of this conversation", "only owner or admin can change disappearing messages":
+			statusCode = fiber.StatusForbidden
+		}
+
+		return c.Status(statusCode).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	// 5. แจ้งสมาชิกและบันทึก activity log (เฉพาะเมื่อค่าเปลี่ยน)
+	if oldTTL != input.TTL {
+		h.notificationService.NotifyConversationUpdated(conversationID, map[string]interface{}{
+			"conversation_id": conversationID.String(),
+			"message_ttl":     input.TTL,
+		})
+
+		if systemMessage != nil {
+			h.notificationService.NotifyNewMessage(conversationID, systemMessage)
+		}
+
+		h.groupActivityService.LogMessageTTLChanged(conversationID, userID, oldTTL, input.TTL)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Disappearing messages updated successfully",
+		"data": fiber.Map{
+			"conversation_id": conversationID,
+			"old_ttl":         oldTTL,
+			"message_ttl":     input.TTL,
+		},
+	})
+}
@@ -0,0 +1,187 @@
+// interfaces/api/handler/conversation_webhook_handler.go
+package handler
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+	"github.com/thizplus/gofiber-chat-api/pkg/utils"
+)
+
+// ConversationWebhookHandler handles incoming webhook management and execution
+type ConversationWebhookHandler struct {
+	webhookService      service.ConversationWebhookService
+	notificationService service.NotificationService
+}
+
+// NewConversationWebhookHandler creates a new conversation webhook handler
+func NewConversationWebhookHandler(webhookService service.ConversationWebhookService, notificationService service.NotificationService) *ConversationWebhookHandler {
+	return &ConversationWebhookHandler{
+		webhookService:      webhookService,
+		notificationService: notificationService,
+	}
+}
+
+// CreateWebhook creates an incoming webhook for a group and returns its token once
+// POST /api/v1/conversations/:conversationId/webhooks
+func (h *ConversationWebhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	var req dto.CreateConversationWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body",
+		})
+	}
+
+	credentials, err := h.webhookService.CreateWebhook(userID, conversationID, &req)
+	if err != nil {
+		return c.Status(conversationWebhookErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Webhook created successfully. Store the URL now, the token will not be shown again",
+		"data":    credentials,
+	})
+}
+
+// ListWebhooks lists incoming webhooks of a group
+// GET /api/v1/conversations/:conversationId/webhooks?include_revoked=false
+func (h *ConversationWebhookHandler) ListWebhooks(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	webhooks, err := h.webhookService.ListWebhooks(userID, conversationID, c.QueryBool("include_revoked", false))
+	if err != nil {
+		return c.Status(conversationWebhookErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    webhooks,
+	})
+}
+
+// RevokeWebhook revokes an incoming webhook
+// DELETE /api/v1/conversations/:conversationId/webhooks/:webhookId
+func (h *ConversationWebhookHandler) RevokeWebhook(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+	webhookID, err := utils.ParseUUIDParam(c, "webhookId")
+	if err != nil {
+		return err
+	}
+
+	if err := h.webhookService.RevokeWebhook(userID, conversationID, webhookID); err != nil {
+		return c.Status(conversationWebhookErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Webhook revoked successfully",
+	})
+}
+
+// Execute posts a message into the webhook's conversation (authenticated by the token in the path)
+// POST /api/v1/hooks/:token
+func (h *ConversationWebhookHandler) Execute(c *fiber.Ctx) error {
+	var payload dto.IncomingWebhookPayload
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body",
+		})
+	}
+
+	message, err := h.webhookService.Execute(c.Params("token"), &payload)
+	if err != nil {
+		return c.Status(conversationWebhookErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	h.notificationService.NotifyNewMessage(message.ConversationID, message)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Message posted successfully",
+		"data": fiber.Map{
+			"message_id":      message.ID,
+			"conversation_id": message.ConversationID,
+		},
+	})
+}
+
+// conversationWebhookErrorStatus แปลง error ของ ConversationWebhookService เป็น HTTP status
+func conversationWebhookErrorStatus(err error) int {
+	switch err.Error() {
+	case "webhook not found", "conversation not found":
+		return fiber.StatusNotFound
+	case "webhook has been revoked":
+		return fiber.StatusGone
+	case "you don't have permission to manage webhooks", "user is not a member of this conversation":
+		return fiber.StatusForbidden
+	case "webhooks are only available for groups and channels", "invalid avatar url",
+		"webhook payload must contain text or attachments", "message content cannot be empty":
+		return fiber.StatusBadRequest
+	default:
+		if strings.HasPrefix(err.Error(), "webhook name must") ||
+			strings.HasPrefix(err.Error(), "too many active webhooks") ||
+			strings.HasPrefix(err.Error(), "text must not exceed") ||
+			strings.HasPrefix(err.Error(), "display_name must not exceed") ||
+			strings.HasPrefix(err.Error(), "too many attachments") ||
+			strings.HasPrefix(err.Error(), "attachment ") {
+			return fiber.StatusBadRequest
+		}
+		if isContentFilterRejection(err) {
+			return fiber.StatusUnprocessableEntity
+		}
+		return fiber.StatusInternalServerError
+	}
+}
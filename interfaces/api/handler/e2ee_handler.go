@@ -0,0 +1,192 @@
+// interfaces/api/handler/e2ee_handler.go
+package handler
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+	"github.com/thizplus/gofiber-chat-api/pkg/utils"
+)
+
+// E2EEHandler handles end-to-end encryption key bundle endpoints
+type E2EEHandler struct {
+	e2eeService service.E2EEService
+}
+
+// NewE2EEHandler creates a new E2EE handler
+func NewE2EEHandler(e2eeService service.E2EEService) *E2EEHandler {
+	return &E2EEHandler{e2eeService: e2eeService}
+}
+
+// UploadBundle uploads (or replaces) the key bundle of one of the current user's devices
+// PUT /api/v1/keys/devices/:device_id
+func (h *E2EEHandler) UploadBundle(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	var req dto.UploadKeyBundleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body",
+		})
+	}
+
+	bundle, err := h.e2eeService.UploadBundle(userID, c.Params("device_id"), &req)
+	if err != nil {
+		return c.Status(e2eeErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Key bundle uploaded successfully",
+		"data":    bundle,
+	})
+}
+
+// UploadPreKeys tops up one-time pre-keys of a device
+// POST /api/v1/keys/devices/:device_id/prekeys
+func (h *E2EEHandler) UploadPreKeys(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	var req dto.UploadPreKeysRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body",
+		})
+	}
+
+	count, err := h.e2eeService.UploadPreKeys(userID, c.Params("device_id"), req.OneTimePreKeys)
+	if err != nil {
+		return c.Status(e2eeErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Pre-keys uploaded successfully",
+		"data": fiber.Map{
+			"remaining": count,
+		},
+	})
+}
+
+// GetPreKeyCount returns how many one-time pre-keys a device has left
+// GET /api/v1/keys/devices/:device_id/prekeys/count
+func (h *E2EEHandler) GetPreKeyCount(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	count, err := h.e2eeService.GetPreKeyCount(userID, c.Params("device_id"))
+	if err != nil {
+		return c.Status(e2eeErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"remaining": count,
+		},
+	})
+}
+
+// RemoveDevice deletes the key bundle of one of the current user's devices
+// DELETE /api/v1/keys/devices/:device_id
+func (h *E2EEHandler) RemoveDevice(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	if err := h.e2eeService.RemoveDevice(userID, c.Params("device_id")); err != nil {
+		return c.Status(e2eeErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Device keys removed successfully",
+	})
+}
+
+// GetUserBundles fetches key bundles for every device of a user (claims one pre-key per device)
+// GET /api/v1/keys/users/:user_id
+func (h *E2EEHandler) GetUserBundles(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	targetUserID, err := utils.ParseUUIDParam(c, "user_id")
+	if err != nil {
+		return err
+	}
+
+	bundles, err := h.e2eeService.GetUserBundles(userID, targetUserID)
+	if err != nil {
+		return c.Status(e2eeErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    bundles,
+	})
+}
+
+// e2eeErrorStatus แปลง error ของ E2EEService เป็น HTTP status
+func e2eeErrorStatus(err error) int {
+	switch err.Error() {
+	case "invalid device id", "key bundle is required", "invalid identity key",
+		"invalid signed pre-key", "invalid signed pre-key signature", "invalid one-time pre-key",
+		"duplicate one-time pre-key id", "one-time pre-keys are required":
+		return fiber.StatusBadRequest
+	case "device key bundle not found", "user not found":
+		return fiber.StatusNotFound
+	case "you are not allowed to fetch keys for this user":
+		return fiber.StatusForbidden
+	default:
+		if strings.HasPrefix(err.Error(), "too many one-time pre-keys") {
+			return fiber.StatusBadRequest
+		}
+		return fiber.StatusInternalServerError
+	}
+}
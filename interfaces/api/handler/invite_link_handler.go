@@ -0,0 +1,220 @@
+// interfaces/api/handler/invite_link_handler.go
+package handler
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+	"github.com/thizplus/gofiber-chat-api/pkg/utils"
+)
+
+// InviteLinkHandler handles group invite link endpoints
+type InviteLinkHandler struct {
+	inviteLinkService service.InviteLinkService
+}
+
+// NewInviteLinkHandler creates a new invite link handler
+func NewInviteLinkHandler(inviteLinkService service.InviteLinkService) *InviteLinkHandler {
+	return &InviteLinkHandler{inviteLinkService: inviteLinkService}
+}
+
+// CreateLink creates a shareable invite link for a group
+// POST /api/v1/conversations/:conversationId/invite-links
+func (h *InviteLinkHandler) CreateLink(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	var req dto.CreateInviteLinkRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+	}
+
+	link, err := h.inviteLinkService.CreateLink(userID, conversationID, &req)
+	if err != nil {
+		return c.Status(inviteLinkErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Invite link created successfully",
+		"data":    link,
+	})
+}
+
+// ListLinks lists invite links of a group
+// GET /api/v1/conversations/:conversationId/invite-links?include_revoked=false
+func (h *InviteLinkHandler) ListLinks(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	links, err := h.inviteLinkService.ListLinks(userID, conversationID, c.QueryBool("include_revoked", false))
+	if err != nil {
+		return c.Status(inviteLinkErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    links,
+	})
+}
+
+// RevokeLink revokes an invite link
+// DELETE /api/v1/conversations/:conversationId/invite-links/:linkId
+func (h *InviteLinkHandler) RevokeLink(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+	linkID, err := utils.ParseUUIDParam(c, "linkId")
+	if err != nil {
+		return err
+	}
+
+	if err := h.inviteLinkService.RevokeLink(userID, conversationID, linkID); err != nil {
+		return c.Status(inviteLinkErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Invite link revoked successfully",
+	})
+}
+
+// PreviewLink returns the group behind an invite code
+// GET /api/v1/invites/:code
+func (h *InviteLinkHandler) PreviewLink(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	preview, err := h.inviteLinkService.PreviewLink(userID, c.Params("code"))
+	if err != nil {
+		return c.Status(inviteLinkErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    preview,
+	})
+}
+
+// JoinGroup joins a group through an invite code (202 with a join request when the link requires approval)
+// POST /api/v1/invites/:code/join
+func (h *InviteLinkHandler) JoinGroup(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	var req dto.JoinViaInviteLinkRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+	}
+
+	result, err := h.inviteLinkService.JoinViaLink(userID, c.Params("code"), req.Message)
+	if err != nil {
+		return c.Status(inviteLinkErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	if result.Status == dto.InviteJoinStatusPending {
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+			"success": true,
+			"message": "Join request submitted and awaiting admin approval",
+			"data":    result,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Joined group successfully",
+		"data":    result,
+	})
+}
+
+// inviteLinkErrorStatus แปลง error ของ InviteLinkService เป็น HTTP status
+func inviteLinkErrorStatus(err error) int {
+	switch err.Error() {
+	case "invite link not found", "conversation not found", "user not found":
+		return fiber.StatusNotFound
+	case "invite link has been revoked", "invite link has expired",
+		"invite link has reached its usage limit", "invite link is no longer valid":
+		return fiber.StatusGone
+	case "you are already a member of this conversation", "you already have a pending join request":
+		return fiber.StatusConflict
+	case "you don't have permission to manage invite links", "user is not a member of this conversation":
+		return fiber.StatusForbidden
+	case "invite links are only available for groups and channels", "join requests are only available for groups and channels":
+		return fiber.StatusBadRequest
+	default:
+		if strings.HasPrefix(err.Error(), "expires_in must be") ||
+			strings.HasPrefix(err.Error(), "max_uses must be") ||
+			strings.HasPrefix(err.Error(), "too many active invite links") ||
+			strings.HasPrefix(err.Error(), "join request message is too long") {
+			return fiber.StatusBadRequest
+		}
+		return fiber.StatusInternalServerError
+	}
+}
@@ -0,0 +1,198 @@
+// interfaces/api/handler/join_request_handler.go
+package handler
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+	"github.com/thizplus/gofiber-chat-api/pkg/utils"
+)
+
+// JoinRequestHandler handles group join request endpoints
+type JoinRequestHandler struct {
+	joinRequestService service.JoinRequestService
+}
+
+// NewJoinRequestHandler creates a new join request handler
+func NewJoinRequestHandler(joinRequestService service.JoinRequestService) *JoinRequestHandler {
+	return &JoinRequestHandler{joinRequestService: joinRequestService}
+}
+
+// SubmitRequest asks to join a group
+// POST /api/v1/conversations/:conversationId/join-requests
+func (h *JoinRequestHandler) SubmitRequest(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	var req dto.SubmitJoinRequestRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+	}
+
+	request, err := h.joinRequestService.SubmitRequest(userID, conversationID, req.Message, nil)
+	if err != nil {
+		return c.Status(joinRequestErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Join request submitted successfully",
+		"data":    request,
+	})
+}
+
+// ListRequests lists join requests of a group
+// GET /api/v1/conversations/:conversationId/join-requests?status=pending&limit=20&offset=0
+func (h *JoinRequestHandler) ListRequests(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	limit := c.QueryInt("limit", 20)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset := c.QueryInt("offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	requests, total, err := h.joinRequestService.ListRequests(userID, conversationID, c.Query("status", "pending"), limit, offset)
+	if err != nil {
+		return c.Status(joinRequestErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    requests,
+		"pagination": fiber.Map{
+			"total":  total,
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
+// ApproveRequest approves a join request and adds the requester to the group
+// POST /api/v1/conversations/:conversationId/join-requests/:requestId/approve
+func (h *JoinRequestHandler) ApproveRequest(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+	requestID, err := utils.ParseUUIDParam(c, "requestId")
+	if err != nil {
+		return err
+	}
+
+	request, err := h.joinRequestService.ApproveRequest(userID, conversationID, requestID)
+	if err != nil {
+		return c.Status(joinRequestErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Join request approved successfully",
+		"data":    request,
+	})
+}
+
+// RejectRequest rejects a join request
+// POST /api/v1/conversations/:conversationId/join-requests/:requestId/reject
+func (h *JoinRequestHandler) RejectRequest(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+	requestID, err := utils.ParseUUIDParam(c, "requestId")
+	if err != nil {
+		return err
+	}
+
+	request, err := h.joinRequestService.RejectRequest(userID, conversationID, requestID)
+	if err != nil {
+		return c.Status(joinRequestErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Join request rejected successfully",
+		"data":    request,
+	})
+}
+
+// joinRequestErrorStatus แปลง error ของ JoinRequestService เป็น HTTP status
+func joinRequestErrorStatus(err error) int {
+	switch err.Error() {
+	case "conversation not found", "join request not found", "user not found", "user to add not found":
+		return fiber.StatusNotFound
+	case "you are already a member of this conversation", "you already have a pending join request",
+		"join request has already been reviewed":
+		return fiber.StatusConflict
+	case "you don't have permission to review join requests", "user is not a member of this conversation",
+		"only admins can add members", "you are not a member of this conversation":
+		return fiber.StatusForbidden
+	case "join requests are only available for groups and channels", "invalid join request status":
+		return fiber.StatusBadRequest
+	default:
+		if strings.HasPrefix(err.Error(), "join request message is too long") {
+			return fiber.StatusBadRequest
+		}
+		return fiber.StatusInternalServerError
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -64,8 +65,8 @@ func (h *MessageHandler) checkBlockStatusBeforeSend(userID, conversationID uuid.
 		return fmt.Errorf("failed to get conversation: %w", err)
 	}
 
-	// ✅ ถ้าเป็น group chat → อนุญาตให้ส่งได้เสมอ (ไม่ตรวจสอบ block)
-	if conversation.Type == "group" {
+	// ✅ ถ้าเป็น group chat หรือ channel → อนุญาตให้ส่งได้เสมอ (ไม่ตรวจสอบ block)
+	if conversation.Type == "group" || conversation.IsChannel() {
 		return nil
 	}
 
@@ -194,10 +195,13 @@ func (h *MessageHandler) SendTextMessage(c *fiber.Ctx) error {
 	if err != nil {
 		statusCode := fiber.StatusInternalServerError
 		// ตรวจสอบประเภทข้อผิดพลาดเพื่อกำหนด status code ที่เหมาะสม
-		if err.Error() == "user is not a member of this conversation" {
+		if err.Error() == "user is not a member of this conversation" ||
+			err.Error() == "only channel admins can post in this channel" {
 			statusCode = fiber.StatusForbidden
 		} else if err.Error() == "message content cannot be empty" {
 			statusCode = fiber.StatusBadRequest
+		} else if isContentFilterRejection(err) {
+			statusCode = fiber.StatusUnprocessableEntity
 		}
 
 		return c.Status(statusCode).JSON(fiber.Map{
@@ -301,7 +305,8 @@ func (h *MessageHandler) SendStickerMessage(c *fiber.Ctx) error {
 	if err != nil {
 		statusCode := fiber.StatusInternalServerError
 		// ตรวจสอบประเภทข้อผิดพลาด
-		if err.Error() == "user is not a member of this conversation" {
+		if err.Error() == "user is not a member of this conversation" ||
+			err.Error() == "only channel admins can post in this channel" {
 			statusCode = fiber.StatusForbidden
 		} else if err.Error() == "sticker URL is required" {
 			statusCode = fiber.StatusBadRequest
@@ -399,7 +404,8 @@ func (h *MessageHandler) SendImageMessage(c *fiber.Ctx) error {
 	if err != nil {
 		statusCode := fiber.StatusInternalServerError
 		// ตรวจสอบประเภทข้อผิดพลาด
-		if err.Error() == "user is not a member of this conversation" {
+		if err.Error() == "user is not a member of this conversation" ||
+			err.Error() == "only channel admins can post in this channel" {
 			statusCode = fiber.StatusForbidden
 		} else if err.Error() == "image URL is required" {
 			statusCode = fiber.StatusBadRequest
@@ -499,7 +505,8 @@ func (h *MessageHandler) SendFileMessage(c *fiber.Ctx) error {
 	if err != nil {
 		statusCode := fiber.StatusInternalServerError
 		// ตรวจสอบประเภทข้อผิดพลาด
-		if err.Error() == "user is not a member of this conversation" {
+		if err.Error() == "user is not a member of this conversation" ||
+			err.Error() == "only channel admins can post in this channel" {
 			statusCode = fiber.StatusForbidden
 		} else if err.Error() == "file URL is required" {
 			statusCode = fiber.StatusBadRequest
@@ -596,7 +603,8 @@ func (h *MessageHandler) SendBulkMessages(c *fiber.Ctx) error {
 		fmt.Printf("❌ [SendBulkMessages] Service error: %v\n", err)
 		statusCode := fiber.StatusInternalServerError
 		// ตรวจสอบประเภทข้อผิดพลาด
-		if err.Error() == "user is not a member of this conversation" {
+		if err.Error() == "user is not a member of this conversation" ||
+			err.Error() == "only channel admins can post in this channel" {
 			statusCode = fiber.StatusForbidden
 		} else if err.Error() == "maximum 10 files per album" ||
 		          err.Error() == "at least one file is required" {
@@ -694,6 +702,8 @@ func (h *MessageHandler) EditMessage(c *fiber.Ctx) error {
 			statusCode = fiber.StatusForbidden
 		} else if err.Error() == "cannot edit deleted message" || err.Error() == "only text messages can be edited" {
 			statusCode = fiber.StatusBadRequest
+		} else if isContentFilterRejection(err) {
+			statusCode = fiber.StatusUnprocessableEntity
 		}
 
 		return c.Status(statusCode).JSON(fiber.Map{
@@ -892,10 +902,13 @@ func (h *MessageHandler) ReplyToMessage(c *fiber.Ctx) error {
 		// ตรวจสอบประเภทข้อผิดพลาด
 		if err.Error() == "message not found" {
 			statusCode = fiber.StatusNotFound
-		} else if err.Error() == "you are not a member of this conversation" {
+		} else if err.Error() == "you are not a member of this conversation" ||
+			err.Error() == "only channel admins can post in this channel" {
 			statusCode = fiber.StatusForbidden
 		} else if err.Error() == "cannot reply to deleted message" || err.Error() == "invalid message type" {
 			statusCode = fiber.StatusBadRequest
+		} else if isContentFilterRejection(err) {
+			statusCode = fiber.StatusUnprocessableEntity
 		}
 
 		return c.Status(statusCode).JSON(fiber.Map{
@@ -1267,7 +1280,8 @@ func (h *MessageHandler) ForwardMessages(c *fiber.Ctx) error {
 	if err != nil {
 		statusCode := fiber.StatusInternalServerError
 		if err.Error() == "user is not a member of the source conversation" ||
-		   err.Error() == "user is not a member of the target conversation" {
+		   err.Error() == "user is not a member of the target conversation" ||
+		   err.Error() == "only channel admins can post in this channel" {
 			statusCode = fiber.StatusForbidden
 		} else if err.Error() == "message not found" {
 			statusCode = fiber.StatusNotFound
@@ -1302,3 +1316,425 @@ func (h *MessageHandler) ForwardMessages(c *fiber.Ctx) error {
 	})
 }
 
+
+// AddReaction เพิ่มปฏิกิริยา (emoji) ต่อข้อความ
+// ส่ง "toggle": true เพื่อสลับสถานะ (กดซ้ำเพื่อเอาออก)
+func (h *MessageHandler) AddReaction(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	messageID, err := utils.ParseUUIDParam(c, "messageId")
+	if err != nil {
+		return err
+	}
+
+	var input struct {
+		Emoji  string `json:"emoji"`
+		Toggle bool   `json:"toggle,omitempty"`
+	}
+
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	added := true
+	var reactions []dto.MessageReactionSummaryDTO
+	if input.Toggle {
+		added, reactions, err = h.messageService.ToggleReaction(conversationID, messageID, userID, input.Emoji)
+	} else {
+		reactions, err = h.messageService.AddReaction(conversationID, messageID, userID, input.Emoji)
+	}
+
+	if err != nil {
+		return c.Status(reactionErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	message := "Reaction added successfully"
+	if !added {
+		message = "Reaction removed successfully"
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": message,
+		"data": fiber.Map{
+			"message_id": messageID,
+			"added":      added,
+			"reactions":  reactions,
+		},
+	})
+}
+
+// RemoveReaction ลบปฏิกิริยา (emoji) ของผู้ใช้ออกจากข้อความ
+// รับ emoji จาก query (?emoji=) หรือ request body
+func (h *MessageHandler) RemoveReaction(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	messageID, err := utils.ParseUUIDParam(c, "messageId")
+	if err != nil {
+		return err
+	}
+
+	emoji := c.Query("emoji")
+	if emoji == "" && len(c.Body()) > 0 {
+		var input dto.MessageReactionRequest
+		if err := c.BodyParser(&input); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body: " + err.Error(),
+			})
+		}
+		emoji = input.Emoji
+	}
+
+	reactions, err := h.messageService.RemoveReaction(conversationID, messageID, userID, emoji)
+	if err != nil {
+		return c.Status(reactionErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Reaction removed successfully",
+		"data": fiber.Map{
+			"message_id": messageID,
+			"reactions":  reactions,
+		},
+	})
+}
+
+// reactionErrorStatus แปลง error ของ reaction เป็น HTTP status code
+func reactionErrorStatus(err error) int {
+	switch err.Error() {
+	case "message not found", "reaction not found":
+		return fiber.StatusNotFound
+	case "user is not a member of this conversation":
+		return fiber.StatusForbidden
+	case "emoji is required", "emoji is too long", "cannot react to deleted message":
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// SendPollMessage จัดการคำขอสร้างข้อความประเภทโพล
+func (h *MessageHandler) SendPollMessage(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	// ตรวจสอบ block status ก่อนส่งข้อความ
+	if err := h.checkBlockStatusBeforeSend(userID, conversationID); err != nil {
+		if blockErr, ok := err.(*BlockError); ok {
+			response := fiber.Map{
+				"success":    false,
+				"error_code": blockErr.Code,
+				"message":    blockErr.Message,
+			}
+			if blockErr.BlockerID != nil {
+				response["blocker_id"] = blockErr.BlockerID.String()
+			}
+			return c.Status(fiber.StatusForbidden).JSON(response)
+		}
+
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	// ตรวจสอบสิทธิ์การสร้างโพล (owner อาจจำกัดให้เฉพาะ admin/owner)
+	canCreate, err := h.conversationMemberService.HasPermission(conversationID, userID, service.PermissionCreatePoll)
+	if err != nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+	if !canCreate {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"success": false,
+			"message": "You do not have permission to create polls in this conversation",
+		})
+	}
+
+	var input dto.PollMessageRequest
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	metadata := input.Metadata
+	if input.TempID != "" {
+		if metadata == nil {
+			metadata = make(types.JSONB)
+		}
+		metadata["tempId"] = input.TempID
+	}
+
+	message, err := h.messageService.SendPollMessage(conversationID, userID, input.Question, input.Options, input.AllowMultiple, input.IsAnonymous, input.ClosesAt, metadata)
+	if err != nil {
+		return c.Status(pollErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	h.notificationService.NotifyNewMessage(conversationID, message)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Poll created successfully",
+		"data":    message,
+	})
+}
+
+// SendEncryptedMessage จัดการคำขอส่งข้อความเข้ารหัสแบบ end-to-end (เฉพาะการสนทนาแบบ direct)
+func (h *MessageHandler) SendEncryptedMessage(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	// ตรวจสอบ block status ก่อนส่งข้อความ
+	if err := h.checkBlockStatusBeforeSend(userID, conversationID); err != nil {
+		if blockErr, ok := err.(*BlockError); ok {
+			response := fiber.Map{
+				"success":    false,
+				"error_code": blockErr.Code,
+				"message":    blockErr.Message,
+			}
+			if blockErr.BlockerID != nil {
+				response["blocker_id"] = blockErr.BlockerID.String()
+			}
+			return c.Status(fiber.StatusForbidden).JSON(response)
+		}
+
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	var input dto.EncryptedMessageRequest
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	metadata := input.Metadata
+	if input.TempID != "" {
+		if metadata == nil {
+			metadata = make(types.JSONB)
+		}
+		metadata["tempId"] = input.TempID
+	}
+
+	message, err := h.messageService.SendEncryptedMessage(conversationID, userID, input.SenderDeviceID, input.Ciphertexts, metadata)
+	if err != nil {
+		statusCode := fiber.StatusInternalServerError
+		switch err.Error() {
+		case "user is not a member of this conversation":
+			statusCode = fiber.StatusForbidden
+		case "conversation not found":
+			statusCode = fiber.StatusNotFound
+		case "encrypted messages are only supported in direct conversations",
+			"invalid sender device id", "ciphertexts are required", "invalid ciphertext type",
+			"invalid ciphertext body", "duplicate ciphertext for device",
+			"ciphertext recipient is not a member of this conversation":
+			statusCode = fiber.StatusBadRequest
+		case "sender device is not registered for encryption",
+			"recipient device is not registered for encryption":
+			// client ต้องดึง key bundle ใหม่ (อุปกรณ์อาจถูกลบ/เปลี่ยน)
+			statusCode = fiber.StatusConflict
+		default:
+			if strings.HasPrefix(err.Error(), "too many ciphertexts") {
+				statusCode = fiber.StatusBadRequest
+			}
+		}
+
+		return c.Status(statusCode).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	h.notificationService.NotifyNewMessage(conversationID, message)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Encrypted message sent successfully",
+		"data":    message,
+	})
+}
+
+// VotePoll โหวตตัวเลือกในโพล
+func (h *MessageHandler) VotePoll(c *fiber.Ctx) error {
+	return h.handlePollVote(c, false)
+}
+
+// RetractPollVote ถอนการโหวต (ไม่ส่ง option_ids = ถอนทั้งหมด)
+func (h *MessageHandler) RetractPollVote(c *fiber.Ctx) error {
+	return h.handlePollVote(c, true)
+}
+
+// handlePollVote จัดการคำขอโหวต/ถอนโหวต
+func (h *MessageHandler) handlePollVote(c *fiber.Ctx, retract bool) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	messageID, err := utils.ParseUUIDParam(c, "messageId")
+	if err != nil {
+		return err
+	}
+
+	var input dto.PollVoteRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body: " + err.Error(),
+			})
+		}
+	}
+
+	var poll *dto.PollDTO
+	message := "Vote recorded successfully"
+	if retract {
+		poll, err = h.messageService.RetractPollVote(conversationID, messageID, userID, input.OptionIDs)
+		message = "Vote retracted successfully"
+	} else {
+		poll, err = h.messageService.VotePoll(conversationID, messageID, userID, input.OptionIDs)
+	}
+
+	if err != nil {
+		return c.Status(pollErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": message,
+		"data":    poll,
+	})
+}
+
+// ClosePoll ปิดโพลก่อนเวลา
+func (h *MessageHandler) ClosePoll(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	conversationID, err := utils.ParseUUIDParam(c, "conversationId")
+	if err != nil {
+		return err
+	}
+
+	messageID, err := utils.ParseUUIDParam(c, "messageId")
+	if err != nil {
+		return err
+	}
+
+	poll, err := h.messageService.ClosePoll(conversationID, messageID, userID)
+	if err != nil {
+		return c.Status(pollErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Poll closed successfully",
+		"data":    poll,
+	})
+}
+
+// pollErrorStatus แปลง error จาก poll service เป็น HTTP status code
+func pollErrorStatus(err error) int {
+	switch err.Error() {
+	case "message not found", "poll not found", "vote not found":
+		return fiber.StatusNotFound
+	case "user is not a member of this conversation", "only the poll creator or group admins can close this poll",
+		"only channel admins can post in this channel":
+		return fiber.StatusForbidden
+	case "poll is closed", "poll is already closed", "poll has been deleted":
+		return fiber.StatusConflict
+	case "message is not a poll", "poll question cannot be empty", "poll question is too long",
+		"poll option cannot be empty", "poll option is too long", "poll options must be unique",
+		"poll close time must be in the future", "at least one option is required",
+		"this poll allows only one option", "invalid poll option":
+		return fiber.StatusBadRequest
+	default:
+		if strings.HasPrefix(err.Error(), "poll must have between") {
+			return fiber.StatusBadRequest
+		}
+		return fiber.StatusInternalServerError
+	}
+}
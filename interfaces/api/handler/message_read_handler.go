@@ -77,8 +77,11 @@ func (h *MessageReadHandler) MarkMessageAsRead(c *fiber.Ctx) error {
 		})
 	}
 
+	// ช่อง (channel) นับเป็นยอดผู้ชม ไม่แจ้งผู้ส่งทุกครั้งที่มีคนอ่าน
+	views, _ := h.messageReadService.GetMessageViews(messageUUID, userUUID)
+
 	// ถ้ามี notificationService ให้ส่งการแจ้งเตือนผ่าน WebSocket
-	if h.notificationService != nil && conversationID != uuid.Nil {
+	if h.notificationService != nil && conversationID != uuid.Nil && views == nil {
 		// ดึงข้อมูลข้อความเพื่อหา senderID
 		message, err := h.messageRepo.GetByID(messageUUID)
 		if err == nil && message != nil && message.SenderID != nil {
@@ -136,6 +139,16 @@ func (h *MessageReadHandler) GetMessageReads(c *fiber.Ctx) error {
 		})
 	}
 
+	// ช่อง (channel) คืนค่ายอดผู้ชมแทนรายชื่อผู้อ่าน
+	views, err := h.messageReadService.GetMessageViews(messageUUID, userUUID)
+	if err == nil && views != nil {
+		return c.JSON(fiber.Map{
+			"success": true,
+			"message": "Views retrieved successfully",
+			"data":    views,
+		})
+	}
+
 	// เรียกใช้ service ด้วย UUID
 	reads, err := h.messageReadService.GetMessageReads(messageUUID, userUUID)
 	if err != nil {
@@ -236,6 +249,13 @@ func (h *MessageReadHandler) MarkAllMessagesAsRead(c *fiber.Ctx) error {
 		// เพื่อให้ Direct Chat และ Group Chat แสดง read receipt ถูกต้อง
 		// ต้องส่งทุก message_id เพื่อให้ frontend update ทุกข้อความ
 
+		// ช่อง (channel) นับเป็นยอดผู้ชม ไม่แจ้งผู้ส่งรายข้อความ
+		if len(unreadMessageIDs) > 0 {
+			if views, _ := h.messageReadService.GetMessageViews(unreadMessageIDs[0], userUUID); views != nil {
+				unreadMessageIDs = nil
+			}
+		}
+
 		for _, msgID := range unreadMessageIDs {
 			// ดึงข้อมูลข้อความ
 			message, err := h.messageRepo.GetByID(msgID)
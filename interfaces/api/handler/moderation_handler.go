@@ -0,0 +1,268 @@
+// interfaces/api/handler/moderation_handler.go
+package handler
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+	"github.com/thizplus/gofiber-chat-api/pkg/utils"
+)
+
+// ModerationHandler handles user reports and the moderator review queue
+type ModerationHandler struct {
+	moderationService service.ModerationService
+}
+
+// NewModerationHandler creates a new moderation handler
+func NewModerationHandler(moderationService service.ModerationService) *ModerationHandler {
+	return &ModerationHandler{moderationService: moderationService}
+}
+
+// =========== Reports ===========
+
+// ReportMessage reports a message to the moderators
+// POST /api/v1/reports/messages/:messageId
+func (h *ModerationHandler) ReportMessage(c *fiber.Ctx) error {
+	return h.createReport(c, "messageId", h.moderationService.ReportMessage)
+}
+
+// ReportUser reports a user to the moderators
+// POST /api/v1/reports/users/:userId
+func (h *ModerationHandler) ReportUser(c *fiber.Ctx) error {
+	return h.createReport(c, "userId", h.moderationService.ReportUser)
+}
+
+// ReportConversation reports a group or channel to the moderators
+// POST /api/v1/reports/conversations/:conversationId
+func (h *ModerationHandler) ReportConversation(c *fiber.Ctx) error {
+	return h.createReport(c, "conversationId", h.moderationService.ReportConversation)
+}
+
+// createReport อ่าน request และเรียก service ที่สร้างรายงานของเป้าหมายแต่ละประเภท
+func (h *ModerationHandler) createReport(c *fiber.Ctx, param string, report func(reporterID, targetID uuid.UUID, req *dto.CreateReportRequest) (*dto.ReportDTO, error)) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	targetID, err := utils.ParseUUIDParam(c, param)
+	if err != nil {
+		return err
+	}
+
+	var req dto.CreateReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body",
+		})
+	}
+
+	result, err := report(userID, targetID, &req)
+	if err != nil {
+		return c.Status(moderationErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Report submitted successfully",
+		"data":    result,
+	})
+}
+
+// =========== Moderator queue ===========
+
+// ListReports lists reports in the review queue (oldest first)
+// GET /api/v1/admin/reports?status=open&target_type=&reason=&source=&assignee=me&limit=20&offset=0
+func (h *ModerationHandler) ListReports(c *fiber.Ctx) error {
+	var assigneeID *uuid.UUID
+	if c.Query("assignee") == "me" {
+		actorID, err := middleware.GetUserUUID(c)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"success": false,
+				"message": "Unauthorized: " + err.Error(),
+			})
+		}
+		assigneeID = &actorID
+	} else {
+		id, err := utils.ParseUUIDQuery(c, "assignee", false)
+		if err != nil {
+			return err
+		}
+		assigneeID = optionalUUID(id)
+	}
+
+	limit, offset := adminPagination(c)
+
+	reports, total, err := h.moderationService.ListReports(c.Query("status"), c.Query("target_type"), c.Query("reason"), c.Query("source"), assigneeID, limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"message": "Error listing reports: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    reports,
+		"pagination": fiber.Map{
+			"total":  total,
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
+// GetReport returns a single report with its content snapshot
+// GET /api/v1/admin/reports/:reportId
+func (h *ModerationHandler) GetReport(c *fiber.Ctx) error {
+	reportID, err := utils.ParseUUIDParam(c, "reportId")
+	if err != nil {
+		return err
+	}
+
+	report, err := h.moderationService.GetReport(reportID)
+	if err != nil {
+		return c.Status(moderationErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// UpdateReportStatus claims, releases, dismisses or reopens a report
+// PATCH /api/v1/admin/reports/:reportId/status
+func (h *ModerationHandler) UpdateReportStatus(c *fiber.Ctx) error {
+	actorID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	reportID, err := utils.ParseUUIDParam(c, "reportId")
+	if err != nil {
+		return err
+	}
+
+	var req dto.UpdateReportStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body",
+		})
+	}
+	c.Locals("auditDetails", types.JSONB{"status": req.Status, "note": req.Note})
+
+	report, err := h.moderationService.UpdateReportStatus(actorID, reportID, req.Status, req.Note)
+	if err != nil {
+		return c.Status(moderationErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Report status updated successfully",
+		"data":    report,
+	})
+}
+
+// TakeAction applies an enforcement action for a report and resolves it
+// POST /api/v1/admin/reports/:reportId/actions
+func (h *ModerationHandler) TakeAction(c *fiber.Ctx) error {
+	actorID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	reportID, err := utils.ParseUUIDParam(c, "reportId")
+	if err != nil {
+		return err
+	}
+
+	var req dto.ModerationActionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body",
+		})
+	}
+	auditDetails := types.JSONB{"moderation_action": req.Action, "note": req.Note}
+	c.Locals("auditDetails", auditDetails)
+
+	report, err := h.moderationService.TakeAction(actorID, middleware.GetSystemRole(c), reportID, &req)
+	if err != nil {
+		return c.Status(moderationErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	// บันทึกเป้าหมายที่ถูกดำเนินการลง audit log
+	auditDetails["report_target_type"] = report.TargetType
+	auditDetails["report_target_id"] = report.TargetID.String()
+	if report.ReportedUserID != nil {
+		auditDetails["reported_user_id"] = report.ReportedUserID.String()
+	}
+	if report.ConversationID != nil {
+		auditDetails["conversation_id"] = report.ConversationID.String()
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Moderation action applied successfully",
+		"data":    report,
+	})
+}
+
+// moderationErrorStatus แปลง error ของ ModerationService เป็น HTTP status
+func moderationErrorStatus(err error) int {
+	switch err.Error() {
+	case "report not found", "message not found", "user not found", "conversation not found":
+		return fiber.StatusNotFound
+	case "you are not a member of this conversation", "you cannot report yourself",
+		"only super admins can suspend staff accounts", "you cannot suspend yourself":
+		return fiber.StatusForbidden
+	case "you have already reported this", "report is already closed", "message is already deleted",
+		"user is already suspended", "conversation is already disabled":
+		return fiber.StatusConflict
+	case "invalid report reason", "description is required when reason is other",
+		"direct conversations cannot be reported, report the user instead",
+		"direct conversations cannot be disabled, suspend the user instead",
+		"use an enforcement action to resolve a report", "action is not applicable to this report",
+		"invalid moderation action":
+		return fiber.StatusBadRequest
+	default:
+		if strings.HasPrefix(err.Error(), "invalid status transition") ||
+			strings.HasPrefix(err.Error(), "description is too long") ||
+			strings.HasPrefix(err.Error(), "note is too long") ||
+			strings.HasPrefix(err.Error(), "suspend reason is too long") ||
+			strings.HasPrefix(err.Error(), "disable reason is too long") {
+			return fiber.StatusBadRequest
+		}
+		return fiber.StatusInternalServerError
+	}
+}
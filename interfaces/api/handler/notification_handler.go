@@ -0,0 +1,221 @@
+// interfaces/api/handler/notification_handler.go
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+	"github.com/thizplus/gofiber-chat-api/pkg/utils"
+)
+
+// NotificationHandler handles notification inbox endpoints
+type NotificationHandler struct {
+	inboxService service.NotificationInboxService
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(inboxService service.NotificationInboxService) *NotificationHandler {
+	return &NotificationHandler{inboxService: inboxService}
+}
+
+// ListNotifications lists the current user's notifications, newest first (cursor-based)
+// GET /api/v1/notifications?limit=20&cursor=<id>&unread_only=true
+func (h *NotificationHandler) ListNotifications(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	limit := c.QueryInt("limit", 20)
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	cursor := c.Query("cursor")
+	var cursorPtr *string
+	if cursor != "" {
+		cursorPtr = &cursor
+	}
+
+	notifications, nextCursor, hasMore, err := h.inboxService.ListNotifications(userID, limit, cursorPtr, c.QueryBool("unread_only", false))
+	if err != nil {
+		return c.Status(notificationErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": "Failed to get notifications: " + err.Error(),
+		})
+	}
+
+	unread, err := h.inboxService.GetUnreadCount(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"message": "Failed to count unread notifications: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"notifications": notifications,
+			"unread_count":  unread,
+			"cursor":        nextCursor,
+			"has_more":      hasMore,
+		},
+	})
+}
+
+// GetUnreadCount returns the unread badge count
+// GET /api/v1/notifications/unread-count
+func (h *NotificationHandler) GetUnreadCount(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	unread, err := h.inboxService.GetUnreadCount(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"message": "Failed to count unread notifications: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    fiber.Map{"unread_count": unread},
+	})
+}
+
+// MarkRead marks the given notifications as read
+// POST /api/v1/notifications/read  {"ids": ["..."]}
+func (h *NotificationHandler) MarkRead(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	var req dto.MarkNotificationsReadRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body",
+		})
+	}
+
+	return h.markRead(c, userID, req.IDs)
+}
+
+// MarkOneRead marks a single notification as read
+// POST /api/v1/notifications/:notificationId/read
+func (h *NotificationHandler) MarkOneRead(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	notificationID, err := utils.ParseUUIDParam(c, "notificationId")
+	if err != nil {
+		return err
+	}
+
+	return h.markRead(c, userID, []uuid.UUID{notificationID})
+}
+
+// MarkAllRead marks every notification as read
+// POST /api/v1/notifications/read-all
+func (h *NotificationHandler) MarkAllRead(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	unread, err := h.inboxService.MarkAllRead(userID)
+	if err != nil {
+		return c.Status(notificationErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "All notifications marked as read",
+		"data":    fiber.Map{"unread_count": unread},
+	})
+}
+
+// DeleteNotification removes a notification from the inbox
+// DELETE /api/v1/notifications/:notificationId
+func (h *NotificationHandler) DeleteNotification(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	notificationID, err := utils.ParseUUIDParam(c, "notificationId")
+	if err != nil {
+		return err
+	}
+
+	if err := h.inboxService.DeleteNotification(userID, notificationID); err != nil {
+		return c.Status(notificationErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Notification deleted successfully",
+	})
+}
+
+func (h *NotificationHandler) markRead(c *fiber.Ctx, userID uuid.UUID, ids []uuid.UUID) error {
+	unread, err := h.inboxService.MarkRead(userID, ids)
+	if err != nil {
+		return c.Status(notificationErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Notifications marked as read",
+		"data":    fiber.Map{"unread_count": unread},
+	})
+}
+
+func notificationErrorStatus(err error) int {
+	switch err.Error() {
+	case "notification not found":
+		return fiber.StatusNotFound
+	case "invalid cursor", "cursor not found", "notification ids are required", "too many notification ids":
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
@@ -0,0 +1,194 @@
+// interfaces/api/handler/push_handler.go
+package handler
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+)
+
+// PushHandler handles push notification device registration and diagnostics
+type PushHandler struct {
+	pushService service.PushService
+}
+
+// NewPushHandler creates a new push handler
+func NewPushHandler(pushService service.PushService) *PushHandler {
+	return &PushHandler{pushService: pushService}
+}
+
+// RegisterDevice registers a device token for push notifications
+// POST /api/v1/push/devices
+func (h *PushHandler) RegisterDevice(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	var req dto.RegisterDeviceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body",
+		})
+	}
+
+	device := &models.DeviceToken{
+		Provider:   req.Provider,
+		Token:      req.Token,
+		Platform:   strings.ToLower(req.Platform),
+		DeviceName: req.DeviceName,
+	}
+	if req.Keys != nil {
+		device.WebPushP256dh = req.Keys.P256dh
+		device.WebPushAuth = req.Keys.Auth
+	}
+
+	device, err = h.pushService.RegisterDevice(userID, device)
+	if err != nil {
+		statusCode := fiber.StatusInternalServerError
+		switch err.Error() {
+		case "device token is required",
+			"push provider is not supported",
+			"webpush token must be the subscription endpoint",
+			"webpush subscription keys are required",
+			"webpush endpoint must not point to a private address":
+			statusCode = fiber.StatusBadRequest
+		}
+
+		return c.Status(statusCode).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Device registered successfully",
+		"data":    device,
+	})
+}
+
+// ListDevices lists the current user's registered devices
+// GET /api/v1/push/devices
+func (h *PushHandler) ListDevices(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	devices, err := h.pushService.ListDevices(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"message": "Failed to get devices: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    devices,
+	})
+}
+
+// UnregisterDevice removes a registered device
+// DELETE /api/v1/push/devices/:device_id
+func (h *PushHandler) UnregisterDevice(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	deviceID, err := uuid.Parse(c.Params("device_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid device ID",
+		})
+	}
+
+	if err := h.pushService.UnregisterDevice(userID, deviceID); err != nil {
+		statusCode := fiber.StatusInternalServerError
+		if err.Error() == "device not found" {
+			statusCode = fiber.StatusNotFound
+		}
+
+		return c.Status(statusCode).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Device unregistered successfully",
+	})
+}
+
+// GetDeliveries lists the current user's push deliveries for diagnosing missed pushes
+// GET /api/v1/push/deliveries?status=failed&limit=50&offset=0
+func (h *PushHandler) GetDeliveries(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	limit := c.QueryInt("limit", 50)
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	offset := c.QueryInt("offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	deliveries, total, err := h.pushService.GetDeliveries(userID, c.Query("status"), limit, offset)
+	if err != nil {
+		statusCode := fiber.StatusInternalServerError
+		if err.Error() == "invalid delivery status" {
+			statusCode = fiber.StatusBadRequest
+		}
+
+		return c.Status(statusCode).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    deliveries,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// GetConfig returns enabled push providers and the VAPID public key for web clients
+// GET /api/v1/push/config
+func (h *PushHandler) GetConfig(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"providers":        h.pushService.SupportedProviders(),
+			"vapid_public_key": h.pushService.VAPIDPublicKey(),
+		},
+	})
+}
@@ -81,7 +81,8 @@ func (h *ScheduledMessageHandler) ScheduleMessage(c *fiber.Ctx) error {
 
 	if err != nil {
 		statusCode := fiber.StatusInternalServerError
-		if err.Error() == "user is not a member of this conversation" {
+		if err.Error() == "user is not a member of this conversation" ||
+			err.Error() == "only channel admins can post in this channel" {
 			statusCode = fiber.StatusForbidden
 		} else if err.Error() == "scheduled_at must be in the future" {
 			statusCode = fiber.StatusBadRequest
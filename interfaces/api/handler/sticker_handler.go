@@ -32,7 +32,7 @@ func (h *StickerHandler) CreateStickerSet(c *fiber.Ctx) error {
 		})
 	}
 
-	// TODO: ตรวจสอบว่าผู้ใช้เป็นแอดมินหรือไม่
+	// สิทธิ์แอดมินตรวจสอบแล้วโดย middleware.RequireSystemRole ที่ route /admin/stickers
 
 	// รับข้อมูลจาก request
 	var input struct {
@@ -174,7 +174,7 @@ func (h *StickerHandler) UpdateStickerSet(c *fiber.Ctx) error {
 		})
 	}
 
-	// TODO: ตรวจสอบว่าผู้ใช้เป็นแอดมินหรือไม่
+	// สิทธิ์แอดมินตรวจสอบแล้วโดย middleware.RequireSystemRole ที่ route /admin/stickers
 
 	// ดึงและแปลง stickerSetId จาก URL parameter เป็น UUID
 	stickerSetID, err := utils.ParseUUIDParam(c, "stickerSetId")
@@ -234,7 +234,7 @@ func (h *StickerHandler) UploadStickerSetCover(c *fiber.Ctx) error {
 		})
 	}
 
-	// TODO: ตรวจสอบว่าผู้ใช้เป็นแอดมินหรือไม่
+	// สิทธิ์แอดมินตรวจสอบแล้วโดย middleware.RequireSystemRole ที่ route /admin/stickers
 
 	// ดึงและแปลง stickerSetId จาก URL parameter เป็น UUID
 	stickerSetID, err := utils.ParseUUIDParam(c, "stickerSetId")
@@ -283,7 +283,7 @@ func (h *StickerHandler) DeleteStickerSet(c *fiber.Ctx) error {
 		})
 	}
 
-	// TODO: ตรวจสอบว่าผู้ใช้เป็นแอดมินหรือไม่
+	// สิทธิ์แอดมินตรวจสอบแล้วโดย middleware.RequireSystemRole ที่ route /admin/stickers
 
 	// ดึงและแปลง stickerSetId จาก URL parameter เป็น UUID
 	stickerSetID, err := utils.ParseUUIDParam(c, "stickerSetId")
@@ -318,7 +318,7 @@ func (h *StickerHandler) AddStickerToSet(c *fiber.Ctx) error {
 		})
 	}
 
-	// TODO: ตรวจสอบว่าผู้ใช้เป็นแอดมินหรือไม่
+	// สิทธิ์แอดมินตรวจสอบแล้วโดย middleware.RequireSystemRole ที่ route /admin/stickers
 
 	// ดึงและแปลง stickerSetId จาก URL parameter เป็น UUID
 	stickerSetID, err := utils.ParseUUIDParam(c, "stickerSetId")
@@ -375,7 +375,7 @@ func (h *StickerHandler) UpdateSticker(c *fiber.Ctx) error {
 		})
 	}
 
-	// TODO: ตรวจสอบว่าผู้ใช้เป็นแอดมินหรือไม่
+	// สิทธิ์แอดมินตรวจสอบแล้วโดย middleware.RequireSystemRole ที่ route /admin/stickers
 
 	// ดึงและแปลง stickerId จาก URL parameter เป็น UUID
 	stickerID, err := utils.ParseUUIDParam(c, "stickerId")
@@ -425,7 +425,7 @@ func (h *StickerHandler) DeleteSticker(c *fiber.Ctx) error {
 		})
 	}
 
-	// TODO: ตรวจสอบว่าผู้ใช้เป็นแอดมินหรือไม่
+	// สิทธิ์แอดมินตรวจสอบแล้วโดย middleware.RequireSystemRole ที่ route /admin/stickers
 
 	// ดึงและแปลง stickerId จาก URL parameter เป็น UUID
 	stickerID, err := utils.ParseUUIDParam(c, "stickerId")
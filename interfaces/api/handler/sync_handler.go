@@ -0,0 +1,57 @@
+// interfaces/api/handler/sync_handler.go
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+)
+
+// SyncHandler handles delta sync for offline-first clients
+type SyncHandler struct {
+	syncService service.SyncService
+}
+
+// NewSyncHandler creates a new sync handler
+func NewSyncHandler(syncService service.SyncService) *SyncHandler {
+	return &SyncHandler{syncService: syncService}
+}
+
+// Sync returns everything that changed for the current user since the given token
+// GET /api/v1/sync?since=<token>&limit=100
+func (h *SyncHandler) Sync(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	limit := c.QueryInt("limit", 100)
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	result, err := h.syncService.Sync(userID, c.Query("since"), limit)
+	if err != nil {
+		statusCode := fiber.StatusInternalServerError
+		switch err.Error() {
+		case "invalid sync token":
+			statusCode = fiber.StatusBadRequest
+		case "sync token expired":
+			// client ต้องโหลดข้อมูลใหม่ทั้งหมดแล้วเริ่ม sync โดยไม่ส่ง since
+			statusCode = fiber.StatusGone
+		}
+
+		return c.Status(statusCode).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    result,
+	})
+}
@@ -0,0 +1,177 @@
+// interfaces/api/handler/thread_handler.go
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/domain/types"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+	"github.com/thizplus/gofiber-chat-api/pkg/utils"
+)
+
+// ThreadHandler จัดการ API ของเธรดข้อความ
+type ThreadHandler struct {
+	messageService      service.MessageService
+	conversationService service.ConversationService
+	notificationService service.NotificationService
+}
+
+// NewThreadHandler สร้าง Handler ใหม่
+func NewThreadHandler(
+	messageService service.MessageService,
+	conversationService service.ConversationService,
+	notificationService service.NotificationService,
+) *ThreadHandler {
+	return &ThreadHandler{
+		messageService:      messageService,
+		conversationService: conversationService,
+		notificationService: notificationService,
+	}
+}
+
+// GetThread ดึงข้อความต้นเธรดพร้อมข้อความในเธรด
+// GET /api/v1/messages/:messageId/thread?before=&after=&limit=
+func (h *ThreadHandler) GetThread(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	messageID, err := utils.ParseUUIDParam(c, "messageId")
+	if err != nil {
+		return err // error response ถูกจัดการในฟังก์ชันแล้ว
+	}
+
+	limit := c.QueryInt("limit", 20)
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 50 {
+		limit = 50 // จำกัดสูงสุดที่ 50 เหมือนการดึงข้อความในการสนทนา
+	}
+
+	thread, err := h.conversationService.GetThreadMessages(messageID, userID, c.Query("before"), c.Query("after"), limit)
+	if err != nil {
+		return c.Status(threadErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Thread retrieved successfully",
+		"data":    thread,
+	})
+}
+
+// ReplyInThread ส่งข้อความตอบกลับในเธรด
+// POST /api/v1/messages/:messageId/thread
+func (h *ThreadHandler) ReplyInThread(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	messageID, err := utils.ParseUUIDParam(c, "messageId")
+	if err != nil {
+		return err
+	}
+
+	var input struct {
+		MessageType       string      `json:"message_type"`
+		Content           string      `json:"content"`
+		MediaURL          string      `json:"media_url"`
+		MediaThumbnailURL string      `json:"media_thumbnail_url"`
+		Metadata          types.JSONB `json:"metadata"`
+	}
+
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	if input.MessageType == "" {
+		input.MessageType = "text"
+	}
+
+	message, err := h.messageService.ReplyInThread(
+		messageID,
+		userID,
+		input.MessageType,
+		input.Content,
+		input.MediaURL,
+		input.MediaThumbnailURL,
+		input.Metadata,
+	)
+	if err != nil {
+		return c.Status(threadErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	// ส่ง thread.reply แทน message.receive เพื่อไม่ให้ข้อความไปโผล่ใน timeline หลัก
+	h.notificationService.NotifyThreadReply(message.ConversationID, *message.ThreadRootID, message)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Thread reply sent successfully",
+		"data":    message,
+	})
+}
+
+// MarkThreadRead บันทึกว่าผู้ใช้อ่านเธรดแล้ว
+// POST /api/v1/messages/:messageId/thread/read
+func (h *ThreadHandler) MarkThreadRead(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	messageID, err := utils.ParseUUIDParam(c, "messageId")
+	if err != nil {
+		return err
+	}
+
+	if err := h.conversationService.MarkThreadRead(messageID, userID); err != nil {
+		return c.Status(threadErrorStatus(err)).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Thread marked as read",
+	})
+}
+
+// threadErrorStatus แปลง error ของเธรดเป็น HTTP status code
+func threadErrorStatus(err error) int {
+	switch err.Error() {
+	case "message not found":
+		return fiber.StatusNotFound
+	case "you are not a member of this conversation", "only channel admins can post in this channel":
+		return fiber.StatusForbidden
+	case "cannot reply to deleted message", "invalid message type",
+		"message content is required", "sticker URL is required", "media URL is required":
+		return fiber.StatusBadRequest
+	default:
+		if isContentFilterRejection(err) {
+			return fiber.StatusUnprocessableEntity
+		}
+		return fiber.StatusInternalServerError
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid" // เพิ่ม import uuid
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
 	"github.com/thizplus/gofiber-chat-api/domain/service"
 	"github.com/thizplus/gofiber-chat-api/domain/types"
 	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
@@ -111,6 +112,72 @@ func (h *UserHandler) GetProfile(c *fiber.Ctx) error {
 	})
 }
 
+// GetNotificationPreferences ดึง quiet hours และ keyword alerts ของผู้ใช้ปัจจุบัน
+// GET /api/v1/users/me/notification-preferences
+func (h *UserHandler) GetNotificationPreferences(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	prefs, err := h.userService.GetNotificationPreferences(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"message": "Error getting notification preferences: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    prefs,
+	})
+}
+
+// UpdateNotificationPreferences ตั้งค่า quiet hours และ keyword alerts (ฟิลด์ที่ไม่ส่งมาคงค่าเดิม)
+// PUT /api/v1/users/me/notification-preferences
+func (h *UserHandler) UpdateNotificationPreferences(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserUUID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"message": "Unauthorized: " + err.Error(),
+		})
+	}
+
+	var req dto.UpdateNotificationPreferencesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request data",
+		})
+	}
+
+	prefs, err := h.userService.UpdateNotificationPreferences(userID, &req)
+	if err != nil {
+		statusCode := fiber.StatusInternalServerError
+		switch err.Error() {
+		case "invalid quiet hours time, expected HH:MM", "quiet hours start and end must differ", "invalid timezone",
+			"keyword alert must be between 2 and 50 characters", "too many keyword alerts":
+			statusCode = fiber.StatusBadRequest
+		}
+
+		return c.Status(statusCode).JSON(fiber.Map{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Notification preferences updated successfully",
+		"data":    prefs,
+	})
+}
+
 // UpdateProfile อัปเดตข้อมูลโปรไฟล์ผู้ใช้
 func (h *UserHandler) UpdateProfile(c *fiber.Ctx) error {
 	// ดึงและแปลง userId จาก URL parameter เป็น UUID
@@ -148,7 +215,15 @@ func (h *UserHandler) UpdateProfile(c *fiber.Ctx) error {
 	// ใช้ service อัปเดตข้อมูล
 	user, err := h.userService.UpdateProfile(userID, input)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		statusCode := fiber.StatusInternalServerError
+		switch err.Error() {
+		case "invalid email address":
+			statusCode = fiber.StatusBadRequest
+		case "email already in use":
+			statusCode = fiber.StatusConflict
+		}
+
+		return c.Status(statusCode).JSON(fiber.Map{
 			"success": false,
 			"message": "Error updating profile: " + err.Error(),
 		})
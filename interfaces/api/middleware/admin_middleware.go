@@ -0,0 +1,81 @@
+// interfaces/api/middleware/admin_middleware.go
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// SystemRoleResolver ดึงข้อมูลผู้ใช้ล่าสุดเพื่อตรวจสอบ system role (role ไม่ได้อยู่ใน JWT เพื่อให้ถอดสิทธิ์มีผลทันที)
+type SystemRoleResolver interface {
+	GetUserByID(userID uuid.UUID) (*models.User, error)
+}
+
+// systemRoleResolver ถูกตั้งค่าตอนเริ่มระบบ (nil = ปฏิเสธทุก request ที่ต้องใช้ system role)
+var systemRoleResolver SystemRoleResolver
+
+// SetSystemRoleResolver ตั้งค่าตัวดึงข้อมูลผู้ใช้ให้ RequireSystemRole
+func SetSystemRoleResolver(resolver SystemRoleResolver) {
+	systemRoleResolver = resolver
+}
+
+// AdminOnly อนุญาตเฉพาะผู้ที่มี system role ใดก็ได้ (ต้องใช้หลัง Protected)
+func AdminOnly() fiber.Handler {
+	return RequireSystemRole(models.SystemRoleSuperAdmin, models.SystemRoleModerator, models.SystemRoleSupport)
+}
+
+// RequireSystemRole อนุญาตเฉพาะ system role ที่ระบุ (super admin ผ่านได้เสมอ, ต้องใช้หลัง Protected)
+func RequireSystemRole(roles ...string) fiber.Handler {
+	allowed := make(map[string]bool, len(roles)+1)
+	allowed[models.SystemRoleSuperAdmin] = true
+	for _, role := range roles {
+		allowed[role] = true
+	}
+
+	return func(c *fiber.Ctx) error {
+		role, ok := c.Locals("systemRole").(string)
+		if !ok {
+			userID, err := GetUserUUID(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"success": false,
+					"message": "Unauthorized: " + err.Error(),
+				})
+			}
+
+			if systemRoleResolver == nil {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"success": false,
+					"message": "Admin access is not configured",
+				})
+			}
+
+			user, err := systemRoleResolver.GetUserByID(userID)
+			if err != nil || user == nil || user.Status == models.UserStatusSuspended {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"success": false,
+					"message": "Admin access required",
+				})
+			}
+
+			role = user.SystemRole
+			c.Locals("systemRole", role)
+		}
+
+		if !allowed[role] {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"success": false,
+				"message": "Admin access required",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// GetSystemRole ดึง system role ที่ RequireSystemRole ตรวจสอบแล้วจาก context
+func GetSystemRole(c *fiber.Ctx) string {
+	role, _ := c.Locals("systemRole").(string)
+	return role
+}
@@ -3,9 +3,7 @@ package middleware
 
 import (
 	"fmt"
-	"os"
 	"strings"
-	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
@@ -15,13 +13,6 @@ import (
 // Protected เป็น middleware สำหรับป้องกันเส้นทางที่ต้องการการยืนยันตัวตน
 func Protected() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// ดึงค่า JWT secret
-		jwtSecret := os.Getenv("JWT_SECRET")
-		if jwtSecret == "" {
-			jwtSecret = "default-jwt-secret-for-development-only"
-			fmt.Println("WARNING: JWT_SECRET not set in environment, using default value")
-		}
-
 		// ดึง Authorization header
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
@@ -42,14 +33,8 @@ func Protected() fiber.Handler {
 
 		tokenString := parts[1]
 
-		// สร้าง parser ด้วย options
-		parser := jwt.NewParser(jwt.WithValidMethods([]string{"HS256"}))
-
-		// แยกวิเคราะห์และตรวจสอบ token
-		token, err := parser.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return []byte(jwtSecret), nil
-		})
-
+		// ตรวจสอบลายเซ็น (เลือก key ตาม kid) และวันหมดอายุของ token
+		claims, err := verifyToken(tokenString)
 		if err != nil {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"error":   true,
@@ -57,37 +42,55 @@ func Protected() fiber.Handler {
 			})
 		}
 
-		// ตรวจสอบว่า token ถูกต้อง
-		if !token.Valid {
+		// ใช้ได้เฉพาะ access token (refresh token และ 2FA challenge token มี type อื่น)
+		if !isAccessToken(claims) {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"error":   true,
 				"message": "Invalid JWT token",
 			})
 		}
 
-		// เก็บ token ไว้ใน locals
-		c.Locals("user", token)
+		// เก็บ claims ของ token ไว้ใน locals
+		c.Locals("user", claims)
 
 		// ดึง user ID จาก claims และเก็บไว้ใน locals
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			if userIDStr, ok := claims["id"].(string); ok {
-				// เก็บ string ID เพื่อความเข้ากันได้กับโค้ดเดิม
-				c.Locals("userID", userIDStr)
-
-				// แปลงเป็น UUID และเก็บไว้ใน locals
-				userUUID, err := uuid.Parse(userIDStr)
-				if err == nil {
-					c.Locals("userUUID", userUUID)
-				} else {
-					// ID ในโทเคนไม่ใช่ UUID ที่ถูกต้อง
-					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-						"error":   true,
-						"message": "Invalid UUID format in token",
-					})
-				}
+		if userIDStr, ok := claims["id"].(string); ok {
+			// เก็บ string ID เพื่อความเข้ากันได้กับโค้ดเดิม
+			c.Locals("userID", userIDStr)
+
+			// แปลงเป็น UUID และเก็บไว้ใน locals
+			userUUID, err := uuid.Parse(userIDStr)
+			if err == nil {
+				c.Locals("userUUID", userUUID)
+			} else {
+				// ID ในโทเคนไม่ใช่ UUID ที่ถูกต้อง
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Invalid UUID format in token",
+				})
 			}
 		}
 
+		// ตรวจสอบว่า token หรือ session ของอุปกรณ์ถูกเพิกถอนแล้วหรือไม่
+		sessionID := sessionIDFromClaims(claims)
+		if sessionID != nil {
+			c.Locals("sessionID", *sessionID)
+		}
+
+		revoked, err := isTokenRevoked(tokenString, sessionID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   true,
+				"message": "Error verifying JWT token",
+			})
+		}
+		if revoked {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   true,
+				"message": "JWT token has been revoked",
+			})
+		}
+
 		return c.Next()
 	}
 }
@@ -115,6 +118,14 @@ func GetUserUUID(c *fiber.Ctx) (uuid.UUID, error) {
 	return uuid.Parse(userIDStr)
 }
 
+// GetSessionID ดึง session ID ของอุปกรณ์จาก context (nil = token แบบเก่าที่ไม่มี sid)
+func GetSessionID(c *fiber.Ctx) *uuid.UUID {
+	if sessionID, ok := c.Locals("sessionID").(uuid.UUID); ok {
+		return &sessionID
+	}
+	return nil
+}
+
 // GetUserUUIDOrError ดึง UUID หรือส่งกลับ error response
 func GetUserUUIDOrError(c *fiber.Ctx) (uuid.UUID, error) {
 	// ลองดึง UUID โดยตรงจาก context ก่อน
@@ -143,88 +154,52 @@ func GetUserUUIDOrError(c *fiber.Ctx) (uuid.UUID, error) {
 	return parsed, nil
 }
 
-// ValidateTokenStringToUUID ตรวจสอบ token และส่งคืน UUID
-func ValidateTokenStringToUUID(tokenString string) (uuid.UUID, error) {
-	userIDStr, err := ValidateTokenString(tokenString)
+// ValidateAccessToken ตรวจสอบ token รวมถึงการเพิกถอน และส่งคืน user ID กับ session ID (ใช้กับ WebSocket)
+func ValidateAccessToken(tokenString string) (uuid.UUID, *uuid.UUID, error) {
+	claims, err := verifyToken(tokenString)
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, nil, err
 	}
-
-	return uuid.Parse(userIDStr)
-}
-
-// ValidateTokenString ตรวจสอบ token และส่งคืน string (เพื่อความเข้ากันได้กับโค้ดเดิม)
-func ValidateTokenString(tokenString string) (string, error) {
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = "default-jwt-secret-for-development-only"
+	if !isAccessToken(claims) {
+		return uuid.Nil, nil, fmt.Errorf("invalid token")
 	}
 
-	// สร้าง parser ด้วย options
-	parser := jwt.NewParser(jwt.WithValidMethods([]string{"HS256"}))
-
-	// แยกวิเคราะห์และตรวจสอบ token
-	token, err := parser.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(jwtSecret), nil
-	})
-
+	userIDStr, ok := claims["id"].(string)
+	if !ok {
+		return uuid.Nil, nil, fmt.Errorf("user ID not found in token claims")
+	}
+	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		return "", err
+		return uuid.Nil, nil, err
 	}
 
-	// ตรวจสอบว่า token ถูกต้องและดึงข้อมูล claims
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		if userID, ok := claims["id"].(string); ok {
-			return userID, nil
-		}
-		return "", fmt.Errorf("user ID not found in token claims")
+	sessionID := sessionIDFromClaims(claims)
+	revoked, err := isTokenRevoked(tokenString, sessionID)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	if revoked {
+		return uuid.Nil, nil, fmt.Errorf("token has been revoked")
 	}
 
-	return "", fmt.Errorf("invalid token")
+	return userID, sessionID, nil
 }
 
-// GenerateJWTFromUUID สร้าง JWT token จาก UUID
-func GenerateJWTFromUUID(userUUID uuid.UUID) (string, error) {
-	return GenerateJWT(userUUID.String())
+// isAccessToken ตรวจสอบ claim "type" (token แบบเก่าที่ไม่มี type ถือเป็น access token)
+func isAccessToken(claims jwt.MapClaims) bool {
+	tokenType, exists := claims["type"]
+	return !exists || tokenType == "access"
 }
 
-// GenerateJWT สร้าง JWT token จาก string ID (เพื่อความเข้ากันได้กับโค้ดเดิม)
-func GenerateJWT(userID string) (string, error) {
-	// ตรวจสอบว่า userID เป็น UUID ที่ถูกต้อง
-	_, err := uuid.Parse(userID)
-	if err != nil {
-		return "", fmt.Errorf("invalid UUID format: %w", err)
+// sessionIDFromClaims ดึง claim "sid" (session ของอุปกรณ์) จาก token
+func sessionIDFromClaims(claims jwt.MapClaims) *uuid.UUID {
+	sidStr, ok := claims["sid"].(string)
+	if !ok {
+		return nil
 	}
-
-	// ดึงค่า JWT secret
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = "default-jwt-secret-for-development-only"
-		fmt.Println("WARNING: JWT_SECRET not set in environment, using default value")
-	}
-
-	// ดึงระยะเวลาหมดอายุของ token (หน่วยเป็นนาที)
-	expiry := 1440 // 24 ชั่วโมง
-	if expiryString := os.Getenv("JWT_ACCESS_EXPIRY"); expiryString != "" {
-		fmt.Sscanf(expiryString, "%d", &expiry)
-	}
-
-	// สร้าง claims
-	now := time.Now()
-	claims := jwt.MapClaims{
-		"id":  userID,
-		"exp": jwt.NewNumericDate(now.Add(time.Duration(expiry) * time.Minute)),
-		"iat": jwt.NewNumericDate(now),
-	}
-
-	// สร้าง token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	// ลงชื่อ token
-	tokenString, err := token.SignedString([]byte(jwtSecret))
+	sessionID, err := uuid.Parse(sidStr)
 	if err != nil {
-		return "", err
+		return nil
 	}
-
-	return tokenString, nil
+	return &sessionID
 }
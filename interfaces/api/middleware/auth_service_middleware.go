@@ -2,10 +2,54 @@
 package middleware
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/thizplus/gofiber-chat-api/domain/service"
 )
 
+// TokenRevocationChecker ตรวจสอบว่า access token หรือ session ของมันถูกเพิกถอนแล้วหรือไม่
+type TokenRevocationChecker interface {
+	IsTokenRevoked(token string, sessionID *uuid.UUID) (bool, error)
+}
+
+// revocationChecker ถูกตั้งค่าตอนเริ่มระบบ (nil = ไม่ตรวจสอบ blacklist)
+var revocationChecker TokenRevocationChecker
+
+// SetTokenRevocationChecker ตั้งค่าตัวตรวจสอบ blacklist ให้ Protected และ ValidateAccessToken
+func SetTokenRevocationChecker(checker TokenRevocationChecker) {
+	revocationChecker = checker
+}
+
+func isTokenRevoked(token string, sessionID *uuid.UUID) (bool, error) {
+	if revocationChecker == nil {
+		return false, nil
+	}
+	return revocationChecker.IsTokenRevoked(token, sessionID)
+}
+
+// tokenSigner ใช้ตรวจลายเซ็นของ JWT ถูกตั้งค่าตอนเริ่มระบบ
+var tokenSigner service.TokenSigner
+
+// SetTokenSigner ตั้งค่าตัวตรวจ JWT ให้ Protected และ ValidateAccessToken
+func SetTokenSigner(signer service.TokenSigner) {
+	tokenSigner = signer
+}
+
+// verifyToken ตรวจลายเซ็นและวันหมดอายุของ token แล้วคืนค่า claims
+func verifyToken(tokenString string) (jwt.MapClaims, error) {
+	if tokenSigner == nil {
+		return nil, errors.New("token signer is not configured")
+	}
+	claims, err := tokenSigner.Verify(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.MapClaims(claims), nil
+}
+
 // AuthMiddleware struct เพื่อใช้ service
 type AuthMiddleware struct {
 	authService service.AuthService
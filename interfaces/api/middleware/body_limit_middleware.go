@@ -0,0 +1,24 @@
+// interfaces/api/middleware/body_limit_middleware.go
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// IncomingWebhookBodyLimit ขนาด body สูงสุดของ incoming webhook (ข้อความ 4000 ตัวอักษร + attachments 10 รายการพอดีในนี้)
+const IncomingWebhookBodyLimit = 256 * 1024
+
+// BodyLimit ปฏิเสธ request ที่ body ใหญ่กว่า limit bytes ด้วย 413 (จำกัดต่อ route ให้เล็กกว่า BodyLimit ของทั้งแอป)
+func BodyLimit(limit int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Request().Header.ContentLength() > limit || len(c.Body()) > limit {
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+				"success": false,
+				"message": fmt.Sprintf("Request body must not exceed %d bytes", limit),
+			})
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,64 @@
+// interfaces/api/middleware/bot_auth_middleware.go
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+)
+
+// BotTokenResolver ตรวจสอบ API token ของบอท
+type BotTokenResolver interface {
+	AuthenticateToken(token string) (*models.Bot, error)
+}
+
+// botTokenResolver ถูกตั้งค่าตอนเริ่มระบบ (nil = ปฏิเสธทุก request ของ Bot API)
+var botTokenResolver BotTokenResolver
+
+// SetBotTokenResolver ตั้งค่าตัวตรวจสอบ token ให้ BotAuth
+func SetBotTokenResolver(resolver BotTokenResolver) {
+	botTokenResolver = resolver
+}
+
+// BotAuth ยืนยันตัวตนของบอทด้วย API token แทน JWT
+// รับ token จาก "Authorization: Bot <token>" หรือ header X-Bot-Token
+// ตั้งค่า userID/userUUID เป็น user ของบอท เพื่อให้ handler ใช้ GetUserUUID ได้เหมือนผู้ใช้ทั่วไป
+func BotAuth() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token := c.Get("X-Bot-Token")
+		if token == "" {
+			parts := strings.SplitN(c.Get("Authorization"), " ", 2)
+			if len(parts) == 2 && parts[0] == "Bot" {
+				token = strings.TrimSpace(parts[1])
+			}
+		}
+
+		if token == "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"success": false,
+				"message": "Bot token is required",
+			})
+		}
+
+		if botTokenResolver == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"success": false,
+				"message": "Bot API is not configured",
+			})
+		}
+
+		bot, err := botTokenResolver.AuthenticateToken(token)
+		if err != nil || bot == nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid or disabled bot token",
+			})
+		}
+
+		c.Locals("userID", bot.UserID.String())
+		c.Locals("userUUID", bot.UserID)
+
+		return c.Next()
+	}
+}
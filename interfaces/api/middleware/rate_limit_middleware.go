@@ -0,0 +1,108 @@
+// interfaces/api/middleware/rate_limit_middleware.go
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+)
+
+// วิธีระบุผู้เรียกของ rate limit policy
+const (
+	RateLimitByIP    = "ip"    // ตาม IP (route ที่ยังไม่ยืนยันตัวตน)
+	RateLimitByUser  = "user"  // ตาม user ID (ถ้าไม่มีผู้ใช้ใน context จะใช้ IP แทน)
+	RateLimitByToken = "token" // ตาม token ใน path (:token) เช่น incoming webhook (เก็บเป็น hash ไม่ใช่ token จริง)
+)
+
+// RateLimitPolicy โควต้าของ route หนึ่งกลุ่ม (route ที่ใช้ Name เดียวกันแชร์โควต้ากัน)
+type RateLimitPolicy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+	KeyBy  string
+}
+
+// Policy ของ REST API
+var (
+	LoginRateLimit                = RateLimitPolicy{Name: "auth_login", Limit: 10, Window: time.Minute, KeyBy: RateLimitByIP}
+	RegisterRateLimit             = RateLimitPolicy{Name: "auth_register", Limit: 5, Window: time.Hour, KeyBy: RateLimitByIP}
+	RefreshTokenRateLimit         = RateLimitPolicy{Name: "auth_refresh", Limit: 30, Window: time.Minute, KeyBy: RateLimitByIP}
+	ForgotPasswordRateLimit       = RateLimitPolicy{Name: "auth_forgot_password", Limit: 5, Window: time.Hour, KeyBy: RateLimitByIP}
+	AccountTokenRateLimit         = RateLimitPolicy{Name: "auth_account_token", Limit: 20, Window: time.Hour, KeyBy: RateLimitByIP}
+	TwoFactorLoginRateLimit       = RateLimitPolicy{Name: "auth_login_2fa", Limit: 10, Window: time.Minute, KeyBy: RateLimitByIP}
+	OAuthRateLimit                = RateLimitPolicy{Name: "auth_oauth", Limit: 20, Window: time.Minute, KeyBy: RateLimitByIP}
+	EmailVerificationRateLimit    = RateLimitPolicy{Name: "auth_email_verification", Limit: 5, Window: time.Hour, KeyBy: RateLimitByUser}
+	FriendRequestRateLimit        = RateLimitPolicy{Name: "friend_request", Limit: 20, Window: time.Hour, KeyBy: RateLimitByUser}
+	MessageSendRateLimit          = RateLimitPolicy{Name: "message_send", Limit: 60, Window: time.Minute, KeyBy: RateLimitByUser}
+	FileUploadRateLimit           = RateLimitPolicy{Name: "file_upload", Limit: 30, Window: time.Minute, KeyBy: RateLimitByUser}
+	BotMessageSendRateLimit       = RateLimitPolicy{Name: "bot_message_send", Limit: 30, Window: time.Minute, KeyBy: RateLimitByUser}
+	IncomingWebhookRateLimit      = RateLimitPolicy{Name: "incoming_webhook", Limit: 60, Window: time.Minute, KeyBy: RateLimitByIP}
+	IncomingWebhookTokenRateLimit = RateLimitPolicy{Name: "incoming_webhook_token", Limit: 30, Window: time.Minute, KeyBy: RateLimitByToken}
+)
+
+// rateLimiter ถูกตั้งค่าตอนเริ่มระบบ (nil = ไม่จำกัด)
+var rateLimiter service.RateLimiter
+
+// SetRateLimiter ตั้งค่า rate limiter ให้ RateLimit
+func SetRateLimiter(limiter service.RateLimiter) {
+	rateLimiter = limiter
+}
+
+// RateLimit จำกัดจำนวน request ตาม policy (sliding window ใน Redis)
+// ใส่ header X-RateLimit-Limit/Remaining/Reset ทุก response และ Retry-After เมื่อเกินโควต้า
+// ถ้า Redis มีปัญหาจะปล่อย request ผ่าน เพื่อไม่ให้ API ล่มตาม
+func RateLimit(policy RateLimitPolicy) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if rateLimiter == nil {
+			return c.Next()
+		}
+
+		key := policy.Name + ":ip:" + c.IP()
+		switch policy.KeyBy {
+		case RateLimitByUser:
+			if userID, ok := c.Locals("userID").(string); ok && userID != "" {
+				key = policy.Name + ":user:" + userID
+			}
+		case RateLimitByToken:
+			if token := c.Params("token"); token != "" {
+				sum := sha256.Sum256([]byte(token))
+				key = policy.Name + ":token:" + hex.EncodeToString(sum[:])
+			}
+		}
+
+		result, err := rateLimiter.Allow(c.UserContext(), key, policy.Limit, policy.Window)
+		if err != nil {
+			fmt.Printf("Error checking rate limit: %v, policy: %s\n", err, policy.Name)
+			return c.Next()
+		}
+
+		c.Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Set("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
+
+		if !result.Allowed {
+			SetRetryAfter(c, result.RetryAfter)
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"success": false,
+				"message": "Too many requests, please try again later",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// SetRetryAfter ใส่ header Retry-After เป็นวินาที (ปัดขึ้น)
+func SetRetryAfter(c *fiber.Ctx, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Set("Retry-After", strconv.Itoa(seconds))
+}
@@ -0,0 +1,57 @@
+// interfaces/api/routes/admin_routes.go
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/handler"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+)
+
+// SetupAdminRoutes กำหนดเส้นทาง API สำหรับผู้ดูแลแพลตฟอร์ม (ทุก request ถูกบันทึก audit log)
+func SetupAdminRoutes(router fiber.Router, adminHandler *handler.AdminHandler, stickerHandler *handler.StickerHandler, moderationHandler *handler.ModerationHandler) {
+	admin := router.Group("/admin")
+	admin.Use(middleware.Protected(), middleware.AdminOnly()) // ต้องล็อกอินและมี system role
+
+	moderators := middleware.RequireSystemRole(models.SystemRoleModerator)
+	superAdmins := middleware.RequireSystemRole()
+	support := middleware.RequireSystemRole(models.SystemRoleSupport)
+
+	// จัดการผู้ใช้
+	admin.Get("/users", adminHandler.Audit("user.list", "user", ""), adminHandler.ListUsers)
+	admin.Get("/users/:userId", adminHandler.Audit("user.view", "user", "userId"), adminHandler.GetUser)
+	admin.Post("/users/:userId/suspend", adminHandler.Audit("user.suspend", "user", "userId"), moderators, adminHandler.SuspendUser)
+	admin.Post("/users/:userId/unsuspend", adminHandler.Audit("user.unsuspend", "user", "userId"), moderators, adminHandler.UnsuspendUser)
+	admin.Put("/users/:userId/role", adminHandler.Audit("user.set_role", "user", "userId"), superAdmins, adminHandler.SetSystemRole)
+
+	// ตรวจสอบการสนทนา (ไม่ต้องเป็นสมาชิก)
+	admin.Get("/conversations/:conversationId", adminHandler.Audit("conversation.inspect", "conversation", "conversationId"), adminHandler.InspectConversation)
+	admin.Get("/conversations/:conversationId/messages", adminHandler.Audit("conversation.messages", "conversation", "conversationId"), adminHandler.GetConversationMessages)
+	admin.Post("/conversations/:conversationId/disable", adminHandler.Audit("conversation.disable", "conversation", "conversationId"), moderators, adminHandler.DisableConversation)
+	admin.Post("/conversations/:conversationId/enable", adminHandler.Audit("conversation.enable", "conversation", "conversationId"), moderators, adminHandler.EnableConversation)
+
+	// คิวตรวจสอบรายงานจากผู้ใช้
+	admin.Get("/reports", adminHandler.Audit("report.list", "report", ""), moderators, moderationHandler.ListReports)
+	admin.Get("/reports/:reportId", adminHandler.Audit("report.view", "report", "reportId"), moderators, moderationHandler.GetReport)
+	admin.Patch("/reports/:reportId/status", adminHandler.Audit("report.update_status", "report", "reportId"), moderators, moderationHandler.UpdateReportStatus)
+	admin.Post("/reports/:reportId/actions", adminHandler.Audit("report.action", "report", "reportId"), moderators, moderationHandler.TakeAction)
+
+	// สถานะ WebSocket Hub
+	admin.Get("/realtime/stats", adminHandler.Audit("realtime.stats", "realtime", ""), support, adminHandler.GetRealtimeStats)
+	admin.Get("/realtime/connections", adminHandler.Audit("realtime.connections", "realtime", ""), support, adminHandler.GetRealtimeConnections)
+
+	// audit log
+	admin.Get("/audit-logs", adminHandler.Audit("audit.list", "audit_log", ""), superAdmins, adminHandler.ListAuditLogs)
+
+	// การจัดการชุดสติกเกอร์ (สำหรับแอดมิน)
+	stickers := admin.Group("/stickers")
+	stickers.Post("/sets", adminHandler.Audit("sticker_set.create", "sticker_set", ""), moderators, stickerHandler.CreateStickerSet)                                    // [success] 18.1.1 การสร้างชุดสติกเกอร์ใหม่ [Y]
+	stickers.Patch("/sets/:stickerSetId", adminHandler.Audit("sticker_set.update", "sticker_set", "stickerSetId"), moderators, stickerHandler.UpdateStickerSet)         // [success] 18.1.2 การอัปเดตข้อมูลชุดสติกเกอร์ [Y]
+	stickers.Delete("/sets/:stickerSetId", adminHandler.Audit("sticker_set.delete", "sticker_set", "stickerSetId"), moderators, stickerHandler.DeleteStickerSet)        // [success] 18.1.4 การลบชุดสติกเกอร์ [Y]
+	stickers.Put("/sets/:stickerSetId/cover", adminHandler.Audit("sticker_set.cover", "sticker_set", "stickerSetId"), moderators, stickerHandler.UploadStickerSetCover) // [success] 18.1.3 การอัปโหลดรูปปกชุดสติกเกอร์ [Y]
+
+	// การจัดการสติกเกอร์ (สำหรับแอดมิน)
+	stickers.Post("/sets/:stickerSetId/stickers", adminHandler.Audit("sticker.create", "sticker_set", "stickerSetId"), moderators, stickerHandler.AddStickerToSet) // [success] 18.2.1 การเพิ่มสติกเกอร์ใหม่ลงในชุด [Y]
+	stickers.Patch("/stickers/:stickerId", adminHandler.Audit("sticker.update", "sticker", "stickerId"), moderators, stickerHandler.UpdateSticker)                 // [success] 18.2.2 การอัปเดตข้อมูลสติกเกอร์ [Y]
+	stickers.Delete("/stickers/:stickerId", adminHandler.Audit("sticker.delete", "sticker", "stickerId"), moderators, stickerHandler.DeleteSticker)                // [success] 18.2.3 การลบสติกเกอร์ [Y]
+}
@@ -11,12 +11,39 @@ import (
 func SetupAuthRoutes(router fiber.Router, authHandler *handler.AuthHandler) {
 	// เส้นทางที่ไม่ต้องการการยืนยันตัวตน
 	authRoutes := router.Group("/auth")
-	authRoutes.Post("/register", authHandler.Register)          // [success] 1.1 การลงทะเบียนสร้างผู้ใช้ใหม่ [Y]
-	authRoutes.Post("/login", authHandler.Login)                // [success] 1.2 การเข้าสู่ระบบ [Y]
-	authRoutes.Post("/refresh-token", authHandler.RefreshToken) // [success] 1.4 การต่ออายุ Token [Y]
-	// authRoutes.Post("/reset-password", authHandler.ResetPassword) // [pendding]
+	authRoutes.Post("/register", middleware.RateLimit(middleware.RegisterRateLimit), authHandler.Register)              // [success] 1.1 การลงทะเบียนสร้างผู้ใช้ใหม่ [Y]
+	authRoutes.Post("/login", middleware.RateLimit(middleware.LoginRateLimit), authHandler.Login)                       // [success] 1.2 การเข้าสู่ระบบ [Y]
+	authRoutes.Post("/refresh-token", middleware.RateLimit(middleware.RefreshTokenRateLimit), authHandler.RefreshToken) // [success] 1.4 การต่ออายุ Token [Y]
+	authRoutes.Post("/login/2fa", middleware.RateLimit(middleware.TwoFactorLoginRateLimit), authHandler.LoginTwoFactor) // ขั้นที่สองของการเข้าสู่ระบบ (บัญชีที่เปิด 2FA)
+
+	// รีเซ็ตรหัสผ่านและยืนยันอีเมล (token ใช้ครั้งเดียวที่ส่งทางอีเมล)
+	authRoutes.Post("/forgot-password", middleware.RateLimit(middleware.ForgotPasswordRateLimit), authHandler.ForgotPassword)
+	authRoutes.Post("/reset-password", middleware.RateLimit(middleware.AccountTokenRateLimit), authHandler.ResetPassword)
+	authRoutes.Post("/verify-email", middleware.RateLimit(middleware.AccountTokenRateLimit), authHandler.VerifyEmail)
+	authRoutes.Post("/verify-email/resend", middleware.Protected(), middleware.RateLimit(middleware.EmailVerificationRateLimit), authHandler.ResendEmailVerification)
 
 	// เส้นทางที่ต้องการการยืนยันตัวตน
 	authRoutes.Get("/user", middleware.Protected(), authHandler.GetCurrentUser) // [success] 1.3 การดึงข้อมูลผู้ใช้ปัจจุบัน [Y]
 	authRoutes.Post("/logout", middleware.Protected(), authHandler.Logout)      // [success] 1.5 การออกจากระบบ [Y]
+
+	// Sessions - จัดการอุปกรณ์ที่เข้าสู่ระบบ (ต้องลงทะเบียน /others ก่อน /:id)
+	authRoutes.Get("/sessions", middleware.Protected(), authHandler.ListSessions)
+	authRoutes.Delete("/sessions/others", middleware.Protected(), authHandler.RevokeOtherSessions) // ออกจากระบบทุกอุปกรณ์ยกเว้นเครื่องนี้
+	authRoutes.Delete("/sessions/:id", middleware.Protected(), authHandler.RevokeSession)
+
+	// Two-factor authentication (TOTP) - ปิดใช้และสร้างรหัสกู้คืนใหม่ต้องยืนยันรหัสผ่านและรหัส 2FA
+	authRoutes.Get("/2fa", middleware.Protected(), authHandler.GetTwoFactorStatus)
+	authRoutes.Post("/2fa/setup", middleware.Protected(), authHandler.SetupTwoFactor)
+	authRoutes.Post("/2fa/confirm", middleware.Protected(), authHandler.ConfirmTwoFactor)
+	authRoutes.Post("/2fa/disable", middleware.Protected(), authHandler.DisableTwoFactor)
+	authRoutes.Post("/2fa/recovery-codes", middleware.Protected(), authHandler.RegenerateRecoveryCodes)
+
+	// OAuth2/OIDC - เข้าสู่ระบบและเชื่อมบัญชีกับ provider ภายนอก (ต้องลงทะเบียน /providers และ /identities ก่อน /:provider)
+	authRoutes.Get("/oauth/providers", authHandler.ListOAuthProviders)
+	authRoutes.Get("/oauth/identities", middleware.Protected(), authHandler.ListOAuthIdentities)
+	authRoutes.Delete("/oauth/identities/:id", middleware.Protected(), authHandler.UnlinkOAuthIdentity)
+	authRoutes.Get("/oauth/:provider/authorize", middleware.RateLimit(middleware.OAuthRateLimit), authHandler.StartOAuthLogin)
+	authRoutes.Post("/oauth/:provider/callback", middleware.RateLimit(middleware.OAuthRateLimit), authHandler.OAuthCallback)
+	authRoutes.Post("/oauth/:provider/link", middleware.Protected(), authHandler.StartOAuthLink)
+	authRoutes.Post("/oauth/:provider/link/callback", middleware.Protected(), authHandler.OAuthLinkCallback)
 }
@@ -0,0 +1,33 @@
+// interfaces/api/routes/bot_routes.go
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/handler"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+)
+
+// SetupBotRoutes sets up routes for bot management and the Bot API
+func SetupBotRoutes(router fiber.Router, botHandler *handler.BotHandler) {
+	// จัดการบอทของตัวเอง (JWT ของผู้ใช้)
+	bots := router.Group("/bots")
+	bots.Use(middleware.Protected())
+
+	bots.Post("/", botHandler.CreateBot)
+	bots.Get("/", botHandler.ListBots)
+	bots.Get("/:botId", botHandler.GetBot)
+	bots.Patch("/:botId", botHandler.UpdateBot)
+	bots.Delete("/:botId", botHandler.DeleteBot)
+	bots.Post("/:botId/token", botHandler.RotateToken)
+	bots.Post("/:botId/webhook-secret", botHandler.RotateWebhookSecret)
+
+	// Delivery log ของ outgoing webhook
+	bots.Get("/:botId/deliveries", botHandler.GetDeliveries)
+
+	// Bot API (ยืนยันตัวตนด้วย API token ของบอท)
+	botAPI := router.Group("/bot-api")
+	botAPI.Use(middleware.BotAuth())
+
+	botAPI.Get("/me", botHandler.GetMe)
+	botAPI.Post("/conversations/:conversationId/messages", middleware.RateLimit(middleware.BotMessageSendRateLimit), botHandler.SendMessage)
+}
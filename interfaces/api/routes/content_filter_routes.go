@@ -0,0 +1,18 @@
+// interfaces/api/routes/content_filter_routes.go
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/handler"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+)
+
+// SetupContentFilterRoutes กำหนดเส้นทาง API สำหรับการตั้งค่า content filter ของกลุ่ม
+func SetupContentFilterRoutes(router fiber.Router, contentFilterHandler *handler.ContentFilterHandler) {
+	// owner/admin ของกลุ่มหรือช่อง
+	conversations := router.Group("/conversations")
+	conversations.Use(middleware.Protected())
+
+	conversations.Get("/:conversationId/content-filters", contentFilterHandler.GetSettings)    // ดึงการตั้งค่า
+	conversations.Put("/:conversationId/content-filters", contentFilterHandler.UpdateSettings) // แก้ไขการตั้งค่า
+}
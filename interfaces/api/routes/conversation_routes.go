@@ -29,6 +29,8 @@ func SetupConversationRoutes(
 	conversations.Patch("/:conversationId/pin", conversationHandler.TogglePinConversation)      // [success] 8.5 การเปลี่ยนสถานะปักหมุดของการสนทนา [Y]
 	conversations.Patch("/:conversationId/mute", conversationHandler.ToggleMuteConversation)    // [success] 8.6 การเปลี่ยนสถานะการปิดเสียงของการสนทนา [Y]
 	conversations.Patch("/:conversationId/hide", conversationHandler.HideConversation)          // การซ่อน/แสดงการสนทนา
+	conversations.Get("/:conversationId/notification-settings", conversationHandler.GetNotificationSettings)    // การตั้งค่าการแจ้งเตือน (level, ปิดเสียงชั่วคราว)
+	conversations.Put("/:conversationId/notification-settings", conversationHandler.UpdateNotificationSettings) // ตั้งค่า all/mentions/none และ muted_until
 	conversations.Delete("/:conversationId", conversationHandler.DeleteConversation)            // การลบการสนทนา (smart delete)
 
 	// Media Gallery & Jump to Message
@@ -46,6 +48,8 @@ func SetupConversationRoutes(
 	// การจัดการ role และ ownership
 	conversations.Patch("/:conversationId/members/:userId/role", conversationMemberHandler.ChangeRole)           // เปลี่ยน role ของสมาชิก (owner/admin/member)
 	conversations.Post("/:conversationId/transfer-ownership", conversationHandler.TransferOwnership)             // โอนความเป็นเจ้าของกลุ่มให้สมาชิกคนอื่น
+	conversations.Patch("/:conversationId/poll-permission", conversationMemberHandler.UpdatePollPermission)      // กำหนดว่าใครสร้างโพลได้ (all/admins/owner)
+	conversations.Patch("/:conversationId/message-ttl", conversationMemberHandler.UpdateMessageTTL)              // ตั้งค่าข้อความที่หายไปเอง (0/1h/1d/7d)
 
 	// Group Activity Log
 	conversations.Get("/:conversationId/activities", conversationHandler.GetActivities) // ดึง activity log ของกลุ่ม
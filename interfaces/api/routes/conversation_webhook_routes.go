@@ -0,0 +1,28 @@
+// interfaces/api/routes/conversation_webhook_routes.go
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/handler"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+)
+
+// SetupConversationWebhookRoutes กำหนดเส้นทาง API สำหรับ incoming webhook ของการสนทนา
+func SetupConversationWebhookRoutes(router fiber.Router, webhookHandler *handler.ConversationWebhookHandler) {
+	// จัดการ webhook (owner/admin ของกลุ่ม)
+	conversations := router.Group("/conversations")
+	conversations.Use(middleware.Protected())
+
+	conversations.Post("/:conversationId/webhooks", webhookHandler.CreateWebhook)              // สร้าง webhook
+	conversations.Get("/:conversationId/webhooks", webhookHandler.ListWebhooks)                // ดึง webhook ของกลุ่ม (?include_revoked=true)
+	conversations.Delete("/:conversationId/webhooks/:webhookId", webhookHandler.RevokeWebhook) // เพิกถอน webhook
+
+	// โพสต์ข้อความจากระบบภายนอก (ยืนยันด้วย token ใน path ไม่ใช้ JWT จึงจำกัดทั้งตาม IP และตาม token)
+	hooks := router.Group("/hooks")
+	hooks.Post("/:token",
+		middleware.BodyLimit(middleware.IncomingWebhookBodyLimit),
+		middleware.RateLimit(middleware.IncomingWebhookRateLimit),
+		middleware.RateLimit(middleware.IncomingWebhookTokenRateLimit),
+		webhookHandler.Execute,
+	)
+}
@@ -0,0 +1,23 @@
+// interfaces/api/routes/e2ee_routes.go
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/handler"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+)
+
+// SetupE2EERoutes sets up routes for end-to-end encryption key bundles
+func SetupE2EERoutes(router fiber.Router, e2eeHandler *handler.E2EEHandler) {
+	keys := router.Group("/keys")
+	keys.Use(middleware.Protected())
+
+	// key bundle ของอุปกรณ์ตัวเอง (device_id กำหนดโดย client)
+	keys.Put("/devices/:device_id", e2eeHandler.UploadBundle)
+	keys.Delete("/devices/:device_id", e2eeHandler.RemoveDevice)
+	keys.Post("/devices/:device_id/prekeys", e2eeHandler.UploadPreKeys)
+	keys.Get("/devices/:device_id/prekeys/count", e2eeHandler.GetPreKeyCount)
+
+	// ดึง key bundle ของผู้ใช้อื่นเพื่อเริ่ม session (ใช้ one-time pre-key อุปกรณ์ละหนึ่งอัน)
+	keys.Get("/users/:user_id", e2eeHandler.GetUserBundles)
+}
@@ -14,15 +14,15 @@ func SetupFileRoutes(router fiber.Router, fileHandler *handler.FileHandler) {
 	files.Use(middleware.Protected())
 
 	// Upload routes (multipart form)
-	files.Post("/image", fileHandler.UploadImage) // [success] 3.1 การอัปโหลดรูปภาพ [Y]
-	files.Post("/file", fileHandler.UploadFile)   // [success] 3.2 การอัปโหลดไฟล์ [Y]
+	files.Post("/image", middleware.RateLimit(middleware.FileUploadRateLimit), fileHandler.UploadImage) // [success] 3.1 การอัปโหลดรูปภาพ [Y]
+	files.Post("/file", middleware.RateLimit(middleware.FileUploadRateLimit), fileHandler.UploadFile)   // [success] 3.2 การอัปโหลดไฟล์ [Y]
 
 	// Presigned URL route (JSON body) - Legacy
-	files.Post("/presigned-upload", fileHandler.GeneratePresignedUploadURL) // สร้าง presigned URL สำหรับ direct upload
+	files.Post("/presigned-upload", middleware.RateLimit(middleware.FileUploadRateLimit), fileHandler.GeneratePresignedUploadURL) // สร้าง presigned URL สำหรับ direct upload
 
 	// New Upload Workflow (Recommended)
-	files.Post("/prepare-upload", fileHandler.PrepareUpload) // เตรียม upload และสร้าง presigned URL พร้อม tracking
-	files.Post("/confirm-upload", fileHandler.ConfirmUpload) // ยืนยันว่า upload สำเร็จ
+	files.Post("/prepare-upload", middleware.RateLimit(middleware.FileUploadRateLimit), fileHandler.PrepareUpload) // เตรียม upload และสร้าง presigned URL พร้อม tracking
+	files.Post("/confirm-upload", fileHandler.ConfirmUpload)                                                       // ยืนยันว่า upload สำเร็จ
 
 	// Delete route (JSON body)
 	files.Delete("/", fileHandler.DeleteFile) // ลบไฟล์
@@ -30,6 +30,6 @@ func SetupFileRoutes(router fiber.Router, fileHandler *handler.FileHandler) {
 	// Legacy routes for backward compatibility
 	upload := router.Group("/upload")
 	upload.Use(middleware.Protected())
-	upload.Post("/image", fileHandler.UploadImage)
-	upload.Post("/file", fileHandler.UploadFile)
+	upload.Post("/image", middleware.RateLimit(middleware.FileUploadRateLimit), fileHandler.UploadImage)
+	upload.Post("/file", middleware.RateLimit(middleware.FileUploadRateLimit), fileHandler.UploadFile)
 }
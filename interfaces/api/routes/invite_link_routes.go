@@ -0,0 +1,26 @@
+// interfaces/api/routes/invite_link_routes.go
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/handler"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+)
+
+// SetupInviteLinkRoutes กำหนดเส้นทาง API สำหรับลิงก์เชิญเข้ากลุ่ม
+func SetupInviteLinkRoutes(router fiber.Router, inviteLinkHandler *handler.InviteLinkHandler) {
+	// จัดการลิงก์เชิญ (owner/admin ของกลุ่ม)
+	conversations := router.Group("/conversations")
+	conversations.Use(middleware.Protected())
+
+	conversations.Post("/:conversationId/invite-links", inviteLinkHandler.CreateLink)           // สร้างลิงก์เชิญ
+	conversations.Get("/:conversationId/invite-links", inviteLinkHandler.ListLinks)             // ดึงลิงก์เชิญของกลุ่ม (?include_revoked=true)
+	conversations.Delete("/:conversationId/invite-links/:linkId", inviteLinkHandler.RevokeLink) // เพิกถอนลิงก์เชิญ
+
+	// ใช้ลิงก์เชิญ (ผู้ที่ยังไม่เป็นสมาชิก)
+	invites := router.Group("/invites")
+	invites.Use(middleware.Protected())
+
+	invites.Get("/:code", inviteLinkHandler.PreviewLink)     // ดูข้อมูลกลุ่มก่อนเข้าร่วม
+	invites.Post("/:code/join", inviteLinkHandler.JoinGroup) // เข้าร่วมกลุ่มผ่านลิงก์
+}
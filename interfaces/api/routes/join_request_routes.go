@@ -0,0 +1,19 @@
+// interfaces/api/routes/join_request_routes.go
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/handler"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+)
+
+// SetupJoinRequestRoutes กำหนดเส้นทาง API สำหรับคำขอเข้าร่วมกลุ่ม
+func SetupJoinRequestRoutes(router fiber.Router, joinRequestHandler *handler.JoinRequestHandler) {
+	conversations := router.Group("/conversations")
+	conversations.Use(middleware.Protected())
+
+	conversations.Post("/:conversationId/join-requests", joinRequestHandler.SubmitRequest)                     // ส่งคำขอเข้าร่วม (ผู้ที่ยังไม่เป็นสมาชิก)
+	conversations.Get("/:conversationId/join-requests", joinRequestHandler.ListRequests)                       // ดึงคำขอ (owner/admin, ?status=pending)
+	conversations.Post("/:conversationId/join-requests/:requestId/approve", joinRequestHandler.ApproveRequest) // อนุมัติคำขอ
+	conversations.Post("/:conversationId/join-requests/:requestId/reject", joinRequestHandler.RejectRequest)   // ปฏิเสธคำขอ
+}
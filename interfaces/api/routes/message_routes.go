@@ -14,21 +14,34 @@ func SetupMessageRoutes(router fiber.Router, messageHandler *handler.MessageHand
 	messages.Use(middleware.Protected())
 
 	// เส้นทางจัดการข้อความ
-	messages.Patch("/:messageId", messageHandler.EditMessage)                          // [success] 10.5 การแก้ไขข้อความ [Y]
-	messages.Get("/:messageId/edit-history", messageHandler.GetMessageEditHistory)     // [success] 10.6 การดูประวัติการแก้ไขข้อความ [Y]
-	messages.Delete("/:messageId", messageHandler.DeleteMessage)                       // [success] 10.7 การลบข้อความ [Y]
-	messages.Get("/:messageId/delete-history", messageHandler.GetMessageDeleteHistory) // [success] 10.8 การดูประวัติการลบข้อความ [Y]
-	messages.Post("/:messageId/reply", messageHandler.ReplyToMessage)                  // [success] 10.9 การตอบกลับข้อความ [Y]
+	messages.Patch("/:messageId", messageHandler.EditMessage)                                                                // [success] 10.5 การแก้ไขข้อความ [Y]
+	messages.Get("/:messageId/edit-history", messageHandler.GetMessageEditHistory)                                           // [success] 10.6 การดูประวัติการแก้ไขข้อความ [Y]
+	messages.Delete("/:messageId", messageHandler.DeleteMessage)                                                             // [success] 10.7 การลบข้อความ [Y]
+	messages.Get("/:messageId/delete-history", messageHandler.GetMessageDeleteHistory)                                       // [success] 10.8 การดูประวัติการลบข้อความ [Y]
+	messages.Post("/:messageId/reply", middleware.RateLimit(middleware.MessageSendRateLimit), messageHandler.ReplyToMessage) // [success] 10.9 การตอบกลับข้อความ [Y]
 
 	// เส้นทางส่งข้อความประเภทต่างๆ ของบัญชีธรรมดา
 	conversations := router.Group("/conversations")
 	conversations.Use(middleware.Protected())
 
-	conversations.Post("/:conversationId/messages/text", messageHandler.SendTextMessage)       //  [success] 10.1 การส่งข้อความประเภทข้อความ [Y]
-	conversations.Post("/:conversationId/messages/sticker", messageHandler.SendStickerMessage) //  [success] 10.2 การส่งข้อความประเภทสติกเกอร์ [Y]
-	conversations.Post("/:conversationId/messages/image", messageHandler.SendImageMessage)     //  [success] 10.3 การส่งข้อความประเภทรูปภาพ [Y]
-	conversations.Post("/:conversationId/messages/file", messageHandler.SendFileMessage)       //  [success] 10.4 การส่งข้อความประเภทไฟล์ [Y]
-	conversations.Post("/:conversationId/messages/bulk", messageHandler.SendBulkMessages)      //  [new] 10.10 การส่งหลายข้อความพร้อมกัน (Album) [Y]
+	conversations.Post("/:conversationId/messages/text", middleware.RateLimit(middleware.MessageSendRateLimit), messageHandler.SendTextMessage)       //  [success] 10.1 การส่งข้อความประเภทข้อความ [Y]
+	conversations.Post("/:conversationId/messages/sticker", middleware.RateLimit(middleware.MessageSendRateLimit), messageHandler.SendStickerMessage) //  [success] 10.2 การส่งข้อความประเภทสติกเกอร์ [Y]
+	conversations.Post("/:conversationId/messages/image", middleware.RateLimit(middleware.MessageSendRateLimit), messageHandler.SendImageMessage)     //  [success] 10.3 การส่งข้อความประเภทรูปภาพ [Y]
+	conversations.Post("/:conversationId/messages/file", middleware.RateLimit(middleware.MessageSendRateLimit), messageHandler.SendFileMessage)       //  [success] 10.4 การส่งข้อความประเภทไฟล์ [Y]
+	conversations.Post("/:conversationId/messages/bulk", middleware.RateLimit(middleware.MessageSendRateLimit), messageHandler.SendBulkMessages)      //  [new] 10.10 การส่งหลายข้อความพร้อมกัน (Album) [Y]
+
+	// Reactions (emoji) ต่อข้อความ
+	conversations.Post("/:conversationId/messages/:messageId/reactions", messageHandler.AddReaction)      // เพิ่ม/สลับปฏิกิริยา
+	conversations.Delete("/:conversationId/messages/:messageId/reactions", messageHandler.RemoveReaction) // ลบปฏิกิริยา (?emoji=)
+
+	// End-to-end encrypted (เฉพาะ direct) - Content เป็น envelope ของ ciphertext ต่ออุปกรณ์
+	conversations.Post("/:conversationId/messages/encrypted", middleware.RateLimit(middleware.MessageSendRateLimit), messageHandler.SendEncryptedMessage)
+
+	// Polls - สร้างโพล, โหวต/ถอนโหวต และปิดโพล
+	conversations.Post("/:conversationId/messages/poll", middleware.RateLimit(middleware.MessageSendRateLimit), messageHandler.SendPollMessage) // สร้างโพล (ตรวจสิทธิ์ create_poll)
+	conversations.Post("/:conversationId/messages/poll/:messageId/vote", messageHandler.VotePoll)                                               // โหวต
+	conversations.Delete("/:conversationId/messages/poll/:messageId/vote", messageHandler.RetractPollVote)                                      // ถอนโหวต
+	conversations.Post("/:conversationId/messages/poll/:messageId/close", messageHandler.ClosePoll)                                             // ปิดโพล (ผู้สร้าง/admin)
 
 	// Pin messages - ใช้ pinned_message_routes.go แทน (pinned_messages table ใหม่)
 	// routes ถูกย้ายไป pinned_message_routes.go แล้ว
@@ -37,5 +50,5 @@ func SetupMessageRoutes(router fiber.Router, messageHandler *handler.MessageHand
 	messages.Get("/search", messageHandler.SearchMessages) // ค้นหาข้อความ
 
 	// Forward messages
-	messages.Post("/forward", messageHandler.ForwardMessages) // ส่งต่อข้อความ
+	messages.Post("/forward", middleware.RateLimit(middleware.MessageSendRateLimit), messageHandler.ForwardMessages) // ส่งต่อข้อความ
 }
@@ -0,0 +1,21 @@
+// interfaces/api/routes/notification_routes.go
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/handler"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+)
+
+// SetupNotificationRoutes กำหนดเส้นทาง API สำหรับกล่องแจ้งเตือน
+func SetupNotificationRoutes(router fiber.Router, notificationHandler *handler.NotificationHandler) {
+	notifications := router.Group("/notifications")
+	notifications.Use(middleware.Protected())
+
+	notifications.Get("", notificationHandler.ListNotifications)                     // ดึงการแจ้งเตือน (cursor, ?unread_only=true)
+	notifications.Get("/unread-count", notificationHandler.GetUnreadCount)           // จำนวนที่ยังไม่ได้อ่าน (badge)
+	notifications.Post("/read", notificationHandler.MarkRead)                        // อ่านแล้วหลายรายการ {"ids": [...]}
+	notifications.Post("/read-all", notificationHandler.MarkAllRead)                 // อ่านแล้วทั้งหมด
+	notifications.Post("/:notificationId/read", notificationHandler.MarkOneRead)     // อ่านแล้วรายการเดียว
+	notifications.Delete("/:notificationId", notificationHandler.DeleteNotification) // ลบออกจากกล่อง
+}
@@ -0,0 +1,25 @@
+// interfaces/api/routes/push_routes.go
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/handler"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+)
+
+// SetupPushRoutes sets up routes for push notification devices
+func SetupPushRoutes(router fiber.Router, pushHandler *handler.PushHandler) {
+	push := router.Group("/push")
+	push.Use(middleware.Protected())
+
+	// Provider config (VAPID public key สำหรับ web)
+	push.Get("/config", pushHandler.GetConfig)
+
+	// Device registration
+	push.Post("/devices", pushHandler.RegisterDevice)
+	push.Get("/devices", pushHandler.ListDevices)
+	push.Delete("/devices/:device_id", pushHandler.UnregisterDevice)
+
+	// Delivery log (ตรวจสอบ push ที่ส่งไม่ถึง)
+	push.Get("/deliveries", pushHandler.GetDeliveries)
+}
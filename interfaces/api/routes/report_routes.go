@@ -0,0 +1,18 @@
+// interfaces/api/routes/report_routes.go
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/handler"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+)
+
+// SetupReportRoutes กำหนดเส้นทาง API สำหรับรายงานเนื้อหาไม่เหมาะสม (คิวตรวจสอบอยู่ใน SetupAdminRoutes)
+func SetupReportRoutes(router fiber.Router, moderationHandler *handler.ModerationHandler) {
+	reports := router.Group("/reports")
+	reports.Use(middleware.Protected())
+
+	reports.Post("/messages/:messageId", moderationHandler.ReportMessage)                // รายงานข้อความ
+	reports.Post("/users/:userId", moderationHandler.ReportUser)                         // รายงานผู้ใช้
+	reports.Post("/conversations/:conversationId", moderationHandler.ReportConversation) // รายงานกลุ่ม/ช่อง
+}
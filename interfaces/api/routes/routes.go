@@ -28,8 +28,23 @@ func SetupRoutes(
 	searchHandler *handler.SearchHandler,
 	presenceHandler *handler.PresenceHandler,
 	pinnedMessageHandler *handler.PinnedMessageHandler,
+	threadHandler *handler.ThreadHandler,
+	pushHandler *handler.PushHandler,
+	syncHandler *handler.SyncHandler,
+	e2eeHandler *handler.E2EEHandler,
+	adminHandler *handler.AdminHandler,
+	inviteLinkHandler *handler.InviteLinkHandler,
+	joinRequestHandler *handler.JoinRequestHandler,
+	botHandler *handler.BotHandler,
+	conversationWebhookHandler *handler.ConversationWebhookHandler,
+	moderationHandler *handler.ModerationHandler,
+	contentFilterHandler *handler.ContentFilterHandler,
+	notificationHandler *handler.NotificationHandler,
 
 ) {
+	// Public key สำหรับให้ service อื่นตรวจ JWT ของระบบ (RFC 7517)
+	app.Get("/.well-known/jwks.json", authHandler.GetJWKS)
+
 	// สร้าง API group
 	api := app.Group("/api/v1")
 
@@ -63,5 +78,17 @@ func SetupRoutes(
 	SetupSearchRoutes(api, searchHandler)
 	SetupPresenceRoutes(api, presenceHandler)
 	SetupPinnedMessageRoutes(api, pinnedMessageHandler)
+	SetupThreadRoutes(api, threadHandler)
+	SetupPushRoutes(api, pushHandler)
+	SetupSyncRoutes(api, syncHandler)
+	SetupE2EERoutes(api, e2eeHandler)
+	SetupAdminRoutes(api, adminHandler, stickerHandler, moderationHandler)
+	SetupInviteLinkRoutes(api, inviteLinkHandler)
+	SetupJoinRequestRoutes(api, joinRequestHandler)
+	SetupBotRoutes(api, botHandler)
+	SetupConversationWebhookRoutes(api, conversationWebhookHandler)
+	SetupReportRoutes(api, moderationHandler)
+	SetupContentFilterRoutes(api, contentFilterHandler)
+	SetupNotificationRoutes(api, notificationHandler)
 
 }
@@ -9,21 +9,7 @@ import (
 
 // SetupStickerRoutes กำหนดเส้นทาง API สำหรับสติกเกอร์
 func SetupStickerRoutes(router fiber.Router, stickerHandler *handler.StickerHandler) {
-	// กลุ่มเส้นทางสำหรับผู้ดูแลระบบ
-	adminStickers := router.Group("/admin/stickers")
-	adminStickers.Use(middleware.Protected()) // ต้องล็อกอินก่อน
-	// TODO: เพิ่ม middleware สำหรับตรวจสอบสิทธิ์แอดมิน
-
-	// การจัดการชุดสติกเกอร์ (สำหรับแอดมิน)
-	adminStickers.Post("/sets", stickerHandler.CreateStickerSet)                         // [success] 18.1.1 การสร้างชุดสติกเกอร์ใหม่ [Y]
-	adminStickers.Patch("/sets/:stickerSetId", stickerHandler.UpdateStickerSet)          // [success] 18.1.2 การอัปเดตข้อมูลชุดสติกเกอร์ [Y]
-	adminStickers.Delete("/sets/:stickerSetId", stickerHandler.DeleteStickerSet)         // [success] 18.1.4 การลบชุดสติกเกอร์ [Y]
-	adminStickers.Put("/sets/:stickerSetId/cover", stickerHandler.UploadStickerSetCover) // [success] 18.1.3 การอัปโหลดรูปปกชุดสติกเกอร์ [Y]
-
-	// การจัดการสติกเกอร์ (สำหรับแอดมิน)
-	adminStickers.Post("/sets/:stickerSetId/stickers", stickerHandler.AddStickerToSet) // [success] 18.2.1 การเพิ่มสติกเกอร์ใหม่ลงในชุด [Y]
-	adminStickers.Patch("/stickers/:stickerId", stickerHandler.UpdateSticker)          // [success] 18.2.2 การอัปเดตข้อมูลสติกเกอร์ [Y]
-	adminStickers.Delete("/stickers/:stickerId", stickerHandler.DeleteSticker)         // [success] 18.2.3 การลบสติกเกอร์ [Y]
+	// เส้นทางจัดการสติกเกอร์สำหรับแอดมิน (/admin/stickers) อยู่ใน admin_routes.go
 
 	// กลุ่มเส้นทางสำหรับผู้ใช้ทั่วไป
 	stickers := router.Group("/stickers")
@@ -0,0 +1,17 @@
+// interfaces/api/routes/sync_routes.go
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/handler"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+)
+
+// SetupSyncRoutes sets up routes for delta sync
+func SetupSyncRoutes(router fiber.Router, syncHandler *handler.SyncHandler) {
+	sync := router.Group("/sync")
+	sync.Use(middleware.Protected())
+
+	// Delta sync (since = opaque token จาก next_token ครั้งก่อน)
+	sync.Get("/", syncHandler.Sync)
+}
@@ -0,0 +1,18 @@
+// interfaces/api/routes/thread_routes.go
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/handler"
+	"github.com/thizplus/gofiber-chat-api/interfaces/api/middleware"
+)
+
+// SetupThreadRoutes กำหนดเส้นทาง API สำหรับเธรดข้อความ
+func SetupThreadRoutes(router fiber.Router, threadHandler *handler.ThreadHandler) {
+	messages := router.Group("/messages")
+	messages.Use(middleware.Protected())
+
+	messages.Get("/:messageId/thread", threadHandler.GetThread)            // ดูเธรด (cursor: before/after)
+	messages.Post("/:messageId/thread", threadHandler.ReplyInThread)       // ตอบกลับในเธรด
+	messages.Post("/:messageId/thread/read", threadHandler.MarkThreadRead) // บันทึกการอ่านเธรด
+}
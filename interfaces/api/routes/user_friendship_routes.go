@@ -35,7 +35,7 @@ func SetupUserFriendshipRoutes(router fiber.Router, userFriendshipHandler *handl
 	friends.Get("/block-status/:userId", userFriendshipHandler.GetBlockStatus) // ตรวจสอบ block status กับผู้ใช้คนใดคนหนึ่ง
 
 	// ส่งคำขอเป็นเพื่อน
-	friends.Post("/request/:friendId", userFriendshipHandler.SendFriendRequest) // [success] 4.3 การส่งคำขอเป็นเพื่อน [Y]
+	friends.Post("/request/:friendId", middleware.RateLimit(middleware.FriendRequestRateLimit), userFriendshipHandler.SendFriendRequest) // [success] 4.3 การส่งคำขอเป็นเพื่อน [Y]
 
 	// ตอบรับคำขอเป็นเพื่อน
 	friends.Put("/accept/:requestId", userFriendshipHandler.AcceptFriendRequest) // [success] 4.5 การตอบรับคำขอเป็นเพื่อน [Y]
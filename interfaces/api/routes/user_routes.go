@@ -21,6 +21,10 @@ func SetupUserRoutes(router fiber.Router, userHandler *handler.UserHandler) {
 	// ดึงข้อมูลผู้ใช้ปัจจุบัน
 	userRoutes.Get("/me", userHandler.GetCurrentUser) // [success] 2.1 การดึงข้อมูลผู้ใช้ปัจจุบัน [Y]
 
+	// การตั้งค่าการแจ้งเตือน (quiet hours, keyword alerts)
+	userRoutes.Get("/me/notification-preferences", userHandler.GetNotificationPreferences)
+	userRoutes.Put("/me/notification-preferences", userHandler.UpdateNotificationPreferences)
+
 	// เส้นทางอื่นๆ
 	userRoutes.Get("/:userId", userHandler.GetProfile) // [success] 2.2 การดูโปรไฟล์ผู้ใช้ [Y]
 
@@ -10,28 +10,26 @@ import (
 )
 
 // broadcastMessage sends a message to specified clients
+// ผู้รับทุกคนได้ payload เดียวกัน (sequence เป็นของ stream ไม่ใช่ของผู้รับ) จึง encode ครั้งเดียว
 func (h *Hub) broadcastMessage(msg *BroadcastMessage) {
-	data, err := json.Marshal(WSResponse{
-		Type:      msg.Type,
-		Data:      msg.Data,
-		Timestamp: time.Now(),
-		Success:   true,
-	})
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+
+	data, err := encodeBroadcast(msg)
 	if err != nil {
+		log.Printf("Error encoding broadcast type %s: %v", msg.Type, err)
 		return
 	}
 
 	// Broadcast to specific users
-	if len(msg.UserIDs) > 0 {
-		for _, userID := range msg.UserIDs {
-			h.sendToUser(userID, data, msg.ExcludeID)
-		}
+	for _, userID := range msg.UserIDs {
+		h.sendToUser(userID, data, msg.ExcludeID)
 	}
 
-
 	// Broadcast to conversation
 	if msg.ConvID != nil {
-		h.broadcastToConversation(*msg.ConvID, msg.Type, msg.Data, msg.ExcludeID)
+		h.broadcastToConversation(*msg.ConvID, data, msg.ExcludeID)
 	}
 }
 
@@ -51,36 +49,19 @@ func (h *Hub) sendToUser(userID uuid.UUID, data []byte, excludeID *uuid.UUID) {
 		h.clientsMux.RUnlock()
 
 		if ok {
-			select {
-			case client.Send <- data:
-			default:
-				// Client's send channel is full, close it
-				go func() {
-					h.unregister <- client
-				}()
-			}
+			h.deliver(client, data)
 		}
 	}
 }
 
 
 // broadcastToConversation sends a message to all members of a conversation
-func (h *Hub) broadcastToConversation(convID uuid.UUID, msgType MessageType, data interface{}, excludeID *uuid.UUID) {
+func (h *Hub) broadcastToConversation(convID uuid.UUID, data []byte, excludeID *uuid.UUID) {
 	// Get conversation subscribers
 	h.conversationSubsMux.RLock()
 	subscriberIDs := h.conversationSubs[convID]
 	h.conversationSubsMux.RUnlock()
 
-	response, err := json.Marshal(WSResponse{
-		Type:      msgType,
-		Data:      data,
-		Timestamp: time.Now(),
-		Success:   true,
-	})
-	if err != nil {
-		return
-	}
-
 	// Send to each subscriber
 	for _, clientID := range subscriberIDs {
 		if excludeID != nil && clientID == *excludeID {
@@ -92,14 +73,28 @@ func (h *Hub) broadcastToConversation(convID uuid.UUID, msgType MessageType, dat
 		h.clientsMux.RUnlock()
 
 		if ok {
-			select {
-			case client.Send <- response:
-			default:
-				go func() {
-					h.unregister <- client
-				}()
-			}
+			h.deliver(client, data)
+		}
+	}
+}
+
+// deliver ส่ง event ให้ client แบบไม่ block
+// ถ้า Send buffer เต็มจะตัดการเชื่อมต่อแทนการทิ้ง event เงียบๆ
+// client จะเชื่อมต่อใหม่พร้อม last_seq แล้วได้รับ event ที่พลาดไปผ่าน resume
+func (h *Hub) deliver(client *Client, data []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Recovered from panic in deliver for client %s: %v", client.ID, r)
 		}
+	}()
+
+	select {
+	case client.Send <- data:
+	default:
+		log.Printf("Send buffer full for client %s, disconnecting so it can resume", client.ID)
+		go func() {
+			h.unregister <- client
+		}()
 	}
 }
 
@@ -127,16 +122,7 @@ func (h *Hub) sendToClient(client *Client, response WSResponse) {
 		return
 	}
 
-	select {
-	case client.Send <- data:
-		// Successfully sent
-	default:
-		// Client's send channel is full or closed
-		log.Printf("Failed to send to client %s (channel full or closed)", client.ID)
-		go func() {
-			h.unregister <- client
-		}()
-	}
+	h.deliver(client, data)
 }
 
 // removeClientFromSlice removes a client ID from a slice
@@ -195,12 +181,29 @@ func (h *Hub) removeClientFromAllUserStatusSubscriptions(clientID uuid.UUID) {
 	}
 }
 
-// NotifyBroadcast ส่งข้อความผ่าน broadcast channel
+// NotifyBroadcast ส่งข้อความผ่าน broadcast channel และกระจายไปยัง instance อื่นผ่าน Redis
 func (h *Hub) NotifyBroadcast(msg *BroadcastMessage) {
 	if h == nil || msg == nil {
 		return
 	}
 
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+
+	// event ที่ replay ได้: กำหนด sequence และ publish ในขั้นตอนเดียว แล้วทุก instance (รวม instance นี้)
+	// ส่งให้ client ตามลำดับที่ได้รับจาก Redis ลำดับการส่งจึงตรงกับ sequence เสมอ
+	if h.redis != nil && !ephemeralEventTypes[msg.Type] && (msg.ConvID != nil || len(msg.UserIDs) > 0) {
+		h.publishSequenced(msg)
+		return
+	}
+
+	h.enqueueBroadcast(msg)
+	h.publishBroadcast(msg)
+}
+
+// enqueueBroadcast ส่งข้อความให้ client ที่เชื่อมต่อกับ instance นี้เท่านั้น
+func (h *Hub) enqueueBroadcast(msg *BroadcastMessage) {
 	select {
 	case h.broadcast <- msg:
 		log.Printf("Message type %s queued to broadcast channel", msg.Type)
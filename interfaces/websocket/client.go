@@ -131,11 +131,7 @@ func (c *Client) sendError(errorMsg string, requestID string) {
 	}
 
 	if data, err := json.Marshal(response); err == nil {
-		select {
-		case c.Send <- data:
-		default:
-			// Channel is full, close connection
-			close(c.Send)
-		}
+		// Channel is full: hub ตัดการเชื่อมต่อ (ปิด Send ที่ hub เพื่อไม่ให้ปิดซ้ำ)
+		c.Hub.deliver(c, data)
 	}
 }
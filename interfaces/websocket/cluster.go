@@ -0,0 +1,169 @@
+// interfaces/websocket/cluster.go
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// Redis channel ที่ทุก API instance ใช้ส่ง event ถึงกัน
+const clusterChannel = "ws:cluster"
+
+// Cluster envelope kinds
+const (
+	clusterKindBroadcast      = "broadcast"       // BroadcastMessage ไปยังผู้ใช้/การสนทนา
+	clusterKindUserStatus     = "user_status"     // online/offline ไปยัง client ที่ subscribe สถานะ
+	clusterKindSessionRevoked = "session_revoked" // ตัดการเชื่อมต่อของ session ที่ถูกเพิกถอน
+)
+
+// clusterEnvelope ข้อความที่ส่งระหว่าง node ผ่าน Redis pub/sub
+type clusterEnvelope struct {
+	Seq        int64           `json:"seq,omitempty"` // sequence ของ stream (appendEventScript เติมค่านี้ตอน publish)
+	Origin     string          `json:"origin"`
+	Kind       string          `json:"kind"`
+	Type       MessageType     `json:"type,omitempty"`
+	Data       json.RawMessage `json:"data,omitempty"`
+	UserIDs    []uuid.UUID     `json:"user_ids,omitempty"`
+	ConvID     *uuid.UUID      `json:"conv_id,omitempty"`
+	ExcludeID  *uuid.UUID      `json:"exclude_id,omitempty"`
+	Online     bool            `json:"online,omitempty"`
+	SessionIDs []uuid.UUID     `json:"session_ids,omitempty"`
+	Timestamp  time.Time       `json:"timestamp"`
+}
+
+// EnableCluster เปิดการกระจาย event ข้าม instance ผ่าน Redis pub/sub (ต้องเรียกก่อน Run)
+func (h *Hub) EnableCluster(redisClient *redis.Client, nodeID string) {
+	h.redis = redisClient
+	h.nodeID = nodeID
+	log.Printf("WebSocket Hub cluster mode enabled (node: %s)", nodeID)
+}
+
+// NodeID คืนค่า ID ของ instance นี้
+func (h *Hub) NodeID() string {
+	return h.nodeID
+}
+
+// publishCluster ส่ง envelope ไปยัง node อื่น (node ต้นทางจะไม่ประมวลผลซ้ำ)
+func (h *Hub) publishCluster(envelope *clusterEnvelope) {
+	if h.redis == nil {
+		return
+	}
+
+	envelope.Origin = h.nodeID
+	if envelope.Timestamp.IsZero() {
+		envelope.Timestamp = time.Now()
+	}
+
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		log.Printf("Error encoding cluster message: %v", err)
+		return
+	}
+
+	if err := h.redis.Publish(context.Background(), clusterChannel, payload).Err(); err != nil {
+		log.Printf("Error publishing cluster message type %s: %v", envelope.Type, err)
+	}
+}
+
+// publishBroadcast ส่ง BroadcastMessage ไปยัง node อื่น
+func (h *Hub) publishBroadcast(msg *BroadcastMessage) {
+	if h.redis == nil {
+		return
+	}
+
+	data, err := json.Marshal(msg.Data)
+	if err != nil {
+		log.Printf("Error encoding broadcast data type %s: %v", msg.Type, err)
+		return
+	}
+
+	h.publishCluster(&clusterEnvelope{
+		Kind:      clusterKindBroadcast,
+		Type:      msg.Type,
+		Data:      data,
+		UserIDs:   msg.UserIDs,
+		ConvID:    msg.ConvID,
+		ExcludeID: msg.ExcludeID,
+		Timestamp: msg.Timestamp,
+	})
+}
+
+// runClusterSubscriber รับ event จาก node อื่นแล้วส่งให้ client ที่เชื่อมต่อกับ node นี้
+func (h *Hub) runClusterSubscriber(ctx context.Context) {
+	pubsub := h.redis.Subscribe(ctx, clusterChannel)
+	defer pubsub.Close()
+
+	log.Printf("WebSocket Hub subscribed to cluster channel %s", clusterChannel)
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("WebSocket Hub cluster subscriber stopped")
+			return
+
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			h.handleClusterMessage([]byte(msg.Payload))
+		}
+	}
+}
+
+// handleClusterMessage ประมวลผล envelope จาก node อื่น
+func (h *Hub) handleClusterMessage(payload []byte) {
+	var envelope clusterEnvelope
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		log.Printf("Error decoding cluster message: %v", err)
+		return
+	}
+
+	// ข้ามข้อความที่ node นี้ส่งเอง (ส่งให้ client ในเครื่องไปแล้ว)
+	// ยกเว้น event ที่มี sequence ซึ่งทุก node ส่งให้ client ตามลำดับที่ได้รับจาก Redis
+	if envelope.Origin == h.nodeID && envelope.Seq == 0 {
+		return
+	}
+
+	switch envelope.Kind {
+	case clusterKindBroadcast:
+		h.enqueueBroadcast(envelope.broadcastMessage())
+
+	case clusterKindUserStatus:
+		if len(envelope.UserIDs) == 0 {
+			return
+		}
+		h.deliverUserStatus(envelope.UserIDs[0], envelope.Online, envelope.Data, envelope.Timestamp, nil)
+
+	case clusterKindSessionRevoked:
+		if len(envelope.UserIDs) == 0 {
+			return
+		}
+		h.disconnectSessions(envelope.UserIDs[0], envelope.SessionIDs)
+	}
+}
+
+// refreshPresence ต่ออายุสถานะออนไลน์ของผู้ใช้ที่ยังเชื่อมต่อกับ node นี้
+func (h *Hub) refreshPresence() {
+	if h.presenceService == nil {
+		return
+	}
+
+	h.userConnectionsMux.RLock()
+	userIDs := make([]uuid.UUID, 0, len(h.userConnections))
+	for userID, connections := range h.userConnections {
+		if len(connections) > 0 {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	h.userConnectionsMux.RUnlock()
+
+	if err := h.presenceService.RefreshOnline(userIDs); err != nil {
+		log.Printf("Error refreshing presence: %v", err)
+	}
+}
@@ -0,0 +1,395 @@
+// interfaces/websocket/event_log.go
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// Event sequence & replay log (เก็บใน Redis เพื่อให้ทุก instance ใช้ลำดับเดียวกัน)
+// event ของการสนทนาใช้ sequence ระดับการสนทนา (stream "conversation:<id>")
+// event ที่ส่งถึงผู้ใช้โดยตรงใช้ sequence ของผู้ใช้ (stream "user")
+const (
+	eventStreamUser               = "user"
+	eventStreamConversationPrefix = "conversation:"
+	eventLogMaxSize               = 1000 // เก็บ event ล่าสุดต่อ stream
+	eventLogTTL                   = 72 * time.Hour
+	replaySendTimeout             = 5 * time.Second
+	maxResumeConversations        = 200 // จำนวน stream ของการสนทนาที่ resume ได้ต่อครั้ง
+)
+
+// Resync reasons
+const (
+	resyncReasonGap       = "gap"        // event ที่ขาดไปถูกตัดออกจาก log แล้ว
+	resyncReasonExpired   = "expired"    // log หมดอายุ
+	resyncReasonAhead     = "ahead"      // last_seq มากกว่าของ server (เช่น Redis ถูกล้าง)
+	resyncReasonNotMember = "not_member" // ไม่ได้เป็นสมาชิกของการสนทนาแล้ว
+)
+
+// ephemeralEventTypes event ที่ไม่ต้องมี sequence และไม่ต้อง replay (สถานะชั่วคราว)
+var ephemeralEventTypes = map[MessageType]bool{
+	TypeMessageTyping: true,
+	TypeUserTyping:    true,
+	TypeTypingStart:   true,
+	TypeTypingStop:    true,
+	TypeUserOnline:    true,
+	TypeUserOffline:   true,
+	TypeUserStatus:    true,
+}
+
+// appendEventScript กำหนด sequence, บันทึก log และ publish ในขั้นตอนเดียว (atomic)
+// ลำดับ sequence จึงตรงกับลำดับที่ทุก node ได้รับจาก pub/sub เสมอ
+// KEYS[1] = seq key, KEYS[2] = log key
+// ARGV[1] = envelope JSON (ยังไม่มี seq), ARGV[2] = channel, ARGV[3] = ขนาด log, ARGV[4] = TTL (วินาที)
+var appendEventScript = redis.NewScript(`
+local seq = redis.call('INCR', KEYS[1])
+local envelope = '{"seq":' .. string.format('%d', seq) .. ',' .. string.sub(ARGV[1], 2)
+redis.call('ZADD', KEYS[2], seq, envelope)
+redis.call('ZREMRANGEBYRANK', KEYS[2], 0, -tonumber(ARGV[3]) - 1)
+redis.call('EXPIRE', KEYS[1], ARGV[4])
+redis.call('EXPIRE', KEYS[2], ARGV[4])
+redis.call('PUBLISH', ARGV[2], envelope)
+return seq
+`)
+
+// userEventScope / conversationEventScope ชื่อ stream ใน Redis (ใช้เป็น hash tag ให้ key ของ stream อยู่ slot เดียวกัน)
+func userEventScope(userID uuid.UUID) string {
+	return "user:" + userID.String()
+}
+
+func conversationEventScope(conversationID uuid.UUID) string {
+	return "conv:" + conversationID.String()
+}
+
+// eventStreamKeys คืนค่า key ของ sequence และ replay log ของ stream
+func eventStreamKeys(scope string) (seqKey, logKey string) {
+	return "ws:{" + scope + "}:seq", "ws:{" + scope + "}:events"
+}
+
+// publishSequenced กำหนด sequence ให้ event แล้วกระจายผ่าน Redis (node นี้ก็ส่งให้ client เมื่อได้รับจาก pub/sub)
+// event ของการสนทนาได้ sequence เดียวของการสนทนา ส่วน event ถึงผู้ใช้แยก stream ตามผู้รับ
+func (h *Hub) publishSequenced(msg *BroadcastMessage) {
+	data, err := json.Marshal(msg.Data)
+	if err != nil {
+		log.Printf("Error encoding broadcast data type %s: %v", msg.Type, err)
+		return
+	}
+
+	base := clusterEnvelope{
+		Origin:    h.nodeID,
+		Kind:      clusterKindBroadcast,
+		Type:      msg.Type,
+		Data:      data,
+		ExcludeID: msg.ExcludeID,
+		Timestamp: msg.Timestamp,
+	}
+
+	if msg.ConvID != nil {
+		envelope := base
+		envelope.ConvID = msg.ConvID
+		h.appendEvent(conversationEventScope(*msg.ConvID), &envelope)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(msg.UserIDs))
+	for _, userID := range msg.UserIDs {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		envelope := base
+		envelope.UserIDs = []uuid.UUID{userID}
+		h.appendEvent(userEventScope(userID), &envelope)
+	}
+}
+
+// appendEvent บันทึก event ลง stream และ publish (ถ้า Redis ใช้งานไม่ได้จะส่งแบบไม่มี sequence แทนการทิ้ง event)
+func (h *Hub) appendEvent(scope string, envelope *clusterEnvelope) {
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		log.Printf("Error encoding cluster message: %v", err)
+		return
+	}
+
+	seqKey, logKey := eventStreamKeys(scope)
+	err = appendEventScript.Run(context.Background(), h.redis,
+		[]string{seqKey, logKey},
+		string(payload), clusterChannel, eventLogMaxSize, int(eventLogTTL.Seconds()),
+	).Err()
+	if err == nil {
+		return
+	}
+
+	log.Printf("Error appending event type %s to stream %s: %v", envelope.Type, scope, err)
+	h.enqueueBroadcast(envelope.broadcastMessage())
+	h.publishCluster(envelope)
+}
+
+// broadcastMessage แปลง envelope กลับเป็น BroadcastMessage
+func (e *clusterEnvelope) broadcastMessage() *BroadcastMessage {
+	return &BroadcastMessage{
+		Type:      e.Type,
+		Data:      e.Data,
+		UserIDs:   e.UserIDs,
+		ConvID:    e.ConvID,
+		ExcludeID: e.ExcludeID,
+		Timestamp: e.Timestamp,
+		Seq:       e.Seq,
+	}
+}
+
+// encodeBroadcast สร้าง JSON ของ event (ผู้รับทุกคนได้ payload เดียวกัน)
+func encodeBroadcast(msg *BroadcastMessage) ([]byte, error) {
+	response := WSResponse{
+		Type:      msg.Type,
+		Data:      msg.Data,
+		Timestamp: msg.Timestamp,
+		Success:   true,
+		Seq:       msg.Seq,
+	}
+	if msg.Seq > 0 {
+		response.Stream = eventStreamUser
+		if msg.ConvID != nil {
+			response.Stream = eventStreamConversationPrefix + msg.ConvID.String()
+		}
+	}
+	return json.Marshal(response)
+}
+
+// currentSeq คืนค่า sequence ล่าสุดของ stream
+func (h *Hub) currentSeq(scope string) int64 {
+	if h.redis == nil {
+		return 0
+	}
+
+	seqKey, _ := eventStreamKeys(scope)
+	seq, err := h.redis.Get(context.Background(), seqKey).Int64()
+	if err != nil && err != redis.Nil {
+		log.Printf("Error getting event sequence for stream %s: %v", scope, err)
+	}
+	return seq
+}
+
+// conversationSeqs คืนค่า sequence ล่าสุดของ stream ของแต่ละการสนทนา (key = conversation ID) ในคำสั่งเดียว
+func (h *Hub) conversationSeqs(conversationIDs []uuid.UUID) map[string]int64 {
+	seqs := make(map[string]int64, len(conversationIDs))
+	for _, conversationID := range conversationIDs {
+		seqs[conversationID.String()] = 0
+	}
+	if h.redis == nil || len(conversationIDs) == 0 {
+		return seqs
+	}
+
+	keys := make([]string, 0, len(conversationIDs))
+	for _, conversationID := range conversationIDs {
+		seqKey, _ := eventStreamKeys(conversationEventScope(conversationID))
+		keys = append(keys, seqKey)
+	}
+
+	values, err := h.redis.MGet(context.Background(), keys...).Result()
+	if err != nil {
+		log.Printf("Error getting conversation event sequences: %v", err)
+		return seqs
+	}
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if seq, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			seqs[conversationIDs[i].String()] = seq
+		}
+	}
+	return seqs
+}
+
+// resumeClient ส่ง event ที่ client พลาดไปใน stream ของผู้ใช้และของการสนทนาที่ระบุ
+// event ที่ส่งสดระหว่าง replay อาจซ้ำ client ต้องข้าม event ที่ seq <= ค่าที่ประมวลผลแล้วของ stream นั้น
+func (h *Hub) resumeClient(client *Client, lastSeq int64, conversations map[uuid.UUID]int64) {
+	if !h.resumeStream(client, userEventScope(client.UserID), eventStreamUser, lastSeq) {
+		return
+	}
+
+	for conversationID, convLastSeq := range conversations {
+		stream := eventStreamConversationPrefix + conversationID.String()
+
+		isMember := false
+		if h.conversationService != nil {
+			var err error
+			isMember, err = h.conversationService.CheckMembership(client.UserID, conversationID)
+			if err != nil {
+				log.Printf("Error checking membership of user %s in %s: %v", client.UserID, conversationID, err)
+			}
+		}
+		if !isMember {
+			h.sendResync(client, stream, resyncReasonNotMember, convLastSeq, 0)
+			continue
+		}
+
+		if !h.resumeStream(client, conversationEventScope(conversationID), stream, convLastSeq) {
+			return
+		}
+	}
+}
+
+// resumeStream replay event หลัง lastSeq ของ stream หรือแจ้งให้ resync (คืนค่า false ถ้า client หลุดระหว่าง replay)
+func (h *Hub) resumeStream(client *Client, scope, stream string, lastSeq int64) bool {
+	if h.redis == nil {
+		h.sendResync(client, stream, resyncReasonExpired, lastSeq, 0)
+		return true
+	}
+
+	ctx := context.Background()
+	current := h.currentSeq(scope)
+	_, logKey := eventStreamKeys(scope)
+
+	if lastSeq > current {
+		h.sendResync(client, stream, resyncReasonAhead, lastSeq, current)
+		return true
+	}
+
+	replayed := 0
+	if lastSeq < current {
+		// ตรวจว่า event ถัดจาก lastSeq ยังอยู่ใน log
+		oldest, err := h.redis.ZRangeWithScores(ctx, logKey, 0, 0).Result()
+		if err != nil {
+			log.Printf("Error reading event log %s: %v", scope, err)
+			h.sendResync(client, stream, resyncReasonExpired, lastSeq, current)
+			return true
+		}
+		if len(oldest) == 0 {
+			h.sendResync(client, stream, resyncReasonExpired, lastSeq, current)
+			return true
+		}
+		if int64(oldest[0].Score) > lastSeq+1 {
+			h.sendResync(client, stream, resyncReasonGap, lastSeq, current)
+			return true
+		}
+
+		events, err := h.redis.ZRangeByScore(ctx, logKey, &redis.ZRangeBy{
+			Min: "(" + strconv.FormatInt(lastSeq, 10),
+			Max: "+inf",
+		}).Result()
+		if err != nil {
+			log.Printf("Error reading event log %s: %v", scope, err)
+			h.sendResync(client, stream, resyncReasonExpired, lastSeq, current)
+			return true
+		}
+
+		for _, event := range events {
+			var envelope clusterEnvelope
+			if err := json.Unmarshal([]byte(event), &envelope); err != nil {
+				log.Printf("Error decoding event log entry %s: %v", scope, err)
+				continue
+			}
+			data, err := encodeBroadcast(envelope.broadcastMessage())
+			if err != nil {
+				continue
+			}
+			if !h.sendReplay(client, data) {
+				log.Printf("Replay to client %s interrupted after %d events", client.ID, replayed)
+				return false
+			}
+			replayed++
+		}
+	}
+
+	log.Printf("Client %s resumed stream %s from seq %d (replayed %d events)", client.ID, stream, lastSeq, replayed)
+	h.sendToClient(client, WSResponse{
+		Type: TypeResumeOK,
+		Data: map[string]interface{}{
+			"stream":   stream,
+			"last_seq": lastSeq,
+			"seq":      current,
+			"replayed": replayed,
+		},
+		Timestamp: time.Now(),
+		Success:   true,
+	})
+	return true
+}
+
+// sendResync แจ้งให้ client โหลดข้อมูลของ stream ใหม่ทั้งหมด
+func (h *Hub) sendResync(client *Client, stream, reason string, lastSeq, current int64) {
+	log.Printf("Client %s must resync stream %s (reason: %s, last_seq: %d, seq: %d)", client.ID, stream, reason, lastSeq, current)
+	h.sendToClient(client, WSResponse{
+		Type: TypeResyncRequired,
+		Data: map[string]interface{}{
+			"stream":   stream,
+			"reason":   reason,
+			"last_seq": lastSeq,
+			"seq":      current,
+		},
+		Timestamp: time.Now(),
+		Success:   true,
+	})
+}
+
+// validateResumeConversations ตรวจ sequence ของการสนทนาที่ขอ resume
+func validateResumeConversations(conversations map[uuid.UUID]int64) error {
+	if len(conversations) > maxResumeConversations {
+		return fmt.Errorf("too many conversations to resume (max %d)", maxResumeConversations)
+	}
+	for _, seq := range conversations {
+		if seq < 0 {
+			return fmt.Errorf("last_seq must not be negative")
+		}
+	}
+	return nil
+}
+
+// parseResumeConversations แปลง conv_seq ของ handshake ("<conversation_id>:<seq>,...") เป็น sequence ของแต่ละการสนทนา
+func parseResumeConversations(raw string) (map[uuid.UUID]int64, error) {
+	conversations := make(map[uuid.UUID]int64)
+	for _, pair := range strings.Split(raw, ",") {
+		if pair == "" {
+			continue
+		}
+		idPart, seqPart, found := strings.Cut(pair, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid conv_seq entry %q", pair)
+		}
+		conversationID, err := uuid.Parse(idPart)
+		if err != nil {
+			return nil, fmt.Errorf("invalid conversation id %q", idPart)
+		}
+		seq, err := strconv.ParseInt(seqPart, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid last_seq for conversation %s", conversationID)
+		}
+		conversations[conversationID] = seq
+	}
+
+	if err := validateResumeConversations(conversations); err != nil {
+		return nil, err
+	}
+	return conversations, nil
+}
+
+// sendReplay ส่ง event ที่บันทึกไว้ให้ client โดยรอได้ถ้า Send buffer เต็ม (replay อาจมีหลายร้อยรายการ)
+func (h *Hub) sendReplay(client *Client, data []byte) (sent bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			// Send channel ถูกปิดแล้ว (client ตัดการเชื่อมต่อระหว่าง replay)
+			sent = false
+		}
+	}()
+
+	timer := time.NewTimer(replaySendTimeout)
+	defer timer.Stop()
+
+	select {
+	case client.Send <- data:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
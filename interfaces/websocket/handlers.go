@@ -36,6 +36,9 @@ func (h *Hub) registerHandlers() {
 
 	// Status handlers
 	h.handlers[string(TypePing)] = &PingHandler{hub: h}
+
+	// Resume handler (replay event ที่พลาดไปตาม last_seq)
+	h.handlers[string(TypeResume)] = &ResumeHandler{hub: h}
 }
 
 // MessageSendHandler handles sending messages
@@ -79,6 +82,13 @@ func (h *MessageSendHandler) Handle(ctx context.Context, client *Client, data js
 		return fmt.Errorf("user is not a member of this conversation")
 	}
 
+	// ช่อง (channel) โพสต์ได้เฉพาะ owner/admin
+	if h.hub.conversationMemberService != nil {
+		if err := h.hub.conversationMemberService.CheckPostPermission(msgData.ConversationID, client.UserID); err != nil {
+			return err
+		}
+	}
+
 	// Check block status before sending message
 	if h.hub.conversationMemberService != nil && h.hub.userFriendshipService != nil {
 		members, _, err := h.hub.conversationMemberService.GetMembers(client.UserID, msgData.ConversationID, 1, 1000)
@@ -158,6 +168,7 @@ type TypingStatus struct {
 	IsTyping       bool
 	StartTime      time.Time
 	StopTimer      *time.Timer
+	Token          string // token ของสถานะใน Redis (ใช้ตรวจว่า typing start ล่าสุดเป็นของ timer นี้หรือไม่)
 }
 
 // MessageTypingHandler handles typing indicators
@@ -178,14 +189,9 @@ func (h *MessageTypingHandler) Handle(ctx context.Context, client *Client, data
 
 	// Rate limiting: Max 1 event per second (only for typing start)
 	key := fmt.Sprintf("%s:%s", typingData.ConversationID.String(), client.UserID.String())
-	if typingData.IsTyping {
-		if lastTime, exists := lastTypingUpdate.Load(key); exists {
-			if time.Since(lastTime.(time.Time)) < 1*time.Second {
-				// Ignore - rate limited
-				return nil
-			}
-		}
-		lastTypingUpdate.Store(key, time.Now())
+	if typingData.IsTyping && !h.hub.allowTypingUpdate(key) {
+		// Ignore - rate limited
+		return nil
 	}
 
 	// Process typing with auto-stop logic
@@ -207,9 +213,12 @@ func (h *MessageTypingHandler) processTypingWithAutoStop(userID, conversationID
 			}
 		}
 
+		// บันทึกสถานะใน Redis เพื่อให้ instance อื่นรู้ว่ายังพิมพ์อยู่
+		token := h.hub.acquireTyping(key)
+
 		// Create auto-stop timer (5 seconds)
 		timer := time.AfterFunc(5*time.Second, func() {
-			h.autoStopTyping(userID, conversationID)
+			h.autoStopTyping(userID, conversationID, token)
 		})
 
 		// Store in cache
@@ -219,6 +228,7 @@ func (h *MessageTypingHandler) processTypingWithAutoStop(userID, conversationID
 			IsTyping:       true,
 			StartTime:      time.Now(),
 			StopTimer:      timer,
+			Token:          token,
 		})
 
 		// Broadcast typing start
@@ -232,6 +242,7 @@ func (h *MessageTypingHandler) processTypingWithAutoStop(userID, conversationID
 			}
 			typingCache.Delete(key)
 		}
+		h.hub.releaseTyping(key, "")
 
 		// Broadcast typing stop
 		h.broadcastTyping(userID, conversationID, false)
@@ -239,16 +250,20 @@ func (h *MessageTypingHandler) processTypingWithAutoStop(userID, conversationID
 }
 
 // autoStopTyping is called by timer after 5 seconds
-func (h *MessageTypingHandler) autoStopTyping(userID, conversationID uuid.UUID) {
+func (h *MessageTypingHandler) autoStopTyping(userID, conversationID uuid.UUID, token string) {
 	key := fmt.Sprintf("%s:%s", conversationID.String(), userID.String())
 
-	// Remove from cache
+	// Remove from cache (เฉพาะเมื่อยังเป็น typing start เดียวกับ timer นี้)
 	if val, exists := typingCache.Load(key); exists {
 		status := val.(*TypingStatus)
-		if status.StopTimer != nil {
-			status.StopTimer.Stop()
+		if status.Token == token {
+			typingCache.Delete(key)
 		}
-		typingCache.Delete(key)
+	}
+
+	// ผู้ใช้เริ่มพิมพ์ใหม่จากอุปกรณ์/instance อื่น ให้ timer ของฝั่งนั้นเป็นผู้หยุด
+	if !h.hub.releaseTyping(key, token) {
+		return
 	}
 
 	// Broadcast typing stop
@@ -358,6 +373,7 @@ func (h *TypingStopHandler) ValidateData(data json.RawMessage) error {
 
 // StartTypingCacheCleanup starts a background routine to cleanup stale typing cache
 // Call this once on application startup
+// (cache นี้เก็บเฉพาะ timer ของ instance นี้ สถานะที่แชร์ใน Redis หมดอายุเองตาม typingStateTTL)
 func StartTypingCacheCleanup() {
 	go func() {
 		ticker := time.NewTicker(1 * time.Minute)
@@ -377,6 +393,7 @@ func StartTypingCacheCleanup() {
 						status.StopTimer.Stop()
 					}
 					typingCache.Delete(key)
+					lastTypingUpdate.Delete(key)
 					cleanedCount++
 					log.Printf("Cleaned up stale typing cache: %v", key)
 				}
@@ -1107,3 +1124,34 @@ func (h *UnsubscribeUserStatusHandler) ValidateData(data json.RawMessage) error
 	}
 	return json.Unmarshal(data, &req)
 }
+
+// ResumeHandler handles resume requests after reconnect
+type ResumeHandler struct {
+	hub *Hub
+}
+
+type ResumeData struct {
+	LastSeq       int64               `json:"last_seq"`                // sequence ล่าสุดของ stream "user"
+	Conversations map[uuid.UUID]int64 `json:"conversations,omitempty"` // sequence ล่าสุดของแต่ละการสนทนา
+}
+
+func (h *ResumeHandler) Handle(ctx context.Context, client *Client, data json.RawMessage) error {
+	var req ResumeData
+	if err := json.Unmarshal(data, &req); err != nil {
+		return err
+	}
+	if req.LastSeq < 0 {
+		return fmt.Errorf("last_seq must not be negative")
+	}
+	if err := validateResumeConversations(req.Conversations); err != nil {
+		return err
+	}
+
+	go h.hub.resumeClient(client, req.LastSeq, req.Conversations)
+	return nil
+}
+
+func (h *ResumeHandler) ValidateData(data json.RawMessage) error {
+	var req ResumeData
+	return json.Unmarshal(data, &req)
+}
@@ -8,8 +8,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/go-redis/redis/v8"
 	"github.com/gofiber/contrib/websocket"
 	"github.com/google/uuid"
+	"github.com/thizplus/gofiber-chat-api/domain/dto"
 	"github.com/thizplus/gofiber-chat-api/domain/repository"
 	"github.com/thizplus/gofiber-chat-api/domain/service"
 )
@@ -45,6 +47,10 @@ type Hub struct {
 	presenceService           service.PresenceService
 	userRepo                  repository.UserRepository // 🆕 เพิ่มสำหรับ typing user info
 
+	// Cluster fan-out (Redis pub/sub) - nil = single instance
+	redis  *redis.Client
+	nodeID string
+
 	// Channels
 	register   chan *Client
 	unregister chan *Client
@@ -103,6 +109,9 @@ type Client struct {
 	IsAlive              bool
 	LastPingTime         time.Time
 	RateLimiter          *RateLimiter
+	ResumeFromSeq        *int64              // last_seq จาก handshake (nil = การเชื่อมต่อใหม่)
+	ResumeConversations  map[uuid.UUID]int64 // conv_seq จาก handshake (sequence ล่าสุดของแต่ละการสนทนา)
+	SessionID            *uuid.UUID          // session (refresh token) ของ access token ที่ใช้เชื่อมต่อ
 	messageCount         int
 	lastReset            time.Time
 }
@@ -117,6 +126,14 @@ const (
 	TypePing       MessageType = "ping"
 	TypePong       MessageType = "pong"
 
+	// Session ถูกเพิกถอน (ออกจากระบบจากอุปกรณ์อื่น) - server จะปิดการเชื่อมต่อหลังส่ง event นี้
+	TypeSessionRevoked MessageType = "session.revoked"
+
+	// Resume after reconnect (replay event ตาม sequence)
+	TypeResume         MessageType = "resume"
+	TypeResumeOK       MessageType = "resume.ok"
+	TypeResyncRequired MessageType = "resync.required"
+
 	// Chat messages
 	TypeMessageSend      MessageType = "message.send"
 	TypeMessageReceive   MessageType = "message.receive"
@@ -185,6 +202,8 @@ type WSResponse struct {
 	RequestID string      `json:"request_id,omitempty"`
 	Success   bool        `json:"success"`
 	Error     string      `json:"error,omitempty"`
+	Seq       int64       `json:"seq,omitempty"`    // sequence ภายใน stream (ใช้ resume หลัง reconnect)
+	Stream    string      `json:"stream,omitempty"` // "user" หรือ "conversation:<id>"
 }
 
 // BroadcastMessage for sending messages to multiple clients
@@ -195,6 +214,8 @@ type BroadcastMessage struct {
 	BusinessID *uuid.UUID
 	ConvID     *uuid.UUID
 	ExcludeID  *uuid.UUID // Exclude specific client
+	Timestamp  time.Time
+	Seq        int64 // sequence ของ stream (0 สำหรับ event ชั่วคราว)
 }
 
 // MessageHandler interface for handling different message types
@@ -246,6 +267,11 @@ func (h *Hub) Run(ctx context.Context) {
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
 
+	// รับ event จาก instance อื่น
+	if h.redis != nil {
+		go h.runClusterSubscriber(ctx)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -267,6 +293,7 @@ func (h *Hub) Run(ctx context.Context) {
 		case <-ticker.C:
 			log.Println("WebSocket Hub: Checking alive clients")
 			h.checkAliveClients()
+			go h.refreshPresence()
 		}
 	}
 }
@@ -294,6 +321,7 @@ func (h *Hub) GetStats() map[string]interface{} {
 	connDistribution := h.getConnectionDistribution()
 
 	return map[string]interface{}{
+		"node_id":                 h.nodeID,
 		"total_connections":       totalClients,
 		"unique_users":            totalUsers,
 		"active_conversations":    totalConversations,
@@ -344,16 +372,14 @@ func (h *Hub) registerClient(client *Client) {
 	h.userConnections[client.UserID] = append(h.userConnections[client.UserID], client.ID)
 	h.userConnectionsMux.Unlock()
 
-	// Load conversations based on connection type
-	if h.conversationService != nil {
-		// Regular user connection - load user conversations
-		go h.loadUserConversations(client)
-	} else {
-		log.Println("Warning: ConversationService is nil, skipping conversation loading")
-	}
+	// Load conversations, ส่ง welcome message แล้ว resume (ถ้ามี last_seq)
+	go h.connectClient(client)
 
 	// แจ้งเตือนสถานะออนไลน์ให้กับผู้ที่ subscribe
 	if isFirstConnection {
+		// ผู้ใช้อาจออนไลน์อยู่แล้วผ่าน instance อื่น (ไม่ต้องแจ้งซ้ำ)
+		wasOnline := h.isUserOnlineElsewhere(client.UserID)
+
 		// Update presence in Redis and Database
 		if h.presenceService != nil {
 			if err := h.presenceService.SetUserOnline(client.UserID); err != nil {
@@ -369,35 +395,9 @@ func (h *Hub) registerClient(client *Client) {
 			"timestamp": now.Format(time.RFC3339),
 		}
 
-		// 1. แจ้งไปยังผู้ใช้ทุกคนที่ subscribe สถานะของผู้ใช้นี้
-		h.userStatusSubsMux.RLock()
-		subscriberIDs := h.userStatusSubs[client.UserID]
-		h.userStatusSubsMux.RUnlock()
-
-		for _, subClientID := range subscriberIDs {
-			h.clientsMux.RLock()
-			subClient, ok := h.clients[subClientID]
-			h.clientsMux.RUnlock()
-
-			if ok && subClientID != client.ID {
-				log.Printf("Notifying client %s that user %s is online", subClientID, client.UserID)
-
-				// ส่ง event แบบเก่า (backward compatible)
-				h.sendToClient(subClient, WSResponse{
-					Type:      TypeUserOnline,  // "user.online"
-					Data:      statusData,
-					Timestamp: now,
-					Success:   true,
-				})
-
-				// ส่ง event แบบใหม่ (ตาม spec)
-				h.sendToClient(subClient, WSResponse{
-					Type:      TypeUserStatus,  // "user.status"
-					Data:      statusData,
-					Timestamp: now,
-					Success:   true,
-				})
-			}
+		// 1. แจ้งไปยังผู้ใช้ทุกคนที่ subscribe สถานะของผู้ใช้นี้ (ทุก instance)
+		if !wasOnline {
+			h.notifyUserStatus(client.UserID, true, statusData, now, &client.ID)
 		}
 
 		// 2. แจ้งสถานะของตัวเองกลับไปที่ client เพื่อให้รู้ว่าเชื่อมต่อสำเร็จ
@@ -415,56 +415,87 @@ func (h *Hub) registerClient(client *Client) {
 		// รอให้ client ได้ subscribe ผู้ใช้อื่นก่อน (รอการเรียก loadUserConversations)
 		time.Sleep(1 * time.Second)
 
-		// ดึงรายการผู้ใช้ทั้งหมดที่ออนไลน์
-		h.userConnectionsMux.RLock()
-		onlineUsers := make([]uuid.UUID, 0, len(h.userConnections))
-		for userID, connections := range h.userConnections {
-			if len(connections) > 0 && userID != client.UserID {
-				onlineUsers = append(onlineUsers, userID)
+		// ดึงรายการผู้ใช้ที่ client นี้ subscribe สถานะไว้
+		h.userStatusSubsMux.RLock()
+		subscribedUsers := make([]uuid.UUID, 0)
+		for userID, subClientIDs := range h.userStatusSubs {
+			if userID == client.UserID {
+				continue
 			}
-		}
-		h.userConnectionsMux.RUnlock()
-
-		// ส่งสถานะของผู้ใช้ที่ออนไลน์ทั้งหมดให้กับ client
-		for _, onlineUserID := range onlineUsers {
-			// ตรวจสอบว่า client นี้ subscribe ผู้ใช้คนนี้หรือไม่
-			h.userStatusSubsMux.RLock()
-			isSubscribed := false
-			for _, subClientID := range h.userStatusSubs[onlineUserID] {
+			for _, subClientID := range subClientIDs {
 				if subClientID == client.ID {
-					isSubscribed = true
+					subscribedUsers = append(subscribedUsers, userID)
 					break
 				}
 			}
-			h.userStatusSubsMux.RUnlock()
-
-			// ถ้า subscribe ไว้ ส่งสถานะออนไลน์ไปให้
-			if isSubscribed {
-				log.Printf("Sending online status of user %s to new client %s", onlineUserID, client.ID)
-				h.sendToClient(client, WSResponse{
-					Type: TypeUserOnline,
-					Data: map[string]interface{}{
-						"user_id":   onlineUserID,
-						"online":    true,
-						"timestamp": time.Now(),
-					},
-					Timestamp: time.Now(),
-					Success:   true,
-				})
+		}
+		h.userStatusSubsMux.RUnlock()
+
+		// ส่งสถานะของผู้ใช้ที่ออนไลน์ (บน instance ใดก็ได้) ให้กับ client
+		for _, onlineUserID := range subscribedUsers {
+			if !h.isUserOnline(onlineUserID) {
+				continue
 			}
+
+			log.Printf("Sending online status of user %s to new client %s", onlineUserID, client.ID)
+			h.sendToClient(client, WSResponse{
+				Type: TypeUserOnline,
+				Data: map[string]interface{}{
+					"user_id":   onlineUserID,
+					"online":    true,
+					"timestamp": time.Now(),
+				},
+				Timestamp: time.Now(),
+				Success:   true,
+			})
 		}
 	}()
 
+}
+
+// connectClient subscribe การสนทนาของผู้ใช้ ส่ง welcome message พร้อม sequence ล่าสุดของทุก stream แล้ว replay ถ้าเป็นการ resume
+// subscribe ก่อนอ่าน sequence: event หลังค่าที่ส่งใน welcome จึงมาถึงสดเสมอ ส่วนที่พลาดไปก่อนหน้านั้นได้จาก replay
+func (h *Hub) connectClient(client *Client) {
+	var conversations []*dto.ConversationDTO
+	loaded := false
+	if h.conversationService != nil {
+		// Regular user connection - load user conversations
+		var ok bool
+		conversations, ok = h.loadUserConversations(client)
+		if !ok {
+			return
+		}
+		loaded = true
+	} else {
+		log.Println("Warning: ConversationService is nil, skipping conversation loading")
+	}
+
+	conversationIDs := make([]uuid.UUID, 0, len(conversations))
+	for _, conv := range conversations {
+		conversationIDs = append(conversationIDs, conv.ID)
+	}
+
 	// Send welcome message
 	h.sendToClient(client, WSResponse{
 		Type: TypeConnect,
 		Data: map[string]interface{}{
-			"message":   "Connected successfully",
-			"client_id": client.ID.String(), // ส่ง client_id ไปด้วย
+			"message":       "Connected successfully",
+			"client_id":     client.ID.String(),                          // ส่ง client_id ไปด้วย
+			"seq":           h.currentSeq(userEventScope(client.UserID)), // sequence ล่าสุดของ stream "user" ใช้เป็น last_seq ตอน resume
+			"conversations": h.conversationSeqs(conversationIDs),         // sequence ล่าสุดของแต่ละการสนทนา ใช้เป็น conv_seq ตอน resume
 		},
 		Timestamp: time.Now(),
 		Success:   true,
 	})
+
+	if loaded {
+		h.sendConversationList(client, conversations)
+	}
+
+	// Resume handshake: replay event ที่พลาดไประหว่างหลุดการเชื่อมต่อ (stream "user" และการสนทนาใน conv_seq)
+	if client.ResumeFromSeq != nil {
+		h.resumeClient(client, *client.ResumeFromSeq, client.ResumeConversations)
+	}
 }
 
 // ปรับปรุง unregisterClient ใน hub.go
@@ -509,6 +540,11 @@ func (h *Hub) unregisterClient(client *Client) {
 			}
 		}
 
+		// ยังเชื่อมต่ออยู่ผ่าน instance อื่น ไม่ต้องแจ้งว่าออฟไลน์
+		if h.isUserOnlineElsewhere(userID) {
+			return
+		}
+
 		now := time.Now()
 		statusData := map[string]interface{}{
 			"user_id":   userID.String(),
@@ -518,45 +554,77 @@ func (h *Hub) unregisterClient(client *Client) {
 			"timestamp": now.Format(time.RFC3339),
 		}
 
-		// แจ้งไปยังผู้ใช้ทุกคนที่ subscribe สถานะของผู้ใช้นี้
-		h.userStatusSubsMux.RLock()
-		subscriberIDs := h.userStatusSubs[userID]
-		h.userStatusSubsMux.RUnlock()
+		// แจ้งไปยังผู้ใช้ทุกคนที่ subscribe สถานะของผู้ใช้นี้ (ทุก instance)
+		h.notifyUserStatus(userID, false, statusData, now, nil)
+	}
+}
 
-		for _, subClientID := range subscriberIDs {
-			h.clientsMux.RLock()
-			subClient, ok := h.clients[subClientID]
-			h.clientsMux.RUnlock()
-
-			if ok {
-				log.Printf("Notifying client %s that user %s is offline", subClientID, userID)
-
-				// ส่ง event แบบเก่า (backward compatible)
-				h.sendToClient(subClient, WSResponse{
-					Type:      TypeUserOffline,  // "user.offline"
-					Data:      statusData,
-					Timestamp: now,
-					Success:   true,
-				})
-
-				// ส่ง event แบบใหม่ (ตาม spec)
-				h.sendToClient(subClient, WSResponse{
-					Type:      TypeUserStatus,  // "user.status"
-					Data:      statusData,
-					Timestamp: now,
-					Success:   true,
-				})
-			}
+// notifyUserStatus แจ้งสถานะออนไลน์/ออฟไลน์ให้ client ในเครื่องและ instance อื่น
+func (h *Hub) notifyUserStatus(userID uuid.UUID, online bool, statusData map[string]interface{}, now time.Time, excludeClientID *uuid.UUID) {
+	h.deliverUserStatus(userID, online, statusData, now, excludeClientID)
+
+	if h.redis != nil {
+		data, err := json.Marshal(statusData)
+		if err != nil {
+			return
 		}
+		h.publishCluster(&clusterEnvelope{
+			Kind:    clusterKindUserStatus,
+			UserIDs: []uuid.UUID{userID},
+			Data:    data,
+			Online:  online,
+		})
 	}
 }
 
-// loadUserConversations loads and subscribes to user's conversations
-func (h *Hub) loadUserConversations(client *Client) {
+// deliverUserStatus ส่งสถานะไปยัง client ในเครื่องที่ subscribe สถานะของผู้ใช้นี้
+func (h *Hub) deliverUserStatus(userID uuid.UUID, online bool, statusData interface{}, now time.Time, excludeClientID *uuid.UUID) {
+	legacyType := TypeUserOffline
+	if online {
+		legacyType = TypeUserOnline
+	}
+
+	h.userStatusSubsMux.RLock()
+	subscriberIDs := append([]uuid.UUID(nil), h.userStatusSubs[userID]...)
+	h.userStatusSubsMux.RUnlock()
+
+	for _, subClientID := range subscriberIDs {
+		if excludeClientID != nil && subClientID == *excludeClientID {
+			continue
+		}
+
+		h.clientsMux.RLock()
+		subClient, ok := h.clients[subClientID]
+		h.clientsMux.RUnlock()
+
+		if ok {
+			log.Printf("Notifying client %s that user %s is online=%v", subClientID, userID, online)
+
+			// ส่ง event แบบเก่า (backward compatible)
+			h.sendToClient(subClient, WSResponse{
+				Type:      legacyType, // "user.online" / "user.offline"
+				Data:      statusData,
+				Timestamp: now,
+				Success:   true,
+			})
+
+			// ส่ง event แบบใหม่ (ตาม spec)
+			h.sendToClient(subClient, WSResponse{
+				Type:      TypeUserStatus, // "user.status"
+				Data:      statusData,
+				Timestamp: now,
+				Success:   true,
+			})
+		}
+	}
+}
+
+// loadUserConversations loads and subscribes to user's conversations (คืนค่า false ถ้าโหลดไม่ได้หรือ client หลุดไปแล้ว)
+func (h *Hub) loadUserConversations(client *Client) ([]*dto.ConversationDTO, bool) {
 	// Check if service is available
 	if h.conversationService == nil {
 		log.Println("Error: ConversationService is nil in loadUserConversations")
-		return
+		return nil, false
 	}
 
 	// Get user's conversations - ใช้พารามิเตอร์ที่ถูกต้อง
@@ -565,7 +633,7 @@ func (h *Hub) loadUserConversations(client *Client) {
 	)
 	if err != nil {
 		log.Printf("Error loading conversations for user %s: %v", client.UserID, err)
-		return
+		return nil, false
 	}
 
 	// Check if client still exists before subscribing
@@ -575,7 +643,7 @@ func (h *Hub) loadUserConversations(client *Client) {
 
 	if !exists {
 		log.Printf("Client %s disconnected before conversations loaded, skipping", client.ID)
-		return
+		return nil, false
 	}
 
 	// Subscribe to each conversation
@@ -592,9 +660,14 @@ func (h *Hub) loadUserConversations(client *Client) {
 
 	if !exists {
 		log.Printf("Client %s disconnected before sending conversations, skipping", client.ID)
-		return
+		return nil, false
 	}
 
+	return conversations, true
+}
+
+// sendConversationList ส่งรายการการสนทนาที่ subscribe แล้วให้ client
+func (h *Hub) sendConversationList(client *Client, conversations []*dto.ConversationDTO) {
 	// สร้างรายการการสนทนาเพื่อส่งให้ client
 	conversationList := make([]map[string]interface{}, len(conversations))
 	for i, conv := range conversations {
@@ -869,13 +942,32 @@ func (h *Hub) unsubscribeFromUserStatus(clientID, targetUserID uuid.UUID) {
 }
 
 // ปรับปรุง isUserOnline ใน hub.go - เพิ่ม logging
+// ตรวจ connection ในเครื่องก่อน แล้วจึงถาม PresenceService (ครอบคลุมทุก instance)
 func (h *Hub) isUserOnline(userID uuid.UUID) bool {
 	h.userConnectionsMux.RLock()
-	defer h.userConnectionsMux.RUnlock()
-
 	connections, exists := h.userConnections[userID]
 	isOnline := exists && len(connections) > 0
-	log.Printf("Checking if user %s is online: %v (connections: %d)",
+	h.userConnectionsMux.RUnlock()
+
+	if !isOnline {
+		isOnline = h.isUserOnlineElsewhere(userID)
+	}
+
+	log.Printf("Checking if user %s is online: %v (local connections: %d)",
 		userID, isOnline, len(connections))
 	return isOnline
 }
+
+// isUserOnlineElsewhere ตรวจสถานะออนไลน์จาก PresenceService (Redis) ซึ่งรวมทุก instance
+func (h *Hub) isUserOnlineElsewhere(userID uuid.UUID) bool {
+	if h.presenceService == nil {
+		return false
+	}
+
+	online, err := h.presenceService.IsUserOnline(userID)
+	if err != nil {
+		log.Printf("Error checking presence for user %s: %v", userID, err)
+		return false
+	}
+	return online
+}
@@ -3,6 +3,7 @@ package websocket
 
 import (
 	"log"
+	"strconv"
 	"time"
 
 	"github.com/gofiber/contrib/websocket"
@@ -57,7 +58,7 @@ func RegisterWebSocketRoutes(app *fiber.App, hub *Hub, authHandler fiber.Handler
 		}
 
 		// Validate token
-		userUUID, err := middleware.ValidateTokenStringToUUID(token)
+		userUUID, sessionID, err := middleware.ValidateAccessToken(token)
 		if err != nil {
 			log.Printf("Token validation error: %v", err)
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -71,6 +72,33 @@ func RegisterWebSocketRoutes(app *fiber.App, hub *Hub, authHandler fiber.Handler
 		// Store user info in locals
 		c.Locals("userID", userUUID.String())
 		c.Locals("userUUID", userUUID)
+		if sessionID != nil {
+			c.Locals("sessionID", *sessionID)
+		}
+
+		// Resume handshake: last_seq ที่ client ประมวลผลล่าสุดก่อนหลุดการเชื่อมต่อ
+		if lastSeqParam := c.Query("last_seq"); lastSeqParam != "" {
+			lastSeq, err := strconv.ParseInt(lastSeqParam, 10, 64)
+			if err != nil || lastSeq < 0 {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   true,
+					"message": "Invalid last_seq",
+				})
+			}
+			c.Locals("lastSeq", lastSeq)
+
+			// conv_seq: sequence ล่าสุดของแต่ละการสนทนา ("<conversation_id>:<seq>,...") เพื่อ replay event ของการสนทนาด้วย
+			if convSeqParam := c.Query("conv_seq"); convSeqParam != "" {
+				conversations, err := parseResumeConversations(convSeqParam)
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   true,
+						"message": "Invalid conv_seq: " + err.Error(),
+					})
+				}
+				c.Locals("resumeConversations", conversations)
+			}
+		}
 
 		return c.Next()
 	}, websocket.New(func(c *websocket.Conn) {
@@ -100,6 +128,13 @@ func RegisterWebSocketRoutes(app *fiber.App, hub *Hub, authHandler fiber.Handler
 			messageCount: 0,
 			lastReset:    time.Now(),
 		}
+		if lastSeq, ok := c.Locals("lastSeq").(int64); ok {
+			client.ResumeFromSeq = &lastSeq
+			client.ResumeConversations, _ = c.Locals("resumeConversations").(map[uuid.UUID]int64)
+		}
+		if sessionID, ok := c.Locals("sessionID").(uuid.UUID); ok {
+			client.SessionID = &sessionID
+		}
 
 		log.Printf("Registering client %s for user %s", client.ID, userUUID.String())
 		hub.register <- client
@@ -0,0 +1,61 @@
+// interfaces/websocket/sessions.go
+package websocket
+
+import (
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DisconnectSessions ปิดการเชื่อมต่อของ session ที่ถูกเพิกถอน ทั้งใน instance นี้และ instance อื่น
+func (h *Hub) DisconnectSessions(userID uuid.UUID, sessionIDs []uuid.UUID) {
+	if len(sessionIDs) == 0 {
+		return
+	}
+
+	h.disconnectSessions(userID, sessionIDs)
+
+	h.publishCluster(&clusterEnvelope{
+		Kind:       clusterKindSessionRevoked,
+		UserIDs:    []uuid.UUID{userID},
+		SessionIDs: sessionIDs,
+	})
+}
+
+// disconnectSessions ส่ง session.revoked แล้วตัดการเชื่อมต่อของ client ในเครื่องที่ใช้ session เหล่านี้
+func (h *Hub) disconnectSessions(userID uuid.UUID, sessionIDs []uuid.UUID) {
+	revoked := make(map[uuid.UUID]bool, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		revoked[sessionID] = true
+	}
+
+	h.userConnectionsMux.RLock()
+	clientIDs := append([]uuid.UUID(nil), h.userConnections[userID]...)
+	h.userConnectionsMux.RUnlock()
+
+	h.clientsMux.RLock()
+	targets := make([]*Client, 0, len(clientIDs))
+	for _, clientID := range clientIDs {
+		client, ok := h.clients[clientID]
+		if ok && client.SessionID != nil && revoked[*client.SessionID] {
+			targets = append(targets, client)
+		}
+	}
+	h.clientsMux.RUnlock()
+
+	for _, client := range targets {
+		log.Printf("Disconnecting client %s of revoked session %s", client.ID, client.SessionID)
+
+		// event จะถูกส่งก่อน close frame เพราะ WritePump อ่าน Send จนหมดก่อนปิด
+		h.sendToClient(client, WSResponse{
+			Type: TypeSessionRevoked,
+			Data: map[string]interface{}{
+				"session_id": client.SessionID.String(),
+			},
+			Timestamp: time.Now(),
+			Success:   true,
+		})
+		h.unregister <- client
+	}
+}
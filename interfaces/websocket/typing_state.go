@@ -0,0 +1,90 @@
+// interfaces/websocket/typing_state.go
+package websocket
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// สถานะ typing ที่แชร์ระหว่าง instance (ใช้เมื่อเปิด cluster mode)
+const (
+	typingKeyPrefix          = "ws:typing:"    // ws:typing:<conv_id>:<user_id> -> token ของ start ล่าสุด
+	typingRateLimitKeyPrefix = "ws:typing:rl:" // rate limit ของ typing start
+	typingStateTTL           = 6 * time.Second // นานกว่า auto-stop timer (5 วินาที) เล็กน้อย
+	typingRateLimitInterval  = 1 * time.Second
+)
+
+// releaseTypingScript ลบสถานะ typing เฉพาะเมื่อ token ยังเป็นของผู้เรียก
+// (ถ้าอุปกรณ์/instance อื่นเริ่มพิมพ์ใหม่แล้ว จะไม่ลบและไม่ส่ง typing stop)
+var releaseTypingScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// allowTypingUpdate จำกัด typing start ไม่เกิน 1 ครั้งต่อวินาทีต่อผู้ใช้ต่อการสนทนา (ทุก instance)
+func (h *Hub) allowTypingUpdate(key string) bool {
+	if h.redis == nil {
+		if lastTime, exists := lastTypingUpdate.Load(key); exists {
+			if time.Since(lastTime.(time.Time)) < typingRateLimitInterval {
+				return false
+			}
+		}
+		lastTypingUpdate.Store(key, time.Now())
+		return true
+	}
+
+	allowed, err := h.redis.SetNX(context.Background(), typingRateLimitKeyPrefix+key, h.nodeID, typingRateLimitInterval).Result()
+	if err != nil {
+		log.Printf("Error checking typing rate limit: %v", err)
+		return true
+	}
+	return allowed
+}
+
+// acquireTyping บันทึกว่าผู้ใช้กำลังพิมพ์ คืนค่า token สำหรับ auto-stop
+func (h *Hub) acquireTyping(key string) string {
+	token := uuid.NewString()
+	if h.redis == nil {
+		return token
+	}
+
+	if err := h.redis.Set(context.Background(), typingKeyPrefix+key, token, typingStateTTL).Err(); err != nil {
+		log.Printf("Error storing typing state: %v", err)
+	}
+	return token
+}
+
+// releaseTyping ล้างสถานะ typing คืนค่า false ถ้า token ถูกแทนที่โดย typing start ที่ใหม่กว่า
+// token ว่าง = หยุดพิมพ์โดยผู้ใช้ (ลบเสมอ)
+func (h *Hub) releaseTyping(key, token string) bool {
+	if h.redis == nil {
+		return true
+	}
+
+	ctx := context.Background()
+	if token == "" {
+		if err := h.redis.Del(ctx, typingKeyPrefix+key).Err(); err != nil {
+			log.Printf("Error clearing typing state: %v", err)
+		}
+		return true
+	}
+
+	released, err := releaseTypingScript.Run(ctx, h.redis, []string{typingKeyPrefix + key}, token).Int()
+	if err != nil {
+		log.Printf("Error releasing typing state: %v", err)
+		return true
+	}
+	if released == 1 {
+		return true
+	}
+
+	// key หมดอายุไปแล้ว (ไม่มีใครพิมพ์ต่อ) ถือว่าหยุดได้
+	exists, err := h.redis.Exists(ctx, typingKeyPrefix+key).Result()
+	return err != nil || exists == 0
+}
@@ -0,0 +1,21 @@
+-- migrations/015_create_message_reactions_table.sql
+-- Create message_reactions table for emoji reactions on messages
+
+CREATE TABLE IF NOT EXISTS message_reactions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    message_id UUID NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    emoji VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    -- Unique Constraint: a user can react with the same emoji only once per message
+    CONSTRAINT unique_message_reaction_user_emoji UNIQUE (message_id, user_id, emoji)
+);
+
+-- Create Indexes for better query performance
+CREATE INDEX IF NOT EXISTS idx_message_reactions_message_id ON message_reactions(message_id);
+CREATE INDEX IF NOT EXISTS idx_message_reactions_user_id ON message_reactions(user_id);
+
+-- Add comments for documentation
+COMMENT ON TABLE message_reactions IS 'Stores emoji reactions on messages';
+COMMENT ON COLUMN message_reactions.emoji IS 'Emoji used for the reaction (unicode or short code)';
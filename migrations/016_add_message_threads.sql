@@ -0,0 +1,29 @@
+-- migrations/016_add_message_threads.sql
+-- Add thread support: thread replies live outside the main conversation timeline
+
+-- Thread columns on messages
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS thread_root_id UUID REFERENCES messages(id) ON DELETE CASCADE;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS thread_reply_count INTEGER DEFAULT 0;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS thread_last_reply_id UUID;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS thread_last_reply_at TIMESTAMP WITH TIME ZONE;
+
+-- Index for loading thread replies with composite cursor (created_at + id)
+CREATE INDEX IF NOT EXISTS idx_messages_thread_root_created ON messages(thread_root_id, created_at, id)
+    WHERE thread_root_id IS NOT NULL;
+
+-- Per-user thread read state
+CREATE TABLE IF NOT EXISTS thread_reads (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    thread_root_id UUID NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    last_read_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT unique_thread_read_user UNIQUE (thread_root_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_thread_reads_user_id ON thread_reads(user_id);
+
+-- Add comments for documentation
+COMMENT ON COLUMN messages.thread_root_id IS 'Root message of the thread this reply belongs to (NULL for main timeline messages)';
+COMMENT ON COLUMN messages.thread_reply_count IS 'Number of non-deleted replies in the thread (root messages only)';
+COMMENT ON TABLE thread_reads IS 'Per-user read position for message threads';
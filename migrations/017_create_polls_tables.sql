@@ -0,0 +1,48 @@
+-- migrations/017_create_polls_tables.sql
+-- Create tables for poll messages (message_type = 'poll')
+
+CREATE TABLE IF NOT EXISTS polls (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    message_id UUID NOT NULL UNIQUE REFERENCES messages(id) ON DELETE CASCADE,
+    conversation_id UUID NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    creator_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    question TEXT NOT NULL,
+    allow_multiple BOOLEAN DEFAULT FALSE,
+    is_anonymous BOOLEAN DEFAULT FALSE,
+    closes_at TIMESTAMP WITH TIME ZONE,
+    is_closed BOOLEAN DEFAULT FALSE,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    closed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS poll_options (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    poll_id UUID NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    text VARCHAR(200) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS poll_votes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    poll_id UUID NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    option_id UUID NOT NULL REFERENCES poll_options(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    -- Unique Constraint: a user can vote for an option only once
+    CONSTRAINT unique_poll_vote_option_user UNIQUE (option_id, user_id)
+);
+
+-- Create Indexes for better query performance
+CREATE INDEX IF NOT EXISTS idx_polls_conversation_id ON polls(conversation_id);
+CREATE INDEX IF NOT EXISTS idx_poll_options_poll_id ON poll_options(poll_id);
+CREATE INDEX IF NOT EXISTS idx_poll_votes_poll_id ON poll_votes(poll_id);
+CREATE INDEX IF NOT EXISTS idx_poll_votes_poll_user ON poll_votes(poll_id, user_id);
+
+-- Add comments for documentation
+COMMENT ON TABLE polls IS 'Polls attached to messages with message_type = poll';
+COMMENT ON COLUMN polls.is_anonymous IS 'When true, voter identities are never returned to clients';
+COMMENT ON COLUMN polls.closes_at IS 'Optional automatic close time; voting is rejected after this time';
+COMMENT ON TABLE poll_votes IS 'One row per (user, option); multiple rows per user only when allow_multiple is true';
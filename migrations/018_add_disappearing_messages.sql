@@ -0,0 +1,13 @@
+-- migrations/018_add_disappearing_messages.sql
+-- Add per-conversation disappearing messages (TTL) and message expiry
+
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS message_ttl INTEGER DEFAULT 0;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+
+-- Index for the purge worker (only messages that are still visible and have an expiry)
+CREATE INDEX IF NOT EXISTS idx_messages_expires_at ON messages(expires_at)
+    WHERE expires_at IS NOT NULL AND is_deleted = FALSE;
+
+-- Add comments for documentation
+COMMENT ON COLUMN conversations.message_ttl IS 'Disappearing messages TTL in seconds (0 = off); stamped as expires_at on new messages';
+COMMENT ON COLUMN messages.expires_at IS 'Time after which the message is purged by the expiry worker (NULL = never)';
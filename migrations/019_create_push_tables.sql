@@ -0,0 +1,49 @@
+-- migrations/019_create_push_tables.sql
+-- Device tokens and push delivery log for offline push notifications (FCM/APNs/WebPush)
+
+CREATE TABLE IF NOT EXISTS device_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(20) NOT NULL,
+    token TEXT NOT NULL,
+    platform VARCHAR(20),
+    device_name VARCHAR(100),
+    webpush_p256dh TEXT,
+    webpush_auth TEXT,
+    is_active BOOLEAN DEFAULT TRUE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    -- Unique Constraint: a token belongs to exactly one user/device per provider
+    CONSTRAINT unique_device_token UNIQUE (provider, token)
+);
+
+CREATE TABLE IF NOT EXISTS push_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_token_id UUID NOT NULL REFERENCES device_tokens(id) ON DELETE CASCADE,
+    provider VARCHAR(20) NOT NULL,
+    conversation_id UUID REFERENCES conversations(id) ON DELETE CASCADE,
+    message_id UUID REFERENCES messages(id) ON DELETE CASCADE,
+    kind VARCHAR(30) NOT NULL,
+    payload JSONB DEFAULT '{}'::jsonb,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER DEFAULT 0,
+    last_error TEXT,
+    next_retry_at TIMESTAMP WITH TIME ZONE,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create Indexes for better query performance
+CREATE INDEX IF NOT EXISTS idx_device_tokens_user_id ON device_tokens(user_id) WHERE is_active = TRUE;
+CREATE INDEX IF NOT EXISTS idx_push_deliveries_user_created ON push_deliveries(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_push_deliveries_retry ON push_deliveries(next_retry_at)
+    WHERE status = 'pending';
+
+-- Add comments for documentation
+COMMENT ON TABLE device_tokens IS 'Push notification tokens registered by user devices';
+COMMENT ON COLUMN device_tokens.token IS 'FCM registration token, APNs device token, or WebPush subscription endpoint';
+COMMENT ON TABLE push_deliveries IS 'Every push attempt with status, attempts and last error for retries and diagnostics';
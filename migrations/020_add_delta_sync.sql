@@ -0,0 +1,68 @@
+-- migrations/020_add_delta_sync.sql
+-- Delta sync support: member change tracking, sync cursors and tombstones for hard deletes
+
+ALTER TABLE conversation_members ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW();
+
+CREATE TABLE IF NOT EXISTS sync_tombstones (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    entity_type VARCHAR(30) NOT NULL,
+    entity_id UUID NOT NULL,
+    user_id UUID,
+    conversation_id UUID,
+    shared BOOLEAN DEFAULT FALSE,
+    deleted_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_tombstones_user_id ON sync_tombstones(user_id);
+CREATE INDEX IF NOT EXISTS idx_sync_tombstones_conversation_id ON sync_tombstones(conversation_id);
+CREATE INDEX IF NOT EXISTS idx_sync_tombstones_deleted_at ON sync_tombstones(deleted_at);
+
+-- Cursor indexes: every sync query scans (timestamp, id) in ascending order
+CREATE INDEX IF NOT EXISTS idx_messages_sync ON messages(conversation_id, updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_conversation_members_sync ON conversation_members(conversation_id, updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_user_friendships_sync ON user_friendships(updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_notes_sync ON notes(updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_pinned_messages_sync ON pinned_messages(updated_at, id);
+
+-- Record hard deletes so offline clients can drop local copies
+CREATE OR REPLACE FUNCTION record_sync_tombstone()
+RETURNS trigger AS $$
+BEGIN
+  IF TG_TABLE_NAME = 'conversation_members' THEN
+    INSERT INTO sync_tombstones (entity_type, entity_id, user_id, conversation_id, shared)
+    VALUES ('member', OLD.id, OLD.user_id, OLD.conversation_id, TRUE);
+  ELSIF TG_TABLE_NAME = 'notes' THEN
+    INSERT INTO sync_tombstones (entity_type, entity_id, user_id, conversation_id, shared)
+    VALUES ('note', OLD.id, OLD.user_id, OLD.conversation_id,
+            OLD.conversation_id IS NOT NULL AND OLD.visibility = 'shared');
+  ELSIF TG_TABLE_NAME = 'pinned_messages' THEN
+    INSERT INTO sync_tombstones (entity_type, entity_id, user_id, conversation_id, shared)
+    VALUES ('pin', OLD.id, OLD.user_id, OLD.conversation_id, OLD.pin_type = 'public');
+  ELSIF TG_TABLE_NAME = 'user_friendships' THEN
+    INSERT INTO sync_tombstones (entity_type, entity_id, user_id, shared)
+    VALUES ('friendship', OLD.id, OLD.user_id, FALSE),
+           ('friendship', OLD.id, OLD.friend_id, FALSE);
+  END IF;
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS sync_tombstone ON conversation_members;
+CREATE TRIGGER sync_tombstone AFTER DELETE ON conversation_members
+    FOR EACH ROW EXECUTE FUNCTION record_sync_tombstone();
+
+DROP TRIGGER IF EXISTS sync_tombstone ON notes;
+CREATE TRIGGER sync_tombstone AFTER DELETE ON notes
+    FOR EACH ROW EXECUTE FUNCTION record_sync_tombstone();
+
+DROP TRIGGER IF EXISTS sync_tombstone ON pinned_messages;
+CREATE TRIGGER sync_tombstone AFTER DELETE ON pinned_messages
+    FOR EACH ROW EXECUTE FUNCTION record_sync_tombstone();
+
+DROP TRIGGER IF EXISTS sync_tombstone ON user_friendships;
+CREATE TRIGGER sync_tombstone AFTER DELETE ON user_friendships
+    FOR EACH ROW EXECUTE FUNCTION record_sync_tombstone();
+
+-- Add comments for documentation
+COMMENT ON COLUMN conversation_members.updated_at IS 'Last change to role, read pointer or per-user settings; used by delta sync';
+COMMENT ON TABLE sync_tombstones IS 'Hard-deleted rows reported by GET /sync; purged after the sync token retention window';
@@ -0,0 +1,52 @@
+-- migrations/021_add_e2ee_keys.sql
+-- End-to-end encrypted direct conversations: per-device key bundles, one-time pre-keys
+-- and excluding encrypted messages from full-text search
+
+CREATE TABLE IF NOT EXISTS e2ee_key_bundles (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_id VARCHAR(64) NOT NULL,
+    registration_id INTEGER NOT NULL,
+    identity_key TEXT NOT NULL,
+    signed_pre_key_id INTEGER NOT NULL,
+    signed_pre_key TEXT NOT NULL,
+    signed_pre_key_signature TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT unique_e2ee_device UNIQUE (user_id, device_id)
+);
+
+CREATE TABLE IF NOT EXISTS e2ee_one_time_pre_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_id VARCHAR(64) NOT NULL,
+    key_id INTEGER NOT NULL,
+    public_key TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT unique_e2ee_prekey UNIQUE (user_id, device_id, key_id)
+);
+
+-- Ciphertext must never be indexed
+CREATE OR REPLACE FUNCTION messages_content_tsvector_update()
+RETURNS trigger AS $$
+BEGIN
+  IF NEW.message_type = 'encrypted' THEN
+    NEW.content_tsvector := NULL;
+  ELSE
+    NEW.content_tsvector := to_tsvector('english', COALESCE(NEW.content, ''));
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS tsvector_update ON messages;
+CREATE TRIGGER tsvector_update
+BEFORE INSERT OR UPDATE OF content, message_type ON messages
+FOR EACH ROW
+EXECUTE FUNCTION messages_content_tsvector_update();
+
+UPDATE messages SET content_tsvector = NULL WHERE message_type = 'encrypted';
+
+-- Add comments for documentation
+COMMENT ON TABLE e2ee_key_bundles IS 'Public identity and signed pre-keys per user device (private keys never leave the device)';
+COMMENT ON TABLE e2ee_one_time_pre_keys IS 'One-time pre-keys; each key is deleted when claimed by a bundle fetch';
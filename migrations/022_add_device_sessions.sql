@@ -0,0 +1,20 @@
+-- migrations/022_add_device_sessions.sql
+-- Multi-device sessions: each refresh token row is one device session
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS device_name VARCHAR(100);
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS user_agent TEXT;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS ip_address VARCHAR(45);
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE refresh_tokens SET last_used_at = created_at WHERE last_used_at IS NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN last_used_at SET DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_token ON refresh_tokens(token);
+
+-- Checked on every authenticated request (access token and "session:<id>" keys)
+CREATE INDEX IF NOT EXISTS idx_token_blacklist_token ON token_blacklist(token);
+
+-- Add comments for documentation
+COMMENT ON COLUMN refresh_tokens.last_used_at IS 'Last login or token refresh from this device';
+COMMENT ON COLUMN token_blacklist.token IS 'Revoked access token, or session:<refresh_token_id> to revoke every access token of a device session';
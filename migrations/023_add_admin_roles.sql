@@ -0,0 +1,33 @@
+-- migrations/023_add_admin_roles.sql
+-- Platform-level system roles, account suspension and admin audit log
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS system_role VARCHAR(20) DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_reason TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_users_system_role ON users(system_role) WHERE system_role <> '';
+
+CREATE TABLE IF NOT EXISTS admin_audit_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    actor_id UUID NOT NULL REFERENCES users(id),
+    actor_role VARCHAR(20) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    target_type VARCHAR(30),
+    target_id UUID,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    details JSONB DEFAULT '{}'::jsonb,
+    ip_address VARCHAR(45),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_audit_logs_actor_id ON admin_audit_logs(actor_id);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_logs_action ON admin_audit_logs(action);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_logs_target_id ON admin_audit_logs(target_id);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_logs_created_at ON admin_audit_logs(created_at DESC);
+
+-- The first super admin must be granted directly in the database, e.g.
+-- UPDATE users SET system_role = 'super_admin' WHERE username = '<username>';
+
+-- Add comments for documentation
+COMMENT ON COLUMN users.system_role IS 'Platform role: super_admin, moderator, support or empty for regular users';
+COMMENT ON TABLE admin_audit_logs IS 'Every request made through /api/v1/admin, including denied and failed ones';
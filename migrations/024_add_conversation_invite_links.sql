@@ -0,0 +1,22 @@
+-- migrations/024_add_conversation_invite_links.sql
+-- Shareable group invite links with expiry, usage limit and approval flag
+
+CREATE TABLE IF NOT EXISTS conversation_invite_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    conversation_id UUID NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    creator_id UUID NOT NULL REFERENCES users(id),
+    code VARCHAR(32) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    max_uses INTEGER DEFAULT 0,
+    use_count INTEGER DEFAULT 0,
+    requires_approval BOOLEAN DEFAULT FALSE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_conversation_invite_links_code ON conversation_invite_links(code);
+CREATE INDEX IF NOT EXISTS idx_conversation_invite_links_conversation_id ON conversation_invite_links(conversation_id);
+
+-- Add comments for documentation
+COMMENT ON COLUMN conversation_invite_links.max_uses IS 'Maximum number of joins, 0 = unlimited';
+COMMENT ON COLUMN conversation_invite_links.requires_approval IS 'Joining through this link requires approval by a group admin';
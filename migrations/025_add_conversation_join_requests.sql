@@ -0,0 +1,26 @@
+-- migrations/025_add_conversation_join_requests.sql
+-- Moderated join flow: requests to join a group wait for admin approval
+
+CREATE TABLE IF NOT EXISTS conversation_join_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    conversation_id UUID NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    message VARCHAR(500),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    invite_link_id UUID REFERENCES conversation_invite_links(id) ON DELETE SET NULL,
+    reviewed_by UUID REFERENCES users(id),
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_join_requests_conversation_status ON conversation_join_requests(conversation_id, status);
+CREATE INDEX IF NOT EXISTS idx_conversation_join_requests_user_id ON conversation_join_requests(user_id);
+
+-- One pending request per user per group
+CREATE UNIQUE INDEX IF NOT EXISTS idx_join_requests_pending_unique
+    ON conversation_join_requests(conversation_id, user_id) WHERE status = 'pending';
+
+-- Add comments for documentation
+COMMENT ON COLUMN conversation_join_requests.status IS 'pending, approved or rejected';
+COMMENT ON COLUMN conversation_join_requests.invite_link_id IS 'Approval-required invite link used to request, NULL for direct requests';
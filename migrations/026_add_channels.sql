@@ -0,0 +1,12 @@
+-- migrations/026_add_channels.sql
+-- Broadcast channels: conversations.type = 'channel', members with role 'subscriber'
+-- Channel reads use conversation_members.last_read_at only (no per-subscriber message_reads rows)
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS view_count BIGINT NOT NULL DEFAULT 0;
+
+-- Paged subscriber listing and member counts
+CREATE INDEX IF NOT EXISTS idx_conversation_members_conversation_joined ON conversation_members(conversation_id, joined_at);
+
+COMMENT ON COLUMN conversations.type IS 'direct, group or channel';
+COMMENT ON COLUMN conversation_members.role IS 'owner, admin, member or subscriber (channels)';
+COMMENT ON COLUMN messages.view_count IS 'Number of subscribers who have read past this channel message';
@@ -0,0 +1,51 @@
+-- migrations/027_add_bots.sql
+-- Bot accounts: users with is_bot = true, owned by a human, authenticated with an API token
+-- Outgoing webhooks are signed with HMAC-SHA256 and logged in bot_webhook_deliveries
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_bot BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS bots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    description TEXT,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    token_prefix VARCHAR(20) NOT NULL,
+    webhook_url TEXT,
+    webhook_secret VARCHAR(128),
+    receive_all BOOLEAN NOT NULL DEFAULT FALSE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_bots_owner_id ON bots(owner_id);
+
+CREATE TABLE IF NOT EXISTS bot_webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+    event VARCHAR(20) NOT NULL,
+    conversation_id UUID,
+    message_id UUID,
+    payload JSONB DEFAULT '{}'::jsonb,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER DEFAULT 0,
+    response_status INTEGER DEFAULT 0,
+    last_error TEXT,
+    next_retry_at TIMESTAMP WITH TIME ZONE,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_bot_webhook_deliveries_bot_created ON bot_webhook_deliveries(bot_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_bot_webhook_deliveries_retry ON bot_webhook_deliveries(next_retry_at) WHERE status = 'pending';
+
+-- Add comments for documentation
+COMMENT ON COLUMN users.status IS 'active, suspended or deleted (deleted bots)';
+COMMENT ON COLUMN messages.sender_type IS 'user, bot, business or system';
+COMMENT ON COLUMN bots.token_hash IS 'SHA-256 of the API token (the token itself is shown once)';
+COMMENT ON COLUMN bots.receive_all IS 'FALSE = in groups and channels only mentions and slash-commands are delivered';
+COMMENT ON COLUMN bot_webhook_deliveries.event IS 'message, mention or command';
+COMMENT ON COLUMN bot_webhook_deliveries.status IS 'pending, sent, failed or dropped';
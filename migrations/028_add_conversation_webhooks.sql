@@ -0,0 +1,23 @@
+-- migrations/028_add_conversation_webhooks.sql
+-- Incoming webhooks: external systems (CI, monitoring) post into a group through /api/v1/hooks/:token
+-- Messages are stored with sender_type = 'webhook' and no sender_id
+
+CREATE TABLE IF NOT EXISTS conversation_webhooks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    conversation_id UUID NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    creator_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(80) NOT NULL,
+    avatar_url TEXT,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    token_prefix VARCHAR(20) NOT NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_conversation_webhooks_conversation_id ON conversation_webhooks(conversation_id);
+
+-- Add comments for documentation
+COMMENT ON COLUMN messages.sender_type IS 'user, bot, webhook, business or system';
+COMMENT ON COLUMN conversation_webhooks.token_hash IS 'SHA-256 of the secret token embedded in the webhook URL';
@@ -0,0 +1,41 @@
+-- migrations/029_add_moderation_reports.sql
+-- User reports (message, user, conversation) and the moderator review queue
+-- Enforcement actions reuse message soft delete, user suspension and conversation disabling
+
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS disabled_reason TEXT;
+
+CREATE TABLE IF NOT EXISTS moderation_reports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    reporter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    target_type VARCHAR(20) NOT NULL,
+    target_id UUID NOT NULL,
+    reported_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    conversation_id UUID REFERENCES conversations(id) ON DELETE SET NULL,
+    reason VARCHAR(30) NOT NULL,
+    description TEXT,
+    snapshot JSONB DEFAULT '{}'::jsonb,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    assignee_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(30),
+    resolution_note TEXT,
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_reports_reporter_id ON moderation_reports(reporter_id);
+CREATE INDEX IF NOT EXISTS idx_moderation_reports_target ON moderation_reports(target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_moderation_reports_reported_user_id ON moderation_reports(reported_user_id);
+CREATE INDEX IF NOT EXISTS idx_moderation_reports_conversation_id ON moderation_reports(conversation_id);
+CREATE INDEX IF NOT EXISTS idx_moderation_reports_status_created_at ON moderation_reports(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_moderation_reports_assignee_id ON moderation_reports(assignee_id);
+
+-- Add comments for documentation
+COMMENT ON COLUMN conversations.disabled_at IS 'Set when a moderator disables the conversation (is_active = false)';
+COMMENT ON COLUMN moderation_reports.target_type IS 'message, user or conversation';
+COMMENT ON COLUMN moderation_reports.reason IS 'spam, harassment, hate_speech, violence, sexual_content, scam, impersonation or other';
+COMMENT ON COLUMN moderation_reports.status IS 'open, in_review, resolved or dismissed';
+COMMENT ON COLUMN moderation_reports.action IS 'delete_message, suspend_user or disable_conversation (resolved reports)';
+COMMENT ON COLUMN moderation_reports.snapshot IS 'Copy of the reported content at report time';
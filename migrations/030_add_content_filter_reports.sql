@@ -0,0 +1,13 @@
+-- migrations/030_add_content_filter_reports.sql
+-- Automatic moderation reports for messages flagged by the content filter chain
+-- Per-group filter settings live in conversations.metadata->'content_filters' (no schema change)
+
+ALTER TABLE moderation_reports ALTER COLUMN reporter_id DROP NOT NULL;
+ALTER TABLE moderation_reports ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'user';
+
+CREATE INDEX IF NOT EXISTS idx_moderation_reports_source ON moderation_reports(source);
+
+-- Add comments for documentation
+COMMENT ON COLUMN moderation_reports.reporter_id IS 'User who filed the report (NULL for content filter reports)';
+COMMENT ON COLUMN moderation_reports.source IS 'user or content_filter';
+COMMENT ON COLUMN moderation_reports.reason IS 'spam, harassment, hate_speech, violence, sexual_content, scam, impersonation, other or content_filter';
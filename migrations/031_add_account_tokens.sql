@@ -0,0 +1,25 @@
+-- migrations/031_add_account_tokens.sql
+-- Single-use, expiring tokens for password reset and email verification (only the SHA-256 hash is stored)
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS account_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR(30) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_account_tokens_token_hash ON account_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_account_tokens_user_id ON account_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_account_tokens_expires_at ON account_tokens(expires_at);
+
+-- Add comments for documentation
+COMMENT ON COLUMN users.email_verified_at IS 'Set when the user confirms their email; cleared when the email changes';
+COMMENT ON COLUMN account_tokens.purpose IS 'password_reset or email_verification';
+COMMENT ON COLUMN account_tokens.email IS 'Address the token was sent to; verification fails if the user email changed since';
+COMMENT ON COLUMN account_tokens.used_at IS 'Set when the token is used or replaced by a newer token';
@@ -0,0 +1,24 @@
+-- migrations/032_add_two_factor_auth.sql
+-- Optional TOTP two-factor authentication with hashed one-time recovery codes
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_secret TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_enabled_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_last_step BIGINT DEFAULT 0;
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS two_factor_verified BOOLEAN DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS two_factor_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_two_factor_recovery_codes_user_id ON two_factor_recovery_codes(user_id);
+
+-- Add comments for documentation
+COMMENT ON COLUMN users.two_factor_secret IS 'AES-GCM encrypted TOTP secret; set without two_factor_enabled_at while enrollment is pending';
+COMMENT ON COLUMN users.two_factor_last_step IS 'Last accepted TOTP time step, prevents reusing a code';
+COMMENT ON COLUMN refresh_tokens.two_factor_verified IS 'Session passed two-factor verification; unverified sessions cannot refresh once 2FA is enabled';
+COMMENT ON COLUMN two_factor_recovery_codes.code_hash IS 'SHA-256 of the normalized recovery code';
@@ -0,0 +1,37 @@
+-- migrations/033_add_oauth_identities.sql
+-- OAuth2/OIDC sign-in: external identities linked to users and single-use authorization states (PKCE)
+
+CREATE TABLE IF NOT EXISTS user_identities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    last_login_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_identities_provider_subject ON user_identities(provider, subject);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_identities_user_provider ON user_identities(user_id, provider);
+
+CREATE TABLE IF NOT EXISTS oauth_states (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    state_hash VARCHAR(64) NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    nonce VARCHAR(128) NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_oauth_states_state_hash ON oauth_states(state_hash);
+CREATE INDEX IF NOT EXISTS idx_oauth_states_expires_at ON oauth_states(expires_at);
+
+-- Add comments for documentation
+COMMENT ON COLUMN user_identities.subject IS 'Provider "sub" claim, unique per provider';
+COMMENT ON COLUMN user_identities.email IS 'Email reported by the provider when the identity was linked';
+COMMENT ON COLUMN oauth_states.state_hash IS 'SHA-256 of the state parameter sent to the provider';
+COMMENT ON COLUMN oauth_states.code_verifier IS 'PKCE code verifier, kept server-side until the callback';
+COMMENT ON COLUMN oauth_states.user_id IS 'Set when the flow links a provider to a signed-in user instead of signing in';
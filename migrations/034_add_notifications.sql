@@ -0,0 +1,25 @@
+-- migrations/034_add_notifications.sql
+-- Notification inbox: persisted notifications with read state, so users who were offline still see them
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    target_type VARCHAR(30),
+    target_id UUID,
+    conversation_id UUID REFERENCES conversations(id) ON DELETE CASCADE,
+    payload JSONB DEFAULT '{}'::jsonb,
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created ON notifications(user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+-- Add comments for documentation
+COMMENT ON COLUMN notifications.user_id IS 'Recipient of the notification';
+COMMENT ON COLUMN notifications.actor_id IS 'User who caused the notification, NULL for system notifications';
+COMMENT ON COLUMN notifications.target_type IS 'Kind of object target_id refers to: message, conversation, friend_request, join_request';
+COMMENT ON COLUMN notifications.payload IS 'Event data as it was pushed over WebSocket';
+COMMENT ON COLUMN notifications.read_at IS 'NULL while unread; counted by the unread badge';
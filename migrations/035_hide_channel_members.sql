@@ -0,0 +1,41 @@
+-- migrations/035_hide_channel_members.sql
+-- Channels hide their subscriber list: member tombstones of channels are no longer shared with every
+-- member (only the removed user receives them), and channel push only reads members who get every post
+
+CREATE OR REPLACE FUNCTION record_sync_tombstone()
+RETURNS trigger AS $$
+BEGIN
+  IF TG_TABLE_NAME = 'conversation_members' THEN
+    INSERT INTO sync_tombstones (entity_type, entity_id, user_id, conversation_id, shared)
+    VALUES ('member', OLD.id, OLD.user_id, OLD.conversation_id,
+            COALESCE((SELECT type FROM conversations WHERE id = OLD.conversation_id), '') <> 'channel');
+  ELSIF TG_TABLE_NAME = 'notes' THEN
+    INSERT INTO sync_tombstones (entity_type, entity_id, user_id, conversation_id, shared)
+    VALUES ('note', OLD.id, OLD.user_id, OLD.conversation_id,
+            OLD.conversation_id IS NOT NULL AND OLD.visibility = 'shared');
+  ELSIF TG_TABLE_NAME = 'pinned_messages' THEN
+    INSERT INTO sync_tombstones (entity_type, entity_id, user_id, conversation_id, shared)
+    VALUES ('pin', OLD.id, OLD.user_id, OLD.conversation_id, OLD.pin_type = 'public');
+  ELSIF TG_TABLE_NAME = 'user_friendships' THEN
+    INSERT INTO sync_tombstones (entity_type, entity_id, user_id, shared)
+    VALUES ('friendship', OLD.id, OLD.user_id, FALSE),
+           ('friendship', OLD.id, OLD.friend_id, FALSE);
+  END IF;
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Existing channel member tombstones must not reach other subscribers either
+UPDATE sync_tombstones t
+SET shared = FALSE
+FROM conversations c
+WHERE t.entity_type = 'member' AND t.shared = TRUE
+  AND c.id = t.conversation_id AND c.type = 'channel';
+
+-- Members notified of every channel post (owners/admins and subscribers with level = all)
+CREATE INDEX IF NOT EXISTS idx_conversation_members_channel_notify ON conversation_members(conversation_id)
+    WHERE role <> 'subscriber' OR notification_settings->>'level' = 'all';
+
+-- Channel unread counts: messages after the member's read cursor
+CREATE INDEX IF NOT EXISTS idx_messages_conversation_created ON messages(conversation_id, created_at)
+    WHERE thread_root_id IS NULL AND is_deleted = FALSE;
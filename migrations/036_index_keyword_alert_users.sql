@@ -0,0 +1,6 @@
+-- migrations/036_index_keyword_alert_users.sql
+-- Keyword alerts only read members whose users have keywords configured, instead of every member
+-- of the conversation for each message
+
+CREATE INDEX IF NOT EXISTS idx_users_keyword_alerts ON users(id)
+    WHERE jsonb_typeof(settings->'keyword_alerts') = 'array' AND settings->'keyword_alerts' <> '[]'::jsonb;
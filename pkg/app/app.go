@@ -47,7 +47,7 @@ func SetupApp(container *di.Container) *fiber.App {
 		AllowOrigins:     "*",
 		AllowMethods:     "GET,POST,PUT,DELETE,PATCH,OPTIONS",
 		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,X-Requested-With",
-		ExposeHeaders:    "Content-Length,Content-Type",
+		ExposeHeaders:    "Content-Length,Content-Type,Retry-After,X-RateLimit-Limit,X-RateLimit-Remaining,X-RateLimit-Reset",
 		AllowCredentials: false,
 		MaxAge:           86400, // 24 ชั่วโมง
 	}))
@@ -62,6 +62,13 @@ func SetupApp(container *di.Container) *fiber.App {
 		})
 	})
 
+	// ตรวจสอบ token ที่ถูกเพิกถอน (logout/เพิกถอนอุปกรณ์) ในทุก route ที่ต้องยืนยันตัวตน
+	middleware.SetTokenSigner(container.TokenSigner)
+	middleware.SetTokenRevocationChecker(container.AuthService)
+	middleware.SetSystemRoleResolver(container.AuthService)
+	middleware.SetBotTokenResolver(container.BotService)
+	middleware.SetRateLimiter(container.RateLimiter)
+
 	// กำหนดเส้นทางทั้งหมด (ไม่แก้ไข - ใช้แบบเดิม)
 	routes.SetupRoutes(
 		app,
@@ -80,6 +87,18 @@ func SetupApp(container *di.Container) *fiber.App {
 		container.SearchHandler,
 		container.PresenceHandler,
 		container.PinnedMessageHandler,
+		container.ThreadHandler,
+		container.PushHandler,
+		container.SyncHandler,
+		container.E2EEHandler,
+		container.AdminHandler,
+		container.InviteLinkHandler,
+		container.JoinRequestHandler,
+		container.BotHandler,
+		container.ConversationWebhookHandler,
+		container.ModerationHandler,
+		container.ContentFilterHandler,
+		container.NotificationHandler,
 	)
 
 	// เพิ่ม WebSocket routes แยกต่างหาก (หลังจาก SetupRoutes)
@@ -0,0 +1,40 @@
+// pkg/configs/bot_config.go
+package configs
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/infrastructure/webhook"
+)
+
+// SetupBotWebhookSender สร้าง sender สำหรับ outgoing webhook ของบอทตาม environment
+// BOT_WEBHOOK_TIMEOUT = เวลารอ response (วินาที), BOT_WEBHOOK_ALLOW_INSECURE=true = อนุญาต http:// (ใช้กับ stand-in บนเครื่อง)
+// BOT_WEBHOOK_ALLOW_PRIVATE=true = อนุญาตปลายทางที่เป็น address ภายใน เช่น localhost (ห้ามเปิดใน production)
+func SetupBotWebhookSender() service.WebhookSender {
+	timeout := 10 * time.Second
+	if value := os.Getenv("BOT_WEBHOOK_TIMEOUT"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			timeout = time.Duration(seconds) * time.Second
+		}
+	}
+
+	allowInsecure := os.Getenv("BOT_WEBHOOK_ALLOW_INSECURE") == "true"
+	if allowInsecure {
+		log.Println("WARNING: BOT_WEBHOOK_ALLOW_INSECURE is enabled, bot webhooks may use plain http")
+	}
+
+	allowPrivate := os.Getenv("BOT_WEBHOOK_ALLOW_PRIVATE") == "true"
+	if allowPrivate {
+		log.Println("WARNING: BOT_WEBHOOK_ALLOW_PRIVATE is enabled, bot webhooks may reach internal addresses")
+	}
+
+	return webhook.NewHTTPSender(&webhook.HTTPSenderConfig{
+		Timeout:       timeout,
+		AllowInsecure: allowInsecure,
+		AllowPrivate:  allowPrivate,
+	})
+}
@@ -0,0 +1,30 @@
+// pkg/configs/cluster.go
+package configs
+
+import (
+	"os"
+
+	"github.com/google/uuid"
+)
+
+// ClusterConfig การตั้งค่าสำหรับการรันหลาย API instance (WebSocket fan-out ผ่าน Redis)
+type ClusterConfig struct {
+	NodeID string // ID ของ instance นี้ (ต้องไม่ซ้ำกันในแต่ละ replica)
+}
+
+// LoadClusterConfig โหลดการตั้งค่า cluster จาก environment
+// ถ้าไม่ได้กำหนด NODE_ID จะใช้ hostname ต่อด้วย ID สุ่ม เพื่อให้ไม่ซ้ำแม้ container ใช้ hostname เดียวกัน
+func LoadClusterConfig() ClusterConfig {
+	nodeID := os.Getenv("NODE_ID")
+	if nodeID == "" {
+		hostname, err := os.Hostname()
+		if err != nil || hostname == "" {
+			hostname = "node"
+		}
+		nodeID = hostname + "-" + uuid.NewString()[:8]
+	}
+
+	return ClusterConfig{
+		NodeID: nodeID,
+	}
+}
@@ -0,0 +1,81 @@
+// pkg/configs/content_filter_config.go
+package configs
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/infrastructure/contentfilter"
+)
+
+// SetupContentFilters สร้าง content filter chain ตาม environment (ลำดับใน chain = ลำดับใน CONTENT_FILTERS)
+// CONTENT_FILTERS = รายการคั่นด้วย comma (default: "word_list,link_domains,duplicate_flood", "none" = ปิด)
+// CONTENT_FILTER_WORDS, CONTENT_FILTER_WORD_ACTION = คำต้องห้ามของระบบ และ mask/reject/flag
+// CONTENT_FILTER_DENY_DOMAINS, CONTENT_FILTER_LINK_ACTION = โดเมนต้องห้ามของระบบ และ mask/reject/flag
+// CONTENT_FILTER_FLOOD_MAX_REPEATS, CONTENT_FILTER_FLOOD_WINDOW (วินาที), CONTENT_FILTER_FLOOD_ACTION = reject/flag
+func SetupContentFilters(redisClient *redis.Client) []service.ContentFilter {
+	filters := []service.ContentFilter{}
+
+	names := os.Getenv("CONTENT_FILTERS")
+	if names == "" {
+		names = strings.Join([]string{
+			contentfilter.WordListFilterName,
+			contentfilter.LinkDomainFilterName,
+			contentfilter.DuplicateFloodFilterName,
+		}, ",")
+	}
+
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || name == "none" {
+			continue
+		}
+
+		switch name {
+		case contentfilter.WordListFilterName:
+			filters = append(filters, contentfilter.NewWordListFilter(&contentfilter.WordListConfig{
+				Words:  splitEnvList("CONTENT_FILTER_WORDS"),
+				Action: os.Getenv("CONTENT_FILTER_WORD_ACTION"),
+			}))
+
+		case contentfilter.LinkDomainFilterName:
+			filters = append(filters, contentfilter.NewLinkDomainFilter(&contentfilter.LinkDomainConfig{
+				DenyDomains: splitEnvList("CONTENT_FILTER_DENY_DOMAINS"),
+				Action:      os.Getenv("CONTENT_FILTER_LINK_ACTION"),
+			}))
+
+		case contentfilter.DuplicateFloodFilterName:
+			maxRepeats, _ := strconv.Atoi(os.Getenv("CONTENT_FILTER_FLOOD_MAX_REPEATS"))
+			windowSeconds, _ := strconv.Atoi(os.Getenv("CONTENT_FILTER_FLOOD_WINDOW"))
+			filters = append(filters, contentfilter.NewDuplicateFloodFilter(redisClient, &contentfilter.DuplicateFloodConfig{
+				MaxRepeats: maxRepeats,
+				Window:     time.Duration(windowSeconds) * time.Second,
+				Action:     os.Getenv("CONTENT_FILTER_FLOOD_ACTION"),
+			}))
+
+		default:
+			log.Printf("WARNING: unknown content filter %q, skipped", name)
+			continue
+		}
+
+		log.Printf("Content filter enabled: %s", name)
+	}
+
+	return filters
+}
+
+// splitEnvList อ่านรายการคั่นด้วย comma จาก environment
+func splitEnvList(key string) []string {
+	result := []string{}
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+// pkg/configs/jwt_config.go
+package configs
+
+import (
+	"crypto/sha256"
+	"errors"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/infrastructure/jwtsigner"
+)
+
+// defaultJWTSecret ค่าเดิมที่ใช้เมื่อไม่ได้ตั้ง JWT_SECRET (ใช้ได้เฉพาะตอนพัฒนา)
+const defaultJWTSecret = "default-jwt-secret-for-development-only"
+
+// SetupTokenSigner สร้างตัวเซ็น/ตรวจ JWT ตาม environment
+// JWT_KEYS_DIR = โฟลเดอร์ไฟล์ .pem (ชื่อไฟล์ = kid) เซ็นด้วย RS256/EdDSA/ES256 ตามชนิดของ key (ว่าง = HS256 ด้วย JWT_SECRET)
+// JWT_ACTIVE_KID = kid ที่ใช้เซ็น token ใหม่ (ไฟล์อื่นใช้ตรวจอย่างเดียว), JWT_ACCEPT_HS256=true = ยังรับ token HS256 เดิมระหว่างย้าย
+// ENV ที่ไม่ใช่ development ต้องตั้ง secret เอง จะไม่เริ่มระบบด้วยค่า default
+func SetupTokenSigner() (service.TokenSigner, error) {
+	keysDir := strings.TrimSpace(os.Getenv("JWT_KEYS_DIR"))
+	acceptHMAC := os.Getenv("JWT_ACCEPT_HS256") == "true"
+
+	secret := os.Getenv("JWT_SECRET")
+	usesSecret := keysDir == "" || acceptHMAC
+	if secret == "" || secret == defaultJWTSecret {
+		if !IsDevelopment() && usesSecret {
+			return nil, errors.New("JWT_SECRET must be set to a non-default value outside development (or configure JWT_KEYS_DIR)")
+		}
+		if usesSecret {
+			log.Println("WARNING: JWT_SECRET not set in environment, using default value")
+		}
+		secret = defaultJWTSecret
+	}
+
+	ring, err := jwtsigner.NewKeyRing(&jwtsigner.KeyRingConfig{
+		KeysDir:     keysDir,
+		ActiveKeyID: strings.TrimSpace(os.Getenv("JWT_ACTIVE_KID")),
+		HMACSecret:  []byte(secret),
+		AcceptHMAC:  acceptHMAC,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if ring.ActiveKeyID() != "" {
+		log.Printf("JWT signing enabled: %s (kid %s, %d verification keys)", ring.Algorithm(), ring.ActiveKeyID(), len(ring.PublicKeys()))
+	} else {
+		log.Printf("JWT signing enabled: %s", ring.Algorithm())
+	}
+	return ring, nil
+}
+
+// SetupTwoFactorEncryptionKey โหลดคีย์ AES-256 สำหรับเข้ารหัส TOTP secret ในฐานข้อมูล (โหลดครั้งเดียวตอนเริ่มระบบ)
+// TWO_FACTOR_ENCRYPTION_KEY (ว่าง = ใช้ JWT_SECRET) เปลี่ยนค่าแล้ว 2FA เดิมจะใช้ไม่ได้
+// ENV ที่ไม่ใช่ development ต้องตั้งค่าใดค่าหนึ่งเอง จะไม่ใช้ค่า default
+func SetupTwoFactorEncryptionKey() ([]byte, error) {
+	secret := os.Getenv("TWO_FACTOR_ENCRYPTION_KEY")
+	if secret == "" {
+		secret = os.Getenv("JWT_SECRET")
+	}
+	if secret == "" || secret == defaultJWTSecret {
+		if !IsDevelopment() {
+			return nil, errors.New("TWO_FACTOR_ENCRYPTION_KEY must be set when JWT_SECRET is not set outside development")
+		}
+		secret = defaultJWTSecret
+	}
+
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:], nil
+}
+
+// IsDevelopment ตรวจว่ารันในโหมดพัฒนา (ENV=development)
+func IsDevelopment() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("ENV"))) {
+	case "development", "dev", "local":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,63 @@
+// pkg/configs/mail_config.go
+package configs
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/infrastructure/mail"
+)
+
+// SetupMailer สร้าง Mailer ตาม environment
+// MAIL_DRIVER = smtp, file หรือ log (default: log), MAIL_FROM = ที่อยู่ผู้ส่ง
+// smtp: SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD, SMTP_IMPLICIT_TLS=true (port 465), SMTP_TIMEOUT (วินาที)
+// file: MAIL_FILE_DIR = โฟลเดอร์ที่เก็บไฟล์ .eml
+func SetupMailer() (service.Mailer, error) {
+	driver := strings.ToLower(strings.TrimSpace(os.Getenv("MAIL_DRIVER")))
+	from := os.Getenv("MAIL_FROM")
+
+	switch driver {
+	case "smtp":
+		port, _ := strconv.Atoi(os.Getenv("SMTP_PORT"))
+		mailer, err := mail.NewSMTPMailer(&mail.SMTPConfig{
+			Host:        os.Getenv("SMTP_HOST"),
+			Port:        port,
+			Username:    os.Getenv("SMTP_USERNAME"),
+			Password:    os.Getenv("SMTP_PASSWORD"),
+			From:        from,
+			ImplicitTLS: os.Getenv("SMTP_IMPLICIT_TLS") == "true",
+			Timeout:     envSeconds("SMTP_TIMEOUT"),
+		})
+		if err != nil {
+			return nil, err
+		}
+		log.Println("Mail driver enabled: smtp")
+		return mailer, nil
+
+	case "file", "log", "":
+		dir := ""
+		if driver == "file" {
+			dir = os.Getenv("MAIL_FILE_DIR")
+			if dir == "" {
+				dir = "./tmp/mail"
+			}
+		}
+		mailer, err := mail.NewFileMailer(&mail.FileMailerConfig{Dir: dir, From: from})
+		if err != nil {
+			return nil, err
+		}
+		if driver == "file" {
+			log.Printf("Mail driver enabled: file (%s)", dir)
+		} else {
+			log.Println("WARNING: MAIL_DRIVER is not smtp, emails are written to the log instead of being sent")
+		}
+		return mailer, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported mail driver: %s (supported: smtp, file, log)", driver)
+	}
+}
@@ -0,0 +1,87 @@
+// pkg/configs/oauth_config.go
+package configs
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/infrastructure/oauth"
+)
+
+// SetupOAuthProviders สร้าง OAuthProvider ตาม environment
+// OAUTH_PROVIDERS เป็นรายการคั่นด้วย comma เช่น "google,microsoft,oidc" (ว่าง = ปิดการเข้าสู่ระบบผ่าน provider ภายนอก)
+// google: GOOGLE_CLIENT_ID, GOOGLE_CLIENT_SECRET, GOOGLE_REDIRECT_URL
+// microsoft: MICROSOFT_CLIENT_ID, MICROSOFT_CLIENT_SECRET, MICROSOFT_REDIRECT_URL, MICROSOFT_TENANT (default: common)
+// oidc: OIDC_NAME (default: oidc), OIDC_ISSUER_URL, OIDC_CLIENT_ID, OIDC_CLIENT_SECRET, OIDC_REDIRECT_URL, OIDC_SCOPES
+// OAUTH_ALLOW_INSECURE=true อนุญาต issuer แบบ http:// (เช่น mock OIDC issuer บนเครื่อง)
+func SetupOAuthProviders() ([]service.OAuthProvider, error) {
+	providers := []service.OAuthProvider{}
+	allowInsecure := os.Getenv("OAUTH_ALLOW_INSECURE") == "true"
+	timeout := envSeconds("OAUTH_TIMEOUT")
+	seen := map[string]bool{}
+
+	names := strings.Split(os.Getenv("OAUTH_PROVIDERS"), ",")
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		var config *oauth.OIDCConfig
+		switch name {
+		case "google":
+			config = &oauth.OIDCConfig{
+				Name:         "google",
+				IssuerURL:    oauth.GoogleIssuerURL,
+				ClientID:     os.Getenv("GOOGLE_CLIENT_ID"),
+				ClientSecret: os.Getenv("GOOGLE_CLIENT_SECRET"),
+				RedirectURL:  os.Getenv("GOOGLE_REDIRECT_URL"),
+			}
+
+		case "microsoft":
+			config = &oauth.OIDCConfig{
+				Name:         "microsoft",
+				IssuerURL:    oauth.MicrosoftIssuerURL(os.Getenv("MICROSOFT_TENANT")),
+				ClientID:     os.Getenv("MICROSOFT_CLIENT_ID"),
+				ClientSecret: os.Getenv("MICROSOFT_CLIENT_SECRET"),
+				RedirectURL:  os.Getenv("MICROSOFT_REDIRECT_URL"),
+			}
+
+		case "oidc":
+			providerName := os.Getenv("OIDC_NAME")
+			if providerName == "" {
+				providerName = "oidc"
+			}
+			config = &oauth.OIDCConfig{
+				Name:         providerName,
+				IssuerURL:    os.Getenv("OIDC_ISSUER_URL"),
+				ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+				ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+				RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+				Scopes:       strings.Fields(strings.ReplaceAll(os.Getenv("OIDC_SCOPES"), ",", " ")),
+			}
+
+		default:
+			return nil, fmt.Errorf("unsupported oauth provider: %s (supported: google, microsoft, oidc)", name)
+		}
+
+		config.AllowInsecure = allowInsecure
+		config.Timeout = timeout
+		provider, err := oauth.NewOIDCProvider(config)
+		if err != nil {
+			return nil, err
+		}
+		if seen[provider.Name()] {
+			return nil, fmt.Errorf("duplicate oauth provider name: %s", provider.Name())
+		}
+		seen[provider.Name()] = true
+		providers = append(providers, provider)
+
+		log.Printf("OAuth provider enabled: %s", provider.Name())
+	}
+
+	return providers, nil
+}
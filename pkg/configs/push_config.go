@@ -0,0 +1,89 @@
+// pkg/configs/push_config.go
+package configs
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/infrastructure/push/apns"
+	"github.com/thizplus/gofiber-chat-api/infrastructure/push/fake"
+	"github.com/thizplus/gofiber-chat-api/infrastructure/push/fcm"
+	"github.com/thizplus/gofiber-chat-api/infrastructure/push/webpush"
+)
+
+// SetupPushProviders สร้าง PushProvider ตาม environment
+// PUSH_PROVIDERS เป็นรายการคั่นด้วย comma เช่น "fcm,apns,webpush" (ว่าง = ปิด push)
+func SetupPushProviders() ([]service.PushProvider, error) {
+	providers := []service.PushProvider{}
+
+	names := strings.Split(os.Getenv("PUSH_PROVIDERS"), ",")
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		switch name {
+		case models.PushProviderFCM:
+			provider, err := fcm.NewFCMProvider(&fcm.FCMConfig{
+				ProjectID:       os.Getenv("FCM_PROJECT_ID"),
+				CredentialsFile: os.Getenv("FCM_CREDENTIALS_FILE"),
+				CredentialsJSON: os.Getenv("FCM_CREDENTIALS_JSON"),
+			})
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, provider)
+
+		case models.PushProviderAPNs:
+			provider, err := apns.NewAPNsProvider(&apns.APNsConfig{
+				KeyID:          os.Getenv("APNS_KEY_ID"),
+				TeamID:         os.Getenv("APNS_TEAM_ID"),
+				BundleID:       os.Getenv("APNS_BUNDLE_ID"),
+				PrivateKeyFile: os.Getenv("APNS_PRIVATE_KEY_FILE"),
+				PrivateKey:     os.Getenv("APNS_PRIVATE_KEY"),
+				Production:     os.Getenv("APNS_PRODUCTION") == "true",
+			})
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, provider)
+
+		case models.PushProviderWebPush:
+			allowPrivate := os.Getenv("WEBPUSH_ALLOW_PRIVATE") == "true"
+			if allowPrivate {
+				log.Println("WARNING: WEBPUSH_ALLOW_PRIVATE is enabled, webpush endpoints may reach internal addresses")
+			}
+			provider, err := webpush.NewWebPushProvider(&webpush.WebPushConfig{
+				VAPIDPublicKey:  os.Getenv("VAPID_PUBLIC_KEY"),
+				VAPIDPrivateKey: os.Getenv("VAPID_PRIVATE_KEY"),
+				Subject:         os.Getenv("VAPID_SUBJECT"),
+				AllowPrivate:    allowPrivate,
+			})
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, provider)
+
+		case models.PushProviderFake:
+			providers = append(providers, fake.NewFakeProvider(&fake.FakeConfig{
+				Verbose: true,
+			}))
+
+		default:
+			return nil, fmt.Errorf("unsupported push provider: %s (supported: fcm, apns, webpush, fake)", name)
+		}
+
+		log.Printf("Push provider enabled: %s", name)
+	}
+
+	if len(providers) == 0 {
+		log.Println("No push providers configured, offline push notifications are disabled")
+	}
+
+	return providers, nil
+}
@@ -0,0 +1,62 @@
+// pkg/configs/rate_limit_config.go
+package configs
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+	"github.com/thizplus/gofiber-chat-api/infrastructure/ratelimit"
+)
+
+// SetupRateLimiting สร้าง rate limiter ของ REST API และตัวล็อกการเข้าสู่ระบบผิดซ้ำตาม environment
+// RATE_LIMIT_ENABLED=false = ปิด rate limit ของ REST API (การล็อกการเข้าสู่ระบบยังทำงาน)
+// LOGIN_LOCKOUT_THRESHOLD = จำนวนครั้งที่ผิดได้, LOGIN_LOCKOUT_WINDOW = ช่วงเวลาที่นับ (วินาที)
+// LOGIN_LOCKOUT_BASE, LOGIN_LOCKOUT_MAX = ระยะล็อกครั้งแรกและสูงสุด (วินาที, เพิ่มเป็น 2 เท่าทุกครั้งที่ถูกล็อกซ้ำ)
+// ค่าข้างบนนับตาม username+IP ส่วน LOGIN_ACCOUNT_LOCKOUT_THRESHOLD, LOGIN_ACCOUNT_LOCKOUT_WINDOW นับรวมทุก IP ของบัญชี
+func SetupRateLimiting(redisClient *redis.Client) (service.RateLimiter, service.LoginAttemptGuard, service.LoginAttemptGuard) {
+	var limiter service.RateLimiter
+	if os.Getenv("RATE_LIMIT_ENABLED") == "false" {
+		log.Println("WARNING: RATE_LIMIT_ENABLED is false, REST API rate limiting is disabled")
+	} else {
+		limiter = ratelimit.NewRedisRateLimiter(redisClient)
+	}
+
+	threshold, _ := strconv.Atoi(os.Getenv("LOGIN_LOCKOUT_THRESHOLD"))
+	guard := ratelimit.NewRedisLoginGuard(redisClient, &ratelimit.LoginGuardConfig{
+		Threshold:   threshold,
+		Window:      envSeconds("LOGIN_LOCKOUT_WINDOW"),
+		BaseLockout: envSeconds("LOGIN_LOCKOUT_BASE"),
+		MaxLockout:  envSeconds("LOGIN_LOCKOUT_MAX"),
+	})
+
+	// ล็อกทั้งบัญชีเมื่อผิดจำนวนมากจากหลาย IP (สูงกว่า threshold ต่อ IP มาก เพื่อไม่ให้ผู้อื่นล็อกบัญชีได้ง่าย)
+	accountThreshold, _ := strconv.Atoi(os.Getenv("LOGIN_ACCOUNT_LOCKOUT_THRESHOLD"))
+	if accountThreshold <= 0 {
+		accountThreshold = 50
+	}
+	accountWindow := envSeconds("LOGIN_ACCOUNT_LOCKOUT_WINDOW")
+	if accountWindow <= 0 {
+		accountWindow = time.Hour
+	}
+	accountGuard := ratelimit.NewRedisLoginGuard(redisClient, &ratelimit.LoginGuardConfig{
+		Threshold:   accountThreshold,
+		Window:      accountWindow,
+		BaseLockout: envSeconds("LOGIN_LOCKOUT_BASE"),
+		MaxLockout:  envSeconds("LOGIN_LOCKOUT_MAX"),
+	})
+
+	return limiter, guard, accountGuard
+}
+
+// envSeconds อ่านจำนวนวินาทีจาก environment (ค่าไม่ถูกต้อง = 0)
+func envSeconds(key string) time.Duration {
+	seconds, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
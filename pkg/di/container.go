@@ -24,6 +24,10 @@ type Container struct {
 	UserRepo                   repository.UserRepository
 	RefreshTokenRepo           repository.RefreshTokenRepository
 	TokenBlacklistRepo         repository.TokenBlacklistRepository
+	AccountTokenRepo           repository.AccountTokenRepository
+	RecoveryCodeRepo           repository.TwoFactorRecoveryCodeRepository
+	UserIdentityRepo           repository.UserIdentityRepository
+	OAuthStateRepo             repository.OAuthStateRepository
 	UserFriendshipRepo         repository.UserFriendshipRepository
 	ConversationRepo           repository.ConversationRepository
 	ConversationMemberRepo     repository.ConversationMemberRepository
@@ -36,6 +40,21 @@ type Container struct {
 	ScheduledMessageRepo       repository.ScheduledMessageRepository
 	NoteRepo                   repository.NoteRepository
 	PinnedMessageRepo          repository.PinnedMessageRepository
+	MessageReactionRepo        repository.MessageReactionRepository
+	ThreadReadRepo             repository.ThreadReadRepository
+	PollRepo                   repository.PollRepository
+	DeviceTokenRepo            repository.DeviceTokenRepository
+	PushDeliveryRepo           repository.PushDeliveryRepository
+	SyncRepo                   repository.SyncRepository
+	E2EEKeyRepo                repository.E2EEKeyRepository
+	AdminAuditLogRepo          repository.AdminAuditLogRepository
+	InviteLinkRepo             repository.ConversationInviteLinkRepository
+	JoinRequestRepo            repository.ConversationJoinRequestRepository
+	BotRepo                    repository.BotRepository
+	BotWebhookDeliveryRepo     repository.BotWebhookDeliveryRepository
+	ConversationWebhookRepo    repository.ConversationWebhookRepository
+	ModerationReportRepo       repository.ModerationReportRepository
+	NotificationRepo           repository.NotificationRepository
 
 	// WebSocket Components
 	WebSocketHub  *websocket.Hub
@@ -57,6 +76,18 @@ type Container struct {
 	ScheduledMessageService       service.ScheduledMessageService
 	NoteService                   service.NoteService
 	PinnedMessageService          service.PinnedMessageService
+	PushService                   service.PushService
+	SyncService                   service.SyncService
+	E2EEService                   service.E2EEService
+	AdminService                  service.AdminService
+	InviteLinkService             service.InviteLinkService
+	JoinRequestService            service.JoinRequestService
+	BotWebhookService             service.BotWebhookService
+	BotService                    service.BotService
+	ConversationWebhookService    service.ConversationWebhookService
+	ModerationService             service.ModerationService
+	ContentFilterService          service.ContentFilterService
+	NotificationInboxService      service.NotificationInboxService
 
 	// Handlers
 	AuthHandler                   *handler.AuthHandler
@@ -74,24 +105,50 @@ type Container struct {
 	ScheduledMessageHandler       *handler.ScheduledMessageHandler
 	NoteHandler                   *handler.NoteHandler
 	PinnedMessageHandler          *handler.PinnedMessageHandler
+	ThreadHandler                 *handler.ThreadHandler
+	PushHandler                   *handler.PushHandler
+	SyncHandler                   *handler.SyncHandler
+	E2EEHandler                   *handler.E2EEHandler
+	AdminHandler                  *handler.AdminHandler
+	InviteLinkHandler             *handler.InviteLinkHandler
+	JoinRequestHandler            *handler.JoinRequestHandler
+	BotHandler                    *handler.BotHandler
+	ConversationWebhookHandler    *handler.ConversationWebhookHandler
+	ModerationHandler             *handler.ModerationHandler
+	ContentFilterHandler          *handler.ContentFilterHandler
+	NotificationHandler           *handler.NotificationHandler
 
 	// Scheduler & Background Jobs
 	RedisClient                    *redis.Client
+	RateLimiter                    service.RateLimiter
+	Mailer                         service.Mailer
+	TokenSigner                    service.TokenSigner
 	FileCleanupScheduler           *scheduler.FileCleanupScheduler
 	ScheduledMessageProcessor      *scheduler.ScheduledMessageProcessor
+	MessageExpiryScheduler         *scheduler.MessageExpiryScheduler
+	PushRetryScheduler             *scheduler.PushRetryScheduler
+	SyncTombstoneCleanupScheduler  *scheduler.SyncTombstoneCleanupScheduler
+	BotWebhookRetryScheduler       *scheduler.BotWebhookRetryScheduler
 }
 
 // NewContainer สร้าง container ใหม่พร้อมกับ dependencies ทั้งหมด
-func NewContainer(db *gorm.DB, storageService service.FileStorageService, redisClient *redis.Client) (*Container, error) {
+func NewContainer(db *gorm.DB, storageService service.FileStorageService, redisClient *redis.Client, pushProviders []service.PushProvider, webhookSender service.WebhookSender, contentFilters []service.ContentFilter, rateLimiter service.RateLimiter, loginGuard, accountGuard service.LoginAttemptGuard, mailer service.Mailer, tokenSigner service.TokenSigner, twoFactorKey []byte, oauthProviders []service.OAuthProvider, nodeID string) (*Container, error) {
 	container := &Container{
 		StorageService: storageService,
 		RedisClient:    redisClient,
+		RateLimiter:    rateLimiter,
+		Mailer:         mailer,
+		TokenSigner:    tokenSigner,
 	}
 
 	// สร้าง repositories
 	container.UserRepo = postgres.NewUserRepository(db)
 	container.RefreshTokenRepo = postgres.NewRefreshTokenRepository(db)
 	container.TokenBlacklistRepo = postgres.NewTokenBlacklistRepository(db)
+	container.AccountTokenRepo = postgres.NewAccountTokenRepository(db)
+	container.RecoveryCodeRepo = postgres.NewTwoFactorRecoveryCodeRepository(db)
+	container.UserIdentityRepo = postgres.NewUserIdentityRepository(db)
+	container.OAuthStateRepo = postgres.NewOAuthStateRepository(db)
 	container.UserFriendshipRepo = postgres.NewUserFriendshipRepository(db)
 	container.ConversationRepo = postgres.NewConversationRepository(db)
 	container.ConversationMemberRepo = postgres.NewConversationMemberRepository(db)
@@ -104,17 +161,25 @@ func NewContainer(db *gorm.DB, storageService service.FileStorageService, redisC
 	container.ScheduledMessageRepo = postgres.NewScheduledMessageRepository(db)
 	container.NoteRepo = postgres.NewNoteRepository(db)
 	container.PinnedMessageRepo = postgres.NewPinnedMessageRepository(db)
+	container.MessageReactionRepo = postgres.NewMessageReactionRepository(db)
+	container.ThreadReadRepo = postgres.NewThreadReadRepository(db)
+	container.PollRepo = postgres.NewPollRepository(db)
+	container.DeviceTokenRepo = postgres.NewDeviceTokenRepository(db)
+	container.PushDeliveryRepo = postgres.NewPushDeliveryRepository(db)
+	container.SyncRepo = postgres.NewSyncRepository(db)
+	container.E2EEKeyRepo = postgres.NewE2EEKeyRepository(db)
+	container.AdminAuditLogRepo = postgres.NewAdminAuditLogRepository(db)
+	container.InviteLinkRepo = postgres.NewConversationInviteLinkRepository(db)
+	container.JoinRequestRepo = postgres.NewConversationJoinRequestRepository(db)
+	container.BotRepo = postgres.NewBotRepository(db)
+	container.BotWebhookDeliveryRepo = postgres.NewBotWebhookDeliveryRepository(db)
+	container.ConversationWebhookRepo = postgres.NewConversationWebhookRepository(db)
+	container.ModerationReportRepo = postgres.NewModerationReportRepository(db)
+	container.NotificationRepo = postgres.NewNotificationRepository(db)
 
 	log.Println("เชื่อมต่อกับบริการจัดเก็บไฟล์สำเร็จ")
 
 	// สร้าง basic services
-	container.AuthService = serviceimpl.NewAuthService(
-		container.UserRepo,
-		container.RefreshTokenRepo,
-		container.TokenBlacklistRepo,
-	)
-
-	container.UserService = serviceimpl.NewUserService(container.UserRepo)
 	container.UserFriendshipService = serviceimpl.NewUserFriendshipService(
 		container.UserFriendshipRepo,
 		container.UserRepo,
@@ -124,6 +189,9 @@ func NewContainer(db *gorm.DB, storageService service.FileStorageService, redisC
 		container.UserRepo,
 		container.MessageRepo,
 		container.MessageMentionRepo,
+		container.MessageReactionRepo,
+		container.ThreadReadRepo,
+		container.PollRepo,
 	)
 	container.ConversationMemberService = serviceimpl.NewConversationMemberService(
 		container.ConversationRepo,
@@ -147,6 +215,7 @@ func NewContainer(db *gorm.DB, storageService service.FileStorageService, redisC
 		redisClient,
 		container.UserRepo,
 		container.UserFriendshipRepo,
+		nodeID,
 	)
 
 	// MessageService และ ScheduledMessageService จะถูกสร้างหลัง NotificationService (ย้ายไปด้านล่าง)
@@ -165,9 +234,43 @@ func NewContainer(db *gorm.DB, storageService service.FileStorageService, redisC
 		container.UserRepo, // 🆕 เพิ่ม UserRepo สำหรับ typing user info
 	)
 
+	// กระจาย event ข้าม API instance ผ่าน Redis pub/sub
+	container.WebSocketHub.EnableCluster(redisClient, nodeID)
+
 	// สร้าง WebSocketAdapter
 	container.WebSocketPort = adapter.NewWebSocketAdapter(container.WebSocketHub)
 
+	// สร้าง AuthService (หลังจาก WebSocketPort เพื่อตัดการเชื่อมต่อของ session ที่ถูกเพิกถอน)
+	container.AuthService = serviceimpl.NewAuthService(
+		container.UserRepo,
+		container.RefreshTokenRepo,
+		container.TokenBlacklistRepo,
+		container.AccountTokenRepo,
+		container.RecoveryCodeRepo,
+		container.UserIdentityRepo,
+		container.OAuthStateRepo,
+		container.WebSocketPort,
+		loginGuard,
+		accountGuard,
+		container.Mailer,
+		container.TokenSigner,
+		twoFactorKey,
+		oauthProviders,
+	)
+
+	// สร้าง UserService (หลังจาก AuthService เพื่อส่งลิงก์ยืนยันเมื่อเปลี่ยนอีเมล)
+	container.UserService = serviceimpl.NewUserService(container.UserRepo, container.AuthService)
+
+	// สร้าง AdminService (หลังจาก AuthService เพื่อเพิกถอน session เมื่อระงับบัญชี)
+	container.AdminService = serviceimpl.NewAdminService(
+		container.UserRepo,
+		container.ConversationRepo,
+		container.MessageRepo,
+		container.AdminAuditLogRepo,
+		container.AuthService,
+		container.WebSocketPort,
+	)
+
 	// สร้าง PinnedMessageService (หลังจาก WebSocketPort เพื่อให้ส่ง realtime events ได้)
 	container.PinnedMessageService = serviceimpl.NewPinnedMessageService(
 		container.PinnedMessageRepo,
@@ -176,12 +279,38 @@ func NewContainer(db *gorm.DB, storageService service.FileStorageService, redisC
 		container.WebSocketPort,
 	)
 
+	// สร้าง PushService (ส่ง push ไปยังผู้ใช้ที่ออฟไลน์ ต้องสร้างก่อน NotificationService)
+	container.PushService = serviceimpl.NewPushService(
+		container.DeviceTokenRepo,
+		container.PushDeliveryRepo,
+		container.ConversationRepo,
+		container.UserRepo,
+		container.PresenceService,
+		pushProviders,
+	)
+
+	// สร้าง BotWebhookService (ส่ง outgoing webhook ไปยังบอท ต้องสร้างก่อน NotificationService)
+	container.BotWebhookService = serviceimpl.NewBotWebhookService(
+		container.BotRepo,
+		container.BotWebhookDeliveryRepo,
+		container.ConversationRepo,
+		container.UserRepo,
+		webhookSender,
+	)
+
 	// สร้าง NotificationService
 	container.NotificationService = serviceimpl.NewNotificationService(
 		container.WebSocketPort,
 		container.UserRepo,
 		container.MessageRepo,
 		container.ConversationRepo,
+		container.PushService,
+		container.BotWebhookService,
+		container.NotificationRepo,
+	)
+	container.NotificationInboxService = serviceimpl.NewNotificationInboxService(
+		container.NotificationRepo,
+		container.WebSocketPort,
 	)
 
 	// ตั้งค่า NotificationService ใน Hub
@@ -194,7 +323,36 @@ func NewContainer(db *gorm.DB, storageService service.FileStorageService, redisC
 		container.NotificationService,
 	)
 
-	// สร้าง MessageService (ต้องสร้างหลัง NotificationService)
+	// สร้าง JoinRequestService (ต้องสร้างหลัง GroupActivityService และ NotificationService)
+	container.JoinRequestService = serviceimpl.NewJoinRequestService(
+		container.JoinRequestRepo,
+		container.ConversationRepo,
+		container.UserRepo,
+		container.ConversationMemberService,
+		container.NotificationService,
+		container.GroupActivityService,
+	)
+
+	// สร้าง InviteLinkService (ต้องสร้างหลัง JoinRequestService สำหรับลิงก์ที่ต้องอนุมัติ)
+	container.InviteLinkService = serviceimpl.NewInviteLinkService(
+		container.InviteLinkRepo,
+		container.ConversationRepo,
+		container.UserRepo,
+		container.ConversationMemberService,
+		container.NotificationService,
+		container.GroupActivityService,
+		container.JoinRequestService,
+	)
+
+	// สร้าง ContentFilterService (filter chain ที่ MessageService รันก่อนบันทึกข้อความ)
+	container.ContentFilterService = serviceimpl.NewContentFilterService(
+		contentFilters,
+		container.ConversationRepo,
+		container.ModerationReportRepo,
+		container.ConversationMemberService,
+	)
+
+	// สร้าง MessageService (ต้องสร้างหลัง NotificationService และ ContentFilterService)
 	container.MessageService = serviceimpl.NewMessageService(
 		container.MessageRepo,
 		container.MessageReadRepo,
@@ -202,6 +360,11 @@ func NewContainer(db *gorm.DB, storageService service.FileStorageService, redisC
 		container.UserRepo,
 		container.NotificationService,
 		container.MessageMentionRepo,
+		container.MessageReactionRepo,
+		container.ThreadReadRepo,
+		container.PollRepo,
+		container.E2EEKeyRepo,
+		container.ContentFilterService,
 	)
 
 	// สร้าง ScheduledMessageService (ต้องสร้างหลัง MessageService และ NotificationService)
@@ -212,6 +375,50 @@ func NewContainer(db *gorm.DB, storageService service.FileStorageService, redisC
 		container.NotificationService, // ✅ เพิ่มเพื่อส่ง WebSocket notification เมื่อส่งข้อความตั้งเวลา
 	)
 
+	// สร้าง BotService (ต้องสร้างหลัง MessageService เพื่อส่งข้อความในนามบอท)
+	container.BotService = serviceimpl.NewBotService(
+		container.BotRepo,
+		container.BotWebhookDeliveryRepo,
+		container.UserRepo,
+		container.MessageRepo,
+		container.MessageService,
+		webhookSender,
+	)
+
+	// สร้าง ModerationService (ดำเนินการผ่าน MessageService และ AdminService)
+	container.ModerationService = serviceimpl.NewModerationService(
+		container.ModerationReportRepo,
+		container.MessageRepo,
+		container.UserRepo,
+		container.ConversationRepo,
+		container.MessageService,
+		container.AdminService,
+	)
+
+	// สร้าง ConversationWebhookService (incoming webhook โพสต์ข้อความผ่าน MessageService)
+	container.ConversationWebhookService = serviceimpl.NewConversationWebhookService(
+		container.ConversationWebhookRepo,
+		container.ConversationRepo,
+		container.UserRepo,
+		container.ConversationMemberService,
+		container.MessageService,
+	)
+
+	// สร้าง E2EEService (ต้องสร้างหลัง NotificationService เพื่อแจ้ง e2ee.key_changed)
+	container.E2EEService = serviceimpl.NewE2EEService(
+		container.E2EEKeyRepo,
+		container.UserRepo,
+		container.ConversationRepo,
+		container.UserFriendshipRepo,
+		container.NotificationService,
+	)
+
+	// สร้าง SyncService (delta sync สำหรับ client แบบ offline-first)
+	container.SyncService = serviceimpl.NewSyncService(
+		container.SyncRepo,
+		container.ConversationService,
+	)
+
 	// สร้าง handlers
 	container.AuthHandler = handler.NewAuthHandler(container.AuthService)
 	container.UserHandler = handler.NewUserHandler(container.UserService, container.AuthService, container.StorageService)
@@ -228,6 +435,18 @@ func NewContainer(db *gorm.DB, storageService service.FileStorageService, redisC
 	container.ScheduledMessageHandler = handler.NewScheduledMessageHandler(container.ScheduledMessageService)
 	container.NoteHandler = handler.NewNoteHandler(container.NoteService, container.WebSocketPort)
 	container.PinnedMessageHandler = handler.NewPinnedMessageHandler(container.PinnedMessageService)
+	container.ThreadHandler = handler.NewThreadHandler(container.MessageService, container.ConversationService, container.NotificationService)
+	container.PushHandler = handler.NewPushHandler(container.PushService)
+	container.SyncHandler = handler.NewSyncHandler(container.SyncService)
+	container.E2EEHandler = handler.NewE2EEHandler(container.E2EEService)
+	container.AdminHandler = handler.NewAdminHandler(container.AdminService)
+	container.InviteLinkHandler = handler.NewInviteLinkHandler(container.InviteLinkService)
+	container.JoinRequestHandler = handler.NewJoinRequestHandler(container.JoinRequestService)
+	container.BotHandler = handler.NewBotHandler(container.BotService, container.NotificationService)
+	container.ConversationWebhookHandler = handler.NewConversationWebhookHandler(container.ConversationWebhookService, container.NotificationService)
+	container.ModerationHandler = handler.NewModerationHandler(container.ModerationService)
+	container.ContentFilterHandler = handler.NewContentFilterHandler(container.ContentFilterService)
+	container.NotificationHandler = handler.NewNotificationHandler(container.NotificationInboxService)
 
 	// สร้าง background jobs
 	container.FileCleanupScheduler = scheduler.NewFileCleanupScheduler(
@@ -239,6 +458,24 @@ func NewContainer(db *gorm.DB, storageService service.FileStorageService, redisC
 		container.ScheduledMessageService,
 	)
 
+	container.MessageExpiryScheduler = scheduler.NewMessageExpiryScheduler(
+		container.MessageService,
+		container.MessageRepo,
+		container.StorageService,
+	)
+
+	container.PushRetryScheduler = scheduler.NewPushRetryScheduler(
+		container.PushService,
+	)
+
+	container.SyncTombstoneCleanupScheduler = scheduler.NewSyncTombstoneCleanupScheduler(
+		container.SyncService,
+	)
+
+	container.BotWebhookRetryScheduler = scheduler.NewBotWebhookRetryScheduler(
+		container.BotWebhookService,
+	)
+
 	// เชื่อมต่อ processor กับ service สำหรับ precise timing
 	// (ต้องทำหลังจากสร้างทั้งสองแล้ว)
 	container.ScheduledMessageService.SetProcessor(container.ScheduledMessageProcessor)
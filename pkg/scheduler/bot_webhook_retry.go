@@ -0,0 +1,59 @@
+// pkg/scheduler/bot_webhook_retry.go
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+)
+
+// BotWebhookRetryScheduler ส่ง webhook ของบอทที่ล้มเหลวซ้ำตามเวลา next_retry_at
+type BotWebhookRetryScheduler struct {
+	botWebhookService service.BotWebhookService
+	interval          time.Duration
+	batchSize         int
+}
+
+// NewBotWebhookRetryScheduler สร้าง scheduler ใหม่
+func NewBotWebhookRetryScheduler(botWebhookService service.BotWebhookService) *BotWebhookRetryScheduler {
+	return &BotWebhookRetryScheduler{
+		botWebhookService: botWebhookService,
+		interval:          15 * time.Second, // ตรวจทุก 15 วินาที
+		batchSize:         100,              // retry สูงสุดรอบละ 100 รายการ
+	}
+}
+
+// Start เริ่มการทำงานของ scheduler
+func (s *BotWebhookRetryScheduler) Start(ctx context.Context) {
+	log.Println("Bot webhook retry scheduler started")
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	// รันทันทีครั้งแรก
+	s.retry()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Bot webhook retry scheduler stopped")
+			return
+		case <-ticker.C:
+			s.retry()
+		}
+	}
+}
+
+// retry ส่ง webhook ที่ถึงเวลา retry แล้ว
+func (s *BotWebhookRetryScheduler) retry() {
+	count, err := s.botWebhookService.RetryDueDeliveries(s.batchSize)
+	if err != nil {
+		log.Printf("Error retrying bot webhook deliveries: %v", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("Retried %d bot webhook deliveries", count)
+	}
+}
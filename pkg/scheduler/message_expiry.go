@@ -0,0 +1,154 @@
+// pkg/scheduler/message_expiry.go
+package scheduler
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/thizplus/gofiber-chat-api/domain/models"
+	"github.com/thizplus/gofiber-chat-api/domain/repository"
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+)
+
+// MessageExpiryScheduler ลบข้อความที่หายไปเอง (expires_at) และไฟล์ media ของข้อความเหล่านั้น
+type MessageExpiryScheduler struct {
+	messageService service.MessageService
+	messageRepo    repository.MessageRepository
+	storageService service.FileStorageService
+	interval       time.Duration
+	batchSize      int
+}
+
+// NewMessageExpiryScheduler สร้าง scheduler ใหม่
+func NewMessageExpiryScheduler(
+	messageService service.MessageService,
+	messageRepo repository.MessageRepository,
+	storageService service.FileStorageService,
+) *MessageExpiryScheduler {
+	return &MessageExpiryScheduler{
+		messageService: messageService,
+		messageRepo:    messageRepo,
+		storageService: storageService,
+		interval:       1 * time.Minute, // ทำงานทุก 1 นาที
+		batchSize:      500,             // ลบสูงสุดรอบละ 500 ข้อความ
+	}
+}
+
+// Start เริ่มการทำงานของ scheduler
+func (s *MessageExpiryScheduler) Start(ctx context.Context) {
+	log.Println("Message expiry scheduler started")
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	// รันทันทีครั้งแรก
+	s.purge()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Message expiry scheduler stopped")
+			return
+		case <-ticker.C:
+			s.purge()
+		}
+	}
+}
+
+// purge ลบข้อความที่หมดอายุจนกว่าจะหมด (ทีละ batch)
+// รันได้พร้อมกันหลาย instance เพราะแต่ละข้อความถูกจองให้ instance เดียว (ลบไฟล์และส่ง event ครั้งเดียว)
+func (s *MessageExpiryScheduler) purge() {
+	now := time.Now()
+	purgedCount := 0
+	fileCount := 0
+
+	for {
+		messages, err := s.messageService.PurgeExpiredMessages(now, s.batchSize)
+		if err != nil {
+			log.Printf("Error purging expired messages: %v", err)
+			break
+		}
+
+		for _, message := range messages {
+			fileCount += s.deleteMedia(message)
+		}
+		purgedCount += len(messages)
+
+		if len(messages) < s.batchSize {
+			break
+		}
+	}
+
+	if purgedCount > 0 {
+		log.Printf("Message expiry completed: %d messages purged, %d files deleted", purgedCount, fileCount)
+	}
+}
+
+// deleteMedia ลบไฟล์ media ของข้อความที่หมดอายุ
+// ข้ามไฟล์ที่ยังถูกใช้โดยข้อความอื่น (เช่น ข้อความที่ถูก forward ไปยังการสนทนาอื่น)
+func (s *MessageExpiryScheduler) deleteMedia(message *models.Message) int {
+	deleted := 0
+	for _, mediaURL := range messageMediaURLs(message) {
+		count, err := s.messageRepo.CountActiveByMediaURL(mediaURL)
+		if err != nil {
+			log.Printf("Error checking media references %s: %v", mediaURL, err)
+			continue
+		}
+		if count > 0 {
+			continue
+		}
+
+		path := s.storagePath(mediaURL)
+		if path == "" {
+			continue
+		}
+
+		if err := s.storageService.DeleteFile(path); err != nil {
+			log.Printf("Error deleting file %s: %v", path, err)
+			continue
+		}
+		deleted++
+	}
+	return deleted
+}
+
+// storagePath แปลง public URL เป็น path ใน storage (คืนค่าว่างถ้าไม่ใช่ไฟล์ของ storage นี้)
+func (s *MessageExpiryScheduler) storagePath(mediaURL string) string {
+	prefix := s.storageService.GetPublicURL("")
+	if prefix == "" || !strings.HasPrefix(mediaURL, prefix) {
+		return ""
+	}
+	return strings.TrimPrefix(mediaURL, prefix)
+}
+
+// messageMediaURLs รวม URL ของ media ทั้งหมดในข้อความ (รวมไฟล์ในอัลบั้ม)
+func messageMediaURLs(message *models.Message) []string {
+	seen := make(map[string]bool)
+	var urls []string
+	add := func(url string) {
+		if url != "" && !seen[url] {
+			seen[url] = true
+			urls = append(urls, url)
+		}
+	}
+
+	add(message.MediaURL)
+	add(message.MediaThumbnailURL)
+
+	if files, ok := message.AlbumFiles.([]interface{}); ok {
+		for _, file := range files {
+			if item, ok := file.(map[string]interface{}); ok {
+				if url, ok := item["media_url"].(string); ok {
+					add(url)
+				}
+				if url, ok := item["media_thumbnail_url"].(string); ok {
+					add(url)
+				}
+			}
+		}
+	}
+
+	return urls
+}
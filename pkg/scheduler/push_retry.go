@@ -0,0 +1,59 @@
+// pkg/scheduler/push_retry.go
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+)
+
+// PushRetryScheduler ส่ง push ที่ล้มเหลวซ้ำตามเวลา next_retry_at
+type PushRetryScheduler struct {
+	pushService service.PushService
+	interval    time.Duration
+	batchSize   int
+}
+
+// NewPushRetryScheduler สร้าง scheduler ใหม่
+func NewPushRetryScheduler(pushService service.PushService) *PushRetryScheduler {
+	return &PushRetryScheduler{
+		pushService: pushService,
+		interval:    30 * time.Second, // ตรวจทุก 30 วินาที
+		batchSize:   200,              // retry สูงสุดรอบละ 200 รายการ
+	}
+}
+
+// Start เริ่มการทำงานของ scheduler
+func (s *PushRetryScheduler) Start(ctx context.Context) {
+	log.Println("Push retry scheduler started")
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	// รันทันทีครั้งแรก
+	s.retry()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Push retry scheduler stopped")
+			return
+		case <-ticker.C:
+			s.retry()
+		}
+	}
+}
+
+// retry ส่ง push ที่ถึงเวลา retry แล้ว
+func (s *PushRetryScheduler) retry() {
+	count, err := s.pushService.RetryDueDeliveries(s.batchSize)
+	if err != nil {
+		log.Printf("Error retrying push deliveries: %v", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("Retried %d push deliveries", count)
+	}
+}
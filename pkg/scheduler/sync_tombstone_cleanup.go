@@ -0,0 +1,57 @@
+// pkg/scheduler/sync_tombstone_cleanup.go
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/thizplus/gofiber-chat-api/domain/service"
+)
+
+// SyncTombstoneCleanupScheduler ลบ tombstone ของ delta sync ที่เกินอายุ
+type SyncTombstoneCleanupScheduler struct {
+	syncService service.SyncService
+	interval    time.Duration
+}
+
+// NewSyncTombstoneCleanupScheduler สร้าง scheduler ใหม่
+func NewSyncTombstoneCleanupScheduler(syncService service.SyncService) *SyncTombstoneCleanupScheduler {
+	return &SyncTombstoneCleanupScheduler{
+		syncService: syncService,
+		interval:    6 * time.Hour, // ทำงานทุก 6 ชั่วโมง
+	}
+}
+
+// Start เริ่มการทำงานของ scheduler
+func (s *SyncTombstoneCleanupScheduler) Start(ctx context.Context) {
+	log.Println("Sync tombstone cleanup scheduler started")
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	// รันทันทีครั้งแรก
+	s.cleanup()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Sync tombstone cleanup scheduler stopped")
+			return
+		case <-ticker.C:
+			s.cleanup()
+		}
+	}
+}
+
+// cleanup ลบ tombstone ที่เก่ากว่า retention
+func (s *SyncTombstoneCleanupScheduler) cleanup() {
+	count, err := s.syncService.PurgeTombstones(time.Now())
+	if err != nil {
+		log.Printf("Error purging sync tombstones: %v", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("Purged %d sync tombstones", count)
+	}
+}